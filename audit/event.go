@@ -0,0 +1,239 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/pagination/keysetpagination"
+	"github.com/ory/x/sqlxx"
+)
+
+// Action is what an actor did to the target of an audit event.
+type Action string
+
+const (
+	ActionIdentityCreated            Action = "identity.created"
+	ActionIdentityBatchCreated       Action = "identity.batch_created"
+	ActionIdentityUpdated            Action = "identity.updated"
+	ActionIdentityPatched            Action = "identity.patched"
+	ActionIdentityDeleted            Action = "identity.deleted"
+	ActionIdentityCredentialsDeleted Action = "identity.credentials_deleted"
+	ActionIdentitySessionsDeleted    Action = "identity.sessions_deleted"
+	ActionSessionDisabled            Action = "session.disabled"
+	ActionSessionExtended            Action = "session.extended"
+)
+
+// TargetType is the type of the resource an audit event's action was performed on.
+type TargetType string
+
+const (
+	TargetTypeIdentity TargetType = "identity"
+	TargetTypeSession  TargetType = "session"
+)
+
+// ActorType describes how the actor of an audit event was identified.
+type ActorType string
+
+const (
+	// ActorTypeUser actors were identified using one of the configured actor headers.
+	ActorTypeUser ActorType = "user"
+	// ActorTypeAPIKey actors were identified using the fingerprint of the API key they sent.
+	ActorTypeAPIKey ActorType = "api_key"
+	// ActorTypeAnonymous actors sent neither an actor header nor an API key.
+	ActorTypeAnonymous ActorType = "anonymous"
+)
+
+// Event records a mutation of security-critical state through the admin API
+//
+// swagger:model auditEvent
+type Event struct {
+	// ID is the audit event's unique identifier.
+	//
+	// required: true
+	ID uuid.UUID `json:"id" faker:"-" db:"id"`
+
+	// Action is what the actor did, for example `identity.updated`.
+	//
+	// required: true
+	Action Action `json:"action" db:"action"`
+
+	// ActorType describes how the actor was identified.
+	//
+	// required: true
+	ActorType ActorType `json:"actor_type" db:"actor_type"`
+
+	// ActorID identifies the actor. It is the value of the actor header, the fingerprint of the API key,
+	// or empty for anonymous actors.
+	ActorID string `json:"actor_id" db:"actor_id"`
+
+	// TargetType is the type of the resource the action was performed on.
+	//
+	// required: true
+	TargetType TargetType `json:"target_type" db:"target_type"`
+
+	// TargetID is the ID of the resource the action was performed on.
+	//
+	// required: true
+	TargetID uuid.UUID `json:"target_id" faker:"-" db:"target_id"`
+
+	// ChangedPaths are the JSON paths of the target which were changed. Paths below secrets such as
+	// credential configurations are cut off at the secret.
+	ChangedPaths sqlxx.StringSliceJSONFormat `json:"changed_paths" faker:"-" db:"changed_paths"`
+
+	// ClientIP is the IP address of the client which performed the action. It is only read from the proxy headers
+	// if the request came from one of the trusted proxies.
+	ClientIP string `json:"client_ip" db:"client_ip"`
+
+	// CreatedAt is the time the action was performed.
+	//
+	// required: true
+	CreatedAt time.Time `json:"created_at" faker:"-" db:"created_at"`
+
+	NID uuid.UUID `json:"-"  faker:"-" db:"nid"`
+}
+
+// The format we need to use in the Page tokens, as it's the only format that is understood by all DBs
+const dbFormat = "2006-01-02 15:04:05.99999"
+
+func (Event) TableName(context.Context) string {
+	return "audit_events"
+}
+
+func (e Event) PageToken() keysetpagination.PageToken {
+	return keysetpagination.MapPageToken{
+		"id":         e.ID.String(),
+		"created_at": e.CreatedAt.Format(dbFormat),
+	}
+}
+
+func (e Event) DefaultPageToken() keysetpagination.PageToken {
+	return keysetpagination.MapPageToken{
+		"id":         uuid.Nil.String(),
+		"created_at": time.Date(2200, 12, 31, 23, 59, 59, 0, time.UTC).Format(dbFormat),
+	}
+}
+
+// ignoredKeys are bookkeeping fields which change on every write and are not reported as changed.
+var ignoredKeys = map[string]bool{"created_at": true, "updated_at": true}
+
+// ChangedPaths returns the sorted JSON paths (in dot notation) which differ between the JSON encodings of
+// before and after. If a changed path matches one of the redact patterns, for example `credentials.*.config`,
+// the matching prefix is reported instead so that neither secrets nor their structure end up in the audit log.
+func ChangedPaths(before, after interface{}, redact ...string) ([]string, error) {
+	b, err := toJSONValue(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := toJSONValue(after)
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]bool{}
+	diff(nil, b, a, func(p []string) {
+		found[redactPath(p, redact)] = true
+	})
+
+	paths := make([]string, 0, len(found))
+	for p := range found {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func toJSONValue(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var out interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return out, nil
+}
+
+func diff(prefix []string, before, after interface{}, changed func([]string)) {
+	child := func(key string) []string {
+		return append(prefix[:len(prefix):len(prefix)], key)
+	}
+
+	// A missing value is compared like an empty object or array so that the leaves of
+	// added or removed objects are reported.
+	bm, bIsMap := before.(map[string]interface{})
+	am, aIsMap := after.(map[string]interface{})
+	if (bIsMap || before == nil) && (aIsMap || after == nil) && (bIsMap || aIsMap) {
+		for k, bv := range bm {
+			if !ignoredKeys[k] {
+				diff(child(k), bv, am[k], changed)
+			}
+		}
+		for k, av := range am {
+			if _, ok := bm[k]; !ok && !ignoredKeys[k] {
+				diff(child(k), nil, av, changed)
+			}
+		}
+		return
+	}
+
+	bs, bIsSlice := before.([]interface{})
+	as, aIsSlice := after.([]interface{})
+	if (bIsSlice || before == nil) && (aIsSlice || after == nil) && (bIsSlice || aIsSlice) {
+		for k := 0; k < len(as) || k < len(bs); k++ {
+			var bv, av interface{}
+			if k < len(bs) {
+				bv = bs[k]
+			}
+			if k < len(as) {
+				av = as[k]
+			}
+			diff(child(strconv.Itoa(k)), bv, av, changed)
+		}
+		return
+	}
+
+	if !jsonEqual(before, after) {
+		changed(prefix)
+	}
+}
+
+func jsonEqual(a, b interface{}) bool {
+	ra, _ := json.Marshal(a)
+	rb, _ := json.Marshal(b)
+	return string(ra) == string(rb)
+}
+
+func redactPath(p []string, patterns []string) string {
+	for _, pattern := range patterns {
+		segments := strings.Split(pattern, ".")
+		if len(p) < len(segments) {
+			continue
+		}
+		matches := true
+		for k, s := range segments {
+			if ok, _ := path.Match(s, p[k]); !ok {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return strings.Join(p[:len(segments)], ".")
+		}
+	}
+	return strings.Join(p, ".")
+}
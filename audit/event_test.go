@@ -0,0 +1,86 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package audit_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/audit"
+)
+
+func TestChangedPaths(t *testing.T) {
+	for k, tc := range []struct {
+		d             string
+		before, after string
+		redact        []string
+		expected      []string
+	}{
+		{
+			d:        "nothing changed",
+			before:   `{"traits":{"email":"foo@ory.sh"}}`,
+			after:    `{"traits":{"email":"foo@ory.sh"}}`,
+			expected: []string{},
+		},
+		{
+			d:        "a leaf changed",
+			before:   `{"traits":{"email":"foo@ory.sh"},"state":"active"}`,
+			after:    `{"traits":{"email":"bar@ory.sh"},"state":"active"}`,
+			expected: []string{"traits.email"},
+		},
+		{
+			d:        "keys were added and removed",
+			before:   `{"metadata_public":{"a":1}}`,
+			after:    `{"metadata_public":{"b":{"c":2}}}`,
+			expected: []string{"metadata_public.a", "metadata_public.b.c"},
+		},
+		{
+			d:        "array elements changed",
+			before:   `{"addresses":[{"value":"a"}]}`,
+			after:    `{"addresses":[{"value":"b"},{"value":"c"}]}`,
+			expected: []string{"addresses.0.value", "addresses.1.value"},
+		},
+		{
+			d:        "the target was created",
+			after:    `{"id":"1","traits":{"email":"foo@ory.sh"}}`,
+			expected: []string{"id", "traits.email"},
+		},
+		{
+			d:        "the type of a value changed",
+			before:   `{"metadata_public":{"a":[1]}}`,
+			after:    `{"metadata_public":{"a":"1"}}`,
+			expected: []string{"metadata_public.a"},
+		},
+		{
+			d:        "timestamps are ignored",
+			before:   `{"state":"active","updated_at":"2023-01-01T00:00:00Z","credentials":{"password":{"created_at":"2023-01-01T00:00:00Z"}}}`,
+			after:    `{"state":"inactive","updated_at":"2023-01-02T00:00:00Z","credentials":{"password":{"created_at":"2023-01-02T00:00:00Z"}}}`,
+			expected: []string{"state"},
+		},
+		{
+			d:        "secrets are redacted",
+			before:   `{"credentials":{"password":{"config":{"hashed_password":"a"}}}}`,
+			after:    `{"credentials":{"password":{"config":{"hashed_password":"b"}},"totp":{"config":{"totp_url":"c"}}}}`,
+			redact:   []string{"credentials.*.config"},
+			expected: []string{"credentials.password.config", "credentials.totp.config"},
+		},
+	} {
+		t.Run("case="+tc.d, func(t *testing.T) {
+			var before, after interface{}
+			if tc.before != "" {
+				before = json.RawMessage(tc.before)
+			}
+			if tc.after != "" {
+				after = json.RawMessage(tc.after)
+			}
+
+			actual, err := audit.ChangedPaths(before, after, tc.redact...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual, "%d", k)
+		})
+	}
+}
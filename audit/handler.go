@@ -0,0 +1,183 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/pagination/keysetpagination"
+	"github.com/ory/x/pagination/migrationpagination"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x"
+)
+
+const (
+	AdminRouteAudit      = "/audit"
+	AdminRouteListEvents = AdminRouteAudit + "/events"
+)
+
+type (
+	handlerDependencies interface {
+		x.WriterProvider
+		x.LoggingProvider
+		x.CSRFProvider
+		PersistenceProvider
+		config.Provider
+	}
+	Handler struct {
+		r handlerDependencies
+	}
+	HandlerProvider interface {
+		AuditHandler() *Handler
+	}
+)
+
+func NewHandler(r handlerDependencies) *Handler {
+	return &Handler{r: r}
+}
+
+func (h *Handler) RegisterPublicRoutes(public *x.RouterPublic) {
+	h.r.CSRFHandler().IgnoreGlobs(x.AdminPrefix+AdminRouteListEvents, AdminRouteListEvents)
+	public.GET(x.AdminPrefix+AdminRouteListEvents, x.RedirectToAdminRoute(h.r))
+}
+
+func (h *Handler) RegisterAdminRoutes(admin *x.RouterAdmin) {
+	admin.GET(AdminRouteListEvents, h.listAuditEvents)
+}
+
+// Paginated Audit Event List Response
+//
+// swagger:response listAuditEvents
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listAuditEventsResponse struct {
+	migrationpagination.ResponseHeaderAnnotation
+
+	// List of audit events
+	//
+	// in:body
+	Body []Event
+}
+
+// Paginated List Audit Event Parameters
+//
+// swagger:parameters listAuditEvents
+type ListEventsParameters struct {
+	keysetpagination.RequestParameters
+
+	// Action filters events by the action, for example `identity.updated`.
+	// If no value is provided, it doesn't take effect on filter.
+	//
+	// required: false
+	// in: query
+	Action Action `json:"action"`
+
+	// ActorID filters events by the actor who performed the action.
+	// If no value is provided, it doesn't take effect on filter.
+	//
+	// required: false
+	// in: query
+	ActorID string `json:"actor_id"`
+
+	// TargetID filters events by the identity or session the action was performed on.
+	// If no value is provided, it doesn't take effect on filter.
+	//
+	// required: false
+	// in: query
+	TargetID *uuid.UUID `json:"target_id"`
+
+	// Since filters out events which were recorded before the given time (RFC 3339).
+	// If no value is provided, it doesn't take effect on filter.
+	//
+	// required: false
+	// in: query
+	Since *time.Time `json:"since"`
+
+	// Until filters out events which were recorded after the given time (RFC 3339).
+	// If no value is provided, it doesn't take effect on filter.
+	//
+	// required: false
+	// in: query
+	Until *time.Time `json:"until"`
+}
+
+// swagger:route GET /admin/audit/events identity listAuditEvents
+//
+// # List Audit Events
+//
+// Lists the audit log of identity, credential, and session mutations performed through the admin API, newest first.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: listAuditEvents
+//	  400: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) listAuditEvents(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	filter, paginator, err := parseEventsFilter(r)
+	if err != nil {
+		h.r.Writer().WriteErrorCode(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	l, tc, nextPage, err := h.r.AuditPersister().ListAuditEvents(r.Context(), filter, paginator)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("X-Total-Count", fmt.Sprint(tc))
+	keysetpagination.Header(w, r.URL, nextPage)
+	h.r.Writer().Write(w, r, l)
+}
+
+func parseEventsFilter(r *http.Request) (ListEventsParameters, []keysetpagination.Option, error) {
+	q := r.URL.Query()
+	filter := ListEventsParameters{
+		Action:  Action(q.Get("action")),
+		ActorID: q.Get("actor_id"),
+	}
+
+	if q.Has("target_id") {
+		id, err := uuid.FromString(q.Get("target_id"))
+		if err != nil {
+			return ListEventsParameters{}, nil, errors.WithStack(herodot.ErrBadRequest.WithError(err.Error()).WithReason("The target_id query parameter must be a UUID."))
+		}
+		filter.TargetID = &id
+	}
+
+	for key, target := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if !q.Has(key) {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, q.Get(key))
+		if err != nil {
+			return ListEventsParameters{}, nil, errors.WithStack(herodot.ErrBadRequest.WithError(err.Error()).WithReasonf("The %s query parameter must be a RFC 3339 timestamp.", key))
+		}
+		t = t.UTC()
+		*target = &t
+	}
+
+	opts, err := keysetpagination.Parse(q, keysetpagination.NewMapPageToken)
+	if err != nil {
+		return ListEventsParameters{}, nil, err
+	}
+
+	return filter, opts, nil
+}
@@ -0,0 +1,196 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/audit"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/x"
+)
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/identity.schema.json")
+	publicTS, adminTS := testhelpers.NewKratosServerWithCSRF(t, reg)
+	conf.MustSet(ctx, config.ViperKeyAdminBaseURL, adminTS.URL)
+
+	var do = func(t *testing.T, ts *httptest.Server, method, href string, header http.Header, body interface{}, expectCode int) gjson.Result {
+		t.Helper()
+		var b bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&b).Encode(body))
+		}
+		req, err := http.NewRequest(method, ts.URL+href, &b)
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		req.Header.Set("Content-Type", "application/json")
+		res, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		resBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+
+		require.EqualValuesf(t, expectCode, res.StatusCode, "%s", resBody)
+		return gjson.ParseBytes(resBody)
+	}
+
+	var list = func(t *testing.T, query string) gjson.Result {
+		t.Helper()
+		return do(t, adminTS, "GET", audit.AdminRouteListEvents+"?"+query, nil, nil, http.StatusOK)
+	}
+
+	createBody := identity.CreateIdentityBody{
+		Traits: json.RawMessage(`{"email":"audit@ory.sh"}`),
+		Credentials: &identity.IdentityWithCredentials{Password: &identity.AdminIdentityImportCredentialsPassword{
+			Config: identity.AdminIdentityImportCredentialsPasswordConfig{Password: "a-very-secret-password-123"},
+		}},
+	}
+
+	t.Run("case=does not record events if disabled", func(t *testing.T) {
+		created := do(t, adminTS, "POST", identity.RouteCollection, nil, createBody, http.StatusCreated)
+		t.Cleanup(func() {
+			do(t, adminTS, "DELETE", identity.RouteCollection+"/"+created.Get("id").String(), nil, nil, http.StatusNoContent)
+		})
+
+		assert.Len(t, list(t, "").Array(), 0)
+	})
+
+	conf.MustSet(ctx, config.ViperKeyAuditEnabled, true)
+	start := time.Now().UTC().Add(-time.Second)
+
+	created := do(t, adminTS, "POST", identity.RouteCollection, nil, createBody, http.StatusCreated)
+	id := created.Get("id").String()
+
+	t.Run("case=records the creation of an identity without secrets", func(t *testing.T) {
+		events := list(t, "target_id="+id).Array()
+		require.Len(t, events, 1)
+		e := events[0]
+
+		assert.Equal(t, string(audit.ActionIdentityCreated), e.Get("action").String())
+		assert.Equal(t, string(audit.ActorTypeAnonymous), e.Get("actor_type").String())
+		assert.Empty(t, e.Get("actor_id").String())
+		assert.Equal(t, string(audit.TargetTypeIdentity), e.Get("target_type").String())
+		assert.NotEmpty(t, e.Get("client_ip").String())
+
+		var paths []string
+		require.NoError(t, json.Unmarshal([]byte(e.Get("changed_paths").Raw), &paths))
+		assert.Contains(t, paths, "traits.email")
+		assert.Contains(t, paths, "credentials.password.config")
+		for _, p := range paths {
+			assert.NotContains(t, p, "hashed_password")
+		}
+	})
+
+	t.Run("case=records updates by the actor from the actor header", func(t *testing.T) {
+		do(t, adminTS, "PUT", identity.RouteCollection+"/"+id, http.Header{"X-Forwarded-User": {"admin@ory.sh"}, "X-Forwarded-For": {"198.51.100.1"}}, identity.UpdateIdentityBody{
+			Traits: json.RawMessage(`{"email":"audit-updated@ory.sh"}`),
+			State:  identity.StateActive,
+		}, http.StatusOK)
+
+		events := list(t, "action="+string(audit.ActionIdentityUpdated)).Array()
+		require.Len(t, events, 1)
+		assert.Equal(t, string(audit.ActorTypeUser), events[0].Get("actor_type").String())
+		assert.Equal(t, "admin@ory.sh", events[0].Get("actor_id").String())
+		assert.Contains(t, events[0].Get("changed_paths").Raw, `"traits.email"`)
+		assert.Equal(t, "127.0.0.1", events[0].Get("client_ip").String(), "the forwarded client IP address of an untrusted client must be ignored")
+	})
+
+	t.Run("case=records patches by the API key fingerprint", func(t *testing.T) {
+		do(t, adminTS, "PATCH", identity.RouteCollection+"/"+id, http.Header{"Authorization": {"Bearer ory_pat_secret"}}, []map[string]interface{}{
+			{"op": "replace", "path": "/metadata_public", "value": map[string]interface{}{"role": "admin"}},
+		}, http.StatusOK)
+
+		events := list(t, "action="+string(audit.ActionIdentityPatched)).Array()
+		require.Len(t, events, 1)
+		assert.Equal(t, string(audit.ActorTypeAPIKey), events[0].Get("actor_type").String())
+		assert.Regexp(t, "^sha256:[0-9a-f]{16}$", events[0].Get("actor_id").String())
+		assert.NotContains(t, events[0].Raw, "ory_pat_secret")
+		assert.JSONEq(t, `["metadata_public.role"]`, events[0].Get("changed_paths").Raw)
+
+		byActor := list(t, "actor_id="+url.QueryEscape(events[0].Get("actor_id").String())).Array()
+		require.Len(t, byActor, 1)
+		assert.Equal(t, events[0].Get("id").String(), byActor[0].Get("id").String())
+	})
+
+	t.Run("case=records session mutations", func(t *testing.T) {
+		i, err := reg.IdentityPool().GetIdentity(ctx, uuid.FromStringOrNil(id), identity.ExpandNothing)
+		require.NoError(t, err)
+		sess := testhelpers.CreateSession(t, reg)
+		sess.IdentityID = i.ID
+		require.NoError(t, reg.SessionPersister().UpsertSession(ctx, sess))
+
+		do(t, adminTS, "DELETE", "/admin/sessions/"+sess.ID.String(), nil, nil, http.StatusNoContent)
+		do(t, adminTS, "DELETE", "/admin/identities/"+id+"/sessions", nil, nil, http.StatusNoContent)
+
+		events := list(t, "target_id="+sess.ID.String()).Array()
+		require.Len(t, events, 1)
+		assert.Equal(t, string(audit.ActionSessionDisabled), events[0].Get("action").String())
+		assert.Equal(t, string(audit.TargetTypeSession), events[0].Get("target_type").String())
+		assert.JSONEq(t, `["active"]`, events[0].Get("changed_paths").Raw)
+
+		assert.Len(t, list(t, "action="+string(audit.ActionIdentitySessionsDeleted)+"&target_id="+id).Array(), 1)
+	})
+
+	t.Run("case=records deletions", func(t *testing.T) {
+		do(t, adminTS, "DELETE", identity.RouteCollection+"/"+id, nil, nil, http.StatusNoContent)
+
+		events := list(t, "target_id="+id).Array()
+		require.Len(t, events, 5)
+		assert.Equal(t, string(audit.ActionIdentityDeleted), events[0].Get("action").String(), "newest events are listed first")
+		assert.Equal(t, string(audit.ActionIdentityCreated), events[4].Get("action").String())
+	})
+
+	t.Run("case=filters by time", func(t *testing.T) {
+		assert.Len(t, list(t, "since="+url.QueryEscape(start.Format(time.RFC3339))).Array(), 6)
+		assert.Len(t, list(t, "until="+url.QueryEscape(start.Format(time.RFC3339))).Array(), 0)
+		assert.Len(t, list(t, "since="+url.QueryEscape(time.Now().Add(time.Hour).Format(time.RFC3339))).Array(), 0)
+	})
+
+	t.Run("case=paginates", func(t *testing.T) {
+		req, err := http.NewRequest("GET", adminTS.URL+audit.AdminRouteListEvents+"?page_size=4", nil)
+		require.NoError(t, err)
+		res, err := adminTS.Client().Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "6", res.Header.Get("X-Total-Count"))
+		assert.Contains(t, strings.Join(res.Header.Values("Link"), ","), `rel="next"`)
+	})
+
+	t.Run("case=lists events through the public endpoint", func(t *testing.T) {
+		parsed := do(t, publicTS, "GET", x.AdminPrefix+audit.AdminRouteListEvents+"?action="+string(audit.ActionIdentityDeleted), nil, nil, http.StatusOK)
+		assert.Len(t, parsed.Array(), 1)
+	})
+
+	t.Run("case=rejects invalid filters", func(t *testing.T) {
+		for _, q := range []string{"target_id=invalid", "since=yesterday", "until=2023-01-01"} {
+			t.Run("query="+q, func(t *testing.T) {
+				do(t, adminTS, "GET", fmt.Sprintf("%s?%s", audit.AdminRouteListEvents, q), nil, nil, http.StatusBadRequest)
+			})
+		}
+	})
+}
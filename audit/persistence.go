@@ -0,0 +1,24 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+
+	"github.com/ory/x/pagination/keysetpagination"
+)
+
+type (
+	Persister interface {
+		// CreateAuditEvent appends the event to the audit log.
+		CreateAuditEvent(ctx context.Context, e *Event) error
+
+		// ListAuditEvents lists the audit events in the store given the filter, newest first.
+		// Returns list of events, total count of events satisfied by given filter, and error if any
+		ListAuditEvents(ctx context.Context, filter ListEventsParameters, opts []keysetpagination.Option) ([]Event, int64, *keysetpagination.Paginator, error)
+	}
+	PersistenceProvider interface {
+		AuditPersister() Persister
+	}
+)
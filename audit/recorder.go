@@ -0,0 +1,116 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/ory/kratos/bruteforce"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x"
+)
+
+type (
+	recorderDependencies interface {
+		config.Provider
+		x.LoggingProvider
+		bruteforce.ThrottlerProvider
+		PersistenceProvider
+	}
+	RecorderProvider interface {
+		AuditRecorder() *Recorder
+	}
+	// Recorder writes audit events for mutations performed through the admin API.
+	Recorder struct {
+		d recorderDependencies
+	}
+)
+
+func NewRecorder(d recorderDependencies) *Recorder {
+	return &Recorder{d: d}
+}
+
+// Record appends an audit event for the action performed by the request's actor on the target. It must be
+// called once the mutation succeeded.
+//
+// The mutation can not be rolled back at this point, which is why failures to record the event do not fail
+// the request. Instead, the event is logged with error level so that it can be recovered from the logs.
+func (r *Recorder) Record(req *http.Request, action Action, targetType TargetType, targetID uuid.UUID, changedPaths []string) {
+	ctx := req.Context()
+	if !r.d.Config().AuditEnabled(ctx) {
+		return
+	}
+
+	if changedPaths == nil {
+		changedPaths = []string{}
+	}
+
+	actorType, actorID := r.actor(req)
+	e := &Event{
+		ID:           uuid.Must(uuid.NewV4()),
+		Action:       action,
+		ActorType:    actorType,
+		ActorID:      actorID,
+		TargetType:   targetType,
+		TargetID:     targetID,
+		ChangedPaths: changedPaths,
+		ClientIP:     r.d.LoginThrottler().ClientIP(req),
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	if err := r.d.AuditPersister().CreateAuditEvent(ctx, e); err != nil {
+		r.d.Logger().
+			WithError(err).
+			WithField("audit_action", e.Action).
+			WithField("audit_actor_type", e.ActorType).
+			WithField("audit_actor_id", e.ActorID).
+			WithField("audit_target_type", e.TargetType).
+			WithField("audit_target_id", e.TargetID).
+			WithField("audit_changed_paths", e.ChangedPaths).
+			WithField("audit_client_ip", e.ClientIP).
+			Error("Unable to write audit event.")
+	}
+}
+
+// RecordChanges is like Record but computes the changed paths from the target's state before and after the
+// mutation. Paths matching one of the redact patterns are cut off at the pattern.
+func (r *Recorder) RecordChanges(req *http.Request, action Action, targetType TargetType, targetID uuid.UUID, before, after interface{}, redact ...string) {
+	if !r.d.Config().AuditEnabled(req.Context()) {
+		return
+	}
+
+	paths, err := ChangedPaths(before, after, redact...)
+	if err != nil {
+		r.d.Logger().WithError(err).Warn("Unable to determine the changed paths of the audit event.")
+	}
+	r.Record(req, action, targetType, targetID, paths)
+}
+
+// actor identifies who performed the request. The configured actor headers take precedence, for example
+// headers set by an authenticating proxy. Otherwise, the fingerprint of the API key in the Authorization
+// header is used. The API key itself is never stored.
+func (r *Recorder) actor(req *http.Request) (ActorType, string) {
+	for _, h := range r.d.Config().AuditActorHeaders(req.Context()) {
+		if v := strings.TrimSpace(req.Header.Get(h)); v != "" {
+			return ActorTypeUser, v
+		}
+	}
+
+	if scheme, key, ok := strings.Cut(req.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "bearer") && key != "" {
+		return ActorTypeAPIKey, apiKeyFingerprint(key)
+	}
+
+	return ActorTypeAnonymous, ""
+}
+
+func apiKeyFingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "sha256:" + hex.EncodeToString(sum[:])[:16]
+}
@@ -0,0 +1,72 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package bruteforce
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/ory/x/sqlxx"
+)
+
+// KeyType is the kind of value failed login attempts are counted for.
+type KeyType string
+
+const (
+	KeyTypeIdentifier KeyType = "identifier"
+	KeyTypeIP         KeyType = "ip"
+)
+
+// LoginThrottle counts the failed login attempts of an identifier or a client IP address.
+type LoginThrottle struct {
+	ID  uuid.UUID `db:"id"`
+	NID uuid.UUID `db:"nid"`
+
+	KeyType KeyType `db:"key_type"`
+
+	// KeyHMAC is the HMAC of the identifier or IP address.
+	KeyHMAC string `db:"key_hmac"`
+
+	FailedAttempts int            `db:"failed_attempts"`
+	LastFailedAt   time.Time      `db:"last_failed_at"`
+	LockedUntil    sqlxx.NullTime `db:"locked_until"`
+
+	// CreatedAt is a helper struct field for gobuffalo.pop.
+	CreatedAt time.Time `db:"created_at"`
+
+	// UpdatedAt is a helper struct field for gobuffalo.pop.
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (LoginThrottle) TableName(context.Context) string {
+	return "selfservice_login_throttles"
+}
+
+// IsLocked returns true if the throttle is locked at the given time.
+func (t *LoginThrottle) IsLocked(now time.Time) bool {
+	return now.Before(time.Time(t.LockedUntil))
+}
+
+type (
+	Persister interface {
+		// GetLoginThrottle returns the throttle of the key or sqlcon.ErrNoRows if there were no failed attempts.
+		GetLoginThrottle(ctx context.Context, kt KeyType, key string) (*LoginThrottle, error)
+
+		// IncrementLoginThrottle records a failed attempt for the key and returns the updated throttle. If the last
+		// failed attempt happened before resetBefore, counting starts over.
+		IncrementLoginThrottle(ctx context.Context, kt KeyType, key string, resetBefore time.Time) (*LoginThrottle, error)
+
+		// LockLoginThrottle locks the throttle until the given time and resets its failed attempts.
+		LockLoginThrottle(ctx context.Context, id uuid.UUID, until time.Time) error
+
+		// DeleteLoginThrottle removes the throttle of the key, clearing any back-off or lockout.
+		DeleteLoginThrottle(ctx context.Context, kt KeyType, key string) error
+	}
+
+	PersistenceProvider interface {
+		LoginThrottlePersister() Persister
+	}
+)
@@ -0,0 +1,243 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package bruteforce
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/httpx"
+	"github.com/ory/x/sqlcon"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/x"
+)
+
+type (
+	throttlerDependencies interface {
+		config.Provider
+		x.LoggingProvider
+		PersistenceProvider
+	}
+	ThrottlerProvider interface {
+		LoginThrottler() *Throttler
+	}
+	// Throttler protects login methods against online guessing by counting failed attempts per identifier and per
+	// client IP address.
+	//
+	// Once a key has failed more often than configured, further attempts have to back off exponentially. Once it
+	// reaches the maximum number of failed attempts, it is locked out for the configured duration.
+	Throttler struct {
+		d throttlerDependencies
+	}
+)
+
+type throttleKey struct {
+	kt          KeyType
+	key         string
+	maxAttempts int
+}
+
+func NewThrottler(d throttlerDependencies) *Throttler {
+	return &Throttler{d: d}
+}
+
+func (t *Throttler) keys(r *http.Request, identifier string, conf *config.LoginBruteForceProtection) []throttleKey {
+	keys := make([]throttleKey, 0, 2)
+	if identifier = normalizeIdentifier(identifier); identifier != "" {
+		keys = append(keys, throttleKey{kt: KeyTypeIdentifier, key: identifier, maxAttempts: conf.MaxAttemptsPerIdentifier})
+	}
+	if ip := clientIP(r, conf.TrustedProxies); ip != "" {
+		keys = append(keys, throttleKey{kt: KeyTypeIP, key: ip, maxAttempts: conf.MaxAttemptsPerIP})
+	}
+	return keys
+}
+
+// Check returns an error if the identifier or the client IP address of the request are locked out or need to back
+// off. It must be called before the credentials are verified.
+func (t *Throttler) Check(r *http.Request, identifier string) error {
+	ctx := r.Context()
+	conf := t.d.Config().SelfServiceFlowLoginBruteForceProtection(ctx)
+	if !conf.Enabled {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	for _, k := range t.keys(r, identifier, conf) {
+		throttle, err := t.d.LoginThrottlePersister().GetLoginThrottle(ctx, k.kt, k.key)
+		if errors.Is(err, sqlcon.ErrNoRows) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if throttle.IsLocked(now) {
+			t.d.Audit().
+				WithRequest(r).
+				WithField("key_type", k.kt).
+				WithSensitiveField("identifier", identifier).
+				Info("Rejected a login attempt because of too many failed attempts.")
+			return schema.NewLoginLockedOutError(time.Time(throttle.LockedUntil))
+		}
+
+		if retryAt := backoffUntil(throttle, conf); now.Before(retryAt) {
+			return schema.NewLoginRetryLaterError(retryAt)
+		}
+	}
+
+	return nil
+}
+
+// RecordFailure counts a failed attempt for the identifier and the client IP address of the request and locks them
+// out once they reach the maximum number of failed attempts.
+func (t *Throttler) RecordFailure(r *http.Request, identifier string) error {
+	ctx := r.Context()
+	conf := t.d.Config().SelfServiceFlowLoginBruteForceProtection(ctx)
+	if !conf.Enabled {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	for _, k := range t.keys(r, identifier, conf) {
+		throttle, err := t.d.LoginThrottlePersister().IncrementLoginThrottle(ctx, k.kt, k.key, now.Add(-conf.LockoutDuration))
+		if err != nil {
+			return err
+		}
+
+		if throttle.FailedAttempts < k.maxAttempts {
+			continue
+		}
+
+		if err := t.d.LoginThrottlePersister().LockLoginThrottle(ctx, throttle.ID, now.Add(conf.LockoutDuration)); err != nil {
+			return err
+		}
+
+		t.d.Audit().
+			WithRequest(r).
+			WithField("key_type", k.kt).
+			WithSensitiveField("identifier", identifier).
+			WithField("locked_until", now.Add(conf.LockoutDuration)).
+			Info("Locked out login attempts because of too many failed attempts.")
+	}
+
+	return nil
+}
+
+// RecordSuccess clears the failed attempts of the identifier. The failed attempts of the client IP address are kept
+// as otherwise an attacker could reset them by signing in to an account they control.
+func (t *Throttler) RecordSuccess(r *http.Request, identifier string) error {
+	ctx := r.Context()
+	if !t.d.Config().SelfServiceFlowLoginBruteForceProtection(ctx).Enabled {
+		return nil
+	}
+
+	return t.Clear(ctx, identifier)
+}
+
+// Clear removes any back-off or lockout of the identifier.
+func (t *Throttler) Clear(ctx context.Context, identifier string) error {
+	if identifier = normalizeIdentifier(identifier); identifier == "" {
+		return nil
+	}
+
+	if err := t.d.LoginThrottlePersister().DeleteLoginThrottle(ctx, KeyTypeIdentifier, identifier); err != nil && !errors.Is(err, sqlcon.ErrNoRows) {
+		return err
+	}
+
+	return nil
+}
+
+// RecentFailures returns the number of failed login attempts from the IP address within the lockout duration.
+// Unlike the failed attempts of an identifier, they are kept after a successful login.
+func (t *Throttler) RecentFailures(ctx context.Context, ip string) (int, error) {
+	conf := t.d.Config().SelfServiceFlowLoginBruteForceProtection(ctx)
+	if !conf.Enabled || ip == "" {
+		return 0, nil
+	}
+
+	throttle, err := t.d.LoginThrottlePersister().GetLoginThrottle(ctx, KeyTypeIP, ip)
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	if throttle.LastFailedAt.Before(time.Now().UTC().Add(-conf.LockoutDuration)) {
+		return 0, nil
+	}
+	return throttle.FailedAttempts, nil
+}
+
+// backoffUntil returns the time until which the key has to back off. The delay doubles with every failed attempt
+// after the configured number of attempts and is capped at the configured maximum.
+func backoffUntil(throttle *LoginThrottle, conf *config.LoginBruteForceProtection) time.Time {
+	exceeded := throttle.FailedAttempts - conf.BackoffAfterAttempts
+	if exceeded < 0 {
+		return time.Time{}
+	}
+
+	delay := conf.BackoffMaxDelay
+	if exceeded < 32 {
+		delay = time.Duration(math.Min(float64(conf.BackoffInitialDelay)*math.Pow(2, float64(exceeded)), float64(conf.BackoffMaxDelay)))
+	}
+
+	return throttle.LastFailedAt.Add(delay)
+}
+
+// ClientIP returns the IP address of the client which is throttled.
+func (t *Throttler) ClientIP(r *http.Request) string {
+	return clientIP(r, t.d.Config().SelfServiceFlowLoginBruteForceProtection(r.Context()).TrustedProxies)
+}
+
+// FromTrustedProxy returns true if the request was forwarded by a trusted proxy, whose headers can be relied upon.
+func (t *Throttler) FromTrustedProxy(r *http.Request) bool {
+	return isTrustedProxy(hostOnly(r.RemoteAddr), t.d.Config().SelfServiceFlowLoginBruteForceProtection(r.Context()).TrustedProxies)
+}
+
+// clientIP returns the IP address of the client without the port. The headers set by reverse proxies can be forged
+// by the client, so they are only used if the request comes from a trusted proxy.
+func clientIP(r *http.Request, trustedProxies []string) string {
+	ip := hostOnly(r.RemoteAddr)
+	if isTrustedProxy(ip, trustedProxies) {
+		ip = hostOnly(httpx.ClientIP(r))
+	}
+	return ip
+}
+
+func isTrustedProxy(ip string, trustedProxies []string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+
+	for _, proxy := range trustedProxies {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			if network.Contains(addr) {
+				return true
+			}
+		} else if proxyAddr := net.ParseIP(proxy); proxyAddr != nil && proxyAddr.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func hostOnly(ip string) string {
+	ip = strings.TrimSpace(ip)
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		return host
+	}
+	return ip
+}
+
+func normalizeIdentifier(identifier string) string {
+	return strings.ToLower(strings.TrimSpace(identifier))
+}
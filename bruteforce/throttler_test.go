@@ -0,0 +1,161 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package bruteforce_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/x/sqlcon"
+
+	"github.com/ory/kratos/bruteforce"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/text"
+)
+
+func TestThrottler(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceProtectionEnabled, true)
+	conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceMaxAttemptsIdentifier, 3)
+	conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceMaxAttemptsIP, 5)
+	conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceLockoutDuration, "1h")
+	conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceBackoffAfter, 10)
+
+	th := reg.LoginThrottler()
+
+	newRequest := func(ip string) *http.Request {
+		r := httptest.NewRequest("POST", "/self-service/login", nil)
+		r.RemoteAddr = ip + ":1234"
+		return r
+	}
+
+	assertValidationID := func(t *testing.T, err error, id text.ID) {
+		t.Helper()
+		var ve *schema.ValidationError
+		require.ErrorAs(t, err, &ve)
+		require.Len(t, ve.Messages, 1)
+		assert.Equal(t, id, ve.Messages[0].ID)
+	}
+
+	t.Run("case=is a no-op if disabled", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceProtectionEnabled, false)
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceProtectionEnabled, true)
+		})
+
+		r := newRequest("192.0.2.1")
+		for k := 0; k < 5; k++ {
+			require.NoError(t, th.RecordFailure(r, "disabled@ory.sh"))
+		}
+		require.NoError(t, th.Check(r, "disabled@ory.sh"))
+
+		_, err := reg.LoginThrottlePersister().GetLoginThrottle(ctx, bruteforce.KeyTypeIdentifier, "disabled@ory.sh")
+		assert.ErrorIs(t, err, sqlcon.ErrNoRows)
+	})
+
+	t.Run("case=locks out the identifier", func(t *testing.T) {
+		r := newRequest("192.0.2.2")
+		for k := 0; k < 2; k++ {
+			require.NoError(t, th.Check(r, "locked@ory.sh"))
+			require.NoError(t, th.RecordFailure(r, "locked@ory.sh"))
+		}
+		require.NoError(t, th.Check(r, "locked@ory.sh"))
+		require.NoError(t, th.RecordFailure(r, " Locked@ory.sh "))
+
+		assertValidationID(t, th.Check(r, "locked@ory.sh"), text.ErrorValidationLoginLockedOut)
+		assertValidationID(t, th.Check(newRequest("192.0.2.3"), "LOCKED@ory.sh"), text.ErrorValidationLoginLockedOut)
+		require.NoError(t, th.Check(r, "other@ory.sh"), "the IP address is not locked yet")
+
+		t.Run("case=clearing unlocks the identifier", func(t *testing.T) {
+			require.NoError(t, th.Clear(ctx, "locked@ory.sh"))
+			require.NoError(t, th.Check(r, "locked@ory.sh"))
+			require.NoError(t, th.Clear(ctx, "locked@ory.sh"), "clearing twice is fine")
+		})
+	})
+
+	t.Run("case=locks out the IP address", func(t *testing.T) {
+		r := newRequest("192.0.2.4")
+		for k := 0; k < 5; k++ {
+			require.NoError(t, th.Check(r, ""))
+			require.NoError(t, th.RecordFailure(r, ""))
+		}
+
+		assertValidationID(t, th.Check(r, "anyone@ory.sh"), text.ErrorValidationLoginLockedOut)
+		require.NoError(t, th.Check(newRequest("192.0.2.5"), "anyone@ory.sh"))
+	})
+
+	t.Run("case=success clears the identifier but not the IP address", func(t *testing.T) {
+		r := newRequest("192.0.2.6")
+		require.NoError(t, th.RecordFailure(r, "success@ory.sh"))
+		require.NoError(t, th.RecordFailure(r, "success@ory.sh"))
+		require.NoError(t, th.RecordSuccess(r, "success@ory.sh"))
+
+		_, err := reg.LoginThrottlePersister().GetLoginThrottle(ctx, bruteforce.KeyTypeIdentifier, "success@ory.sh")
+		assert.ErrorIs(t, err, sqlcon.ErrNoRows)
+
+		throttle, err := reg.LoginThrottlePersister().GetLoginThrottle(ctx, bruteforce.KeyTypeIP, "192.0.2.6")
+		require.NoError(t, err)
+		assert.Equal(t, 2, throttle.FailedAttempts)
+	})
+
+	t.Run("case=backs off after failed attempts", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceBackoffAfter, 2)
+		conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceBackoffInitialDelay, "1h")
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceBackoffAfter, 10)
+		})
+
+		r := newRequest("192.0.2.7")
+		require.NoError(t, th.RecordFailure(r, "backoff@ory.sh"))
+		require.NoError(t, th.Check(r, "backoff@ory.sh"))
+		require.NoError(t, th.RecordFailure(r, "backoff@ory.sh"))
+
+		assertValidationID(t, th.Check(r, "backoff@ory.sh"), text.ErrorValidationLoginRetryLater)
+	})
+
+	t.Run("case=counting starts over after the lockout duration", func(t *testing.T) {
+		r := newRequest("192.0.2.8")
+		require.NoError(t, th.RecordFailure(r, "stale@ory.sh"))
+		require.NoError(t, th.RecordFailure(r, "stale@ory.sh"))
+
+		throttle, err := reg.LoginThrottlePersister().IncrementLoginThrottle(ctx, bruteforce.KeyTypeIdentifier, "stale@ory.sh", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 1, throttle.FailedAttempts)
+	})
+
+	t.Run("case=only trusts forwarded client IP addresses from trusted proxies", func(t *testing.T) {
+		r := newRequest("192.0.2.9")
+		r.Header.Set("X-Forwarded-For", "198.51.100.1")
+		r.Header.Set("True-Client-IP", "198.51.100.1")
+		assert.Equal(t, "192.0.2.9", th.ClientIP(r))
+		assert.False(t, th.FromTrustedProxy(r))
+
+		conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceTrustedProxies, []string{"192.0.2.0/28", "192.0.2.100"})
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceTrustedProxies, []string{})
+		})
+		assert.Equal(t, "198.51.100.1", th.ClientIP(r))
+		assert.True(t, th.FromTrustedProxy(r))
+		assert.Equal(t, "192.0.2.99", th.ClientIP(newRequest("192.0.2.99")))
+
+		r = newRequest("192.0.2.100")
+		r.Header.Set("X-Forwarded-For", "198.51.100.2")
+		assert.Equal(t, "198.51.100.2", th.ClientIP(r))
+	})
+
+	t.Run("case=persister returns not found for unknown keys", func(t *testing.T) {
+		assert.ErrorIs(t, reg.LoginThrottlePersister().DeleteLoginThrottle(ctx, bruteforce.KeyTypeIP, "192.0.2.99"), sqlcon.ErrNoRows)
+		assert.ErrorIs(t, reg.LoginThrottlePersister().LockLoginThrottle(ctx, uuid.Nil, time.Now()), sqlcon.ErrNoRows)
+	})
+}
@@ -52,22 +52,43 @@ func (a *AES) Decrypt(ctx context.Context, ciphertext string) ([]byte, error) {
 		return nil, nil
 	}
 
+	plaintext, _, err := a.decrypt(ctx, ciphertext)
+	return plaintext, err
+}
+
+// Rotate re-encrypts the ciphertext with the current secret if it was encrypted with an older one
+func (a *AES) Rotate(ctx context.Context, ciphertext string) (string, int, error) {
+	if len(ciphertext) == 0 {
+		return "", 0, nil
+	}
+
+	plaintext, key, err := a.decrypt(ctx, ciphertext)
+	if err != nil || key == 0 {
+		return ciphertext, key, err
+	}
+
+	rotated, err := a.Encrypt(ctx, plaintext)
+	return rotated, key, err
+}
+
+// decrypt returns the decrypted aes data and the index of the secret which decrypted it
+func (a *AES) decrypt(ctx context.Context, ciphertext string) ([]byte, int, error) {
 	secrets := a.c.Config().SecretsCipher(ctx)
 	if len(secrets) == 0 {
-		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("Unable to decipher the encrypted message because no AES secrets were configured."))
+		return nil, 0, errors.WithStack(herodot.ErrInternalServerError.WithReason("Unable to decipher the encrypted message because no AES secrets were configured."))
 	}
 
 	decode, err := hex.DecodeString(ciphertext)
 	if err != nil {
-		return nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err))
+		return nil, 0, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err))
 	}
 
 	for i := range secrets {
 		plaintext, err := cryptopasta.Decrypt(decode, &secrets[i])
 		if err == nil {
-			return plaintext, nil
+			return plaintext, i, nil
 		}
 	}
 
-	return nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("Unable to decipher the encrypted message."))
+	return nil, 0, errors.WithStack(herodot.ErrInternalServerError.WithReason("Unable to decipher the encrypted message."))
 }
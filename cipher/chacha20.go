@@ -59,32 +59,53 @@ func (c *XChaCha20Poly1305) Decrypt(ctx context.Context, ciphertext string) ([]b
 		return nil, nil
 	}
 
+	plaintext, _, err := c.decrypt(ctx, ciphertext)
+	return plaintext, err
+}
+
+// Rotate re-encrypts the ciphertext with the current secret if it was encrypted with an older one
+func (c *XChaCha20Poly1305) Rotate(ctx context.Context, ciphertext string) (string, int, error) {
+	if len(ciphertext) == 0 {
+		return "", 0, nil
+	}
+
+	plaintext, key, err := c.decrypt(ctx, ciphertext)
+	if err != nil || key == 0 {
+		return ciphertext, key, err
+	}
+
+	rotated, err := c.Encrypt(ctx, plaintext)
+	return rotated, key, err
+}
+
+// decrypt decrypts data and returns the index of the secret which decrypted it
+func (c *XChaCha20Poly1305) decrypt(ctx context.Context, ciphertext string) ([]byte, int, error) {
 	secrets := c.c.Config().SecretsCipher(ctx)
 	if len(secrets) == 0 {
-		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("Unable to decipher the encrypted message because no cipher secrets were configured."))
+		return nil, 0, errors.WithStack(herodot.ErrInternalServerError.WithReason("Unable to decipher the encrypted message because no cipher secrets were configured."))
 	}
 
 	rawCiphertext, err := hex.DecodeString(ciphertext)
 	if err != nil {
-		return nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to decode hex encrypted string"))
+		return nil, 0, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to decode hex encrypted string"))
 	}
 
 	for i := range secrets {
 		aead, err := chacha20poly1305.NewX(secrets[i][:])
 		if err != nil {
-			return nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to instanciate chacha20"))
+			return nil, 0, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to instanciate chacha20"))
 		}
 
 		if len(ciphertext) < aead.NonceSize() {
-			return nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("cipher text too short"))
+			return nil, 0, errors.WithStack(herodot.ErrInternalServerError.WithReason("cipher text too short"))
 		}
 
 		nonce, ciphertext := rawCiphertext[:aead.NonceSize()], rawCiphertext[aead.NonceSize():]
 		plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
 		if err == nil {
-			return plaintext, nil
+			return plaintext, i, nil
 		}
 	}
 
-	return nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("Unable to decrypt string"))
+	return nil, 0, errors.WithStack(herodot.ErrInternalServerError.WithReason("Unable to decrypt string"))
 }
@@ -18,6 +18,13 @@ type Cipher interface {
 	//
 	// If the ciphertext is empty a nil byte slice is returned.
 	Decrypt(ctx context.Context, encrypted string) ([]byte, error)
+
+	// Rotate re-encrypts a hex-encoded binary ciphertext with the current secret if it was encrypted with an older
+	// secret. It returns the index of the secret the ciphertext was encrypted with, and the ciphertext unchanged if
+	// that index is zero.
+	//
+	// If the ciphertext is empty it is returned unchanged.
+	Rotate(ctx context.Context, encrypted string) (rotated string, key int, err error)
 }
 
 type Provider interface {
@@ -77,6 +77,43 @@ func TestCipher(t *testing.T) {
 				_, err = c.Decrypt(context.Background(), "not-empty")
 				require.Error(t, err)
 			})
+
+			t.Run("case=rotate", func(t *testing.T) {
+				oldSecret := "old-secret-thirty-two-characters"
+				cfg.MustSet(ctx, config.ViperKeySecretsCipher, []string{oldSecret})
+
+				encrypted, err := c.Encrypt(ctx, []byte("my secret message!"))
+				require.NoError(t, err)
+
+				rotated, key, err := c.Rotate(ctx, encrypted)
+				require.NoError(t, err)
+				assert.Equal(t, 0, key)
+				assert.Equal(t, encrypted, rotated)
+
+				cfg.MustSet(ctx, config.ViperKeySecretsCipher, append(goodSecret, oldSecret))
+
+				rotated, key, err = c.Rotate(ctx, encrypted)
+				require.NoError(t, err)
+				assert.Equal(t, 1, key)
+				assert.NotEqual(t, encrypted, rotated)
+
+				// The rotated ciphertext can be decrypted without the old secret.
+				cfg.MustSet(ctx, config.ViperKeySecretsCipher, goodSecret)
+				decrypted, err := c.Decrypt(ctx, rotated)
+				require.NoError(t, err)
+				assert.Equal(t, "my secret message!", string(decrypted))
+
+				_, key, err = c.Rotate(ctx, rotated)
+				require.NoError(t, err)
+				assert.Equal(t, 0, key)
+
+				_, _, err = c.Rotate(ctx, encrypted)
+				require.Error(t, err)
+
+				rotated, _, err = c.Rotate(ctx, "")
+				require.NoError(t, err)
+				assert.Empty(t, rotated)
+			})
 		})
 	}
 	c := cipher.NewNoop(reg)
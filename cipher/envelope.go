@@ -0,0 +1,290 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package cipher
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/jsonnetsecure"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x"
+)
+
+// envelopeVersion is the first byte of every envelope ciphertext.
+const envelopeVersion byte = 1
+
+// KeyEncryptionKey wraps and unwraps the data keys of the envelope cipher using keys which are held outside of the
+// configuration, for example in a key management service.
+type KeyEncryptionKey interface {
+	// Wrap encrypts the data key with the current key encryption key and returns the ID of that key.
+	Wrap(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+
+	// Unwrap decrypts a data key which was wrapped by the key encryption key with the given ID.
+	Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+type EnvelopeConfiguration interface {
+	config.Provider
+	x.HTTPClientProvider
+	x.LoggingProvider
+	jsonnetsecure.VMProvider
+}
+
+// Envelope encrypts messages with XChaCha20-Poly1305 using random data keys. The data keys are wrapped by a
+// KeyEncryptionKey and stored in the ciphertext together with the ID of the key encryption key:
+//
+//	version (1 byte) | key ID length (1 byte) | key ID | wrapped data key length (2 bytes) | wrapped data key | nonce | sealed message
+//
+// A data key is used for all messages encrypted within `ciphers.envelope.data_key_lifespan`. Unwrapped data keys are
+// cached, so that the key encryption key is only used once per data key.
+type Envelope struct {
+	c   EnvelopeConfiguration
+	kek KeyEncryptionKey
+
+	mu        sync.Mutex
+	current   *dataKey
+	unwrapped *lru.Cache
+}
+
+type dataKey struct {
+	keyID     string
+	wrapped   []byte
+	key       []byte
+	expiresAt time.Time
+}
+
+// NewEnvelope returns an envelope cipher which uses the key encryption key provider configured in
+// `ciphers.envelope.provider`.
+func NewEnvelope(c EnvelopeConfiguration) *Envelope {
+	return NewEnvelopeWithKEK(c, nil)
+}
+
+// NewEnvelopeWithKEK returns an envelope cipher which uses the given key encryption key. If it is nil, the configured
+// key encryption key provider is used.
+func NewEnvelopeWithKEK(c EnvelopeConfiguration, kek KeyEncryptionKey) *Envelope {
+	unwrapped, _ := lru.New(1024)
+	return &Envelope{c: c, kek: kek, unwrapped: unwrapped}
+}
+
+func (e *Envelope) keyEncryptionKey(ctx context.Context) (KeyEncryptionKey, error) {
+	if e.kek != nil {
+		return e.kek, nil
+	}
+
+	switch provider := e.c.Config().CipherEnvelopeProvider(ctx); provider {
+	case "file":
+		return NewFileKEK(e.c), nil
+	case "http":
+		return NewHTTPKEK(e.c), nil
+	default:
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unknown key encryption key provider %q.", provider))
+	}
+}
+
+// dataKey returns the data key new messages are encrypted with. It generates and wraps a new data key if the current
+// one expired.
+func (e *Envelope) dataKey(ctx context.Context) (*dataKey, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.current != nil && time.Now().Before(e.current.expiresAt) {
+		return e.current, nil
+	}
+
+	kek, err := e.keyEncryptionKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to generate data key"))
+	}
+
+	keyID, wrapped, err := kek.Wrap(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyID) == 0 || len(keyID) > 0xff || len(wrapped) > 0xffff {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("The key encryption key returned an invalid key ID or wrapped data key."))
+	}
+
+	e.current = &dataKey{
+		keyID:     keyID,
+		wrapped:   wrapped,
+		key:       key,
+		expiresAt: time.Now().Add(e.c.Config().CipherEnvelopeDataKeyLifespan(ctx)),
+	}
+	e.unwrapped.Add(keyID+"\x00"+string(wrapped), key)
+	return e.current, nil
+}
+
+// unwrap returns the unwrapped data key, using the key encryption key if it is not cached.
+func (e *Envelope) unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	cacheKey := keyID + "\x00" + string(wrapped)
+	if key, ok := e.unwrapped.Get(cacheKey); ok {
+		return key.([]byte), nil
+	}
+
+	kek, err := e.keyEncryptionKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := kek.Unwrap(ctx, keyID, wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	e.unwrapped.Add(cacheKey, key)
+	return key, nil
+}
+
+// Encrypt returns an envelope encryption of the message
+func (e *Envelope) Encrypt(ctx context.Context, message []byte) (string, error) {
+	if len(message) == 0 {
+		return "", nil
+	}
+
+	dk, err := e.dataKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := chacha20poly1305.NewX(dk.key)
+	if err != nil {
+		return "", errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to generate key"))
+	}
+
+	header := make([]byte, 0, 4+len(dk.keyID)+len(dk.wrapped))
+	header = append(header, envelopeVersion, byte(len(dk.keyID)))
+	header = append(header, dk.keyID...)
+	header = binary.BigEndian.AppendUint16(header, uint16(len(dk.wrapped)))
+	header = append(header, dk.wrapped...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to generate nonce"))
+	}
+
+	out := make([]byte, 0, len(header)+len(nonce)+len(message)+aead.Overhead())
+	out = append(append(out, header...), nonce...)
+	return hex.EncodeToString(aead.Seal(out, nonce, message, header)), nil
+}
+
+// envelope is a parsed envelope ciphertext.
+type envelope struct {
+	header  []byte
+	keyID   string
+	wrapped []byte
+	sealed  []byte
+}
+
+func parseEnvelope(ciphertext string) (*envelope, error) {
+	raw, err := hex.DecodeString(ciphertext)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to decode hex encrypted string"))
+	}
+
+	tooShort := errors.WithStack(herodot.ErrInternalServerError.WithReason("cipher text too short"))
+	if len(raw) < 2 {
+		return nil, tooShort
+	}
+	if raw[0] != envelopeVersion {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unknown envelope version %d.", raw[0]))
+	}
+
+	offset := 2 + int(raw[1])
+	if len(raw) < offset+2 {
+		return nil, tooShort
+	}
+	keyID := string(raw[2:offset])
+
+	wrappedLen := int(binary.BigEndian.Uint16(raw[offset:]))
+	offset += 2
+	if len(raw) < offset+wrappedLen {
+		return nil, tooShort
+	}
+
+	return &envelope{
+		header:  raw[:offset+wrappedLen],
+		keyID:   keyID,
+		wrapped: raw[offset : offset+wrappedLen],
+		sealed:  raw[offset+wrappedLen:],
+	}, nil
+}
+
+// Decrypt decrypts an envelope ciphertext using the key encryption key it references
+func (e *Envelope) Decrypt(ctx context.Context, ciphertext string) ([]byte, error) {
+	if len(ciphertext) == 0 {
+		return nil, nil
+	}
+
+	env, err := parseEnvelope(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := e.unwrap(ctx, env.keyID, env.wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to instanciate chacha20"))
+	}
+
+	if len(env.sealed) < aead.NonceSize() {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("cipher text too short"))
+	}
+
+	nonce, sealed := env.sealed[:aead.NonceSize()], env.sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, env.header)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to decrypt string"))
+	}
+	return plaintext, nil
+}
+
+// Rotate re-encrypts the ciphertext if its data key was not wrapped by the current key encryption key. The returned
+// key index is zero if the data key was wrapped by the current key encryption key, and one otherwise.
+func (e *Envelope) Rotate(ctx context.Context, ciphertext string) (string, int, error) {
+	if len(ciphertext) == 0 {
+		return "", 0, nil
+	}
+
+	env, err := parseEnvelope(ciphertext)
+	if err != nil {
+		return ciphertext, 0, err
+	}
+
+	dk, err := e.dataKey(ctx)
+	if err != nil {
+		return ciphertext, 0, err
+	}
+
+	plaintext, err := e.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return ciphertext, 0, err
+	}
+	if env.keyID == dk.keyID {
+		return ciphertext, 0, nil
+	}
+
+	rotated, err := e.Encrypt(ctx, plaintext)
+	return rotated, 1, err
+}
@@ -0,0 +1,239 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package cipher_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/ory/kratos/cipher"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/internal"
+)
+
+func keySet(t *testing.T, ids ...string) string {
+	var set jose.JSONWebKeySet
+	for _, id := range ids {
+		set.Keys = append(set.Keys, jose.JSONWebKey{KeyID: id, Key: []byte(fmt.Sprintf("%-32s", id))})
+	}
+	raw, err := json.Marshal(set)
+	require.NoError(t, err)
+	return "base64://" + base64.StdEncoding.EncodeToString(raw)
+}
+
+// kms emulates a key management service which wraps data keys by XORing them with the key ID.
+type kms struct {
+	sync.Mutex
+	current string
+	unwraps int
+}
+
+func (k *kms) xor(keyID string, in []byte) []byte {
+	out := make([]byte, len(in))
+	for i := range in {
+		out[i] = in[i] ^ keyID[i%len(keyID)]
+	}
+	return out
+}
+
+func (k *kms) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	k.Lock()
+	defer k.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer kms-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Operation  string `json:"operation"`
+		KeyID      string `json:"key_id"`
+		Plaintext  []byte `json:"plaintext"`
+		Ciphertext []byte `json:"ciphertext"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch req.Operation {
+	case "wrap":
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"key_id": k.current, "ciphertext": k.xor(k.current, req.Plaintext)})
+	case "unwrap":
+		k.unwraps++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"plaintext": k.xor(req.KeyID, req.Ciphertext)})
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	ctx := context.Background()
+	message := []byte("my secret message!")
+
+	t.Run("kek=file", func(t *testing.T) {
+		conf, reg := internal.NewFastRegistryWithMocks(t)
+		conf.MustSet(ctx, config.ViperKeyCipherEnvelopeFileKeys, keySet(t, "k1"))
+
+		c := cipher.NewEnvelope(reg)
+		testAllWork(t, c, conf)
+
+		encrypted, err := c.Encrypt(ctx, message)
+		require.NoError(t, err)
+
+		t.Run("case=ciphertext references the key encryption key", func(t *testing.T) {
+			raw, err := hex.DecodeString(encrypted)
+			require.NoError(t, err)
+			assert.EqualValues(t, 1, raw[0], "version")
+			assert.Equal(t, "k1", string(raw[2:2+raw[1]]))
+		})
+
+		t.Run("case=tampered ciphertexts are rejected", func(t *testing.T) {
+			raw, err := hex.DecodeString(encrypted)
+			require.NoError(t, err)
+			raw[len(raw)-1] ^= 0xff
+			_, err = c.Decrypt(ctx, hex.EncodeToString(raw))
+			require.Error(t, err)
+
+			_, err = c.Decrypt(ctx, "0102")
+			require.Error(t, err)
+		})
+
+		t.Run("case=rotates the key encryption key", func(t *testing.T) {
+			conf.MustSet(ctx, config.ViperKeyCipherEnvelopeFileKeys, keySet(t, "k2", "k1"))
+			c := cipher.NewEnvelope(reg)
+
+			decrypted, err := c.Decrypt(ctx, encrypted)
+			require.NoError(t, err)
+			assert.Equal(t, message, decrypted)
+
+			rotated, key, err := c.Rotate(ctx, encrypted)
+			require.NoError(t, err)
+			assert.Equal(t, 1, key)
+
+			_, key, err = c.Rotate(ctx, rotated)
+			require.NoError(t, err)
+			assert.Equal(t, 0, key)
+
+			conf.MustSet(ctx, config.ViperKeyCipherEnvelopeFileKeys, keySet(t, "k2"))
+			c = cipher.NewEnvelope(reg)
+
+			decrypted, err = c.Decrypt(ctx, rotated)
+			require.NoError(t, err)
+			assert.Equal(t, message, decrypted)
+
+			_, err = c.Decrypt(ctx, encrypted)
+			require.Error(t, err)
+		})
+
+		t.Run("case=fails without keys", func(t *testing.T) {
+			conf.MustSet(ctx, config.ViperKeyCipherEnvelopeFileKeys, "")
+			_, err := cipher.NewEnvelope(reg).Encrypt(ctx, message)
+			require.Error(t, err)
+
+			conf.MustSet(ctx, config.ViperKeyCipherEnvelopeFileKeys, "base64://"+base64.StdEncoding.EncodeToString([]byte(`{"keys":[{"kty":"oct","kid":"short","k":"c2hvcnQ"}]}`)))
+			_, err = cipher.NewEnvelope(reg).Encrypt(ctx, message)
+			require.Error(t, err)
+		})
+	})
+
+	t.Run("kek=http", func(t *testing.T) {
+		service := &kms{current: "kms-key-1"}
+		srv := httptest.NewServer(service)
+		t.Cleanup(srv.Close)
+
+		conf, reg := internal.NewFastRegistryWithMocks(t)
+		conf.MustSet(ctx, config.ViperKeyCipherEnvelopeProvider, "http")
+		conf.MustSet(ctx, config.ViperKeyCipherEnvelopeHTTP, map[string]interface{}{
+			"url": srv.URL,
+			"auth": map[string]interface{}{
+				"type":   "api_key",
+				"config": map[string]interface{}{"name": "Authorization", "value": "Bearer kms-token", "in": "header"},
+			},
+		})
+
+		c := cipher.NewEnvelope(reg)
+		testAllWork(t, c, conf)
+
+		encrypted, err := c.Encrypt(ctx, message)
+		require.NoError(t, err)
+
+		t.Run("case=unwrapped data keys are cached", func(t *testing.T) {
+			service.unwraps = 0
+
+			c := cipher.NewEnvelope(reg)
+			for i := 0; i < 3; i++ {
+				decrypted, err := c.Decrypt(ctx, encrypted)
+				require.NoError(t, err)
+				assert.Equal(t, message, decrypted)
+			}
+			assert.Equal(t, 1, service.unwraps)
+		})
+
+		t.Run("case=rotates the key encryption key", func(t *testing.T) {
+			service.current = "kms-key-2"
+			c := cipher.NewEnvelope(reg)
+
+			rotated, key, err := c.Rotate(ctx, encrypted)
+			require.NoError(t, err)
+			assert.Equal(t, 1, key)
+
+			decrypted, err := c.Decrypt(ctx, rotated)
+			require.NoError(t, err)
+			assert.Equal(t, message, decrypted)
+		})
+
+		t.Run("case=fails if the key management service rejects the request", func(t *testing.T) {
+			conf.MustSet(ctx, config.ViperKeyCipherEnvelopeHTTP+".auth.config.value", "Bearer wrong")
+			_, err := cipher.NewEnvelope(reg).Encrypt(ctx, message)
+			require.Error(t, err)
+			assert.Contains(t, fmt.Sprintf("%+v", err), "401")
+		})
+	})
+
+	t.Run("kek=custom", func(t *testing.T) {
+		conf, reg := internal.NewFastRegistryWithMocks(t)
+		c := cipher.NewEnvelopeWithKEK(reg, staticKEK("custom"))
+		testAllWork(t, c, conf)
+	})
+
+	t.Run("case=registry", func(t *testing.T) {
+		conf, reg := internal.NewFastRegistryWithMocks(t)
+		conf.MustSet(ctx, config.ViperKeyCipherAlgorithm, "envelope")
+		assert.IsType(t, new(cipher.Envelope), reg.Cipher(ctx))
+	})
+}
+
+// staticKEK "wraps" data keys by reversing them.
+type staticKEK string
+
+func (k staticKEK) Wrap(_ context.Context, dataKey []byte) (string, []byte, error) {
+	return string(k), reverse(dataKey), nil
+}
+
+func (k staticKEK) Unwrap(_ context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	if keyID != string(k) {
+		return nil, fmt.Errorf("unknown key %s", keyID)
+	}
+	return reverse(wrapped), nil
+}
+
+func reverse(in []byte) []byte {
+	out := make([]byte, len(in))
+	for i := range in {
+		out[len(in)-1-i] = in[i]
+	}
+	return out
+}
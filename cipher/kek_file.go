@@ -0,0 +1,116 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package cipher
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/chacha20poly1305"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/fetcher"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x"
+)
+
+type FileKEKConfiguration interface {
+	config.Provider
+	x.HTTPClientProvider
+}
+
+// FileKEK wraps data keys with the symmetric keys of the JSON Web Key Set configured in
+// `ciphers.envelope.file.keys`. The first key wraps new data keys. The key set is loaded whenever a data key is
+// wrapped or unwrapped, so that keys can be rotated without restarting.
+type FileKEK struct {
+	c FileKEKConfiguration
+}
+
+func NewFileKEK(c FileKEKConfiguration) *FileKEK {
+	return &FileKEK{c: c}
+}
+
+func (k *FileKEK) keys(ctx context.Context) ([]jose.JSONWebKey, error) {
+	uri := k.c.Config().CipherEnvelopeFileKeys(ctx)
+	if uri == "" {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("Unable to load the key encryption keys because ciphers.envelope.file.keys is not set."))
+	}
+
+	raw, err := fetcher.NewFetcher(fetcher.WithClient(k.c.HTTPClient(ctx))).FetchContext(ctx, uri)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to load the key encryption keys."))
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.NewDecoder(raw).Decode(&set); err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to decode the key encryption keys."))
+	}
+
+	if len(set.Keys) == 0 {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("The key encryption key set does not contain any keys."))
+	}
+	for _, key := range set.Keys {
+		if b, ok := key.Key.([]byte); !ok || len(b) != chacha20poly1305.KeySize || key.KeyID == "" {
+			return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("The key encryption key %q must be a symmetric key of %d bytes with a key ID.", key.KeyID, chacha20poly1305.KeySize))
+		}
+	}
+	return set.Keys, nil
+}
+
+// Wrap encrypts the data key with the first key of the key set
+func (k *FileKEK) Wrap(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	keys, err := k.keys(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	aead, err := chacha20poly1305.NewX(keys[0].Key.([]byte))
+	if err != nil {
+		return "", nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to generate key"))
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(dataKey)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to generate nonce"))
+	}
+
+	return keys[0].KeyID, aead.Seal(nonce, nonce, dataKey, []byte(keys[0].KeyID)), nil
+}
+
+// Unwrap decrypts the data key with the key of the key set with the given ID
+func (k *FileKEK) Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	keys, err := k.keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		if key.KeyID != keyID {
+			continue
+		}
+
+		aead, err := chacha20poly1305.NewX(key.Key.([]byte))
+		if err != nil {
+			return nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to instanciate chacha20"))
+		}
+
+		if len(wrapped) < aead.NonceSize() {
+			return nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("wrapped data key too short"))
+		}
+
+		nonce, sealed := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+		dataKey, err := aead.Open(nil, nonce, sealed, []byte(keyID))
+		if err != nil {
+			return nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to unwrap the data key."))
+		}
+		return dataKey, nil
+	}
+
+	return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("The key encryption key %q does not exist.", keyID))
+}
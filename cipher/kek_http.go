@@ -0,0 +1,108 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package cipher
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/jsonnetsecure"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/request"
+	"github.com/ory/kratos/x"
+)
+
+type HTTPKEKConfiguration interface {
+	config.Provider
+	x.HTTPClientProvider
+	x.LoggingProvider
+	jsonnetsecure.VMProvider
+}
+
+// HTTPKEK wraps and unwraps data keys using the key management service configured in `ciphers.envelope.http`. The key
+// encryption key never leaves the key management service.
+type HTTPKEK struct {
+	c HTTPKEKConfiguration
+}
+
+type (
+	kmsRequest struct {
+		Operation  string `json:"operation"`
+		KeyID      string `json:"key_id,omitempty"`
+		Plaintext  []byte `json:"plaintext,omitempty"`
+		Ciphertext []byte `json:"ciphertext,omitempty"`
+	}
+	kmsResponse struct {
+		KeyID      string `json:"key_id"`
+		Plaintext  []byte `json:"plaintext"`
+		Ciphertext []byte `json:"ciphertext"`
+	}
+)
+
+func NewHTTPKEK(c HTTPKEKConfiguration) *HTTPKEK {
+	return &HTTPKEK{c: c}
+}
+
+func (k *HTTPKEK) do(ctx context.Context, body *kmsRequest) (*kmsResponse, error) {
+	conf := k.c.Config().CipherEnvelopeHTTPConfig(ctx)
+	if conf == nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("Unable to reach the key management service because ciphers.envelope.http is not set."))
+	}
+
+	builder, err := request.NewBuilder(conf, k.c)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := builder.BuildRequest(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := k.c.HTTPClient(ctx).Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to reach the key management service."))
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		_, _ = io.Copy(io.Discard, res.Body)
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("The key management service responded with status code %d to the %s operation.", res.StatusCode, body.Operation))
+	}
+
+	var out kmsResponse
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReason("Unable to decode the response of the key management service."))
+	}
+	return &out, nil
+}
+
+// Wrap asks the key management service to encrypt the data key
+func (k *HTTPKEK) Wrap(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	res, err := k.do(ctx, &kmsRequest{Operation: "wrap", Plaintext: dataKey})
+	if err != nil {
+		return "", nil, err
+	}
+	if res.KeyID == "" || len(res.Ciphertext) == 0 {
+		return "", nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("The key management service did not return a key ID and wrapped data key."))
+	}
+	return res.KeyID, res.Ciphertext, nil
+}
+
+// Unwrap asks the key management service to decrypt the data key
+func (k *HTTPKEK) Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	res, err := k.do(ctx, &kmsRequest{Operation: "unwrap", KeyID: keyID, Ciphertext: wrapped})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Plaintext) == 0 {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("The key management service did not return the unwrapped data key."))
+	}
+	return res.Plaintext, nil
+}
@@ -33,3 +33,8 @@ func (c *Noop) Encrypt(_ context.Context, message []byte) (string, error) {
 func (c *Noop) Decrypt(_ context.Context, ciphertext string) ([]byte, error) {
 	return hex.DecodeString(ciphertext)
 }
+
+// Rotate returns the message unchanged because it is not encrypted
+func (c *Noop) Rotate(_ context.Context, ciphertext string) (string, int, error) {
+	return ciphertext, 0, nil
+}
@@ -0,0 +1,32 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package cipher
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/kratos/driver"
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/servicelocatorx"
+)
+
+// NewCipherCmd creates a new cipher command
+func NewCipherCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "cipher",
+		Short: "Commands related to the secrets used to encrypt data at rest",
+	}
+	configx.RegisterFlags(c.PersistentFlags())
+	return c
+}
+
+func RegisterCommandRecursive(parent *cobra.Command, slOpts []servicelocatorx.Option, dOpts []driver.RegistryOption) {
+	c := NewCipherCmd()
+	parent.AddCommand(c)
+
+	rotate := NewRotateCmd(slOpts, dOpts)
+	cmdx.RegisterFormatFlags(rotate.Flags())
+	c.AddCommand(rotate)
+}
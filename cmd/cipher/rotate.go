@@ -0,0 +1,132 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package cipher
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/kratos/driver"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/flagx"
+	"github.com/ory/x/servicelocatorx"
+)
+
+func NewRotateCmd(slOpts []servicelocatorx.Option, dOpts []driver.RegistryOption) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "rotate",
+		Short: "Re-encrypt stored data with the current cipher secret",
+		Long: `Re-encrypts all data at rest, such as the OpenID Connect tokens stored in identity credentials, which was encrypted
+with an older secret in "secrets.cipher" using the first, current secret. Once the rotation is done, the older secrets
+can be removed from the configuration.
+
+The credentials are processed in batches and the progress is logged after every batch. If the rotation is interrupted,
+pass the last logged cursor using --after to resume it.
+
+Use --dry-run to count the ciphertexts per secret without changing anything:
+
+	kratos cipher rotate -c config.yml --dry-run
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var after uuid.UUID
+			if raw := flagx.MustGetString(cmd, "after"); raw != "" {
+				var err error
+				if after, err = uuid.FromString(raw); err != nil {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Unable to parse the cursor %q: %s\n", raw, err)
+					return cmdx.FailSilently(cmd)
+				}
+			}
+
+			r, err := driver.New(cmd.Context(), cmd.ErrOrStderr(), servicelocatorx.NewOptions(slOpts...), dOpts, []configx.OptionModifier{configx.WithFlags(cmd.Flags())})
+			if err != nil {
+				return err
+			}
+
+			progress, err := Rotate(cmd.Context(), r,
+				identity.CipherRotationAfter(after),
+				identity.CipherRotationBatchSize(flagx.MustGetInt(cmd, "batch-size")),
+				identity.CipherRotationDryRun(flagx.MustGetBool(cmd, "dry-run")),
+			)
+			if progress != nil {
+				cmdx.PrintRow(cmd, (*outputProgress)(progress))
+			}
+			if err != nil {
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), err)
+				return cmdx.FailSilently(cmd)
+			}
+			return nil
+		},
+	}
+
+	c.Flags().IntP("batch-size", "b", identity.DefaultCipherRotationBatchSize, "The number of credentials to re-encrypt at once.")
+	c.Flags().Bool("dry-run", false, "Only count the ciphertexts per cipher secret without re-encrypting them.")
+	c.Flags().String("after", "", "Resume an interrupted rotation after the given cursor.")
+	return c
+}
+
+// Rotate re-encrypts the data at rest with the current cipher secret and logs the progress after every batch.
+func Rotate(ctx context.Context, r driver.Registry, opts ...identity.CipherRotationOption) (*identity.CipherRotationProgress, error) {
+	r.Logger().Println("Cipher secret rotation started.")
+
+	progress, err := r.IdentityCipherRotator().Rotate(ctx, append(opts, identity.CipherRotationWithProgress(func(progress *identity.CipherRotationProgress) {
+		r.Logger().
+			WithField("cursor", progress.Cursor).
+			WithField("credentials", progress.Credentials).
+			WithField("rotated", progress.Rotated).
+			WithField("undecryptable", progress.Undecryptable).
+			Info("Processed a batch of credentials.")
+	}))...)
+	if err != nil {
+		r.Logger().WithError(err).Error("Failed to rotate the cipher secret.")
+		return progress, errors.WithMessagef(err, "resume the rotation with --after %s", progress.Cursor)
+	}
+
+	r.Logger().
+		WithField("credentials", progress.Credentials).
+		WithField("rotated", progress.Rotated).
+		WithField("undecryptable", progress.Undecryptable).
+		WithField("skipped", progress.Skipped).
+		Println("Cipher secret rotation finished.")
+	return progress, nil
+}
+
+type outputProgress identity.CipherRotationProgress
+
+func (o *outputProgress) keys() []int {
+	keys := make([]int, 0, len(o.Keys))
+	for key := range o.Keys {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func (o *outputProgress) Header() []string {
+	header := []string{"CURSOR", "CREDENTIALS", "ROTATED", "UNDECRYPTABLE", "SKIPPED"}
+	for _, key := range o.keys() {
+		header = append(header, fmt.Sprintf("SECRET %d", key))
+	}
+	return header
+}
+
+func (o *outputProgress) Columns() []string {
+	columns := []string{o.Cursor.String(), strconv.Itoa(o.Credentials), strconv.Itoa(o.Rotated), strconv.Itoa(o.Undecryptable), strconv.Itoa(o.Skipped)}
+	for _, key := range o.keys() {
+		columns = append(columns, strconv.Itoa(o.Keys[key]))
+	}
+	return columns
+}
+
+func (o *outputProgress) Interface() interface{} {
+	return o
+}
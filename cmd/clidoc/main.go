@@ -114,6 +114,14 @@ func init() {
 		"NewInfoLoginVerify":                                      text.NewInfoLoginVerify(),
 		"NewInfoLoginWith":                                        text.NewInfoLoginWith("{provider}"),
 		"NewErrorValidationLoginFlowExpired":                      text.NewErrorValidationLoginFlowExpired(aSecondAgo),
+		"NewErrorValidationLoginRetryLater":                       text.NewErrorValidationLoginRetryLater(inAMinute),
+		"NewErrorValidationLoginLockedOut":                        text.NewErrorValidationLoginLockedOut(inAMinute),
+		"NewErrorValidationLoginOrganizationSSORequired":          text.NewErrorValidationLoginOrganizationSSORequired("{provider}"),
+		"NewErrorValidationLoginCodeRequired":                     text.NewErrorValidationLoginCodeRequired(),
+		"NewErrorValidationLoginIdentityInactive":                 text.NewErrorValidationLoginIdentityInactive(),
+		"NewErrorValidationLoginIdentitySuspended":                text.NewErrorValidationLoginIdentitySuspended(),
+		"NewErrorValidationLoginIdentitySuspendedUntil":           text.NewErrorValidationLoginIdentitySuspendedUntil(inAMinute),
+		"NewErrorValidationLoginIdentityPendingDeletion":          text.NewErrorValidationLoginIdentityPendingDeletion(inAMinute),
 		"NewErrorValidationLoginNoStrategyFound":                  text.NewErrorValidationLoginNoStrategyFound(),
 		"NewErrorValidationRegistrationNoStrategyFound":           text.NewErrorValidationRegistrationNoStrategyFound(),
 		"NewErrorValidationSettingsNoStrategyFound":               text.NewErrorValidationSettingsNoStrategyFound(),
@@ -131,6 +139,7 @@ func init() {
 		"NewErrorValidationRecoveryTokenInvalidOrAlreadyUsed":     text.NewErrorValidationRecoveryTokenInvalidOrAlreadyUsed(),
 		"NewErrorValidationRecoveryCodeInvalidOrAlreadyUsed":      text.NewErrorValidationRecoveryCodeInvalidOrAlreadyUsed(),
 		"NewErrorValidationRecoveryRetrySuccess":                  text.NewErrorValidationRecoveryRetrySuccess(),
+		"NewErrorValidationRecoveryOrganizationSSORequired":       text.NewErrorValidationRecoveryOrganizationSSORequired("{provider}"),
 		"NewErrorValidationRecoveryStateFailure":                  text.NewErrorValidationRecoveryStateFailure(),
 		"NewInfoNodeInputEmail":                                   text.NewInfoNodeInputEmail(),
 		"NewInfoNodeResendOTP":                                    text.NewInfoNodeResendOTP(),
@@ -142,6 +151,22 @@ func init() {
 		"NewInfoSelfServiceContinueLoginWebAuthn":                 text.NewInfoSelfServiceContinueLoginWebAuthn(),
 		"NewInfoSelfServiceLoginContinue":                         text.NewInfoSelfServiceLoginContinue(),
 		"NewErrorValidationSuchNoWebAuthnUser":                    text.NewErrorValidationSuchNoWebAuthnUser(),
+		"NewInfoSelfServiceLoginCode":                             text.NewInfoSelfServiceLoginCode(),
+		"NewLoginCodeSent":                                        text.NewLoginCodeSent(),
+		"NewInfoSelfServiceLoginLinkCredentials":                  text.NewInfoSelfServiceLoginLinkCredentials("{provider}"),
+		"NewErrorValidationLoginCodeInvalidOrAlreadyUsed":         text.NewErrorValidationLoginCodeInvalidOrAlreadyUsed(),
+		"NewErrorValidationLoginCodeSubmittedTooOften":            text.NewErrorValidationLoginCodeSubmittedTooOften(),
+		"NewInfoSelfServiceRegistrationRegisterCode":              text.NewInfoSelfServiceRegistrationRegisterCode(),
+		"NewRegistrationCodeSent":                                 text.NewRegistrationCodeSent(),
+		"NewErrorValidationRegistrationCodeInvalidOrAlreadyUsed":  text.NewErrorValidationRegistrationCodeInvalidOrAlreadyUsed(),
+		"NewErrorValidationRegistrationOrganizationSSORequired":   text.NewErrorValidationRegistrationOrganizationSSORequired("{provider}"),
+		"NewInfoSelfServiceLoginPasskey":                          text.NewInfoSelfServiceLoginPasskey(),
+		"NewInfoSelfServiceRegistrationRegisterPasskey":           text.NewInfoSelfServiceRegistrationRegisterPasskey(),
+		"NewInfoSelfServiceSettingsRegisterPasskey":               text.NewInfoSelfServiceSettingsRegisterPasskey(),
+		"NewInfoSelfServiceSettingsRegisterPasskeyDisplayName":    text.NewInfoSelfServiceSettingsRegisterPasskeyDisplayName(),
+		"NewInfoSelfServiceSettingsRemovePasskey":                 text.NewInfoSelfServiceSettingsRemovePasskey("{name}", aSecondAgo),
+		"NewInfoSelfServiceSettingsUpdateLinkSAML":                text.NewInfoSelfServiceSettingsUpdateLinkSAML("{provider}"),
+		"NewInfoSelfServiceSettingsUpdateUnlinkSAML":              text.NewInfoSelfServiceSettingsUpdateUnlinkSAML("{provider}"),
 	}
 }
 
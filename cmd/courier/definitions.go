@@ -0,0 +1,80 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"fmt"
+
+	kratos "github.com/ory/kratos/internal/httpclient"
+
+	"github.com/ory/x/cmdx"
+)
+
+type (
+	outputMessage           kratos.Message
+	outputMessageCollection struct {
+		messages []kratos.Message
+	}
+	outputMessageCount kratos.CourierMessageCount
+)
+
+func (outputMessage) Header() []string {
+	return []string{"ID", "STATUS", "TYPE", "RECIPIENT", "TEMPLATE TYPE", "SEND COUNT", "LAST ERROR"}
+}
+
+func (m outputMessage) Columns() []string {
+	lastError := cmdx.None
+	for i := len(m.Dispatches) - 1; i >= 0; i-- {
+		if msg, ok := m.Dispatches[i].Error["message"]; ok {
+			lastError = fmt.Sprint(msg)
+			break
+		}
+	}
+
+	return []string{
+		m.Id,
+		string(m.Status),
+		string(m.Type),
+		m.Recipient,
+		m.TemplateType,
+		fmt.Sprint(m.SendCount),
+		lastError,
+	}
+}
+
+func (m outputMessage) Interface() interface{} {
+	return m
+}
+
+func (outputMessageCollection) Header() []string {
+	return outputMessage{}.Header()
+}
+
+func (c outputMessageCollection) Table() [][]string {
+	rows := make([][]string, len(c.messages))
+	for i, m := range c.messages {
+		rows[i] = outputMessage(m).Columns()
+	}
+	return rows
+}
+
+func (c outputMessageCollection) Interface() interface{} {
+	return c.messages
+}
+
+func (c *outputMessageCollection) Len() int {
+	return len(c.messages)
+}
+
+func (outputMessageCount) Header() []string {
+	return []string{"COUNT"}
+}
+
+func (c outputMessageCount) Columns() []string {
+	return []string{fmt.Sprint(c.Count)}
+}
+
+func (c outputMessageCount) Interface() interface{} {
+	return c
+}
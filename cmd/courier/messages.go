@@ -0,0 +1,238 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tomnomnom/linkheader"
+
+	"github.com/ory/kratos/cmd/cliclient"
+	kratos "github.com/ory/kratos/internal/httpclient"
+	"github.com/ory/x/cmdx"
+)
+
+const (
+	FlagRecipient    = "recipient"
+	FlagTemplateType = "template-type"
+	FlagAll          = "all"
+	FlagOlderThan    = "older-than"
+	FlagStatus       = "status"
+)
+
+func NewRetryCmd() *cobra.Command {
+	var (
+		recipient, templateType string
+		all                     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "retry [id-0] [id-1] [id-n]",
+		Short: "Re-queue abandoned messages",
+		Long: `Re-queue abandoned messages, either by their ID(s) or all abandoned messages matching a filter.
+
+Re-queued messages have their send count reset, so that the courier retries to deliver them
+up to "courier.message_retries" times again. Their dispatch history is kept.`,
+		Example: `{{ .CommandPath }} 5a8a9d38-bd2e-4cbe-8d3c-2fee3ac9bbd1
+{{ .CommandPath }} --recipient foo@bar.com --template-type recovery_code_valid
+{{ .CommandPath }} --all`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filtered := recipient != "" || templateType != ""
+			if len(args) > 0 && (filtered || all) {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Message IDs can not be combined with --%s, --%s, or --%s.\n", FlagRecipient, FlagTemplateType, FlagAll)
+				return cmdx.FailSilently(cmd)
+			} else if len(args) == 0 && !filtered && !all {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Provide at least one message ID, a filter, or --%s to re-queue all abandoned messages.\n", FlagAll)
+				return cmdx.FailSilently(cmd)
+			}
+
+			c, err := cliclient.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			if len(args) > 0 {
+				return forEachMessage(cmd, args, func(id string) (*kratos.Message, error) {
+					m, _, err := c.CourierApi.RetryCourierMessage(cmd.Context(), id).Execute()
+					return m, err
+				})
+			}
+
+			filter := kratos.NewCourierDeadLetterFilter()
+			if recipient != "" {
+				filter.SetRecipient(recipient)
+			}
+			if templateType != "" {
+				filter.SetTemplateType(templateType)
+			}
+
+			count, _, err := c.CourierApi.RetryCourierDeadLetters(cmd.Context()).CourierDeadLetterFilter(*filter).Execute()
+			if err != nil {
+				return cmdx.PrintOpenAPIError(cmd, err)
+			}
+
+			cmdx.PrintRow(cmd, (*outputMessageCount)(count))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&recipient, FlagRecipient, "", "Only re-queue abandoned messages sent to this recipient.")
+	cmd.Flags().StringVar(&templateType, FlagTemplateType, "", "Only re-queue abandoned messages of this template type, for example recovery_code_valid.")
+	cmd.Flags().BoolVar(&all, FlagAll, false, "Re-queue all abandoned messages.")
+	return cmd
+}
+
+func NewCancelCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel id-0 [id-1] [id-n]",
+		Short: "Cancel queued messages",
+		Long:  "Cancel one or more queued messages by their ID(s), so that the courier does not deliver them.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := cliclient.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			return forEachMessage(cmd, args, func(id string) (*kratos.Message, error) {
+				m, _, err := c.CourierApi.CancelCourierMessage(cmd.Context(), id).Execute()
+				return m, err
+			})
+		},
+	}
+}
+
+func NewPurgeCmd() *cobra.Command {
+	var (
+		olderThan time.Duration
+		status    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Delete old messages",
+		Long: `Delete sent, abandoned, and cancelled messages older than the given age, including their dispatch history.
+
+Queued messages and messages which are being processed are never purged.`,
+		Example: `{{ .CommandPath }} --older-than 720h
+{{ .CommandPath }} --older-than 2160h --status abandoned`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if olderThan <= 0 {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Flag --%s must be a positive duration such as 720h.\n", FlagOlderThan)
+				return cmdx.FailSilently(cmd)
+			}
+
+			c, err := cliclient.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := c.CourierApi.PurgeCourierMessages(cmd.Context()).OlderThan(olderThan.String())
+			if status != "" {
+				req = req.Status(kratos.CourierMessageStatus(status))
+			}
+
+			count, _, err := req.Execute()
+			if err != nil {
+				return cmdx.PrintOpenAPIError(cmd, err)
+			}
+
+			cmdx.PrintRow(cmd, (*outputMessageCount)(count))
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&olderThan, FlagOlderThan, 0, "Only delete messages older than this duration, for example 720h.")
+	cmd.Flags().StringVar(&status, FlagStatus, "", "Only delete messages with this status. One of sent, abandoned, and cancelled.")
+	return cmd
+}
+
+func NewListDeadLettersCmd() *cobra.Command {
+	var recipient string
+
+	cmd := &cobra.Command{
+		Use:   "dead-letters",
+		Short: "List abandoned messages",
+		Long: `List messages which were abandoned after exhausting "courier.message_retries", together with the errors of all attempts to deliver them.
+
+If there are more messages, the token of the next page is printed to stderr.`,
+		Example: `{{ .CommandPath }} --format json
+{{ .CommandPath }} --recipient foo@bar.com`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := cliclient.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			pageToken, pageSize, err := cmdx.ParseTokenPaginationArgs(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := c.CourierApi.ListCourierDeadLetters(cmd.Context()).PageSize(int64(pageSize))
+			if pageToken != "" {
+				req = req.PageToken(pageToken)
+			}
+			if recipient != "" {
+				req = req.Recipient(recipient)
+			}
+
+			messages, res, err := req.Execute()
+			if err != nil {
+				return cmdx.PrintOpenAPIError(cmd, err)
+			}
+
+			cmdx.PrintTable(cmd, &outputMessageCollection{messages: messages})
+
+			for _, link := range linkheader.ParseMultiple(res.Header.Values("Link")) {
+				if link.Rel != "next" {
+					continue
+				}
+				if u, err := url.Parse(link.URL); err == nil && u.Query().Get("page_token") != "" {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Next page token: %s\n", u.Query().Get("page_token"))
+				}
+			}
+			return nil
+		},
+	}
+
+	cmdx.RegisterTokenPaginationFlags(cmd)
+	cmd.Flags().StringVar(&recipient, FlagRecipient, "", "Only list abandoned messages sent to this recipient.")
+	return cmd
+}
+
+// forEachMessage applies the operation to every message ID and prints the resulting messages and errors.
+func forEachMessage(cmd *cobra.Command, ids []string, op func(id string) (*kratos.Message, error)) error {
+	var (
+		messages = make([]kratos.Message, 0, len(ids))
+		failed   = make(map[string]error)
+	)
+
+	for _, id := range ids {
+		m, err := op(id)
+		if err != nil {
+			failed[id] = cmdx.PrintOpenAPIError(cmd, err)
+			continue
+		}
+		messages = append(messages, *m)
+	}
+
+	if len(messages) == 1 {
+		cmdx.PrintRow(cmd, (*outputMessage)(&messages[0]))
+	} else if len(messages) > 1 {
+		cmdx.PrintTable(cmd, &outputMessageCollection{messages: messages})
+	}
+
+	cmdx.PrintErrors(cmd, failed)
+	if len(failed) != 0 {
+		return cmdx.FailSilently(cmd)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/cmd/cliclient"
+	cmdcourier "github.com/ory/kratos/cmd/courier"
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/x"
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/sqlcon"
+)
+
+func TestMessageCmds(t *testing.T) {
+	ctx := context.Background()
+	_, reg := internal.NewFastRegistryWithMocks(t)
+	_, admin := testhelpers.NewKratosServerWithCSRF(t, reg)
+
+	newCmd := func(t *testing.T, cmd *cobra.Command) *cobra.Command {
+		cliclient.RegisterClientFlags(cmd.Flags())
+		cmdx.RegisterFormatFlags(cmd.Flags())
+		require.NoError(t, cmd.Flags().Set(cliclient.FlagEndpoint, admin.URL))
+		require.NoError(t, cmd.Flags().Set(cmdx.FlagFormat, string(cmdx.FormatJSON)))
+		return cmd
+	}
+
+	exec := func(cmd *cobra.Command, args ...string) (string, string, error) {
+		stdOut, stdErr := &bytes.Buffer{}, &bytes.Buffer{}
+		cmd.SetOut(stdOut)
+		cmd.SetErr(stdErr)
+		cmd.SetArgs(append([]string{}, args...))
+		err := cmd.Execute()
+		return stdOut.String(), stdErr.String(), err
+	}
+
+	execNoErr := func(t *testing.T, cmd *cobra.Command, args ...string) gjson.Result {
+		stdOut, stdErr, err := exec(cmd, args...)
+		require.NoError(t, err, "stdout: %s\nstderr: %s", stdOut, stdErr)
+		return gjson.Parse(stdOut)
+	}
+
+	newMessage := func(t *testing.T, status courier.MessageStatus) courier.Message {
+		var message courier.Message
+		require.NoError(t, faker.FakeData(&message))
+		message.Type = courier.MessageTypeEmail
+		message.Recipient = x.NewUUID().String() + "@ory.sh"
+		require.NoError(t, reg.CourierPersister().AddMessage(ctx, &message))
+		require.NoError(t, reg.CourierPersister().SetMessageStatus(ctx, message.ID, status))
+		return message
+	}
+
+	status := func(t *testing.T, m courier.Message) courier.MessageStatus {
+		actual, err := reg.CourierPersister().FetchMessage(ctx, m.ID)
+		require.NoError(t, err)
+		return actual.Status
+	}
+
+	t.Run("case=lists dead letters", func(t *testing.T) {
+		abandoned := newMessage(t, courier.MessageStatusAbandoned)
+
+		out := execNoErr(t, newCmd(t, cmdcourier.NewListDeadLettersCmd()), "--"+cmdcourier.FlagRecipient, abandoned.Recipient)
+		require.Len(t, out.Array(), 1, out.Raw)
+		assert.Equal(t, abandoned.ID.String(), out.Get("0.id").String())
+	})
+
+	t.Run("case=retries messages by ID", func(t *testing.T) {
+		first, second := newMessage(t, courier.MessageStatusAbandoned), newMessage(t, courier.MessageStatusAbandoned)
+
+		out := execNoErr(t, newCmd(t, cmdcourier.NewRetryCmd()), first.ID.String(), second.ID.String())
+		assert.Len(t, out.Array(), 2, out.Raw)
+		assert.Equal(t, courier.MessageStatusQueued, status(t, first))
+		assert.Equal(t, courier.MessageStatusQueued, status(t, second))
+
+		_, stdErr, err := exec(newCmd(t, cmdcourier.NewRetryCmd()), first.ID.String())
+		require.ErrorIs(t, err, cmdx.ErrNoPrintButFail)
+		assert.Contains(t, stdErr, courier.ErrMessageNotAbandoned.Reason())
+	})
+
+	t.Run("case=retries messages by filter", func(t *testing.T) {
+		abandoned := newMessage(t, courier.MessageStatusAbandoned)
+
+		out := execNoErr(t, newCmd(t, cmdcourier.NewRetryCmd()), "--"+cmdcourier.FlagRecipient, abandoned.Recipient)
+		assert.EqualValues(t, 1, out.Get("count").Int(), out.Raw)
+		assert.Equal(t, courier.MessageStatusQueued, status(t, abandoned))
+	})
+
+	t.Run("case=retry requires IDs or a filter", func(t *testing.T) {
+		_, stdErr, err := exec(newCmd(t, cmdcourier.NewRetryCmd()))
+		require.ErrorIs(t, err, cmdx.ErrNoPrintButFail)
+		assert.Contains(t, stdErr, "--all")
+	})
+
+	t.Run("case=cancels messages", func(t *testing.T) {
+		queued := newMessage(t, courier.MessageStatusQueued)
+
+		out := execNoErr(t, newCmd(t, cmdcourier.NewCancelCmd()), queued.ID.String())
+		assert.Equal(t, "cancelled", out.Get("status").String(), out.Raw)
+		assert.Equal(t, courier.MessageStatusCancelled, status(t, queued))
+	})
+
+	t.Run("case=purges messages", func(t *testing.T) {
+		sent := newMessage(t, courier.MessageStatusSent)
+
+		out := execNoErr(t, newCmd(t, cmdcourier.NewPurgeCmd()), "--"+cmdcourier.FlagOlderThan, "1h")
+		assert.EqualValues(t, 0, out.Get("count").Int(), out.Raw)
+
+		time.Sleep(time.Second)
+		out = execNoErr(t, newCmd(t, cmdcourier.NewPurgeCmd()), "--"+cmdcourier.FlagOlderThan, "1ms", "--"+cmdcourier.FlagStatus, "sent")
+		assert.EqualValues(t, 1, out.Get("count").Int(), out.Raw)
+
+		_, err := reg.CourierPersister().FetchMessage(ctx, sent.ID)
+		require.ErrorIs(t, err, sqlcon.ErrNoRows)
+	})
+}
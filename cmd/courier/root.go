@@ -6,7 +6,9 @@ package courier
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/ory/kratos/cmd/cliclient"
 	"github.com/ory/kratos/driver"
+	"github.com/ory/x/cmdx"
 	"github.com/ory/x/servicelocatorx"
 
 	"github.com/ory/x/configx"
@@ -26,4 +28,10 @@ func RegisterCommandRecursive(parent *cobra.Command, slOpts []servicelocatorx.Op
 	c := NewCourierCmd()
 	parent.AddCommand(c)
 	c.AddCommand(NewWatchCmd(slOpts, dOpts))
+
+	for _, cmd := range []*cobra.Command{NewRetryCmd(), NewCancelCmd(), NewPurgeCmd(), NewListDeadLettersCmd()} {
+		cliclient.RegisterClientFlags(cmd.Flags())
+		cmdx.RegisterFormatFlags(cmd.Flags())
+		c.AddCommand(cmd)
+	}
 }
@@ -29,7 +29,10 @@ import (
 	"github.com/ory/x/reqlog"
 	"github.com/ory/x/servicelocatorx"
 
+	"github.com/ory/kratos/cmd/cipher"
 	"github.com/ory/kratos/cmd/courier"
+	"github.com/ory/kratos/cmd/identities"
+	"github.com/ory/kratos/cmd/outbox"
 	"github.com/ory/kratos/driver"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
@@ -92,6 +95,7 @@ func ServePublic(r driver.Registry, cmd *cobra.Command, args []string, slOpts *s
 	n.UseFunc(semconv.Middleware)
 	n.Use(publicLogger)
 	n.Use(x.HTTPLoaderContextMiddleware(r))
+	n.UseFunc(x.AcceptLanguageContextMiddleware)
 	n.Use(sqa(ctx, cmd, r))
 
 	n.Use(r.PrometheusManager())
@@ -176,6 +180,7 @@ func ServeAdmin(r driver.Registry, cmd *cobra.Command, args []string, slOpts *se
 	n.Use(adminLogger)
 	n.UseFunc(x.RedirectAdminMiddleware)
 	n.Use(x.HTTPLoaderContextMiddleware(r))
+	n.UseFunc(x.AcceptLanguageContextMiddleware)
 	n.Use(sqa(ctx, cmd, r))
 	n.Use(r.PrometheusManager())
 
@@ -303,11 +308,32 @@ func bgTasks(d driver.Registry, cmd *cobra.Command, args []string, slOpts *servi
 	modifiers := NewOptions(cmd.Context(), opts)
 	ctx := modifiers.ctx
 
+	g, ctx := errgroup.WithContext(ctx)
 	if d.Config().IsBackgroundCourierEnabled(ctx) {
-		return courier.Watch(ctx, d)
+		g.Go(func() error {
+			return courier.Watch(ctx, d)
+		})
+	}
+	if d.Config().IsBackgroundOutboxEnabled(ctx) {
+		g.Go(func() error {
+			return outbox.Watch(ctx, d)
+		})
+	}
+	if d.Config().IsBackgroundIdentityStateExpiryEnabled(ctx) {
+		g.Go(func() error {
+			return identities.WatchIdentities(ctx, d)
+		})
+	}
+	if d.Config().IsBackgroundCipherRotationEnabled(ctx) {
+		g.Go(func() error {
+			// A failed rotation is logged and can be resumed with "kratos cipher rotate", so it does not stop the
+			// server.
+			_, _ = cipher.Rotate(ctx, d)
+			return nil
+		})
 	}
 
-	return nil
+	return g.Wait()
 }
 
 func ServeAll(d driver.Registry, slOpts *servicelocatorx.Options, opts []Option) func(cmd *cobra.Command, args []string) error {
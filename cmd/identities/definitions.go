@@ -5,6 +5,7 @@ package identities
 
 import (
 	"strings"
+	"time"
 
 	kratos "github.com/ory/kratos/internal/httpclient"
 
@@ -16,6 +17,10 @@ type (
 	outputIdentityCollection struct {
 		identities []kratos.Identity
 	}
+	outputAuditEvent           kratos.AuditEvent
+	outputAuditEventCollection struct {
+		events []kratos.AuditEvent
+	}
 )
 
 func (outputIdentity) Header() []string {
@@ -75,3 +80,60 @@ func (c outputIdentityCollection) Interface() interface{} {
 func (c *outputIdentityCollection) Len() int {
 	return len(c.identities)
 }
+
+func (outputAuditEvent) Header() []string {
+	return []string{"ID", "TIME", "ACTION", "ACTOR", "TARGET", "CHANGED PATHS", "CLIENT IP"}
+}
+
+func (e outputAuditEvent) Columns() []string {
+	ae := kratos.AuditEvent(e)
+
+	actor := ae.ActorType
+	if id := ae.GetActorId(); id != "" {
+		actor += ":" + id
+	}
+
+	paths := cmdx.None
+	if len(e.ChangedPaths) > 0 {
+		paths = strings.Join(e.ChangedPaths, ", ")
+	}
+
+	ip := cmdx.None
+	if ae.GetClientIp() != "" {
+		ip = ae.GetClientIp()
+	}
+
+	return []string{
+		e.Id,
+		e.CreatedAt.Format(time.RFC3339),
+		e.Action,
+		actor,
+		e.TargetType + ":" + e.TargetId,
+		paths,
+		ip,
+	}
+}
+
+func (e outputAuditEvent) Interface() interface{} {
+	return e
+}
+
+func (outputAuditEventCollection) Header() []string {
+	return outputAuditEvent{}.Header()
+}
+
+func (c outputAuditEventCollection) Table() [][]string {
+	rows := make([][]string, len(c.events))
+	for i, e := range c.events {
+		rows[i] = outputAuditEvent(e).Columns()
+	}
+	return rows
+}
+
+func (c outputAuditEventCollection) Interface() interface{} {
+	return c.events
+}
+
+func (c *outputAuditEventCollection) Len() int {
+	return len(c.events)
+}
@@ -0,0 +1,114 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identities
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/kratos/driver"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/flagx"
+	"github.com/ory/x/servicelocatorx"
+)
+
+func NewExportCmd(slOpts []servicelocatorx.Option, dOpts []driver.RegistryOption) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export resources",
+	}
+	cmd.AddCommand(NewExportIdentitiesCmd(slOpts, dOpts))
+	configx.RegisterFlags(cmd.PersistentFlags())
+	return cmd
+}
+
+// NewExportIdentitiesCmd represents the export identities command
+func NewExportIdentitiesCmd(slOpts []servicelocatorx.Option, dOpts []driver.RegistryOption) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "identities",
+		Short: "Export all identities as newline delimited JSON",
+		Long: `Exports all identities from the database to STD_OUT, one identity per line. Every line has the format of the
+create identity request body, which means that the export can be imported again using "... import identities".
+
+Password hashes and OpenID Connect subjects are only exported when --include-credentials is set. The verifiable
+and recovery addresses, including their verification status, are only exported when --include-addresses is set.
+
+The identities are exported in batches and the progress is logged after every batch. If the export is interrupted,
+pass the last logged cursor using --after to resume it.`,
+		Example: `{{ .CommandPath }} -c config.yml --include-credentials --include-addresses > identities.jsonl
+
+{{ .Root.Name }} import identities identities.jsonl --endpoint http://localhost:4434`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var after uuid.UUID
+			if raw := flagx.MustGetString(cmd, "after"); raw != "" {
+				var err error
+				if after, err = uuid.FromString(raw); err != nil {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Unable to parse the cursor %q: %s\n", raw, err)
+					return cmdx.FailSilently(cmd)
+				}
+			}
+
+			r, err := driver.New(cmd.Context(), cmd.ErrOrStderr(), servicelocatorx.NewOptions(slOpts...), dOpts, []configx.OptionModifier{configx.WithFlags(cmd.Flags())})
+			if err != nil {
+				return err
+			}
+
+			if _, err := Export(cmd.Context(), r, cmd.OutOrStdout(),
+				identity.ExportAfter(after),
+				identity.ExportBatchSize(flagx.MustGetInt(cmd, "batch-size")),
+				identity.ExportIncludeCredentials(flagx.MustGetBool(cmd, "include-credentials")),
+				identity.ExportIncludeAddresses(flagx.MustGetBool(cmd, "include-addresses")),
+			); err != nil {
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), err)
+				return cmdx.FailSilently(cmd)
+			}
+			return nil
+		},
+	}
+
+	c.Flags().IntP("batch-size", "b", identity.DefaultExportBatchSize, "The number of identities to load at once.")
+	c.Flags().Bool("include-credentials", false, "Include the password hashes and OpenID Connect subjects.")
+	c.Flags().Bool("include-addresses", false, "Include the verifiable and recovery addresses.")
+	c.Flags().String("after", "", "Resume an interrupted export after the given cursor.")
+	return c
+}
+
+// Export writes all identities to w and logs the progress after every batch.
+func Export(ctx context.Context, r driver.Registry, w io.Writer, opts ...identity.ExportOption) (*identity.ExportProgress, error) {
+	r.Logger().Println("Identity export started.")
+
+	out := bufio.NewWriter(w)
+	progress, err := r.IdentityExporter().Export(ctx, out, append(opts, identity.ExportWithProgress(func(progress *identity.ExportProgress) {
+		// The cursor is only logged once the batch was written, so that it can always be used to resume the export.
+		if err := out.Flush(); err != nil {
+			return
+		}
+		r.Logger().
+			WithField("cursor", progress.Cursor).
+			WithField("identities", progress.Identities).
+			Info("Exported a batch of identities.")
+	}))...)
+	// Everything up to the cursor has to be written out, also if the export failed.
+	if flushErr := out.Flush(); err == nil {
+		err = errors.WithStack(flushErr)
+	}
+	if err != nil {
+		r.Logger().WithError(err).Error("Failed to export the identities.")
+		return progress, errors.WithMessagef(err, "resume the export with --after %s", progress.Cursor)
+	}
+
+	r.Logger().
+		WithField("identities", progress.Identities).
+		Println("Identity export finished.")
+	return progress, nil
+}
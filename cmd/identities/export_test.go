@@ -0,0 +1,54 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identities_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/cmd/identities"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/x/servicelocatorx"
+)
+
+func TestExportCmd(t *testing.T) {
+	c := identities.NewImportIdentitiesCmd()
+	reg := setup(t, c)
+
+	t.Run("case=exported identities can be imported", func(t *testing.T) {
+		is, _ := makeIdentities(t, reg, 3)
+
+		var out bytes.Buffer
+		progress, err := identities.Export(context.Background(), reg, &out)
+		require.NoError(t, err)
+		assert.Equal(t, len(is), progress.Identities)
+		assert.Len(t, strings.Split(strings.TrimSpace(out.String()), "\n"), len(is))
+
+		stdOut, stdErr, err := exec(c, &out)
+		require.NoError(t, err, "stdout: %s\nstderr: %s", stdOut, stdErr)
+
+		imported := gjson.Parse(stdOut).Array()
+		require.Len(t, imported, len(is))
+		for _, res := range imported {
+			id, err := uuid.FromString(res.Get("id").String())
+			require.NoError(t, err)
+			i, err := reg.Persister().GetIdentity(context.Background(), id, identity.ExpandNothing)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"foo":"bar"}`, string(i.MetadataPublic))
+		}
+	})
+
+	t.Run("case=fails on an invalid cursor", func(t *testing.T) {
+		cmd := identities.NewExportIdentitiesCmd([]servicelocatorx.Option{}, nil)
+		stdErr := execErr(t, cmd, "--after", "not-a-uuid")
+		assert.Contains(t, stdErr, "Unable to parse the cursor")
+	})
+}
@@ -14,15 +14,23 @@ import (
 	"github.com/ory/x/cmdx"
 )
 
+// parseIdentities parses a single identity, an array of identities, or newline delimited identities as written by
+// "export identities".
 func parseIdentities(raw []byte) (rawIdentities []string) {
-	res := gjson.ParseBytes(raw)
-	if !res.IsArray() {
-		return []string{res.Raw}
-	}
-	res.ForEach(func(_, v gjson.Result) bool {
-		rawIdentities = append(rawIdentities, v.Raw)
+	gjson.ForEachLine(string(raw), func(res gjson.Result) bool {
+		if !res.IsArray() {
+			rawIdentities = append(rawIdentities, res.Raw)
+			return true
+		}
+		res.ForEach(func(_, v gjson.Result) bool {
+			rawIdentities = append(rawIdentities, v.Raw)
+			return true
+		})
 		return true
 	})
+	if len(rawIdentities) == 0 {
+		return []string{gjson.ParseBytes(raw).Raw}
+	}
 	return
 }
 
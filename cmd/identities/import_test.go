@@ -124,4 +124,24 @@ func TestImportCmd(t *testing.T) {
 		_, err = reg.Persister().GetIdentity(context.Background(), id, identity.ExpandNothing)
 		assert.NoError(t, err)
 	})
+
+	t.Run("case=imports newline delimited identities from STD_IN", func(t *testing.T) {
+		var ij bytes.Buffer
+		for k := 0; k < 2; k++ {
+			require.NoError(t, json.NewEncoder(&ij).Encode(kratos.CreateIdentityBody{
+				SchemaId: config.DefaultIdentityTraitsSchemaID,
+				Traits:   map[string]interface{}{},
+			}))
+		}
+
+		stdOut, stdErr, err := exec(c, &ij)
+		require.NoError(t, err, "%s %s", stdOut, stdErr)
+
+		for _, path := range []string{"0.id", "1.id"} {
+			id, err := uuid.FromString(gjson.Get(stdOut, path).String())
+			require.NoError(t, err)
+			_, err = reg.Persister().GetIdentity(context.Background(), id, identity.ExpandNothing)
+			assert.NoError(t, err)
+		}
+	})
 }
@@ -4,12 +4,25 @@
 package identities
 
 import (
+	"fmt"
+	"net/url"
+	"time"
+
 	"github.com/spf13/cobra"
+	"github.com/tomnomnom/linkheader"
 
 	"github.com/ory/kratos/cmd/cliclient"
 	"github.com/ory/x/cmdx"
 )
 
+const (
+	FlagAction   = "action"
+	FlagActorID  = "actor-id"
+	FlagTargetID = "target-id"
+	FlagSince    = "since"
+	FlagUntil    = "until"
+)
+
 func NewListCmd() *cobra.Command {
 	c := &cobra.Command{
 		Use:     "list",
@@ -17,6 +30,7 @@ func NewListCmd() *cobra.Command {
 		Short:   "List resources",
 	}
 	c.AddCommand(NewListIdentitiesCmd())
+	c.AddCommand(NewListAuditEventsCmd())
 	cliclient.RegisterClientFlags(c.PersistentFlags())
 	cmdx.RegisterFormatFlags(c.PersistentFlags())
 	return c
@@ -57,3 +71,84 @@ func NewListIdentitiesCmd() *cobra.Command {
 		},
 	}
 }
+
+func NewListAuditEventsCmd() *cobra.Command {
+	var action, actorID, targetID string
+
+	cmd := &cobra.Command{
+		Use:   "audit-events",
+		Short: "List audit events",
+		Long: `List the audit log of identity, credential, and session mutations performed through the admin API, newest first.
+
+The audit log has to be enabled using the "audit.enabled" configuration key. If there are more events,
+the token of the next page is printed to stderr.`,
+		Example: `{{ .CommandPath }} --target-id 5a8a9d38-bd2e-4cbe-8d3c-2fee3ac9bbd1
+{{ .CommandPath }} --action identity.deleted --since 2023-01-01T00:00:00Z`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := cliclient.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			pageToken, pageSize, err := cmdx.ParseTokenPaginationArgs(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := c.IdentityApi.ListAuditEvents(cmd.Context()).PageSize(int64(pageSize))
+			if pageToken != "" {
+				req = req.PageToken(pageToken)
+			}
+			if action != "" {
+				req = req.Action(action)
+			}
+			if actorID != "" {
+				req = req.ActorId(actorID)
+			}
+			if targetID != "" {
+				req = req.TargetId(targetID)
+			}
+			for flag, set := range map[string]func(time.Time){
+				FlagSince: func(t time.Time) { req = req.Since(t) },
+				FlagUntil: func(t time.Time) { req = req.Until(t) },
+			} {
+				value, _ := cmd.Flags().GetString(flag)
+				if value == "" {
+					continue
+				}
+				t, err := time.Parse(time.RFC3339, value)
+				if err != nil {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Could not parse --%s \"%s\" as RFC 3339 timestamp: %s\n", flag, value, err)
+					return cmdx.FailSilently(cmd)
+				}
+				set(t)
+			}
+
+			events, res, err := req.Execute()
+			if err != nil {
+				return cmdx.PrintOpenAPIError(cmd, err)
+			}
+
+			cmdx.PrintTable(cmd, &outputAuditEventCollection{events: events})
+
+			for _, link := range linkheader.ParseMultiple(res.Header.Values("Link")) {
+				if link.Rel != "next" {
+					continue
+				}
+				if u, err := url.Parse(link.URL); err == nil && u.Query().Get("page_token") != "" {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Next page token: %s\n", u.Query().Get("page_token"))
+				}
+			}
+			return nil
+		},
+	}
+
+	cmdx.RegisterTokenPaginationFlags(cmd)
+	cmd.Flags().StringVar(&action, FlagAction, "", "Only list events with this action, for example identity.updated.")
+	cmd.Flags().StringVar(&actorID, FlagActorID, "", "Only list events performed by this actor.")
+	cmd.Flags().StringVar(&targetID, FlagTargetID, "", "Only list events of this identity or session ID.")
+	cmd.Flags().String(FlagSince, "", "Only list events recorded at or after this RFC 3339 timestamp.")
+	cmd.Flags().String(FlagUntil, "", "Only list events recorded at or before this RFC 3339 timestamp.")
+	return cmd
+}
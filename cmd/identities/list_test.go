@@ -7,6 +7,12 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/audit"
+	"github.com/ory/kratos/x"
 
 	"github.com/ory/kratos/cmd/identities"
 
@@ -53,3 +59,64 @@ func TestListCmd(t *testing.T) {
 		}
 	})
 }
+
+func TestListAuditEventsCmd(t *testing.T) {
+	c := identities.NewListAuditEventsCmd()
+	reg := setup(t, c)
+	ctx := context.Background()
+
+	target := x.NewUUID()
+	events := make([]audit.Event, 3)
+	for k := range events {
+		events[k] = audit.Event{
+			ID:           x.NewUUID(),
+			Action:       audit.ActionIdentityUpdated,
+			ActorType:    audit.ActorTypeUser,
+			ActorID:      "admin@ory.sh",
+			TargetType:   audit.TargetTypeIdentity,
+			TargetID:     target,
+			ChangedPaths: []string{"traits.email"},
+			CreatedAt:    time.Now().UTC().Add(time.Duration(k-3) * time.Minute),
+		}
+		require.NoError(t, reg.AuditPersister().CreateAuditEvent(ctx, &events[k]))
+	}
+	deleted := audit.Event{
+		ID:         x.NewUUID(),
+		Action:     audit.ActionIdentityDeleted,
+		ActorType:  audit.ActorTypeAnonymous,
+		TargetType: audit.TargetTypeIdentity,
+		TargetID:   x.NewUUID(),
+		CreatedAt:  time.Now().UTC(),
+	}
+	require.NoError(t, reg.AuditPersister().CreateAuditEvent(ctx, &deleted))
+
+	t.Run("case=lists all events", func(t *testing.T) {
+		stdOut := execNoErr(t, c)
+		assert.Len(t, gjson.Parse(stdOut).Array(), 4, stdOut)
+		assert.Equal(t, deleted.ID.String(), gjson.Get(stdOut, "0.id").String(), "newest events are listed first")
+	})
+
+	t.Run("case=filters events", func(t *testing.T) {
+		stdOut := execNoErr(t, c, "--"+identities.FlagTargetID, target.String())
+		assert.Len(t, gjson.Parse(stdOut).Array(), 3, stdOut)
+
+		stdOut = execNoErr(t, c, "--"+identities.FlagTargetID, "", "--"+identities.FlagAction, string(audit.ActionIdentityDeleted))
+		assert.Len(t, gjson.Parse(stdOut).Array(), 1, stdOut)
+		assert.Equal(t, deleted.ID.String(), gjson.Get(stdOut, "0.id").String())
+
+		stdOut = execNoErr(t, c, "--"+identities.FlagAction, "", "--"+identities.FlagActorID, "admin@ory.sh", "--"+identities.FlagUntil, events[1].CreatedAt.Add(time.Second).Format(time.RFC3339))
+		assert.Len(t, gjson.Parse(stdOut).Array(), 2, stdOut)
+	})
+
+	t.Run("case=prints the next page token", func(t *testing.T) {
+		stdOut, stdErr, err := exec(c, nil, "--"+identities.FlagActorID, "", "--"+identities.FlagUntil, "", "--"+cmdx.FlagPageSize, "1")
+		require.NoError(t, err, stdErr)
+		assert.Len(t, gjson.Parse(stdOut).Array(), 1, stdOut)
+		assert.Contains(t, stdErr, "Next page token: ")
+	})
+
+	t.Run("case=fails on invalid timestamps", func(t *testing.T) {
+		stdErr := execErr(t, c, "--"+cmdx.FlagPageSize, "100", "--"+identities.FlagSince, "yesterday")
+		assert.Contains(t, stdErr, "RFC 3339")
+	})
+}
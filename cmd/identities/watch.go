@@ -0,0 +1,62 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identities
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ory/graceful"
+	"github.com/ory/kratos/driver"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/servicelocatorx"
+)
+
+func NewWatchCmd(slOpts []servicelocatorx.Option, dOpts []driver.RegistryOption) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "watch",
+		Short: "Run background workers",
+	}
+	cmd.AddCommand(NewWatchIdentitiesCmd(slOpts, dOpts))
+	configx.RegisterFlags(cmd.PersistentFlags())
+	return cmd
+}
+
+// NewWatchIdentitiesCmd represents the watch identities command
+func NewWatchIdentitiesCmd(slOpts []servicelocatorx.Option, dOpts []driver.RegistryOption) *cobra.Command {
+	return &cobra.Command{
+		Use:   "identities",
+		Short: "Reactivate and delete identities whose state expired",
+		Long: `Periodically reactivates suspended identities whose suspension ended and deletes identities pending
+deletion whose deletion grace period passed. The interval is configured using identity.lifecycle.expiry_interval.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := driver.New(cmd.Context(), cmd.ErrOrStderr(), servicelocatorx.NewOptions(slOpts...), dOpts, []configx.OptionModifier{configx.WithFlags(cmd.Flags())})
+			if err != nil {
+				return err
+			}
+
+			return WatchIdentities(cmd.Context(), r)
+		},
+	}
+}
+
+func WatchIdentities(ctx context.Context, r driver.Registry) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	r.Logger().Println("Identity state expiry started.")
+	if err := graceful.Graceful(func() error {
+		return r.IdentityStateExpirer().Watch(ctx)
+	}, func(_ context.Context) error {
+		cancel()
+		return nil
+	}); err != nil {
+		r.Logger().WithError(err).Error("Failed to run identity state expiry.")
+		return err
+	}
+
+	r.Logger().Println("Identity state expiry was shutdown gracefully.")
+	return nil
+}
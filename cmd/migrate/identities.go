@@ -0,0 +1,160 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/kratos/driver"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/fetcher"
+	"github.com/ory/x/flagx"
+	"github.com/ory/x/servicelocatorx"
+)
+
+func NewMigrateIdentitiesCmd(slOpts []servicelocatorx.Option, dOpts []driver.RegistryOption) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "identities",
+		Short: "Migrate identities to another identity schema",
+		Long: `Moves all identities of the source identity schema to the target identity schema and rewrites their traits
+using a Jsonnet transform. The identity is available in the transform as std.extVar('identity') and the transform
+must return an object with the new traits in the "traits" key:
+
+	local identity = std.extVar('identity');
+	{
+	  traits: {
+	    email: identity.traits.email,
+	    name: { first: identity.traits.first_name, last: identity.traits.last_name },
+	  },
+	}
+
+Identities whose traits can not be transformed or are not valid for the target identity schema keep their schema
+and traits and are recorded as failures. Use --dry-run to only transform and validate the traits.
+
+The identities are migrated in batches and the progress is stored after every batch. If the migration is
+interrupted, pass its ID using --resume to continue after the last processed identity.`,
+		Example: `{{ .CommandPath }} -c config.yml --source customer@v1 --target customer@v2 --transform file://transform.jsonnet --dry-run
+
+{{ .CommandPath }} -c config.yml --resume 9f425a8d-7efc-4768-8f23-7647a74fdf13`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var resume uuid.UUID
+			if raw := flagx.MustGetString(cmd, "resume"); raw != "" {
+				var err error
+				if resume, err = uuid.FromString(raw); err != nil {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Unable to parse the migration ID %q: %s\n", raw, err)
+					return cmdx.FailSilently(cmd)
+				}
+			} else if flagx.MustGetString(cmd, "transform") == "" {
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "Either --transform or --resume must be set.")
+				return cmdx.FailSilently(cmd)
+			}
+
+			r, err := driver.New(cmd.Context(), cmd.ErrOrStderr(), servicelocatorx.NewOptions(slOpts...), dOpts, []configx.OptionModifier{configx.WithFlags(cmd.Flags())})
+			if err != nil {
+				return err
+			}
+
+			if resume == uuid.Nil {
+				transform, err := fetcher.NewFetcher().FetchContext(cmd.Context(), flagx.MustGetString(cmd, "transform"))
+				if err != nil {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Unable to load the Jsonnet transform: %s\n", err)
+					return cmdx.FailSilently(cmd)
+				}
+
+				sm := &identity.SchemaMigration{
+					SourceSchemaID: flagx.MustGetString(cmd, "source"),
+					TargetSchemaID: flagx.MustGetString(cmd, "target"),
+					Transform:      transform.String(),
+					DryRun:         flagx.MustGetBool(cmd, "dry-run"),
+					BatchSize:      flagx.MustGetInt(cmd, "batch-size"),
+				}
+				if err := r.IdentitySchemaMigrator().Create(cmd.Context(), sm); err != nil {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Unable to create the identity schema migration: %s\n", err)
+					return cmdx.FailSilently(cmd)
+				}
+				resume = sm.ID
+			}
+
+			sm, err := MigrateIdentities(cmd.Context(), r, resume)
+			if sm != nil {
+				cmdx.PrintRow(cmd, (*outputSchemaMigration)(sm))
+			}
+			if err != nil {
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), err)
+				return cmdx.FailSilently(cmd)
+			}
+			return nil
+		},
+	}
+
+	configx.RegisterFlags(c.PersistentFlags())
+	cmdx.RegisterFormatFlags(c.Flags())
+	c.Flags().String("source", "", "The ID of the identity schema to migrate the identities from.")
+	c.Flags().String("target", "", "The ID of the identity schema to migrate the identities to.")
+	c.Flags().String("transform", "", "The URL of the Jsonnet transform, for example file://transform.jsonnet.")
+	c.Flags().Bool("dry-run", false, "Only transform and validate the traits without updating the identities.")
+	c.Flags().IntP("batch-size", "b", identity.DefaultSchemaMigrationBatchSize, "The number of identities to migrate at once.")
+	c.Flags().String("resume", "", "Resume the failed or interrupted migration with the given ID.")
+	return c
+}
+
+// MigrateIdentities runs the identity schema migration and logs the progress after every batch.
+func MigrateIdentities(ctx context.Context, r driver.Registry, id uuid.UUID) (*identity.SchemaMigration, error) {
+	r.Logger().WithField("migration_id", id).Println("Identity schema migration started.")
+
+	sm, err := r.IdentitySchemaMigrator().Run(ctx, id, identity.SchemaMigrationWithProgress(func(sm *identity.SchemaMigration) {
+		r.Logger().
+			WithField("migration_id", sm.ID).
+			WithField("cursor", sm.Cursor).
+			WithField("processed", sm.Processed).
+			WithField("migrated", sm.Migrated).
+			WithField("failed", sm.Failed).
+			Info("Migrated a batch of identities.")
+	}))
+	if err != nil {
+		r.Logger().WithError(err).WithField("migration_id", id).Error("Failed to migrate the identities.")
+		return sm, errors.WithMessagef(err, "resume the migration with --resume %s", id)
+	}
+
+	r.Logger().
+		WithField("migration_id", sm.ID).
+		WithField("processed", sm.Processed).
+		WithField("migrated", sm.Migrated).
+		WithField("failed", sm.Failed).
+		Println("Identity schema migration finished.")
+	return sm, nil
+}
+
+type outputSchemaMigration identity.SchemaMigration
+
+func (o *outputSchemaMigration) Header() []string {
+	return []string{"ID", "SOURCE", "TARGET", "DRY RUN", "STATE", "CURSOR", "PROCESSED", "MIGRATED", "FAILED"}
+}
+
+func (o *outputSchemaMigration) Columns() []string {
+	return []string{
+		o.ID.String(),
+		o.SourceSchemaID,
+		o.TargetSchemaID,
+		strconv.FormatBool(o.DryRun),
+		string(o.State),
+		o.Cursor.String(),
+		strconv.Itoa(o.Processed),
+		strconv.Itoa(o.Migrated),
+		strconv.Itoa(o.Failed),
+	}
+}
+
+func (o *outputSchemaMigration) Interface() interface{} {
+	return o
+}
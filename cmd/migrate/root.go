@@ -18,4 +18,5 @@ func RegisterCommandRecursive(parent *cobra.Command) {
 	c := NewMigrateCmd()
 	parent.AddCommand(c)
 	c.AddCommand(NewMigrateSQLCmd())
+	c.AddCommand(NewMigrateIdentitiesCmd(nil, nil))
 }
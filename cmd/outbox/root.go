@@ -0,0 +1,28 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package outbox
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/kratos/driver"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/servicelocatorx"
+)
+
+// NewOutboxCmd creates a new outbox command
+func NewOutboxCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "outbox",
+		Short: "Commands related to the Ory Kratos transactional outbox",
+	}
+	configx.RegisterFlags(c.PersistentFlags())
+	return c
+}
+
+func RegisterCommandRecursive(parent *cobra.Command, slOpts []servicelocatorx.Option, dOpts []driver.RegistryOption) {
+	c := NewOutboxCmd()
+	parent.AddCommand(c)
+	c.AddCommand(NewWatchCmd(slOpts, dOpts))
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/ory/kratos/cmd/cipher"
 	"github.com/ory/kratos/cmd/cleanup"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/x/jsonnetsecure"
@@ -20,6 +21,7 @@ import (
 	"github.com/ory/kratos/cmd/identities"
 	"github.com/ory/kratos/cmd/jsonnet"
 	"github.com/ory/kratos/cmd/migrate"
+	"github.com/ory/kratos/cmd/outbox"
 	"github.com/ory/kratos/cmd/serve"
 	"github.com/ory/x/cmdx"
 
@@ -32,19 +34,23 @@ func NewRootCmd() (cmd *cobra.Command) {
 	}
 	cmdx.EnableUsageTemplating(cmd)
 
+	cipher.RegisterCommandRecursive(cmd, nil, nil)
 	courier.RegisterCommandRecursive(cmd, nil, nil)
 	cmd.AddCommand(identities.NewGetCmd())
 	cmd.AddCommand(identities.NewDeleteCmd())
+	cmd.AddCommand(identities.NewExportCmd(nil, nil))
 	cmd.AddCommand(jsonnet.NewFormatCmd())
 	hashers.RegisterCommandRecursive(cmd)
 	cmd.AddCommand(identities.NewImportCmd())
 	cmd.AddCommand(jsonnet.NewLintCmd())
 	cmd.AddCommand(identities.NewListCmd())
 	migrate.RegisterCommandRecursive(cmd)
+	outbox.RegisterCommandRecursive(cmd, nil, nil)
 	serve.RegisterCommandRecursive(cmd, nil, nil)
 	cleanup.RegisterCommandRecursive(cmd)
 	remote.RegisterCommandRecursive(cmd)
 	cmd.AddCommand(identities.NewValidateCmd())
+	cmd.AddCommand(identities.NewWatchCmd(nil, nil))
 	cmd.AddCommand(cmdx.Version(&config.Version, &config.Commit, &config.Date))
 
 	// Registers a hidden "jsonnet" subcommand for process-isolated Jsonnet VMs.
@@ -54,6 +54,9 @@ DON'T DO THIS IN PRODUCTION!
 	serveCmd.PersistentFlags().Bool("sqa-opt-out", false, "Disable anonymized telemetry reports - for more information please visit https://www.ory.sh/docs/ecosystem/sqa")
 	serveCmd.PersistentFlags().Bool("dev", false, "Disables critical security features to make development easier")
 	serveCmd.PersistentFlags().Bool("watch-courier", false, "Run the message courier as a background task, to simplify single-instance setup")
+	serveCmd.PersistentFlags().Bool("watch-outbox", false, "Run the outbox dispatcher as a background task, to simplify single-instance setup")
+	serveCmd.PersistentFlags().Bool("watch-identities", false, "Reactivate and delete identities whose state expired as a background task, see \"kratos watch identities\"")
+	serveCmd.PersistentFlags().Bool("rotate-cipher", false, "Re-encrypt data at rest with the current cipher secret as a background task, see \"kratos cipher rotate\"")
 	return serveCmd
 }
 
@@ -0,0 +1,166 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/x/stringsx"
+)
+
+const (
+	// DefaultEmailChannelID is the channel emails are delivered through unless their template targets another
+	// channel. Unless configured in `courier.channels`, it is derived from `courier.smtp` or `courier.http`.
+	DefaultEmailChannelID = "email"
+
+	// DefaultSMSChannelID is the channel SMS are delivered through unless their template targets another
+	// channel. Unless configured in `courier.channels`, it is derived from `courier.sms`.
+	DefaultSMSChannelID = "sms"
+)
+
+const (
+	ChannelTypeSMTP = "smtp"
+	ChannelTypeHTTP = "http"
+	ChannelTypeSMS  = "sms"
+)
+
+// Channel delivers messages, for example through an SMTP relay or to an HTTP endpoint.
+type Channel interface {
+	ID() string
+	Dispatch(ctx context.Context, msg Message) error
+}
+
+func (c *courier) newChannels(ctx context.Context) (map[string]Channel, error) {
+	conf := c.deps.CourierConfig()
+
+	configs, err := conf.CourierChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !containsChannel(configs, DefaultEmailChannelID) {
+		legacy, err := legacyEmailChannelConfig(ctx, conf)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, legacy)
+	}
+
+	if !containsChannel(configs, DefaultSMSChannelID) && conf.CourierSMSEnabled(ctx) {
+		configs = append(configs, &config.CourierChannel{
+			ID:            DefaultSMSChannelID,
+			Type:          ChannelTypeSMS,
+			RequestConfig: conf.CourierSMSRequestConfig(ctx),
+			From:          conf.CourierSMSFrom(ctx),
+		})
+	}
+
+	channels := make(map[string]Channel, len(configs))
+	for _, channelConfig := range configs {
+		if _, ok := channels[channelConfig.ID]; ok {
+			return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("The courier channel %q is configured more than once.", channelConfig.ID))
+		}
+
+		channel, err := c.newChannel(channelConfig)
+		if err != nil {
+			return nil, err
+		}
+		channels[channelConfig.ID] = channel
+	}
+
+	return channels, nil
+}
+
+func (c *courier) newChannel(channelConfig *config.CourierChannel) (Channel, error) {
+	switch channelConfig.Type {
+	case ChannelTypeSMTP:
+		if channelConfig.SMTPConfig == nil {
+			return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("The courier channel %q is of type %q but has no SMTP configuration.", channelConfig.ID, channelConfig.Type))
+		}
+		return newSMTPChannel(c, channelConfig.ID, channelConfig.SMTPConfig)
+	case ChannelTypeHTTP:
+		return newHTTPChannel(c, channelConfig.ID, channelConfig.RequestConfig), nil
+	case ChannelTypeSMS:
+		return newSMSChannel(c, channelConfig.ID, channelConfig.RequestConfig, channelConfig.From), nil
+	default:
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("The courier channel %q has the unknown type %q.", channelConfig.ID, channelConfig.Type))
+	}
+}
+
+// legacyEmailChannelConfig maps `courier.delivery_strategy`, `courier.smtp` and `courier.http` onto the default email
+// channel.
+func legacyEmailChannelConfig(ctx context.Context, conf config.CourierConfigs) (*config.CourierChannel, error) {
+	if conf.CourierEmailStrategy(ctx) == ChannelTypeHTTP {
+		return &config.CourierChannel{
+			ID:            DefaultEmailChannelID,
+			Type:          ChannelTypeHTTP,
+			RequestConfig: conf.CourierEmailRequestConfig(ctx),
+		}, nil
+	}
+
+	uri, err := conf.CourierSMTPURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config.CourierChannel{
+		ID:   DefaultEmailChannelID,
+		Type: ChannelTypeSMTP,
+		SMTPConfig: &config.CourierSMTPConfig{
+			ConnectionURI:  uri.String(),
+			ClientCertPath: conf.CourierSMTPClientCertPath(ctx),
+			ClientKeyPath:  conf.CourierSMTPClientKeyPath(ctx),
+			FromAddress:    conf.CourierSMTPFrom(ctx),
+			FromName:       conf.CourierSMTPFromName(ctx),
+			Headers:        conf.CourierSMTPHeaders(ctx),
+			LocalName:      conf.CourierSMTPLocalName(ctx),
+		},
+	}, nil
+}
+
+func containsChannel(configs []*config.CourierChannel, id string) bool {
+	for _, c := range configs {
+		if c.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// templateChannel returns the ID of the channel messages of the template type are queued for.
+func (c *courier) templateChannel(ctx context.Context, templateType TemplateType, defaultChannel string) (string, error) {
+	id := stringsx.Coalesce(c.deps.CourierConfig().CourierTemplateChannel(ctx, string(templateType)), defaultChannel)
+	if _, ok := c.channels[id]; !ok && id != defaultChannel {
+		return "", errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Template %q targets the courier channel %q, which is not configured.", templateType, id))
+	}
+	return id, nil
+}
+
+// messageChannel returns the channel the message is delivered through.
+func (c *courier) messageChannel(msg Message) (Channel, error) {
+	id := msg.Channel
+	if id == "" {
+		switch msg.Type {
+		case MessageTypeEmail:
+			id = DefaultEmailChannelID
+		case MessageTypePhone:
+			id = DefaultSMSChannelID
+		default:
+			return nil, errors.Errorf("received unexpected message type: %d", msg.Type)
+		}
+	}
+
+	channel, ok := c.channels[id]
+	if !ok {
+		if id == DefaultSMSChannelID {
+			return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Courier tried to deliver an sms but courier.sms.enabled is set to false!"))
+		}
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Courier tried to deliver a message through the channel %q, which is not configured.", id))
+	}
+	return channel, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/cenkalti/backoff"
 	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
 
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/x"
@@ -50,27 +51,38 @@ type (
 	}
 
 	courier struct {
-		smsClient   *smsClient
-		smtpClient  *smtpClient
-		httpClient  *httpClient
+		channels    map[string]Channel
+		limiters    map[string]*rate.Limiter
 		deps        Dependencies
 		failOnError bool
 		backoff     backoff.BackOff
+
+		getEmailTemplateType        func(t EmailTemplate) (TemplateType, error)
+		newEmailTemplateFromMessage func(d template.Dependencies, msg Message) (EmailTemplate, error)
+		getSMSTemplateType          func(t SMSTemplate) (TemplateType, error)
+		newSMSTemplateFromMessage   func(d Dependencies, msg Message) (SMSTemplate, error)
 	}
 )
 
 func NewCourier(ctx context.Context, deps Dependencies) (Courier, error) {
-	smtp, err := newSMTP(ctx, deps)
+	c := &courier{
+		deps:    deps,
+		backoff: backoff.NewExponentialBackOff(),
+
+		getEmailTemplateType:        GetEmailTemplateType,
+		newEmailTemplateFromMessage: NewEmailTemplateFromMessage,
+		getSMSTemplateType:          SMSTemplateType,
+		newSMSTemplateFromMessage:   NewSMSTemplateFromMessage,
+	}
+
+	channels, err := c.newChannels(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return &courier{
-		smsClient:  newSMS(ctx, deps),
-		smtpClient: smtp,
-		httpClient: newHTTP(ctx, deps),
-		deps:       deps,
-		backoff:    backoff.NewExponentialBackOff(),
-	}, nil
+	c.channels = channels
+	c.limiters = c.newLimiters(ctx)
+
+	return c, nil
 }
 
 func (c *courier) FailOnDispatchError() {
@@ -5,8 +5,12 @@ package courier
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/courier/template"
 )
 
 func (c *courier) DispatchMessage(ctx context.Context, msg Message) error {
@@ -19,17 +23,15 @@ func (c *courier) DispatchMessage(ctx context.Context, msg Message) error {
 		return err
 	}
 
-	switch msg.Type {
-	case MessageTypeEmail:
-		if err := c.dispatchEmail(ctx, msg); err != nil {
-			return err
-		}
-	case MessageTypePhone:
-		if err := c.dispatchSMS(ctx, msg); err != nil {
-			return err
-		}
-	default:
-		return errors.Errorf("received unexpected message type: %d", msg.Type)
+	channel, err := c.messageChannel(msg)
+	if err != nil {
+		return err
+	}
+
+	// Render the templates in the locale the message was queued with.
+	ctx = template.WithLocale(ctx, msg.Locale)
+	if err := channel.Dispatch(ctx, msg); err != nil {
+		return err
 	}
 
 	if err := c.deps.CourierPersister().SetMessageStatus(ctx, msg.ID, MessageStatusSent); err != nil {
@@ -45,6 +47,7 @@ func (c *courier) DispatchMessage(ctx context.Context, msg Message) error {
 		WithField("message_id", msg.ID).
 		WithField("message_nid", msg.NID).
 		WithField("message_type", msg.Type).
+		WithField("message_channel", channel.ID()).
 		WithField("message_template_type", msg.TemplateType).
 		WithField("message_subject", msg.Subject).
 		Debug("Courier sent out message.")
@@ -79,6 +82,12 @@ func (c *courier) DispatchQueue(ctx context.Context) error {
 				WithField("message_nid", msg.NID).
 				Warnf(`Message was abandoned because it did not deliver after %d attempts`, msg.SendCount)
 
+		} else if until, reason, err := c.throttledUntil(ctx, msg); err != nil {
+			return err
+		} else if !until.IsZero() {
+			if err := c.throttleMessage(ctx, msg, until, reason); err != nil {
+				return err
+			}
 		} else if err := c.DispatchMessage(ctx, msg); err != nil {
 
 			if err := c.deps.CourierPersister().RecordDispatch(ctx, msg.ID, CourierMessageDispatchStatusFailed, err); err != nil {
@@ -115,3 +124,33 @@ func (c *courier) DispatchQueue(ctx context.Context) error {
 
 	return nil
 }
+
+// throttleMessage queues a message again which a rate limit prevents from being delivered before the given time. The
+// message's send count is not incremented, so throttling never causes a message to be abandoned.
+func (c *courier) throttleMessage(ctx context.Context, msg Message, until time.Time, reason *herodot.DefaultError) error {
+	if err := c.deps.CourierPersister().ThrottleMessage(ctx, msg.ID, until); err != nil {
+		c.deps.Logger().
+			WithError(err).
+			WithField("message_id", msg.ID).
+			WithField("message_nid", msg.NID).
+			Error(`Unable to reset the throttled message's status to "queued".`)
+		return err
+	}
+
+	if err := c.deps.CourierPersister().RecordDispatch(ctx, msg.ID, CourierMessageDispatchStatusThrottled, reason); err != nil {
+		c.deps.Logger().
+			WithError(err).
+			WithField("message_id", msg.ID).
+			WithField("message_nid", msg.NID).
+			Error(`Unable to record throttled log entry.`)
+		// continue with execution, as the message was queued again
+	}
+
+	c.deps.Logger().
+		WithField("message_id", msg.ID).
+		WithField("message_nid", msg.NID).
+		WithField("throttled_until", until).
+		Debugf("Courier throttled message: %s", reason.Reason())
+
+	return nil
+}
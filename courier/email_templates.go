@@ -38,6 +38,13 @@ const (
 	TypeVerificationValid       TemplateType = "verification_valid"
 	TypeVerificationCodeInvalid TemplateType = "verification_code_invalid"
 	TypeVerificationCodeValid   TemplateType = "verification_code_valid"
+	TypeLoginCodeValid          TemplateType = "login_code_valid"
+	TypeRegistrationCodeValid   TemplateType = "registration_code_valid"
+	TypeSecurityNewDeviceLogin  TemplateType = "security_new_device_login"
+	TypeSecurityPasswordChanged TemplateType = "security_password_changed"
+	TypeSecurityMFAAdded        TemplateType = "security_mfa_added"
+	TypeSecurityMFARemoved      TemplateType = "security_mfa_removed"
+	TypeSecurityRecoveryUsed    TemplateType = "security_recovery_used"
 	TypeOTP                     TemplateType = "otp"
 	TypeTestStub                TemplateType = "stub"
 )
@@ -60,6 +67,20 @@ func GetEmailTemplateType(t EmailTemplate) (TemplateType, error) {
 		return TypeVerificationCodeInvalid, nil
 	case *email.VerificationCodeValid:
 		return TypeVerificationCodeValid, nil
+	case *email.LoginCodeValid:
+		return TypeLoginCodeValid, nil
+	case *email.RegistrationCodeValid:
+		return TypeRegistrationCodeValid, nil
+	case *email.SecurityNewDeviceLogin:
+		return TypeSecurityNewDeviceLogin, nil
+	case *email.SecurityPasswordChanged:
+		return TypeSecurityPasswordChanged, nil
+	case *email.SecurityMFAAdded:
+		return TypeSecurityMFAAdded, nil
+	case *email.SecurityMFARemoved:
+		return TypeSecurityMFARemoved, nil
+	case *email.SecurityRecoveryUsed:
+		return TypeSecurityRecoveryUsed, nil
 	case *email.TestStub:
 		return TypeTestStub, nil
 	default:
@@ -117,6 +138,48 @@ func NewEmailTemplateFromMessage(d template.Dependencies, msg Message) (EmailTem
 			return nil, err
 		}
 		return email.NewVerificationCodeValid(d, &t), nil
+	case TypeLoginCodeValid:
+		var t email.LoginCodeValidModel
+		if err := json.Unmarshal(msg.TemplateData, &t); err != nil {
+			return nil, err
+		}
+		return email.NewLoginCodeValid(d, &t), nil
+	case TypeRegistrationCodeValid:
+		var t email.RegistrationCodeValidModel
+		if err := json.Unmarshal(msg.TemplateData, &t); err != nil {
+			return nil, err
+		}
+		return email.NewRegistrationCodeValid(d, &t), nil
+	case TypeSecurityNewDeviceLogin:
+		var t email.SecurityNewDeviceLoginModel
+		if err := json.Unmarshal(msg.TemplateData, &t); err != nil {
+			return nil, err
+		}
+		return email.NewSecurityNewDeviceLogin(d, &t), nil
+	case TypeSecurityPasswordChanged:
+		var t email.SecurityPasswordChangedModel
+		if err := json.Unmarshal(msg.TemplateData, &t); err != nil {
+			return nil, err
+		}
+		return email.NewSecurityPasswordChanged(d, &t), nil
+	case TypeSecurityMFAAdded:
+		var t email.SecurityMFAAddedModel
+		if err := json.Unmarshal(msg.TemplateData, &t); err != nil {
+			return nil, err
+		}
+		return email.NewSecurityMFAAdded(d, &t), nil
+	case TypeSecurityMFARemoved:
+		var t email.SecurityMFARemovedModel
+		if err := json.Unmarshal(msg.TemplateData, &t); err != nil {
+			return nil, err
+		}
+		return email.NewSecurityMFARemoved(d, &t), nil
+	case TypeSecurityRecoveryUsed:
+		var t email.SecurityRecoveryUsedModel
+		if err := json.Unmarshal(msg.TemplateData, &t); err != nil {
+			return nil, err
+		}
+		return email.NewSecurityRecoveryUsed(d, &t), nil
 	case TypeTestStub:
 		var t email.TestStubModel
 		if err := json.Unmarshal(msg.TemplateData, &t); err != nil {
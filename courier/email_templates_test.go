@@ -26,6 +26,13 @@ func TestGetTemplateType(t *testing.T) {
 		courier.TypeVerificationValid:       &email.VerificationValid{},
 		courier.TypeVerificationCodeInvalid: &email.VerificationCodeInvalid{},
 		courier.TypeVerificationCodeValid:   &email.VerificationCodeValid{},
+		courier.TypeLoginCodeValid:          &email.LoginCodeValid{},
+		courier.TypeRegistrationCodeValid:   &email.RegistrationCodeValid{},
+		courier.TypeSecurityNewDeviceLogin:  &email.SecurityNewDeviceLogin{},
+		courier.TypeSecurityPasswordChanged: &email.SecurityPasswordChanged{},
+		courier.TypeSecurityMFAAdded:        &email.SecurityMFAAdded{},
+		courier.TypeSecurityMFARemoved:      &email.SecurityMFARemoved{},
+		courier.TypeSecurityRecoveryUsed:    &email.SecurityRecoveryUsed{},
 		courier.TypeTestStub:                &email.TestStub{},
 	} {
 		t.Run(fmt.Sprintf("case=%s", expectedType), func(t *testing.T) {
@@ -49,6 +56,13 @@ func TestNewEmailTemplateFromMessage(t *testing.T) {
 		courier.TypeVerificationValid:       email.NewVerificationValid(reg, &email.VerificationValidModel{To: "faz", VerificationURL: "http://bar.foo"}),
 		courier.TypeVerificationCodeInvalid: email.NewVerificationCodeInvalid(reg, &email.VerificationCodeInvalidModel{To: "baz"}),
 		courier.TypeVerificationCodeValid:   email.NewVerificationCodeValid(reg, &email.VerificationCodeValidModel{To: "faz", VerificationURL: "http://bar.foo", VerificationCode: "123456678"}),
+		courier.TypeLoginCodeValid:          email.NewLoginCodeValid(reg, &email.LoginCodeValidModel{To: "far", LoginCode: "123456"}),
+		courier.TypeRegistrationCodeValid:   email.NewRegistrationCodeValid(reg, &email.RegistrationCodeValidModel{To: "far", RegistrationCode: "123456"}),
+		courier.TypeSecurityNewDeviceLogin:  email.NewSecurityNewDeviceLogin(reg, &email.SecurityNewDeviceLoginModel{To: "far", Device: email.DeviceModel{IPAddress: "192.0.2.1", UserAgent: "agent", Location: "Munich, DE"}}),
+		courier.TypeSecurityPasswordChanged: email.NewSecurityPasswordChanged(reg, &email.SecurityPasswordChangedModel{To: "far", Device: email.DeviceModel{IPAddress: "192.0.2.1", UserAgent: "agent", Location: "Munich, DE"}}),
+		courier.TypeSecurityMFAAdded:        email.NewSecurityMFAAdded(reg, &email.SecurityMFAAddedModel{To: "far", Device: email.DeviceModel{IPAddress: "192.0.2.1", UserAgent: "agent", Location: "Munich, DE"}, Method: "totp"}),
+		courier.TypeSecurityMFARemoved:      email.NewSecurityMFARemoved(reg, &email.SecurityMFARemovedModel{To: "far", Device: email.DeviceModel{IPAddress: "192.0.2.1", UserAgent: "agent", Location: "Munich, DE"}, Method: "webauthn"}),
+		courier.TypeSecurityRecoveryUsed:    email.NewSecurityRecoveryUsed(reg, &email.SecurityRecoveryUsedModel{To: "far", Device: email.DeviceModel{IPAddress: "192.0.2.1", UserAgent: "agent", Location: "Munich, DE"}}),
 		courier.TypeTestStub:                email.NewTestStub(reg, &email.TestStubModel{To: "far", Subject: "test subject", Body: "test body"}),
 	} {
 		t.Run(fmt.Sprintf("case=%s", tmplType), func(t *testing.T) {
@@ -5,11 +5,15 @@ package courier
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 
 	"github.com/ory/herodot"
+	"github.com/ory/x/jsonx"
 	"github.com/ory/x/pagination/keysetpagination"
 	"github.com/ory/x/pagination/migrationpagination"
 
@@ -22,6 +26,10 @@ import (
 const AdminRouteCourier = "/courier"
 const AdminRouteListMessages = AdminRouteCourier + "/messages"
 const AdminRouteGetMessage = AdminRouteCourier + "/messages/:msgID"
+const AdminRouteRetryMessage = AdminRouteGetMessage + "/retry"
+const AdminRouteCancelMessage = AdminRouteGetMessage + "/cancel"
+const AdminRouteDeadLetters = AdminRouteCourier + "/dead-letters"
+const AdminRouteRetryDeadLetters = AdminRouteDeadLetters + "/retry"
 
 type (
 	handlerDependencies interface {
@@ -44,14 +52,28 @@ func NewHandler(r handlerDependencies) *Handler {
 }
 
 func (h *Handler) RegisterPublicRoutes(public *x.RouterPublic) {
-	h.r.CSRFHandler().IgnoreGlobs(x.AdminPrefix+AdminRouteListMessages, AdminRouteListMessages)
+	h.r.CSRFHandler().IgnoreGlobs(
+		x.AdminPrefix+AdminRouteListMessages, AdminRouteListMessages,
+		x.AdminPrefix+AdminRouteListMessages+"/*/*", AdminRouteListMessages+"/*/*",
+		x.AdminPrefix+AdminRouteRetryDeadLetters, AdminRouteRetryDeadLetters,
+	)
 	public.GET(x.AdminPrefix+AdminRouteListMessages, x.RedirectToAdminRoute(h.r))
+	public.DELETE(x.AdminPrefix+AdminRouteListMessages, x.RedirectToAdminRoute(h.r))
 	public.GET(x.AdminPrefix+AdminRouteGetMessage, x.RedirectToAdminRoute(h.r))
+	public.POST(x.AdminPrefix+AdminRouteRetryMessage, x.RedirectToAdminRoute(h.r))
+	public.POST(x.AdminPrefix+AdminRouteCancelMessage, x.RedirectToAdminRoute(h.r))
+	public.GET(x.AdminPrefix+AdminRouteDeadLetters, x.RedirectToAdminRoute(h.r))
+	public.POST(x.AdminPrefix+AdminRouteRetryDeadLetters, x.RedirectToAdminRoute(h.r))
 }
 
 func (h *Handler) RegisterAdminRoutes(admin *x.RouterAdmin) {
 	admin.GET(AdminRouteListMessages, h.listCourierMessages)
+	admin.DELETE(AdminRouteListMessages, h.purgeCourierMessages)
 	admin.GET(AdminRouteGetMessage, h.getCourierMessage)
+	admin.POST(AdminRouteRetryMessage, h.retryCourierMessage)
+	admin.POST(AdminRouteCancelMessage, h.cancelCourierMessage)
+	admin.GET(AdminRouteDeadLetters, h.listCourierDeadLetters)
+	admin.POST(AdminRouteRetryDeadLetters, h.retryCourierDeadLetters)
 }
 
 // Paginated Courier Message List Response
@@ -208,3 +230,338 @@ func (h *Handler) getCourierMessage(w http.ResponseWriter, r *http.Request, ps h
 
 	h.r.Writer().Write(w, r, message)
 }
+
+// Courier Message ID Parameters
+//
+// swagger:parameters retryCourierMessage cancelCourierMessage
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type courierMessageIDParameters struct {
+	// MessageID is the ID of the message.
+	//
+	// required: true
+	// in: path
+	MessageID string `json:"id"`
+}
+
+func parseMessageID(ps httprouter.Params) (uuid.UUID, error) {
+	msgID, err := uuid.FromString(ps.ByName("msgID"))
+	if err != nil {
+		return uuid.Nil, errors.WithStack(herodot.ErrBadRequest.WithError(err.Error()).WithDebugf("could not parse parameter {id} as UUID, got %s", ps.ByName("msgID")))
+	}
+	return msgID, nil
+}
+
+func (h *Handler) writeMessage(w http.ResponseWriter, r *http.Request, msgID uuid.UUID) {
+	message, err := h.r.CourierPersister().FetchMessage(r.Context(), msgID)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if !h.r.Config().IsInsecureDevMode(r.Context()) {
+		message.Body = "<redacted-unless-dev-mode>"
+	}
+
+	h.r.Writer().Write(w, r, message)
+}
+
+// swagger:route POST /admin/courier/messages/{id}/retry courier retryCourierMessage
+//
+// # Retry an Abandoned Message
+//
+// Queues an abandoned message again and resets its send count, so that the courier retries
+// to deliver it. The dispatch history of the message is kept.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//		oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//		200: message
+//		400: errorGeneric
+//		404: errorGeneric
+//		409: errorGeneric
+//		default: errorGeneric
+func (h *Handler) retryCourierMessage(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	msgID, err := parseMessageID(ps)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	count, err := h.r.CourierPersister().RequeueMessages(r.Context(), DeadLetterFilter{IDs: []uuid.UUID{msgID}})
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if count == 0 {
+		if _, err := h.r.CourierPersister().FetchMessage(r.Context(), msgID); err != nil {
+			h.r.Writer().WriteError(w, r, err)
+			return
+		}
+		h.r.Writer().WriteError(w, r, errors.WithStack(ErrMessageNotAbandoned))
+		return
+	}
+
+	h.writeMessage(w, r, msgID)
+}
+
+// swagger:route POST /admin/courier/messages/{id}/cancel courier cancelCourierMessage
+//
+// # Cancel a Queued Message
+//
+// Cancels a message which is still queued, so that the courier does not deliver it.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//		oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//		200: message
+//		400: errorGeneric
+//		404: errorGeneric
+//		409: errorGeneric
+//		default: errorGeneric
+func (h *Handler) cancelCourierMessage(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	msgID, err := parseMessageID(ps)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if err := h.r.CourierPersister().CancelMessage(r.Context(), msgID); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.writeMessage(w, r, msgID)
+}
+
+// Courier Message Count
+//
+// swagger:model courierMessageCount
+type MessageCount struct {
+	// Count is the number of messages affected by the operation.
+	//
+	// required: true
+	Count int `json:"count"`
+}
+
+// Purge Courier Messages Parameters
+//
+// swagger:parameters purgeCourierMessages
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type purgeCourierMessagesParameters struct {
+	// OlderThan is the minimum age of the purged messages, for example `720h`.
+	//
+	// required: true
+	// in: query
+	OlderThan string `json:"older_than"`
+
+	// Status restricts purging to messages with this status. Only sent, abandoned, and
+	// cancelled messages can be purged. If no value is provided, messages with any of
+	// these statuses are purged.
+	//
+	// required: false
+	// in: query
+	Status *MessageStatus `json:"status"`
+}
+
+// swagger:route DELETE /admin/courier/messages courier purgeCourierMessages
+//
+// # Purge Messages
+//
+// Deletes sent, abandoned, and cancelled messages older than the given age, including their
+// dispatch history. Queued messages and messages which are being processed are never purged.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//		oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//		200: courierMessageCount
+//		400: errorGeneric
+//		default: errorGeneric
+func (h *Handler) purgeCourierMessages(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	olderThan, err := time.ParseDuration(r.URL.Query().Get("older_than"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithReason("The query parameter older_than must be a duration such as 720h.").WithDebug(err.Error())))
+		return
+	}
+
+	statuses := []MessageStatus{MessageStatusSent, MessageStatusAbandoned, MessageStatusCancelled}
+	if r.URL.Query().Has("status") {
+		status, err := ToMessageStatus(r.URL.Query().Get("status"))
+		if err != nil {
+			h.r.Writer().WriteError(w, r, err)
+			return
+		}
+		if status == MessageStatusQueued || status == MessageStatusProcessing {
+			h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithReasonf("Messages with status %s can not be purged.", status)))
+			return
+		}
+		statuses = []MessageStatus{status}
+	}
+
+	count, err := h.r.CourierPersister().PurgeMessages(r.Context(), time.Now().Add(-olderThan), statuses)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, &MessageCount{Count: count})
+}
+
+// Paginated Courier Dead Letter List Response
+//
+// swagger:response listCourierDeadLetters
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listCourierDeadLettersResponse struct {
+	migrationpagination.ResponseHeaderAnnotation
+
+	// List of abandoned messages including their dispatches
+	//
+	// in:body
+	Body []Message
+}
+
+// Paginated List Courier Dead Letters Parameters
+//
+// swagger:parameters listCourierDeadLetters
+type ListCourierDeadLettersParameters struct {
+	keysetpagination.RequestParameters
+
+	// Recipient filters out messages based on recipient.
+	// If no value is provided, it doesn't take effect on filter.
+	//
+	// required: false
+	// in: query
+	Recipient string `json:"recipient"`
+}
+
+// swagger:route GET /admin/courier/dead-letters courier listCourierDeadLetters
+//
+// # List Dead Letters
+//
+// Lists all messages which were abandoned because they could not be delivered, together with
+// the errors of all attempts to deliver them.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: listCourierDeadLetters
+//	  400: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) listCourierDeadLetters(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	opts, err := keysetpagination.Parse(r.URL.Query(), keysetpagination.NewMapPageToken)
+	if err != nil {
+		h.r.Writer().WriteErrorCode(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	l, tc, nextPage, err := h.r.CourierPersister().ListDeadLetters(r.Context(), ListCourierDeadLettersParameters{
+		Recipient: r.URL.Query().Get("recipient"),
+	}, opts)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if !h.r.Config().IsInsecureDevMode(r.Context()) {
+		for i := range l {
+			l[i].Body = "<redacted-unless-dev-mode>"
+		}
+	}
+
+	w.Header().Set("X-Total-Count", fmt.Sprint(tc))
+	keysetpagination.Header(w, r.URL, nextPage)
+	h.r.Writer().Write(w, r, l)
+}
+
+// Courier Dead Letter Filter
+//
+// swagger:model courierDeadLetterFilter
+type DeadLetterFilter struct {
+	// IDs restricts the filter to the messages with these IDs.
+	IDs []uuid.UUID `json:"ids"`
+
+	// Recipient restricts the filter to messages sent to this recipient.
+	Recipient string `json:"recipient"`
+
+	// TemplateType restricts the filter to messages of this template type.
+	TemplateType TemplateType `json:"template_type"`
+}
+
+// Retry Courier Dead Letters Parameters
+//
+// swagger:parameters retryCourierDeadLetters
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type retryCourierDeadLettersParameters struct {
+	// in: body
+	Body DeadLetterFilter
+}
+
+// swagger:route POST /admin/courier/dead-letters/retry courier retryCourierDeadLetters
+//
+// # Retry Dead Letters
+//
+// Queues all abandoned messages matching the filter again and resets their send count, so
+// that the courier retries to deliver them. An empty filter matches all abandoned messages.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//		oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//		200: courierMessageCount
+//		400: errorGeneric
+//		default: errorGeneric
+func (h *Handler) retryCourierDeadLetters(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var filter DeadLetterFilter
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&filter); err != nil && !errors.Is(err, io.EOF) {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithReason("Unable to decode the request body.").WithDebug(err.Error())))
+		return
+	}
+
+	count, err := h.r.CourierPersister().RequeueMessages(r.Context(), filter)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, &MessageCount{Count: count})
+}
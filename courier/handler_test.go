@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/ory/x/ioutilx"
 	"github.com/ory/x/pagination/keysetpagination"
 	"github.com/ory/x/snapshotx"
+	"github.com/ory/x/sqlcon"
 	"github.com/ory/x/urlx"
 
 	"github.com/stretchr/testify/assert"
@@ -279,4 +281,128 @@ func TestHandler(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("handler=deadLetters", func(t *testing.T) {
+		conf.MustSet(ctx, "dev", true)
+
+		newMessage := func(t *testing.T, status courier.MessageStatus) courier.Message {
+			var message courier.Message
+			require.NoError(t, faker.FakeData(&message))
+			message.Type = courier.MessageTypeEmail
+			message.Recipient = x.NewUUID().String() + "@ory.sh"
+			message.TemplateType = courier.TypeTestStub
+			require.NoError(t, reg.CourierPersister().AddMessage(ctx, &message))
+			require.NoError(t, reg.CourierPersister().SetMessageStatus(ctx, message.ID, status))
+			return message
+		}
+
+		do := func(t *testing.T, method, href string, body io.Reader, expectCode int) gjson.Result {
+			t.Helper()
+			req, err := http.NewRequest(method, adminTS.URL+href, body)
+			require.NoError(t, err)
+			res, err := adminTS.Client().Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			raw := ioutilx.MustReadAll(res.Body)
+			assert.EqualValuesf(t, expectCode, res.StatusCode, "%s", raw)
+			return gjson.ParseBytes(raw)
+		}
+
+		status := func(t *testing.T, id uuid.UUID) courier.MessageStatus {
+			message, err := reg.CourierPersister().FetchMessage(ctx, id)
+			require.NoError(t, err)
+			return message.Status
+		}
+
+		t.Run("case=lists abandoned messages with their dispatches", func(t *testing.T) {
+			abandoned := newMessage(t, courier.MessageStatusAbandoned)
+			require.NoError(t, reg.CourierPersister().RecordDispatch(ctx, abandoned.ID, courier.CourierMessageDispatchStatusFailed, errors.New("relay is down")))
+			newMessage(t, courier.MessageStatusSent)
+
+			// Only abandoned messages are dead letters.
+			for _, m := range get(t, adminTS, courier.AdminRouteDeadLetters, http.StatusOK).Array() {
+				assert.Equal(t, "abandoned", m.Get("status").String())
+			}
+
+			body := get(t, adminTS, courier.AdminRouteDeadLetters+"?recipient="+abandoned.Recipient, http.StatusOK)
+			require.Len(t, body.Array(), 1, "%s", body.Raw)
+			assert.Equal(t, abandoned.ID.String(), body.Get("0.id").String())
+			assert.Equal(t, "relay is down", body.Get("0.dispatches.0.error.message").String(), "%s", body.Raw)
+
+			assert.Len(t, get(t, publicTS, x.AdminPrefix+courier.AdminRouteDeadLetters+"?recipient="+abandoned.Recipient, http.StatusOK).Array(), 1)
+		})
+
+		t.Run("case=retries a single abandoned message", func(t *testing.T) {
+			abandoned := newMessage(t, courier.MessageStatusAbandoned)
+			require.NoError(t, reg.CourierPersister().IncrementMessageSendCount(ctx, abandoned.ID))
+
+			body := do(t, "POST", "/admin/courier/messages/"+abandoned.ID.String()+"/retry", nil, http.StatusOK)
+			assert.Equal(t, "queued", body.Get("status").String())
+			assert.EqualValues(t, 0, body.Get("send_count").Int())
+
+			body = do(t, "POST", "/admin/courier/messages/"+abandoned.ID.String()+"/retry", nil, http.StatusConflict)
+			assert.Equal(t, courier.ErrMessageNotAbandoned.Reason(), body.Get("error.reason").String())
+
+			do(t, "POST", "/admin/courier/messages/"+x.NewUUID().String()+"/retry", nil, http.StatusNotFound)
+			do(t, "POST", "/admin/courier/messages/not-a-uuid/retry", nil, http.StatusBadRequest)
+		})
+
+		t.Run("case=retries abandoned messages by filter", func(t *testing.T) {
+			matching := newMessage(t, courier.MessageStatusAbandoned)
+			other := newMessage(t, courier.MessageStatusAbandoned)
+			_, err := reg.Persister().GetConnection(ctx).RawQuery("UPDATE courier_messages SET recipient = ?, template_type = ? WHERE id = ?", matching.Recipient, courier.TypeOTP, other.ID).ExecWithCount()
+			require.NoError(t, err)
+
+			body := do(t, "POST", courier.AdminRouteRetryDeadLetters, strings.NewReader(`{"recipient":"`+matching.Recipient+`","template_type":"stub"}`), http.StatusOK)
+			assert.EqualValues(t, 1, body.Get("count").Int(), "%s", body.Raw)
+			assert.Equal(t, courier.MessageStatusQueued, status(t, matching.ID))
+			assert.Equal(t, courier.MessageStatusAbandoned, status(t, other.ID))
+
+			body = do(t, "POST", courier.AdminRouteRetryDeadLetters, strings.NewReader(`{"ids":["`+other.ID.String()+`"]}`), http.StatusOK)
+			assert.EqualValues(t, 1, body.Get("count").Int(), "%s", body.Raw)
+			assert.Equal(t, courier.MessageStatusQueued, status(t, other.ID))
+
+			do(t, "POST", courier.AdminRouteRetryDeadLetters, strings.NewReader(`{"unknown":true}`), http.StatusBadRequest)
+		})
+
+		t.Run("case=cancels queued messages", func(t *testing.T) {
+			queued := newMessage(t, courier.MessageStatusQueued)
+
+			body := do(t, "POST", "/admin/courier/messages/"+queued.ID.String()+"/cancel", nil, http.StatusOK)
+			assert.Equal(t, "cancelled", body.Get("status").String())
+
+			body = do(t, "POST", "/admin/courier/messages/"+queued.ID.String()+"/cancel", nil, http.StatusConflict)
+			assert.Equal(t, courier.ErrMessageNotQueued.Reason(), body.Get("error.reason").String())
+
+			do(t, "POST", "/admin/courier/messages/"+x.NewUUID().String()+"/cancel", nil, http.StatusNotFound)
+		})
+
+		t.Run("case=purges old messages", func(t *testing.T) {
+			sent := newMessage(t, courier.MessageStatusSent)
+			cancelled := newMessage(t, courier.MessageStatusCancelled)
+			queued := newMessage(t, courier.MessageStatusQueued)
+			require.NoError(t, reg.CourierPersister().RecordDispatch(ctx, sent.ID, courier.CourierMessageDispatchStatusSuccess, nil))
+
+			// Nothing is old enough yet.
+			body := do(t, "DELETE", courier.AdminRouteListMessages+"?older_than=1h", nil, http.StatusOK)
+			assert.EqualValues(t, 0, body.Get("count").Int())
+
+			time.Sleep(time.Second)
+			body = do(t, "DELETE", courier.AdminRouteListMessages+"?older_than=1ms&status=cancelled", nil, http.StatusOK)
+			assert.GreaterOrEqual(t, body.Get("count").Int(), int64(1))
+			_, err := reg.CourierPersister().FetchMessage(ctx, cancelled.ID)
+			require.ErrorIs(t, err, sqlcon.ErrNoRows)
+
+			assert.Equal(t, courier.MessageStatusSent, status(t, sent.ID))
+
+			do(t, "DELETE", courier.AdminRouteListMessages+"?older_than=1ms", nil, http.StatusOK)
+			_, err = reg.CourierPersister().FetchMessage(ctx, sent.ID)
+			require.ErrorIs(t, err, sqlcon.ErrNoRows)
+			assert.Equal(t, courier.MessageStatusQueued, status(t, queued.ID))
+
+			do(t, "DELETE", courier.AdminRouteListMessages+"?older_than=1ms&status=queued", nil, http.StatusBadRequest)
+			do(t, "DELETE", courier.AdminRouteListMessages, nil, http.StatusBadRequest)
+		})
+	})
 }
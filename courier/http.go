@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/pkg/errors"
+
 	"github.com/ory/kratos/request"
 	"github.com/ory/x/otelx"
 )
@@ -18,28 +20,35 @@ type httpDataModel struct {
 	Subject      string
 	Body         string
 	TemplateType TemplateType
-	TemplateData EmailTemplate
+	TemplateData interface{}
 }
 
-type httpClient struct {
-	RequestConfig json.RawMessage
+// httpChannel sends emails and SMS to an HTTP endpoint. The request body is generated by the Jsonnet template of the
+// request configuration.
+type httpChannel struct {
+	id            string
+	c             *courier
+	requestConfig json.RawMessage
 }
 
-func newHTTP(ctx context.Context, deps Dependencies) *httpClient {
-	return &httpClient{
-		RequestConfig: deps.CourierConfig().CourierEmailRequestConfig(ctx),
+func newHTTPChannel(c *courier, id string, requestConfig json.RawMessage) *httpChannel {
+	return &httpChannel{
+		id:            id,
+		c:             c,
+		requestConfig: requestConfig,
 	}
 }
-func (c *courier) dispatchMailerEmail(ctx context.Context, msg Message) (err error) {
-	ctx, span := c.deps.Tracer(ctx).Tracer().Start(ctx, "courier.http.dispatchMailerEmail")
-	defer otelx.End(span, &err)
 
-	builder, err := request.NewBuilder(c.httpClient.RequestConfig, c.deps)
-	if err != nil {
-		return err
-	}
+func (h *httpChannel) ID() string {
+	return h.id
+}
 
-	tmpl, err := c.smtpClient.NewTemplateFromMessage(c.deps, msg)
+func (h *httpChannel) Dispatch(ctx context.Context, msg Message) (err error) {
+	c := h.c
+	ctx, span := c.deps.Tracer(ctx).Tracer().Start(ctx, "courier.httpChannel.Dispatch")
+	defer otelx.End(span, &err)
+
+	builder, err := request.NewBuilder(h.requestConfig, c.deps)
 	if err != nil {
 		return err
 	}
@@ -49,7 +58,26 @@ func (c *courier) dispatchMailerEmail(ctx context.Context, msg Message) (err err
 		Subject:      msg.Subject,
 		Body:         msg.Body,
 		TemplateType: msg.TemplateType,
-		TemplateData: tmpl,
+	}
+
+	switch msg.Type {
+	case MessageTypeEmail:
+		tmpl, err := c.newEmailTemplateFromMessage(c.deps, msg)
+		if err != nil {
+			return err
+		}
+		td.TemplateData = tmpl
+	case MessageTypePhone:
+		tmpl, err := c.newSMSTemplateFromMessage(c.deps, msg)
+		if err != nil {
+			return err
+		}
+		if td.Body, err = tmpl.SMSBody(ctx); err != nil {
+			return err
+		}
+		td.TemplateData = tmpl
+	default:
+		return errors.Errorf("received unexpected message type: %d", msg.Type)
 	}
 
 	req, err := builder.BuildRequest(ctx, td)
@@ -69,25 +97,27 @@ func (c *courier) dispatchMailerEmail(ctx context.Context, msg Message) (err err
 	case http.StatusCreated:
 	default:
 		err = fmt.Errorf(
-			"unable to dispatch mail delivery because upstream server replied with status code %d",
-			res.StatusCode,
+			"unable to dispatch message through channel %q because upstream server replied with status code %d",
+			h.id, res.StatusCode,
 		)
 		c.deps.Logger().
 			WithField("message_id", msg.ID).
 			WithField("message_type", msg.Type).
+			WithField("message_channel", h.id).
 			WithField("message_template_type", msg.TemplateType).
 			WithField("message_subject", msg.Subject).
 			WithError(err).
-			Error("sending mail via HTTP failed.")
+			Error("sending message via HTTP failed.")
 		return err
 	}
 
 	c.deps.Logger().
 		WithField("message_id", msg.ID).
 		WithField("message_type", msg.Type).
+		WithField("message_channel", h.id).
 		WithField("message_template_type", msg.TemplateType).
 		WithField("message_subject", msg.Subject).
-		Debug("Courier sent out mailer.")
+		Debug("Courier sent out message via HTTP.")
 
 	return nil
 }
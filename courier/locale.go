@@ -0,0 +1,26 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"context"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/courier/template"
+	"github.com/ory/kratos/x"
+)
+
+// messageLocale returns the locale a message is rendered in. The identity's preferred locale, taken from the trait
+// configured in `courier.template_locale_trait`, takes precedence over the Accept-Language header of the request which
+// caused the message.
+func (c *courier) messageLocale(ctx context.Context, templateData []byte) string {
+	if trait := c.deps.CourierConfig().CourierTemplateLocaleTrait(ctx); trait != "" {
+		if locale := template.NormalizeLocale(gjson.GetBytes(templateData, "Identity.traits."+trait).String()); locale != "" {
+			return locale
+		}
+	}
+
+	return template.NormalizeLocale(x.AcceptLanguageFromContext(ctx))
+}
@@ -0,0 +1,102 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/courier/template/email"
+	"github.com/ory/kratos/courier/template/sms"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/x"
+)
+
+func TestMessageLocale(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("case=locale of the Accept-Language header", func(t *testing.T) {
+		_, reg := internal.NewFastRegistryWithMocks(t)
+		c, err := reg.Courier(ctx)
+		require.NoError(t, err)
+
+		id, err := c.QueueEmail(x.ContextWithAcceptLanguage(ctx, "de-CH, de;q=0.9"), email.NewTestStub(reg, &email.TestStubModel{To: "foo@ory.sh", Subject: "subject", Body: "body"}))
+		require.NoError(t, err)
+
+		msg, err := reg.CourierPersister().FetchMessage(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "de-CH,de", msg.Locale)
+	})
+
+	t.Run("case=identity trait takes precedence", func(t *testing.T) {
+		conf, reg := internal.NewFastRegistryWithMocks(t)
+		conf.MustSet(ctx, config.ViperKeyCourierTemplateLocaleTrait, "locale")
+		c, err := reg.Courier(ctx)
+		require.NoError(t, err)
+
+		withTrait := email.NewRecoveryCodeValid(reg, &email.RecoveryCodeValidModel{
+			To:       "foo@ory.sh",
+			Identity: map[string]interface{}{"traits": map[string]interface{}{"locale": "fr"}},
+		})
+		withoutTrait := email.NewRecoveryCodeValid(reg, &email.RecoveryCodeValidModel{
+			To:       "foo@ory.sh",
+			Identity: map[string]interface{}{"traits": map[string]interface{}{}},
+		})
+
+		for tmpl, expected := range map[*email.RecoveryCodeValid]string{withTrait: "fr", withoutTrait: "de"} {
+			id, err := c.QueueEmail(x.ContextWithAcceptLanguage(ctx, "de"), tmpl)
+			require.NoError(t, err)
+
+			msg, err := reg.CourierPersister().FetchMessage(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, expected, msg.Locale)
+		}
+	})
+
+	t.Run("case=renders messages in their locale when dispatching", func(t *testing.T) {
+		var body string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rb, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req struct {
+				Body string `json:"body"`
+			}
+			require.NoError(t, json.Unmarshal(rb, &req))
+			body = req.Body
+		}))
+		t.Cleanup(srv.Close)
+
+		root := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "otp", "test_stub"), 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "otp", "test_stub", "sms.body.gotmpl"), []byte("Hello {{ .Body }}"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "otp", "test_stub", "sms.body.de.gotmpl"), []byte("Hallo {{ .Body }}"), 0600))
+
+		conf, reg := internal.NewFastRegistryWithMocks(t)
+		conf.MustSet(ctx, config.ViperKeyCourierTemplatesPath, root)
+		conf.MustSet(ctx, config.ViperKeyCourierSMSEnabled, true)
+		conf.MustSet(ctx, config.ViperKeyCourierSMSRequestConfig, map[string]interface{}{
+			"url":    srv.URL,
+			"method": "POST",
+			"body":   "file://./stub/request.config.twilio.jsonnet",
+		})
+		c, err := reg.Courier(ctx)
+		require.NoError(t, err)
+
+		_, err = c.QueueSMS(x.ContextWithAcceptLanguage(ctx, "de-AT"), sms.NewTestStub(reg, &sms.TestStubModel{To: "+12065550101", Body: "Jane"}))
+		require.NoError(t, err)
+
+		require.NoError(t, c.DispatchQueue(ctx))
+		assert.Equal(t, "Hallo Jane", body)
+	})
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/ory/herodot"
 	"github.com/ory/x/pagination/keysetpagination"
+	"github.com/ory/x/sqlxx"
 	"github.com/ory/x/stringsx"
 )
 
@@ -26,6 +27,7 @@ const (
 	MessageStatusSent
 	MessageStatusProcessing
 	MessageStatusAbandoned
+	MessageStatusCancelled
 )
 
 const (
@@ -33,6 +35,7 @@ const (
 	messageStatusSentText       = "sent"
 	messageStatusProcessingText = "processing"
 	messageStatusAbandonedText  = "abandoned"
+	messageStatusCancelledText  = "cancelled"
 )
 
 func ToMessageStatus(str string) (MessageStatus, error) {
@@ -45,6 +48,8 @@ func ToMessageStatus(str string) (MessageStatus, error) {
 		return MessageStatusProcessing, nil
 	case s.AddCase(MessageStatusAbandoned.String()):
 		return MessageStatusAbandoned, nil
+	case s.AddCase(MessageStatusCancelled.String()):
+		return MessageStatusCancelled, nil
 	default:
 		return 0, errors.WithStack(herodot.ErrBadRequest.WithWrap(s.ToUnknownCaseErr()).WithReason("Message status is not valid"))
 	}
@@ -60,6 +65,8 @@ func (ms MessageStatus) String() string {
 		return messageStatusProcessingText
 	case MessageStatusAbandoned:
 		return messageStatusAbandonedText
+	case MessageStatusCancelled:
+		return messageStatusCancelledText
 	default:
 		return ""
 	}
@@ -67,7 +74,7 @@ func (ms MessageStatus) String() string {
 
 func (ms MessageStatus) IsValid() error {
 	switch ms {
-	case MessageStatusQueued, MessageStatusSent, MessageStatusProcessing, MessageStatusAbandoned:
+	case MessageStatusQueued, MessageStatusSent, MessageStatusProcessing, MessageStatusAbandoned, MessageStatusCancelled:
 		return nil
 	default:
 		return errors.WithStack(herodot.ErrBadRequest.WithReason("Message status is not valid"))
@@ -190,9 +197,20 @@ type Message struct {
 	TemplateType TemplateType `json:"template_type" db:"template_type"`
 
 	TemplateData []byte `json:"-" db:"template_data"`
+
+	// Channel is the ID of the channel the message is delivered through. Messages without a channel are
+	// delivered through the default channel of their type.
+	Channel string `json:"channel,omitempty" db:"channel"`
+	// Locale holds the language tags the message is rendered in, in order of preference, such as "de-CH,de".
+	// Messages without a locale are rendered with the default templates.
+	Locale string `json:"locale,omitempty" db:"locale"`
 	// required: true
 	SendCount int `json:"send_count" db:"send_count"`
 
+	// ThrottledUntil is set when a rate limit deferred the delivery of the message. The courier does not try to
+	// deliver the message before this time.
+	ThrottledUntil sqlxx.NullTime `json:"throttled_until,omitempty" faker:"-" db:"throttled_until"`
+
 	// Dispatches store information about the attempts of delivering a message
 	// May contain an error if any happened, or just the `success` state.
 	Dispatches []MessageDispatch `json:"dispatches,omitempty" has_many:"courier_message_dispatches" order_by:"created_at desc" faker:"-"`
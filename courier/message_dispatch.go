@@ -15,12 +15,13 @@ import (
 type CourierMessageDispatchStatus string
 
 const (
-	CourierMessageDispatchStatusFailed  CourierMessageDispatchStatus = "failed"
-	CourierMessageDispatchStatusSuccess CourierMessageDispatchStatus = "success"
+	CourierMessageDispatchStatusFailed    CourierMessageDispatchStatus = "failed"
+	CourierMessageDispatchStatusSuccess   CourierMessageDispatchStatus = "success"
+	CourierMessageDispatchStatusThrottled CourierMessageDispatchStatus = "throttled"
 )
 
 // MessageDispatch represents an attempt of sending a courier message
-// It contains the status of the attempt (failed or successful) and the error if any occured
+// It contains the status of the attempt (failed, successful, or throttled) and the error if any occured
 //
 // swagger:model messageDispatch
 type MessageDispatch struct {
@@ -33,7 +34,7 @@ type MessageDispatch struct {
 	MessageID uuid.UUID `json:"message_id" db:"message_id"`
 
 	// The status of this dispatch
-	// Either "failed" or "success"
+	// Either "failed", "success", or "throttled"
 	// required: true
 	Status CourierMessageDispatchStatus `json:"status" db:"status"`
 
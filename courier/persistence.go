@@ -5,14 +5,21 @@ package courier
 
 import (
 	"context"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
 
+	"github.com/ory/herodot"
 	"github.com/ory/x/pagination/keysetpagination"
 )
 
-var ErrQueueEmpty = errors.New("queue is empty")
+var (
+	ErrQueueEmpty = errors.New("queue is empty")
+
+	ErrMessageNotQueued    = herodot.ErrConflict.WithReason("Only queued messages can be cancelled.")
+	ErrMessageNotAbandoned = herodot.ErrConflict.WithReason("Only abandoned messages can be re-queued.")
+)
 
 type (
 	Persister interface {
@@ -36,6 +43,27 @@ type (
 		// Records an attempt of sending out a courier message
 		// Returns an error if it fails
 		RecordDispatch(ctx context.Context, msgID uuid.UUID, status CourierMessageDispatchStatus, err error) error
+
+		// ListDeadLetters lists abandoned messages together with the history of their dispatches.
+		ListDeadLetters(context.Context, ListCourierDeadLettersParameters, []keysetpagination.Option) ([]Message, int64, *keysetpagination.Paginator, error)
+
+		// RequeueMessages queues the abandoned messages matching the filter again and resets their send count.
+		// Returns the number of re-queued messages.
+		RequeueMessages(context.Context, DeadLetterFilter) (int, error)
+
+		// CancelMessage cancels a queued message. Returns ErrMessageNotQueued if the message is not queued.
+		CancelMessage(context.Context, uuid.UUID) error
+
+		// PurgeMessages deletes the messages with any of the statuses which were created before the given time.
+		// Returns the number of deleted messages.
+		PurgeMessages(ctx context.Context, createdBefore time.Time, statuses []MessageStatus) (int, error)
+
+		// ThrottleMessage queues a message again which must not be delivered before the given time.
+		ThrottleMessage(ctx context.Context, id uuid.UUID, until time.Time) error
+
+		// RecentRecipientDispatches returns when messages were successfully delivered to the recipient since the
+		// given time, newest first and at most limit times.
+		RecentRecipientDispatches(ctx context.Context, recipient string, since time.Time, limit int) ([]time.Time, error)
 	}
 	PersistenceProvider interface {
 		CourierPersister() Persister
@@ -0,0 +1,67 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/ory/herodot"
+)
+
+// ErrThrottled is recorded as the error of a dispatch which a rate limit deferred.
+var ErrThrottled = herodot.DefaultError{
+	CodeField:   http.StatusTooManyRequests,
+	StatusField: http.StatusText(http.StatusTooManyRequests),
+	ErrorField:  "The message was not delivered because a courier rate limit was reached.",
+}
+
+// newLimiters returns a limiter for every channel with a rate limit. The limits are enforced per courier process.
+func (c *courier) newLimiters(ctx context.Context) map[string]*rate.Limiter {
+	limiters := make(map[string]*rate.Limiter)
+	for id := range c.channels {
+		if limit := c.deps.CourierConfig().CourierChannelRateLimit(ctx, id); limit != nil {
+			limiters[id] = rate.NewLimiter(rate.Limit(limit.MessagesPerSecond), limit.Burst)
+		}
+	}
+	return limiters
+}
+
+// throttledUntil returns when the message may be delivered if a rate limit prevents delivering it now, together with
+// the reason. It returns the zero time if the message may be delivered now, in which case the message counts against
+// the rate limit of its channel.
+func (c *courier) throttledUntil(ctx context.Context, msg Message) (time.Time, *herodot.DefaultError, error) {
+	now := time.Now().UTC()
+
+	if limit := c.deps.CourierConfig().CourierRecipientRateLimit(ctx); limit != nil {
+		recent, err := c.deps.CourierPersister().RecentRecipientDispatches(ctx, msg.Recipient, now.Add(-limit.Window), limit.MaxMessages)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+
+		if len(recent) >= limit.MaxMessages {
+			// A message may be delivered again once the oldest of the counted dispatches leaves the window.
+			return recent[limit.MaxMessages-1].Add(limit.Window), ErrThrottled.WithReasonf("The recipient received %d messages within %s.", len(recent), limit.Window), nil
+		}
+	}
+
+	channel, err := c.messageChannel(msg)
+	if err != nil {
+		// Let the dispatch report the missing channel.
+		return time.Time{}, nil, nil
+	}
+
+	if limiter, ok := c.limiters[channel.ID()]; ok {
+		reservation := limiter.ReserveN(now, 1)
+		if delay := reservation.DelayFrom(now); delay > 0 {
+			reservation.CancelAt(now)
+			return now.Add(delay), ErrThrottled.WithReasonf("The courier channel %q delivers at most %v messages per second.", channel.ID(), limiter.Limit()), nil
+		}
+	}
+
+	return time.Time{}, nil, nil
+}
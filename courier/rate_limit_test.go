@@ -0,0 +1,139 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/courier/template/email"
+	"github.com/ory/kratos/driver"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/internal"
+)
+
+func TestRateLimits(t *testing.T) {
+	ctx := context.Background()
+
+	var delivered int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&delivered, 1)
+	}))
+	t.Cleanup(srv.Close)
+
+	newCourier := func(t *testing.T, values map[string]interface{}) (*driver.RegistryDefault, courier.Courier) {
+		conf, reg := internal.NewFastRegistryWithMocks(t)
+		conf.MustSet(ctx, config.ViperKeyCourierChannels, []map[string]interface{}{{
+			"id":   "chat",
+			"type": "http",
+			"request_config": map[string]interface{}{
+				"url":    srv.URL,
+				"method": "POST",
+				"body":   "file://./stub/request.config.channel.jsonnet",
+			},
+		}})
+		conf.MustSet(ctx, config.ViperKeyCourierTemplateChannels+".stub", "chat")
+		for k, v := range values {
+			conf.MustSet(ctx, k, v)
+		}
+
+		c, err := reg.Courier(ctx)
+		require.NoError(t, err)
+
+		atomic.StoreInt32(&delivered, 0)
+		return reg, c
+	}
+
+	queue := func(t *testing.T, reg *driver.RegistryDefault, c courier.Courier, to string) uuid.UUID {
+		id, err := c.QueueEmail(ctx, email.NewTestStub(reg, &email.TestStubModel{To: to, Subject: "subject", Body: "body"}))
+		require.NoError(t, err)
+		return id
+	}
+
+	// expectThrottled asserts that exactly one of the messages was throttled and returns it.
+	expectThrottled := func(t *testing.T, reg *driver.RegistryDefault, reason string, ids ...uuid.UUID) courier.Message {
+		var message *courier.Message
+		for _, id := range ids {
+			m, err := reg.CourierPersister().FetchMessage(ctx, id)
+			require.NoError(t, err)
+			if m.Status != courier.MessageStatusSent {
+				require.Nil(t, message, "expected only one message to be throttled")
+				message = m
+			}
+		}
+		require.NotNil(t, message, "expected one message to be throttled")
+
+		assert.Equal(t, courier.MessageStatusQueued, message.Status)
+		assert.Zero(t, message.SendCount)
+		require.Len(t, message.Dispatches, 1)
+		assert.Equal(t, courier.CourierMessageDispatchStatusThrottled, message.Dispatches[0].Status)
+		assert.Contains(t, gjson.GetBytes(message.Dispatches[0].Error, "reason").String(), reason)
+		return *message
+	}
+
+	t.Run("case=caps messages per recipient", func(t *testing.T) {
+		reg, c := newCourier(t, map[string]interface{}{
+			config.ViperKeyCourierRateLimitRecipientMaxMessages: 2,
+			config.ViperKeyCourierRateLimitRecipientWindow:      "1h",
+		})
+
+		victim := []uuid.UUID{
+			queue(t, reg, c, "victim@ory.sh"),
+			queue(t, reg, c, "victim@ory.sh"),
+			queue(t, reg, c, "victim@ory.sh"),
+		}
+		other := queue(t, reg, c, "other@ory.sh")
+
+		require.NoError(t, c.DispatchQueue(ctx))
+		assert.EqualValues(t, 3, atomic.LoadInt32(&delivered))
+
+		message := expectThrottled(t, reg, "The recipient received 2 messages within 1h0m0s.", victim...)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), time.Time(message.ThrottledUntil), time.Minute)
+
+		message2, err := reg.CourierPersister().FetchMessage(ctx, other)
+		require.NoError(t, err)
+		assert.Equal(t, courier.MessageStatusSent, message2.Status)
+
+		// The throttled message is not pulled from the queue again until the window passed.
+		require.NoError(t, c.DispatchQueue(ctx))
+		assert.EqualValues(t, 3, atomic.LoadInt32(&delivered))
+		assert.Equal(t, message.ID, expectThrottled(t, reg, "", victim...).ID)
+	})
+
+	t.Run("case=limits messages per second per channel", func(t *testing.T) {
+		reg, c := newCourier(t, map[string]interface{}{
+			config.ViperKeyCourierRateLimitChannels + ".chat.messages_per_second": 0.001,
+		})
+
+		first, second := queue(t, reg, c, "first@ory.sh"), queue(t, reg, c, "second@ory.sh")
+
+		require.NoError(t, c.DispatchQueue(ctx))
+		assert.EqualValues(t, 1, atomic.LoadInt32(&delivered))
+
+		message := expectThrottled(t, reg, `The courier channel "chat" delivers at most 0.001 messages per second.`, first, second)
+		assert.WithinDuration(t, time.Now().Add(1000*time.Second), time.Time(message.ThrottledUntil), time.Minute)
+	})
+
+	t.Run("case=does not limit other channels", func(t *testing.T) {
+		reg, c := newCourier(t, map[string]interface{}{
+			config.ViperKeyCourierRateLimitChannels + ".email.messages_per_second": 0.001,
+		})
+
+		queue(t, reg, c, "first@ory.sh")
+		queue(t, reg, c, "second@ory.sh")
+
+		require.NoError(t, c.DispatchQueue(ctx))
+		assert.EqualValues(t, 2, atomic.LoadInt32(&delivered))
+	})
+}
@@ -23,28 +23,39 @@ type sendSMSRequestBody struct {
 	Body string `json:"body"`
 }
 
-type smsClient struct {
-	RequestConfig json.RawMessage
-
-	GetTemplateType        func(t SMSTemplate) (TemplateType, error)
-	NewTemplateFromMessage func(d Dependencies, msg Message) (SMSTemplate, error)
+// smsChannel sends SMS to a generic SMS provider.
+type smsChannel struct {
+	id            string
+	c             *courier
+	requestConfig json.RawMessage
+	from          string
 }
 
-func newSMS(ctx context.Context, deps Dependencies) *smsClient {
-	return &smsClient{
-		RequestConfig:          deps.CourierConfig().CourierSMSRequestConfig(ctx),
-		GetTemplateType:        SMSTemplateType,
-		NewTemplateFromMessage: NewSMSTemplateFromMessage,
+func newSMSChannel(c *courier, id string, requestConfig json.RawMessage, from string) *smsChannel {
+	return &smsChannel{
+		id:            id,
+		c:             c,
+		requestConfig: requestConfig,
+		from:          from,
 	}
 }
 
+func (s *smsChannel) ID() string {
+	return s.id
+}
+
 func (c *courier) QueueSMS(ctx context.Context, t SMSTemplate) (uuid.UUID, error) {
 	recipient, err := t.PhoneNumber()
 	if err != nil {
 		return uuid.Nil, err
 	}
 
-	templateType, err := c.smsClient.GetTemplateType(t)
+	templateType, err := c.getSMSTemplateType(t)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	channel, err := c.templateChannel(ctx, templateType, DefaultSMSChannelID)
 	if err != nil {
 		return uuid.Nil, err
 	}
@@ -60,6 +71,8 @@ func (c *courier) QueueSMS(ctx context.Context, t SMSTemplate) (uuid.UUID, error
 		Recipient:    recipient,
 		TemplateType: templateType,
 		TemplateData: templateData,
+		Channel:      channel,
+		Locale:       c.messageLocale(ctx, templateData),
 	}
 	if err := c.deps.CourierPersister().AddMessage(ctx, message); err != nil {
 		return uuid.Nil, err
@@ -68,12 +81,13 @@ func (c *courier) QueueSMS(ctx context.Context, t SMSTemplate) (uuid.UUID, error
 	return message.ID, nil
 }
 
-func (c *courier) dispatchSMS(ctx context.Context, msg Message) error {
-	if !c.deps.CourierConfig().CourierSMSEnabled(ctx) {
-		return errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Courier tried to deliver an sms but courier.sms.enabled is set to false!"))
+func (s *smsChannel) Dispatch(ctx context.Context, msg Message) error {
+	c := s.c
+	if msg.Type != MessageTypePhone {
+		return errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Courier tried to deliver a message of type %q through the SMS channel %q, which only delivers SMS.", msg.Type, s.id))
 	}
 
-	tmpl, err := c.smsClient.NewTemplateFromMessage(c.deps, msg)
+	tmpl, err := c.newSMSTemplateFromMessage(c.deps, msg)
 	if err != nil {
 		return err
 	}
@@ -83,14 +97,14 @@ func (c *courier) dispatchSMS(ctx context.Context, msg Message) error {
 		return err
 	}
 
-	builder, err := request.NewBuilder(c.smsClient.RequestConfig, c.deps)
+	builder, err := request.NewBuilder(s.requestConfig, c.deps)
 	if err != nil {
 		return err
 	}
 
 	req, err := builder.BuildRequest(ctx, &sendSMSRequestBody{
 		To:   msg.Recipient,
-		From: c.deps.CourierConfig().CourierSMSFrom(ctx),
+		From: s.from,
 		Body: body,
 	})
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/textproto"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -23,35 +24,36 @@ import (
 	gomail "github.com/ory/mail/v3"
 )
 
-type smtpClient struct {
-	*gomail.Dialer
-
-	GetTemplateType        func(t EmailTemplate) (TemplateType, error)
-	NewTemplateFromMessage func(d template.Dependencies, msg Message) (EmailTemplate, error)
+type smtpChannel struct {
+	id     string
+	c      *courier
+	conf   *config.CourierSMTPConfig
+	dialer *gomail.Dialer
 }
 
-func newSMTP(ctx context.Context, deps Dependencies) (*smtpClient, error) {
-	uri, err := deps.CourierConfig().CourierSMTPURL(ctx)
+func newSMTPChannel(c *courier, id string, conf *config.CourierSMTPConfig) (*smtpChannel, error) {
+	uri, err := url.Parse(conf.ConnectionURI)
 	if err != nil {
-		return nil, err
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to parse the SMTP URL of the courier channel %q. Please ensure that it is properly escaped: https://www.ory.sh/dr/3", id).WithDebugf("%s", err))
 	}
 
 	var tlsCertificates []tls.Certificate
-	clientCertPath := deps.CourierConfig().CourierSMTPClientCertPath(ctx)
-	clientKeyPath := deps.CourierConfig().CourierSMTPClientKeyPath(ctx)
+	clientCertPath := conf.ClientCertPath
+	clientKeyPath := conf.ClientKeyPath
 
 	if clientCertPath != "" && clientKeyPath != "" {
 		clientCert, err := tls.LoadX509KeyPair(clientCertPath, clientKeyPath)
 		if err == nil {
 			tlsCertificates = append(tlsCertificates, clientCert)
 		} else {
-			deps.Logger().
+			c.deps.Logger().
 				WithError(err).
+				WithField("channel_id", id).
 				Error("Unable to load tls certificate and private key for smtp client.")
 		}
 	}
 
-	localName := deps.CourierConfig().CourierSMTPLocalName(ctx)
+	localName := conf.LocalName
 	password, _ := uri.User.Password()
 	port, _ := strconv.ParseInt(uri.Port(), 10, 0)
 
@@ -93,24 +95,28 @@ func newSMTP(ctx context.Context, deps Dependencies) (*smtpClient, error) {
 		dialer.SSL = true
 	}
 
-	return &smtpClient{
-		Dialer: dialer,
-
-		GetTemplateType:        GetEmailTemplateType,
-		NewTemplateFromMessage: NewEmailTemplateFromMessage,
+	return &smtpChannel{
+		id:     id,
+		c:      c,
+		conf:   conf,
+		dialer: dialer,
 	}, nil
 }
 
 func (c *courier) SetGetEmailTemplateType(f func(t EmailTemplate) (TemplateType, error)) {
-	c.smtpClient.GetTemplateType = f
+	c.getEmailTemplateType = f
 }
 
 func (c *courier) SetNewEmailTemplateFromMessage(f func(d template.Dependencies, msg Message) (EmailTemplate, error)) {
-	c.smtpClient.NewTemplateFromMessage = f
+	c.newEmailTemplateFromMessage = f
 }
 
+// SmtpDialer returns the dialer of the default email channel, or nil if that channel does not use SMTP.
 func (c *courier) SmtpDialer() *gomail.Dialer {
-	return c.smtpClient.Dialer
+	if channel, ok := c.channels[DefaultEmailChannelID].(*smtpChannel); ok {
+		return channel.dialer
+	}
+	return nil
 }
 
 func (c *courier) QueueEmail(ctx context.Context, t EmailTemplate) (uuid.UUID, error) {
@@ -119,6 +125,14 @@ func (c *courier) QueueEmail(ctx context.Context, t EmailTemplate) (uuid.UUID, e
 		return uuid.Nil, err
 	}
 
+	templateData, err := json.Marshal(t)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	locale := c.messageLocale(ctx, templateData)
+	ctx = template.WithLocale(ctx, locale)
+
 	subject, err := t.EmailSubject(ctx)
 	if err != nil {
 		return uuid.Nil, err
@@ -129,12 +143,12 @@ func (c *courier) QueueEmail(ctx context.Context, t EmailTemplate) (uuid.UUID, e
 		return uuid.Nil, err
 	}
 
-	templateType, err := c.smtpClient.GetTemplateType(t)
+	templateType, err := c.getEmailTemplateType(t)
 	if err != nil {
 		return uuid.Nil, err
 	}
 
-	templateData, err := json.Marshal(t)
+	channel, err := c.templateChannel(ctx, templateType, DefaultEmailChannelID)
 	if err != nil {
 		return uuid.Nil, err
 	}
@@ -147,6 +161,8 @@ func (c *courier) QueueEmail(ctx context.Context, t EmailTemplate) (uuid.UUID, e
 		Subject:      subject,
 		TemplateType: templateType,
 		TemplateData: templateData,
+		Channel:      channel,
+		Locale:       locale,
 	}
 
 	if err := c.deps.CourierPersister().AddMessage(ctx, message); err != nil {
@@ -156,16 +172,24 @@ func (c *courier) QueueEmail(ctx context.Context, t EmailTemplate) (uuid.UUID, e
 	return message.ID, nil
 }
 
-func (c *courier) dispatchEmail(ctx context.Context, msg Message) error {
-	if c.deps.CourierConfig().CourierEmailStrategy(ctx) == "http" {
-		return c.dispatchMailerEmail(ctx, msg)
+func (s *smtpChannel) ID() string {
+	return s.id
+}
+
+func (s *smtpChannel) Dispatch(ctx context.Context, msg Message) error {
+	c := s.c
+	if msg.Type != MessageTypeEmail {
+		return errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Courier tried to deliver a message of type %q through the SMTP channel %q, which only delivers emails.", msg.Type, s.id))
 	}
-	if c.smtpClient.Host == "" {
-		return errors.WithStack(herodot.ErrInternalServerError.WithErrorf("Courier tried to deliver an email but %s is not set!", config.ViperKeyCourierSMTPURL))
+	if s.dialer.Host == "" {
+		if s.id == DefaultEmailChannelID {
+			return errors.WithStack(herodot.ErrInternalServerError.WithErrorf("Courier tried to deliver an email but %s is not set!", config.ViperKeyCourierSMTPURL))
+		}
+		return errors.WithStack(herodot.ErrInternalServerError.WithErrorf("Courier tried to deliver an email through the channel %q but its SMTP connection URI is not set!", s.id))
 	}
 
-	from := c.deps.CourierConfig().CourierSMTPFrom(ctx)
-	fromName := c.deps.CourierConfig().CourierSMTPFromName(ctx)
+	from := s.conf.FromAddress
+	fromName := s.conf.FromName
 
 	gm := gomail.NewMessage()
 	if fromName == "" {
@@ -177,14 +201,13 @@ func (c *courier) dispatchEmail(ctx context.Context, msg Message) error {
 	gm.SetHeader("To", msg.Recipient)
 	gm.SetHeader("Subject", msg.Subject)
 
-	headers := c.deps.CourierConfig().CourierSMTPHeaders(ctx)
-	for k, v := range headers {
+	for k, v := range s.conf.Headers {
 		gm.SetHeader(k, v)
 	}
 
 	gm.SetBody("text/plain", msg.Body)
 
-	tmpl, err := c.smtpClient.NewTemplateFromMessage(c.deps, msg)
+	tmpl, err := c.newEmailTemplateFromMessage(c.deps, msg)
 	if err != nil {
 		c.deps.Logger().
 			WithError(err).
@@ -204,11 +227,12 @@ func (c *courier) dispatchEmail(ctx context.Context, msg Message) error {
 		}
 	}
 
-	if err := c.smtpClient.DialAndSend(ctx, gm); err != nil {
+	if err := s.dialer.DialAndSend(ctx, gm); err != nil {
 		c.deps.Logger().
 			WithError(err).
-			WithField("smtp_server", fmt.Sprintf("%s:%d", c.smtpClient.Host, c.smtpClient.Port)).
-			WithField("smtp_ssl_enabled", c.smtpClient.SSL).
+			WithField("smtp_server", fmt.Sprintf("%s:%d", s.dialer.Host, s.dialer.Port)).
+			WithField("smtp_ssl_enabled", s.dialer.SSL).
+			WithField("channel_id", s.id).
 			WithField("message_from", from).
 			WithField("message_id", msg.ID).
 			WithField("message_nid", msg.NID).
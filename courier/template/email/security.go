@@ -0,0 +1,12 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package email
+
+// DeviceModel describes the device which caused a security notification.
+type DeviceModel struct {
+	IPAddress string
+	UserAgent string
+	// Location is "<city>, <country>" or empty if the location is unknown.
+	Location string
+}
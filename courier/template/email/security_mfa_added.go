@@ -0,0 +1,53 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/ory/kratos/courier/template"
+)
+
+type (
+	SecurityMFAAdded struct {
+		deps  template.Dependencies
+		model *SecurityMFAAddedModel
+	}
+	SecurityMFAAddedModel struct {
+		To       string
+		Identity map[string]interface{}
+		Device   DeviceModel
+		// Method is the second factor which was added, for example `totp` or `webauthn`.
+		Method string
+	}
+)
+
+func NewSecurityMFAAdded(d template.Dependencies, m *SecurityMFAAddedModel) *SecurityMFAAdded {
+	return &SecurityMFAAdded{deps: d, model: m}
+}
+
+func (t *SecurityMFAAdded) EmailRecipient() (string, error) {
+	return t.model.To, nil
+}
+
+func (t *SecurityMFAAdded) EmailSubject(ctx context.Context) (string, error) {
+	subject, err := template.LoadText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "security/mfa_added/email.subject.gotmpl", "security/mfa_added/email.subject*", t.model, t.deps.CourierConfig().CourierTemplatesSecurityMFAAdded(ctx).Subject)
+
+	return strings.TrimSpace(subject), err
+}
+
+func (t *SecurityMFAAdded) EmailBody(ctx context.Context) (string, error) {
+	return template.LoadHTML(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "security/mfa_added/email.body.gotmpl", "security/mfa_added/email.body*", t.model, t.deps.CourierConfig().CourierTemplatesSecurityMFAAdded(ctx).Body.HTML)
+}
+
+func (t *SecurityMFAAdded) EmailBodyPlaintext(ctx context.Context) (string, error) {
+	return template.LoadPlainText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "security/mfa_added/email.body.plaintext.gotmpl", "security/mfa_added/email.body.plaintext*", t.model, t.deps.CourierConfig().CourierTemplatesSecurityMFAAdded(ctx).Body)
+}
+
+func (t *SecurityMFAAdded) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.model)
+}
@@ -0,0 +1,53 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/ory/kratos/courier/template"
+)
+
+type (
+	SecurityMFARemoved struct {
+		deps  template.Dependencies
+		model *SecurityMFARemovedModel
+	}
+	SecurityMFARemovedModel struct {
+		To       string
+		Identity map[string]interface{}
+		Device   DeviceModel
+		// Method is the second factor which was removed, for example `totp` or `webauthn`.
+		Method string
+	}
+)
+
+func NewSecurityMFARemoved(d template.Dependencies, m *SecurityMFARemovedModel) *SecurityMFARemoved {
+	return &SecurityMFARemoved{deps: d, model: m}
+}
+
+func (t *SecurityMFARemoved) EmailRecipient() (string, error) {
+	return t.model.To, nil
+}
+
+func (t *SecurityMFARemoved) EmailSubject(ctx context.Context) (string, error) {
+	subject, err := template.LoadText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "security/mfa_removed/email.subject.gotmpl", "security/mfa_removed/email.subject*", t.model, t.deps.CourierConfig().CourierTemplatesSecurityMFARemoved(ctx).Subject)
+
+	return strings.TrimSpace(subject), err
+}
+
+func (t *SecurityMFARemoved) EmailBody(ctx context.Context) (string, error) {
+	return template.LoadHTML(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "security/mfa_removed/email.body.gotmpl", "security/mfa_removed/email.body*", t.model, t.deps.CourierConfig().CourierTemplatesSecurityMFARemoved(ctx).Body.HTML)
+}
+
+func (t *SecurityMFARemoved) EmailBodyPlaintext(ctx context.Context) (string, error) {
+	return template.LoadPlainText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "security/mfa_removed/email.body.plaintext.gotmpl", "security/mfa_removed/email.body.plaintext*", t.model, t.deps.CourierConfig().CourierTemplatesSecurityMFARemoved(ctx).Body)
+}
+
+func (t *SecurityMFARemoved) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.model)
+}
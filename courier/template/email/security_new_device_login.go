@@ -0,0 +1,51 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/ory/kratos/courier/template"
+)
+
+type (
+	SecurityNewDeviceLogin struct {
+		deps  template.Dependencies
+		model *SecurityNewDeviceLoginModel
+	}
+	SecurityNewDeviceLoginModel struct {
+		To       string
+		Identity map[string]interface{}
+		Device   DeviceModel
+	}
+)
+
+func NewSecurityNewDeviceLogin(d template.Dependencies, m *SecurityNewDeviceLoginModel) *SecurityNewDeviceLogin {
+	return &SecurityNewDeviceLogin{deps: d, model: m}
+}
+
+func (t *SecurityNewDeviceLogin) EmailRecipient() (string, error) {
+	return t.model.To, nil
+}
+
+func (t *SecurityNewDeviceLogin) EmailSubject(ctx context.Context) (string, error) {
+	subject, err := template.LoadText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "security/new_device_login/email.subject.gotmpl", "security/new_device_login/email.subject*", t.model, t.deps.CourierConfig().CourierTemplatesSecurityNewDeviceLogin(ctx).Subject)
+
+	return strings.TrimSpace(subject), err
+}
+
+func (t *SecurityNewDeviceLogin) EmailBody(ctx context.Context) (string, error) {
+	return template.LoadHTML(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "security/new_device_login/email.body.gotmpl", "security/new_device_login/email.body*", t.model, t.deps.CourierConfig().CourierTemplatesSecurityNewDeviceLogin(ctx).Body.HTML)
+}
+
+func (t *SecurityNewDeviceLogin) EmailBodyPlaintext(ctx context.Context) (string, error) {
+	return template.LoadPlainText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "security/new_device_login/email.body.plaintext.gotmpl", "security/new_device_login/email.body.plaintext*", t.model, t.deps.CourierConfig().CourierTemplatesSecurityNewDeviceLogin(ctx).Body)
+}
+
+func (t *SecurityNewDeviceLogin) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.model)
+}
@@ -0,0 +1,51 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/ory/kratos/courier/template"
+)
+
+type (
+	SecurityPasswordChanged struct {
+		deps  template.Dependencies
+		model *SecurityPasswordChangedModel
+	}
+	SecurityPasswordChangedModel struct {
+		To       string
+		Identity map[string]interface{}
+		Device   DeviceModel
+	}
+)
+
+func NewSecurityPasswordChanged(d template.Dependencies, m *SecurityPasswordChangedModel) *SecurityPasswordChanged {
+	return &SecurityPasswordChanged{deps: d, model: m}
+}
+
+func (t *SecurityPasswordChanged) EmailRecipient() (string, error) {
+	return t.model.To, nil
+}
+
+func (t *SecurityPasswordChanged) EmailSubject(ctx context.Context) (string, error) {
+	subject, err := template.LoadText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "security/password_changed/email.subject.gotmpl", "security/password_changed/email.subject*", t.model, t.deps.CourierConfig().CourierTemplatesSecurityPasswordChanged(ctx).Subject)
+
+	return strings.TrimSpace(subject), err
+}
+
+func (t *SecurityPasswordChanged) EmailBody(ctx context.Context) (string, error) {
+	return template.LoadHTML(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "security/password_changed/email.body.gotmpl", "security/password_changed/email.body*", t.model, t.deps.CourierConfig().CourierTemplatesSecurityPasswordChanged(ctx).Body.HTML)
+}
+
+func (t *SecurityPasswordChanged) EmailBodyPlaintext(ctx context.Context) (string, error) {
+	return template.LoadPlainText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "security/password_changed/email.body.plaintext.gotmpl", "security/password_changed/email.body.plaintext*", t.model, t.deps.CourierConfig().CourierTemplatesSecurityPasswordChanged(ctx).Body)
+}
+
+func (t *SecurityPasswordChanged) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.model)
+}
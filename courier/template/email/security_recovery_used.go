@@ -0,0 +1,51 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/ory/kratos/courier/template"
+)
+
+type (
+	SecurityRecoveryUsed struct {
+		deps  template.Dependencies
+		model *SecurityRecoveryUsedModel
+	}
+	SecurityRecoveryUsedModel struct {
+		To       string
+		Identity map[string]interface{}
+		Device   DeviceModel
+	}
+)
+
+func NewSecurityRecoveryUsed(d template.Dependencies, m *SecurityRecoveryUsedModel) *SecurityRecoveryUsed {
+	return &SecurityRecoveryUsed{deps: d, model: m}
+}
+
+func (t *SecurityRecoveryUsed) EmailRecipient() (string, error) {
+	return t.model.To, nil
+}
+
+func (t *SecurityRecoveryUsed) EmailSubject(ctx context.Context) (string, error) {
+	subject, err := template.LoadText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "security/recovery_used/email.subject.gotmpl", "security/recovery_used/email.subject*", t.model, t.deps.CourierConfig().CourierTemplatesSecurityRecoveryUsed(ctx).Subject)
+
+	return strings.TrimSpace(subject), err
+}
+
+func (t *SecurityRecoveryUsed) EmailBody(ctx context.Context) (string, error) {
+	return template.LoadHTML(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "security/recovery_used/email.body.gotmpl", "security/recovery_used/email.body*", t.model, t.deps.CourierConfig().CourierTemplatesSecurityRecoveryUsed(ctx).Body.HTML)
+}
+
+func (t *SecurityRecoveryUsed) EmailBodyPlaintext(ctx context.Context) (string, error) {
+	return template.LoadPlainText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "security/recovery_used/email.body.plaintext.gotmpl", "security/recovery_used/email.body.plaintext*", t.model, t.deps.CourierConfig().CourierTemplatesSecurityRecoveryUsed(ctx).Body)
+}
+
+func (t *SecurityRecoveryUsed) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.model)
+}
@@ -57,14 +57,14 @@ func (t *VerificationCodeInvalid) EmailBody(ctx context.Context) (string, error)
 }
 
 func (t *VerificationCodeInvalid) EmailBodyPlaintext(ctx context.Context) (string, error) {
-	return template.LoadText(
+	return template.LoadPlainText(
 		ctx,
 		t.d,
 		os.DirFS(t.d.CourierConfig().CourierTemplatesRoot(ctx)),
 		"verification_code/invalid/email.body.plaintext.gotmpl",
 		"verification_code/invalid/email.body.plaintext*",
 		t.m,
-		t.d.CourierConfig().CourierTemplatesVerificationCodeInvalid(ctx).Body.PlainText,
+		t.d.CourierConfig().CourierTemplatesVerificationCodeInvalid(ctx).Body,
 	)
 }
 
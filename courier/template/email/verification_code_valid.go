@@ -59,13 +59,13 @@ func (t *VerificationCodeValid) EmailBody(ctx context.Context) (string, error) {
 }
 
 func (t *VerificationCodeValid) EmailBodyPlaintext(ctx context.Context) (string, error) {
-	return template.LoadText(ctx,
+	return template.LoadPlainText(ctx,
 		t.d,
 		os.DirFS(t.d.CourierConfig().CourierTemplatesRoot(ctx)),
 		"verification_code/valid/email.body.plaintext.gotmpl",
 		"verification_code/valid/email.body.plaintext*",
 		t.m,
-		t.d.CourierConfig().CourierTemplatesVerificationCodeValid(ctx).Body.PlainText,
+		t.d.CourierConfig().CourierTemplatesVerificationCodeValid(ctx).Body,
 	)
 }
 
@@ -15,6 +15,7 @@ import (
 
 	"github.com/hashicorp/go-retryablehttp"
 
+	"github.com/ory/kratos/driver/config"
 	"github.com/ory/x/fetcher"
 	"github.com/ory/x/httpx"
 
@@ -28,14 +29,126 @@ var templates embed.FS
 
 var Cache, _ = lru.New(16)
 
+// partialDirs are the directories of the template root whose templates are available to all templates.
+var partialDirs = []string{"layouts", "partials"}
+
 type Template interface {
 	Execute(wr io.Writer, data interface{}) error
 }
 
 type templateDependencies interface {
+	CourierConfig() config.CourierConfigs
 	HTTPClient(ctx context.Context, opts ...httpx.ResilientOptions) *retryablehttp.Client
 }
 
+// templateSet is a template together with the templates it may reference, such as layouts, partials, and
+// localized variants.
+type templateSet interface {
+	Template
+	parse(name, text string) error
+	parseFS(filesystem fs.FS, patterns ...string) error
+	lookup(name string) Template
+}
+
+type (
+	htmlSet struct{ *htemplate.Template }
+	textSet struct{ *template.Template }
+)
+
+func newTemplateSet(name string, html, hermetic bool) templateSet {
+	if html {
+		funcs := sprig.HtmlFuncMap()
+		if hermetic {
+			funcs = sprig.HermeticHtmlFuncMap()
+		}
+		return htmlSet{htemplate.New(name).Funcs(funcs)}
+	}
+
+	funcs := sprig.TxtFuncMap()
+	if hermetic {
+		funcs = sprig.HermeticTxtFuncMap()
+	}
+	return textSet{template.New(name).Funcs(funcs)}
+}
+
+func (s htmlSet) parse(name, text string) (err error) {
+	t := s.Template
+	if name != t.Name() {
+		t = t.New(name)
+	}
+	_, err = t.Parse(text)
+	return errors.WithStack(err)
+}
+
+func (s htmlSet) parseFS(filesystem fs.FS, patterns ...string) error {
+	_, err := s.ParseFS(filesystem, patterns...)
+	return errors.WithStack(err)
+}
+
+func (s htmlSet) lookup(name string) Template {
+	if t := s.Lookup(name); t != nil {
+		return t
+	}
+	return nil
+}
+
+func (s textSet) parse(name, text string) (err error) {
+	t := s.Template
+	if name != t.Name() {
+		t = t.New(name)
+	}
+	_, err = t.Parse(text)
+	return errors.WithStack(err)
+}
+
+func (s textSet) parseFS(filesystem fs.FS, patterns ...string) error {
+	_, err := s.ParseFS(filesystem, patterns...)
+	return errors.WithStack(err)
+}
+
+func (s textSet) lookup(name string) Template {
+	if t := s.Lookup(name); t != nil {
+		return t
+	}
+	return nil
+}
+
+// parsePartials adds the layouts and partials of the filesystem and the configured template partials to the template
+// set. They are parsed before the template itself, so that the template can override blocks they define.
+func parsePartials(ctx context.Context, d templateDependencies, filesystem fs.FS, set templateSet) error {
+	if filesystem != nil {
+		for _, dir := range partialDirs {
+			matches, _ := fs.Glob(filesystem, dir+"/*.gotmpl")
+			for _, match := range matches {
+				b, err := fs.ReadFile(filesystem, match)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				if err := set.parse(match, string(b)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, url := range d.CourierConfig().CourierTemplatePartials(ctx) {
+		b, err := fetchRemoteTemplate(ctx, d, url)
+		if err != nil {
+			return err
+		}
+		if err := set.parse(url, string(b)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func builtInTemplateExists(name string) bool {
+	_, err := fs.Stat(templates, filepath.Join("courier/builtin/templates", name))
+	return err == nil
+}
+
 func loadBuiltInTemplate(filesystem fs.FS, name string, html bool) (Template, error) {
 	if t, found := Cache.Get(name); found {
 		return t.(Template), nil
@@ -59,99 +172,132 @@ func loadBuiltInTemplate(filesystem fs.FS, name string, html bool) (Template, er
 		return nil, errors.WithStack(err)
 	}
 
-	var tpl Template
-	if html {
-		t, err := htemplate.New(name).Funcs(sprig.HtmlFuncMap()).Parse(b.String())
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
-		tpl = t
-	} else {
-		t, err := template.New(name).Funcs(sprig.TxtFuncMap()).Parse(b.String())
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
-		tpl = t
+	tpl := newTemplateSet(name, html, false)
+	if err := tpl.parse(name, b.String()); err != nil {
+		return nil, err
 	}
 
 	_ = Cache.Add(name, tpl)
 	return tpl, nil
 }
 
-func loadRemoteTemplate(ctx context.Context, d templateDependencies, url string, html bool) (Template, error) {
-	var b []byte
-	var err error
-
+func fetchRemoteTemplate(ctx context.Context, d templateDependencies, url string) ([]byte, error) {
 	// instead of creating a new request always we always cache the bytes.Buffer using the url as the key
 	if t, found := Cache.Get(url); found {
-		b = t.([]byte)
-	} else {
-		f := fetcher.NewFetcher(fetcher.WithClient(d.HTTPClient(ctx)))
-		bb, err := f.FetchContext(ctx, url)
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
-		b = bb.Bytes()
-		_ = Cache.Add(url, b)
+		return t.([]byte), nil
 	}
 
-	var t Template
-	if html {
-		t, err = htemplate.New(url).Funcs(sprig.HermeticHtmlFuncMap()).Parse(string(b))
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
-	} else {
-		t, err = template.New(url).Funcs(sprig.HermeticTxtFuncMap()).Parse(string(b))
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
+	f := fetcher.NewFetcher(fetcher.WithClient(d.HTTPClient(ctx)))
+	bb, err := f.FetchContext(ctx, url)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	b := bb.Bytes()
+	_ = Cache.Add(url, b)
+	return b, nil
+}
+
+func loadRemoteTemplate(ctx context.Context, d templateDependencies, url string, html bool) (Template, error) {
+	b, err := fetchRemoteTemplate(ctx, d, url)
+	if err != nil {
+		return nil, err
+	}
+
+	t := newTemplateSet(url, html, true)
+	if err := parsePartials(ctx, d, nil, t); err != nil {
+		return nil, err
+	}
+	if err := t.parse(url, string(b)); err != nil {
+		return nil, err
 	}
 
 	return t, nil
 }
 
-func loadTemplate(filesystem fs.FS, name, pattern string, html bool) (Template, error) {
-	if t, found := Cache.Get(name); found {
-		return t.(Template), nil
+// resolveTemplateName returns the name of the most specific variant of the template for the locale of the context
+// which exists in the filesystem, and its position in the order of preference. It returns -1 if no variant exists.
+func resolveTemplateName(ctx context.Context, filesystem fs.FS, name string) (string, int) {
+	for i, n := range localizedNames(ctx, name) {
+		if templateExists(filesystem, n) {
+			return n, i
+		}
 	}
+	return "", -1
+}
 
+func templateExists(filesystem fs.FS, name string) bool {
+	if filesystem == nil {
+		return false
+	}
 	matches, _ := fs.Glob(filesystem, name)
-	// make sure the file exists in the fs, otherwise fallback to built in templates
-	if matches == nil {
-		return loadBuiltInTemplate(filesystem, name, html)
+	return matches != nil
+}
+
+func loadTemplate(ctx context.Context, d templateDependencies, filesystem fs.FS, name, pattern string, html bool) (Template, error) {
+	names := localizedNames(ctx, name)
+	for _, n := range names {
+		if t, found := Cache.Get(n); found {
+			return t.(Template), nil
+		}
+
+		if templateExists(filesystem, n) {
+			return parseTemplate(ctx, d, filesystem, n, pattern, html)
+		}
 	}
 
+	// the file does not exist in the fs, fallback to built in templates
+	for _, n := range names[:len(names)-1] {
+		if builtInTemplateExists(n) {
+			return loadBuiltInTemplate(filesystem, n, html)
+		}
+	}
+	return loadBuiltInTemplate(filesystem, name, html)
+}
+
+func parseTemplate(ctx context.Context, d templateDependencies, filesystem fs.FS, name, pattern string, html bool) (Template, error) {
 	glob := name
 	if pattern != "" {
 		// pattern matching is used when we have more than one gotmpl for different use cases, such as i18n support
 		// e.g. some_template/template_name* will match some_template/template_name.body.en_US.gotmpl
-		matches, _ = fs.Glob(filesystem, pattern)
+		matches, _ := fs.Glob(filesystem, pattern)
 		// set the glob string to match patterns
 		if matches != nil {
 			glob = pattern
 		}
 	}
 
-	var tpl Template
-	if html {
-		t, err := htemplate.New(filepath.Base(name)).Funcs(sprig.HermeticHtmlFuncMap()).ParseFS(filesystem, glob)
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
-		tpl = t
-	} else {
-		t, err := template.New(filepath.Base(name)).Funcs(sprig.HermeticTxtFuncMap()).ParseFS(filesystem, glob)
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
-		tpl = t
+	tpl := newTemplateSet(filepath.Base(name), html, true)
+	if err := parsePartials(ctx, d, filesystem, tpl); err != nil {
+		return nil, err
+	}
+	if err := tpl.parseFS(filesystem, glob); err != nil {
+		return nil, err
 	}
 
 	_ = Cache.Add(name, tpl)
 	return tpl, nil
 }
 
+// execute renders the template. If the template defines a template named after the locale of the context, such as
+// {{ define "de" }}, that template is rendered instead.
+func execute(ctx context.Context, t Template, model interface{}) (string, error) {
+	if set, ok := t.(templateSet); ok {
+		for _, suffix := range localeSuffixes(ctx) {
+			if localized := set.lookup(suffix); localized != nil {
+				t = localized
+				break
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, model); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
 func LoadText(ctx context.Context, d templateDependencies, filesystem fs.FS, name, pattern string, model interface{}, remoteURL string) (string, error) {
 	var t Template
 	var err error
@@ -161,17 +307,13 @@ func LoadText(ctx context.Context, d templateDependencies, filesystem fs.FS, nam
 			return "", err
 		}
 	} else {
-		t, err = loadTemplate(filesystem, name, pattern, false)
+		t, err = loadTemplate(ctx, d, filesystem, name, pattern, false)
 		if err != nil {
 			return "", err
 		}
 	}
 
-	var b bytes.Buffer
-	if err := t.Execute(&b, model); err != nil {
-		return "", err
-	}
-	return b.String(), nil
+	return execute(ctx, t, model)
 }
 
 func LoadHTML(ctx context.Context, d templateDependencies, filesystem fs.FS, name, pattern string, model interface{}, remoteURL string) (string, error) {
@@ -183,15 +325,11 @@ func LoadHTML(ctx context.Context, d templateDependencies, filesystem fs.FS, nam
 			return "", err
 		}
 	} else {
-		t, err = loadTemplate(filesystem, name, pattern, true)
+		t, err = loadTemplate(ctx, d, filesystem, name, pattern, true)
 		if err != nil {
 			return "", err
 		}
 	}
 
-	var b bytes.Buffer
-	if err := t.Execute(&b, model); err != nil {
-		return "", err
-	}
-	return b.String(), nil
+	return execute(ctx, t, model)
 }
@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/julienschmidt/httprouter"
@@ -211,3 +212,144 @@ func TestLoadTextTemplate(t *testing.T) {
 
 	})
 }
+
+func TestTemplateSets(t *testing.T) {
+	ctx := context.Background()
+	_, reg := internal.NewFastRegistryWithMocks(t)
+
+	b64 := func(s string) string {
+		return "base64://" + base64.StdEncoding.EncodeToString([]byte(s))
+	}
+
+	newFS := func(files map[string]string) fstest.MapFS {
+		template.Cache, _ = lru.New(16) // prevent Cache hit
+		fsys := fstest.MapFS{}
+		for name, data := range files {
+			fsys[name] = &fstest.MapFile{Data: []byte(data)}
+		}
+		return fsys
+	}
+
+	layout := map[string]string{
+		"layouts/base.gotmpl":              `{{ define "layout" }}<html>{{ template "header" . }}{{ block "content" . }}default{{ end }}{{ template "partials/footer.gotmpl" . }}</html>{{ end }}`,
+		"partials/header.gotmpl":           `{{ define "header" }}<h1>Hi {{ .Name }}</h1>{{ end }}`,
+		"partials/footer.gotmpl":           `<footer>Bye</footer>`,
+		"stub/email.body.gotmpl":           `{{ define "content" }}<p>Body</p>{{ end }}{{ template "layout" . }}`,
+		"stub/email.body.plaintext.gotmpl": `Body {{ .Name }}`,
+	}
+
+	t.Run("case=layouts and partials", func(t *testing.T) {
+		actual, err := template.LoadHTML(ctx, reg, newFS(layout), "stub/email.body.gotmpl", "stub/email.body*", map[string]interface{}{"Name": "Jane"}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "<html><h1>Hi Jane</h1><p>Body</p><footer>Bye</footer></html>", actual)
+	})
+
+	t.Run("case=configured partials", func(t *testing.T) {
+		conf, reg := internal.NewFastRegistryWithMocks(t)
+		conf.MustSet(ctx, config.ViperKeyCourierTemplatePartials, []string{b64(`{{ define "footer" }}The Ory Team{{ end }}`)})
+
+		actual, err := template.LoadText(ctx, reg, nil, "", "", map[string]interface{}{}, b64(`Thanks, {{ template "footer" . }}`))
+		require.NoError(t, err)
+		assert.Equal(t, "Thanks, The Ory Team", actual)
+
+		actual, err = template.LoadText(ctx, reg, newFS(map[string]string{"stub/email.subject.gotmpl": `Hi from {{ template "footer" . }}`}), "stub/email.subject.gotmpl", "", map[string]interface{}{}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "Hi from The Ory Team", actual)
+	})
+
+	t.Run("case=localized files", func(t *testing.T) {
+		fsys := newFS(map[string]string{
+			"stub/email.subject.gotmpl":       "Hello",
+			"stub/email.subject.de.gotmpl":    "Hallo",
+			"stub/email.subject.de_AT.gotmpl": "Servus",
+		})
+
+		for locale, expected := range map[string]string{
+			"":                   "Hello",
+			"fr":                 "Hello",
+			"de-CH, de;q=0.9":    "Hallo",
+			"fr-FR, de-AT;q=0.5": "Servus",
+			"de-AT":              "Servus",
+			"en-US, de-DE;q=0.5": "Hallo",
+		} {
+			t.Run("locale="+locale, func(t *testing.T) {
+				actual, err := template.LoadText(template.WithLocale(ctx, template.NormalizeLocale(locale)), reg, fsys, "stub/email.subject.gotmpl", "", nil, "")
+				require.NoError(t, err)
+				assert.Equal(t, expected, actual)
+			})
+		}
+	})
+
+	t.Run("case=localized templates", func(t *testing.T) {
+		remote := b64(`{{ define "de" }}Hallo {{ .Name }}{{ end }}Hello {{ .Name }}`)
+		for locale, expected := range map[string]string{
+			"":   "Hello Jane",
+			"de": "Hallo Jane",
+			"fr": "Hello Jane",
+		} {
+			actual, err := template.LoadText(template.WithLocale(ctx, locale), reg, nil, "", "", map[string]interface{}{"Name": "Jane"}, remote)
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual, "locale=%s", locale)
+		}
+	})
+
+	t.Run("case=plaintext", func(t *testing.T) {
+		html := `<html><head><title>Ignored</title><style>p { color: red; }</style></head><body>
+			<p>Hello <b>{{ .Name }}</b>,</p>
+			<p>Use <a href="https://example.org/recover">this link</a> or <a href="https://example.org">https://example.org</a>.<br>Thanks!</p>
+			<ul><li>one</li><li>two &amp; three</li></ul>
+		</body></html>`
+		expected := "Hello Jane,\n\nUse this link (https://example.org/recover) or https://example.org.\nThanks!\n\n- one\n- two & three"
+		model := map[string]interface{}{"Name": "Jane"}
+
+		t.Run("case=generated from html file", func(t *testing.T) {
+			actual, err := template.LoadPlainText(ctx, reg, newFS(map[string]string{"stub/email.body.gotmpl": html}), "stub/email.body.plaintext.gotmpl", "stub/email.body.plaintext*", model, nil)
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+
+		t.Run("case=generated from remote html", func(t *testing.T) {
+			actual, err := template.LoadPlainText(ctx, reg, newFS(nil), "test_stub/email.body.plaintext.gotmpl", "test_stub/email.body.plaintext*", model, &config.CourierEmailBodyTemplate{HTML: b64(html)})
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+
+		t.Run("case=generated from localized html file", func(t *testing.T) {
+			fsys := newFS(map[string]string{
+				"stub/email.body.gotmpl":           html,
+				"stub/email.body.plaintext.gotmpl": "Hello",
+				"stub/email.body.de.gotmpl":        "<p>Hallo</p>",
+			})
+			actual, err := template.LoadPlainText(template.WithLocale(ctx, "de"), reg, fsys, "stub/email.body.plaintext.gotmpl", "stub/email.body.plaintext*", model, nil)
+			require.NoError(t, err)
+			assert.Equal(t, "Hallo", actual)
+		})
+
+		t.Run("case=uses plaintext file", func(t *testing.T) {
+			actual, err := template.LoadPlainText(ctx, reg, newFS(layout), "stub/email.body.plaintext.gotmpl", "stub/email.body.plaintext*", model, nil)
+			require.NoError(t, err)
+			assert.Equal(t, "Body Jane", actual)
+		})
+
+		t.Run("case=uses built in plaintext", func(t *testing.T) {
+			actual, err := template.LoadPlainText(ctx, reg, newFS(nil), "test_stub/email.body.plaintext.gotmpl", "test_stub/email.body.plaintext*", map[string]interface{}{"Body": "something"}, nil)
+			require.NoError(t, err)
+			assert.Contains(t, actual, "stub email body something")
+		})
+	})
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	for in, expected := range map[string]string{
+		"":                                   "",
+		"de":                                 "de",
+		"de-CH":                              "de-CH",
+		"de-CH, de;q=0.9, en;q=0.8, *;q=0.5": "de-CH,de,en",
+		"en;q=0.1, fr":                       "fr,en",
+		"a, b, c, d, e, f, g":                "",
+		"fr, de, es, it, nl, pt, ja":         "fr,de,es,it,nl",
+		"not a locale!":                      "",
+	} {
+		assert.Equal(t, expected, template.NormalizeLocale(in), "%q", in)
+	}
+}
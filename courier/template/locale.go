@@ -0,0 +1,87 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package template
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// maxLocales limits how many locales of an Accept-Language header are considered.
+const maxLocales = 5
+
+type localeContextKey struct{}
+
+var mul = language.MustParse("mul")
+
+// WithLocale returns a copy of the context in which templates are rendered in the given locale. The locale is a comma
+// separated list of language tags in order of preference, as returned by NormalizeLocale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeContextKey{}, locale)
+}
+
+// NormalizeLocale parses a language tag or an Accept-Language header and returns its language tags in order of
+// preference, separated by commas. It returns an empty string if the locale does not contain a valid language tag.
+func NormalizeLocale(locale string) string {
+	tags, _, err := language.ParseAcceptLanguage(locale)
+	if err != nil {
+		return ""
+	}
+
+	normalized := make([]string, 0, maxLocales)
+	for _, tag := range tags {
+		// "*" parses to "mul" and matches any language, which is the default template anyway.
+		if tag == language.Und || tag == mul {
+			continue
+		}
+		normalized = append(normalized, tag.String())
+		if len(normalized) == maxLocales {
+			break
+		}
+	}
+	return strings.Join(normalized, ",")
+}
+
+// localeSuffixes returns the suffixes of the template variants for the locale of the context, such as "de_CH" and "de"
+// for "de-CH", in order of preference.
+func localeSuffixes(ctx context.Context) []string {
+	locale, _ := ctx.Value(localeContextKey{}).(string)
+	if locale == "" {
+		return nil
+	}
+
+	var suffixes []string
+	seen := make(map[string]bool)
+	add := func(suffix string) {
+		if !seen[suffix] {
+			seen[suffix] = true
+			suffixes = append(suffixes, suffix)
+		}
+	}
+
+	for _, raw := range strings.Split(locale, ",") {
+		tag, err := language.Parse(raw)
+		if err != nil {
+			continue
+		}
+
+		add(strings.ReplaceAll(tag.String(), "-", "_"))
+		if base, confidence := tag.Base(); confidence != language.No {
+			add(base.String())
+		}
+	}
+	return suffixes
+}
+
+// localizedNames returns the names of the template variants for the locale of the context, followed by the name
+// itself. The variant of "email.body.gotmpl" for "de" is "email.body.de.gotmpl".
+func localizedNames(ctx context.Context, name string) []string {
+	var names []string
+	for _, suffix := range localeSuffixes(ctx) {
+		names = append(names, strings.TrimSuffix(name, ".gotmpl")+"."+suffix+".gotmpl")
+	}
+	return append(names, name)
+}
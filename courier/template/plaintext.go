@@ -0,0 +1,145 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package template
+
+import (
+	"context"
+	"io/fs"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/ory/kratos/driver/config"
+)
+
+// LoadPlainText renders the plain-text body of an email. If the HTML body is customized but the plain-text body is not,
+// the plain-text body is generated from the rendered HTML body, so that both bodies have the same content.
+//
+// The name and pattern are those of the plain-text template, such as "recovery_code/valid/email.body.plaintext.gotmpl".
+// The HTML template is located by removing ".plaintext" from them.
+func LoadPlainText(ctx context.Context, d templateDependencies, filesystem fs.FS, name, pattern string, model interface{}, body *config.CourierEmailBodyTemplate) (string, error) {
+	if body == nil {
+		body = new(config.CourierEmailBodyTemplate)
+	}
+	if body.PlainText != "" {
+		return LoadText(ctx, d, filesystem, name, pattern, model, body.PlainText)
+	}
+
+	htmlName, htmlPattern := strings.Replace(name, ".plaintext", "", 1), strings.Replace(pattern, ".plaintext", "", 1)
+	_, textPos := resolveTemplateName(ctx, filesystem, name)
+	_, htmlPos := resolveTemplateName(ctx, filesystem, htmlName)
+
+	// The HTML body is customized if it is loaded from a remote source or the filesystem. Generate the plain-text body
+	// if there is no plain-text template, or if the HTML template is the better match for the locale.
+	customHTML := body.HTML != "" || htmlPos >= 0
+	if customHTML && (textPos < 0 || (body.HTML == "" && htmlPos < textPos)) {
+		rendered, err := LoadHTML(ctx, d, filesystem, htmlName, htmlPattern, model, body.HTML)
+		if err != nil {
+			return "", err
+		}
+		return htmlToText(rendered), nil
+	}
+
+	return LoadText(ctx, d, filesystem, name, pattern, model, "")
+}
+
+var (
+	spaces         = regexp.MustCompile(`[ \t\r\n\f]+`)
+	trailingSpaces = regexp.MustCompile(`[ \t]+\n`)
+	blankLines     = regexp.MustCompile(`\n{3,}`)
+)
+
+// blockElements start on a new line in the plain-text body.
+var blockElements = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Aside: true, atom.Blockquote: true, atom.Div: true, atom.Dl: true,
+	atom.Dt: true, atom.Dd: true, atom.Footer: true, atom.Form: true, atom.H1: true, atom.H2: true, atom.H3: true,
+	atom.H4: true, atom.H5: true, atom.H6: true, atom.Header: true, atom.Hr: true, atom.Main: true, atom.Nav: true,
+	atom.Ol: true, atom.P: true, atom.Pre: true, atom.Section: true, atom.Table: true, atom.Tr: true, atom.Ul: true,
+}
+
+// skippedElements have no content which is shown to the reader.
+var skippedElements = map[atom.Atom]bool{
+	atom.Head: true, atom.Script: true, atom.Style: true, atom.Title: true,
+}
+
+// htmlToText converts a rendered HTML email body into plain text. Paragraphs and other block elements are separated
+// by blank lines, list items start with a dash, and links are followed by their target in parentheses.
+func htmlToText(in string) string {
+	var (
+		out strings.Builder
+		// skip counts the open elements whose content is skipped.
+		skip int
+		// href and link hold the target and text of the open link, if any.
+		href string
+		link *strings.Builder
+	)
+
+	newline := func(n int) {
+		out.WriteString(strings.Repeat("\n", n))
+	}
+
+	write := func(s string) {
+		if link != nil {
+			link.WriteString(s)
+		}
+		out.WriteString(s)
+	}
+
+	z := html.NewTokenizer(strings.NewReader(in))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			text := trailingSpaces.ReplaceAllString(out.String(), "\n")
+			text = blankLines.ReplaceAllString(text, "\n\n")
+			lines := strings.Split(strings.TrimSpace(text), "\n")
+			for i := range lines {
+				lines[i] = strings.TrimSpace(lines[i])
+			}
+			return strings.Join(lines, "\n")
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+			write(spaces.ReplaceAllString(string(z.Text()), " "))
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			switch {
+			case skippedElements[tok.DataAtom] && tok.Type == html.StartTagToken:
+				skip++
+			case tok.DataAtom == atom.Br:
+				newline(1)
+			case tok.DataAtom == atom.Li:
+				newline(1)
+				out.WriteString("- ")
+			case tok.DataAtom == atom.A:
+				href, link = "", new(strings.Builder)
+				for _, attr := range tok.Attr {
+					if attr.Key == "href" {
+						href = attr.Val
+					}
+				}
+			case blockElements[tok.DataAtom]:
+				newline(2)
+			}
+		case html.EndTagToken:
+			tok := z.Token()
+			switch {
+			case skippedElements[tok.DataAtom]:
+				if skip > 0 {
+					skip--
+				}
+			case tok.DataAtom == atom.A && link != nil:
+				text := strings.TrimSpace(link.String())
+				link = nil
+				if href != "" && !strings.HasPrefix(href, "#") && href != text {
+					write(" (" + href + ")")
+				}
+			case blockElements[tok.DataAtom]:
+				newline(2)
+			}
+		}
+	}
+}
@@ -83,6 +83,20 @@ func TestRemoteTemplates(t *testing.T, basePath string, tmplType courier.Templat
 			return email.NewVerificationCodeInvalid(d, &email.VerificationCodeInvalidModel{})
 		case courier.TypeVerificationCodeValid:
 			return email.NewVerificationCodeValid(d, &email.VerificationCodeValidModel{})
+		case courier.TypeLoginCodeValid:
+			return email.NewLoginCodeValid(d, &email.LoginCodeValidModel{})
+		case courier.TypeRegistrationCodeValid:
+			return email.NewRegistrationCodeValid(d, &email.RegistrationCodeValidModel{})
+		case courier.TypeSecurityNewDeviceLogin:
+			return email.NewSecurityNewDeviceLogin(d, &email.SecurityNewDeviceLoginModel{})
+		case courier.TypeSecurityPasswordChanged:
+			return email.NewSecurityPasswordChanged(d, &email.SecurityPasswordChangedModel{})
+		case courier.TypeSecurityMFAAdded:
+			return email.NewSecurityMFAAdded(d, &email.SecurityMFAAddedModel{})
+		case courier.TypeSecurityMFARemoved:
+			return email.NewSecurityMFARemoved(d, &email.SecurityMFARemovedModel{})
+		case courier.TypeSecurityRecoveryUsed:
+			return email.NewSecurityRecoveryUsed(d, &email.SecurityRecoveryUsedModel{})
 		default:
 			return nil
 		}
@@ -282,5 +282,167 @@ func TestPersister(ctx context.Context, newNetworkUnlessExisting NetworkWrapper,
 				require.ErrorIs(t, err, sqlcon.ErrNoRows)
 			})
 		})
+
+		newMessage := func(t *testing.T, p PersisterWrapper, status courier.MessageStatus) courier.Message {
+			var message courier.Message
+			require.NoError(t, faker.FakeData(&message))
+			message.Recipient = x.NewUUID().String() + "@ory.sh"
+			require.NoError(t, p.AddMessage(ctx, &message))
+			require.NoError(t, p.SetMessageStatus(ctx, message.ID, status))
+			return message
+		}
+
+		t.Run("case=ListDeadLetters", func(t *testing.T) {
+			abandoned := newMessage(t, p, courier.MessageStatusAbandoned)
+			require.NoError(t, p.RecordDispatch(ctx, abandoned.ID, courier.CourierMessageDispatchStatusFailed, errors.New("testerror")))
+			newMessage(t, p, courier.MessageStatusSent)
+
+			ms, _, _, err := p.ListDeadLetters(ctx, courier.ListCourierDeadLettersParameters{Recipient: abandoned.Recipient}, []keysetpagination.Option{})
+			require.NoError(t, err)
+			require.Len(t, ms, 1)
+			assert.Equal(t, abandoned.ID, ms[0].ID)
+			require.Len(t, ms[0].Dispatches, 1)
+			assert.Equal(t, "testerror", gjson.GetBytes(ms[0].Dispatches[0].Error, "message").String())
+
+			t.Run("can not list on another network", func(t *testing.T) {
+				_, p := newNetwork(t, ctx)
+
+				ms, _, _, err := p.ListDeadLetters(ctx, courier.ListCourierDeadLettersParameters{Recipient: abandoned.Recipient}, []keysetpagination.Option{})
+				require.NoError(t, err)
+				assert.Len(t, ms, 0)
+			})
+		})
+
+		t.Run("case=RequeueMessages", func(t *testing.T) {
+			abandoned := newMessage(t, p, courier.MessageStatusAbandoned)
+			require.NoError(t, p.IncrementMessageSendCount(ctx, abandoned.ID))
+			queued := newMessage(t, p, courier.MessageStatusQueued)
+
+			t.Run("can not requeue on another network", func(t *testing.T) {
+				_, p := newNetwork(t, ctx)
+
+				count, err := p.RequeueMessages(ctx, courier.DeadLetterFilter{IDs: []uuid.UUID{abandoned.ID}})
+				require.NoError(t, err)
+				assert.Zero(t, count)
+			})
+
+			count, err := p.RequeueMessages(ctx, courier.DeadLetterFilter{IDs: []uuid.UUID{abandoned.ID, queued.ID}})
+			require.NoError(t, err)
+			assert.Equal(t, 1, count)
+
+			message, err := p.FetchMessage(ctx, abandoned.ID)
+			require.NoError(t, err)
+			assert.Equal(t, courier.MessageStatusQueued, message.Status)
+			assert.Zero(t, message.SendCount)
+		})
+
+		t.Run("case=CancelMessage", func(t *testing.T) {
+			queued := newMessage(t, p, courier.MessageStatusQueued)
+
+			t.Run("can not cancel on another network", func(t *testing.T) {
+				_, p := newNetwork(t, ctx)
+
+				require.ErrorIs(t, p.CancelMessage(ctx, queued.ID), sqlcon.ErrNoRows)
+			})
+
+			require.NoError(t, p.CancelMessage(ctx, queued.ID))
+			message, err := p.FetchMessage(ctx, queued.ID)
+			require.NoError(t, err)
+			assert.Equal(t, courier.MessageStatusCancelled, message.Status)
+
+			require.ErrorIs(t, p.CancelMessage(ctx, queued.ID), courier.ErrMessageNotQueued)
+			require.ErrorIs(t, p.CancelMessage(ctx, x.NewUUID()), sqlcon.ErrNoRows)
+		})
+
+		t.Run("case=PurgeMessages", func(t *testing.T) {
+			sent := newMessage(t, p, courier.MessageStatusSent)
+			queued := newMessage(t, p, courier.MessageStatusQueued)
+			require.NoError(t, p.RecordDispatch(ctx, sent.ID, courier.CourierMessageDispatchStatusSuccess, nil))
+
+			t.Run("can not purge on another network", func(t *testing.T) {
+				_, p := newNetwork(t, ctx)
+
+				_, err := p.PurgeMessages(ctx, time.Now().Add(time.Hour), []courier.MessageStatus{courier.MessageStatusSent})
+				require.NoError(t, err)
+			})
+
+			_, err := p.FetchMessage(ctx, sent.ID)
+			require.NoError(t, err)
+
+			count, err := p.PurgeMessages(ctx, time.Now().Add(time.Hour), []courier.MessageStatus{courier.MessageStatusSent})
+			require.NoError(t, err)
+			assert.GreaterOrEqual(t, count, 1)
+
+			_, err = p.FetchMessage(ctx, sent.ID)
+			require.ErrorIs(t, err, sqlcon.ErrNoRows)
+			_, err = p.FetchMessage(ctx, queued.ID)
+			require.NoError(t, err)
+		})
+
+		t.Run("case=ThrottleMessage", func(t *testing.T) {
+			throttled := newMessage(t, p, courier.MessageStatusProcessing)
+
+			t.Run("can not throttle on another network", func(t *testing.T) {
+				_, p := newNetwork(t, ctx)
+
+				require.ErrorIs(t, p.ThrottleMessage(ctx, throttled.ID, time.Now().Add(time.Hour)), sqlcon.ErrNoRows)
+			})
+
+			require.NoError(t, p.ThrottleMessage(ctx, throttled.ID, time.Now().Add(time.Hour)))
+			message, err := p.FetchMessage(ctx, throttled.ID)
+			require.NoError(t, err)
+			assert.Equal(t, courier.MessageStatusQueued, message.Status)
+			assert.False(t, time.Time(message.ThrottledUntil).IsZero())
+
+			pulled := func(t *testing.T) []uuid.UUID {
+				ms, err := p.NextMessages(ctx, 255)
+				if errors.Is(err, courier.ErrQueueEmpty) {
+					return nil
+				}
+				require.NoError(t, err)
+
+				ids := make([]uuid.UUID, len(ms))
+				for i, m := range ms {
+					ids[i] = m.ID
+				}
+				return ids
+			}
+
+			assert.NotContains(t, pulled(t), throttled.ID)
+
+			require.NoError(t, p.ThrottleMessage(ctx, throttled.ID, time.Now().Add(-time.Second)))
+			assert.Contains(t, pulled(t), throttled.ID)
+
+			require.ErrorIs(t, p.ThrottleMessage(ctx, x.NewUUID(), time.Now()), sqlcon.ErrNoRows)
+		})
+
+		t.Run("case=RecentRecipientDispatches", func(t *testing.T) {
+			sent := newMessage(t, p, courier.MessageStatusSent)
+			require.NoError(t, p.RecordDispatch(ctx, sent.ID, courier.CourierMessageDispatchStatusFailed, errors.New("testerror")))
+			require.NoError(t, p.RecordDispatch(ctx, sent.ID, courier.CourierMessageDispatchStatusSuccess, nil))
+			time.Sleep(time.Second) // wait a bit so that the timestamp ordering works in MySQL.
+			require.NoError(t, p.RecordDispatch(ctx, sent.ID, courier.CourierMessageDispatchStatusSuccess, nil))
+
+			recent, err := p.RecentRecipientDispatches(ctx, sent.Recipient, time.Now().Add(-time.Hour), 10)
+			require.NoError(t, err)
+			require.Len(t, recent, 2)
+			assert.True(t, recent[0].After(recent[1]), "%s should be after %s", recent[0], recent[1])
+
+			recent, err = p.RecentRecipientDispatches(ctx, sent.Recipient, time.Now().Add(-time.Hour), 1)
+			require.NoError(t, err)
+			assert.Len(t, recent, 1)
+
+			recent, err = p.RecentRecipientDispatches(ctx, sent.Recipient, time.Now().Add(time.Hour), 10)
+			require.NoError(t, err)
+			assert.Len(t, recent, 0)
+
+			t.Run("can not list on another network", func(t *testing.T) {
+				_, p := newNetwork(t, ctx)
+
+				recent, err := p.RecentRecipientDispatches(ctx, sent.Recipient, time.Now().Add(-time.Hour), 10)
+				require.NoError(t, err)
+				assert.Len(t, recent, 0)
+			})
+		})
 	}
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/rs/cors"
 	"github.com/stretchr/testify/require"
 	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/publicsuffix"
 
@@ -66,6 +67,13 @@ const (
 	ViperKeyCourierTemplatesVerificationValidEmail           = "courier.templates.verification.valid.email"
 	ViperKeyCourierTemplatesVerificationCodeInvalidEmail     = "courier.templates.verification_code.invalid.email"
 	ViperKeyCourierTemplatesVerificationCodeValidEmail       = "courier.templates.verification_code.valid.email"
+	ViperKeyCourierTemplatesLoginCodeValidEmail              = "courier.templates.login_code.valid.email"
+	ViperKeyCourierTemplatesRegistrationCodeValidEmail       = "courier.templates.registration_code.valid.email"
+	ViperKeyCourierTemplatesSecurityNewDeviceLoginEmail      = "courier.templates.security.new_device_login.email"
+	ViperKeyCourierTemplatesSecurityPasswordChangedEmail     = "courier.templates.security.password_changed.email"
+	ViperKeyCourierTemplatesSecurityMFAAddedEmail            = "courier.templates.security.mfa_added.email"
+	ViperKeyCourierTemplatesSecurityMFARemovedEmail          = "courier.templates.security.mfa_removed.email"
+	ViperKeyCourierTemplatesSecurityRecoveryUsedEmail        = "courier.templates.security.recovery_used.email"
 	ViperKeyCourierDeliveryStrategy                          = "courier.delivery_strategy"
 	ViperKeyCourierHTTPRequestConfig                         = "courier.http.request_config"
 	ViperKeyCourierSMTPFrom                                  = "courier.smtp.from_address"
@@ -76,6 +84,18 @@ const (
 	ViperKeyCourierSMSEnabled                                = "courier.sms.enabled"
 	ViperKeyCourierSMSFrom                                   = "courier.sms.from"
 	ViperKeyCourierMessageRetries                            = "courier.message_retries"
+	ViperKeyCourierChannels                                  = "courier.channels"
+	ViperKeyCourierTemplateChannels                          = "courier.template_channels"
+	ViperKeyCourierTemplatePartials                          = "courier.template_partials"
+	ViperKeyCourierTemplateLocaleTrait                       = "courier.template_locale_trait"
+	ViperKeyCourierRateLimitChannels                         = "courier.rate_limits.channels"
+	ViperKeyCourierRateLimitRecipientMaxMessages             = "courier.rate_limits.per_recipient.max_messages"
+	ViperKeyCourierRateLimitRecipientWindow                  = "courier.rate_limits.per_recipient.window"
+	ViperKeyOutboxEnabled                                    = "outbox.enabled"
+	ViperKeyOutboxSinks                                      = "outbox.sinks"
+	ViperKeyOutboxEventRetries                               = "outbox.event_retries"
+	ViperKeyAuditEnabled                                     = "audit.enabled"
+	ViperKeyAuditActorHeaders                                = "audit.actor_headers"
 	ViperKeySecretsDefault                                   = "secrets.default"
 	ViperKeySecretsCookie                                    = "secrets.cookie"
 	ViperKeySecretsCipher                                    = "secrets.cipher"
@@ -109,6 +129,12 @@ const (
 	ViperKeySessionPersistentCookie                          = "session.cookie.persistent"
 	ViperKeySessionWhoAmIAAL                                 = "session.whoami.required_aal"
 	ViperKeySessionWhoAmICaching                             = "feature_flags.cacheable_sessions"
+	ViperKeySessionTokenizerTemplates                        = "session.whoami.tokenizer.templates"
+	ViperKeySessionCacheEnabled                              = "session.cache.enabled"
+	ViperKeySessionCacheBackend                              = "session.cache.backend"
+	ViperKeySessionCacheTTL                                  = "session.cache.ttl"
+	ViperKeySessionCacheMemoryMaxEntries                     = "session.cache.memory.max_entries"
+	ViperKeySessionCacheRedisURL                             = "session.cache.redis.url"
 	ViperKeySessionRefreshMinTimeLeft                        = "session.earliest_possible_extend"
 	ViperKeyCookieSameSite                                   = "cookies.same_site"
 	ViperKeyCookieDomain                                     = "cookies.domain"
@@ -125,6 +151,20 @@ const (
 	ViperKeySelfServiceLoginRequestLifespan                  = "selfservice.flows.login.lifespan"
 	ViperKeySelfServiceLoginAfter                            = "selfservice.flows.login.after"
 	ViperKeySelfServiceLoginBeforeHooks                      = "selfservice.flows.login.before.hooks"
+	ViperKeySelfServiceLoginBruteForceProtectionEnabled      = "selfservice.flows.login.brute_force_protection.enabled"
+	ViperKeySelfServiceLoginBruteForceMaxAttemptsIdentifier  = "selfservice.flows.login.brute_force_protection.max_attempts_per_identifier"
+	ViperKeySelfServiceLoginBruteForceMaxAttemptsIP          = "selfservice.flows.login.brute_force_protection.max_attempts_per_ip"
+	ViperKeySelfServiceLoginBruteForceLockoutDuration        = "selfservice.flows.login.brute_force_protection.lockout_duration"
+	ViperKeySelfServiceLoginBruteForceBackoffAfter           = "selfservice.flows.login.brute_force_protection.backoff.after_attempts"
+	ViperKeySelfServiceLoginBruteForceBackoffInitialDelay    = "selfservice.flows.login.brute_force_protection.backoff.initial_delay"
+	ViperKeySelfServiceLoginBruteForceBackoffMaxDelay        = "selfservice.flows.login.brute_force_protection.backoff.max_delay"
+	ViperKeySelfServiceLoginBruteForceTrustedProxies         = "selfservice.flows.login.brute_force_protection.trusted_proxies"
+	ViperKeySelfServiceLoginRiskEnabled                      = "selfservice.flows.login.risk.enabled"
+	ViperKeySelfServiceLoginRiskThreshold                    = "selfservice.flows.login.risk.threshold"
+	ViperKeySelfServiceLoginRiskScoreNewDevice               = "selfservice.flows.login.risk.scores.new_device"
+	ViperKeySelfServiceLoginRiskScoreNewCountry              = "selfservice.flows.login.risk.scores.new_country"
+	ViperKeySelfServiceLoginRiskScoreFailedAttempt           = "selfservice.flows.login.risk.scores.failed_attempt"
+	ViperKeySelfServiceLoginRiskWebhookURL                   = "selfservice.flows.login.risk.webhook.url"
 	ViperKeySelfServiceErrorUI                               = "selfservice.flows.error.ui_url"
 	ViperKeySelfServiceLogoutBrowserDefaultReturnTo          = "selfservice.flows.logout.after." + DefaultBrowserReturnURL
 	ViperKeySelfServiceSettingsURL                           = "selfservice.flows.settings.ui_url"
@@ -151,6 +191,8 @@ const (
 	ViperKeySelfServiceVerificationNotifyUnknownRecipients   = "selfservice.flows.verification.notify_unknown_recipients"
 	ViperKeyDefaultIdentitySchemaID                          = "identity.default_schema_id"
 	ViperKeyIdentitySchemas                                  = "identity.schemas"
+	ViperKeyIdentityDeletionGracePeriod                      = "identity.lifecycle.deletion_grace_period"
+	ViperKeyIdentityStateExpiryInterval                      = "identity.lifecycle.expiry_interval"
 	ViperKeyHasherAlgorithm                                  = "hashers.algorithm"
 	ViperKeyHasherArgon2ConfigMemory                         = "hashers.argon2.memory"
 	ViperKeyHasherArgon2ConfigIterations                     = "hashers.argon2.iterations"
@@ -162,11 +204,16 @@ const (
 	ViperKeyHasherArgon2ConfigDedicatedMemory                = "hashers.argon2.dedicated_memory"
 	ViperKeyHasherBcryptCost                                 = "hashers.bcrypt.cost"
 	ViperKeyCipherAlgorithm                                  = "ciphers.algorithm"
+	ViperKeyCipherEnvelopeProvider                           = "ciphers.envelope.provider"
+	ViperKeyCipherEnvelopeFileKeys                           = "ciphers.envelope.file.keys"
+	ViperKeyCipherEnvelopeHTTP                               = "ciphers.envelope.http"
+	ViperKeyCipherEnvelopeDataKeyLifespan                    = "ciphers.envelope.data_key_lifespan"
 	ViperKeyDatabaseCleanupSleepTables                       = "database.cleanup.sleep.tables"
 	ViperKeyDatabaseCleanupBatchSize                         = "database.cleanup.batch_size"
 	ViperKeyLinkLifespan                                     = "selfservice.methods.link.config.lifespan"
 	ViperKeyLinkBaseURL                                      = "selfservice.methods.link.config.base_url"
 	ViperKeyCodeLifespan                                     = "selfservice.methods.code.config.lifespan"
+	ViperKeyCodePasswordlessEnabled                          = "selfservice.methods.code.passwordless_enabled"
 	ViperKeyPasswordHaveIBeenPwnedHost                       = "selfservice.methods.password.config.haveibeenpwned_host"
 	ViperKeyPasswordHaveIBeenPwnedEnabled                    = "selfservice.methods.password.config.haveibeenpwned_enabled"
 	ViperKeyPasswordMaxBreaches                              = "selfservice.methods.password.config.max_breaches"
@@ -180,6 +227,9 @@ const (
 	ViperKeyWebAuthnRPOrigin                                 = "selfservice.methods.webauthn.config.rp.origin"
 	ViperKeyWebAuthnRPIcon                                   = "selfservice.methods.webauthn.config.rp.issuer"
 	ViperKeyWebAuthnPasswordless                             = "selfservice.methods.webauthn.config.passwordless"
+	ViperKeyPasskeyRPDisplayName                             = "selfservice.methods.passkey.config.rp.display_name"
+	ViperKeyPasskeyRPID                                      = "selfservice.methods.passkey.config.rp.id"
+	ViperKeyPasskeyRPOrigin                                  = "selfservice.methods.passkey.config.rp.origin"
 	ViperKeyOAuth2ProviderURL                                = "oauth2_provider.url"
 	ViperKeyOAuth2ProviderHeader                             = "oauth2_provider.headers"
 	ViperKeyOAuth2ProviderOverrideReturnTo                   = "oauth2_provider.override_return_to"
@@ -217,6 +267,44 @@ type (
 	Bcrypt struct {
 		Cost uint32 `json:"cost"`
 	}
+	CourierChannelRateLimit struct {
+		MessagesPerSecond float64 `json:"messages_per_second"`
+		Burst             int     `json:"burst"`
+	}
+	CourierRecipientRateLimit struct {
+		MaxMessages int           `json:"max_messages"`
+		Window      time.Duration `json:"window"`
+	}
+	LoginBruteForceProtection struct {
+		Enabled                  bool          `json:"enabled"`
+		MaxAttemptsPerIdentifier int           `json:"max_attempts_per_identifier"`
+		MaxAttemptsPerIP         int           `json:"max_attempts_per_ip"`
+		LockoutDuration          time.Duration `json:"lockout_duration"`
+		BackoffAfterAttempts     int           `json:"backoff_after_attempts"`
+		BackoffInitialDelay      time.Duration `json:"backoff_initial_delay"`
+		BackoffMaxDelay          time.Duration `json:"backoff_max_delay"`
+		TrustedProxies           []string      `json:"trusted_proxies"`
+	}
+	LoginRisk struct {
+		Enabled            bool     `json:"enabled"`
+		Threshold          int      `json:"threshold"`
+		NewDeviceScore     int      `json:"new_device_score"`
+		NewCountryScore    int      `json:"new_country_score"`
+		FailedAttemptScore int      `json:"failed_attempt_score"`
+		WebhookURL         *url.URL `json:"webhook_url"`
+	}
+	SessionCache struct {
+		Enabled          bool          `json:"enabled"`
+		Backend          string        `json:"backend"`
+		TTL              time.Duration `json:"ttl"`
+		MemoryMaxEntries int           `json:"memory_max_entries"`
+		RedisURL         string        `json:"redis_url"`
+	}
+	SessionTokenizeFormat struct {
+		TTL             time.Duration `json:"ttl" koanf:"ttl"`
+		ClaimsMapperURL string        `json:"claims_mapper_url" koanf:"claims_mapper_url"`
+		JWKSURL         string        `json:"jwks_url" koanf:"jwks_url"`
+	}
 	SelfServiceHook struct {
 		Name   string          `json:"hook"`
 		Config json.RawMessage `json:"config"`
@@ -246,6 +334,22 @@ type (
 		Body    *CourierEmailBodyTemplate `json:"body"`
 		Subject string                    `json:"subject"`
 	}
+	CourierSMTPConfig struct {
+		ConnectionURI  string            `json:"connection_uri"`
+		ClientCertPath string            `json:"client_cert_path"`
+		ClientKeyPath  string            `json:"client_key_path"`
+		FromAddress    string            `json:"from_address"`
+		FromName       string            `json:"from_name"`
+		Headers        map[string]string `json:"headers"`
+		LocalName      string            `json:"local_name"`
+	}
+	CourierChannel struct {
+		ID            string             `json:"id"`
+		Type          string             `json:"type"`
+		SMTPConfig    *CourierSMTPConfig `json:"smtp_config"`
+		RequestConfig json.RawMessage    `json:"request_config"`
+		From          string             `json:"from"`
+	}
 	Config struct {
 		l                  *logrusx.Logger
 		p                  *configx.Provider
@@ -278,7 +382,20 @@ type (
 		CourierTemplatesRecoveryCodeValid(ctx context.Context) *CourierEmailTemplate
 		CourierTemplatesVerificationCodeInvalid(ctx context.Context) *CourierEmailTemplate
 		CourierTemplatesVerificationCodeValid(ctx context.Context) *CourierEmailTemplate
+		CourierTemplatesLoginCodeValid(ctx context.Context) *CourierEmailTemplate
+		CourierTemplatesRegistrationCodeValid(ctx context.Context) *CourierEmailTemplate
+		CourierTemplatesSecurityNewDeviceLogin(ctx context.Context) *CourierEmailTemplate
+		CourierTemplatesSecurityPasswordChanged(ctx context.Context) *CourierEmailTemplate
+		CourierTemplatesSecurityMFAAdded(ctx context.Context) *CourierEmailTemplate
+		CourierTemplatesSecurityMFARemoved(ctx context.Context) *CourierEmailTemplate
+		CourierTemplatesSecurityRecoveryUsed(ctx context.Context) *CourierEmailTemplate
 		CourierMessageRetries(ctx context.Context) int
+		CourierChannels(ctx context.Context) ([]*CourierChannel, error)
+		CourierChannelRateLimit(ctx context.Context, channelID string) *CourierChannelRateLimit
+		CourierRecipientRateLimit(ctx context.Context) *CourierRecipientRateLimit
+		CourierTemplateChannel(ctx context.Context, templateType string) string
+		CourierTemplatePartials(ctx context.Context) []string
+		CourierTemplateLocaleTrait(ctx context.Context) string
 	}
 )
 
@@ -566,6 +683,17 @@ func (p *Config) IdentityTraitsSchemas(ctx context.Context) (ss Schemas, err err
 	return ss, nil
 }
 
+// IdentityDeletionGracePeriod is the time an identity pending deletion is kept before it is deleted.
+func (p *Config) IdentityDeletionGracePeriod(ctx context.Context) time.Duration {
+	return p.GetProvider(ctx).DurationF(ViperKeyIdentityDeletionGracePeriod, 30*24*time.Hour)
+}
+
+// IdentityStateExpiryInterval is the interval in which ended suspensions and passed deletion grace periods are
+// processed.
+func (p *Config) IdentityStateExpiryInterval(ctx context.Context) time.Duration {
+	return p.GetProvider(ctx).DurationF(ViperKeyIdentityStateExpiryInterval, time.Minute)
+}
+
 func (p *Config) AdminListenOn(ctx context.Context) string {
 	return p.listenOn(ctx, "admin")
 }
@@ -968,6 +1096,30 @@ func (p *Config) SelfServiceFlowLoginRequestLifespan(ctx context.Context) time.D
 	return p.GetProvider(ctx).DurationF(ViperKeySelfServiceLoginRequestLifespan, time.Hour)
 }
 
+func (p *Config) SelfServiceFlowLoginBruteForceProtection(ctx context.Context) *LoginBruteForceProtection {
+	return &LoginBruteForceProtection{
+		Enabled:                  p.GetProvider(ctx).Bool(ViperKeySelfServiceLoginBruteForceProtectionEnabled),
+		MaxAttemptsPerIdentifier: p.GetProvider(ctx).IntF(ViperKeySelfServiceLoginBruteForceMaxAttemptsIdentifier, 10),
+		MaxAttemptsPerIP:         p.GetProvider(ctx).IntF(ViperKeySelfServiceLoginBruteForceMaxAttemptsIP, 100),
+		LockoutDuration:          p.GetProvider(ctx).DurationF(ViperKeySelfServiceLoginBruteForceLockoutDuration, 15*time.Minute),
+		BackoffAfterAttempts:     p.GetProvider(ctx).IntF(ViperKeySelfServiceLoginBruteForceBackoffAfter, 3),
+		BackoffInitialDelay:      p.GetProvider(ctx).DurationF(ViperKeySelfServiceLoginBruteForceBackoffInitialDelay, time.Second),
+		BackoffMaxDelay:          p.GetProvider(ctx).DurationF(ViperKeySelfServiceLoginBruteForceBackoffMaxDelay, time.Minute),
+		TrustedProxies:           p.GetProvider(ctx).Strings(ViperKeySelfServiceLoginBruteForceTrustedProxies),
+	}
+}
+
+func (p *Config) SelfServiceFlowLoginRisk(ctx context.Context) *LoginRisk {
+	return &LoginRisk{
+		Enabled:            p.GetProvider(ctx).Bool(ViperKeySelfServiceLoginRiskEnabled),
+		Threshold:          p.GetProvider(ctx).IntF(ViperKeySelfServiceLoginRiskThreshold, 50),
+		NewDeviceScore:     p.GetProvider(ctx).IntF(ViperKeySelfServiceLoginRiskScoreNewDevice, 30),
+		NewCountryScore:    p.GetProvider(ctx).IntF(ViperKeySelfServiceLoginRiskScoreNewCountry, 100),
+		FailedAttemptScore: p.GetProvider(ctx).IntF(ViperKeySelfServiceLoginRiskScoreFailedAttempt, 10),
+		WebhookURL:         p.GetProvider(ctx).RequestURIF(ViperKeySelfServiceLoginRiskWebhookURL, nil),
+	}
+}
+
 func (p *Config) SelfServiceFlowSettingsFlowLifespan(ctx context.Context) time.Duration {
 	return p.GetProvider(ctx).DurationF(ViperKeySelfServiceSettingsRequestLifespan, time.Hour)
 }
@@ -1090,10 +1242,110 @@ func (p *Config) CourierTemplatesVerificationCodeValid(ctx context.Context) *Cou
 	return p.CourierTemplatesHelper(ctx, ViperKeyCourierTemplatesVerificationCodeValidEmail)
 }
 
+func (p *Config) CourierTemplatesLoginCodeValid(ctx context.Context) *CourierEmailTemplate {
+	return p.CourierTemplatesHelper(ctx, ViperKeyCourierTemplatesLoginCodeValidEmail)
+}
+
+func (p *Config) CourierTemplatesRegistrationCodeValid(ctx context.Context) *CourierEmailTemplate {
+	return p.CourierTemplatesHelper(ctx, ViperKeyCourierTemplatesRegistrationCodeValidEmail)
+}
+
+func (p *Config) CourierTemplatesSecurityNewDeviceLogin(ctx context.Context) *CourierEmailTemplate {
+	return p.CourierTemplatesHelper(ctx, ViperKeyCourierTemplatesSecurityNewDeviceLoginEmail)
+}
+
+func (p *Config) CourierTemplatesSecurityPasswordChanged(ctx context.Context) *CourierEmailTemplate {
+	return p.CourierTemplatesHelper(ctx, ViperKeyCourierTemplatesSecurityPasswordChangedEmail)
+}
+
+func (p *Config) CourierTemplatesSecurityMFAAdded(ctx context.Context) *CourierEmailTemplate {
+	return p.CourierTemplatesHelper(ctx, ViperKeyCourierTemplatesSecurityMFAAddedEmail)
+}
+
+func (p *Config) CourierTemplatesSecurityMFARemoved(ctx context.Context) *CourierEmailTemplate {
+	return p.CourierTemplatesHelper(ctx, ViperKeyCourierTemplatesSecurityMFARemovedEmail)
+}
+
+func (p *Config) CourierTemplatesSecurityRecoveryUsed(ctx context.Context) *CourierEmailTemplate {
+	return p.CourierTemplatesHelper(ctx, ViperKeyCourierTemplatesSecurityRecoveryUsedEmail)
+}
+
 func (p *Config) CourierMessageRetries(ctx context.Context) int {
 	return p.GetProvider(ctx).IntF(ViperKeyCourierMessageRetries, 5)
 }
 
+// CourierChannels returns the channels configured in `courier.channels`. The channels derived from the
+// `courier.smtp`, `courier.http` and `courier.sms` keys are not part of the result.
+func (p *Config) CourierChannels(ctx context.Context) ([]*CourierChannel, error) {
+	out, err := p.GetProvider(ctx).Marshal(kjson.Parser())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	config := gjson.GetBytes(out, ViperKeyCourierChannels).Raw
+	if len(config) == 0 {
+		return nil, nil
+	}
+
+	var channels []*CourierChannel
+	if err := json.Unmarshal([]byte(config), &channels); err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to decode values from %s.", ViperKeyCourierChannels).WithDebug(err.Error()))
+	}
+
+	for _, c := range channels {
+		if c.SMTPConfig != nil {
+			c.SMTPConfig.FromAddress = stringsx.Coalesce(c.SMTPConfig.FromAddress, "noreply@kratos.ory.sh")
+			c.SMTPConfig.LocalName = stringsx.Coalesce(c.SMTPConfig.LocalName, "localhost")
+		}
+		c.From = stringsx.Coalesce(c.From, "Ory Kratos")
+	}
+	return channels, nil
+}
+
+// CourierTemplateChannel returns the ID of the channel messages of the given template type are sent through, or an
+// empty string if the template uses the default channel of its message type.
+func (p *Config) CourierTemplateChannel(ctx context.Context, templateType string) string {
+	return p.GetProvider(ctx).String(ViperKeyCourierTemplateChannels + "." + templateType)
+}
+
+// CourierTemplatePartials returns the URIs of the templates which are available to all message templates.
+func (p *Config) CourierTemplatePartials(ctx context.Context) []string {
+	return p.GetProvider(ctx).Strings(ViperKeyCourierTemplatePartials)
+}
+
+// CourierTemplateLocaleTrait returns the identity trait holding the identity's preferred locale.
+func (p *Config) CourierTemplateLocaleTrait(ctx context.Context) string {
+	return p.GetProvider(ctx).String(ViperKeyCourierTemplateLocaleTrait)
+}
+
+// CourierChannelRateLimit returns the rate limit of the courier channel, or nil if the channel is not rate limited.
+func (p *Config) CourierChannelRateLimit(ctx context.Context, channelID string) *CourierChannelRateLimit {
+	key := ViperKeyCourierRateLimitChannels + "." + channelID
+	perSecond := p.GetProvider(ctx).Float64(key + ".messages_per_second")
+	if perSecond <= 0 {
+		return nil
+	}
+
+	return &CourierChannelRateLimit{
+		MessagesPerSecond: perSecond,
+		Burst:             p.GetProvider(ctx).IntF(key+".burst", 1),
+	}
+}
+
+// CourierRecipientRateLimit returns the maximum number of messages delivered to a single recipient within a window,
+// or nil if messages to a recipient are not rate limited.
+func (p *Config) CourierRecipientRateLimit(ctx context.Context) *CourierRecipientRateLimit {
+	maxMessages := p.GetProvider(ctx).Int(ViperKeyCourierRateLimitRecipientMaxMessages)
+	if maxMessages <= 0 {
+		return nil
+	}
+
+	return &CourierRecipientRateLimit{
+		MaxMessages: maxMessages,
+		Window:      p.GetProvider(ctx).DurationF(ViperKeyCourierRateLimitRecipientWindow, time.Hour),
+	}
+}
+
 func (p *Config) CourierSMTPHeaders(ctx context.Context) map[string]string {
 	return p.GetProvider(ctx).StringMap(ViperKeyCourierSMTPHeaders)
 }
@@ -1188,6 +1440,57 @@ func (p *Config) IsBackgroundCourierEnabled(ctx context.Context) bool {
 	return p.GetProvider(ctx).Bool("watch-courier")
 }
 
+func (p *Config) IsBackgroundOutboxEnabled(ctx context.Context) bool {
+	return p.GetProvider(ctx).Bool("watch-outbox")
+}
+
+func (p *Config) IsBackgroundIdentityStateExpiryEnabled(ctx context.Context) bool {
+	return p.GetProvider(ctx).Bool("watch-identities")
+}
+
+func (p *Config) IsBackgroundCipherRotationEnabled(ctx context.Context) bool {
+	return p.GetProvider(ctx).Bool("rotate-cipher")
+}
+
+func (p *Config) OutboxEnabled(ctx context.Context) bool {
+	return p.GetProvider(ctx).Bool(ViperKeyOutboxEnabled)
+}
+
+func (p *Config) OutboxEventRetries(ctx context.Context) int {
+	return p.GetProvider(ctx).IntF(ViperKeyOutboxEventRetries, 5)
+}
+
+// OutboxSinks returns the HTTP request configurations of the outbox sinks. The request method
+// defaults to POST and the body to the JSON encoded event.
+func (p *Config) OutboxSinks(ctx context.Context) []json.RawMessage {
+	out, err := p.GetProvider(ctx).Marshal(kjson.Parser())
+	if err != nil {
+		p.l.WithError(err).Warn("Unable to marshal outbox sink configuration.")
+		return nil
+	}
+
+	var sinks []json.RawMessage
+	for _, sink := range gjson.GetBytes(out, ViperKeyOutboxSinks).Array() {
+		raw := []byte(sink.Raw)
+		if !sink.Get("method").Exists() {
+			raw, _ = sjson.SetBytes(raw, "method", "POST")
+		}
+		if !sink.Get("body").Exists() {
+			raw, _ = sjson.SetBytes(raw, "body", "base64://ZnVuY3Rpb24oY3R4KSBjdHg=")
+		}
+		sinks = append(sinks, raw)
+	}
+	return sinks
+}
+
+func (p *Config) AuditEnabled(ctx context.Context) bool {
+	return p.GetProvider(ctx).Bool(ViperKeyAuditEnabled)
+}
+
+func (p *Config) AuditActorHeaders(ctx context.Context) []string {
+	return p.GetProvider(ctx).StringsF(ViperKeyAuditActorHeaders, []string{"X-Forwarded-User"})
+}
+
 func (p *Config) CourierExposeMetricsPort(ctx context.Context) int {
 	return p.GetProvider(ctx).Int("expose-metrics-port")
 }
@@ -1236,6 +1539,10 @@ func (p *Config) SelfServiceCodeMethodLifespan(ctx context.Context) time.Duratio
 	return p.GetProvider(ctx).DurationF(ViperKeyCodeLifespan, time.Hour)
 }
 
+func (p *Config) SelfServiceCodeStrategyPasswordlessEnabled(ctx context.Context) bool {
+	return p.GetProvider(ctx).Bool(ViperKeyCodePasswordlessEnabled)
+}
+
 func (p *Config) DatabaseCleanupSleepTables(ctx context.Context) time.Duration {
 	return p.GetProvider(ctx).Duration(ViperKeyDatabaseCleanupSleepTables)
 }
@@ -1287,6 +1594,44 @@ func (p *Config) SessionWhoAmICaching(ctx context.Context) bool {
 	return p.GetProvider(ctx).Bool(ViperKeySessionWhoAmICaching)
 }
 
+// SessionCacheMemoryMaxTTL limits how long the in-memory session cache holds sessions. The in-memory cache is only
+// invalidated on the instance which changed a session, so other instances may return a revoked session for this long.
+const SessionCacheMemoryMaxTTL = 5 * time.Second
+
+func (p *Config) SessionCache(ctx context.Context) *SessionCache {
+	c := &SessionCache{
+		Enabled:          p.GetProvider(ctx).Bool(ViperKeySessionCacheEnabled),
+		Backend:          p.GetProvider(ctx).StringF(ViperKeySessionCacheBackend, "memory"),
+		MemoryMaxEntries: p.GetProvider(ctx).IntF(ViperKeySessionCacheMemoryMaxEntries, 10000),
+		RedisURL:         p.GetProvider(ctx).String(ViperKeySessionCacheRedisURL),
+	}
+
+	if c.Backend == "memory" {
+		c.TTL = p.GetProvider(ctx).DurationF(ViperKeySessionCacheTTL, SessionCacheMemoryMaxTTL)
+		if c.TTL > SessionCacheMemoryMaxTTL {
+			c.TTL = SessionCacheMemoryMaxTTL
+		}
+	} else {
+		c.TTL = p.GetProvider(ctx).DurationF(ViperKeySessionCacheTTL, time.Minute)
+	}
+	return c
+}
+
+func (p *Config) TokenizeTemplate(ctx context.Context, key string) (_ *SessionTokenizeFormat, err error) {
+	var result SessionTokenizeFormat
+	path := ViperKeySessionTokenizerTemplates + "." + key
+	if !p.GetProvider(ctx).Exists(path) {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf("Unable to find tokenizer template \"%s\".", key))
+	}
+
+	if err := p.GetProvider(ctx).Unmarshal(path, &result); err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to decode tokenizer template \"%s\": %s", key, err))
+	}
+
+	result.TTL = p.GetProvider(ctx).DurationF(path+".ttl", time.Minute)
+	return &result, nil
+}
+
 func (p *Config) SessionRefreshMinTimeLeft(ctx context.Context) time.Duration {
 	return p.GetProvider(ctx).DurationF(ViperKeySessionRefreshMinTimeLeft, p.SessionLifespan(ctx))
 }
@@ -1375,6 +1720,19 @@ func (p *Config) WebAuthnConfig(ctx context.Context) *webauthn.Config {
 	}
 }
 
+func (p *Config) PasskeyConfig(ctx context.Context) *webauthn.Config {
+	return &webauthn.Config{
+		RPDisplayName: p.GetProvider(ctx).String(ViperKeyPasskeyRPDisplayName),
+		RPID:          p.GetProvider(ctx).String(ViperKeyPasskeyRPID),
+		RPOrigin:      p.GetProvider(ctx).String(ViperKeyPasskeyRPOrigin),
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			UserVerification:   protocol.VerificationPreferred,
+		},
+	}
+}
+
 func (p *Config) HasherPasswordHashingAlgorithm(ctx context.Context) string {
 	configValue := p.GetProvider(ctx).StringF(ViperKeyHasherAlgorithm, DefaultPasswordHashingAlgorithm)
 	switch configValue {
@@ -1394,6 +1752,8 @@ func (p *Config) CipherAlgorithm(ctx context.Context) string {
 		return configValue
 	case "xchacha20-poly1305":
 		return configValue
+	case "envelope":
+		return configValue
 	case "aes":
 		fallthrough
 	default:
@@ -1401,6 +1761,42 @@ func (p *Config) CipherAlgorithm(ctx context.Context) string {
 	}
 }
 
+// CipherEnvelopeProvider returns the provider of the key encryption key used by the envelope cipher, either "file" or
+// "http".
+func (p *Config) CipherEnvelopeProvider(ctx context.Context) string {
+	return p.GetProvider(ctx).StringF(ViperKeyCipherEnvelopeProvider, "file")
+}
+
+// CipherEnvelopeFileKeys returns the URI of the JSON Web Key Set holding the key encryption keys of the file provider.
+func (p *Config) CipherEnvelopeFileKeys(ctx context.Context) string {
+	return p.GetProvider(ctx).String(ViperKeyCipherEnvelopeFileKeys)
+}
+
+// CipherEnvelopeHTTPConfig returns the request configuration of the key management service used by the http provider.
+func (p *Config) CipherEnvelopeHTTPConfig(ctx context.Context) json.RawMessage {
+	out, err := p.GetProvider(ctx).Marshal(kjson.Parser())
+	if err != nil {
+		p.l.WithError(err).Warn("Unable to marshal the envelope cipher configuration.")
+		return nil
+	}
+
+	raw := []byte(gjson.GetBytes(out, ViperKeyCipherEnvelopeHTTP).Raw)
+	if len(raw) == 0 {
+		return nil
+	}
+	raw, _ = sjson.SetBytes(raw, "method", "POST")
+	if !gjson.GetBytes(raw, "body").Exists() {
+		raw, _ = sjson.SetBytes(raw, "body", "base64://ZnVuY3Rpb24oY3R4KSBjdHg=")
+	}
+	return raw
+}
+
+// CipherEnvelopeDataKeyLifespan returns how long the envelope cipher encrypts data with the same data key before it
+// generates and wraps a new one.
+func (p *Config) CipherEnvelopeDataKeyLifespan(ctx context.Context) time.Duration {
+	return p.GetProvider(ctx).DurationF(ViperKeyCipherEnvelopeDataKeyLifespan, time.Hour)
+}
+
 type CertFunc = func(*tls.ClientHelloInfo) (*tls.Certificate, error)
 
 func (p *Config) GetTLSCertificatesForPublic(ctx context.Context) CertFunc {
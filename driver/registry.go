@@ -19,6 +19,8 @@ import (
 
 	"github.com/ory/x/logrusx"
 
+	"github.com/ory/kratos/audit"
+	"github.com/ory/kratos/bruteforce"
 	"github.com/ory/kratos/continuity"
 	"github.com/ory/kratos/courier"
 	"github.com/ory/kratos/hash"
@@ -32,6 +34,7 @@ import (
 	"github.com/ory/x/healthx"
 
 	"github.com/ory/kratos/persistence"
+	"github.com/ory/kratos/risk"
 	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/logout"
 	"github.com/ory/kratos/selfservice/flow/registration"
@@ -42,6 +45,8 @@ import (
 
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
+	"github.com/ory/kratos/outbox"
 	"github.com/ory/kratos/selfservice/errorx"
 	password2 "github.com/ory/kratos/selfservice/strategy/password"
 	"github.com/ory/kratos/session"
@@ -57,6 +62,8 @@ type Registry interface {
 
 	WithCSRFHandler(c nosurf.Handler)
 	WithCSRFTokenGenerator(cg x.CSRFToken)
+	WithLoginRiskEvaluators(evaluators ...risk.Evaluator)
+	WithSessionCache(c session.Cache)
 
 	MetricsHandler() *prometheus.Handler
 	HealthHandler(ctx context.Context) *healthx.Handler
@@ -99,19 +106,38 @@ type Registry interface {
 	identity.PoolProvider
 	identity.PrivilegedPoolProvider
 	identity.ManagementProvider
+	identity.CipherRotatorProvider
+	identity.ExporterProvider
+	identity.SchemaMigratorProvider
+	identity.SchemaMigrationPersistenceProvider
+	identity.StateExpirerProvider
 	identity.ActiveCredentialsCounterStrategyProvider
 
+	organization.HandlerProvider
+	organization.PersistenceProvider
+
 	courier.HandlerProvider
 	courier.PersistenceProvider
 
+	outbox.HandlerProvider
+	outbox.PersistenceProvider
+	outbox.DispatcherProvider
+
+	audit.HandlerProvider
+	audit.PersistenceProvider
+	audit.RecorderProvider
+
 	schema.HandlerProvider
 	schema.IdentityTraitsProvider
+	schema.PersistenceProvider
 
 	password2.ValidationProvider
 
 	session.HandlerProvider
 	session.ManagementProvider
+	session.TokenizerProvider
 	session.PersistenceProvider
+	session.CacheProvider
 
 	settings.HandlerProvider
 	settings.ErrorHandlerProvider
@@ -125,6 +151,12 @@ type Registry interface {
 	login.HandlerProvider
 	login.StrategyProvider
 
+	bruteforce.PersistenceProvider
+	bruteforce.ThrottlerProvider
+
+	risk.EvaluatorsProvider
+	risk.AssessorProvider
+
 	logout.HandlerProvider
 
 	registration.FlowPersistenceProvider
@@ -28,6 +28,8 @@ import (
 
 	"github.com/ory/kratos/hydra"
 	"github.com/ory/kratos/selfservice/strategy/code"
+	"github.com/ory/kratos/selfservice/strategy/passkey"
+	"github.com/ory/kratos/selfservice/strategy/saml"
 	"github.com/ory/kratos/selfservice/strategy/webauthn"
 
 	"github.com/ory/kratos/selfservice/strategy/lookup"
@@ -38,6 +40,8 @@ import (
 
 	prometheus "github.com/ory/x/prometheusx"
 
+	"github.com/ory/kratos/audit"
+	"github.com/ory/kratos/bruteforce"
 	"github.com/ory/kratos/cipher"
 	"github.com/ory/kratos/continuity"
 	"github.com/ory/kratos/hash"
@@ -63,6 +67,7 @@ import (
 	"github.com/ory/kratos/courier"
 	"github.com/ory/kratos/persistence"
 	"github.com/ory/kratos/persistence/sql"
+	"github.com/ory/kratos/risk"
 	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/logout"
 	"github.com/ory/kratos/selfservice/flow/registration"
@@ -72,6 +77,8 @@ import (
 
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
+	"github.com/ory/kratos/outbox"
 	"github.com/ory/kratos/selfservice/errorx"
 	password2 "github.com/ory/kratos/selfservice/strategy/password"
 	"github.com/ory/kratos/session"
@@ -85,6 +92,7 @@ type RegistryDefault struct {
 	ctxer contextx.Contextualizer
 
 	injectedSelfserviceHooks map[string]func(config.SelfServiceHook) interface{}
+	injectedRiskEvaluators   []risk.Evaluator
 
 	nosurf         nosurf.Handler
 	trc            *otelx.Tracer
@@ -101,19 +109,34 @@ type RegistryDefault struct {
 	hookSessionDestroyer   *hook.SessionDestroyer
 	hookAddressVerifier    *hook.AddressVerifier
 	hookShowVerificationUI *hook.ShowVerificationUIHook
+	hookSecurityNotifier   *hook.SecurityNotifier
 
-	identityHandler   *identity.Handler
-	identityValidator *identity.Validator
-	identityManager   *identity.Manager
+	identityHandler        *identity.Handler
+	identityValidator      *identity.Validator
+	identityManager        *identity.Manager
+	identityCipherRotator  *identity.CipherRotator
+	identityExporter       *identity.Exporter
+	identitySchemaMigrator *identity.SchemaMigrator
+	identityStateExpirer   *identity.StateExpirer
+
+	organizationHandler *organization.Handler
 
 	courierHandler *courier.Handler
 
+	outboxHandler    *outbox.Handler
+	outboxDispatcher *outbox.Dispatcher
+
+	auditHandler  *audit.Handler
+	auditRecorder *audit.Recorder
+
 	continuityManager continuity.Manager
 
 	schemaHandler *schema.Handler
 
-	sessionHandler *session.Handler
-	sessionManager session.Manager
+	sessionHandler   *session.Handler
+	sessionManager   session.Manager
+	sessionTokenizer *session.Tokenizer
+	sessionCache     session.Cache
 
 	passwordHasher    hash.Hasher
 	passwordValidator password2.Validator
@@ -132,6 +155,9 @@ type RegistryDefault struct {
 	selfserviceLoginHandler             *login.Handler
 	selfserviceLoginRequestErrorHandler *login.ErrorHandler
 
+	loginThrottler    *bruteforce.Throttler
+	loginRiskAssessor *risk.Assessor
+
 	selfserviceSettingsHandler      *settings.Handler
 	selfserviceSettingsErrorHandler *settings.ErrorHandler
 	selfserviceSettingsExecutor     *settings.HookExecutor
@@ -180,7 +206,10 @@ func (m *RegistryDefault) RegisterPublicRoutes(ctx context.Context, router *x.Ro
 	m.LogoutHandler().RegisterPublicRoutes(router)
 	m.SettingsHandler().RegisterPublicRoutes(router)
 	m.IdentityHandler().RegisterPublicRoutes(router)
+	m.OrganizationHandler().RegisterPublicRoutes(router)
 	m.CourierHandler().RegisterPublicRoutes(router)
+	m.OutboxHandler().RegisterPublicRoutes(router)
+	m.AuditHandler().RegisterPublicRoutes(router)
 	m.AllLoginStrategies().RegisterPublicRoutes(router)
 	m.AllSettingsStrategies().RegisterPublicRoutes(router)
 	m.AllRegistrationStrategies().RegisterPublicRoutes(router)
@@ -204,7 +233,10 @@ func (m *RegistryDefault) RegisterAdminRoutes(ctx context.Context, router *x.Rou
 	m.SchemaHandler().RegisterAdminRoutes(router)
 	m.SettingsHandler().RegisterAdminRoutes(router)
 	m.IdentityHandler().RegisterAdminRoutes(router)
+	m.OrganizationHandler().RegisterAdminRoutes(router)
 	m.CourierHandler().RegisterAdminRoutes(router)
+	m.OutboxHandler().RegisterAdminRoutes(router)
+	m.AuditHandler().RegisterAdminRoutes(router)
 	m.SelfServiceErrorHandler().RegisterAdminRoutes(router)
 
 	m.RecoveryHandler().RegisterAdminRoutes(router)
@@ -314,11 +346,13 @@ func (m *RegistryDefault) selfServiceStrategies() []interface{} {
 		m.selfserviceStrategies = []interface{}{
 			password2.NewStrategy(m),
 			oidc.NewStrategy(m),
+			saml.NewStrategy(m),
 			profile.NewStrategy(m),
 			code.NewStrategy(m),
 			link.NewStrategy(m),
 			totp.NewStrategy(m),
 			webauthn.NewStrategy(m),
+			passkey.NewStrategy(m),
 			lookup.NewStrategy(m),
 		}
 	}
@@ -412,6 +446,13 @@ func (m *RegistryDefault) IdentityHandler() *identity.Handler {
 	return m.identityHandler
 }
 
+func (m *RegistryDefault) OrganizationHandler() *organization.Handler {
+	if m.organizationHandler == nil {
+		m.organizationHandler = organization.NewHandler(m)
+	}
+	return m.organizationHandler
+}
+
 func (m *RegistryDefault) CourierHandler() *courier.Handler {
 	if m.courierHandler == nil {
 		m.courierHandler = courier.NewHandler(m)
@@ -419,6 +460,34 @@ func (m *RegistryDefault) CourierHandler() *courier.Handler {
 	return m.courierHandler
 }
 
+func (m *RegistryDefault) OutboxHandler() *outbox.Handler {
+	if m.outboxHandler == nil {
+		m.outboxHandler = outbox.NewHandler(m)
+	}
+	return m.outboxHandler
+}
+
+func (m *RegistryDefault) OutboxDispatcher() *outbox.Dispatcher {
+	if m.outboxDispatcher == nil {
+		m.outboxDispatcher = outbox.NewDispatcher(m)
+	}
+	return m.outboxDispatcher
+}
+
+func (m *RegistryDefault) AuditHandler() *audit.Handler {
+	if m.auditHandler == nil {
+		m.auditHandler = audit.NewHandler(m)
+	}
+	return m.auditHandler
+}
+
+func (m *RegistryDefault) AuditRecorder() *audit.Recorder {
+	if m.auditRecorder == nil {
+		m.auditRecorder = audit.NewRecorder(m)
+	}
+	return m.auditRecorder
+}
+
 func (m *RegistryDefault) SchemaHandler() *schema.Handler {
 	if m.schemaHandler == nil {
 		m.schemaHandler = schema.NewHandler(m)
@@ -440,6 +509,8 @@ func (m *RegistryDefault) Cipher(ctx context.Context) cipher.Cipher {
 			m.crypter = cipher.NewCryptChaCha20(m)
 		case "aes":
 			m.crypter = cipher.NewCryptAES(m)
+		case "envelope":
+			m.crypter = cipher.NewEnvelope(m)
 		default:
 			m.crypter = cipher.NewNoop(m)
 			m.l.Logger.Warning("No encryption configuration found. Default algorithm (noop) will be use that mean sensitive data will be recorded in plaintext")
@@ -535,6 +606,13 @@ func (m *RegistryDefault) SessionManager() session.Manager {
 	return m.sessionManager
 }
 
+func (m *RegistryDefault) SessionTokenizer() *session.Tokenizer {
+	if m.sessionTokenizer == nil {
+		m.sessionTokenizer = session.NewTokenizer(m)
+	}
+	return m.sessionTokenizer
+}
+
 func (m *RegistryDefault) Hydra() hydra.Hydra {
 	if m.hydra == nil {
 		m.hydra = hydra.NewDefaultHydra(m)
@@ -654,6 +732,14 @@ func (m *RegistryDefault) Init(ctx context.Context, ctxer contextx.Contextualize
 		return err
 	}
 
+	if c := m.Config().SessionCache(ctx); c.Enabled && m.sessionCache == nil {
+		cache, err := newSessionCache(c)
+		if err != nil {
+			return err
+		}
+		m.sessionCache = cache
+	}
+
 	if o.inspect != nil {
 		if err := o.inspect(m); err != nil {
 			return errors.WithStack(err)
@@ -710,9 +796,42 @@ func (m *RegistryDefault) SelfServiceErrorPersister() errorx.Persister {
 }
 
 func (m *RegistryDefault) SessionPersister() session.Persister {
+	if m.sessionCache != nil {
+		return session.NewCachingPersister(m, m.persister, m.sessionCache)
+	}
 	return m.persister
 }
 
+func newSessionCache(c *config.SessionCache) (session.Cache, error) {
+	switch c.Backend {
+	case "redis":
+		return session.NewRedisCacheFromURL(c.RedisURL)
+	case "memory":
+		return session.NewMemoryCache(c.MemoryMaxEntries)
+	}
+	return nil, errors.Errorf("unknown session cache backend: %s", c.Backend)
+}
+
+// SessionCache returns the session cache, or nil if sessions are not cached.
+func (m *RegistryDefault) SessionCache() session.Cache {
+	return m.sessionCache
+}
+
+func (m *RegistryDefault) WithSessionCache(c session.Cache) {
+	m.sessionCache = c
+}
+
+func (m *RegistryDefault) IdentityCacheInvalidator() identity.CacheInvalidator {
+	if m.sessionCache != nil {
+		return session.NewCachingPersister(m, m.persister, m.sessionCache)
+	}
+	return identity.NoopCacheInvalidator{}
+}
+
+func (m *RegistryDefault) IdentitySessionRevoker() identity.SessionRevoker {
+	return m.SessionPersister()
+}
+
 func (m *RegistryDefault) CourierPersister() courier.Persister {
 	return m.persister
 }
@@ -733,6 +852,68 @@ func (m *RegistryDefault) VerificationCodePersister() code.VerificationCodePersi
 	return m.Persister()
 }
 
+func (m *RegistryDefault) LoginCodePersister() code.LoginCodePersister {
+	return m.Persister()
+}
+
+func (m *RegistryDefault) RegistrationCodePersister() code.RegistrationCodePersister {
+	return m.Persister()
+}
+
+func (m *RegistryDefault) LoginThrottlePersister() bruteforce.Persister {
+	return m.Persister()
+}
+
+func (m *RegistryDefault) SAMLAssertionPersister() saml.AssertionPersister {
+	return m.Persister()
+}
+
+func (m *RegistryDefault) OrganizationPersister() organization.Persister {
+	return m.Persister()
+}
+
+func (m *RegistryDefault) OutboxPersister() outbox.Persister {
+	return m.Persister()
+}
+
+func (m *RegistryDefault) AuditPersister() audit.Persister {
+	return m.Persister()
+}
+
+func (m *RegistryDefault) IdentitySchemaPersister() schema.Persister {
+	return m.Persister()
+}
+
+func (m *RegistryDefault) IdentitySchemaMigrationPersister() identity.SchemaMigrationPersister {
+	return m.Persister()
+}
+
+func (m *RegistryDefault) LoginThrottler() *bruteforce.Throttler {
+	if m.loginThrottler == nil {
+		m.loginThrottler = bruteforce.NewThrottler(m)
+	}
+	return m.loginThrottler
+}
+
+func (m *RegistryDefault) WithLoginRiskEvaluators(evaluators ...risk.Evaluator) {
+	m.injectedRiskEvaluators = evaluators
+}
+
+func (m *RegistryDefault) LoginRiskEvaluators(ctx context.Context) []risk.Evaluator {
+	return append([]risk.Evaluator{
+		risk.NewDeviceEvaluator(m),
+		risk.NewFailedAttemptsEvaluator(m),
+		risk.NewWebhookEvaluator(m),
+	}, m.injectedRiskEvaluators...)
+}
+
+func (m *RegistryDefault) LoginRiskAssessor() *risk.Assessor {
+	if m.loginRiskAssessor == nil {
+		m.loginRiskAssessor = risk.NewAssessor(m)
+	}
+	return m.loginRiskAssessor
+}
+
 func (m *RegistryDefault) Persister() persistence.Persister {
 	return m.persister
 }
@@ -759,6 +940,34 @@ func (m *RegistryDefault) IdentityManager() *identity.Manager {
 	return m.identityManager
 }
 
+func (m *RegistryDefault) IdentityCipherRotator() *identity.CipherRotator {
+	if m.identityCipherRotator == nil {
+		m.identityCipherRotator = identity.NewCipherRotator(m)
+	}
+	return m.identityCipherRotator
+}
+
+func (m *RegistryDefault) IdentityExporter() *identity.Exporter {
+	if m.identityExporter == nil {
+		m.identityExporter = identity.NewExporter(m)
+	}
+	return m.identityExporter
+}
+
+func (m *RegistryDefault) IdentitySchemaMigrator() *identity.SchemaMigrator {
+	if m.identitySchemaMigrator == nil {
+		m.identitySchemaMigrator = identity.NewSchemaMigrator(m)
+	}
+	return m.identitySchemaMigrator
+}
+
+func (m *RegistryDefault) IdentityStateExpirer() *identity.StateExpirer {
+	if m.identityStateExpirer == nil {
+		m.identityStateExpirer = identity.NewStateExpirer(m)
+	}
+	return m.identityStateExpirer
+}
+
 func (m *RegistryDefault) PrometheusManager() *prometheus.MetricsManager {
 	m.rwl.Lock()
 	defer m.rwl.Unlock()
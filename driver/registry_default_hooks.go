@@ -43,6 +43,13 @@ func (m *RegistryDefault) HookShowVerificationUI() *hook.ShowVerificationUIHook
 	return m.hookShowVerificationUI
 }
 
+func (m *RegistryDefault) HookSecurityNotifier() *hook.SecurityNotifier {
+	if m.hookSecurityNotifier == nil {
+		m.hookSecurityNotifier = hook.NewSecurityNotifier(m)
+	}
+	return m.hookSecurityNotifier
+}
+
 func (m *RegistryDefault) WithHooks(hooks map[string]func(config.SelfServiceHook) interface{}) {
 	m.injectedSelfserviceHooks = hooks
 }
@@ -60,6 +67,8 @@ func (m *RegistryDefault) getHooks(credentialsType string, configs []config.Self
 			i = append(i, m.HookAddressVerifier())
 		case hook.KeyVerificationUI:
 			i = append(i, m.HookShowVerificationUI())
+		case hook.KeySecurityNotifier:
+			i = append(i, m.HookSecurityNotifier())
 		default:
 			var found bool
 			for name, m := range m.injectedSelfserviceHooks {
@@ -9,10 +9,61 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/ory/x/sqlcon"
+
 	"github.com/ory/kratos/schema"
 )
 
 func (m *RegistryDefault) IdentityTraitsSchemas(ctx context.Context) (schema.Schemas, error) {
+	ss, err := m.configuredIdentityTraitsSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The schemas managed through the admin API are only available once the database is connected.
+	if m.persister == nil {
+		return ss, nil
+	}
+
+	vs, err := m.IdentitySchemaPersister().ListIdentitySchemaVersions(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for k := range vs {
+		s, err := vs[k].ToSchema()
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, *s)
+	}
+
+	return ss, nil
+}
+
+// IdentityTraitsSchema returns the identity schema with the given ID, or the default schema if the ID is empty.
+// Unlike IdentityTraitsSchemas it does not list all schema versions managed through the admin API, but loads the
+// version by its ID.
+func (m *RegistryDefault) IdentityTraitsSchema(ctx context.Context, id string) (*schema.Schema, error) {
+	ss, err := m.configuredIdentityTraitsSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := ss.GetByID(id)
+	if err == nil || id == "" || m.persister == nil {
+		return s, err
+	}
+
+	v, verr := m.IdentitySchemaPersister().GetIdentitySchemaVersion(ctx, id)
+	if errors.Is(verr, sqlcon.ErrNoRows) {
+		return nil, err
+	} else if verr != nil {
+		return nil, verr
+	}
+	return v.ToSchema()
+}
+
+func (m *RegistryDefault) configuredIdentityTraitsSchemas(ctx context.Context) (schema.Schemas, error) {
 	ms, err := m.Config().IdentityTraitsSchemas(ctx)
 	if err != nil {
 		return nil, err
@@ -31,6 +82,5 @@ func (m *RegistryDefault) IdentityTraitsSchemas(ctx context.Context) (schema.Sch
 			RawURL: s.URL,
 		})
 	}
-
 	return ss, nil
 }
@@ -11,6 +11,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/ory/herodot"
+
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/internal"
 	"github.com/ory/kratos/schema"
@@ -43,3 +45,33 @@ func TestRegistryDefault_IdentityTraitsSchemas(t *testing.T) {
 	assert.Contains(t, ss, defaultSchema)
 	assert.Contains(t, ss, altSchema)
 }
+
+func TestRegistryDefault_IdentityTraitsSchema(t *testing.T) {
+	ctx := context.Background()
+
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	conf.MustSet(ctx, config.ViperKeyIdentitySchemas, []config.Schema{
+		{ID: "default", URL: "file://default.schema.json"},
+		{ID: "alt", URL: "file://other.schema.json"},
+	})
+
+	v := &schema.Version{Name: "customer", Schema: []byte(`{"type":"object","properties":{"traits":{"type":"object"}}}`)}
+	require.NoError(t, reg.IdentitySchemaPersister().CreateIdentitySchemaVersion(ctx, v))
+
+	s, err := reg.IdentityTraitsSchema(ctx, "alt")
+	require.NoError(t, err)
+	assert.Equal(t, "file://other.schema.json", s.RawURL)
+
+	s, err = reg.IdentityTraitsSchema(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, "default", s.ID)
+
+	s, err = reg.IdentityTraitsSchema(ctx, v.SchemaID)
+	require.NoError(t, err)
+	expected, err := v.ToSchema()
+	require.NoError(t, err)
+	assert.Equal(t, expected, s)
+
+	_, err = reg.IdentityTraitsSchema(ctx, "customer@v2")
+	require.ErrorIs(t, err, herodot.ErrBadRequest)
+}
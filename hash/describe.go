@@ -0,0 +1,92 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package hash
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Describe returns the algorithm of the given hash and the parameters it was generated with, for example `bcrypt`
+// and `cost=12`, or `argon2id` and `m=131072,t=1,p=4`. Hashes which do not have any parameters besides the salt have
+// empty parameters. The algorithm of hashes in an unknown format is `unknown`.
+func Describe(hash []byte) (algorithm, parameters string) {
+	switch {
+	case IsBcryptHash(hash):
+		cost, err := bcrypt.Cost(hash)
+		if err != nil {
+			return "bcrypt", ""
+		}
+		return "bcrypt", fmt.Sprintf("cost=%d", cost)
+	case IsArgon2idHash(hash), IsArgon2iHash(hash):
+		algorithm = "argon2id"
+		if IsArgon2iHash(hash) {
+			algorithm = "argon2i"
+		}
+		p, _, _, err := decodeArgon2idHash(string(hash))
+		if err != nil {
+			return algorithm, ""
+		}
+		return algorithm, fmt.Sprintf("m=%d,t=%d,p=%d", uint32(p.Memory), p.Iterations, p.Parallelism)
+	case IsPbkdf2Hash(hash):
+		p, _, _, err := decodePbkdf2Hash(string(hash))
+		if err != nil {
+			return "pbkdf2", ""
+		}
+		return "pbkdf2-" + p.Algorithm, fmt.Sprintf("i=%d,l=%d", p.Iterations, p.KeyLength)
+	case IsScryptHash(hash):
+		p, _, _, err := decodeScryptHash(string(hash))
+		if err != nil {
+			return "scrypt", ""
+		}
+		return "scrypt", fmt.Sprintf("ln=%d,r=%d,p=%d", p.Cost, p.Block, p.Parrellization)
+	case IsFirebaseScryptHash(hash):
+		if _, _, _, _, _, err := decodeFirebaseScryptHash(string(hash)); err != nil {
+			return "firescrypt", ""
+		}
+		return "firescrypt", strings.Split(string(hash), "$")[2]
+	case IsSSHAHash(hash):
+		return strings.ToLower(strings.Trim(strings.SplitN(string(hash), "}", 2)[0], "{")), ""
+	case IsSHAHash(hash):
+		return strings.Split(string(hash), "$")[1], ""
+	case IsMD5CryptHash(hash):
+		return "md5-crypt", ""
+	case IsSHA256CryptHash(hash), IsSHA512CryptHash(hash):
+		parts := strings.Split(string(hash), "$")
+		if len(parts) > 3 && strings.HasPrefix(parts[2], "rounds=") {
+			return parts[1], parts[2]
+		}
+		return parts[1], ""
+	case IsMD5Hash(hash):
+		return "md5", ""
+	case IsDjangoPbkdf2Hash(hash):
+		p, _, _, err := decodeDjangoPbkdf2Hash(string(hash))
+		if err != nil {
+			return "django-pbkdf2", ""
+		}
+		return "django-pbkdf2-" + p.Algorithm, fmt.Sprintf("i=%d", p.Iterations)
+	case IsDjangoArgon2Hash(hash):
+		algorithm, parameters = Describe(bytes.TrimPrefix(hash, []byte("argon2")))
+		return "django-" + algorithm, parameters
+	case IsPHPassHash(hash):
+		count, _, err := decodePHPassHash(string(hash))
+		if err != nil {
+			return "phpass", ""
+		}
+		return "phpass", fmt.Sprintf("i=%d", count)
+	case IsKeycloakPbkdf2Hash(hash):
+		p, _, _, err := decodeKeycloakPbkdf2Hash(hash)
+		if err != nil {
+			return "keycloak-pbkdf2", ""
+		}
+		return "keycloak-pbkdf2-" + p.Algorithm, fmt.Sprintf("i=%d,l=%d", p.Iterations, p.KeyLength)
+	case IsHMACHash(hash):
+		return strings.Split(string(hash), "$")[1], ""
+	default:
+		return "unknown", ""
+	}
+}
@@ -8,13 +8,16 @@ import (
 	"context"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/md5"  //#nosec G501 -- compatibility for imported passwords
 	"crypto/sha1" //#nosec G505 -- compatibility for imported passwords
 	"crypto/sha256"
 	"crypto/sha512"
 	"crypto/subtle"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"hash"
 	"regexp"
 	"strings"
 
@@ -77,6 +80,16 @@ func Compare(ctx context.Context, password []byte, hash []byte) error {
 		return CompareFirebaseScrypt(ctx, password, hash)
 	case IsMD5Hash(hash):
 		return CompareMD5(ctx, password, hash)
+	case IsDjangoPbkdf2Hash(hash):
+		return CompareDjangoPbkdf2(ctx, password, hash)
+	case IsDjangoArgon2Hash(hash):
+		return CompareDjangoArgon2(ctx, password, hash)
+	case IsPHPassHash(hash):
+		return ComparePHPass(ctx, password, hash)
+	case IsKeycloakPbkdf2Hash(hash):
+		return CompareKeycloakPbkdf2(ctx, password, hash)
+	case IsHMACHash(hash):
+		return CompareHMAC(ctx, password, hash)
 	default:
 		return errors.WithStack(ErrUnknownHashAlgorithm)
 	}
@@ -252,6 +265,76 @@ func CompareMD5(_ context.Context, password []byte, hash []byte) error {
 	return comparePasswordHashConstantTime(hash, otherHash[:])
 }
 
+func CompareDjangoPbkdf2(_ context.Context, password []byte, hash []byte) error {
+	// Extract the parameters, salt and derived key from the encoded password
+	// hash.
+	p, salt, hash, err := decodeDjangoPbkdf2Hash(string(hash))
+	if err != nil {
+		return err
+	}
+
+	// Derive the key from the other password using the same parameters.
+	otherHash := pbkdf2.Key(password, salt, int(p.Iterations), int(p.KeyLength), getPseudorandomFunctionForPbkdf2(p.Algorithm))
+
+	return comparePasswordHashConstantTime(hash, otherHash)
+}
+
+func CompareDjangoArgon2(ctx context.Context, password []byte, hash []byte) error {
+	// Django prefixes the argon2 hash with the name of its password hasher,
+	// the remainder is a regular argon2 encoded hash.
+	hash = bytes.TrimPrefix(hash, []byte("argon2"))
+
+	if IsArgon2iHash(hash) {
+		return CompareArgon2i(ctx, password, hash)
+	}
+	return CompareArgon2id(ctx, password, hash)
+}
+
+func ComparePHPass(_ context.Context, password []byte, hash []byte) error {
+	// Extract the iteration count and salt from the encoded password hash.
+	count, salt, err := decodePHPassHash(string(hash))
+	if err != nil {
+		return err
+	}
+
+	//#nosec G401 -- compatibility for imported passwords
+	sum := md5.Sum(append(salt, password...))
+	for i := 0; i < count; i++ {
+		//#nosec G401 -- compatibility for imported passwords
+		sum = md5.Sum(append(sum[:], password...))
+	}
+
+	otherHash := append(append([]byte{}, hash[:12]...), encodePHPassBase64(sum[:])...)
+
+	return comparePasswordHashConstantTime(hash, otherHash)
+}
+
+func CompareKeycloakPbkdf2(_ context.Context, password []byte, hash []byte) error {
+	// Extract the parameters, salt and derived key from the credential JSON.
+	p, salt, hash, err := decodeKeycloakPbkdf2Hash(hash)
+	if err != nil {
+		return err
+	}
+
+	// Derive the key from the other password using the same parameters.
+	otherHash := pbkdf2.Key(password, salt, int(p.Iterations), int(p.KeyLength), getPseudorandomFunctionForPbkdf2(p.Algorithm))
+
+	return comparePasswordHashConstantTime(hash, otherHash)
+}
+
+func CompareHMAC(_ context.Context, password []byte, hash []byte) error {
+	// Extract the hash function, key and hash from the encoded password hash.
+	hasher, hash, key, err := decodeHMACHash(string(hash))
+	if err != nil {
+		return err
+	}
+
+	mac := hmac.New(hasher, key)
+	_, _ = mac.Write(password)
+
+	return comparePasswordHashConstantTime(hash, mac.Sum(nil))
+}
+
 var (
 	isMD5CryptHash       = regexp.MustCompile(`^\$md5-crypt\$`)
 	isBcryptHash         = regexp.MustCompile(`^\$2[abzy]?\$`)
@@ -265,6 +348,11 @@ var (
 	isSHAHash            = regexp.MustCompile(`^\$sha(1|256|512)\$`)
 	isFirebaseScryptHash = regexp.MustCompile(`^\$firescrypt\$`)
 	isMD5Hash            = regexp.MustCompile(`^\$md5\$`)
+	isDjangoPbkdf2Hash   = regexp.MustCompile(`^pbkdf2_sha(1|256)\$`)
+	isDjangoArgon2Hash   = regexp.MustCompile(`^argon2\$argon2id?\$`)
+	isPHPassHash         = regexp.MustCompile(`^\$[PH]\$`)
+	isKeycloakPbkdf2Hash = regexp.MustCompile(`(?s)^\s*{.*"credentialData"\s*:`)
+	isHMACHash           = regexp.MustCompile(`^\$hmac-sha(1|256|512)\$`)
 )
 
 func IsMD5CryptHash(hash []byte) bool       { return isMD5CryptHash.Match(hash) }
@@ -279,6 +367,11 @@ func IsSSHAHash(hash []byte) bool           { return isSSHAHash.Match(hash) }
 func IsSHAHash(hash []byte) bool            { return isSHAHash.Match(hash) }
 func IsFirebaseScryptHash(hash []byte) bool { return isFirebaseScryptHash.Match(hash) }
 func IsMD5Hash(hash []byte) bool            { return isMD5Hash.Match(hash) }
+func IsDjangoPbkdf2Hash(hash []byte) bool   { return isDjangoPbkdf2Hash.Match(hash) }
+func IsDjangoArgon2Hash(hash []byte) bool   { return isDjangoArgon2Hash.Match(hash) }
+func IsPHPassHash(hash []byte) bool         { return isPHPassHash.Match(hash) }
+func IsKeycloakPbkdf2Hash(hash []byte) bool { return isKeycloakPbkdf2Hash.Match(hash) }
+func IsHMACHash(hash []byte) bool           { return isHMACHash.Match(hash) }
 
 func IsValidHashFormat(hash []byte) bool {
 	if IsMD5CryptHash(hash) ||
@@ -292,7 +385,12 @@ func IsValidHashFormat(hash []byte) bool {
 		IsSSHAHash(hash) ||
 		IsSHAHash(hash) ||
 		IsFirebaseScryptHash(hash) ||
-		IsMD5Hash(hash) {
+		IsMD5Hash(hash) ||
+		IsDjangoPbkdf2Hash(hash) ||
+		IsDjangoArgon2Hash(hash) ||
+		IsPHPassHash(hash) ||
+		IsKeycloakPbkdf2Hash(hash) ||
+		IsHMACHash(hash) {
 		return true
 	} else {
 		return false
@@ -596,6 +694,208 @@ func decodeMD5Hash(encodedHash string) (pf, salt, hash []byte, err error) {
 	}
 }
 
+// decodeDjangoPbkdf2Hash decodes Django's PBKDF2 encoded password hash.
+// format: pbkdf2_<digest>$<iterations>$<salt>$<hash>
+func decodeDjangoPbkdf2Hash(encodedHash string) (p *Pbkdf2, salt, hash []byte, err error) {
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 4 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	p = new(Pbkdf2)
+	p.Algorithm = strings.TrimPrefix(parts[0], "pbkdf2_")
+
+	_, err = fmt.Sscanf(parts[1], "%d", &p.Iterations)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if p.Iterations == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	// Django uses the salt as is, it is not encoded.
+	salt = []byte(parts[2])
+	if len(salt) == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	p.SaltLength = uint32(len(salt))
+
+	hash, err = base64.StdEncoding.Strict().DecodeString(parts[3])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(hash) == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	p.KeyLength = uint32(len(hash))
+
+	return p, salt, hash, nil
+}
+
+const phpassItoa64 = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// decodePHPassHash decodes the portable PHPass password hash used by WordPress and Drupal.
+// format: $P$<log2 of iterations><salt><hash> (the $H$ prefix is used by phpBB)
+func decodePHPassHash(encodedHash string) (count int, salt []byte, err error) {
+	if len(encodedHash) != 34 {
+		return 0, nil, ErrInvalidHash
+	}
+
+	countLog2 := strings.IndexByte(phpassItoa64, encodedHash[3])
+	if countLog2 < 7 || countLog2 > 30 {
+		return 0, nil, ErrInvalidHash
+	}
+
+	return 1 << countLog2, []byte(encodedHash[4:12]), nil
+}
+
+// encodePHPassBase64 encodes the input using PHPass' own base64 variant.
+func encodePHPassBase64(input []byte) []byte {
+	var output []byte
+	for i := 0; i < len(input); {
+		value := uint(input[i])
+		i++
+		output = append(output, phpassItoa64[value&0x3f])
+		if i < len(input) {
+			value |= uint(input[i]) << 8
+		}
+		output = append(output, phpassItoa64[(value>>6)&0x3f])
+		if i >= len(input) {
+			break
+		}
+		i++
+		if i < len(input) {
+			value |= uint(input[i]) << 16
+		}
+		output = append(output, phpassItoa64[(value>>12)&0x3f])
+		if i >= len(input) {
+			break
+		}
+		i++
+		output = append(output, phpassItoa64[(value>>18)&0x3f])
+	}
+	return output
+}
+
+type keycloakCredential struct {
+	SecretData     json.RawMessage `json:"secretData"`
+	CredentialData json.RawMessage `json:"credentialData"`
+}
+
+type keycloakSecretData struct {
+	Value string `json:"value"`
+	Salt  string `json:"salt"`
+}
+
+type keycloakCredentialData struct {
+	HashIterations uint32 `json:"hashIterations"`
+	Algorithm      string `json:"algorithm"`
+}
+
+// decodeKeycloakPbkdf2Hash decodes a Keycloak PBKDF2 password credential as found in realm exports.
+// format: {"secretData":{"value":"<hash>","salt":"<salt>"},"credentialData":{"hashIterations":<iterations>,"algorithm":"pbkdf2-<digest>"}}
+// Keycloak exports secretData and credentialData as JSON encoded strings, both representations are accepted.
+func decodeKeycloakPbkdf2Hash(encodedHash []byte) (p *Pbkdf2, salt, hash []byte, err error) {
+	var credential keycloakCredential
+	if err := json.Unmarshal(encodedHash, &credential); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	var secret keycloakSecretData
+	if err := unmarshalKeycloakData(credential.SecretData, &secret); err != nil {
+		return nil, nil, nil, err
+	}
+
+	var data keycloakCredentialData
+	if err := unmarshalKeycloakData(credential.CredentialData, &data); err != nil {
+		return nil, nil, nil, err
+	}
+
+	p = new(Pbkdf2)
+	switch data.Algorithm {
+	case "pbkdf2":
+		p.Algorithm = "sha1"
+	case "pbkdf2-sha256":
+		p.Algorithm = "sha256"
+	case "pbkdf2-sha512":
+		p.Algorithm = "sha512"
+	default:
+		return nil, nil, nil, ErrInvalidHash
+	}
+	p.Iterations = data.HashIterations
+	if p.Iterations == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	salt, err = base64.StdEncoding.Strict().DecodeString(secret.Salt)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(salt) == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	p.SaltLength = uint32(len(salt))
+
+	hash, err = base64.StdEncoding.Strict().DecodeString(secret.Value)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(hash) == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	p.KeyLength = uint32(len(hash))
+
+	return p, salt, hash, nil
+}
+
+func unmarshalKeycloakData(raw json.RawMessage, v interface{}) error {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '"' {
+		var encoded string
+		if err := json.Unmarshal(raw, &encoded); err != nil {
+			return ErrInvalidHash
+		}
+		raw = []byte(encoded)
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return ErrInvalidHash
+	}
+	return nil
+}
+
+// decodeHMACHash decodes HMAC-SHA[1|256|512] encoded password hash.
+// format: $hmac-<digest>$<hash>$<key>
+func decodeHMACHash(encodedHash string) (hasher func() hash.Hash, hash, key []byte, err error) {
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 4 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	switch strings.TrimPrefix(parts[1], "hmac-") {
+	case "sha1":
+		hasher = sha1.New
+	case "sha256":
+		hasher = sha256.New
+	case "sha512":
+		hasher = sha512.New
+	default:
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	hash, err = base64.StdEncoding.Strict().DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	key, err = base64.StdEncoding.Strict().DecodeString(parts[3])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return hasher, hash, key, nil
+}
+
 func comparePasswordHashConstantTime(hash, otherHash []byte) error {
 	// use subtle.ConstantTimeCompare() to prevent timing attacks.
 	if subtle.ConstantTimeCompare(hash, otherHash) == 1 {
@@ -78,3 +78,18 @@ func (h *Argon2) Generate(ctx context.Context, password []byte) ([]byte, error)
 func (h *Argon2) Understands(hash []byte) bool {
 	return IsArgon2idHash(hash)
 }
+
+func (h *Argon2) NeedsRehash(ctx context.Context, hash []byte) bool {
+	p, _, _, err := decodeArgon2idHash(string(hash))
+	if err != nil {
+		return true
+	}
+
+	// The memory of decoded hashes is in KB.
+	c := h.c.Config().HasherArgon2(ctx)
+	return uint32(p.Memory) < toKB(c.Memory) ||
+		p.Iterations < c.Iterations ||
+		p.Parallelism < c.Parallelism ||
+		p.SaltLength < c.SaltLength ||
+		p.KeyLength < c.KeyLength
+}
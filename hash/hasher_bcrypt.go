@@ -67,3 +67,11 @@ func validateBcryptPasswordLength(password []byte) error {
 func (h *Bcrypt) Understands(hash []byte) bool {
 	return IsBcryptHash(hash)
 }
+
+func (h *Bcrypt) NeedsRehash(ctx context.Context, hash []byte) bool {
+	cost, err := bcrypt.Cost(hash)
+	if err != nil {
+		return true
+	}
+	return uint32(cost) < h.c.Config().HasherBcrypt(ctx).Cost
+}
@@ -61,6 +61,17 @@ func (h *Pbkdf2) Understands(hash []byte) bool {
 	return IsPbkdf2Hash(hash)
 }
 
+func (h *Pbkdf2) NeedsRehash(_ context.Context, hash []byte) bool {
+	p, _, _, err := decodePbkdf2Hash(string(hash))
+	if err != nil {
+		return true
+	}
+	return p.Algorithm != h.Algorithm ||
+		p.Iterations < h.Iterations ||
+		p.SaltLength < h.SaltLength ||
+		p.KeyLength < h.KeyLength
+}
+
 func getPseudorandomFunctionForPbkdf2(alg string) func() hash.Hash {
 	switch alg {
 	case "sha1":
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/hash"
 	"github.com/ory/kratos/internal"
 )
@@ -200,6 +201,96 @@ func TestPbkdf2Hasher(t *testing.T) {
 	}
 }
 
+func TestNeedsRehash(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("hasher=bcrypt", func(t *testing.T) {
+		t.Parallel()
+		conf, reg := internal.NewFastRegistryWithMocks(t)
+		conf.MustSet(ctx, config.ViperKeyHasherBcryptCost, 4)
+		h := hash.NewHasherBcrypt(reg)
+
+		hs, err := h.Generate(ctx, []byte("test"))
+		require.NoError(t, err)
+		assert.False(t, h.NeedsRehash(ctx, hs))
+
+		conf.MustSet(ctx, config.ViperKeyHasherBcryptCost, 5)
+		assert.True(t, h.NeedsRehash(ctx, hs))
+
+		conf.MustSet(ctx, config.ViperKeyHasherBcryptCost, 3)
+		assert.False(t, h.NeedsRehash(ctx, hs), "lowering the cost does not rehash")
+	})
+
+	t.Run("hasher=argon2", func(t *testing.T) {
+		t.Parallel()
+		conf, reg := internal.NewFastRegistryWithMocks(t)
+		conf.MustSet(ctx, config.ViperKeyHasherArgon2ConfigMemory, "16KB")
+		conf.MustSet(ctx, config.ViperKeyHasherArgon2ConfigIterations, 1)
+		h := hash.NewHasherArgon2(reg)
+
+		hs, err := h.Generate(ctx, []byte("test"))
+		require.NoError(t, err)
+		assert.False(t, h.NeedsRehash(ctx, hs))
+
+		for key, value := range map[string]interface{}{
+			config.ViperKeyHasherArgon2ConfigMemory:      "32KB",
+			config.ViperKeyHasherArgon2ConfigIterations:  2,
+			config.ViperKeyHasherArgon2ConfigParallelism: 64,
+			config.ViperKeyHasherArgon2ConfigSaltLength:  64,
+			config.ViperKeyHasherArgon2ConfigKeyLength:   64,
+		} {
+			previous := conf.GetProvider(ctx).Get(key)
+			conf.MustSet(ctx, key, value)
+			assert.True(t, h.NeedsRehash(ctx, hs), "%s=%v", key, value)
+			conf.MustSet(ctx, key, previous)
+		}
+		assert.False(t, h.NeedsRehash(ctx, hs))
+	})
+
+	t.Run("hasher=pbkdf2", func(t *testing.T) {
+		t.Parallel()
+		h := &hash.Pbkdf2{Algorithm: "sha256", Iterations: 1000, SaltLength: 16, KeyLength: 32}
+		hs, err := h.Generate(ctx, []byte("test"))
+		require.NoError(t, err)
+		assert.False(t, h.NeedsRehash(ctx, hs))
+
+		assert.True(t, (&hash.Pbkdf2{Algorithm: "sha256", Iterations: 2000, SaltLength: 16, KeyLength: 32}).NeedsRehash(ctx, hs))
+		assert.True(t, (&hash.Pbkdf2{Algorithm: "sha512", Iterations: 1000, SaltLength: 16, KeyLength: 32}).NeedsRehash(ctx, hs))
+	})
+}
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		hash, algorithm, parameters string
+	}{
+		{hash: "$2a$12$o6hx.Wog/wvFSkT/Bp/6DOxCtLRTDj7lm9on9suF/WaCGNVHbkfL6", algorithm: "bcrypt", parameters: "cost=12"},
+		{hash: "$argon2id$v=19$m=32,t=2,p=4$cm94YnRVOW5jZzFzcVE4bQ$MNzk5BtR2vUhrp6qQEjRNw", algorithm: "argon2id", parameters: "m=32,t=2,p=4"},
+		{hash: "$argon2i$v=19$m=65536,t=3,p=4$kk51rW/vxIVCYn+EG4kTSg$NyT88uraJ6im6dyha/M5jhXvpqlEdlS/9fEm7ScMb8c", algorithm: "argon2i", parameters: "m=65536,t=3,p=4"},
+		{hash: "$pbkdf2-sha256$i=100000,l=32$1jP+5Zxpxgtee/iPxGgOz0RfE9/KJuDElP1ley4VxXc$QJxzfvdbHYBpydCbHoFg3GJEqMFULwskiuqiJctoYpI", algorithm: "pbkdf2-sha256", parameters: "i=100000,l=32"},
+		{hash: "$scrypt$ln=16384,r=8,p=1$2npRo7P03Mt8keSoMbyD/tKFWyUzjiQf2svUaNDSrhA=$MiCzNcIplSMqSBrm4HckjYqYhaVPPjTARTzwB1cVNYE=", algorithm: "scrypt", parameters: "ln=16384,r=8,p=1"},
+		{hash: "$firescrypt$ln=14,r=8,p=1$sPtDhWcd1MfdAw==$xbSou7FOl6mChCyzpCPIQ7tku7nsQMTFtyOZSXXd7tjBa4NtimOx7v42Gv2SfzPQu1oxM2/k4SsbOu73wlKe1A==$Bw==$YE0dO4bwD4JnJafh6lZZfkp1MtKzuKAXQcDCJNJNyeCHairWHKENOkbh3dzwaCdizzOspwr/FITUVlnOAwPKyw==", algorithm: "firescrypt", parameters: "ln=14,r=8,p=1"},
+		{hash: "{SSHA256}czO44OTV17PcF1cRxWrLZLy9xHd7CWyVYplr1rOhuzE=", algorithm: "ssha256"},
+		{hash: "$sha1$pf=e1NBTFR9e1BBU1NXT1JEfQ==$NW9wbWtnejAzcg==$2qU2SGWP8viTM1md3FiI3+rjWXQ=", algorithm: "sha1"},
+		{hash: "$md5$CY9rzUYh03PK3k6DJie09g==", algorithm: "md5"},
+		{hash: "$md5-crypt$TVEiiKNb$SN6/pUaRQS/E8Jh46As2C/", algorithm: "md5-crypt"},
+		{hash: "$sha256-crypt$rounds=535000$05R.9KB6UC2kLI3w$Q/zslzx./JjkAVPTwp6th7nW5l7JU91Gte/UmIh.U78", algorithm: "sha256-crypt", parameters: "rounds=535000"},
+		{hash: "pbkdf2_sha256$1000$saltsalt$PV/NgngXg7GnyIijVakkmRje8KEdSsAPgecsnyDghT4=", algorithm: "django-pbkdf2-sha256", parameters: "i=1000"},
+		{hash: "argon2$argon2id$v=19$m=16,t=2,p=1$bVI1aE1SaTV6SGQ3bzdXdw$fnjCcZYmEPOUOjYXsT92Cg", algorithm: "django-argon2id", parameters: "m=16,t=2,p=1"},
+		{hash: "$P$9IQRaTwmfeRo7ud9Fh4E2PdI0S3r.L0", algorithm: "phpass", parameters: "i=2048"},
+		{hash: `{"secretData":{"value":"QvEGxi97vkYNn6fjg3u+Y5XyQc4=","salt":"MDEyMzQ1Njc4OWFiY2RlZg=="},"credentialData":{"hashIterations":1000,"algorithm":"pbkdf2"}}`, algorithm: "keycloak-pbkdf2-sha1", parameters: "i=1000,l=20"},
+		{hash: "$hmac-sha256$Aq+1YwSQLGVvy3N83QPeYgW7bUAdooEu/ZstNqCK8Vk=$a2V5", algorithm: "hmac-sha256"},
+		{hash: "$unknown$12$o6hx.Wog/wvFSkT/Bp/6DOxCtLRTDj7lm9on9suF/WaCGNVHbkfL6", algorithm: "unknown"},
+	} {
+		t.Run("algorithm="+tc.algorithm, func(t *testing.T) {
+			algorithm, parameters := hash.Describe([]byte(tc.hash))
+			assert.Equal(t, tc.algorithm, algorithm)
+			assert.Equal(t, tc.parameters, parameters)
+		})
+	}
+}
+
 func TestCompare(t *testing.T) {
 	t.Parallel()
 	t.Run("unknown", func(t *testing.T) {
@@ -413,4 +504,85 @@ func TestCompare(t *testing.T) {
 		assert.Error(t, hash.Compare(context.Background(), []byte("ory"), []byte("$sha512-crypt$$")), "shacrypt decode error: provided encoded hash has an invalid format")
 		assert.Error(t, hash.Compare(context.Background(), []byte("ory"), []byte("$sha512-crypt$$$")))
 	})
+
+	t.Run("django-pbkdf2", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, hash.Compare(context.Background(), []byte("test"), []byte("pbkdf2_sha256$1000$saltsalt$PV/NgngXg7GnyIijVakkmRje8KEdSsAPgecsnyDghT4=")))
+		assert.Nil(t, hash.CompareDjangoPbkdf2(context.Background(), []byte("test"), []byte("pbkdf2_sha256$1000$saltsalt$PV/NgngXg7GnyIijVakkmRje8KEdSsAPgecsnyDghT4=")))
+		assert.Error(t, hash.Compare(context.Background(), []byte("ory"), []byte("pbkdf2_sha256$1000$saltsalt$PV/NgngXg7GnyIijVakkmRje8KEdSsAPgecsnyDghT4=")))
+		assert.Error(t, hash.Compare(context.Background(), []byte("test"), []byte("pbkdf2_sha256$1001$saltsalt$PV/NgngXg7GnyIijVakkmRje8KEdSsAPgecsnyDghT4=")))
+
+		assert.Nil(t, hash.Compare(context.Background(), []byte("test"), []byte("pbkdf2_sha1$1000$saltsalt$oq8zYNlDWbuqqKQZF4YOSjn5P8k=")))
+		assert.Error(t, hash.Compare(context.Background(), []byte("ory"), []byte("pbkdf2_sha1$1000$saltsalt$oq8zYNlDWbuqqKQZF4YOSjn5P8k=")))
+
+		assert.ErrorIs(t, hash.Compare(context.Background(), []byte("test"), []byte("pbkdf2_sha256$1000$saltsalt")), hash.ErrInvalidHash)
+		assert.Error(t, hash.Compare(context.Background(), []byte("test"), []byte("pbkdf2_sha256$i$saltsalt$PV/NgngXg7GnyIijVakkmRje8KEdSsAPgecsnyDghT4=")))
+		assert.ErrorIs(t, hash.Compare(context.Background(), []byte("test"), []byte("pbkdf2_sha256$1000$saltsalt$")), hash.ErrInvalidHash)
+		assert.ErrorIs(t, hash.Compare(context.Background(), []byte("test"), []byte("pbkdf2_sha256$1000$$PV/NgngXg7GnyIijVakkmRje8KEdSsAPgecsnyDghT4=")), hash.ErrInvalidHash)
+		assert.ErrorIs(t, hash.Compare(context.Background(), []byte("test"), []byte("pbkdf2_sha256$0$saltsalt$PV/NgngXg7GnyIijVakkmRje8KEdSsAPgecsnyDghT4=")), hash.ErrInvalidHash)
+	})
+
+	t.Run("django-argon2", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, hash.Compare(context.Background(), []byte("123456"), []byte("argon2$argon2id$v=19$m=16,t=2,p=1$bVI1aE1SaTV6SGQ3bzdXdw$fnjCcZYmEPOUOjYXsT92Cg")))
+		assert.Nil(t, hash.CompareDjangoArgon2(context.Background(), []byte("123456"), []byte("argon2$argon2id$v=19$m=16,t=2,p=1$bVI1aE1SaTV6SGQ3bzdXdw$fnjCcZYmEPOUOjYXsT92Cg")))
+		assert.Error(t, hash.Compare(context.Background(), []byte("test"), []byte("argon2$argon2id$v=19$m=16,t=2,p=1$bVI1aE1SaTV6SGQ3bzdXdw$fnjCcZYmEPOUOjYXsT92Cg")))
+
+		assert.Nil(t, hash.Compare(context.Background(), []byte("test"), []byte("argon2$argon2i$v=19$m=65536,t=3,p=4$kk51rW/vxIVCYn+EG4kTSg$NyT88uraJ6im6dyha/M5jhXvpqlEdlS/9fEm7ScMb8c")))
+		assert.Error(t, hash.Compare(context.Background(), []byte("ory"), []byte("argon2$argon2i$v=19$m=65536,t=3,p=4$kk51rW/vxIVCYn+EG4kTSg$NyT88uraJ6im6dyha/M5jhXvpqlEdlS/9fEm7ScMb8c")))
+	})
+
+	t.Run("phpass", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, hash.Compare(context.Background(), []byte("test12345"), []byte("$P$9IQRaTwmfeRo7ud9Fh4E2PdI0S3r.L0")))
+		assert.Nil(t, hash.ComparePHPass(context.Background(), []byte("test12345"), []byte("$P$9IQRaTwmfeRo7ud9Fh4E2PdI0S3r.L0")))
+		assert.Error(t, hash.Compare(context.Background(), []byte("test12346"), []byte("$P$9IQRaTwmfeRo7ud9Fh4E2PdI0S3r.L0")))
+
+		assert.Nil(t, hash.Compare(context.Background(), []byte("test"), []byte("$H$9abcdefgh0x3dpRWafM0Gr0e5vgdFV1")))
+		assert.Error(t, hash.Compare(context.Background(), []byte("ory"), []byte("$H$9abcdefgh0x3dpRWafM0Gr0e5vgdFV1")))
+
+		assert.ErrorIs(t, hash.Compare(context.Background(), []byte("test"), []byte("$P$9IQRaTwmfeRo7ud9Fh4E2PdI0S3r")), hash.ErrInvalidHash)
+		assert.ErrorIs(t, hash.Compare(context.Background(), []byte("test"), []byte("$P$!IQRaTwmfeRo7ud9Fh4E2PdI0S3r.L0")), hash.ErrInvalidHash)
+	})
+
+	t.Run("keycloak-pbkdf2", func(t *testing.T) {
+		t.Parallel()
+
+		exported := `{"type": "password", "secretData": "{\"value\": \"U9tyXqETdZHkyi9zi6jcDHsHd54rod/5eK1EVcgvPXhuf4dQHn6CAOypy9grYmuTZcMpk5vfptlvEVUDRj4PVQ==\", \"salt\": \"MDEyMzQ1Njc4OWFiY2RlZg==\"}", "credentialData": "{\"hashIterations\": 27500, \"algorithm\": \"pbkdf2-sha256\"}"}`
+		assert.Nil(t, hash.Compare(context.Background(), []byte("test"), []byte(exported)))
+		assert.Nil(t, hash.CompareKeycloakPbkdf2(context.Background(), []byte("test"), []byte(exported)))
+		assert.Error(t, hash.Compare(context.Background(), []byte("ory"), []byte(exported)))
+
+		decoded := `{"secretData":{"value":"QvEGxi97vkYNn6fjg3u+Y5XyQc4=","salt":"MDEyMzQ1Njc4OWFiY2RlZg=="},"credentialData":{"hashIterations":1000,"algorithm":"pbkdf2"}}`
+		assert.Nil(t, hash.Compare(context.Background(), []byte("test"), []byte(decoded)))
+		assert.Error(t, hash.Compare(context.Background(), []byte("ory"), []byte(decoded)))
+
+		assert.ErrorIs(t, hash.Compare(context.Background(), []byte("test"), []byte(`{"secretData":{"value":"QvEGxi97vkYNn6fjg3u+Y5XyQc4=","salt":"MDEyMzQ1Njc4OWFiY2RlZg=="},"credentialData":{"hashIterations":1000,"algorithm":"md5"}}`)), hash.ErrInvalidHash)
+		assert.ErrorIs(t, hash.Compare(context.Background(), []byte("test"), []byte(`{"secretData":"{","credentialData":{}}`)), hash.ErrInvalidHash)
+		assert.ErrorIs(t, hash.Compare(context.Background(), []byte("test"), []byte(`{"secretData":{"value":"","salt":"MDEyMzQ1Njc4OWFiY2RlZg=="},"credentialData":{"hashIterations":1000,"algorithm":"pbkdf2"}}`)), hash.ErrInvalidHash)
+		assert.ErrorIs(t, hash.Compare(context.Background(), []byte("test"), []byte(`{"secretData":{"value":"QvEGxi97vkYNn6fjg3u+Y5XyQc4=","salt":""},"credentialData":{"hashIterations":1000,"algorithm":"pbkdf2"}}`)), hash.ErrInvalidHash)
+		assert.ErrorIs(t, hash.Compare(context.Background(), []byte("test"), []byte(`{"secretData":{"value":"QvEGxi97vkYNn6fjg3u+Y5XyQc4=","salt":"MDEyMzQ1Njc4OWFiY2RlZg=="},"credentialData":{"hashIterations":0,"algorithm":"pbkdf2"}}`)), hash.ErrInvalidHash)
+		assert.ErrorIs(t, hash.Compare(context.Background(), []byte("test"), []byte(`{"secretData":{"value":"QvEGxi97vkYNn6fjg3u+Y5XyQc4=","salt":"MDEyMzQ1Njc4OWFiY2RlZg=="},"credentialData":{"algorithm":"pbkdf2"}}`)), hash.ErrInvalidHash)
+	})
+
+	t.Run("hmac", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, hash.Compare(context.Background(), []byte("test"), []byte("$hmac-sha1$Zx9UzgxUD3j/4eJtz5wqBHrqT9o=$a2V5")))
+		assert.Nil(t, hash.CompareHMAC(context.Background(), []byte("test"), []byte("$hmac-sha1$Zx9UzgxUD3j/4eJtz5wqBHrqT9o=$a2V5")))
+		assert.Error(t, hash.Compare(context.Background(), []byte("ory"), []byte("$hmac-sha1$Zx9UzgxUD3j/4eJtz5wqBHrqT9o=$a2V5")))
+
+		assert.Nil(t, hash.Compare(context.Background(), []byte("test"), []byte("$hmac-sha256$Aq+1YwSQLGVvy3N83QPeYgW7bUAdooEu/ZstNqCK8Vk=$a2V5")))
+		assert.Error(t, hash.Compare(context.Background(), []byte("ory"), []byte("$hmac-sha256$Aq+1YwSQLGVvy3N83QPeYgW7bUAdooEu/ZstNqCK8Vk=$a2V5")))
+		assert.Error(t, hash.Compare(context.Background(), []byte("test"), []byte("$hmac-sha256$Aq+1YwSQLGVvy3N83QPeYgW7bUAdooEu/ZstNqCK8Vk=$a2V6")))
+
+		assert.Nil(t, hash.Compare(context.Background(), []byte("test"), []byte("$hmac-sha512$KHoPuJp/vfpbVThjaRjlN6W4MGXk/zMSaLeqoRXd4EepsPT7W4KGCPwLYyfxAFX3Y3sFjp4Nu55piQGj5t1GHA==$a2V5")))
+		assert.Error(t, hash.Compare(context.Background(), []byte("ory"), []byte("$hmac-sha512$KHoPuJp/vfpbVThjaRjlN6W4MGXk/zMSaLeqoRXd4EepsPT7W4KGCPwLYyfxAFX3Y3sFjp4Nu55piQGj5t1GHA==$a2V5")))
+
+		assert.ErrorIs(t, hash.Compare(context.Background(), []byte("test"), []byte("$hmac-sha256$Aq+1YwSQLGVvy3N83QPeYgW7bUAdooEu/ZstNqCK8Vk=")), hash.ErrInvalidHash)
+		assert.ErrorIs(t, hash.Compare(context.Background(), []byte("test"), []byte("$hmac-sha256$Z$a2V5")), base64.CorruptInputError(0))
+	})
 }
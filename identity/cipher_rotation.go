@@ -0,0 +1,210 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+
+	"github.com/ory/x/otelx"
+
+	"github.com/ory/kratos/cipher"
+	"github.com/ory/kratos/x"
+)
+
+// DefaultCipherRotationBatchSize is the number of credentials loaded at once when rotating the cipher secrets.
+const DefaultCipherRotationBatchSize = 100
+
+// encryptedOIDCTokens are the fields of the OpenID Connect providers in the credentials config which are encrypted
+// with `secrets.cipher`.
+var encryptedOIDCTokens = []string{"initial_id_token", "initial_access_token", "initial_refresh_token"}
+
+type (
+	cipherRotatorDependencies interface {
+		PrivilegedPoolProvider
+		cipher.Provider
+		x.LoggingProvider
+		x.TracingProvider
+	}
+	CipherRotatorProvider interface {
+		IdentityCipherRotator() *CipherRotator
+	}
+	// CipherRotator re-encrypts the ciphertexts stored in identity credentials with the current cipher secret, so
+	// that older secrets can be removed from `secrets.cipher`.
+	CipherRotator struct {
+		r cipherRotatorDependencies
+	}
+
+	CipherRotationOptions struct {
+		// After is the ID of the credentials after which the rotation starts. Set it to the cursor of the last
+		// reported progress to resume an interrupted rotation.
+		After uuid.UUID
+
+		// BatchSize is the number of credentials loaded and updated at once.
+		BatchSize int
+
+		// DryRun only counts the ciphertexts per secret without re-encrypting them.
+		DryRun bool
+
+		// Progress is called after every batch.
+		Progress func(progress *CipherRotationProgress)
+	}
+
+	CipherRotationOption func(*CipherRotationOptions)
+
+	// CipherRotationProgress reports the progress of a cipher rotation.
+	CipherRotationProgress struct {
+		// Cursor is the ID of the last processed credentials.
+		Cursor uuid.UUID `json:"cursor"`
+
+		// Credentials is the number of processed credentials.
+		Credentials int `json:"credentials"`
+
+		// Keys counts the ciphertexts by the index of the secret in `secrets.cipher` they were encrypted with.
+		Keys map[int]int `json:"keys"`
+
+		// Rotated is the number of ciphertexts which were re-encrypted with the current secret, or which would have
+		// been re-encrypted in a dry run.
+		Rotated int `json:"rotated"`
+
+		// Undecryptable is the number of ciphertexts which none of the secrets could decrypt.
+		Undecryptable int `json:"undecryptable"`
+
+		// Skipped is the number of credentials which were changed during the rotation and were therefore not
+		// updated. It is only known once the rotation is done. Run the rotation again to re-encrypt them.
+		Skipped int `json:"skipped"`
+	}
+)
+
+func NewCipherRotator(r cipherRotatorDependencies) *CipherRotator {
+	return &CipherRotator{r: r}
+}
+
+// CipherRotationAfter resumes the rotation after the credentials with the given ID.
+func CipherRotationAfter(id uuid.UUID) CipherRotationOption {
+	return func(o *CipherRotationOptions) {
+		o.After = id
+	}
+}
+
+// CipherRotationBatchSize sets the number of credentials loaded and updated at once.
+func CipherRotationBatchSize(size int) CipherRotationOption {
+	return func(o *CipherRotationOptions) {
+		o.BatchSize = size
+	}
+}
+
+// CipherRotationDryRun only counts the ciphertexts per secret.
+func CipherRotationDryRun(dryRun bool) CipherRotationOption {
+	return func(o *CipherRotationOptions) {
+		o.DryRun = dryRun
+	}
+}
+
+// CipherRotationWithProgress calls the given function after every batch.
+func CipherRotationWithProgress(f func(progress *CipherRotationProgress)) CipherRotationOption {
+	return func(o *CipherRotationOptions) {
+		o.Progress = f
+	}
+}
+
+func newCipherRotationOptions(opts []CipherRotationOption) *CipherRotationOptions {
+	o := CipherRotationOptions{BatchSize: DefaultCipherRotationBatchSize}
+	for _, f := range opts {
+		f(&o)
+	}
+	return &o
+}
+
+// Rotate walks all credentials which contain ciphertexts and re-encrypts the ciphertexts which were encrypted with an
+// older secret. Ciphertexts which can not be decrypted are counted and left unchanged.
+func (r *CipherRotator) Rotate(ctx context.Context, opts ...CipherRotationOption) (_ *CipherRotationProgress, err error) {
+	ctx, span := r.r.Tracer(ctx).Tracer().Start(ctx, "identity.CipherRotator.Rotate")
+	defer otelx.End(span, &err)
+
+	o := newCipherRotationOptions(opts)
+	progress := &CipherRotationProgress{Cursor: o.After, Keys: map[int]int{}}
+
+	// The progress of a batch is only reported once its updates were committed, which is when the next batch is
+	// loaded or the walk is done, so that the cursor can always be used to resume the rotation.
+	var pending bool
+	report := func() {
+		if pending && o.Progress != nil {
+			o.Progress(progress)
+		}
+		pending = false
+	}
+
+	skipped, err := r.r.PrivilegedIdentityPool().WalkCredentials(ctx, CredentialsTypeOIDC, o.After, o.BatchSize, func(ctx context.Context, credentials []Credentials) ([]Credentials, error) {
+		report()
+
+		var updated []Credentials
+		for _, cred := range credentials {
+			config, rotated, err := r.rotateOIDC(ctx, cred, progress)
+			if err != nil {
+				return nil, err
+			}
+
+			progress.Credentials++
+			progress.Rotated += rotated
+			if rotated > 0 && !o.DryRun {
+				cred.Config = config
+				updated = append(updated, cred)
+			}
+		}
+
+		progress.Cursor = credentials[len(credentials)-1].ID
+		pending = true
+		return updated, nil
+	})
+	progress.Skipped = skipped
+	if err != nil {
+		return progress, err
+	}
+
+	report()
+	return progress, nil
+}
+
+// rotateOIDC re-encrypts the tokens of the OpenID Connect providers in the credentials config. It returns the new
+// config and the number of re-encrypted tokens.
+func (r *CipherRotator) rotateOIDC(ctx context.Context, cred Credentials, progress *CipherRotationProgress) ([]byte, int, error) {
+	config := []byte(cred.Config)
+	var rotated int
+	for i, provider := range gjson.GetBytes(config, "providers").Array() {
+		for _, token := range encryptedOIDCTokens {
+			ciphertext := provider.Get(token).String()
+			if ciphertext == "" {
+				continue
+			}
+
+			reencrypted, key, err := r.r.Cipher(ctx).Rotate(ctx, ciphertext)
+			if err != nil {
+				progress.Undecryptable++
+				r.r.Logger().
+					WithError(err).
+					WithField("identity_id", cred.IdentityID).
+					WithField("credentials_id", cred.ID).
+					Warnf("Unable to decrypt the %s of the credentials with any of the configured cipher secrets.", token)
+				continue
+			}
+
+			progress.Keys[key]++
+			if key == 0 {
+				continue
+			}
+
+			config, err = sjson.SetBytes(config, fmt.Sprintf("providers.%d.%s", i, token), reencrypted)
+			if err != nil {
+				return nil, 0, err
+			}
+			rotated++
+		}
+	}
+	return config, rotated, nil
+}
@@ -0,0 +1,120 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/x"
+)
+
+func TestCipherRotator(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/identity.schema.json")
+	conf.MustSet(ctx, config.ViperKeyCipherAlgorithm, "xchacha20-poly1305")
+
+	oldSecret, newSecret := "old-secret-thirty-two-characters", "new-secret-thirty-two-characters"
+	conf.MustSet(ctx, config.ViperKeySecretsCipher, []string{oldSecret})
+
+	encrypt := func(t *testing.T, plaintext string) string {
+		ciphertext, err := reg.Cipher(ctx).Encrypt(ctx, []byte(plaintext))
+		require.NoError(t, err)
+		return ciphertext
+	}
+
+	var ids []uuid.UUID
+	for k := 0; k < 3; k++ {
+		subject := x.NewUUID().String()
+		creds, err := identity.NewCredentialsOIDC(encrypt(t, "id-token"), encrypt(t, "access-token"), "", "google", subject)
+		require.NoError(t, err)
+
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.SetCredentials(identity.CredentialsTypeOIDC, *creds)
+		require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(ctx, i))
+		ids = append(ids, i.ID)
+	}
+
+	conf.MustSet(ctx, config.ViperKeySecretsCipher, []string{newSecret, oldSecret})
+
+	t.Run("case=dry run counts ciphertexts per secret", func(t *testing.T) {
+		var batches int
+		progress, err := reg.IdentityCipherRotator().Rotate(ctx,
+			identity.CipherRotationDryRun(true),
+			identity.CipherRotationBatchSize(2),
+			identity.CipherRotationWithProgress(func(*identity.CipherRotationProgress) { batches++ }))
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, progress.Credentials)
+		assert.Equal(t, 6, progress.Rotated)
+		assert.Equal(t, map[int]int{1: 6}, progress.Keys)
+		assert.Equal(t, 2, batches)
+
+		progress, err = reg.IdentityCipherRotator().Rotate(ctx, identity.CipherRotationDryRun(true))
+		require.NoError(t, err)
+		assert.Equal(t, map[int]int{1: 6}, progress.Keys, "a dry run does not change anything")
+	})
+
+	t.Run("case=resumes after the cursor", func(t *testing.T) {
+		var cursors []uuid.UUID
+		_, err := reg.IdentityCipherRotator().Rotate(ctx,
+			identity.CipherRotationDryRun(true),
+			identity.CipherRotationBatchSize(1),
+			identity.CipherRotationWithProgress(func(p *identity.CipherRotationProgress) { cursors = append(cursors, p.Cursor) }))
+		require.NoError(t, err)
+		require.Len(t, cursors, 3)
+
+		progress, err := reg.IdentityCipherRotator().Rotate(ctx, identity.CipherRotationDryRun(true), identity.CipherRotationAfter(cursors[0]))
+		require.NoError(t, err)
+		assert.Equal(t, 2, progress.Credentials)
+		assert.Equal(t, cursors[2], progress.Cursor)
+	})
+
+	t.Run("case=re-encrypts ciphertexts with the current secret", func(t *testing.T) {
+		progress, err := reg.IdentityCipherRotator().Rotate(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, progress.Credentials)
+		assert.Equal(t, 6, progress.Rotated)
+
+		progress, err = reg.IdentityCipherRotator().Rotate(ctx, identity.CipherRotationDryRun(true))
+		require.NoError(t, err)
+		assert.Equal(t, map[int]int{0: 6}, progress.Keys)
+		assert.Zero(t, progress.Rotated)
+
+		conf.MustSet(ctx, config.ViperKeySecretsCipher, []string{newSecret})
+		for _, id := range ids {
+			i, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, id)
+			require.NoError(t, err)
+
+			declassified, err := i.WithDeclassifiedCredentials(ctx, reg, []identity.CredentialsType{identity.CredentialsTypeOIDC})
+			require.NoError(t, err)
+
+			config := declassified.Credentials[identity.CredentialsTypeOIDC].Config
+			assert.Equal(t, "id-token", gjson.GetBytes(config, "providers.0.initial_id_token").String(), "%s", config)
+			assert.Equal(t, "access-token", gjson.GetBytes(config, "providers.0.initial_access_token").String(), "%s", config)
+		}
+	})
+
+	t.Run("case=counts undecryptable ciphertexts", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySecretsCipher, []string{oldSecret})
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeySecretsCipher, []string{newSecret})
+		})
+
+		progress, err := reg.IdentityCipherRotator().Rotate(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 6, progress.Undecryptable)
+		assert.Empty(t, progress.Keys)
+	})
+}
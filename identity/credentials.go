@@ -49,12 +49,18 @@ func (c CredentialsType) ToUiNodeGroup() node.UiNodeGroup {
 		return node.PasswordGroup
 	case CredentialsTypeOIDC:
 		return node.OpenIDConnectGroup
+	case CredentialsTypeSAML:
+		return node.SAMLGroup
 	case CredentialsTypeTOTP:
 		return node.TOTPGroup
 	case CredentialsTypeWebAuthn:
 		return node.WebAuthnGroup
 	case CredentialsTypeLookup:
 		return node.LookupGroup
+	case CredentialsTypeCodeAuth:
+		return node.CodeGroup
+	case CredentialsTypePasskey:
+		return node.PasskeyGroup
 	default:
 		return node.DefaultGroup
 	}
@@ -67,6 +73,9 @@ const (
 	CredentialsTypeTOTP     CredentialsType = "totp"
 	CredentialsTypeLookup   CredentialsType = "lookup_secret"
 	CredentialsTypeWebAuthn CredentialsType = "webauthn"
+	CredentialsTypeCodeAuth CredentialsType = "code"
+	CredentialsTypePasskey  CredentialsType = "passkey"
+	CredentialsTypeSAML     CredentialsType = "saml"
 )
 
 const (
@@ -84,6 +93,9 @@ func ParseCredentialsType(in string) (CredentialsType, bool) {
 		CredentialsTypeTOTP,
 		CredentialsTypeLookup,
 		CredentialsTypeWebAuthn,
+		CredentialsTypeCodeAuth,
+		CredentialsTypePasskey,
+		CredentialsTypeSAML,
 		CredentialsTypeRecoveryLink,
 		CredentialsTypeRecoveryCode,
 	} {
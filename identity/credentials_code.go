@@ -0,0 +1,44 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+// CodeAddressType is the channel a one-time login or registration code is delivered through.
+//
+// swagger:model identityCredentialsCodeAddressType
+type CodeAddressType string
+
+const (
+	CodeAddressTypeEmail CodeAddressType = AddressTypeEmail
+	CodeAddressTypeSMS   CodeAddressType = "sms"
+)
+
+// CredentialsCode represents a one-time code credential which is delivered to one of the
+// identity's addresses.
+//
+// swagger:model identityCredentialsCode
+type CredentialsCode struct {
+	// Addresses lists all addresses a code can be sent to.
+	Addresses []CredentialsCodeAddress `json:"addresses"`
+}
+
+// CredentialsCodeAddress is an address a one-time code can be delivered to.
+//
+// swagger:model identityCredentialsCodeAddress
+type CredentialsCodeAddress struct {
+	// The channel to deliver the code through (e.g. "email" or "sms").
+	Channel CodeAddressType `json:"channel"`
+
+	// The address the code is delivered to (e.g. the email address or phone number).
+	Address string `json:"address"`
+}
+
+// FindAddress returns the address matching the given value or false if it does not exist.
+func (c *CredentialsCode) FindAddress(value string) (*CredentialsCodeAddress, bool) {
+	for k := range c.Addresses {
+		if c.Addresses[k].Address == value {
+			return &c.Addresses[k], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,59 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/x"
+)
+
+// CredentialsSAML contains the configuration for credentials of the type saml.
+//
+// swagger:model identityCredentialsSaml
+type CredentialsSAML struct {
+	Providers []CredentialsSAMLProvider `json:"providers"`
+}
+
+// CredentialsSAMLProvider contains a specific SAML credential for a particular identity provider (e.g. Okta).
+//
+// swagger:model identityCredentialsSamlProvider
+type CredentialsSAMLProvider struct {
+	Subject  string `json:"subject"`
+	Provider string `json:"provider"`
+}
+
+// NewCredentialsSAML creates a new SAML credential.
+func NewCredentialsSAML(provider, subject string) (*Credentials, error) {
+	if provider == "" {
+		return nil, errors.New("received empty provider in saml credentials")
+	}
+
+	if subject == "" {
+		return nil, errors.New("received empty subject in saml credentials")
+	}
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(CredentialsSAML{
+		Providers: []CredentialsSAMLProvider{{Subject: subject, Provider: provider}},
+	}); err != nil {
+		return nil, errors.WithStack(x.PseudoPanic.
+			WithDebugf("Unable to encode SAML credentials to JSON: %s", err))
+	}
+
+	return &Credentials{
+		Type:        CredentialsTypeSAML,
+		Identifiers: []string{SAMLUniqueID(provider, subject)},
+		Config:      b.Bytes(),
+	}, nil
+}
+
+// SAMLUniqueID returns the identifier of a subject (the SAML name identifier) at the given identity provider.
+func SAMLUniqueID(provider, subject string) string {
+	return fmt.Sprintf("%s:%s", provider, subject)
+}
@@ -0,0 +1,24 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCredentialsSAML(t *testing.T) {
+	_, err := NewCredentialsSAML("", "not-empty")
+	require.Error(t, err)
+	_, err = NewCredentialsSAML("not-empty", "")
+	require.Error(t, err)
+
+	c, err := NewCredentialsSAML("okta", "Jane.Doe")
+	require.NoError(t, err)
+	assert.Equal(t, CredentialsTypeSAML, c.Type)
+	assert.Equal(t, []string{"okta:Jane.Doe"}, c.Identifiers)
+	assert.JSONEq(t, `{"providers":[{"subject":"Jane.Doe","provider":"okta"}]}`, string(c.Config))
+}
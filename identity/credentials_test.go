@@ -40,6 +40,9 @@ func TestParseCredentialsType(t *testing.T) {
 		{"totp", CredentialsTypeTOTP},
 		{"webauthn", CredentialsTypeWebAuthn},
 		{"lookup_secret", CredentialsTypeLookup},
+		{"code", CredentialsTypeCodeAuth},
+		{"passkey", CredentialsTypePasskey},
+		{"saml", CredentialsTypeSAML},
 		{"link_recovery", CredentialsTypeRecoveryLink},
 		{"code_recovery", CredentialsTypeRecoveryCode},
 	} {
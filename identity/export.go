@@ -0,0 +1,245 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/pagination/keysetpagination"
+
+	"github.com/ory/kratos/x"
+)
+
+// DefaultExportBatchSize is the number of identities loaded at once when exporting identities.
+const DefaultExportBatchSize = 250
+
+type (
+	exporterDependencies interface {
+		PrivilegedPoolProvider
+		x.TracingProvider
+	}
+	ExporterProvider interface {
+		IdentityExporter() *Exporter
+	}
+	// Exporter writes identities as newline delimited JSON. Every line is a CreateIdentityBody, which means that
+	// the export can be imported again using `kratos import identities` or `POST /admin/identities`.
+	Exporter struct {
+		r exporterDependencies
+	}
+
+	ExportOptions struct {
+		// After is the ID of the identity after which the export starts. Set it to the cursor of the last reported
+		// progress to resume an interrupted export.
+		After uuid.UUID
+
+		// BatchSize is the number of identities loaded at once.
+		BatchSize int
+
+		// IncludeCredentials includes the password hashes and the OpenID Connect subjects.
+		IncludeCredentials bool
+
+		// IncludeAddresses includes the verifiable and recovery addresses together with their verification status.
+		IncludeAddresses bool
+
+		// Progress is called after every batch.
+		Progress func(progress *ExportProgress)
+	}
+
+	ExportOption func(*ExportOptions)
+
+	// ExportProgress reports the progress of an export.
+	ExportProgress struct {
+		// Cursor is the ID of the last exported identity.
+		Cursor uuid.UUID `json:"cursor"`
+
+		// Identities is the number of exported identities.
+		Identities int `json:"identities"`
+	}
+)
+
+func NewExporter(r exporterDependencies) *Exporter {
+	return &Exporter{r: r}
+}
+
+// ExportAfter resumes the export after the identity with the given ID.
+func ExportAfter(id uuid.UUID) ExportOption {
+	return func(o *ExportOptions) {
+		o.After = id
+	}
+}
+
+// ExportBatchSize sets the number of identities loaded at once.
+func ExportBatchSize(size int) ExportOption {
+	return func(o *ExportOptions) {
+		o.BatchSize = size
+	}
+}
+
+// ExportIncludeCredentials includes the password hashes and the OpenID Connect subjects in the export.
+func ExportIncludeCredentials(include bool) ExportOption {
+	return func(o *ExportOptions) {
+		o.IncludeCredentials = include
+	}
+}
+
+// ExportIncludeAddresses includes the verifiable and recovery addresses in the export.
+func ExportIncludeAddresses(include bool) ExportOption {
+	return func(o *ExportOptions) {
+		o.IncludeAddresses = include
+	}
+}
+
+// ExportWithProgress calls the given function after every batch.
+func ExportWithProgress(f func(progress *ExportProgress)) ExportOption {
+	return func(o *ExportOptions) {
+		o.Progress = f
+	}
+}
+
+func newExportOptions(opts []ExportOption) *ExportOptions {
+	o := ExportOptions{BatchSize: DefaultExportBatchSize}
+	for _, f := range opts {
+		f(&o)
+	}
+	return &o
+}
+
+// Export walks all identities ordered by their ID and writes one CreateIdentityBody per line to w.
+func (e *Exporter) Export(ctx context.Context, w io.Writer, opts ...ExportOption) (_ *ExportProgress, err error) {
+	ctx, span := e.r.Tracer(ctx).Tracer().Start(ctx, "identity.Exporter.Export")
+	defer otelx.End(span, &err)
+
+	o := newExportOptions(opts)
+	progress := &ExportProgress{Cursor: o.After}
+
+	expand := Expandables{}
+	if o.IncludeCredentials {
+		expand = append(expand, ExpandFieldCredentials)
+	}
+	if o.IncludeAddresses {
+		expand = append(expand, ExpandFieldVerifiableAddresses, ExpandFieldRecoveryAddresses)
+	}
+
+	enc := json.NewEncoder(w)
+	pagination := []keysetpagination.Option{
+		keysetpagination.WithToken(keysetpagination.StringPageToken(o.After.String())),
+		keysetpagination.WithSize(o.BatchSize),
+	}
+	for {
+		is, next, err := e.r.PrivilegedIdentityPool().ListIdentities(ctx, ListIdentityParameters{
+			Expand:           expand,
+			KeySetPagination: pagination,
+		})
+		if err != nil {
+			return progress, err
+		}
+
+		for k := range is {
+			body, err := exportIdentity(&is[k], o)
+			if err != nil {
+				return progress, err
+			}
+			if err := enc.Encode(body); err != nil {
+				return progress, errors.WithStack(err)
+			}
+			progress.Cursor = is[k].ID
+			progress.Identities++
+		}
+
+		if len(is) > 0 && o.Progress != nil {
+			o.Progress(progress)
+		}
+		if next.IsLast() {
+			return progress, nil
+		}
+		pagination = next.ToOptions()
+	}
+}
+
+// exportIdentity converts the identity to the body accepted by the create identity endpoint.
+func exportIdentity(i *Identity, o *ExportOptions) (*CreateIdentityBody, error) {
+	body := &CreateIdentityBody{
+		SchemaID:       i.SchemaID,
+		Traits:         json.RawMessage(i.Traits),
+		MetadataPublic: json.RawMessage(i.MetadataPublic),
+		MetadataAdmin:  json.RawMessage(i.MetadataAdmin),
+		OrganizationID: i.OrganizationID,
+		State:          i.State,
+	}
+
+	// The create identity body can not carry the reason of a suspension or pending deletion, which is why these
+	// identities are exported as inactive. This keeps them from signing in after they were imported.
+	if i.State == StateSuspended || i.State == StatePendingDeletion {
+		body.State = StateInactive
+	}
+
+	if o.IncludeAddresses {
+		// The addresses are created anew on import, which is why their IDs are not exported.
+		body.VerifiableAddresses = make([]VerifiableAddress, len(i.VerifiableAddresses))
+		for k, a := range i.VerifiableAddresses {
+			a.ID = uuid.Nil
+			body.VerifiableAddresses[k] = a
+		}
+		body.RecoveryAddresses = make([]RecoveryAddress, len(i.RecoveryAddresses))
+		for k, a := range i.RecoveryAddresses {
+			a.ID = uuid.Nil
+			body.RecoveryAddresses[k] = a
+		}
+	}
+
+	if o.IncludeCredentials {
+		creds, err := exportCredentials(i)
+		if err != nil {
+			return nil, err
+		}
+		body.Credentials = creds
+	}
+
+	return body, nil
+}
+
+// exportCredentials exports the credentials which can be imported, which are the password hashes and the OpenID
+// Connect subjects. The OpenID Connect tokens are not exported.
+func exportCredentials(i *Identity) (*IdentityWithCredentials, error) {
+	var creds IdentityWithCredentials
+
+	if c, ok := i.GetCredentials(CredentialsTypePassword); ok {
+		var conf CredentialsPassword
+		if err := json.Unmarshal(c.Config, &conf); err != nil {
+			return nil, errors.WithStack(x.PseudoPanic.WithWrap(err))
+		}
+		if conf.HashedPassword != "" {
+			creds.Password = &AdminIdentityImportCredentialsPassword{
+				Config: AdminIdentityImportCredentialsPasswordConfig{HashedPassword: conf.HashedPassword},
+			}
+		}
+	}
+
+	if c, ok := i.GetCredentials(CredentialsTypeOIDC); ok {
+		var conf CredentialsOIDC
+		if err := json.Unmarshal(c.Config, &conf); err != nil {
+			return nil, errors.WithStack(x.PseudoPanic.WithWrap(err))
+		}
+		if len(conf.Providers) > 0 {
+			providers := make([]AdminCreateIdentityImportCredentialsOidcProvider, len(conf.Providers))
+			for k, p := range conf.Providers {
+				providers[k] = AdminCreateIdentityImportCredentialsOidcProvider{Subject: p.Subject, Provider: p.Provider}
+			}
+			creds.OIDC = &AdminIdentityImportCredentialsOIDC{
+				Config: AdminIdentityImportCredentialsOIDCConfig{Providers: providers},
+			}
+		}
+	}
+
+	if creds.Password == nil && creds.OIDC == nil {
+		return nil, nil
+	}
+	return &creds, nil
+}
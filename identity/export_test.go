@@ -0,0 +1,110 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+)
+
+func TestExporter(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/identity.schema.json")
+
+	var ids []uuid.UUID
+	emails := map[uuid.UUID]string{}
+	for k := 0; k < 5; k++ {
+		email := fmt.Sprintf("export-%d@ory.sh", k)
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.Traits = identity.Traits(fmt.Sprintf(`{"email":%q}`, email))
+		i.MetadataAdmin = []byte(`{"export":true}`)
+		i.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{
+			Type:        identity.CredentialsTypePassword,
+			Identifiers: []string{email},
+			Config:      []byte(`{"hashed_password":"$2a$04$zvZz1zV"}`),
+		})
+		creds, err := identity.NewCredentialsOIDC("id-token", "access-token", "refresh-token", "google", fmt.Sprintf("subject-%d", k))
+		require.NoError(t, err)
+		i.SetCredentials(identity.CredentialsTypeOIDC, *creds)
+		i.VerifiableAddresses = []identity.VerifiableAddress{*identity.NewVerifiableEmailAddress(email, i.ID)}
+		i.VerifiableAddresses[0].Verified = true
+		i.VerifiableAddresses[0].Status = identity.VerifiableAddressStatusCompleted
+		require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(ctx, i))
+		ids = append(ids, i.ID)
+		emails[i.ID] = email
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+
+	export := func(t *testing.T, opts ...identity.ExportOption) ([]gjson.Result, []identity.ExportProgress) {
+		var out bytes.Buffer
+		var batches []identity.ExportProgress
+		progress, err := reg.IdentityExporter().Export(ctx, &out, append(opts, identity.ExportWithProgress(func(p *identity.ExportProgress) {
+			batches = append(batches, *p)
+		}))...)
+		require.NoError(t, err)
+
+		var lines []gjson.Result
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			require.True(t, gjson.Valid(line), line)
+			lines = append(lines, gjson.Parse(line))
+		}
+		assert.Equal(t, len(lines), progress.Identities)
+		return lines, batches
+	}
+
+	t.Run("case=exports identities without credentials and addresses by default", func(t *testing.T) {
+		lines, _ := export(t)
+		require.Len(t, lines, len(ids))
+		for k, line := range lines {
+			assert.Equal(t, emails[ids[k]], line.Get("traits.email").String(), "%s", line.Raw)
+			assert.Equal(t, config.DefaultIdentityTraitsSchemaID, line.Get("schema_id").String())
+			assert.Equal(t, string(identity.StateActive), line.Get("state").String())
+			assert.JSONEq(t, `{"export":true}`, line.Get("metadata_admin").Raw)
+			assert.Equal(t, "null", line.Get("credentials").Raw)
+			assert.Equal(t, "null", line.Get("verifiable_addresses").Raw)
+			assert.False(t, line.Get("id").Exists())
+		}
+	})
+
+	t.Run("case=exports credentials and addresses", func(t *testing.T) {
+		lines, _ := export(t, identity.ExportIncludeCredentials(true), identity.ExportIncludeAddresses(true))
+		require.Len(t, lines, len(ids))
+		for _, line := range lines {
+			assert.Equal(t, "$2a$04$zvZz1zV", line.Get("credentials.password.config.hashed_password").String(), "%s", line.Raw)
+			assert.Equal(t, "google", line.Get("credentials.oidc.config.providers.0.provider").String(), "%s", line.Raw)
+			assert.True(t, strings.HasPrefix(line.Get("credentials.oidc.config.providers.0.subject").String(), "subject-"), "%s", line.Raw)
+			assert.NotContains(t, line.Raw, "access-token")
+
+			assert.Equal(t, line.Get("traits.email").String(), line.Get("verifiable_addresses.0.value").String(), "%s", line.Raw)
+			assert.True(t, line.Get("verifiable_addresses.0.verified").Bool(), "%s", line.Raw)
+			assert.Equal(t, uuid.Nil.String(), line.Get("verifiable_addresses.0.id").String(), "%s", line.Raw)
+		}
+	})
+
+	t.Run("case=exports in batches and resumes after the cursor", func(t *testing.T) {
+		lines, batches := export(t, identity.ExportBatchSize(2))
+		require.Len(t, lines, len(ids))
+		require.Len(t, batches, 3)
+		assert.Equal(t, identity.ExportProgress{Cursor: ids[1], Identities: 2}, batches[0])
+		assert.Equal(t, identity.ExportProgress{Cursor: ids[4], Identities: 5}, batches[2])
+
+		lines, _ = export(t, identity.ExportAfter(batches[0].Cursor))
+		assert.Len(t, lines, len(ids)-2)
+	})
+}
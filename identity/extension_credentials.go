@@ -4,10 +4,13 @@
 package identity
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
 
+	"github.com/pkg/errors"
+
 	"github.com/ory/jsonschema/v3"
 	"github.com/ory/x/sqlxx"
 	"github.com/ory/x/stringslice"
@@ -18,6 +21,7 @@ import (
 type SchemaExtensionCredentials struct {
 	i *Identity
 	v map[CredentialsType][]string
+	a []CredentialsCodeAddress
 	l sync.Mutex
 }
 
@@ -43,6 +47,33 @@ func (r *SchemaExtensionCredentials) setIdentifier(ct CredentialsType, value int
 	r.i.SetCredentials(ct, *cred)
 }
 
+func (r *SchemaExtensionCredentials) setCodeAddress(via string, value interface{}) error {
+	r.setIdentifier(CredentialsTypeCodeAuth, value)
+
+	channel := CodeAddressTypeEmail
+	if via == string(CodeAddressTypeSMS) {
+		channel = CodeAddressTypeSMS
+	}
+
+	address := strings.ToLower(fmt.Sprintf("%s", value))
+	for _, a := range r.a {
+		if a.Address == address {
+			return nil
+		}
+	}
+	r.a = append(r.a, CredentialsCodeAddress{Channel: channel, Address: address})
+
+	conf, err := json.Marshal(&CredentialsCode{Addresses: r.a})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	cred, _ := r.i.GetCredentials(CredentialsTypeCodeAuth)
+	cred.Config = conf
+	r.i.SetCredentials(CredentialsTypeCodeAuth, *cred)
+	return nil
+}
+
 func (r *SchemaExtensionCredentials) Run(_ jsonschema.ValidationContext, s schema.ExtensionConfig, value interface{}) error {
 	r.l.Lock()
 	defer r.l.Unlock()
@@ -55,6 +86,12 @@ func (r *SchemaExtensionCredentials) Run(_ jsonschema.ValidationContext, s schem
 		r.setIdentifier(CredentialsTypeWebAuthn, value)
 	}
 
+	if s.Credentials.Code.Identifier {
+		if err := r.setCodeAddress(s.Credentials.Code.Via, value); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -6,6 +6,7 @@ package identity_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -72,6 +73,12 @@ func TestSchemaExtensionCredentials(t *testing.T) {
 			},
 			ct: identity.CredentialsTypeWebAuthn,
 		},
+		{
+			doc:    `{"email":"FOO@ory.sh","phone":"+4917612345678"}`,
+			schema: "file://./stub/extension/credentials/code.schema.json",
+			expect: []string{"foo@ory.sh", "+4917612345678"},
+			ct:     identity.CredentialsTypeCodeAuth,
+		},
 	} {
 		t.Run(fmt.Sprintf("case=%d", k), func(t *testing.T) {
 			c := jsonschema.NewCompiler()
@@ -97,3 +104,30 @@ func TestSchemaExtensionCredentials(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaExtensionCredentialsCodeAddresses(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	runner, err := schema.NewExtensionRunner(ctx)
+	require.NoError(t, err)
+
+	i := new(identity.Identity)
+	e := identity.NewSchemaExtensionCredentials(i)
+	runner.AddRunner(e).Register(c)
+	require.NoError(t, c.MustCompile(ctx, "file://./stub/extension/credentials/code.schema.json").
+		Validate(bytes.NewBufferString(`{"email":"FOO@ory.sh","phone":"+4917612345678"}`)))
+	require.NoError(t, e.Finish())
+
+	credentials, ok := i.GetCredentials(identity.CredentialsTypeCodeAuth)
+	require.True(t, ok)
+
+	var conf identity.CredentialsCode
+	require.NoError(t, json.Unmarshal(credentials.Config, &conf))
+	assert.ElementsMatch(t, []identity.CredentialsCodeAddress{
+		{Channel: identity.CodeAddressTypeEmail, Address: "foo@ory.sh"},
+		{Channel: identity.CodeAddressTypeSMS, Address: "+4917612345678"},
+	}, conf.Addresses)
+
+	address, ok := conf.FindAddress("+4917612345678")
+	require.True(t, ok)
+	assert.Equal(t, identity.CodeAddressTypeSMS, address.Channel)
+}
@@ -8,10 +8,14 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/ory/x/pagination/keysetpagination"
 	"github.com/ory/x/pagination/migrationpagination"
 
+	"github.com/ory/kratos/audit"
+	"github.com/ory/kratos/bruteforce"
 	"github.com/ory/kratos/hash"
 	"github.com/ory/kratos/x"
 
@@ -19,6 +23,7 @@ import (
 
 	"github.com/ory/herodot"
 
+	"github.com/gofrs/uuid"
 	"github.com/julienschmidt/httprouter"
 	"github.com/pkg/errors"
 
@@ -26,6 +31,7 @@ import (
 	"github.com/ory/x/jsonx"
 	"github.com/ory/x/openapix"
 	"github.com/ory/x/sqlxx"
+	"github.com/ory/x/stringslice"
 	"github.com/ory/x/urlx"
 
 	"github.com/ory/kratos/driver/config"
@@ -35,6 +41,15 @@ const (
 	RouteCollection     = "/identities"
 	RouteItem           = RouteCollection + "/:id"
 	RouteCredentialItem = RouteItem + "/credentials/:type"
+	RouteLoginLockout   = RouteItem + "/login-lockout"
+	RouteState          = RouteItem + "/state"
+	RoutePasswordHashes = "/password-hashes"
+	RouteExport         = "/export/identities"
+
+	RouteSchemaMigrationCollection = "/identity-schema-migrations"
+	RouteSchemaMigrationItem       = RouteSchemaMigrationCollection + "/:id"
+	RouteSchemaMigrationFailures   = RouteSchemaMigrationItem + "/failures"
+	RouteSchemaMigrationResume     = RouteSchemaMigrationItem + "/resume"
 
 	BatchPatchIdentitiesLimit = 2000
 )
@@ -49,6 +64,12 @@ type (
 		x.CSRFProvider
 		cipher.Provider
 		hash.HashProvider
+		bruteforce.ThrottlerProvider
+		audit.RecorderProvider
+		ExporterProvider
+		SchemaMigratorProvider
+		SchemaMigrationPersistenceProvider
+		x.LoggingProvider
 	}
 	HandlerProvider interface {
 		IdentityHandler() *Handler
@@ -59,6 +80,16 @@ type (
 	}
 )
 
+// auditRedactedPaths are cut off in the audit log because they contain secrets such as password hashes.
+var auditRedactedPaths = []string{"credentials.*.config"}
+
+// auditSnapshot returns the identity's state as recorded in the audit log. It has to be taken before the
+// identity is modified because the credentials are shared with copies of the identity.
+func auditSnapshot(i *Identity) json.RawMessage {
+	raw, _ := json.Marshal(WithCredentialsAndAdminMetadataInJSON(*i))
+	return raw
+}
+
 func (h *Handler) Config(ctx context.Context) *config.Config {
 	return h.r.Config()
 }
@@ -74,8 +105,14 @@ func (h *Handler) RegisterPublicRoutes(public *x.RouterPublic) {
 	h.r.CSRFHandler().IgnoreGlobs(
 		RouteCollection, RouteCollection+"/*",
 		RouteCollection+"/*/credentials/*",
+		RouteCollection+"/*/login-lockout",
+		RouteCollection+"/*/state",
 		x.AdminPrefix+RouteCollection, x.AdminPrefix+RouteCollection+"/*",
 		x.AdminPrefix+RouteCollection+"/*/credentials/*",
+		x.AdminPrefix+RouteCollection+"/*/login-lockout",
+		x.AdminPrefix+RouteCollection+"/*/state",
+		RouteSchemaMigrationCollection, RouteSchemaMigrationCollection+"/*/resume",
+		x.AdminPrefix+RouteSchemaMigrationCollection, x.AdminPrefix+RouteSchemaMigrationCollection+"/*/resume",
 	)
 
 	public.GET(RouteCollection, x.RedirectToAdminRoute(h.r))
@@ -85,6 +122,14 @@ func (h *Handler) RegisterPublicRoutes(public *x.RouterPublic) {
 	public.PUT(RouteItem, x.RedirectToAdminRoute(h.r))
 	public.PATCH(RouteItem, x.RedirectToAdminRoute(h.r))
 	public.DELETE(RouteCredentialItem, x.RedirectToAdminRoute(h.r))
+	public.DELETE(RouteLoginLockout, x.RedirectToAdminRoute(h.r))
+	public.PUT(RouteState, x.RedirectToAdminRoute(h.r))
+	public.GET(RoutePasswordHashes, x.RedirectToAdminRoute(h.r))
+	public.GET(RouteExport, x.RedirectToAdminRoute(h.r))
+	public.POST(RouteSchemaMigrationCollection, x.RedirectToAdminRoute(h.r))
+	public.GET(RouteSchemaMigrationItem, x.RedirectToAdminRoute(h.r))
+	public.GET(RouteSchemaMigrationFailures, x.RedirectToAdminRoute(h.r))
+	public.POST(RouteSchemaMigrationResume, x.RedirectToAdminRoute(h.r))
 
 	public.GET(x.AdminPrefix+RouteCollection, x.RedirectToAdminRoute(h.r))
 	public.GET(x.AdminPrefix+RouteItem, x.RedirectToAdminRoute(h.r))
@@ -93,6 +138,14 @@ func (h *Handler) RegisterPublicRoutes(public *x.RouterPublic) {
 	public.PUT(x.AdminPrefix+RouteItem, x.RedirectToAdminRoute(h.r))
 	public.PATCH(x.AdminPrefix+RouteItem, x.RedirectToAdminRoute(h.r))
 	public.DELETE(x.AdminPrefix+RouteCredentialItem, x.RedirectToAdminRoute(h.r))
+	public.DELETE(x.AdminPrefix+RouteLoginLockout, x.RedirectToAdminRoute(h.r))
+	public.PUT(x.AdminPrefix+RouteState, x.RedirectToAdminRoute(h.r))
+	public.GET(x.AdminPrefix+RoutePasswordHashes, x.RedirectToAdminRoute(h.r))
+	public.GET(x.AdminPrefix+RouteExport, x.RedirectToAdminRoute(h.r))
+	public.POST(x.AdminPrefix+RouteSchemaMigrationCollection, x.RedirectToAdminRoute(h.r))
+	public.GET(x.AdminPrefix+RouteSchemaMigrationItem, x.RedirectToAdminRoute(h.r))
+	public.GET(x.AdminPrefix+RouteSchemaMigrationFailures, x.RedirectToAdminRoute(h.r))
+	public.POST(x.AdminPrefix+RouteSchemaMigrationResume, x.RedirectToAdminRoute(h.r))
 }
 
 func (h *Handler) RegisterAdminRoutes(admin *x.RouterAdmin) {
@@ -106,6 +159,16 @@ func (h *Handler) RegisterAdminRoutes(admin *x.RouterAdmin) {
 	admin.PUT(RouteItem, h.update)
 
 	admin.DELETE(RouteCredentialItem, h.deleteIdentityCredentials)
+	admin.DELETE(RouteLoginLockout, h.deleteIdentityLoginLockout)
+	admin.PUT(RouteState, h.updateState)
+
+	admin.GET(RoutePasswordHashes, h.getPasswordHashReport)
+	admin.GET(RouteExport, h.exportIdentities)
+
+	admin.POST(RouteSchemaMigrationCollection, h.createSchemaMigration)
+	admin.GET(RouteSchemaMigrationItem, h.getSchemaMigration)
+	admin.GET(RouteSchemaMigrationFailures, h.listSchemaMigrationFailures)
+	admin.POST(RouteSchemaMigrationResume, h.resumeSchemaMigration)
 }
 
 // Paginated Identity List Response
@@ -132,11 +195,88 @@ type listIdentitiesResponse struct {
 type listIdentitiesParameters struct {
 	migrationpagination.RequestParameters
 
+	// Items per Page
+	//
+	// This is the number of items per page to return when using keyset pagination.
+	// For details on pagination please head over to the [pagination documentation](https://www.ory.sh/docs/ecosystem/api-design#pagination).
+	//
+	// required: false
+	// in: query
+	// default: 250
+	// min: 1
+	// max: 1000
+	PageSize int `json:"page_size"`
+
+	// Next Page Token
+	//
+	// The next page token. When using keyset pagination, this is the ID of the last identity of the previous page.
+	// For details on pagination please head over to the [pagination documentation](https://www.ory.sh/docs/ecosystem/api-design#pagination).
+	//
+	// required: false
+	// in: query
+	PageToken string `json:"page_token"`
+
 	// CredentialsIdentifier is the identifier (username, email) of the credentials to look up.
 	//
 	// required: false
 	// in: query
 	CredentialsIdentifier string `json:"credentials_identifier"`
+
+	// Trait filters identities by a trait in the format `<path>:<value>`, for example `email:foo@ory.sh` or
+	// `address.city:Munich`. If the value ends with `*`, all identities whose trait starts with the value match,
+	// for example `email:foo@*`. Can be given multiple times, in which case all filters must match.
+	//
+	// required: false
+	// in: query
+	Trait []string `json:"trait"`
+
+	// State filters identities by their state.
+	//
+	// required: false
+	// in: query
+	State State `json:"state"`
+
+	// SchemaID filters identities by their identity schema.
+	//
+	// required: false
+	// in: query
+	SchemaID string `json:"schema_id"`
+
+	// CredentialsType filters identities which have credentials of the given type, for example `password` or `oidc`.
+	//
+	// required: false
+	// in: query
+	CredentialsType string `json:"credentials_type"`
+
+	// Verified filters identities which have at least one verified address if true, or none if false.
+	//
+	// required: false
+	// in: query
+	Verified *bool `json:"verified"`
+
+	// CreatedAfter filters out identities which were created before the given time (RFC 3339).
+	//
+	// required: false
+	// in: query
+	CreatedAfter *time.Time `json:"created_after"`
+
+	// CreatedBefore filters out identities which were created after the given time (RFC 3339).
+	//
+	// required: false
+	// in: query
+	CreatedBefore *time.Time `json:"created_before"`
+
+	// UpdatedAfter filters out identities which were last updated before the given time (RFC 3339).
+	//
+	// required: false
+	// in: query
+	UpdatedAfter *time.Time `json:"updated_after"`
+
+	// UpdatedBefore filters out identities which were last updated after the given time (RFC 3339).
+	//
+	// required: false
+	// in: query
+	UpdatedBefore *time.Time `json:"updated_before"`
 }
 
 // swagger:route GET /admin/identities identity listIdentities
@@ -145,6 +285,12 @@ type listIdentitiesParameters struct {
 //
 // Lists all [identities](https://www.ory.sh/docs/kratos/concepts/identity-user-model) in the system.
 //
+// Identities can be filtered by trait values, state, schema, credential type, verified address status and
+// creation or update time. Filtered lists are paginated using keyset pagination ordered by the identity ID, as
+// are requests whose `page_token` is an identity ID. To page through all identities using keyset pagination,
+// start with `page_token=00000000-0000-0000-0000-000000000000`. The `page` and `per_page` parameters can not be
+// combined with keyset pagination.
+//
 //	Produces:
 //	- application/json
 //
@@ -155,21 +301,37 @@ type listIdentitiesParameters struct {
 //
 //	Responses:
 //	  200: listIdentities
+//	  400: errorGeneric
 //	  default: errorGeneric
 func (h *Handler) list(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-	page, itemsPerPage := x.ParsePagination(r)
+	params, err := parseListIdentitiesParameters(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
 
-	params := ListIdentityParameters{Expand: ExpandDefault, Page: page, PerPage: itemsPerPage, CredentialsIdentifier: r.URL.Query().Get("credentials_identifier")}
 	if params.CredentialsIdentifier != "" {
 		params.Expand = ExpandEverything
 	}
 
-	is, err := h.r.IdentityPool().ListIdentities(r.Context(), params)
+	is, nextPage, err := h.r.IdentityPool().ListIdentities(r.Context(), params)
 	if err != nil {
 		h.r.Writer().WriteError(w, r, err)
 		return
 	}
 
+	// Identities using the marshaler for including metadata_admin
+	isam := make([]WithCredentialsMetadataAndAdminMetadataInJSON, len(is))
+	for i, identity := range is {
+		isam[i] = WithCredentialsMetadataAndAdminMetadataInJSON(identity)
+	}
+
+	if params.KeySetPagination != nil {
+		keysetpagination.Header(w, r.URL, nextPage)
+		h.r.Writer().Write(w, r, isam)
+		return
+	}
+
 	total := int64(len(is))
 	if params.CredentialsIdentifier == "" {
 		total, err = h.r.IdentityPool().CountIdentities(r.Context())
@@ -179,14 +341,81 @@ func (h *Handler) list(w http.ResponseWriter, r *http.Request, _ httprouter.Para
 		}
 	}
 
-	// Identities using the marshaler for including metadata_admin
-	isam := make([]WithCredentialsMetadataAndAdminMetadataInJSON, len(is))
-	for i, identity := range is {
-		isam[i] = WithCredentialsMetadataAndAdminMetadataInJSON(identity)
+	migrationpagination.PaginationHeader(w, urlx.AppendPaths(h.r.Config().SelfAdminURL(r.Context()), RouteCollection), total, params.Page, params.PerPage)
+	h.r.Writer().Write(w, r, isam)
+}
+
+func parseListIdentitiesParameters(r *http.Request) (ListIdentityParameters, error) {
+	q := r.URL.Query()
+	params := ListIdentityParameters{
+		Expand:                ExpandDefault,
+		CredentialsIdentifier: q.Get("credentials_identifier"),
+		State:                 State(q.Get("state")),
+		SchemaID:              q.Get("schema_id"),
+		CredentialsType:       CredentialsType(q.Get("credentials_type")),
 	}
 
-	migrationpagination.PaginationHeader(w, urlx.AppendPaths(h.r.Config().SelfAdminURL(r.Context()), RouteCollection), total, page, itemsPerPage)
-	h.r.Writer().Write(w, r, isam)
+	for _, raw := range q["trait"] {
+		f, err := ParseTraitFilter(raw)
+		if err != nil {
+			return params, err
+		}
+		params.Traits = append(params.Traits, *f)
+	}
+
+	if params.State != "" {
+		if err := params.State.IsValid(); err != nil {
+			return params, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The state query parameter must be one of %q, %q, %q, or %q.", StateActive, StateInactive, StateSuspended, StatePendingDeletion))
+		}
+	}
+
+	if q.Has("verified") {
+		verified, err := strconv.ParseBool(q.Get("verified"))
+		if err != nil {
+			return params, errors.WithStack(herodot.ErrBadRequest.WithError(err.Error()).WithReason("The verified query parameter must be a boolean."))
+		}
+		params.Verified = &verified
+	}
+
+	for key, target := range map[string]**time.Time{
+		"created_after":  &params.CreatedAfter,
+		"created_before": &params.CreatedBefore,
+		"updated_after":  &params.UpdatedAfter,
+		"updated_before": &params.UpdatedBefore,
+	} {
+		if !q.Has(key) {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, q.Get(key))
+		if err != nil {
+			return params, errors.WithStack(herodot.ErrBadRequest.WithError(err.Error()).WithReasonf("The %s query parameter must be a RFC 3339 timestamp.", key))
+		}
+		*target = &t
+	}
+
+	// Keyset pagination tokens are identity IDs, which allows telling them apart from the opaque tokens of the
+	// migration pagination.
+	_, err := uuid.FromString(q.Get("page_token"))
+	isKeySet := err == nil
+	if !isKeySet && !params.HasFilters() {
+		params.Page, params.PerPage = x.ParsePagination(r)
+		return params, nil
+	}
+	if !isKeySet && q.Has("page_token") {
+		return params, errors.WithStack(herodot.ErrBadRequest.WithReason("The page_token query parameter must be an identity ID when filtering identities."))
+	}
+
+	if q.Has("page") || q.Has("per_page") {
+		return params, errors.WithStack(herodot.ErrBadRequest.WithReason("The page and per_page query parameters can not be combined with filters or keyset pagination."))
+	}
+
+	opts, err := keysetpagination.Parse(q, keysetpagination.NewStringPageToken)
+	if err != nil {
+		return params, errors.WithStack(herodot.ErrBadRequest.WithError(err.Error()).WithReason("The page_size query parameter must be a number."))
+	}
+	params.KeySetPagination = append([]keysetpagination.Option{}, opts...)
+
+	return params, nil
 }
 
 // Get Identity Parameters
@@ -314,6 +543,9 @@ type CreateIdentityBody struct {
 	// Store metadata about the user which is only accessible through admin APIs such as `GET /admin/identities/<id>`.
 	MetadataAdmin json.RawMessage `json:"metadata_admin,omitempty"`
 
+	// OrganizationID is the ID of the organization the identity belongs to.
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+
 	// State is the identity's state.
 	//
 	// required: false
@@ -422,6 +654,7 @@ func (h *Handler) create(w http.ResponseWriter, r *http.Request, _ httprouter.Pa
 		h.r.Writer().WriteError(w, r, err)
 		return
 	}
+	h.r.AuditRecorder().RecordChanges(r, audit.ActionIdentityCreated, audit.TargetTypeIdentity, i.ID, nil, auditSnapshot(i), auditRedactedPaths...)
 
 	h.r.Writer().WriteCreated(w, r,
 		urlx.AppendPaths(
@@ -435,23 +668,22 @@ func (h *Handler) create(w http.ResponseWriter, r *http.Request, _ httprouter.Pa
 
 func (h *Handler) identityFromCreateIdentityBody(ctx context.Context, cr *CreateIdentityBody) (*Identity, error) {
 	stateChangedAt := sqlxx.NullTime(time.Now())
-	state := StateActive
-	if cr.State != "" {
-		if err := cr.State.IsValid(); err != nil {
-			return nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf("%s", err).WithWrap(err))
-		}
-		state = cr.State
-	}
-
 	i := &Identity{
 		SchemaID:            cr.SchemaID,
 		Traits:              []byte(cr.Traits),
-		State:               state,
+		State:               StateActive,
 		StateChangedAt:      &stateChangedAt,
 		VerifiableAddresses: cr.VerifiableAddresses,
 		RecoveryAddresses:   cr.RecoveryAddresses,
 		MetadataAdmin:       []byte(cr.MetadataAdmin),
 		MetadataPublic:      []byte(cr.MetadataPublic),
+		OrganizationID:      cr.OrganizationID,
+	}
+
+	if cr.State != "" {
+		if err := i.ChangeState(StateChange{State: cr.State}, h.r.Config().IdentityDeletionGracePeriod(ctx)); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := h.importCredentials(ctx, i, cr.Credentials); err != nil {
@@ -536,6 +768,9 @@ func (h *Handler) batchPatchIdentities(w http.ResponseWriter, r *http.Request, _
 			res.Identities[resIdx].IdentityID = &identities[*identitiesIdx].ID
 		}
 	}
+	for _, i := range identities {
+		h.r.AuditRecorder().RecordChanges(r, audit.ActionIdentityBatchCreated, audit.TargetTypeIdentity, i.ID, nil, auditSnapshot(i), auditRedactedPaths...)
+	}
 
 	h.r.Writer().Write(w, r, &res)
 }
@@ -588,6 +823,10 @@ type UpdateIdentityBody struct {
 	// Store metadata about the user which is only accessible through admin APIs such as `GET /admin/identities/<id>`.
 	MetadataAdmin json.RawMessage `json:"metadata_admin,omitempty"`
 
+	// OrganizationID is the ID of the organization the identity belongs to. If empty, the identity is removed
+	// from its organization.
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+
 	// State is the identity's state.
 	//
 	// required: true
@@ -632,26 +871,23 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request, ps httprouter.P
 		h.r.Writer().WriteError(w, r, err)
 		return
 	}
+	before := auditSnapshot(identity)
 
 	if ur.SchemaID != "" {
 		identity.SchemaID = ur.SchemaID
 	}
 
 	if ur.State != "" && identity.State != ur.State {
-		if err := ur.State.IsValid(); err != nil {
-			h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithReasonf("%s", err).WithWrap(err)))
+		if err := identity.ChangeState(StateChange{State: ur.State}, h.r.Config().IdentityDeletionGracePeriod(r.Context())); err != nil {
+			h.r.Writer().WriteError(w, r, err)
 			return
 		}
-
-		stateChangedAt := sqlxx.NullTime(time.Now())
-
-		identity.State = ur.State
-		identity.StateChangedAt = &stateChangedAt
 	}
 
 	identity.Traits = []byte(ur.Traits)
 	identity.MetadataPublic = []byte(ur.MetadataPublic)
 	identity.MetadataAdmin = []byte(ur.MetadataAdmin)
+	identity.OrganizationID = ur.OrganizationID
 
 	// Although this is PUT and not PATCH, if the Credentials are not supplied keep the old one
 	if ur.Credentials != nil {
@@ -669,6 +905,7 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request, ps httprouter.P
 		h.r.Writer().WriteError(w, r, err)
 		return
 	}
+	h.r.AuditRecorder().RecordChanges(r, audit.ActionIdentityUpdated, audit.TargetTypeIdentity, identity.ID, before, auditSnapshot(identity), auditRedactedPaths...)
 
 	h.r.Writer().Write(w, r, WithCredentialsMetadataAndAdminMetadataInJSON(*identity))
 }
@@ -708,10 +945,12 @@ type deleteIdentity struct {
 //	  404: errorGeneric
 //	  default: errorGeneric
 func (h *Handler) delete(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	if err := h.r.PrivilegedIdentityPool().DeleteIdentity(r.Context(), x.ParseUUID(ps.ByName("id"))); err != nil {
+	id := x.ParseUUID(ps.ByName("id"))
+	if err := h.r.PrivilegedIdentityPool().DeleteIdentity(r.Context(), id); err != nil {
 		h.r.Writer().WriteError(w, r, err)
 		return
 	}
+	h.r.AuditRecorder().Record(r, audit.ActionIdentityDeleted, audit.TargetTypeIdentity, id, nil)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -738,7 +977,8 @@ type patchIdentity struct {
 // # Patch an Identity
 //
 // Partially updates an [identity's](https://www.ory.sh/docs/kratos/concepts/identity-user-model) field using [JSON Patch](https://jsonpatch.com/).
-// The fields `id`, `stateChangedAt` and `credentials` can not be updated using this method.
+// The fields `id`, `stateChangedAt`, `credentials`, `state_reason`, `state_note`, `suspended_until`, and
+// `delete_after` can not be updated using this method. Use `PUT /admin/identities/{id}/state` to suspend an identity.
 //
 //	Consumes:
 //	- application/json
@@ -771,12 +1011,13 @@ func (h *Handler) patch(w http.ResponseWriter, r *http.Request, ps httprouter.Pa
 		return
 	}
 
+	before := auditSnapshot(identity)
 	credentials := identity.Credentials
 	oldState := identity.State
 
 	patchedIdentity := WithAdminMetadataInJSON(*identity)
 
-	if err := jsonx.ApplyJSONPatch(requestBody, &patchedIdentity, "/id", "/stateChangedAt", "/credentials"); err != nil {
+	if err := jsonx.ApplyJSONPatch(requestBody, &patchedIdentity, "/id", "/stateChangedAt", "/credentials", "/state_reason", "/state_note", "/suspended_until", "/delete_after"); err != nil {
 		h.r.Writer().WriteError(w, r, errors.WithStack(
 			herodot.
 				ErrBadRequest.
@@ -797,16 +1038,20 @@ func (h *Handler) patch(w http.ResponseWriter, r *http.Request, ps httprouter.Pa
 			h.r.Writer().WriteError(w, r, errors.WithStack(
 				herodot.
 					ErrBadRequest.
-					WithReasonf("The supplied state ('%s') was not valid. Valid states are ('%s', '%s').", string(patchedIdentity.State), StateActive, StateInactive).
+					WithReasonf("The supplied state ('%s') was not valid. Valid states are ('%s', '%s', '%s', '%s').", string(patchedIdentity.State), StateActive, StateInactive, StateSuspended, StatePendingDeletion).
 					WithErrorf("%v", err).
 					WithWrap(err),
 			))
 			return
 		}
 
-		// If the state changed, we need to update the timestamp of it
-		stateChangedAt := sqlxx.NullTime(time.Now())
-		patchedIdentity.StateChangedAt = &stateChangedAt
+		// If the state changed, we need to update the timestamp of it and clear the previous state's details
+		patched := Identity(patchedIdentity)
+		if err := patched.ChangeState(StateChange{State: patchedIdentity.State}, h.r.Config().IdentityDeletionGracePeriod(r.Context())); err != nil {
+			h.r.Writer().WriteError(w, r, err)
+			return
+		}
+		patchedIdentity = WithAdminMetadataInJSON(patched)
 	}
 
 	updatedIdenty := Identity(patchedIdentity)
@@ -819,6 +1064,7 @@ func (h *Handler) patch(w http.ResponseWriter, r *http.Request, ps httprouter.Pa
 		h.r.Writer().WriteError(w, r, err)
 		return
 	}
+	h.r.AuditRecorder().RecordChanges(r, audit.ActionIdentityPatched, audit.TargetTypeIdentity, updatedIdenty.ID, before, auditSnapshot(&updatedIdenty), auditRedactedPaths...)
 
 	h.r.Writer().Write(w, r, WithCredentialsMetadataAndAdminMetadataInJSON(updatedIdenty))
 }
@@ -910,6 +1156,7 @@ func (h *Handler) deleteIdentityCredentials(w http.ResponseWriter, r *http.Reque
 		h.r.Writer().WriteError(w, r, err)
 		return
 	}
+	before := auditSnapshot(identity)
 
 	cred, ok := identity.GetCredentials(CredentialsType(ps.ByName("type")))
 	if !ok {
@@ -930,6 +1177,12 @@ func (h *Handler) deleteIdentityCredentials(w http.ResponseWriter, r *http.Reque
 		}
 	case CredentialsTypeOIDC:
 		fallthrough
+	case CredentialsTypeCodeAuth:
+		fallthrough
+	case CredentialsTypePasskey:
+		fallthrough
+	case CredentialsTypeSAML:
+		fallthrough
 	case CredentialsTypePassword:
 		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithReasonf("You can't remove first factor credentials.")))
 		return
@@ -946,6 +1199,64 @@ func (h *Handler) deleteIdentityCredentials(w http.ResponseWriter, r *http.Reque
 		h.r.Writer().WriteError(w, r, err)
 		return
 	}
+	h.r.AuditRecorder().RecordChanges(r, audit.ActionIdentityCredentialsDeleted, audit.TargetTypeIdentity, identity.ID, before, auditSnapshot(identity), auditRedactedPaths...)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Delete Login Lockout Parameters
+//
+// swagger:parameters deleteIdentityLoginLockout
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type deleteIdentityLoginLockout struct {
+	// ID is the identity's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route DELETE /admin/identities/{id}/login-lockout identity deleteIdentityLoginLockout
+//
+// # Unlock an Identity's Login
+//
+// Removes the brute-force protection lockout and back-off of all identifiers of an
+// [identity](https://www.ory.sh/docs/kratos/concepts/identity-user-model), allowing it to sign in again
+// immediately. Lockouts of client IP addresses are not affected.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  204: emptyResponse
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) deleteIdentityLoginLockout(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	i, err := h.r.PrivilegedIdentityPool().GetIdentityConfidential(r.Context(), x.ParseUUID(ps.ByName("id")))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	// Second factors are throttled by the identity ID instead of an identifier.
+	identifiers := []string{i.ID.String()}
+	for _, c := range i.Credentials {
+		identifiers = append(identifiers, c.Identifiers...)
+	}
+
+	for _, identifier := range stringslice.Unique(identifiers) {
+		if err := h.r.LoginThrottler().Clear(r.Context(), identifier); err != nil {
+			h.r.Writer().WriteError(w, r, err)
+			return
+		}
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,143 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+)
+
+// Export Identities Parameters
+//
+// swagger:parameters exportIdentities
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type exportIdentitiesParameters struct {
+	// IncludeCredentials includes the password hashes and the OpenID Connect subjects of the identities.
+	//
+	// required: false
+	// in: query
+	IncludeCredentials bool `json:"include_credentials"`
+
+	// IncludeAddresses includes the verifiable and recovery addresses of the identities, including their
+	// verification status.
+	//
+	// required: false
+	// in: query
+	IncludeAddresses bool `json:"include_addresses"`
+}
+
+// Export Identities Response
+//
+// swagger:response exportIdentities
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type exportIdentitiesResponse struct {
+	// Newline delimited JSON, one identity per line.
+	//
+	// in: body
+	Body string
+}
+
+// swagger:route GET /admin/export/identities identity exportIdentities
+//
+// # Export Identities
+//
+// Streams all identities as newline delimited JSON. Every line has the format of the
+// [create identity](https://www.ory.sh/docs/kratos/manage-identities/import-user-accounts-identities) request
+// body, which means that the export can be imported again using `kratos import identities`.
+//
+// Credentials are only included if `include_credentials` is set. Only password hashes and OpenID Connect
+// subjects are exported, as those are the credentials which can be imported. If the export fails after the
+// response started, the connection is aborted so that an incomplete export can not be mistaken for a
+// complete one.
+//
+//	Produces:
+//	- application/x-ndjson
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: exportIdentities
+//	  400: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) exportIdentities(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var opts []ExportOption
+	for key, option := range map[string]func(bool) ExportOption{
+		"include_credentials": ExportIncludeCredentials,
+		"include_addresses":   ExportIncludeAddresses,
+	} {
+		if !r.URL.Query().Has(key) {
+			continue
+		}
+		include, err := strconv.ParseBool(r.URL.Query().Get(key))
+		if err != nil {
+			h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithError(err.Error()).WithReasonf("The %s query parameter must be a boolean.", key)))
+			return
+		}
+		opts = append(opts, option(include))
+	}
+
+	out := &exportResponseWriter{w: w}
+	opts = append(opts, ExportWithProgress(func(*ExportProgress) { out.flush() }))
+
+	progress, err := h.r.IdentityExporter().Export(r.Context(), out, opts...)
+	if err == nil {
+		out.flush()
+		return
+	}
+
+	if !out.started {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Logger().
+		WithError(err).
+		WithField("cursor", progress.Cursor).
+		WithField("identities", progress.Identities).
+		Error("Unable to finish the identity export.")
+	panic(http.ErrAbortHandler)
+}
+
+// exportResponseWriter sets the response headers on the first write, so that errors which happen before any
+// identity was exported can still be reported with the appropriate status code.
+type exportResponseWriter struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+var _ io.Writer = (*exportResponseWriter)(nil)
+
+func (e *exportResponseWriter) Write(p []byte) (int, error) {
+	e.start()
+	return e.w.Write(p)
+}
+
+func (e *exportResponseWriter) start() {
+	if e.started {
+		return
+	}
+	e.w.Header().Set("Content-Type", "application/x-ndjson")
+	e.w.WriteHeader(http.StatusOK)
+	e.started = true
+}
+
+func (e *exportResponseWriter) flush() {
+	e.start()
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
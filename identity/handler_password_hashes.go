@@ -0,0 +1,151 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/gofrs/uuid"
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ory/kratos/hash"
+)
+
+// passwordHashReportBatchSize is the number of password credentials loaded at once when generating the report.
+const passwordHashReportBatchSize = 1000
+
+// Password Hash Report
+//
+// swagger:model passwordHashReport
+type PasswordHashReport struct {
+	// Total is the number of password credentials.
+	//
+	// required: true
+	Total int `json:"total"`
+
+	// Outdated is the number of password hashes which will be rehashed with the configured hasher on the next login.
+	//
+	// required: true
+	Outdated int `json:"outdated"`
+
+	// Hashes counts the password hashes by algorithm and parameters, most used first.
+	//
+	// required: true
+	Hashes []PasswordHashReportEntry `json:"hashes"`
+}
+
+// Password Hash Report Entry
+//
+// swagger:model passwordHashReportEntry
+type PasswordHashReportEntry struct {
+	// Algorithm is the hash algorithm, for example `bcrypt`, `argon2id`, `pbkdf2-sha256` or `md5`.
+	//
+	// required: true
+	Algorithm string `json:"algorithm"`
+
+	// Parameters are the parameters the hashes were generated with, for example `cost=12` for bcrypt or
+	// `m=131072,t=1,p=4` for argon2. Empty if the algorithm has no parameters.
+	//
+	// required: true
+	Parameters string `json:"parameters"`
+
+	// Count is the number of password credentials using this algorithm and parameters.
+	//
+	// required: true
+	Count int `json:"count"`
+
+	// Outdated is true if these hashes are not generated by the configured hasher or with weaker parameters than
+	// configured. They are rehashed on the next successful login.
+	//
+	// required: true
+	Outdated bool `json:"outdated"`
+}
+
+// Get Password Hash Report Response
+//
+// swagger:response getPasswordHashReport
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type getPasswordHashReportResponse struct {
+	// in: body
+	Body PasswordHashReport
+}
+
+// swagger:route GET /admin/password-hashes identity getPasswordHashReport
+//
+// # Get Password Hash Report
+//
+// Counts the password credentials of all identities by hash algorithm and parameters. Hashes which are not
+// generated by the configured hasher, or with weaker parameters than configured (for example a lower
+// `hashers.bcrypt.cost`), are marked as outdated and are rehashed on the next successful login. Use this
+// report to find out when imported legacy hashes are fully migrated.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: getPasswordHashReport
+//	  default: errorGeneric
+func (h *Handler) getPasswordHashReport(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	report, err := h.passwordHashReport(r.Context())
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, report)
+}
+
+func (h *Handler) passwordHashReport(ctx context.Context) (*PasswordHashReport, error) {
+	hasher := h.r.Hasher(ctx)
+	entries := map[PasswordHashReportEntry]int{}
+	report := &PasswordHashReport{Hashes: []PasswordHashReportEntry{}}
+
+	if _, err := h.r.PrivilegedIdentityPool().WalkCredentials(ctx, CredentialsTypePassword, uuid.Nil, passwordHashReportBatchSize, func(ctx context.Context, credentials []Credentials) ([]Credentials, error) {
+		for _, c := range credentials {
+			var conf CredentialsPassword
+			_ = json.Unmarshal(c.Config, &conf)
+			hashed := []byte(conf.HashedPassword)
+
+			var entry PasswordHashReportEntry
+			entry.Algorithm, entry.Parameters = hash.Describe(hashed)
+			entry.Outdated = !hasher.Understands(hashed) || hasher.NeedsRehash(ctx, hashed)
+
+			entries[entry]++
+			report.Total++
+			if entry.Outdated {
+				report.Outdated++
+			}
+		}
+		return nil, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for entry, count := range entries {
+		entry.Count = count
+		report.Hashes = append(report.Hashes, entry)
+	}
+	sort.Slice(report.Hashes, func(i, j int) bool {
+		a, b := report.Hashes[i], report.Hashes[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Algorithm != b.Algorithm {
+			return a.Algorithm < b.Algorithm
+		}
+		return a.Parameters < b.Parameters
+	})
+
+	return report, nil
+}
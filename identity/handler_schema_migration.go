@@ -0,0 +1,290 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/jsonx"
+	"github.com/ory/x/pagination/migrationpagination"
+	"github.com/ory/x/urlx"
+
+	"github.com/ory/kratos/x"
+)
+
+// Create Identity Schema Migration Parameters
+//
+// swagger:parameters createIdentitySchemaMigration
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type createIdentitySchemaMigration struct {
+	// in: body
+	Body CreateSchemaMigrationBody
+}
+
+// Create Identity Schema Migration Body
+//
+// swagger:model createIdentitySchemaMigrationBody
+type CreateSchemaMigrationBody struct {
+	// SourceSchemaID is the ID of the identity schema the identities are migrated from.
+	//
+	// required: true
+	SourceSchemaID string `json:"source_schema_id"`
+
+	// TargetSchemaID is the ID of the identity schema the identities are migrated to.
+	//
+	// required: true
+	TargetSchemaID string `json:"target_schema_id"`
+
+	// Transform is the Jsonnet code which rewrites the traits. The identity is available as
+	// `std.extVar('identity')` and the transform must return an object with the new traits in the
+	// `traits` key.
+	//
+	// required: true
+	Transform string `json:"transform"`
+
+	// DryRun only transforms and validates the traits without updating the identities.
+	DryRun bool `json:"dry_run"`
+
+	// BatchSize is the number of identities migrated at once. Defaults to 100.
+	BatchSize int `json:"batch_size"`
+}
+
+// swagger:route POST /admin/identity-schema-migrations identity createIdentitySchemaMigration
+//
+// # Start an Identity Schema Migration
+//
+// Starts a migration which moves all identities of the source identity schema to the target identity schema.
+// The traits of every identity are rewritten using the Jsonnet transform and validated against the target
+// identity schema. Identities which can not be migrated are recorded as failures and keep their schema and
+// traits.
+//
+// The migration runs in the background. Use the returned ID to follow its progress.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  201: identitySchemaMigration
+//	  400: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) createSchemaMigration(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var cr CreateSchemaMigrationBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&cr); err != nil {
+		h.r.Writer().WriteErrorCode(w, r, http.StatusBadRequest, errors.WithStack(err))
+		return
+	}
+
+	sm := &SchemaMigration{
+		SourceSchemaID: cr.SourceSchemaID,
+		TargetSchemaID: cr.TargetSchemaID,
+		Transform:      cr.Transform,
+		DryRun:         cr.DryRun,
+		BatchSize:      cr.BatchSize,
+	}
+	if err := h.r.IdentitySchemaMigrator().Create(r.Context(), sm); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.startSchemaMigration(r.Context(), sm.ID)
+	h.r.Writer().WriteCreated(w, r,
+		urlx.AppendPaths(
+			h.r.Config().SelfAdminURL(r.Context()),
+			RouteSchemaMigrationCollection,
+			sm.ID.String(),
+		).String(),
+		sm,
+	)
+}
+
+// Get Identity Schema Migration Parameters
+//
+// swagger:parameters getIdentitySchemaMigration
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type getIdentitySchemaMigration struct {
+	// ID is the identity schema migration's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route GET /admin/identity-schema-migrations/{id} identity getIdentitySchemaMigration
+//
+// # Get an Identity Schema Migration
+//
+// Returns the state and progress of an identity schema migration.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: identitySchemaMigration
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) getSchemaMigration(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	sm, err := h.r.IdentitySchemaMigrationPersister().GetSchemaMigration(r.Context(), x.ParseUUID(ps.ByName("id")))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, sm)
+}
+
+// Paginated Identity Schema Migration Failure List Response
+//
+// swagger:response listIdentitySchemaMigrationFailures
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listIdentitySchemaMigrationFailuresResponse struct {
+	migrationpagination.ResponseHeaderAnnotation
+
+	// List of identities which could not be migrated
+	//
+	// in:body
+	Body []SchemaMigrationFailure
+}
+
+// Paginated List Identity Schema Migration Failure Parameters
+//
+// swagger:parameters listIdentitySchemaMigrationFailures
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listIdentitySchemaMigrationFailuresParameters struct {
+	migrationpagination.RequestParameters
+
+	// ID is the identity schema migration's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route GET /admin/identity-schema-migrations/{id}/failures identity listIdentitySchemaMigrationFailures
+//
+// # List Identity Schema Migration Failures
+//
+// Lists the identities which could not be migrated, together with the reason, in the order they were processed.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: listIdentitySchemaMigrationFailures
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) listSchemaMigrationFailures(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	page, itemsPerPage := x.ParsePagination(r)
+
+	sm, err := h.r.IdentitySchemaMigrationPersister().GetSchemaMigration(r.Context(), x.ParseUUID(ps.ByName("id")))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	fs, err := h.r.IdentitySchemaMigrationPersister().ListSchemaMigrationFailures(r.Context(), sm.ID, page, itemsPerPage)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	migrationpagination.PaginationHeader(w, urlx.AppendPaths(h.r.Config().SelfAdminURL(r.Context()), RouteSchemaMigrationCollection, sm.ID.String(), "failures"), int64(sm.Failed), page, itemsPerPage)
+	h.r.Writer().Write(w, r, fs)
+}
+
+// Resume Identity Schema Migration Parameters
+//
+// swagger:parameters resumeIdentitySchemaMigration
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type resumeIdentitySchemaMigration struct {
+	// ID is the identity schema migration's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route POST /admin/identity-schema-migrations/{id}/resume identity resumeIdentitySchemaMigration
+//
+// # Resume an Identity Schema Migration
+//
+// Resumes a failed or interrupted identity schema migration after the last identity it processed. A running
+// migration is considered interrupted if it did not report any progress for five minutes.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  202: identitySchemaMigration
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) resumeSchemaMigration(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	sm, err := h.r.IdentitySchemaMigrationPersister().GetSchemaMigration(r.Context(), x.ParseUUID(ps.ByName("id")))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if sm.State == SchemaMigrationStateCompleted ||
+		(sm.State == SchemaMigrationStateRunning && sm.UpdatedAt.After(time.Now().Add(-SchemaMigrationStaleAfter))) {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrConflict.WithReasonf("The identity schema migration %s is already running or completed.", sm.ID)))
+		return
+	}
+
+	h.startSchemaMigration(r.Context(), sm.ID)
+	h.r.Writer().WriteCode(w, r, http.StatusAccepted, sm)
+}
+
+// startSchemaMigration runs the migration in the background, because migrating all identities takes longer
+// than a request may.
+func (h *Handler) startSchemaMigration(ctx context.Context, id uuid.UUID) {
+	// The migration must not be canceled together with the request which started it.
+	ctx = trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+	go func() {
+		if _, err := h.r.IdentitySchemaMigrator().Run(ctx, id); err != nil {
+			h.r.Logger().WithError(err).WithField("migration_id", id).Error("Unable to finish the identity schema migration.")
+		}
+	}()
+}
@@ -0,0 +1,88 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/jsonx"
+
+	"github.com/ory/kratos/audit"
+	"github.com/ory/kratos/x"
+)
+
+// Update Identity State Parameters
+//
+// swagger:parameters updateIdentityState
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type updateIdentityState struct {
+	// ID is the identity's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+
+	// in: body
+	Body StateChange
+}
+
+// swagger:route PUT /admin/identities/{id}/state identity updateIdentityState
+//
+// # Change an Identity's State
+//
+// Changes the state of an [identity](https://www.ory.sh/docs/kratos/concepts/identity-user-model).
+//
+// Suspending an identity requires a reason code and optionally accepts a note and the time the suspension ends,
+// after which the identity is reactivated. Identities pending deletion are deleted once the configured deletion
+// grace period passed, unless they are reactivated before. All sessions of the identity are revoked when it is
+// suspended or marked for deletion.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: identity
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) updateState(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var sc StateChange
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&sc); err != nil {
+		h.r.Writer().WriteErrorCode(w, r, http.StatusBadRequest, errors.WithStack(err))
+		return
+	}
+
+	i, err := h.r.PrivilegedIdentityPool().GetIdentityConfidential(r.Context(), x.ParseUUID(ps.ByName("id")))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	before := auditSnapshot(i)
+
+	if err := i.ChangeState(sc, h.r.Config().IdentityDeletionGracePeriod(r.Context())); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if err := h.r.IdentityManager().Update(r.Context(), i, ManagerAllowWriteProtectedTraits); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	h.r.AuditRecorder().RecordChanges(r, audit.ActionIdentityUpdated, audit.TargetTypeIdentity, i.ID, before, auditSnapshot(i), auditRedactedPaths...)
+
+	h.r.Writer().Write(w, r, WithCredentialsMetadataAndAdminMetadataInJSON(*i))
+}
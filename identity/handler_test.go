@@ -4,8 +4,10 @@
 package identity_test
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -263,6 +265,31 @@ func TestHandler(t *testing.T) {
 					name: "SSHA512",
 					hash: "{SSHA512}xPUl/px+1cG55rUH4rzcwxdOIPSB2TingLpiJJumN2xyDWN4Ix1WQG3ihnvHaWUE8MYNkvMi5rf0C9NYixHsE6Yh59M=",
 					pass: "test123",
+				}, {
+					name: "django-pbkdf2",
+					hash: "pbkdf2_sha256$1000$saltsalt$PV/NgngXg7GnyIijVakkmRje8KEdSsAPgecsnyDghT4=",
+					pass: "test",
+				}, {
+					name: "django-argon2",
+					hash: "argon2$argon2id$v=19$m=16,t=2,p=1$bVI1aE1SaTV6SGQ3bzdXdw$fnjCcZYmEPOUOjYXsT92Cg",
+					pass: "123456",
+				}, {
+					name: "phpass",
+					hash: "$P$9IQRaTwmfeRo7ud9Fh4E2PdI0S3r.L0",
+					pass: "test12345",
+				}, {
+					name: "keycloak-pbkdf2",
+					hash: `{"secretData":{"value":"QvEGxi97vkYNn6fjg3u+Y5XyQc4=","salt":"MDEyMzQ1Njc4OWFiY2RlZg=="},"credentialData":{"hashIterations":1000,"algorithm":"pbkdf2"}}`,
+					pass: "test",
+				}, {
+					name: "hmac-sha256",
+					hash: "$hmac-sha256$Aq+1YwSQLGVvy3N83QPeYgW7bUAdooEu/ZstNqCK8Vk=$a2V5",
+					pass: "test",
+				}, {
+					// Auth0 exports bcrypt hashes with the $2b$ prefix.
+					name: "auth0-bcrypt",
+					hash: "$2b$10$3loAOX854jhENynKKJKoQ.JnYtuFznx1eJfifrzpBcmKCsP1FcnoC",
+					pass: "123456",
 				},
 			} {
 				t.Run("hash="+tt.name, func(t *testing.T) {
@@ -841,7 +868,7 @@ func TestHandler(t *testing.T) {
 				}
 
 				res := send(t, ts, "PATCH", "/identities/"+i.ID.String(), http.StatusBadRequest, &patch)
-				assert.EqualValues(t, "The supplied state ('invalid-value') was not valid. Valid states are ('active', 'inactive').", res.Get("error.reason").String(), "%s", res.Raw)
+				assert.EqualValues(t, "The supplied state ('invalid-value') was not valid. Valid states are ('active', 'inactive', 'suspended', 'pending_deletion').", res.Get("error.reason").String(), "%s", res.Raw)
 
 				res = get(t, ts, "/identities/"+i.ID.String(), http.StatusOK)
 				// Assert that the schema ID is unchanged
@@ -1444,6 +1471,326 @@ func TestHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("case=should remove the login lockout of an identity", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceProtectionEnabled, true)
+		conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceMaxAttemptsIdentifier, 1)
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceProtectionEnabled, false)
+		})
+
+		for name, ts := range map[string]*httptest.Server{"public": publicTS, "admin": adminTS} {
+			t.Run("type=unknown identity/"+name, func(t *testing.T) {
+				remove(t, ts, "/identities/"+x.NewUUID().String()+"/login-lockout", http.StatusNotFound)
+			})
+
+			t.Run("type=locked identity/"+name, func(t *testing.T) {
+				email := x.NewUUID().String() + "@ory.sh"
+				i := identity.NewIdentity("")
+				i.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{
+					Type:        identity.CredentialsTypePassword,
+					Identifiers: []string{email},
+					Config:      sqlxx.JSONRawMessage(`{"hashed_password":"foo"}`),
+				})
+				require.NoError(t, reg.Persister().CreateIdentity(ctx, i))
+
+				r := httptest.NewRequest("POST", "/self-service/login", nil)
+				require.NoError(t, reg.LoginThrottler().RecordFailure(r, email))
+				require.NoError(t, reg.LoginThrottler().RecordFailure(r, i.ID.String()))
+				require.Error(t, reg.LoginThrottler().Check(r, email))
+				require.Error(t, reg.LoginThrottler().Check(r, i.ID.String()))
+
+				remove(t, ts, "/identities/"+i.ID.String()+"/login-lockout", http.StatusNoContent)
+
+				r = httptest.NewRequest("POST", "/self-service/login", nil)
+				r.RemoteAddr = "198.51.100.1:1234"
+				assert.NoError(t, reg.LoginThrottler().Check(r, email))
+				assert.NoError(t, reg.LoginThrottler().Check(r, i.ID.String()))
+			})
+		}
+	})
+
+	t.Run("case=should change the state of an identity", func(t *testing.T) {
+		for name, ts := range map[string]*httptest.Server{"public": publicTS, "admin": adminTS} {
+			t.Run("type=unknown identity/"+name, func(t *testing.T) {
+				send(t, ts, "PUT", "/identities/"+x.NewUUID().String()+"/state", http.StatusNotFound, &identity.StateChange{State: identity.StateInactive})
+			})
+
+			t.Run("type=suspend and reactivate/"+name, func(t *testing.T) {
+				i := identity.NewIdentity("")
+				require.NoError(t, reg.Persister().CreateIdentity(ctx, i))
+
+				res := send(t, ts, "PUT", "/identities/"+i.ID.String()+"/state", http.StatusBadRequest, &identity.StateChange{State: identity.StateSuspended})
+				assert.Contains(t, res.Get("error.reason").String(), "A reason is required", "%s", res.Raw)
+
+				until := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+				res = send(t, ts, "PUT", "/identities/"+i.ID.String()+"/state", http.StatusOK, &identity.StateChange{
+					State:          identity.StateSuspended,
+					Reason:         "fraud",
+					Note:           "Reported by the payment provider.",
+					SuspendedUntil: &until,
+				})
+				assert.EqualValues(t, identity.StateSuspended, res.Get("state").String(), "%s", res.Raw)
+				assert.EqualValues(t, "fraud", res.Get("state_reason").String(), "%s", res.Raw)
+				assert.EqualValues(t, "Reported by the payment provider.", res.Get("state_note").String(), "%s", res.Raw)
+				assert.True(t, until.Equal(res.Get("suspended_until").Time()), "%s", res.Raw)
+
+				res = send(t, ts, "PATCH", "/identities/"+i.ID.String(), http.StatusBadRequest, &[]patch{{"op": "replace", "path": "/state_reason", "value": "other"}})
+				assert.Contains(t, res.Get("error.message").String(), "state_reason", "%s", res.Raw)
+
+				res = send(t, ts, "PUT", "/identities/"+i.ID.String()+"/state", http.StatusOK, &identity.StateChange{State: identity.StateActive})
+				assert.EqualValues(t, identity.StateActive, res.Get("state").String(), "%s", res.Raw)
+				assert.False(t, res.Get("state_reason").Exists(), "%s", res.Raw)
+				assert.False(t, res.Get("suspended_until").Exists(), "%s", res.Raw)
+			})
+
+			t.Run("type=pending deletion/"+name, func(t *testing.T) {
+				i := identity.NewIdentity("")
+				require.NoError(t, reg.Persister().CreateIdentity(ctx, i))
+
+				res := send(t, ts, "PUT", "/identities/"+i.ID.String()+"/state", http.StatusOK, &identity.StateChange{State: identity.StatePendingDeletion, Reason: "user-request"})
+				assert.EqualValues(t, identity.StatePendingDeletion, res.Get("state").String(), "%s", res.Raw)
+				assert.WithinDuration(t, time.Now().Add(conf.IdentityDeletionGracePeriod(ctx)), res.Get("delete_after").Time(), time.Minute, "%s", res.Raw)
+			})
+		}
+	})
+
+	t.Run("case=should report the password hashes", func(t *testing.T) {
+		conf, reg := internal.NewFastRegistryWithMocks(t)
+		_, ts := testhelpers.NewKratosServerWithCSRF(t, reg)
+		testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/identity.schema.json")
+		conf.MustSet(ctx, config.ViperKeyHasherBcryptCost, 5)
+
+		for _, hashed := range []string{
+			"$2a$05$o6hx.Wog/wvFSkT/Bp/6DOxCtLRTDj7lm9on9suF/WaCGNVHbkfL6",
+			"$2a$05$o6hx.Wog/wvFSkT/Bp/6DOxCtLRTDj7lm9on9suF/WaCGNVHbkfL6",
+			"$2a$04$o6hx.Wog/wvFSkT/Bp/6DOxCtLRTDj7lm9on9suF/WaCGNVHbkfL6",
+			"$pbkdf2-sha256$i=100000,l=32$1jP+5Zxpxgtee/iPxGgOz0RfE9/KJuDElP1ley4VxXc$QJxzfvdbHYBpydCbHoFg3GJEqMFULwskiuqiJctoYpI",
+		} {
+			i := identity.NewIdentity("")
+			i.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{
+				Type:        identity.CredentialsTypePassword,
+				Identifiers: []string{x.NewUUID().String()},
+				Config:      sqlxx.JSONRawMessage(`{"hashed_password":"` + hashed + `"}`),
+			})
+			require.NoError(t, reg.Persister().CreateIdentity(ctx, i))
+		}
+
+		res := get(t, ts, "/password-hashes", http.StatusOK)
+		assert.EqualValues(t, 4, res.Get("total").Int(), "%s", res.Raw)
+		assert.EqualValues(t, 2, res.Get("outdated").Int(), "%s", res.Raw)
+		assert.JSONEq(t, `[
+	{"algorithm": "bcrypt", "parameters": "cost=5", "count": 2, "outdated": false},
+	{"algorithm": "bcrypt", "parameters": "cost=4", "count": 1, "outdated": true},
+	{"algorithm": "pbkdf2-sha256", "parameters": "i=100000,l=32", "count": 1, "outdated": true}
+]`, res.Get("hashes").Raw)
+	})
+
+	t.Run("case=should filter identities", func(t *testing.T) {
+		conf, reg := internal.NewFastRegistryWithMocks(t)
+		_, ts := testhelpers.NewKratosServerWithCSRF(t, reg)
+		testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/identity.schema.json")
+
+		for k, traits := range []string{
+			`{"email":"filter-alice@ory.sh","bar":"ory"}`,
+			`{"email":"filter-bob@ory.sh","bar":"ory"}`,
+			`{"email":"filter-carol@example.org","bar":"acme"}`,
+		} {
+			i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+			i.Traits = identity.Traits(traits)
+			if k == 2 {
+				i.State = identity.StateInactive
+			}
+			require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(ctx, i))
+		}
+
+		emails := func(res gjson.Result) []string {
+			var emails []string
+			for _, i := range res.Array() {
+				emails = append(emails, i.Get("traits.email").String())
+			}
+			sort.Strings(emails)
+			return emails
+		}
+
+		for _, tc := range []struct {
+			query    string
+			expected []string
+		}{
+			{query: "trait=bar:ory", expected: []string{"filter-alice@ory.sh", "filter-bob@ory.sh"}},
+			{query: "trait=traits.email:filter-a*", expected: []string{"filter-alice@ory.sh"}},
+			{query: "trait=bar:ory&trait=email:filter-b*", expected: []string{"filter-bob@ory.sh"}},
+			{query: "state=inactive", expected: []string{"filter-carol@example.org"}},
+			{query: "schema_id=default&state=active", expected: []string{"filter-alice@ory.sh", "filter-bob@ory.sh"}},
+			{query: "created_after=2000-01-01T00:00:00Z&trait=bar:acme", expected: []string{"filter-carol@example.org"}},
+			{query: "created_before=2000-01-01T00:00:00Z"},
+		} {
+			t.Run("query="+tc.query, func(t *testing.T) {
+				body, res := getFull(t, ts, "/identities?"+tc.query, http.StatusOK)
+				assert.Equal(t, tc.expected, emails(body), "%s", body.Raw)
+				assert.Contains(t, res.Header.Get("Link"), "page_token=00000000-0000-0000-0000-000000000000")
+			})
+		}
+
+		t.Run("case=should paginate filtered identities", func(t *testing.T) {
+			var found []string
+			path := "/identities?trait=email:filter-*&page_size=1"
+			for path != "" {
+				body, res := getFull(t, ts, path, http.StatusOK)
+				require.LessOrEqual(t, len(body.Array()), 1)
+				found = append(found, emails(body)...)
+
+				path = ""
+				for _, link := range linkheader.ParseMultiple(res.Header.Values("Link")) {
+					if link.Rel == "next" {
+						next, err := url.Parse(link.URL)
+						require.NoError(t, err)
+						path = "/identities?" + next.RawQuery
+					}
+				}
+			}
+			sort.Strings(found)
+			assert.Equal(t, []string{"filter-alice@ory.sh", "filter-bob@ory.sh", "filter-carol@example.org"}, found)
+		})
+
+		for _, query := range []string{
+			"trait=email",
+			"trait=em%20ail:foo",
+			"state=unknown",
+			"verified=maybe",
+			"created_after=yesterday",
+			"trait=bar:ory&page=2",
+			"trait=bar:ory&page_token=eyJvZmZzZXQiOiIxMCIsInYiOjJ9",
+		} {
+			t.Run("case=should reject query="+query, func(t *testing.T) {
+				get(t, ts, "/identities?"+query, http.StatusBadRequest)
+			})
+		}
+	})
+
+	t.Run("case=should export identities", func(t *testing.T) {
+		exportIdentities := func(t *testing.T, query string) (lines []gjson.Result) {
+			res, err := adminTS.Client().Get(adminTS.URL + "/export/identities?" + query)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "application/x-ndjson", res.Header.Get("Content-Type"))
+
+			scanner := bufio.NewScanner(res.Body)
+			for scanner.Scan() {
+				line := gjson.ParseBytes(scanner.Bytes())
+				if strings.HasPrefix(line.Get("traits.username").String(), "export-") {
+					lines = append(lines, line)
+				}
+			}
+			require.NoError(t, scanner.Err())
+			return lines
+		}
+
+		originals := map[string]gjson.Result{}
+		for k := 0; k < 3; k++ {
+			created := send(t, adminTS, "POST", "/identities", http.StatusCreated, validCreateIdentityBody("export", k))
+			res := get(t, adminTS, "/identities/"+created.Get("id").String()+"?include_credential=password", http.StatusOK)
+			originals[res.Get("traits.username").String()] = res
+		}
+
+		t.Run("case=should omit credentials by default", func(t *testing.T) {
+			lines := exportIdentities(t, "")
+			require.Len(t, lines, len(originals))
+			for _, line := range lines {
+				assert.Equal(t, "null", line.Get("credentials").Raw, "%s", line.Raw)
+				assert.Equal(t, "null", line.Get("verifiable_addresses").Raw, "%s", line.Raw)
+			}
+		})
+
+		t.Run("case=should round-trip through the create endpoint", func(t *testing.T) {
+			lines := exportIdentities(t, "include_credentials=true&include_addresses=true")
+			require.Len(t, lines, len(originals))
+
+			for _, line := range lines {
+				original := originals[line.Get("traits.username").String()]
+				require.True(t, original.Exists(), "%s", line.Raw)
+				remove(t, adminTS, "/identities/"+original.Get("id").String(), http.StatusNoContent)
+
+				created := send(t, adminTS, "POST", "/identities", http.StatusCreated, json.RawMessage(line.Raw))
+				res := get(t, adminTS, "/identities/"+created.Get("id").String()+"?include_credential=password", http.StatusOK)
+
+				for _, path := range []string{"schema_id", "state", "traits", "metadata_public", "metadata_admin", "credentials.password.identifiers"} {
+					assert.JSONEq(t, original.Get(path).Raw, res.Get(path).Raw, "%s", path)
+				}
+				assert.Equal(t, original.Get("credentials.password.config.hashed_password").String(), res.Get("credentials.password.config.hashed_password").String())
+				for _, path := range []string{"verifiable_addresses.#.value", "verifiable_addresses.#.verified", "verifiable_addresses.#.status", "recovery_addresses.#.value"} {
+					assert.ElementsMatch(t, original.Get(path).Value(), res.Get(path).Value(), "%s", path)
+				}
+			}
+		})
+
+		t.Run("case=should reject invalid flags", func(t *testing.T) {
+			get(t, adminTS, "/export/identities?include_credentials=maybe", http.StatusBadRequest)
+		})
+	})
+
+	t.Run("case=should migrate identities to another schema", func(t *testing.T) {
+		source := testhelpers.UseIdentitySchema(t, conf, "base64://"+base64.StdEncoding.EncodeToString([]byte(schemaMigrationSourceSchema)))
+		target := testhelpers.UseIdentitySchema(t, conf, "base64://"+base64.StdEncoding.EncodeToString([]byte(schemaMigrationTargetSchema)))
+
+		migrated := send(t, adminTS, "POST", "/identities", http.StatusCreated, json.RawMessage(`{"schema_id":"`+source+`","traits":{"email":"schema-migration-0@ory.sh","first_name":"Ada","last_name":"Lovelace"}}`))
+		failed := send(t, adminTS, "POST", "/identities", http.StatusCreated, json.RawMessage(`{"schema_id":"`+source+`","traits":{"email":"schema-migration-1@ory.sh","first_name":1234,"last_name":"Liskov"}}`))
+		t.Cleanup(func() {
+			remove(t, adminTS, "/identities/"+migrated.Get("id").String(), http.StatusNoContent)
+			remove(t, adminTS, "/identities/"+failed.Get("id").String(), http.StatusNoContent)
+		})
+
+		body := func(dryRun bool) *identity.CreateSchemaMigrationBody {
+			return &identity.CreateSchemaMigrationBody{SourceSchemaID: source, TargetSchemaID: target, Transform: schemaMigrationTransform, DryRun: dryRun}
+		}
+		waitFor := func(t *testing.T, id string) gjson.Result {
+			var res gjson.Result
+			require.Eventually(t, func() bool {
+				res = get(t, adminTS, "/identity-schema-migrations/"+id, http.StatusOK)
+				return res.Get("state").String() == string(identity.SchemaMigrationStateCompleted)
+			}, 10*time.Second, 50*time.Millisecond)
+			return res
+		}
+
+		t.Run("case=should reject invalid migrations", func(t *testing.T) {
+			invalid := body(false)
+			invalid.TargetSchemaID = source
+			_ = send(t, adminTS, "POST", "/identity-schema-migrations", http.StatusBadRequest, invalid)
+			_ = send(t, adminTS, "POST", "/identity-schema-migrations", http.StatusBadRequest, json.RawMessage(`{"unknown":true}`))
+		})
+
+		t.Run("case=should return 404 for unknown migrations", func(t *testing.T) {
+			id := x.NewUUID().String()
+			_ = get(t, adminTS, "/identity-schema-migrations/"+id, http.StatusNotFound)
+			_ = get(t, adminTS, "/identity-schema-migrations/"+id+"/failures", http.StatusNotFound)
+			_ = send(t, adminTS, "POST", "/identity-schema-migrations/"+id+"/resume", http.StatusNotFound, nil)
+		})
+
+		for _, dryRun := range []bool{true, false} {
+			t.Run(fmt.Sprintf("dry_run=%v", dryRun), func(t *testing.T) {
+				created := send(t, adminTS, "POST", "/identity-schema-migrations", http.StatusCreated, body(dryRun))
+				res := waitFor(t, created.Get("id").String())
+				assert.EqualValues(t, 2, res.Get("processed").Int(), "%s", res.Raw)
+				assert.EqualValues(t, 1, res.Get("migrated").Int(), "%s", res.Raw)
+				assert.EqualValues(t, 1, res.Get("failed").Int(), "%s", res.Raw)
+
+				failures := get(t, adminTS, "/identity-schema-migrations/"+created.Get("id").String()+"/failures", http.StatusOK)
+				require.Len(t, failures.Array(), 1, "%s", failures.Raw)
+				assert.Equal(t, failed.Get("id").String(), failures.Get("0.identity_id").String())
+				assert.NotEmpty(t, failures.Get("0.reason").String())
+
+				_ = send(t, adminTS, "POST", "/identity-schema-migrations/"+created.Get("id").String()+"/resume", http.StatusConflict, nil)
+
+				expected := source
+				if !dryRun {
+					expected = target
+				}
+				i := get(t, adminTS, "/identities/"+migrated.Get("id").String(), http.StatusOK)
+				assert.Equal(t, expected, i.Get("schema_id").String(), "%s", i.Raw)
+			})
+		}
+	})
+
 	t.Run("case=should paginate all identities", func(t *testing.T) {
 		// Start new server
 		conf, reg := internal.NewFastRegistryWithMocks(t)
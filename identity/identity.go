@@ -20,6 +20,7 @@ import (
 	"github.com/ory/kratos/cipher"
 
 	"github.com/ory/herodot"
+	"github.com/ory/x/pagination/keysetpagination"
 	"github.com/ory/x/sqlxx"
 
 	"github.com/ory/kratos/driver/config"
@@ -30,7 +31,7 @@ import (
 
 // An Identity's State
 //
-// The state can either be `active` or `inactive`.
+// The state can either be `active`, `inactive`, `suspended`, or `pending_deletion`.
 //
 // swagger:model identityState
 type State string
@@ -38,11 +39,17 @@ type State string
 const (
 	StateActive   State = "active"
 	StateInactive State = "inactive"
+
+	// StateSuspended identities can not sign in until they are reactivated or until their suspension ends.
+	StateSuspended State = "suspended"
+
+	// StatePendingDeletion identities can not sign in and are deleted once their deletion grace period passed.
+	StatePendingDeletion State = "pending_deletion"
 )
 
 func (lt State) IsValid() error {
 	switch lt {
-	case StateActive, StateInactive:
+	case StateActive, StateInactive, StateSuspended, StatePendingDeletion:
 		return nil
 	}
 	return errors.New("identity state is not valid")
@@ -83,12 +90,26 @@ type Identity struct {
 
 	// State is the identity's state.
 	//
-	// This value has currently no effect.
+	// Identities which are not active can not sign in.
 	State State `json:"state" faker:"-" db:"state"`
 
 	// StateChangedAt contains the last time when the identity's state changed.
 	StateChangedAt *sqlxx.NullTime `json:"state_changed_at,omitempty" faker:"-" db:"state_changed_at"`
 
+	// StateReason is the reason code of the identity's state, for example `fraud` or `chargeback` for a
+	// suspended identity. It is only accessible through admin APIs.
+	StateReason string `json:"state_reason,omitempty" faker:"-" db:"state_reason"`
+
+	// StateNote is a note about the identity's state which is only accessible through admin APIs.
+	StateNote string `json:"state_note,omitempty" faker:"-" db:"state_note"`
+
+	// SuspendedUntil is the time after which a suspended identity is reactivated. If it is not set, the identity
+	// stays suspended until it is reactivated through the admin API.
+	SuspendedUntil *sqlxx.NullTime `json:"suspended_until,omitempty" faker:"-" db:"suspended_until"`
+
+	// DeleteAfter is the time after which an identity pending deletion is deleted.
+	DeleteAfter *sqlxx.NullTime `json:"delete_after,omitempty" faker:"-" db:"delete_after"`
+
 	// Traits represent an identity's traits. The identity is able to create, modify, and delete traits
 	// in a self-service manner. The input will always be validated against the JSON Schema defined
 	// in `schema_url`.
@@ -119,6 +140,9 @@ type Identity struct {
 	// Store metadata about the user which is only accessible through admin APIs such as `GET /admin/identities/<id>`.
 	MetadataAdmin sqlxx.NullJSONRawMessage `json:"metadata_admin,omitempty" faker:"-" db:"metadata_admin"`
 
+	// OrganizationID is the ID of the organization the identity belongs to.
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty" faker:"-" db:"organization_id"`
+
 	// CreatedAt is a helper struct field for gobuffalo.pop.
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 
@@ -175,7 +199,8 @@ func (i *Identity) lock() *sync.RWMutex {
 }
 
 func (i *Identity) IsActive() bool {
-	return i.State == StateActive
+	// A suspension which ended is treated as lifted even if the identity was not reactivated yet.
+	return i.State == StateActive || (i.State == StateSuspended && i.SuspendedUntil != nil && time.Time(*i.SuspendedUntil).Before(time.Now()))
 }
 
 func (i *Identity) SetCredentials(t CredentialsType, c Credentials) {
@@ -263,6 +288,35 @@ func (i *Identity) ParseCredentials(t CredentialsType, config interface{}) (*Cre
 	return nil, herodot.ErrNotFound.WithReasonf("identity does not have credential type %s", t)
 }
 
+// VerifiedAddresses returns the values of the identity's verified addresses.
+func (i *Identity) VerifiedAddresses() []string {
+	addresses := make([]string, 0, len(i.VerifiableAddresses))
+	for _, a := range i.VerifiableAddresses {
+		if a.Verified {
+			addresses = append(addresses, a.Value)
+		}
+	}
+	return addresses
+}
+
+// UnverifiedAddresses returns the values of the identity's unverified and recovery addresses as well as the
+// identifiers of its password credentials. The identity claims these addresses, but has not proven to own them.
+func (i *Identity) UnverifiedAddresses() []string {
+	addresses := make([]string, 0, len(i.VerifiableAddresses)+len(i.RecoveryAddresses))
+	for _, a := range i.VerifiableAddresses {
+		if !a.Verified {
+			addresses = append(addresses, a.Value)
+		}
+	}
+	for _, a := range i.RecoveryAddresses {
+		addresses = append(addresses, a.Value)
+	}
+	if c, ok := i.GetCredentials(CredentialsTypePassword); ok {
+		addresses = append(addresses, c.Identifiers...)
+	}
+	return addresses
+}
+
 func (i *Identity) CopyWithoutCredentials() *Identity {
 	i.lock().RLock()
 	defer i.lock().RUnlock()
@@ -298,10 +352,20 @@ func (i Identity) GetNID() uuid.UUID {
 	return i.NID
 }
 
+func (i Identity) PageToken() keysetpagination.PageToken {
+	return keysetpagination.StringPageToken(i.ID.String())
+}
+
+func (i Identity) DefaultPageToken() keysetpagination.PageToken {
+	return keysetpagination.StringPageToken(uuid.Nil.String())
+}
+
 func (i Identity) MarshalJSON() ([]byte, error) {
 	type localIdentity Identity
 	i.Credentials = nil
 	i.MetadataAdmin = nil
+	i.StateReason = ""
+	i.StateNote = ""
 	result, err := json.Marshal(localIdentity(i))
 	if err != nil {
 		return nil, err
@@ -314,6 +378,8 @@ func (i *Identity) UnmarshalJSON(b []byte) error {
 	err := json.Unmarshal(b, (*localIdentity)(i))
 	i.Credentials = nil
 	i.MetadataAdmin = nil
+	i.StateReason = ""
+	i.StateNote = ""
 	return err
 }
 
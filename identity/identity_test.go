@@ -142,6 +142,27 @@ func TestMarshalIgnoresAdminMetadata(t *testing.T) {
 	require.NotEmpty(t, i.MetadataPublic)
 }
 
+func TestMarshalIgnoresStateReasonAndNote(t *testing.T) {
+	i := NewIdentity(config.DefaultIdentityTraitsSchemaID)
+	i.State = StateSuspended
+	i.StateReason = "fraud"
+	i.StateNote = "Reported by the payment provider."
+
+	var b bytes.Buffer
+	require.Nil(t, json.NewEncoder(&b).Encode(&i))
+	assert.False(t, gjson.Get(b.String(), "state_reason").Exists(), "The state reason should not be rendered to json but got: %s", b.String())
+	assert.False(t, gjson.Get(b.String(), "state_note").Exists(), "The state note should not be rendered to json but got: %s", b.String())
+	assert.Equal(t, "suspended", gjson.Get(b.String(), "state").String())
+
+	b.Reset()
+	require.Nil(t, json.NewEncoder(&b).Encode(WithAdminMetadataInJSON(*i)))
+	assert.Equal(t, "fraud", gjson.Get(b.String(), "state_reason").String(), "%s", b.String())
+	assert.Equal(t, "Reported by the payment provider.", gjson.Get(b.String(), "state_note").String(), "%s", b.String())
+
+	// To ensure the original identity is not changed / Marshal has no side effects:
+	require.Equal(t, "fraud", i.StateReason)
+}
+
 func TestUnMarshallIgnoresCredentials(t *testing.T) {
 	jsonText := "{\"id\":\"3234ad11-49c6-49e2-bfac-537f3e06cd85\",\"schema_id\":\"default\",\"schema_url\":\"\",\"traits\":{}, \"credentials\" : {\"password\":{\"type\":\"\",\"identifiers\":null,\"config\":null,\"updatedAt\":\"0001-01-01T00:00:00Z\"}}}"
 	var i Identity
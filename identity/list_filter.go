@@ -0,0 +1,65 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+)
+
+var traitPathSegment = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// TraitFilter matches identities whose trait at the given path is equal to, or starts with, the given value.
+type TraitFilter struct {
+	// Path is the path of the trait, for example `["email"]` or `["address", "city"]`.
+	Path []string
+
+	// Value is the string the trait is compared with.
+	Value string
+
+	// Prefix is true if the trait only has to start with the value.
+	Prefix bool
+}
+
+// ParseTraitFilter parses a trait filter in the format `<path>:<value>`, for example `email:foo@ory.sh` or
+// `address.city:Munich`. If the value ends with `*`, all traits starting with the value match, for example
+// `email:foo@*`.
+func ParseTraitFilter(raw string) (*TraitFilter, error) {
+	path, value, ok := strings.Cut(raw, ":")
+	if !ok {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The trait filter %q must be in the format <path>:<value>.", raw))
+	}
+
+	f := &TraitFilter{Path: strings.Split(strings.TrimPrefix(path, "traits."), "."), Value: value}
+	for _, segment := range f.Path {
+		if !traitPathSegment.MatchString(segment) {
+			return nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The trait path %q of filter %q is invalid. Path segments may only contain letters, digits, dashes and underscores.", path, raw))
+		}
+	}
+
+	if strings.HasSuffix(value, "*") {
+		f.Value = strings.TrimSuffix(value, "*")
+		f.Prefix = true
+	}
+
+	return f, nil
+}
+
+// HasFilters returns true if any filter besides the credentials identifier is set.
+func (p ListIdentityParameters) HasFilters() bool {
+	return len(p.Traits) > 0 ||
+		p.State != "" ||
+		p.SchemaID != "" ||
+		p.CredentialsType != "" ||
+		p.Verified != nil ||
+		p.CreatedAfter != nil ||
+		p.CreatedBefore != nil ||
+		p.UpdatedAfter != nil ||
+		p.UpdatedBefore != nil ||
+		p.StateExpiredBefore != nil
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/ory/x/errorsx"
 
 	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/schema"
 )
 
 var ErrProtectedFieldModified = herodot.ErrForbidden.
@@ -40,7 +41,25 @@ type (
 		x.TracingProvider
 		courier.Provider
 		ValidationProvider
+		schema.IdentityTraitsProvider
 		ActiveCredentialsCounterStrategyProvider
+		SessionRevokerProvider
+	}
+	// CacheInvalidator drops cached data derived from an identity, such as cached sessions, once the identity
+	// changed. The identity persister calls it whenever it updates or deletes an identity or one of its addresses.
+	CacheInvalidator interface {
+		InvalidateIdentity(ctx context.Context, id uuid.UUID) error
+	}
+	CacheInvalidatorProvider interface {
+		IdentityCacheInvalidator() CacheInvalidator
+	}
+	// SessionRevoker revokes the sessions of an identity, for example once the identity was suspended.
+	SessionRevoker interface {
+		// RevokeSessionsIdentityExcept revokes all sessions of the identity except the given one.
+		RevokeSessionsIdentityExcept(ctx context.Context, iID, sID uuid.UUID) (int, error)
+	}
+	SessionRevokerProvider interface {
+		IdentitySessionRevoker() SessionRevoker
 	}
 	ManagementProvider interface {
 		IdentityManager() *Manager
@@ -61,6 +80,13 @@ func NewManager(r managerDependencies) *Manager {
 	return &Manager{r: r}
 }
 
+// NoopCacheInvalidator is used when nothing derived from identities is cached.
+type NoopCacheInvalidator struct{}
+
+func (NoopCacheInvalidator) InvalidateIdentity(context.Context, uuid.UUID) error {
+	return nil
+}
+
 func ManagerExposeValidationErrorsForInternalTypeAssertion(options *ManagerOptions) {
 	options.ExposeValidationErrors = true
 }
@@ -85,6 +111,10 @@ func (m *Manager) Create(ctx context.Context, i *Identity, opts ...ManagerOption
 		i.SchemaID = m.r.Config().DefaultIdentityTraitsSchemaID(ctx)
 	}
 
+	if err := m.requireAssignableSchema(ctx, i.SchemaID); err != nil {
+		return err
+	}
+
 	o := newManagerOptions(opts)
 	if err := m.ValidateIdentity(ctx, i, o); err != nil {
 		return err
@@ -107,6 +137,10 @@ func (m *Manager) CreateIdentities(ctx context.Context, identities []*Identity,
 			i.SchemaID = m.r.Config().DefaultIdentityTraitsSchemaID(ctx)
 		}
 
+		if err := m.requireAssignableSchema(ctx, i.SchemaID); err != nil {
+			return err
+		}
+
 		o := newManagerOptions(opts)
 		if err := m.ValidateIdentity(ctx, i, o); err != nil {
 			return err
@@ -164,7 +198,22 @@ func (m *Manager) Update(ctx context.Context, updated *Identity, opts ...Manager
 		return err
 	}
 
-	return m.r.PrivilegedIdentityPool().UpdateIdentity(ctx, updated)
+	if original.SchemaID != updated.SchemaID {
+		if err := m.requireAssignableSchema(ctx, updated.SchemaID); err != nil {
+			return err
+		}
+	}
+
+	if err := m.r.PrivilegedIdentityPool().UpdateIdentity(ctx, updated); err != nil {
+		return err
+	}
+
+	if original.State != updated.State && updated.State.revokesSessions() {
+		if _, err := m.r.IdentitySessionRevoker().RevokeSessionsIdentityExcept(ctx, updated.ID, uuid.Nil); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (m *Manager) UpdateSchemaID(ctx context.Context, id uuid.UUID, schemaID string, opts ...ManagerOption) (err error) {
@@ -181,6 +230,12 @@ func (m *Manager) UpdateSchemaID(ctx context.Context, id uuid.UUID, schemaID str
 		return errors.WithStack(ErrProtectedFieldModified)
 	}
 
+	if original.SchemaID != schemaID {
+		if err := m.requireAssignableSchema(ctx, schemaID); err != nil {
+			return err
+		}
+	}
+
 	original.SchemaID = schemaID
 	if err := m.ValidateIdentity(ctx, original, o); err != nil {
 		return err
@@ -241,6 +296,20 @@ func (m *Manager) ValidateIdentity(ctx context.Context, i *Identity, o *ManagerO
 	return nil
 }
 
+// requireAssignableSchema returns an error if the identity schema is deprecated, because deprecated
+// schemas can only be used by the identities which already use them.
+func (m *Manager) requireAssignableSchema(ctx context.Context, schemaID string) error {
+	s, err := m.r.IdentityTraitsSchema(ctx, schemaID)
+	if err != nil {
+		return err
+	}
+
+	if s.Deprecated {
+		return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The identity schema %s is deprecated and can no longer be assigned to identities.", schemaID))
+	}
+	return nil
+}
+
 func (m *Manager) CountActiveFirstFactorCredentials(ctx context.Context, i *Identity) (count int, err error) {
 	ctx, span := m.r.Tracer(ctx).Tracer().Start(ctx, "identity.Manager.CountActiveFirstFactorCredentials")
 	defer otelx.End(span, &err)
@@ -5,7 +5,9 @@ package identity
 
 import (
 	"context"
+	"time"
 
+	"github.com/ory/x/pagination/keysetpagination"
 	"github.com/ory/x/sqlxx"
 
 	"github.com/gofrs/uuid"
@@ -15,13 +17,35 @@ type (
 	ListIdentityParameters struct {
 		Expand                Expandables
 		CredentialsIdentifier string
-		Page                  int
-		PerPage               int
+
+		// Page and PerPage are used for offset pagination, unless KeySetPagination is set.
+		Page    int
+		PerPage int
+
+		// KeySetPagination paginates the identities ordered by their ID. If set, Page and PerPage are ignored.
+		KeySetPagination []keysetpagination.Option
+
+		// Traits, State, SchemaID, CredentialsType, Verified and the time ranges filter the identities. All filters
+		// must match.
+		Traits          []TraitFilter
+		State           State
+		SchemaID        string
+		CredentialsType CredentialsType
+		Verified        *bool
+		CreatedAfter    *time.Time
+		CreatedBefore   *time.Time
+		UpdatedAfter    *time.Time
+		UpdatedBefore   *time.Time
+
+		// StateExpiredBefore lists only suspended identities whose suspension ended, and identities pending
+		// deletion whose deletion grace period passed, before the given time.
+		StateExpiredBefore *time.Time
 	}
 
 	Pool interface {
-		// ListIdentities lists all identities in the store given the page and itemsPerPage.
-		ListIdentities(ctx context.Context, params ListIdentityParameters) ([]Identity, error)
+		// ListIdentities lists the identities in the store matching the given parameters. The returned paginator
+		// points to the next page if keyset pagination is used and is nil otherwise.
+		ListIdentities(ctx context.Context, params ListIdentityParameters) ([]Identity, *keysetpagination.Paginator, error)
 
 		// CountIdentities counts the number of identities in the store.
 		CountIdentities(ctx context.Context) (int64, error)
@@ -84,5 +108,11 @@ type (
 
 		// InjectTraitsSchemaURL sets the identity's traits JSON schema URL from the schema's ID.
 		InjectTraitsSchemaURL(ctx context.Context, i *Identity) error
+
+		// WalkCredentials loads all credentials of the given type in batches ordered by their ID, starting after the
+		// given ID, and calls fn with every batch. The configs of the credentials returned by fn are updated in a
+		// single transaction before the next batch is loaded. Credentials whose config was changed since the batch
+		// was loaded are not updated, and their number is returned.
+		WalkCredentials(ctx context.Context, ct CredentialsType, after uuid.UUID, size int, fn func(ctx context.Context, credentials []Credentials) (updated []Credentials, err error)) (skipped int, err error)
 	}
 )
@@ -0,0 +1,408 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/herodot"
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/x/jsonnetsecure"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/pagination/keysetpagination"
+
+	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/x"
+)
+
+const (
+	// DefaultSchemaMigrationBatchSize is the number of identities loaded at once when migrating identities to
+	// another identity schema.
+	DefaultSchemaMigrationBatchSize = 100
+
+	// SchemaMigrationStaleAfter is the time after which a running schema migration which did not report any
+	// progress is considered interrupted and can be resumed.
+	SchemaMigrationStaleAfter = 5 * time.Minute
+)
+
+// SchemaMigrationState is the state of an identity schema migration.
+//
+// swagger:enum SchemaMigrationState
+type SchemaMigrationState string
+
+const (
+	SchemaMigrationStatePending   SchemaMigrationState = "pending"
+	SchemaMigrationStateRunning   SchemaMigrationState = "running"
+	SchemaMigrationStateCompleted SchemaMigrationState = "completed"
+	SchemaMigrationStateFailed    SchemaMigrationState = "failed"
+)
+
+type (
+	// Identity Schema Migration
+	//
+	// An identity schema migration moves all identities of the source identity schema to the target identity
+	// schema and rewrites their traits using a Jsonnet transform.
+	//
+	// swagger:model identitySchemaMigration
+	SchemaMigration struct {
+		// ID is the identity schema migration's ID.
+		//
+		// required: true
+		ID uuid.UUID `json:"id" faker:"-" db:"id"`
+
+		// SourceSchemaID is the ID of the identity schema the identities are migrated from.
+		//
+		// required: true
+		SourceSchemaID string `json:"source_schema_id" db:"source_schema_id"`
+
+		// TargetSchemaID is the ID of the identity schema the identities are migrated to.
+		//
+		// required: true
+		TargetSchemaID string `json:"target_schema_id" db:"target_schema_id"`
+
+		// Transform is the Jsonnet code which rewrites the traits. The identity is available as
+		// `std.extVar('identity')` and the transform must return an object with the new traits in the
+		// `traits` key.
+		//
+		// required: true
+		Transform string `json:"transform" db:"transform"`
+
+		// DryRun only transforms and validates the traits without updating the identities.
+		//
+		// required: true
+		DryRun bool `json:"dry_run" db:"dry_run"`
+
+		// BatchSize is the number of identities migrated at once.
+		//
+		// required: true
+		BatchSize int `json:"batch_size" db:"batch_size"`
+
+		// State is the state of the migration.
+		//
+		// required: true
+		State SchemaMigrationState `json:"state" db:"state"`
+
+		// Cursor is the ID of the last processed identity. An interrupted migration resumes after it.
+		//
+		// required: true
+		Cursor uuid.UUID `json:"cursor" faker:"-" db:"last_identity_id"`
+
+		// Processed is the number of processed identities.
+		//
+		// required: true
+		Processed int `json:"processed" db:"processed"`
+
+		// Migrated is the number of identities which were migrated, or which would have been migrated in a
+		// dry run.
+		//
+		// required: true
+		Migrated int `json:"migrated" db:"migrated"`
+
+		// Failed is the number of identities which could not be migrated.
+		//
+		// required: true
+		Failed int `json:"failed" db:"failed"`
+
+		// Error is the error which stopped a failed migration.
+		Error string `json:"error,omitempty" db:"last_error"`
+
+		// CreatedAt is a helper struct field for gobuffalo.pop.
+		CreatedAt time.Time `json:"created_at" faker:"-" db:"created_at"`
+
+		// UpdatedAt is a helper struct field for gobuffalo.pop.
+		UpdatedAt time.Time `json:"updated_at" faker:"-" db:"updated_at"`
+		NID       uuid.UUID `json:"-" faker:"-" db:"nid"`
+	}
+
+	// Identity Schema Migration Failure
+	//
+	// An identity which could not be migrated to the target identity schema.
+	//
+	// swagger:model identitySchemaMigrationFailure
+	SchemaMigrationFailure struct {
+		// IdentityID is the ID of the identity which could not be migrated.
+		//
+		// required: true
+		IdentityID uuid.UUID `json:"identity_id" faker:"-" db:"identity_id"`
+
+		// Reason explains why the identity could not be migrated, for example because the transformed traits
+		// are not valid for the target identity schema.
+		//
+		// required: true
+		Reason string `json:"reason" db:"reason"`
+
+		// CreatedAt is a helper struct field for gobuffalo.pop.
+		CreatedAt   time.Time `json:"created_at" faker:"-" db:"created_at"`
+		ID          uuid.UUID `json:"-" faker:"-" db:"id"`
+		MigrationID uuid.UUID `json:"-" faker:"-" db:"migration_id"`
+		NID         uuid.UUID `json:"-" faker:"-" db:"nid"`
+	}
+
+	SchemaMigrationPersister interface {
+		CreateSchemaMigration(ctx context.Context, m *SchemaMigration) error
+		GetSchemaMigration(ctx context.Context, id uuid.UUID) (*SchemaMigration, error)
+
+		// ClaimSchemaMigration marks the migration as running. Returns herodot.ErrConflict if the migration is
+		// completed, or if it is running and was updated after staleBefore.
+		ClaimSchemaMigration(ctx context.Context, id uuid.UUID, staleBefore time.Time) error
+
+		// UpdateSchemaMigration stores the state and progress of the migration together with the failures
+		// recorded since the last update.
+		UpdateSchemaMigration(ctx context.Context, m *SchemaMigration, failures []SchemaMigrationFailure) error
+		ListSchemaMigrationFailures(ctx context.Context, id uuid.UUID, page, itemsPerPage int) ([]SchemaMigrationFailure, error)
+	}
+
+	SchemaMigrationPersistenceProvider interface {
+		IdentitySchemaMigrationPersister() SchemaMigrationPersister
+	}
+
+	schemaMigratorDependencies interface {
+		PrivilegedPoolProvider
+		ManagementProvider
+		ValidationProvider
+		SchemaMigrationPersistenceProvider
+		schema.IdentityTraitsProvider
+		jsonnetsecure.VMProvider
+		x.LoggingProvider
+		x.TracingProvider
+	}
+	SchemaMigratorProvider interface {
+		IdentitySchemaMigrator() *SchemaMigrator
+	}
+	// SchemaMigrator moves identities from one identity schema to another and rewrites their traits using a
+	// Jsonnet transform.
+	SchemaMigrator struct {
+		r schemaMigratorDependencies
+	}
+
+	SchemaMigrationOptions struct {
+		// Progress is called after every batch.
+		Progress func(m *SchemaMigration)
+	}
+
+	SchemaMigrationOption func(*SchemaMigrationOptions)
+)
+
+func (SchemaMigration) TableName(context.Context) string {
+	return "identity_schema_migrations"
+}
+
+func (SchemaMigrationFailure) TableName(context.Context) string {
+	return "identity_schema_migration_failures"
+}
+
+func NewSchemaMigrator(r schemaMigratorDependencies) *SchemaMigrator {
+	return &SchemaMigrator{r: r}
+}
+
+// SchemaMigrationWithProgress calls the given function after every batch.
+func SchemaMigrationWithProgress(f func(m *SchemaMigration)) SchemaMigrationOption {
+	return func(o *SchemaMigrationOptions) {
+		o.Progress = f
+	}
+}
+
+func newSchemaMigrationOptions(opts []SchemaMigrationOption) *SchemaMigrationOptions {
+	var o SchemaMigrationOptions
+	for _, f := range opts {
+		f(&o)
+	}
+	return &o
+}
+
+// Create validates and stores a new migration. The migration is started using Run.
+func (m *SchemaMigrator) Create(ctx context.Context, sm *SchemaMigration) (err error) {
+	ctx, span := m.r.Tracer(ctx).Tracer().Start(ctx, "identity.SchemaMigrator.Create")
+	defer otelx.End(span, &err)
+
+	if sm.SourceSchemaID == "" || sm.TargetSchemaID == "" {
+		return errors.WithStack(herodot.ErrBadRequest.WithReason("The source and the target identity schema must be set."))
+	} else if sm.SourceSchemaID == sm.TargetSchemaID {
+		return errors.WithStack(herodot.ErrBadRequest.WithReason("The source and the target identity schema must be different."))
+	} else if strings.TrimSpace(sm.Transform) == "" {
+		return errors.WithStack(herodot.ErrBadRequest.WithReason("The Jsonnet transform must be set."))
+	}
+
+	if sm.BatchSize == 0 {
+		sm.BatchSize = DefaultSchemaMigrationBatchSize
+	} else if sm.BatchSize < 0 {
+		return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The batch size must be positive but got %d.", sm.BatchSize))
+	}
+
+	if _, err := m.r.IdentityTraitsSchema(ctx, sm.SourceSchemaID); err != nil {
+		return err
+	}
+	target, err := m.r.IdentityTraitsSchema(ctx, sm.TargetSchemaID)
+	if err != nil {
+		return err
+	}
+	if target.Deprecated && !sm.DryRun {
+		return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The identity schema %s is deprecated and can no longer be assigned to identities.", sm.TargetSchemaID))
+	}
+
+	sm.State = SchemaMigrationStatePending
+	sm.Cursor = uuid.Nil
+	sm.Processed, sm.Migrated, sm.Failed = 0, 0, 0
+	sm.Error = ""
+	return m.r.IdentitySchemaMigrationPersister().CreateSchemaMigration(ctx, sm)
+}
+
+// Run migrates the identities of the migration in batches, starting after the migration's cursor. The progress is
+// stored after every batch, so that an interrupted or failed migration can be resumed by calling Run again.
+//
+// Identities whose traits can not be transformed or are not valid for the target identity schema are recorded as
+// failures and skipped. All other errors stop the migration and mark it as failed.
+func (m *SchemaMigrator) Run(ctx context.Context, id uuid.UUID, opts ...SchemaMigrationOption) (_ *SchemaMigration, err error) {
+	ctx, span := m.r.Tracer(ctx).Tracer().Start(ctx, "identity.SchemaMigrator.Run")
+	defer otelx.End(span, &err)
+
+	o := newSchemaMigrationOptions(opts)
+	p := m.r.IdentitySchemaMigrationPersister()
+
+	sm, err := p.GetSchemaMigration(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.ClaimSchemaMigration(ctx, id, time.Now().Add(-SchemaMigrationStaleAfter)); err != nil {
+		return sm, err
+	}
+	sm.State = SchemaMigrationStateRunning
+	sm.Error = ""
+
+	if failures, err := m.run(ctx, sm, o); err != nil {
+		sm.State = SchemaMigrationStateFailed
+		sm.Error = err.Error()
+		if err := p.UpdateSchemaMigration(ctx, sm, failures); err != nil {
+			m.r.Logger().WithError(err).WithField("migration_id", sm.ID).Error("Unable to store the state of the failed identity schema migration.")
+		}
+		return sm, err
+	}
+
+	sm.State = SchemaMigrationStateCompleted
+	return sm, p.UpdateSchemaMigration(ctx, sm, nil)
+}
+
+// run migrates the identities batch by batch. If it fails, it returns the failures which were not stored yet.
+func (m *SchemaMigrator) run(ctx context.Context, sm *SchemaMigration, o *SchemaMigrationOptions) ([]SchemaMigrationFailure, error) {
+	pagination := []keysetpagination.Option{
+		keysetpagination.WithToken(keysetpagination.StringPageToken(sm.Cursor.String())),
+		keysetpagination.WithSize(sm.BatchSize),
+	}
+	for {
+		// Migrated identities no longer use the source schema, but the cursor keeps them from being loaded again
+		// in a dry run.
+		is, next, err := m.r.PrivilegedIdentityPool().ListIdentities(ctx, ListIdentityParameters{
+			SchemaID:         sm.SourceSchemaID,
+			KeySetPagination: pagination,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var failures []SchemaMigrationFailure
+		for k := range is {
+			if err := m.migrate(ctx, sm, &is[k]); err != nil {
+				reason, ok := schemaMigrationFailureReason(err)
+				if !ok {
+					return failures, err
+				}
+				failures = append(failures, SchemaMigrationFailure{MigrationID: sm.ID, IdentityID: is[k].ID, Reason: reason})
+				sm.Failed++
+			} else {
+				sm.Migrated++
+			}
+			sm.Processed++
+			sm.Cursor = is[k].ID
+		}
+
+		if len(is) > 0 {
+			if err := m.r.IdentitySchemaMigrationPersister().UpdateSchemaMigration(ctx, sm, failures); err != nil {
+				return nil, err
+			}
+			if o.Progress != nil {
+				o.Progress(sm)
+			}
+		}
+		if next.IsLast() {
+			return nil, nil
+		}
+		pagination = next.ToOptions()
+	}
+}
+
+// migrate transforms the traits of the identity and validates them against the target identity schema. Unless the
+// migration is a dry run, the identity is then updated.
+func (m *SchemaMigrator) migrate(ctx context.Context, sm *SchemaMigration, i *Identity) error {
+	traits, err := m.transform(ctx, sm, i)
+	if err != nil {
+		return err
+	}
+
+	if sm.DryRun {
+		i.SchemaID = sm.TargetSchemaID
+		i.Traits = traits
+		return m.r.IdentityValidator().Validate(ctx, i)
+	}
+
+	// The listed identity does not include the credentials, which would be removed by the update.
+	full, err := m.r.PrivilegedIdentityPool().GetIdentityConfidential(ctx, i.ID)
+	if err != nil {
+		return err
+	}
+	full.SchemaID = sm.TargetSchemaID
+	full.Traits = traits
+	return m.r.IdentityManager().Update(ctx, full, ManagerAllowWriteProtectedTraits, ManagerExposeValidationErrorsForInternalTypeAssertion)
+}
+
+// transform evaluates the Jsonnet transform of the migration with the identity and returns the new traits.
+func (m *SchemaMigrator) transform(ctx context.Context, sm *SchemaMigration, i *Identity) (Traits, error) {
+	vm, err := m.r.JsonnetVM(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	identityJSON, err := json.Marshal(WithAdminMetadataInJSON(*i))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	vm.ExtCode("identity", string(identityJSON))
+
+	evaluated, err := vm.EvaluateAnonymousSnippet("transform.jsonnet", sm.Transform)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithWrap(err).WithReasonf("Unable to execute the Jsonnet transform: %s", err))
+	}
+
+	traits := gjson.Get(evaluated, "traits")
+	if !traits.IsObject() {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReason("Expected the Jsonnet transform to return an object with the new traits in the `traits` key."))
+	}
+	return Traits(traits.Raw), nil
+}
+
+// schemaMigrationFailureReason returns why the identity could not be migrated. It returns false if the error was not
+// caused by the identity, in which case the migration has to stop.
+func schemaMigrationFailureReason(err error) (string, bool) {
+	var validationErr *jsonschema.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Error(), true
+	}
+
+	var statusErr herodot.StatusCodeCarrier
+	if !errors.As(err, &statusErr) || statusErr.StatusCode() < 400 || statusErr.StatusCode() >= 500 {
+		return "", false
+	}
+
+	var reasonErr herodot.ReasonCarrier
+	if errors.As(err, &reasonErr) && reasonErr.Reason() != "" {
+		return reasonErr.Reason(), true
+	}
+	return err.Error(), true
+}
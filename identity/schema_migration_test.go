@@ -0,0 +1,215 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+)
+
+const (
+	schemaMigrationSourceSchema = `{
+  "type": "object",
+  "properties": {
+    "traits": {
+      "type": "object",
+      "properties": {
+        "email": {
+          "type": "string",
+          "ory.sh/kratos": {"credentials": {"password": {"identifier": true}}}
+        },
+        "first_name": {},
+        "last_name": {"type": "string"}
+      },
+      "required": ["email"]
+    }
+  }
+}`
+	schemaMigrationTargetSchema = `{
+  "type": "object",
+  "properties": {
+    "traits": {
+      "type": "object",
+      "properties": {
+        "email": {
+          "type": "string",
+          "ory.sh/kratos": {"credentials": {"password": {"identifier": true}}}
+        },
+        "name": {
+          "type": "object",
+          "properties": {
+            "first": {"type": "string"},
+            "last": {"type": "string"}
+          },
+          "required": ["first"]
+        }
+      },
+      "required": ["email", "name"]
+    }
+  }
+}`
+	schemaMigrationTransform = `local identity = std.extVar('identity');
+{
+  traits: {
+    email: identity.traits.email,
+    name: { first: identity.traits.first_name, last: identity.traits.last_name },
+  },
+}`
+)
+
+func TestSchemaMigrator(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	testhelpers.SetIdentitySchemas(t, conf, map[string]string{
+		config.DefaultIdentityTraitsSchemaID: "file://./stub/identity.schema.json",
+		"source":                             "base64://" + base64.StdEncoding.EncodeToString([]byte(schemaMigrationSourceSchema)),
+		"target":                             "base64://" + base64.StdEncoding.EncodeToString([]byte(schemaMigrationTargetSchema)),
+	})
+
+	var ids []uuid.UUID
+	failing := map[uuid.UUID]bool{}
+	for k, traits := range []string{
+		`{"email":"migrate-0@ory.sh","first_name":"Ada","last_name":"Lovelace"}`,
+		`{"email":"migrate-1@ory.sh","first_name":"Grace","last_name":"Hopper"}`,
+		`{"email":"migrate-2@ory.sh","last_name":"Turing"}`,
+		`{"email":"migrate-3@ory.sh","first_name":1234,"last_name":"Liskov"}`,
+		`{"email":"migrate-4@ory.sh","first_name":"Edsger","last_name":"Dijkstra"}`,
+	} {
+		i := identity.NewIdentity("source")
+		i.Traits = identity.Traits(traits)
+		i.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{
+			Type:   identity.CredentialsTypePassword,
+			Config: []byte(`{"hashed_password":"$2a$04$zvZz1zV"}`),
+		})
+		require.NoError(t, reg.IdentityManager().Create(ctx, i))
+		ids = append(ids, i.ID)
+		if k == 2 || k == 3 {
+			failing[i.ID] = true
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+
+	create := func(t *testing.T, sm *identity.SchemaMigration) *identity.SchemaMigration {
+		require.NoError(t, reg.IdentitySchemaMigrator().Create(ctx, sm))
+		return sm
+	}
+
+	t.Run("case=rejects invalid migrations", func(t *testing.T) {
+		for k, sm := range []identity.SchemaMigration{
+			{SourceSchemaID: "source", TargetSchemaID: "source", Transform: schemaMigrationTransform},
+			{SourceSchemaID: "source", TargetSchemaID: "does-not-exist", Transform: schemaMigrationTransform},
+			{SourceSchemaID: "does-not-exist", TargetSchemaID: "target", Transform: schemaMigrationTransform},
+			{SourceSchemaID: "source", TargetSchemaID: "target", Transform: " "},
+			{SourceSchemaID: "source", TargetSchemaID: "target", Transform: schemaMigrationTransform, BatchSize: -1},
+		} {
+			t.Run(fmt.Sprintf("case=%d", k), func(t *testing.T) {
+				err := reg.IdentitySchemaMigrator().Create(ctx, &sm)
+				require.ErrorIs(t, err, herodot.ErrBadRequest)
+			})
+		}
+	})
+
+	t.Run("case=dry run records failures without updating identities", func(t *testing.T) {
+		sm := create(t, &identity.SchemaMigration{SourceSchemaID: "source", TargetSchemaID: "target", Transform: schemaMigrationTransform, DryRun: true})
+		assert.Equal(t, identity.SchemaMigrationStatePending, sm.State)
+
+		sm, err := reg.IdentitySchemaMigrator().Run(ctx, sm.ID)
+		require.NoError(t, err)
+		assert.Equal(t, identity.SchemaMigrationStateCompleted, sm.State)
+		assert.Equal(t, 5, sm.Processed)
+		assert.Equal(t, 3, sm.Migrated)
+		assert.Equal(t, 2, sm.Failed)
+		assert.Equal(t, ids[len(ids)-1], sm.Cursor)
+
+		for _, id := range ids {
+			i, err := reg.IdentityPool().GetIdentity(ctx, id, identity.ExpandNothing)
+			require.NoError(t, err)
+			assert.Equal(t, "source", i.SchemaID)
+		}
+
+		fs, err := reg.IdentitySchemaMigrationPersister().ListSchemaMigrationFailures(ctx, sm.ID, 1, 100)
+		require.NoError(t, err)
+		require.Len(t, fs, 2)
+		for _, f := range fs {
+			assert.True(t, failing[f.IdentityID], "%s", f.IdentityID)
+			assert.NotEmpty(t, f.Reason)
+		}
+
+		_, err = reg.IdentitySchemaMigrator().Run(ctx, sm.ID)
+		require.ErrorIs(t, err, herodot.ErrConflict, "completed migrations can not be run again")
+	})
+
+	t.Run("case=resumes after the cursor", func(t *testing.T) {
+		sm := create(t, &identity.SchemaMigration{SourceSchemaID: "source", TargetSchemaID: "target", Transform: schemaMigrationTransform, DryRun: true, BatchSize: 2})
+
+		// Simulate a migration which failed after the first batch.
+		require.NoError(t, reg.IdentitySchemaMigrationPersister().ClaimSchemaMigration(ctx, sm.ID, time.Now()))
+		_, err := reg.IdentitySchemaMigrator().Run(ctx, sm.ID)
+		require.ErrorIs(t, err, herodot.ErrConflict, "running migrations can not be claimed twice")
+
+		sm.State = identity.SchemaMigrationStateFailed
+		sm.Cursor = ids[1]
+		sm.Processed = 2
+		require.NoError(t, reg.IdentitySchemaMigrationPersister().UpdateSchemaMigration(ctx, sm, nil))
+
+		var batches []identity.SchemaMigration
+		sm, err = reg.IdentitySchemaMigrator().Run(ctx, sm.ID, identity.SchemaMigrationWithProgress(func(sm *identity.SchemaMigration) {
+			batches = append(batches, *sm)
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, identity.SchemaMigrationStateCompleted, sm.State)
+		assert.Equal(t, 5, sm.Processed)
+		assert.Equal(t, 3, sm.Migrated+sm.Failed)
+		require.Len(t, batches, 2)
+		assert.Equal(t, ids[3], batches[0].Cursor)
+		assert.Equal(t, ids[4], batches[1].Cursor)
+	})
+
+	t.Run("case=migrates the identities", func(t *testing.T) {
+		sm := create(t, &identity.SchemaMigration{SourceSchemaID: "source", TargetSchemaID: "target", Transform: schemaMigrationTransform, BatchSize: 2})
+
+		sm, err := reg.IdentitySchemaMigrator().Run(ctx, sm.ID)
+		require.NoError(t, err)
+		assert.Equal(t, identity.SchemaMigrationStateCompleted, sm.State)
+		assert.Equal(t, 3, sm.Migrated)
+		assert.Equal(t, 2, sm.Failed)
+
+		for _, id := range ids {
+			i, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, id)
+			require.NoError(t, err)
+			if failing[id] {
+				assert.Equal(t, "source", i.SchemaID)
+				continue
+			}
+
+			assert.Equal(t, "target", i.SchemaID)
+			assert.NotEmpty(t, gjson.GetBytes(i.Traits, "name.first").String(), "%s", i.Traits)
+			assert.False(t, gjson.GetBytes(i.Traits, "first_name").Exists(), "%s", i.Traits)
+
+			creds, ok := i.GetCredentials(identity.CredentialsTypePassword)
+			require.True(t, ok)
+			assert.Equal(t, []string{gjson.GetBytes(i.Traits, "email").String()}, creds.Identifiers)
+		}
+
+		sm = create(t, &identity.SchemaMigration{SourceSchemaID: "source", TargetSchemaID: "target", Transform: schemaMigrationTransform})
+		sm, err = reg.IdentitySchemaMigrator().Run(ctx, sm.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, sm.Processed, "only the identities which failed before are left")
+	})
+}
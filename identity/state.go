@@ -0,0 +1,198 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/pagination/keysetpagination"
+	"github.com/ory/x/sqlxx"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x"
+)
+
+const (
+	// StateNoteMaxLength is the maximum length of a state note.
+	StateNoteMaxLength = 1024
+
+	// stateExpiryBatchSize is the number of identities loaded at once when expiring identity states.
+	stateExpiryBatchSize = 100
+)
+
+var stateReasonPattern = regexp.MustCompile(`^[a-z0-9_\-]{1,64}$`)
+
+// Update Identity State Body
+//
+// swagger:model updateIdentityStateBody
+type StateChange struct {
+	// State is the new state of the identity.
+	//
+	// required: true
+	State State `json:"state"`
+
+	// Reason is the reason code of the new state, for example `fraud` or `chargeback`. It may consist of up to 64
+	// lowercase letters, digits, dashes, and underscores and is required to suspend an identity.
+	Reason string `json:"reason"`
+
+	// Note is a note about the new state which is only accessible through admin APIs.
+	Note string `json:"note"`
+
+	// SuspendedUntil is the time after which a suspended identity is reactivated. If it is not set, the identity
+	// stays suspended until it is reactivated. It can only be set when suspending an identity.
+	SuspendedUntil *time.Time `json:"suspended_until"`
+}
+
+// revokesSessions returns true if the sessions of an identity are revoked when the identity enters the state.
+func (lt State) revokesSessions() bool {
+	return lt == StateSuspended || lt == StatePendingDeletion
+}
+
+// ChangeState validates the state change and applies it to the identity. The reason, the note, and the expiry of
+// the previous state are cleared. Identities pending deletion are deleted once the deletion grace period passed.
+func (i *Identity) ChangeState(c StateChange, deletionGracePeriod time.Duration) error {
+	if err := c.State.IsValid(); err != nil {
+		return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The %s. It must be one of %q, %q, %q, or %q.", err, StateActive, StateInactive, StateSuspended, StatePendingDeletion).WithWrap(err))
+	}
+
+	if c.State == StateActive && (c.Reason != "" || c.Note != "") {
+		return errors.WithStack(herodot.ErrBadRequest.WithReason("A reason or a note can not be set when activating an identity."))
+	} else if c.State == StateSuspended && c.Reason == "" {
+		return errors.WithStack(herodot.ErrBadRequest.WithReason("A reason is required to suspend an identity."))
+	} else if c.Reason != "" && !stateReasonPattern.MatchString(c.Reason) {
+		return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The reason %q must consist of up to 64 lowercase letters, digits, dashes, and underscores.", c.Reason))
+	} else if len(c.Note) > StateNoteMaxLength {
+		return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The note must not be longer than %d characters.", StateNoteMaxLength))
+	}
+
+	now := time.Now().UTC()
+	if c.SuspendedUntil != nil {
+		if c.State != StateSuspended {
+			return errors.WithStack(herodot.ErrBadRequest.WithReason("The suspension end can only be set when suspending an identity."))
+		} else if !c.SuspendedUntil.After(now) {
+			return errors.WithStack(herodot.ErrBadRequest.WithReason("The suspension end must be in the future."))
+		}
+	}
+
+	changedAt := sqlxx.NullTime(now)
+	i.State = c.State
+	i.StateChangedAt = &changedAt
+	i.StateReason = c.Reason
+	i.StateNote = c.Note
+	i.SuspendedUntil = nil
+	i.DeleteAfter = nil
+
+	if c.SuspendedUntil != nil {
+		until := sqlxx.NullTime(c.SuspendedUntil.UTC())
+		i.SuspendedUntil = &until
+	}
+	if c.State == StatePendingDeletion {
+		deleteAfter := sqlxx.NullTime(now.Add(deletionGracePeriod))
+		i.DeleteAfter = &deleteAfter
+	}
+	return nil
+}
+
+type (
+	stateExpirerDependencies interface {
+		PrivilegedPoolProvider
+		ManagementProvider
+		config.Provider
+		x.LoggingProvider
+		x.TracingProvider
+	}
+	StateExpirerProvider interface {
+		IdentityStateExpirer() *StateExpirer
+	}
+	// StateExpirer reactivates identities whose suspension ended and deletes identities whose deletion grace
+	// period passed.
+	StateExpirer struct {
+		r stateExpirerDependencies
+	}
+)
+
+func NewStateExpirer(r stateExpirerDependencies) *StateExpirer {
+	return &StateExpirer{r: r}
+}
+
+// Watch expires identity states in the configured interval until the context is canceled.
+func (e *StateExpirer) Watch(ctx context.Context) error {
+	for {
+		if _, _, err := e.ExpireStates(ctx); err != nil {
+			e.r.Logger().WithError(err).Error("Unable to expire identity states.")
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil
+			}
+			return ctx.Err()
+		case <-time.After(e.r.Config().IdentityStateExpiryInterval(ctx)):
+		}
+	}
+}
+
+// ExpireStates reactivates all identities whose suspension ended and deletes all identities whose deletion grace
+// period passed. Identities which can not be reactivated or deleted are logged and skipped.
+func (e *StateExpirer) ExpireStates(ctx context.Context) (reactivated, deleted int, err error) {
+	ctx, span := e.r.Tracer(ctx).Tracer().Start(ctx, "identity.StateExpirer.ExpireStates")
+	defer otelx.End(span, &err)
+
+	now := time.Now().UTC()
+	pagination := []keysetpagination.Option{keysetpagination.WithSize(stateExpiryBatchSize)}
+	for {
+		is, next, err := e.r.PrivilegedIdentityPool().ListIdentities(ctx, ListIdentityParameters{
+			StateExpiredBefore: &now,
+			KeySetPagination:   pagination,
+		})
+		if err != nil {
+			return reactivated, deleted, err
+		}
+
+		for k := range is {
+			logger := e.r.Logger().WithField("identity_id", is[k].ID).WithField("state", is[k].State)
+			switch is[k].State {
+			case StateSuspended:
+				if err := e.reactivate(ctx, &is[k]); err != nil {
+					logger.WithError(err).Warn("Unable to reactivate the identity after its suspension ended.")
+					continue
+				}
+				logger.Info("Reactivated the identity because its suspension ended.")
+				reactivated++
+			case StatePendingDeletion:
+				if err := e.r.PrivilegedIdentityPool().DeleteIdentity(ctx, is[k].ID); err != nil {
+					logger.WithError(err).Warn("Unable to delete the identity after its deletion grace period passed.")
+					continue
+				}
+				logger.Info("Deleted the identity because its deletion grace period passed.")
+				deleted++
+			}
+		}
+
+		if next.IsLast() {
+			return reactivated, deleted, nil
+		}
+		pagination = next.ToOptions()
+	}
+}
+
+func (e *StateExpirer) reactivate(ctx context.Context, i *Identity) error {
+	// The listed identity does not include the credentials, which would be removed by the update.
+	full, err := e.r.PrivilegedIdentityPool().GetIdentityConfidential(ctx, i.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := full.ChangeState(StateChange{State: StateActive}, 0); err != nil {
+		return err
+	}
+	return e.r.IdentityManager().Update(ctx, full, ManagerAllowWriteProtectedTraits)
+}
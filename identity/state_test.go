@@ -0,0 +1,172 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/pointerx"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x"
+)
+
+func TestChangeState(t *testing.T) {
+	t.Run("case=rejects invalid state changes", func(t *testing.T) {
+		for k, c := range []identity.StateChange{
+			{State: "invalid"},
+			{State: identity.StateActive, Reason: "fraud"},
+			{State: identity.StateActive, Note: "note"},
+			{State: identity.StateSuspended},
+			{State: identity.StateSuspended, Reason: "Fraud!"},
+			{State: identity.StateSuspended, Reason: strings.Repeat("a", 65)},
+			{State: identity.StateSuspended, Reason: "fraud", Note: strings.Repeat("a", identity.StateNoteMaxLength+1)},
+			{State: identity.StateSuspended, Reason: "fraud", SuspendedUntil: pointerx.Ptr(time.Now().Add(-time.Minute))},
+			{State: identity.StateInactive, SuspendedUntil: pointerx.Ptr(time.Now().Add(time.Hour))},
+		} {
+			t.Run(fmt.Sprintf("case=%d", k), func(t *testing.T) {
+				i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+				require.ErrorIs(t, i.ChangeState(c, time.Hour), herodot.ErrBadRequest)
+				assert.Equal(t, identity.StateActive, i.State)
+			})
+		}
+	})
+
+	t.Run("case=suspends until the given time", func(t *testing.T) {
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		until := time.Now().Add(time.Hour)
+		require.NoError(t, i.ChangeState(identity.StateChange{State: identity.StateSuspended, Reason: "chargeback", Note: "Disputed order 1234.", SuspendedUntil: &until}, time.Hour))
+
+		assert.Equal(t, identity.StateSuspended, i.State)
+		assert.Equal(t, "chargeback", i.StateReason)
+		assert.Equal(t, "Disputed order 1234.", i.StateNote)
+		require.NotNil(t, i.SuspendedUntil)
+		assert.WithinDuration(t, until, time.Time(*i.SuspendedUntil), time.Second)
+		assert.Nil(t, i.DeleteAfter)
+		assert.False(t, i.IsActive())
+
+		i.SuspendedUntil = pointerx.Ptr(sqlxx.NullTime(time.Now().Add(-time.Second)))
+		assert.True(t, i.IsActive(), "a suspension which ended no longer blocks the identity")
+	})
+
+	t.Run("case=pending deletion sets the deletion time and clears the suspension", func(t *testing.T) {
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		require.NoError(t, i.ChangeState(identity.StateChange{State: identity.StateSuspended, Reason: "fraud", SuspendedUntil: pointerx.Ptr(time.Now().Add(time.Hour))}, time.Hour))
+		require.NoError(t, i.ChangeState(identity.StateChange{State: identity.StatePendingDeletion, Reason: "user-request"}, 48*time.Hour))
+
+		assert.Equal(t, "user-request", i.StateReason)
+		assert.Nil(t, i.SuspendedUntil)
+		require.NotNil(t, i.DeleteAfter)
+		assert.WithinDuration(t, time.Now().Add(48*time.Hour), time.Time(*i.DeleteAfter), time.Minute)
+		assert.False(t, i.IsActive())
+
+		require.NoError(t, i.ChangeState(identity.StateChange{State: identity.StateActive}, time.Hour))
+		assert.Empty(t, i.StateReason)
+		assert.Nil(t, i.DeleteAfter)
+		assert.True(t, i.IsActive())
+	})
+}
+
+func TestStateExpirer(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/identity.schema.json")
+
+	create := func(t *testing.T, c identity.StateChange) *identity.Identity {
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.Traits = identity.Traits(fmt.Sprintf(`{"email":"%s@ory.sh"}`, x.NewUUID()))
+		require.NoError(t, reg.IdentityManager().Create(ctx, i))
+		if c.State != "" {
+			require.NoError(t, i.ChangeState(c, time.Hour))
+			require.NoError(t, reg.IdentityManager().Update(ctx, i, identity.ManagerAllowWriteProtectedTraits))
+		}
+		return i
+	}
+
+	createSession := func(t *testing.T, i *identity.Identity) *session.Session {
+		s, err := session.NewActiveSession(new(http.Request), i, conf, time.Now(), identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+		require.NoError(t, err)
+		require.NoError(t, reg.SessionPersister().UpsertSession(ctx, s))
+		return s
+	}
+
+	t.Run("case=suspending an identity revokes its sessions", func(t *testing.T) {
+		i := create(t, identity.StateChange{})
+		s := createSession(t, i)
+
+		require.NoError(t, i.ChangeState(identity.StateChange{State: identity.StateSuspended, Reason: "fraud"}, time.Hour))
+		require.NoError(t, reg.IdentityManager().Update(ctx, i, identity.ManagerAllowWriteProtectedTraits))
+
+		actual, err := reg.SessionPersister().GetSession(ctx, s.ID, session.ExpandNothing)
+		require.NoError(t, err)
+		assert.False(t, actual.Active)
+	})
+
+	t.Run("case=deactivating an identity keeps its sessions", func(t *testing.T) {
+		i := create(t, identity.StateChange{})
+		s := createSession(t, i)
+
+		require.NoError(t, i.ChangeState(identity.StateChange{State: identity.StateInactive}, time.Hour))
+		require.NoError(t, reg.IdentityManager().Update(ctx, i, identity.ManagerAllowWriteProtectedTraits))
+
+		actual, err := reg.SessionPersister().GetSession(ctx, s.ID, session.ExpandNothing)
+		require.NoError(t, err)
+		assert.True(t, actual.Active)
+	})
+
+	t.Run("case=reactivates ended suspensions and deletes identities after the grace period", func(t *testing.T) {
+		ended := create(t, identity.StateChange{State: identity.StateSuspended, Reason: "fraud", SuspendedUntil: pointerx.Ptr(time.Now().Add(time.Hour))})
+		ended.SuspendedUntil = pointerx.Ptr(sqlxx.NullTime(time.Now().Add(-time.Minute)))
+		require.NoError(t, reg.PrivilegedIdentityPool().UpdateIdentity(ctx, ended))
+
+		running := create(t, identity.StateChange{State: identity.StateSuspended, Reason: "fraud", SuspendedUntil: pointerx.Ptr(time.Now().Add(time.Hour))})
+		indefinite := create(t, identity.StateChange{State: identity.StateSuspended, Reason: "fraud"})
+
+		expired := create(t, identity.StateChange{State: identity.StatePendingDeletion})
+		expired.DeleteAfter = pointerx.Ptr(sqlxx.NullTime(time.Now().Add(-time.Minute)))
+		require.NoError(t, reg.PrivilegedIdentityPool().UpdateIdentity(ctx, expired))
+
+		pending := create(t, identity.StateChange{State: identity.StatePendingDeletion})
+
+		reactivated, deleted, err := reg.IdentityStateExpirer().ExpireStates(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, reactivated)
+		assert.Equal(t, 1, deleted)
+
+		actual, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, ended.ID)
+		require.NoError(t, err)
+		assert.Equal(t, identity.StateActive, actual.State)
+		assert.Empty(t, actual.StateReason)
+		assert.Nil(t, actual.SuspendedUntil)
+
+		_, err = reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, expired.ID)
+		require.ErrorIs(t, err, sqlcon.ErrNoRows)
+
+		for _, i := range []*identity.Identity{running, indefinite, pending} {
+			actual, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, i.ID)
+			require.NoError(t, err)
+			assert.Equal(t, i.State, actual.State)
+		}
+
+		reactivated, deleted, err = reg.IdentityStateExpirer().ExpireStates(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, reactivated)
+		assert.Zero(t, deleted)
+	})
+}
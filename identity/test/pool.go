@@ -8,11 +8,14 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/ory/x/pagination/keysetpagination"
+	"github.com/ory/x/pointerx"
 	"github.com/ory/x/randx"
 
 	"github.com/tidwall/gjson"
@@ -121,7 +124,7 @@ func TestPool(ctx context.Context, conf *config.Config, p persistence.Persister,
 				})
 
 				t.Run("list", func(t *testing.T) {
-					actual, err := p.ListIdentities(ctx, identity.ListIdentityParameters{Expand: expand, Page: 0, PerPage: 10})
+					actual, _, err := p.ListIdentities(ctx, identity.ListIdentityParameters{Expand: expand, Page: 0, PerPage: 10})
 					require.NoError(t, err)
 					require.Len(t, actual, 1)
 					assertion(t, &actual[0])
@@ -622,7 +625,7 @@ func TestPool(ctx context.Context, conf *config.Config, p persistence.Persister,
 		})
 
 		t.Run("case=list", func(t *testing.T) {
-			is, err := p.ListIdentities(ctx, identity.ListIdentityParameters{Expand: identity.ExpandDefault, Page: 0, PerPage: 25})
+			is, _, err := p.ListIdentities(ctx, identity.ListIdentityParameters{Expand: identity.ExpandDefault, Page: 0, PerPage: 25})
 			require.NoError(t, err)
 			assert.Len(t, is, len(createdIDs))
 			for _, id := range createdIDs {
@@ -640,12 +643,130 @@ func TestPool(ctx context.Context, conf *config.Config, p persistence.Persister,
 
 			t.Run("no results on other network", func(t *testing.T) {
 				_, p := testhelpers.NewNetwork(t, ctx, p)
-				is, err := p.ListIdentities(ctx, identity.ListIdentityParameters{Expand: identity.ExpandDefault, Page: 0, PerPage: 25})
+				is, _, err := p.ListIdentities(ctx, identity.ListIdentityParameters{Expand: identity.ExpandDefault, Page: 0, PerPage: 25})
 				require.NoError(t, err)
 				assert.Len(t, is, 0)
 			})
 		})
 
+		t.Run("case=list with filters and keyset pagination", func(t *testing.T) {
+			_, p := testhelpers.NewNetwork(t, ctx, p)
+
+			var created []*identity.Identity
+			for k, traits := range []string{
+				`{"email":"filter-alice@ory.sh","bar":"ory"}`,
+				`{"email":"filter-bob@ory.sh","bar":"ory"}`,
+				`{"email":"filter-carol@example.org","bar":"acme"}`,
+				`{"email":"filter_dave@example.org","bar":"acme"}`,
+			} {
+				i := passwordIdentity("", fmt.Sprintf("filter-%d@ory.sh", k))
+				i.Traits = identity.Traits(traits)
+				i.VerifiableAddresses = []identity.VerifiableAddress{{
+					Value:    gjson.Get(traits, "email").String(),
+					Via:      identity.VerifiableAddressTypeEmail,
+					Status:   identity.VerifiableAddressStatusPending,
+					Verified: k == 0,
+				}}
+				if k == 3 {
+					i.State = identity.StateInactive
+					i.SetCredentials(identity.CredentialsTypeOIDC, identity.Credentials{
+						Type: identity.CredentialsTypeOIDC, Identifiers: []string{"filter:dave"},
+						Config: sqlxx.JSONRawMessage(`{}`),
+					})
+				}
+				require.NoError(t, p.CreateIdentity(ctx, i))
+				created = append(created, i)
+			}
+
+			list := func(t *testing.T, params identity.ListIdentityParameters) []string {
+				if params.KeySetPagination == nil {
+					params.KeySetPagination = []keysetpagination.Option{}
+				}
+				is, _, err := p.ListIdentities(ctx, params)
+				require.NoError(t, err)
+				emails := make([]string, len(is))
+				for k, i := range is {
+					emails[k] = gjson.GetBytes(i.Traits, "email").String()
+				}
+				sort.Strings(emails)
+				return emails
+			}
+
+			t.Run("filter=traits", func(t *testing.T) {
+				assert.Equal(t, []string{"filter-alice@ory.sh", "filter-bob@ory.sh"}, list(t, identity.ListIdentityParameters{
+					Traits: []identity.TraitFilter{{Path: []string{"bar"}, Value: "ory"}},
+				}))
+				assert.Equal(t, []string{"filter-carol@example.org"}, list(t, identity.ListIdentityParameters{
+					Traits: []identity.TraitFilter{{Path: []string{"email"}, Value: "filter-c", Prefix: true}},
+				}))
+				assert.Equal(t, []string{"filter-bob@ory.sh"}, list(t, identity.ListIdentityParameters{
+					Traits: []identity.TraitFilter{
+						{Path: []string{"bar"}, Value: "ory"},
+						{Path: []string{"email"}, Value: "filter-bob@ory.sh"},
+					},
+				}))
+				assert.Equal(t, []string{"filter_dave@example.org"}, list(t, identity.ListIdentityParameters{
+					Traits: []identity.TraitFilter{{Path: []string{"email"}, Value: "filter_", Prefix: true}},
+				}), "LIKE wildcards in the value are escaped")
+				assert.Empty(t, list(t, identity.ListIdentityParameters{
+					Traits: []identity.TraitFilter{{Path: []string{"unknown"}, Value: "ory"}},
+				}))
+			})
+
+			t.Run("filter=state", func(t *testing.T) {
+				assert.Equal(t, []string{"filter_dave@example.org"}, list(t, identity.ListIdentityParameters{State: identity.StateInactive}))
+				assert.Len(t, list(t, identity.ListIdentityParameters{State: identity.StateActive}), 3)
+			})
+
+			t.Run("filter=schema_id", func(t *testing.T) {
+				assert.Len(t, list(t, identity.ListIdentityParameters{SchemaID: config.DefaultIdentityTraitsSchemaID}), 4)
+				assert.Empty(t, list(t, identity.ListIdentityParameters{SchemaID: altSchema.ID}))
+			})
+
+			t.Run("filter=credentials_type", func(t *testing.T) {
+				assert.Equal(t, []string{"filter_dave@example.org"}, list(t, identity.ListIdentityParameters{CredentialsType: identity.CredentialsTypeOIDC}))
+				assert.Len(t, list(t, identity.ListIdentityParameters{CredentialsType: identity.CredentialsTypePassword}), 4)
+			})
+
+			t.Run("filter=verified", func(t *testing.T) {
+				assert.Equal(t, []string{"filter-alice@ory.sh"}, list(t, identity.ListIdentityParameters{Verified: pointerx.Bool(true)}))
+				assert.Len(t, list(t, identity.ListIdentityParameters{Verified: pointerx.Bool(false)}), 3)
+			})
+
+			t.Run("filter=time", func(t *testing.T) {
+				before := created[0].CreatedAt.Add(-time.Minute)
+				after := created[3].CreatedAt.Add(time.Minute)
+				assert.Len(t, list(t, identity.ListIdentityParameters{CreatedAfter: &before, CreatedBefore: &after}), 4)
+				assert.Empty(t, list(t, identity.ListIdentityParameters{CreatedAfter: &after}))
+				assert.Empty(t, list(t, identity.ListIdentityParameters{UpdatedBefore: &before}))
+			})
+
+			t.Run("filter=credentials_identifier", func(t *testing.T) {
+				assert.Equal(t, []string{"filter-bob@ory.sh"}, list(t, identity.ListIdentityParameters{CredentialsIdentifier: "filter-1@ory.sh"}))
+			})
+
+			t.Run("pagination", func(t *testing.T) {
+				var ids []uuid.UUID
+				opts := []keysetpagination.Option{keysetpagination.WithSize(3)}
+				for {
+					is, next, err := p.ListIdentities(ctx, identity.ListIdentityParameters{KeySetPagination: opts})
+					require.NoError(t, err)
+					for _, i := range is {
+						ids = append(ids, i.ID)
+					}
+					if next.IsLast() {
+						break
+					}
+					opts = next.ToOptions()
+				}
+
+				require.Len(t, ids, len(created))
+				assert.True(t, sort.SliceIsSorted(ids, func(i, j int) bool {
+					return ids[i].String() < ids[j].String()
+				}), "%v", ids)
+			})
+		})
+
 		t.Run("case=find identity by its credentials identifier", func(t *testing.T) {
 			var expectedIdentifiers []string
 			var expectedIdentities []*identity.Identity
@@ -670,7 +791,7 @@ func TestPool(ctx context.Context, conf *config.Config, p persistence.Persister,
 			create.SetCredentials(identity.CredentialsTypeWebAuthn, identity.Credentials{Type: identity.CredentialsTypeWebAuthn, Identifiers: []string{"find-identity-by-identifier-common@ory.sh"}, Config: sqlxx.JSONRawMessage(`{}`)})
 			require.NoError(t, p.CreateIdentity(ctx, create))
 
-			actual, err := p.ListIdentities(ctx, identity.ListIdentityParameters{
+			actual, _, err := p.ListIdentities(ctx, identity.ListIdentityParameters{
 				Expand: identity.ExpandEverything,
 			})
 			require.NoError(t, err)
@@ -681,7 +802,7 @@ func TestPool(ctx context.Context, conf *config.Config, p persistence.Persister,
 				identity.CredentialsTypeWebAuthn,
 			} {
 				t.Run(ct.String(), func(t *testing.T) {
-					actual, err := p.ListIdentities(ctx, identity.ListIdentityParameters{
+					actual, _, err := p.ListIdentities(ctx, identity.ListIdentityParameters{
 						// Match is normalized
 						CredentialsIdentifier: expectedIdentifiers[c],
 					})
@@ -694,7 +815,7 @@ func TestPool(ctx context.Context, conf *config.Config, p persistence.Persister,
 			}
 
 			t.Run("only webauthn and password", func(t *testing.T) {
-				actual, err := p.ListIdentities(ctx, identity.ListIdentityParameters{
+				actual, _, err := p.ListIdentities(ctx, identity.ListIdentityParameters{
 					CredentialsIdentifier: "find-identity-by-identifier-oidc@ory.sh",
 					Expand:                identity.ExpandEverything,
 				})
@@ -703,7 +824,7 @@ func TestPool(ctx context.Context, conf *config.Config, p persistence.Persister,
 			})
 
 			t.Run("one result set even if multiple matches", func(t *testing.T) {
-				actual, err := p.ListIdentities(ctx, identity.ListIdentityParameters{
+				actual, _, err := p.ListIdentities(ctx, identity.ListIdentityParameters{
 					CredentialsIdentifier: "find-identity-by-identifier-common@ory.sh",
 					Expand:                identity.ExpandEverything,
 				})
@@ -712,7 +833,7 @@ func TestPool(ctx context.Context, conf *config.Config, p persistence.Persister,
 			})
 
 			t.Run("non existing identifier", func(t *testing.T) {
-				actual, err := p.ListIdentities(ctx, identity.ListIdentityParameters{
+				actual, _, err := p.ListIdentities(ctx, identity.ListIdentityParameters{
 					CredentialsIdentifier: "find-identity-by-identifier-non-existing@ory.sh",
 					Expand:                identity.ExpandEverything,
 				})
@@ -722,7 +843,7 @@ func TestPool(ctx context.Context, conf *config.Config, p persistence.Persister,
 
 			t.Run("not if on another network", func(t *testing.T) {
 				_, on := testhelpers.NewNetwork(t, ctx, p)
-				actual, err := on.ListIdentities(ctx, identity.ListIdentityParameters{
+				actual, _, err := on.ListIdentities(ctx, identity.ListIdentityParameters{
 					CredentialsIdentifier: expectedIdentifiers[0],
 					Expand:                identity.ExpandEverything,
 				})
@@ -1123,6 +1244,80 @@ func TestPool(ctx context.Context, conf *config.Config, p persistence.Persister,
 			})
 		})
 
+		t.Run("case=walk credentials", func(t *testing.T) {
+			_, p := testhelpers.NewNetwork(t, ctx, p)
+
+			var expected []uuid.UUID
+			for k := 0; k < 5; k++ {
+				i := oidcIdentity("", x.NewUUID().String())
+				require.NoError(t, p.CreateIdentity(ctx, i))
+				expected = append(expected, i.Credentials[identity.CredentialsTypeOIDC].ID)
+			}
+			require.NoError(t, p.CreateIdentity(ctx, NewTestIdentity(1, "walk-credentials", 0)))
+			sort.Slice(expected, func(i, j int) bool { return expected[i].String() < expected[j].String() })
+
+			walk := func(t *testing.T, p identity.PrivilegedPool, after uuid.UUID, fn func(identity.Credentials) bool) (actual []uuid.UUID) {
+				skipped, err := p.WalkCredentials(ctx, identity.CredentialsTypeOIDC, after, 2, func(ctx context.Context, credentials []identity.Credentials) (updated []identity.Credentials, err error) {
+					assert.LessOrEqual(t, len(credentials), 2)
+					for _, c := range credentials {
+						assert.Equal(t, identity.CredentialsTypeOIDC, c.Type)
+						actual = append(actual, c.ID)
+						if fn != nil && fn(c) {
+							c.Config = sqlxx.JSONRawMessage(`{"providers":[]}`)
+							updated = append(updated, c)
+						}
+					}
+					return updated, nil
+				})
+				require.NoError(t, err)
+				assert.Zero(t, skipped)
+				return actual
+			}
+
+			assert.Equal(t, expected, walk(t, p, uuid.Nil, nil))
+			assert.Equal(t, expected[2:], walk(t, p, expected[1], nil))
+			assert.Empty(t, walk(t, p, expected[4], nil))
+
+			walk(t, p, uuid.Nil, func(c identity.Credentials) bool { return c.ID == expected[3] })
+			for k, id := range expected {
+				var actual identity.Credentials
+				require.NoError(t, p.GetConnection(ctx).Where("id = ?", id).First(&actual))
+				if k == 3 {
+					assert.JSONEq(t, `{"providers":[]}`, string(actual.Config))
+				} else {
+					assert.JSONEq(t, `{}`, string(actual.Config))
+				}
+			}
+
+			t.Run("skips credentials which were changed concurrently", func(t *testing.T) {
+				skipped, err := p.WalkCredentials(ctx, identity.CredentialsTypeOIDC, uuid.Nil, 10, func(ctx context.Context, credentials []identity.Credentials) (updated []identity.Credentials, err error) {
+					require.NoError(t, p.GetConnection(ctx).RawQuery("UPDATE identity_credentials SET config = ? WHERE id = ?", sqlxx.JSONRawMessage(`{"providers":[{"provider":"changed"}]}`), expected[0]).Exec())
+					for _, c := range credentials {
+						c.Config = sqlxx.JSONRawMessage(`{"providers":[{"provider":"walked"}]}`)
+						updated = append(updated, c)
+					}
+					return updated, nil
+				})
+				require.NoError(t, err)
+				assert.Equal(t, 1, skipped)
+
+				for k, id := range expected {
+					var actual identity.Credentials
+					require.NoError(t, p.GetConnection(ctx).Where("id = ?", id).First(&actual))
+					if k == 0 {
+						assert.JSONEq(t, `{"providers":[{"provider":"changed"}]}`, string(actual.Config))
+					} else {
+						assert.JSONEq(t, `{"providers":[{"provider":"walked"}]}`, string(actual.Config))
+					}
+				}
+			})
+
+			t.Run("not on another network", func(t *testing.T) {
+				_, p := testhelpers.NewNetwork(t, ctx, p)
+				assert.Empty(t, walk(t, p, uuid.Nil, nil))
+			})
+		})
+
 		t.Run("network reference isolation", func(t *testing.T) {
 			nid1, p := testhelpers.NewNetwork(t, ctx, p)
 			nid2, _ := testhelpers.NewNetwork(t, ctx, p)
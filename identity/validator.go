@@ -15,7 +15,7 @@ import (
 
 type (
 	validatorDependencies interface {
-		IdentityTraitsSchemas(ctx context.Context) (schema.Schemas, error)
+		IdentityTraitsSchema(ctx context.Context, id string) (*schema.Schema, error)
 		config.Provider
 	}
 	Validator struct {
@@ -37,12 +37,7 @@ func (v *Validator) ValidateWithRunner(ctx context.Context, i *Identity, runners
 		return err
 	}
 
-	ss, err := v.d.IdentityTraitsSchemas(ctx)
-	if err != nil {
-		return err
-	}
-
-	s, err := ss.GetByID(i.SchemaID)
+	s, err := v.d.IdentityTraitsSchema(ctx, i.SchemaID)
 	if err != nil {
 		return err
 	}
@@ -694,6 +694,9 @@ type FrontendApi interface {
 
 		`session_inactive`: No active session was found in the request (e.g. no Ory Session Cookie / Ory Session Token).
 		`session_aal2_required`: An active session was found but it does not fulfil the Authenticator Assurance Level, implying that the session must (e.g.) authenticate the second factor.
+
+		If the `tokenize_as` query parameter is set, the session is additionally returned as a short-lived JSON Web Token
+		in the `tokenized` field. The parameter must name a template configured at `session.whoami.tokenizer.templates`.
 			 * @param ctx context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
 			 * @return FrontendApiApiToSessionRequest
 	*/
@@ -4479,6 +4482,7 @@ type FrontendApiApiToSessionRequest struct {
 	ApiService    FrontendApi
 	xSessionToken *string
 	cookie        *string
+	tokenizeAs    *string
 }
 
 func (r FrontendApiApiToSessionRequest) XSessionToken(xSessionToken string) FrontendApiApiToSessionRequest {
@@ -4489,6 +4493,10 @@ func (r FrontendApiApiToSessionRequest) Cookie(cookie string) FrontendApiApiToSe
 	r.cookie = &cookie
 	return r
 }
+func (r FrontendApiApiToSessionRequest) TokenizeAs(tokenizeAs string) FrontendApiApiToSessionRequest {
+	r.tokenizeAs = &tokenizeAs
+	return r
+}
 
 func (r FrontendApiApiToSessionRequest) Execute() (*Session, *http.Response, error) {
 	return r.ApiService.ToSessionExecute(r)
@@ -4546,6 +4554,9 @@ As explained above, this request may fail due to several reasons. The `error.id`
 
 `session_inactive`: No active session was found in the request (e.g. no Ory Session Cookie / Ory Session Token).
 `session_aal2_required`: An active session was found but it does not fulfil the Authenticator Assurance Level, implying that the session must (e.g.) authenticate the second factor.
+
+If the `tokenize_as` query parameter is set, the session is additionally returned as a short-lived JSON Web Token
+in the `tokenized` field. The parameter must name a template configured at `session.whoami.tokenizer.templates`.
   - @param ctx context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
   - @return FrontendApiApiToSessionRequest
 */
@@ -4581,6 +4592,10 @@ func (a *FrontendApiService) ToSessionExecute(r FrontendApiApiToSessionRequest)
 	localVarQueryParams := url.Values{}
 	localVarFormParams := url.Values{}
 
+	if r.tokenizeAs != nil {
+		localVarQueryParams.Add("tokenize_as", parameterToString(*r.tokenizeAs, ""))
+	}
+
 	// to determine the Content-Type header
 	localVarHTTPContentTypes := []string{}
 
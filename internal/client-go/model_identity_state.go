@@ -16,13 +16,15 @@ import (
 	"fmt"
 )
 
-// IdentityState The state can either be `active` or `inactive`.
+// IdentityState The state can either be `active`, `inactive`, `suspended`, or `pending_deletion`.
 type IdentityState string
 
 // List of identityState
 const (
-	IDENTITYSTATE_ACTIVE   IdentityState = "active"
-	IDENTITYSTATE_INACTIVE IdentityState = "inactive"
+	IDENTITYSTATE_ACTIVE           IdentityState = "active"
+	IDENTITYSTATE_INACTIVE         IdentityState = "inactive"
+	IDENTITYSTATE_SUSPENDED        IdentityState = "suspended"
+	IDENTITYSTATE_PENDING_DELETION IdentityState = "pending_deletion"
 )
 
 func (v *IdentityState) UnmarshalJSON(src []byte) error {
@@ -32,7 +34,7 @@ func (v *IdentityState) UnmarshalJSON(src []byte) error {
 		return err
 	}
 	enumTypeValue := IdentityState(value)
-	for _, existing := range []IdentityState{"active", "inactive"} {
+	for _, existing := range []IdentityState{"active", "inactive", "suspended", "pending_deletion"} {
 		if existing == enumTypeValue {
 			*v = enumTypeValue
 			return nil
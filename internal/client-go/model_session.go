@@ -34,6 +34,8 @@ type Session struct {
 	Identity Identity `json:"identity"`
 	// The Session Issuance Timestamp  When this session was issued at. Usually equal or close to `authenticated_at`.
 	IssuedAt *time.Time `json:"issued_at,omitempty"`
+	// Tokenized is the tokenized (e.g. JWT) version of the session.  It is only set when the `tokenize_as` query parameter was set to a valid tokenize template during calls to `/session/whoami`.
+	Tokenized *string `json:"tokenized,omitempty"`
 }
 
 // NewSession instantiates a new Session object
@@ -327,6 +329,38 @@ func (o *Session) SetIssuedAt(v time.Time) {
 	o.IssuedAt = &v
 }
 
+// GetTokenized returns the Tokenized field value if set, zero value otherwise.
+func (o *Session) GetTokenized() string {
+	if o == nil || o.Tokenized == nil {
+		var ret string
+		return ret
+	}
+	return *o.Tokenized
+}
+
+// GetTokenizedOk returns a tuple with the Tokenized field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *Session) GetTokenizedOk() (*string, bool) {
+	if o == nil || o.Tokenized == nil {
+		return nil, false
+	}
+	return o.Tokenized, true
+}
+
+// HasTokenized returns a boolean if a field has been set.
+func (o *Session) HasTokenized() bool {
+	if o != nil && o.Tokenized != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetTokenized gets a reference to the given string and assigns it to the Tokenized field.
+func (o *Session) SetTokenized(v string) {
+	o.Tokenized = &v
+}
+
 func (o Session) MarshalJSON() ([]byte, error) {
 	toSerialize := map[string]interface{}{}
 	if o.Active != nil {
@@ -356,6 +390,9 @@ func (o Session) MarshalJSON() ([]byte, error) {
 	if o.IssuedAt != nil {
 		toSerialize["issued_at"] = o.IssuedAt
 	}
+	if o.Tokenized != nil {
+		toSerialize["tokenized"] = o.Tokenized
+	}
 	return json.Marshal(toSerialize)
 }
 
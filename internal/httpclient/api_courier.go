@@ -27,6 +27,21 @@ var (
 
 type CourierApi interface {
 
+	/*
+	 * CancelCourierMessage Cancel a Queued Message
+	 * Cancels a message which is still queued, so that the courier does not deliver it.
+	 * @param ctx context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+	 * @param id MessageID is the ID of the message.
+	 * @return CourierApiApiCancelCourierMessageRequest
+	 */
+	CancelCourierMessage(ctx context.Context, id string) CourierApiApiCancelCourierMessageRequest
+
+	/*
+	 * CancelCourierMessageExecute executes the request
+	 * @return Message
+	 */
+	CancelCourierMessageExecute(r CourierApiApiCancelCourierMessageRequest) (*Message, *http.Response, error)
+
 	/*
 	 * GetCourierMessage Get a Message
 	 * Gets a specific messages by the given ID.
@@ -42,6 +57,21 @@ type CourierApi interface {
 	 */
 	GetCourierMessageExecute(r CourierApiApiGetCourierMessageRequest) (*Message, *http.Response, error)
 
+	/*
+	 * ListCourierDeadLetters List Dead Letters
+	 * Lists all messages which were abandoned because they could not be delivered, together with
+	 * the errors of all attempts to deliver them.
+	 * @param ctx context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+	 * @return CourierApiApiListCourierDeadLettersRequest
+	 */
+	ListCourierDeadLetters(ctx context.Context) CourierApiApiListCourierDeadLettersRequest
+
+	/*
+	 * ListCourierDeadLettersExecute executes the request
+	 * @return []Message
+	 */
+	ListCourierDeadLettersExecute(r CourierApiApiListCourierDeadLettersRequest) ([]Message, *http.Response, error)
+
 	/*
 	 * ListCourierMessages List Messages
 	 * Lists all messages by given status and recipient.
@@ -55,11 +85,213 @@ type CourierApi interface {
 	 * @return []Message
 	 */
 	ListCourierMessagesExecute(r CourierApiApiListCourierMessagesRequest) ([]Message, *http.Response, error)
+
+	/*
+	 * PurgeCourierMessages Purge Messages
+	 * Deletes sent, abandoned, and cancelled messages older than the given age, including their
+	 * dispatch history. Queued messages and messages which are being processed are never purged.
+	 * @param ctx context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+	 * @return CourierApiApiPurgeCourierMessagesRequest
+	 */
+	PurgeCourierMessages(ctx context.Context) CourierApiApiPurgeCourierMessagesRequest
+
+	/*
+	 * PurgeCourierMessagesExecute executes the request
+	 * @return CourierMessageCount
+	 */
+	PurgeCourierMessagesExecute(r CourierApiApiPurgeCourierMessagesRequest) (*CourierMessageCount, *http.Response, error)
+
+	/*
+	 * RetryCourierDeadLetters Retry Dead Letters
+	 * Queues all abandoned messages matching the filter again and resets their send count, so
+	 * that the courier retries to deliver them. An empty filter matches all abandoned messages.
+	 * @param ctx context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+	 * @return CourierApiApiRetryCourierDeadLettersRequest
+	 */
+	RetryCourierDeadLetters(ctx context.Context) CourierApiApiRetryCourierDeadLettersRequest
+
+	/*
+	 * RetryCourierDeadLettersExecute executes the request
+	 * @return CourierMessageCount
+	 */
+	RetryCourierDeadLettersExecute(r CourierApiApiRetryCourierDeadLettersRequest) (*CourierMessageCount, *http.Response, error)
+
+	/*
+	 * RetryCourierMessage Retry an Abandoned Message
+	 * Queues an abandoned message again and resets its send count, so that the courier retries
+	 * to deliver it. The dispatch history of the message is kept.
+	 * @param ctx context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+	 * @param id MessageID is the ID of the message.
+	 * @return CourierApiApiRetryCourierMessageRequest
+	 */
+	RetryCourierMessage(ctx context.Context, id string) CourierApiApiRetryCourierMessageRequest
+
+	/*
+	 * RetryCourierMessageExecute executes the request
+	 * @return Message
+	 */
+	RetryCourierMessageExecute(r CourierApiApiRetryCourierMessageRequest) (*Message, *http.Response, error)
 }
 
 // CourierApiService CourierApi service
 type CourierApiService service
 
+type CourierApiApiCancelCourierMessageRequest struct {
+	ctx        context.Context
+	ApiService CourierApi
+	id         string
+}
+
+func (r CourierApiApiCancelCourierMessageRequest) Execute() (*Message, *http.Response, error) {
+	return r.ApiService.CancelCourierMessageExecute(r)
+}
+
+/*
+ * CancelCourierMessage Cancel a Queued Message
+ * Cancels a message which is still queued, so that the courier does not deliver it.
+ * @param ctx context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+ * @param id MessageID is the ID of the message.
+ * @return CourierApiApiCancelCourierMessageRequest
+ */
+func (a *CourierApiService) CancelCourierMessage(ctx context.Context, id string) CourierApiApiCancelCourierMessageRequest {
+	return CourierApiApiCancelCourierMessageRequest{
+		ApiService: a,
+		ctx:        ctx,
+		id:         id,
+	}
+}
+
+/*
+ * Execute executes the request
+ * @return Message
+ */
+func (a *CourierApiService) CancelCourierMessageExecute(r CourierApiApiCancelCourierMessageRequest) (*Message, *http.Response, error) {
+	var (
+		localVarHTTPMethod   = http.MethodPost
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+		localVarReturnValue  *Message
+	)
+
+	localBasePath, err := a.client.cfg.ServerURLWithContext(r.ctx, "CourierApiService.CancelCourierMessage")
+	if err != nil {
+		return localVarReturnValue, nil, &GenericOpenAPIError{error: err.Error()}
+	}
+
+	localVarPath := localBasePath + "/admin/courier/messages/{id}/cancel"
+	localVarPath = strings.Replace(localVarPath, "{"+"id"+"}", url.PathEscape(parameterToString(r.id, "")), -1)
+
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := url.Values{}
+	localVarFormParams := url.Values{}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	if r.ctx != nil {
+		// API Key Authentication
+		if auth, ok := r.ctx.Value(ContextAPIKeys).(map[string]APIKey); ok {
+			if apiKey, ok := auth["oryAccessToken"]; ok {
+				var key string
+				if apiKey.Prefix != "" {
+					key = apiKey.Prefix + " " + apiKey.Key
+				} else {
+					key = apiKey.Key
+				}
+				localVarHeaderParams["Authorization"] = key
+			}
+		}
+	}
+	req, err := a.client.prepareRequest(r.ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(req)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := io.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	localVarHTTPResponse.Body = io.NopCloser(bytes.NewBuffer(localVarBody))
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := &GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		if localVarHTTPResponse.StatusCode == 400 {
+			var v ErrorGeneric
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+			return localVarReturnValue, localVarHTTPResponse, newErr
+		}
+		if localVarHTTPResponse.StatusCode == 404 {
+			var v ErrorGeneric
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+			return localVarReturnValue, localVarHTTPResponse, newErr
+		}
+		if localVarHTTPResponse.StatusCode == 409 {
+			var v ErrorGeneric
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+			return localVarReturnValue, localVarHTTPResponse, newErr
+		}
+		var v ErrorGeneric
+		err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+		if err != nil {
+			newErr.error = err.Error()
+			return localVarReturnValue, localVarHTTPResponse, newErr
+		}
+		newErr.model = v
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := &GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
+
 type CourierApiApiGetCourierMessageRequest struct {
 	ctx        context.Context
 	ApiService CourierApi
@@ -196,44 +428,40 @@ func (a *CourierApiService) GetCourierMessageExecute(r CourierApiApiGetCourierMe
 	return localVarReturnValue, localVarHTTPResponse, nil
 }
 
-type CourierApiApiListCourierMessagesRequest struct {
+type CourierApiApiListCourierDeadLettersRequest struct {
 	ctx        context.Context
 	ApiService CourierApi
 	pageSize   *int64
 	pageToken  *string
-	status     *CourierMessageStatus
 	recipient  *string
 }
 
-func (r CourierApiApiListCourierMessagesRequest) PageSize(pageSize int64) CourierApiApiListCourierMessagesRequest {
+func (r CourierApiApiListCourierDeadLettersRequest) PageSize(pageSize int64) CourierApiApiListCourierDeadLettersRequest {
 	r.pageSize = &pageSize
 	return r
 }
-func (r CourierApiApiListCourierMessagesRequest) PageToken(pageToken string) CourierApiApiListCourierMessagesRequest {
+func (r CourierApiApiListCourierDeadLettersRequest) PageToken(pageToken string) CourierApiApiListCourierDeadLettersRequest {
 	r.pageToken = &pageToken
 	return r
 }
-func (r CourierApiApiListCourierMessagesRequest) Status(status CourierMessageStatus) CourierApiApiListCourierMessagesRequest {
-	r.status = &status
-	return r
-}
-func (r CourierApiApiListCourierMessagesRequest) Recipient(recipient string) CourierApiApiListCourierMessagesRequest {
+func (r CourierApiApiListCourierDeadLettersRequest) Recipient(recipient string) CourierApiApiListCourierDeadLettersRequest {
 	r.recipient = &recipient
 	return r
 }
 
-func (r CourierApiApiListCourierMessagesRequest) Execute() ([]Message, *http.Response, error) {
-	return r.ApiService.ListCourierMessagesExecute(r)
+func (r CourierApiApiListCourierDeadLettersRequest) Execute() ([]Message, *http.Response, error) {
+	return r.ApiService.ListCourierDeadLettersExecute(r)
 }
 
 /*
- * ListCourierMessages List Messages
- * Lists all messages by given status and recipient.
+ * ListCourierDeadLetters List Dead Letters
+ * Lists all messages which were abandoned because they could not be delivered, together with
+ * the errors of all attempts to deliver them.
  * @param ctx context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
- * @return CourierApiApiListCourierMessagesRequest
+ * @return CourierApiApiListCourierDeadLettersRequest
  */
-func (a *CourierApiService) ListCourierMessages(ctx context.Context) CourierApiApiListCourierMessagesRequest {
-	return CourierApiApiListCourierMessagesRequest{
+func (a *CourierApiService) ListCourierDeadLetters(ctx context.Context) CourierApiApiListCourierDeadLettersRequest {
+	return CourierApiApiListCourierDeadLettersRequest{
 		ApiService: a,
 		ctx:        ctx,
 	}
@@ -243,7 +471,7 @@ func (a *CourierApiService) ListCourierMessages(ctx context.Context) CourierApiA
  * Execute executes the request
  * @return []Message
  */
-func (a *CourierApiService) ListCourierMessagesExecute(r CourierApiApiListCourierMessagesRequest) ([]Message, *http.Response, error) {
+func (a *CourierApiService) ListCourierDeadLettersExecute(r CourierApiApiListCourierDeadLettersRequest) ([]Message, *http.Response, error) {
 	var (
 		localVarHTTPMethod   = http.MethodGet
 		localVarPostBody     interface{}
@@ -253,12 +481,12 @@ func (a *CourierApiService) ListCourierMessagesExecute(r CourierApiApiListCourie
 		localVarReturnValue  []Message
 	)
 
-	localBasePath, err := a.client.cfg.ServerURLWithContext(r.ctx, "CourierApiService.ListCourierMessages")
+	localBasePath, err := a.client.cfg.ServerURLWithContext(r.ctx, "CourierApiService.ListCourierDeadLetters")
 	if err != nil {
 		return localVarReturnValue, nil, &GenericOpenAPIError{error: err.Error()}
 	}
 
-	localVarPath := localBasePath + "/admin/courier/messages"
+	localVarPath := localBasePath + "/admin/courier/dead-letters"
 
 	localVarHeaderParams := make(map[string]string)
 	localVarQueryParams := url.Values{}
@@ -270,9 +498,6 @@ func (a *CourierApiService) ListCourierMessagesExecute(r CourierApiApiListCourie
 	if r.pageToken != nil {
 		localVarQueryParams.Add("page_token", parameterToString(*r.pageToken, ""))
 	}
-	if r.status != nil {
-		localVarQueryParams.Add("status", parameterToString(*r.status, ""))
-	}
 	if r.recipient != nil {
 		localVarQueryParams.Add("recipient", parameterToString(*r.recipient, ""))
 	}
@@ -360,3 +585,617 @@ func (a *CourierApiService) ListCourierMessagesExecute(r CourierApiApiListCourie
 
 	return localVarReturnValue, localVarHTTPResponse, nil
 }
+
+type CourierApiApiListCourierMessagesRequest struct {
+	ctx        context.Context
+	ApiService CourierApi
+	pageSize   *int64
+	pageToken  *string
+	status     *CourierMessageStatus
+	recipient  *string
+}
+
+func (r CourierApiApiListCourierMessagesRequest) PageSize(pageSize int64) CourierApiApiListCourierMessagesRequest {
+	r.pageSize = &pageSize
+	return r
+}
+func (r CourierApiApiListCourierMessagesRequest) PageToken(pageToken string) CourierApiApiListCourierMessagesRequest {
+	r.pageToken = &pageToken
+	return r
+}
+func (r CourierApiApiListCourierMessagesRequest) Status(status CourierMessageStatus) CourierApiApiListCourierMessagesRequest {
+	r.status = &status
+	return r
+}
+func (r CourierApiApiListCourierMessagesRequest) Recipient(recipient string) CourierApiApiListCourierMessagesRequest {
+	r.recipient = &recipient
+	return r
+}
+
+func (r CourierApiApiListCourierMessagesRequest) Execute() ([]Message, *http.Response, error) {
+	return r.ApiService.ListCourierMessagesExecute(r)
+}
+
+/*
+ * ListCourierMessages List Messages
+ * Lists all messages by given status and recipient.
+ * @param ctx context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+ * @return CourierApiApiListCourierMessagesRequest
+ */
+func (a *CourierApiService) ListCourierMessages(ctx context.Context) CourierApiApiListCourierMessagesRequest {
+	return CourierApiApiListCourierMessagesRequest{
+		ApiService: a,
+		ctx:        ctx,
+	}
+}
+
+/*
+ * Execute executes the request
+ * @return []Message
+ */
+func (a *CourierApiService) ListCourierMessagesExecute(r CourierApiApiListCourierMessagesRequest) ([]Message, *http.Response, error) {
+	var (
+		localVarHTTPMethod   = http.MethodGet
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+		localVarReturnValue  []Message
+	)
+
+	localBasePath, err := a.client.cfg.ServerURLWithContext(r.ctx, "CourierApiService.ListCourierMessages")
+	if err != nil {
+		return localVarReturnValue, nil, &GenericOpenAPIError{error: err.Error()}
+	}
+
+	localVarPath := localBasePath + "/admin/courier/messages"
+
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := url.Values{}
+	localVarFormParams := url.Values{}
+
+	if r.pageSize != nil {
+		localVarQueryParams.Add("page_size", parameterToString(*r.pageSize, ""))
+	}
+	if r.pageToken != nil {
+		localVarQueryParams.Add("page_token", parameterToString(*r.pageToken, ""))
+	}
+	if r.status != nil {
+		localVarQueryParams.Add("status", parameterToString(*r.status, ""))
+	}
+	if r.recipient != nil {
+		localVarQueryParams.Add("recipient", parameterToString(*r.recipient, ""))
+	}
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	if r.ctx != nil {
+		// API Key Authentication
+		if auth, ok := r.ctx.Value(ContextAPIKeys).(map[string]APIKey); ok {
+			if apiKey, ok := auth["oryAccessToken"]; ok {
+				var key string
+				if apiKey.Prefix != "" {
+					key = apiKey.Prefix + " " + apiKey.Key
+				} else {
+					key = apiKey.Key
+				}
+				localVarHeaderParams["Authorization"] = key
+			}
+		}
+	}
+	req, err := a.client.prepareRequest(r.ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(req)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := io.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	localVarHTTPResponse.Body = io.NopCloser(bytes.NewBuffer(localVarBody))
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := &GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		if localVarHTTPResponse.StatusCode == 400 {
+			var v ErrorGeneric
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+			return localVarReturnValue, localVarHTTPResponse, newErr
+		}
+		var v ErrorGeneric
+		err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+		if err != nil {
+			newErr.error = err.Error()
+			return localVarReturnValue, localVarHTTPResponse, newErr
+		}
+		newErr.model = v
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := &GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
+
+type CourierApiApiPurgeCourierMessagesRequest struct {
+	ctx        context.Context
+	ApiService CourierApi
+	olderThan  *string
+	status     *CourierMessageStatus
+}
+
+func (r CourierApiApiPurgeCourierMessagesRequest) OlderThan(olderThan string) CourierApiApiPurgeCourierMessagesRequest {
+	r.olderThan = &olderThan
+	return r
+}
+func (r CourierApiApiPurgeCourierMessagesRequest) Status(status CourierMessageStatus) CourierApiApiPurgeCourierMessagesRequest {
+	r.status = &status
+	return r
+}
+
+func (r CourierApiApiPurgeCourierMessagesRequest) Execute() (*CourierMessageCount, *http.Response, error) {
+	return r.ApiService.PurgeCourierMessagesExecute(r)
+}
+
+/*
+ * PurgeCourierMessages Purge Messages
+ * Deletes sent, abandoned, and cancelled messages older than the given age, including their
+ * dispatch history. Queued messages and messages which are being processed are never purged.
+ * @param ctx context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+ * @return CourierApiApiPurgeCourierMessagesRequest
+ */
+func (a *CourierApiService) PurgeCourierMessages(ctx context.Context) CourierApiApiPurgeCourierMessagesRequest {
+	return CourierApiApiPurgeCourierMessagesRequest{
+		ApiService: a,
+		ctx:        ctx,
+	}
+}
+
+/*
+ * Execute executes the request
+ * @return CourierMessageCount
+ */
+func (a *CourierApiService) PurgeCourierMessagesExecute(r CourierApiApiPurgeCourierMessagesRequest) (*CourierMessageCount, *http.Response, error) {
+	var (
+		localVarHTTPMethod   = http.MethodDelete
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+		localVarReturnValue  *CourierMessageCount
+	)
+
+	localBasePath, err := a.client.cfg.ServerURLWithContext(r.ctx, "CourierApiService.PurgeCourierMessages")
+	if err != nil {
+		return localVarReturnValue, nil, &GenericOpenAPIError{error: err.Error()}
+	}
+
+	localVarPath := localBasePath + "/admin/courier/messages"
+
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := url.Values{}
+	localVarFormParams := url.Values{}
+	if r.olderThan == nil {
+		return localVarReturnValue, nil, reportError("olderThan is required and must be specified")
+	}
+
+	localVarQueryParams.Add("older_than", parameterToString(*r.olderThan, ""))
+	if r.status != nil {
+		localVarQueryParams.Add("status", parameterToString(*r.status, ""))
+	}
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	if r.ctx != nil {
+		// API Key Authentication
+		if auth, ok := r.ctx.Value(ContextAPIKeys).(map[string]APIKey); ok {
+			if apiKey, ok := auth["oryAccessToken"]; ok {
+				var key string
+				if apiKey.Prefix != "" {
+					key = apiKey.Prefix + " " + apiKey.Key
+				} else {
+					key = apiKey.Key
+				}
+				localVarHeaderParams["Authorization"] = key
+			}
+		}
+	}
+	req, err := a.client.prepareRequest(r.ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(req)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := io.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	localVarHTTPResponse.Body = io.NopCloser(bytes.NewBuffer(localVarBody))
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := &GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		if localVarHTTPResponse.StatusCode == 400 {
+			var v ErrorGeneric
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+			return localVarReturnValue, localVarHTTPResponse, newErr
+		}
+		var v ErrorGeneric
+		err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+		if err != nil {
+			newErr.error = err.Error()
+			return localVarReturnValue, localVarHTTPResponse, newErr
+		}
+		newErr.model = v
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := &GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
+
+type CourierApiApiRetryCourierDeadLettersRequest struct {
+	ctx                     context.Context
+	ApiService              CourierApi
+	courierDeadLetterFilter *CourierDeadLetterFilter
+}
+
+func (r CourierApiApiRetryCourierDeadLettersRequest) CourierDeadLetterFilter(courierDeadLetterFilter CourierDeadLetterFilter) CourierApiApiRetryCourierDeadLettersRequest {
+	r.courierDeadLetterFilter = &courierDeadLetterFilter
+	return r
+}
+
+func (r CourierApiApiRetryCourierDeadLettersRequest) Execute() (*CourierMessageCount, *http.Response, error) {
+	return r.ApiService.RetryCourierDeadLettersExecute(r)
+}
+
+/*
+ * RetryCourierDeadLetters Retry Dead Letters
+ * Queues all abandoned messages matching the filter again and resets their send count, so
+ * that the courier retries to deliver them. An empty filter matches all abandoned messages.
+ * @param ctx context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+ * @return CourierApiApiRetryCourierDeadLettersRequest
+ */
+func (a *CourierApiService) RetryCourierDeadLetters(ctx context.Context) CourierApiApiRetryCourierDeadLettersRequest {
+	return CourierApiApiRetryCourierDeadLettersRequest{
+		ApiService: a,
+		ctx:        ctx,
+	}
+}
+
+/*
+ * Execute executes the request
+ * @return CourierMessageCount
+ */
+func (a *CourierApiService) RetryCourierDeadLettersExecute(r CourierApiApiRetryCourierDeadLettersRequest) (*CourierMessageCount, *http.Response, error) {
+	var (
+		localVarHTTPMethod   = http.MethodPost
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+		localVarReturnValue  *CourierMessageCount
+	)
+
+	localBasePath, err := a.client.cfg.ServerURLWithContext(r.ctx, "CourierApiService.RetryCourierDeadLetters")
+	if err != nil {
+		return localVarReturnValue, nil, &GenericOpenAPIError{error: err.Error()}
+	}
+
+	localVarPath := localBasePath + "/admin/courier/dead-letters/retry"
+
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := url.Values{}
+	localVarFormParams := url.Values{}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{"application/json"}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	// body params
+	localVarPostBody = r.courierDeadLetterFilter
+	if r.ctx != nil {
+		// API Key Authentication
+		if auth, ok := r.ctx.Value(ContextAPIKeys).(map[string]APIKey); ok {
+			if apiKey, ok := auth["oryAccessToken"]; ok {
+				var key string
+				if apiKey.Prefix != "" {
+					key = apiKey.Prefix + " " + apiKey.Key
+				} else {
+					key = apiKey.Key
+				}
+				localVarHeaderParams["Authorization"] = key
+			}
+		}
+	}
+	req, err := a.client.prepareRequest(r.ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(req)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := io.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	localVarHTTPResponse.Body = io.NopCloser(bytes.NewBuffer(localVarBody))
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := &GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		if localVarHTTPResponse.StatusCode == 400 {
+			var v ErrorGeneric
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+			return localVarReturnValue, localVarHTTPResponse, newErr
+		}
+		var v ErrorGeneric
+		err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+		if err != nil {
+			newErr.error = err.Error()
+			return localVarReturnValue, localVarHTTPResponse, newErr
+		}
+		newErr.model = v
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := &GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
+
+type CourierApiApiRetryCourierMessageRequest struct {
+	ctx        context.Context
+	ApiService CourierApi
+	id         string
+}
+
+func (r CourierApiApiRetryCourierMessageRequest) Execute() (*Message, *http.Response, error) {
+	return r.ApiService.RetryCourierMessageExecute(r)
+}
+
+/*
+ * RetryCourierMessage Retry an Abandoned Message
+ * Queues an abandoned message again and resets its send count, so that the courier retries
+ * to deliver it. The dispatch history of the message is kept.
+ * @param ctx context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+ * @param id MessageID is the ID of the message.
+ * @return CourierApiApiRetryCourierMessageRequest
+ */
+func (a *CourierApiService) RetryCourierMessage(ctx context.Context, id string) CourierApiApiRetryCourierMessageRequest {
+	return CourierApiApiRetryCourierMessageRequest{
+		ApiService: a,
+		ctx:        ctx,
+		id:         id,
+	}
+}
+
+/*
+ * Execute executes the request
+ * @return Message
+ */
+func (a *CourierApiService) RetryCourierMessageExecute(r CourierApiApiRetryCourierMessageRequest) (*Message, *http.Response, error) {
+	var (
+		localVarHTTPMethod   = http.MethodPost
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+		localVarReturnValue  *Message
+	)
+
+	localBasePath, err := a.client.cfg.ServerURLWithContext(r.ctx, "CourierApiService.RetryCourierMessage")
+	if err != nil {
+		return localVarReturnValue, nil, &GenericOpenAPIError{error: err.Error()}
+	}
+
+	localVarPath := localBasePath + "/admin/courier/messages/{id}/retry"
+	localVarPath = strings.Replace(localVarPath, "{"+"id"+"}", url.PathEscape(parameterToString(r.id, "")), -1)
+
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := url.Values{}
+	localVarFormParams := url.Values{}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	if r.ctx != nil {
+		// API Key Authentication
+		if auth, ok := r.ctx.Value(ContextAPIKeys).(map[string]APIKey); ok {
+			if apiKey, ok := auth["oryAccessToken"]; ok {
+				var key string
+				if apiKey.Prefix != "" {
+					key = apiKey.Prefix + " " + apiKey.Key
+				} else {
+					key = apiKey.Key
+				}
+				localVarHeaderParams["Authorization"] = key
+			}
+		}
+	}
+	req, err := a.client.prepareRequest(r.ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(req)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := io.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	localVarHTTPResponse.Body = io.NopCloser(bytes.NewBuffer(localVarBody))
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := &GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		if localVarHTTPResponse.StatusCode == 400 {
+			var v ErrorGeneric
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+			return localVarReturnValue, localVarHTTPResponse, newErr
+		}
+		if localVarHTTPResponse.StatusCode == 404 {
+			var v ErrorGeneric
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+			return localVarReturnValue, localVarHTTPResponse, newErr
+		}
+		if localVarHTTPResponse.StatusCode == 409 {
+			var v ErrorGeneric
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+			return localVarReturnValue, localVarHTTPResponse, newErr
+		}
+		var v ErrorGeneric
+		err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+		if err != nil {
+			newErr.error = err.Error()
+			return localVarReturnValue, localVarHTTPResponse, newErr
+		}
+		newErr.model = v
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := &GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
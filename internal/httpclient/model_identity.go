@@ -22,6 +22,7 @@ type Identity struct {
 	CreatedAt *time.Time `json:"created_at,omitempty"`
 	// Credentials represents all credentials that can be used for authenticating this identity.
 	Credentials *map[string]IdentityCredentials `json:"credentials,omitempty"`
+	DeleteAfter *time.Time                      `json:"delete_after,omitempty"`
 	// ID is the identity's unique identifier.  The Identity ID can not be changed and can not be chosen. This ensures future compatibility and optimization for distributed stores such as CockroachDB.
 	Id string `json:"id"`
 	// NullJSONRawMessage represents a json.RawMessage that works well with JSON, SQL, and Swagger and is NULLable-
@@ -36,6 +37,11 @@ type Identity struct {
 	SchemaUrl      string         `json:"schema_url"`
 	State          *IdentityState `json:"state,omitempty"`
 	StateChangedAt *time.Time     `json:"state_changed_at,omitempty"`
+	// StateNote is a note about the identity's state which is only accessible through admin APIs.
+	StateNote *string `json:"state_note,omitempty"`
+	// StateReason is the reason code of the identity's state, for example `fraud` or `chargeback` for a suspended identity. It is only accessible through admin APIs.
+	StateReason    *string    `json:"state_reason,omitempty"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
 	// Traits represent an identity's traits. The identity is able to create, modify, and delete traits in a self-service manner. The input will always be validated against the JSON Schema defined in `schema_url`.
 	Traits interface{} `json:"traits"`
 	// UpdatedAt is a helper struct field for gobuffalo.pop.
@@ -129,6 +135,38 @@ func (o *Identity) SetCredentials(v map[string]IdentityCredentials) {
 	o.Credentials = &v
 }
 
+// GetDeleteAfter returns the DeleteAfter field value if set, zero value otherwise.
+func (o *Identity) GetDeleteAfter() time.Time {
+	if o == nil || o.DeleteAfter == nil {
+		var ret time.Time
+		return ret
+	}
+	return *o.DeleteAfter
+}
+
+// GetDeleteAfterOk returns a tuple with the DeleteAfter field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *Identity) GetDeleteAfterOk() (*time.Time, bool) {
+	if o == nil || o.DeleteAfter == nil {
+		return nil, false
+	}
+	return o.DeleteAfter, true
+}
+
+// HasDeleteAfter returns a boolean if a field has been set.
+func (o *Identity) HasDeleteAfter() bool {
+	if o != nil && o.DeleteAfter != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetDeleteAfter gets a reference to the given time.Time and assigns it to the DeleteAfter field.
+func (o *Identity) SetDeleteAfter(v time.Time) {
+	o.DeleteAfter = &v
+}
+
 // GetId returns the Id field value
 func (o *Identity) GetId() string {
 	if o == nil {
@@ -363,6 +401,102 @@ func (o *Identity) SetStateChangedAt(v time.Time) {
 	o.StateChangedAt = &v
 }
 
+// GetStateNote returns the StateNote field value if set, zero value otherwise.
+func (o *Identity) GetStateNote() string {
+	if o == nil || o.StateNote == nil {
+		var ret string
+		return ret
+	}
+	return *o.StateNote
+}
+
+// GetStateNoteOk returns a tuple with the StateNote field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *Identity) GetStateNoteOk() (*string, bool) {
+	if o == nil || o.StateNote == nil {
+		return nil, false
+	}
+	return o.StateNote, true
+}
+
+// HasStateNote returns a boolean if a field has been set.
+func (o *Identity) HasStateNote() bool {
+	if o != nil && o.StateNote != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetStateNote gets a reference to the given string and assigns it to the StateNote field.
+func (o *Identity) SetStateNote(v string) {
+	o.StateNote = &v
+}
+
+// GetStateReason returns the StateReason field value if set, zero value otherwise.
+func (o *Identity) GetStateReason() string {
+	if o == nil || o.StateReason == nil {
+		var ret string
+		return ret
+	}
+	return *o.StateReason
+}
+
+// GetStateReasonOk returns a tuple with the StateReason field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *Identity) GetStateReasonOk() (*string, bool) {
+	if o == nil || o.StateReason == nil {
+		return nil, false
+	}
+	return o.StateReason, true
+}
+
+// HasStateReason returns a boolean if a field has been set.
+func (o *Identity) HasStateReason() bool {
+	if o != nil && o.StateReason != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetStateReason gets a reference to the given string and assigns it to the StateReason field.
+func (o *Identity) SetStateReason(v string) {
+	o.StateReason = &v
+}
+
+// GetSuspendedUntil returns the SuspendedUntil field value if set, zero value otherwise.
+func (o *Identity) GetSuspendedUntil() time.Time {
+	if o == nil || o.SuspendedUntil == nil {
+		var ret time.Time
+		return ret
+	}
+	return *o.SuspendedUntil
+}
+
+// GetSuspendedUntilOk returns a tuple with the SuspendedUntil field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *Identity) GetSuspendedUntilOk() (*time.Time, bool) {
+	if o == nil || o.SuspendedUntil == nil {
+		return nil, false
+	}
+	return o.SuspendedUntil, true
+}
+
+// HasSuspendedUntil returns a boolean if a field has been set.
+func (o *Identity) HasSuspendedUntil() bool {
+	if o != nil && o.SuspendedUntil != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetSuspendedUntil gets a reference to the given time.Time and assigns it to the SuspendedUntil field.
+func (o *Identity) SetSuspendedUntil(v time.Time) {
+	o.SuspendedUntil = &v
+}
+
 // GetTraits returns the Traits field value
 // If the value is explicit nil, the zero value for interface{} will be returned
 func (o *Identity) GetTraits() interface{} {
@@ -461,6 +595,9 @@ func (o Identity) MarshalJSON() ([]byte, error) {
 	if o.Credentials != nil {
 		toSerialize["credentials"] = o.Credentials
 	}
+	if o.DeleteAfter != nil {
+		toSerialize["delete_after"] = o.DeleteAfter
+	}
 	if true {
 		toSerialize["id"] = o.Id
 	}
@@ -485,6 +622,15 @@ func (o Identity) MarshalJSON() ([]byte, error) {
 	if o.StateChangedAt != nil {
 		toSerialize["state_changed_at"] = o.StateChangedAt
 	}
+	if o.StateNote != nil {
+		toSerialize["state_note"] = o.StateNote
+	}
+	if o.StateReason != nil {
+		toSerialize["state_reason"] = o.StateReason
+	}
+	if o.SuspendedUntil != nil {
+		toSerialize["suspended_until"] = o.SuspendedUntil
+	}
 	if o.Traits != nil {
 		toSerialize["traits"] = o.Traits
 	}
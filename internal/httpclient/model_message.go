@@ -19,18 +19,24 @@ import (
 // Message struct for Message
 type Message struct {
 	Body string `json:"body"`
+	// Channel is the ID of the channel the message is delivered through. Messages without a channel are delivered through the default channel of their type.
+	Channel *string `json:"channel,omitempty"`
 	// CreatedAt is a helper struct field for gobuffalo.pop.
 	CreatedAt time.Time `json:"created_at"`
 	// Dispatches store information about the attempts of delivering a message May contain an error if any happened, or just the `success` state.
-	Dispatches []MessageDispatch    `json:"dispatches,omitempty"`
-	Id         string               `json:"id"`
-	Recipient  string               `json:"recipient"`
-	SendCount  int64                `json:"send_count"`
-	Status     CourierMessageStatus `json:"status"`
-	Subject    string               `json:"subject"`
+	Dispatches []MessageDispatch `json:"dispatches,omitempty"`
+	Id         string            `json:"id"`
+	// Locale holds the language tags the message is rendered in, in order of preference, such as \"de-CH,de\". Messages without a locale are rendered with the default templates.
+	Locale    *string              `json:"locale,omitempty"`
+	Recipient string               `json:"recipient"`
+	SendCount int64                `json:"send_count"`
+	Status    CourierMessageStatus `json:"status"`
+	Subject   string               `json:"subject"`
 	//  recovery_invalid TypeRecoveryInvalid recovery_valid TypeRecoveryValid recovery_code_invalid TypeRecoveryCodeInvalid recovery_code_valid TypeRecoveryCodeValid verification_invalid TypeVerificationInvalid verification_valid TypeVerificationValid verification_code_invalid TypeVerificationCodeInvalid verification_code_valid TypeVerificationCodeValid otp TypeOTP stub TypeTestStub
-	TemplateType string             `json:"template_type"`
-	Type         CourierMessageType `json:"type"`
+	TemplateType string `json:"template_type"`
+	// ThrottledUntil is set when a rate limit deferred the delivery of the message. The courier does not try to deliver the message before this time.
+	ThrottledUntil *time.Time         `json:"throttled_until,omitempty"`
+	Type           CourierMessageType `json:"type"`
 	// UpdatedAt is a helper struct field for gobuffalo.pop.
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -86,6 +92,38 @@ func (o *Message) SetBody(v string) {
 	o.Body = v
 }
 
+// GetChannel returns the Channel field value if set, zero value otherwise.
+func (o *Message) GetChannel() string {
+	if o == nil || o.Channel == nil {
+		var ret string
+		return ret
+	}
+	return *o.Channel
+}
+
+// GetChannelOk returns a tuple with the Channel field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *Message) GetChannelOk() (*string, bool) {
+	if o == nil || o.Channel == nil {
+		return nil, false
+	}
+	return o.Channel, true
+}
+
+// HasChannel returns a boolean if a field has been set.
+func (o *Message) HasChannel() bool {
+	if o != nil && o.Channel != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetChannel gets a reference to the given string and assigns it to the Channel field.
+func (o *Message) SetChannel(v string) {
+	o.Channel = &v
+}
+
 // GetCreatedAt returns the CreatedAt field value
 func (o *Message) GetCreatedAt() time.Time {
 	if o == nil {
@@ -166,6 +204,38 @@ func (o *Message) SetId(v string) {
 	o.Id = v
 }
 
+// GetLocale returns the Locale field value if set, zero value otherwise.
+func (o *Message) GetLocale() string {
+	if o == nil || o.Locale == nil {
+		var ret string
+		return ret
+	}
+	return *o.Locale
+}
+
+// GetLocaleOk returns a tuple with the Locale field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *Message) GetLocaleOk() (*string, bool) {
+	if o == nil || o.Locale == nil {
+		return nil, false
+	}
+	return o.Locale, true
+}
+
+// HasLocale returns a boolean if a field has been set.
+func (o *Message) HasLocale() bool {
+	if o != nil && o.Locale != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetLocale gets a reference to the given string and assigns it to the Locale field.
+func (o *Message) SetLocale(v string) {
+	o.Locale = &v
+}
+
 // GetRecipient returns the Recipient field value
 func (o *Message) GetRecipient() string {
 	if o == nil {
@@ -286,6 +356,38 @@ func (o *Message) SetTemplateType(v string) {
 	o.TemplateType = v
 }
 
+// GetThrottledUntil returns the ThrottledUntil field value if set, zero value otherwise.
+func (o *Message) GetThrottledUntil() time.Time {
+	if o == nil || o.ThrottledUntil == nil {
+		var ret time.Time
+		return ret
+	}
+	return *o.ThrottledUntil
+}
+
+// GetThrottledUntilOk returns a tuple with the ThrottledUntil field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *Message) GetThrottledUntilOk() (*time.Time, bool) {
+	if o == nil || o.ThrottledUntil == nil {
+		return nil, false
+	}
+	return o.ThrottledUntil, true
+}
+
+// HasThrottledUntil returns a boolean if a field has been set.
+func (o *Message) HasThrottledUntil() bool {
+	if o != nil && o.ThrottledUntil != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetThrottledUntil gets a reference to the given time.Time and assigns it to the ThrottledUntil field.
+func (o *Message) SetThrottledUntil(v time.Time) {
+	o.ThrottledUntil = &v
+}
+
 // GetType returns the Type field value
 func (o *Message) GetType() CourierMessageType {
 	if o == nil {
@@ -339,6 +441,9 @@ func (o Message) MarshalJSON() ([]byte, error) {
 	if true {
 		toSerialize["body"] = o.Body
 	}
+	if o.Channel != nil {
+		toSerialize["channel"] = o.Channel
+	}
 	if true {
 		toSerialize["created_at"] = o.CreatedAt
 	}
@@ -348,6 +453,9 @@ func (o Message) MarshalJSON() ([]byte, error) {
 	if true {
 		toSerialize["id"] = o.Id
 	}
+	if o.Locale != nil {
+		toSerialize["locale"] = o.Locale
+	}
 	if true {
 		toSerialize["recipient"] = o.Recipient
 	}
@@ -363,6 +471,9 @@ func (o Message) MarshalJSON() ([]byte, error) {
 	if true {
 		toSerialize["template_type"] = o.TemplateType
 	}
+	if o.ThrottledUntil != nil {
+		toSerialize["throttled_until"] = o.ThrottledUntil
+	}
 	if true {
 		toSerialize["type"] = o.Type
 	}
@@ -25,7 +25,7 @@ type MessageDispatch struct {
 	Id string `json:"id"`
 	// The ID of the message being dispatched
 	MessageId string `json:"message_id"`
-	// The status of this dispatch Either \"failed\" or \"success\" failed CourierMessageDispatchStatusFailed success CourierMessageDispatchStatusSuccess
+	// The status of this dispatch Either \"failed\", \"success\", or \"throttled\" failed CourierMessageDispatchStatusFailed success CourierMessageDispatchStatusSuccess throttled CourierMessageDispatchStatusThrottled
 	Status string `json:"status"`
 	// UpdatedAt is a helper struct field for gobuffalo.pop.
 	UpdatedAt time.Time `json:"updated_at"`
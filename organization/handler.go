@@ -0,0 +1,407 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package organization
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/jsonx"
+	"github.com/ory/x/pagination/migrationpagination"
+	"github.com/ory/x/urlx"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x"
+)
+
+const (
+	RouteCollection = "/organizations"
+	RouteItem       = RouteCollection + "/:id"
+)
+
+type (
+	handlerDependencies interface {
+		PersistenceProvider
+		x.WriterProvider
+		x.CSRFProvider
+		config.Provider
+	}
+	HandlerProvider interface {
+		OrganizationHandler() *Handler
+	}
+	Handler struct {
+		r handlerDependencies
+	}
+)
+
+func NewHandler(r handlerDependencies) *Handler {
+	return &Handler{r: r}
+}
+
+func (h *Handler) RegisterPublicRoutes(public *x.RouterPublic) {
+	h.r.CSRFHandler().IgnoreGlobs(
+		RouteCollection, RouteCollection+"/*",
+		x.AdminPrefix+RouteCollection, x.AdminPrefix+RouteCollection+"/*",
+	)
+
+	public.GET(RouteCollection, x.RedirectToAdminRoute(h.r))
+	public.GET(RouteItem, x.RedirectToAdminRoute(h.r))
+	public.POST(RouteCollection, x.RedirectToAdminRoute(h.r))
+	public.PUT(RouteItem, x.RedirectToAdminRoute(h.r))
+	public.DELETE(RouteItem, x.RedirectToAdminRoute(h.r))
+
+	public.GET(x.AdminPrefix+RouteCollection, x.RedirectToAdminRoute(h.r))
+	public.GET(x.AdminPrefix+RouteItem, x.RedirectToAdminRoute(h.r))
+	public.POST(x.AdminPrefix+RouteCollection, x.RedirectToAdminRoute(h.r))
+	public.PUT(x.AdminPrefix+RouteItem, x.RedirectToAdminRoute(h.r))
+	public.DELETE(x.AdminPrefix+RouteItem, x.RedirectToAdminRoute(h.r))
+}
+
+func (h *Handler) RegisterAdminRoutes(admin *x.RouterAdmin) {
+	admin.GET(RouteCollection, h.list)
+	admin.GET(RouteItem, h.get)
+	admin.POST(RouteCollection, h.create)
+	admin.PUT(RouteItem, h.update)
+	admin.DELETE(RouteItem, h.delete)
+}
+
+// Paginated Organization List Response
+//
+// swagger:response listOrganizations
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listOrganizationsResponse struct {
+	migrationpagination.ResponseHeaderAnnotation
+
+	// List of organizations
+	//
+	// in:body
+	Body []Organization
+}
+
+// Paginated List Organization Parameters
+//
+// swagger:parameters listOrganizations
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listOrganizationsParameters struct {
+	migrationpagination.RequestParameters
+}
+
+// swagger:route GET /admin/organizations identity listOrganizations
+//
+// # List Organizations
+//
+// Lists all organizations in the system.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: listOrganizations
+//	  default: errorGeneric
+func (h *Handler) list(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	page, itemsPerPage := x.ParsePagination(r)
+
+	os, err := h.r.OrganizationPersister().ListOrganizations(r.Context(), page, itemsPerPage)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	total, err := h.r.OrganizationPersister().CountOrganizations(r.Context())
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	migrationpagination.PaginationHeader(w, urlx.AppendPaths(h.r.Config().SelfAdminURL(r.Context()), RouteCollection), total, page, itemsPerPage)
+	h.r.Writer().Write(w, r, os)
+}
+
+// Get Organization Parameters
+//
+// swagger:parameters getOrganization
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type getOrganization struct {
+	// ID must be set to the ID of organization you want to get
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route GET /admin/organizations/{id} identity getOrganization
+//
+// # Get an Organization
+//
+// Return an organization by its ID.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: organization
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) get(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	o, err := h.r.OrganizationPersister().GetOrganization(r.Context(), x.ParseUUID(ps.ByName("id")))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, o)
+}
+
+// Create Organization Parameters
+//
+// swagger:parameters createOrganization
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type createOrganization struct {
+	// in: body
+	Body CreateOrganizationBody
+}
+
+// Create Organization Body
+//
+// swagger:model createOrganizationBody
+type CreateOrganizationBody struct {
+	// Name is the organization's human-readable name.
+	//
+	// required: true
+	Name string `json:"name"`
+
+	// Domains are the email domains of the organization's members, for example `example.org`.
+	Domains []string `json:"domains"`
+
+	// OIDCProvider is the ID of the OpenID Connect provider the organization's members must use to
+	// sign up and sign in. It must match the ID of a provider configured for the `oidc` method.
+	OIDCProvider string `json:"oidc_provider"`
+}
+
+// swagger:route POST /admin/organizations identity createOrganization
+//
+// # Create an Organization
+//
+// Create an organization. Identities whose email address belongs to one of the organization's domains
+// become members of the organization when they sign up.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  201: organization
+//	  400: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) create(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var cr CreateOrganizationBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&cr); err != nil {
+		h.r.Writer().WriteErrorCode(w, r, http.StatusBadRequest, errors.WithStack(err))
+		return
+	}
+
+	o := &Organization{Name: cr.Name, Domains: cr.Domains, OIDCProvider: cr.OIDCProvider}
+	if err := normalize(o); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if err := h.r.OrganizationPersister().CreateOrganization(r.Context(), o); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().WriteCreated(w, r,
+		urlx.AppendPaths(
+			h.r.Config().SelfAdminURL(r.Context()),
+			"organizations",
+			o.ID.String(),
+		).String(),
+		o,
+	)
+}
+
+// Update Organization Parameters
+//
+// swagger:parameters updateOrganization
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type updateOrganization struct {
+	// ID must be set to the ID of organization you want to update
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+
+	// in: body
+	Body UpdateOrganizationBody
+}
+
+// Update Organization Body
+//
+// swagger:model updateOrganizationBody
+type UpdateOrganizationBody struct {
+	// Name is the organization's human-readable name.
+	//
+	// required: true
+	Name string `json:"name"`
+
+	// Domains are the email domains of the organization's members. Replaces all existing domains.
+	Domains []string `json:"domains"`
+
+	// OIDCProvider is the ID of the OpenID Connect provider the organization's members must use to
+	// sign up and sign in. If empty, members may use any enabled method.
+	OIDCProvider string `json:"oidc_provider"`
+}
+
+// swagger:route PUT /admin/organizations/{id} identity updateOrganization
+//
+// # Update an Organization
+//
+// This endpoint updates an organization. The full organization payload is expected.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: organization
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) update(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var ur UpdateOrganizationBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&ur); err != nil {
+		h.r.Writer().WriteErrorCode(w, r, http.StatusBadRequest, errors.WithStack(err))
+		return
+	}
+
+	o, err := h.r.OrganizationPersister().GetOrganization(r.Context(), x.ParseUUID(ps.ByName("id")))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	o.Name = ur.Name
+	o.Domains = ur.Domains
+	o.OIDCProvider = ur.OIDCProvider
+	if err := normalize(o); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if err := h.r.OrganizationPersister().UpdateOrganization(r.Context(), o); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, o)
+}
+
+// Delete Organization Parameters
+//
+// swagger:parameters deleteOrganization
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type deleteOrganization struct {
+	// ID is the organization's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route DELETE /admin/organizations/{id} identity deleteOrganization
+//
+// # Delete an Organization
+//
+// Deletes an organization. Its members are not deleted but no longer belong to any organization.
+// This action can not be undone.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  204: emptyResponse
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	if err := h.r.OrganizationPersister().DeleteOrganization(r.Context(), x.ParseUUID(ps.ByName("id"))); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func normalize(o *Organization) error {
+	o.Name = strings.TrimSpace(o.Name)
+	if o.Name == "" {
+		return errors.WithStack(herodot.ErrBadRequest.WithReason("The organization name must not be empty."))
+	}
+
+	o.OIDCProvider = strings.TrimSpace(o.OIDCProvider)
+
+	domains := make([]string, 0, len(o.Domains))
+	seen := map[string]bool{}
+	for _, d := range o.Domains {
+		domain := NormalizeDomain(d)
+		if domain == "" || strings.ContainsAny(domain, "@/ ") || !strings.Contains(domain, ".") {
+			return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The organization domain %q is not a valid domain.", d))
+		}
+		if seen[domain] {
+			continue
+		}
+		seen[domain] = true
+		domains = append(domains, domain)
+	}
+	o.Domains = domains
+
+	return nil
+}
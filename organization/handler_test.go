@@ -0,0 +1,218 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package organization_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/x/sqlcon"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/organization"
+)
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	publicTS, adminTS := testhelpers.NewKratosServerWithCSRF(t, reg)
+	conf.MustSet(ctx, config.ViperKeyAdminBaseURL, adminTS.URL)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/identity.schema.json")
+
+	send := func(t *testing.T, base *httptest.Server, method, href string, expectCode int, send interface{}) gjson.Result {
+		t.Helper()
+		var b bytes.Buffer
+		if send != nil {
+			require.NoError(t, json.NewEncoder(&b).Encode(send))
+		}
+		req, err := http.NewRequest(method, base.URL+href, &b)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		res, err := base.Client().Do(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+
+		require.EqualValues(t, expectCode, res.StatusCode, "%s", body)
+		return gjson.ParseBytes(body)
+	}
+
+	create := func(t *testing.T, body organization.CreateOrganizationBody) gjson.Result {
+		t.Helper()
+		return send(t, adminTS, "POST", "/admin/organizations", http.StatusCreated, &body)
+	}
+
+	t.Run("case=should return an empty list", func(t *testing.T) {
+		for name, ts := range map[string]*httptest.Server{"public": publicTS, "admin": adminTS} {
+			t.Run("endpoint="+name, func(t *testing.T) {
+				res := send(t, ts, "GET", "/organizations", http.StatusOK, nil)
+				require.True(t, res.IsArray(), "%s", res.Raw)
+				assert.Len(t, res.Array(), 0)
+			})
+		}
+	})
+
+	t.Run("case=should return 404 on a non-existing resource", func(t *testing.T) {
+		for name, ts := range map[string]*httptest.Server{"public": publicTS, "admin": adminTS} {
+			t.Run("endpoint="+name, func(t *testing.T) {
+				_ = send(t, ts, "GET", "/organizations/"+uuid.Must(uuid.NewV4()).String(), http.StatusNotFound, nil)
+				_ = send(t, ts, "DELETE", "/organizations/"+uuid.Must(uuid.NewV4()).String(), http.StatusNotFound, nil)
+			})
+		}
+	})
+
+	t.Run("case=should reject invalid organizations", func(t *testing.T) {
+		for _, tc := range []organization.CreateOrganizationBody{
+			{Name: " "},
+			{Name: "Acme", Domains: []string{"user@acme.example"}},
+			{Name: "Acme", Domains: []string{"localhost"}},
+			{Name: "Acme", Domains: []string{""}},
+		} {
+			res := send(t, adminTS, "POST", "/admin/organizations", http.StatusBadRequest, &tc)
+			assert.NotEmpty(t, res.Get("error.reason").String(), "%s", res.Raw)
+		}
+	})
+
+	t.Run("case=should create, get, list, and update an organization", func(t *testing.T) {
+		created := create(t, organization.CreateOrganizationBody{
+			Name:         "Acme",
+			Domains:      []string{"Acme.example", "acme.example", "sub.acme.example."},
+			OIDCProvider: "acme-sso",
+		})
+		id := created.Get("id").String()
+		assert.Equal(t, "Acme", created.Get("name").String())
+		assert.Equal(t, []interface{}{"acme.example", "sub.acme.example"}, created.Get("domains").Value())
+		assert.Equal(t, "acme-sso", created.Get("oidc_provider").String())
+
+		for name, ts := range map[string]*httptest.Server{"public": publicTS, "admin": adminTS} {
+			t.Run("endpoint="+name, func(t *testing.T) {
+				res := send(t, ts, "GET", "/organizations/"+id, http.StatusOK, nil)
+				assert.JSONEq(t, created.Raw, res.Raw)
+
+				list := send(t, ts, "GET", "/organizations", http.StatusOK, nil)
+				assert.Contains(t, list.Get("#.id").Value(), id)
+			})
+		}
+
+		updated := send(t, adminTS, "PUT", "/admin/organizations/"+id, http.StatusOK, &organization.UpdateOrganizationBody{
+			Name:    "Acme Inc.",
+			Domains: []string{"acme.example", "acme-inc.example"},
+		})
+		assert.Equal(t, "Acme Inc.", updated.Get("name").String())
+		assert.Equal(t, []interface{}{"acme.example", "acme-inc.example"}, updated.Get("domains").Value())
+		assert.False(t, updated.Get("oidc_provider").Exists(), "%s", updated.Raw)
+
+		res := send(t, adminTS, "GET", "/admin/organizations/"+id, http.StatusOK, nil)
+		assert.Equal(t, []interface{}{"acme-inc.example", "acme.example"}, res.Get("domains").Value())
+
+		o, err := reg.OrganizationPersister().FindOrganizationByDomain(ctx, "ACME-INC.example")
+		require.NoError(t, err)
+		assert.Equal(t, id, o.ID.String())
+
+		_, err = reg.OrganizationPersister().FindOrganizationByDomain(ctx, "sub.acme.example")
+		require.ErrorIs(t, err, sqlcon.ErrNoRows)
+	})
+
+	t.Run("case=should not allow a domain to belong to two organizations", func(t *testing.T) {
+		create(t, organization.CreateOrganizationBody{Name: "Foo", Domains: []string{"foo.example"}})
+		other := create(t, organization.CreateOrganizationBody{Name: "Bar", Domains: []string{"bar.example"}})
+
+		_ = send(t, adminTS, "POST", "/admin/organizations", http.StatusConflict, &organization.CreateOrganizationBody{Name: "Foo 2", Domains: []string{"FOO.example"}})
+		_ = send(t, adminTS, "PUT", "/admin/organizations/"+other.Get("id").String(), http.StatusConflict, &organization.UpdateOrganizationBody{Name: "Bar", Domains: []string{"foo.example"}})
+
+		res := send(t, adminTS, "GET", "/admin/organizations/"+other.Get("id").String(), http.StatusOK, nil)
+		assert.Equal(t, []interface{}{"bar.example"}, res.Get("domains").Value(), "a failed update must not change the domains")
+	})
+
+	t.Run("case=should assign identities to an organization", func(t *testing.T) {
+		org := create(t, organization.CreateOrganizationBody{Name: "Members", Domains: []string{"members.example"}})
+		orgID := org.Get("id").String()
+
+		res := send(t, adminTS, "POST", "/admin/identities", http.StatusCreated, json.RawMessage(`{"traits":{"email":"member@members.example"},"organization_id":"`+orgID+`"}`))
+		identityID := res.Get("id").String()
+		assert.Equal(t, orgID, res.Get("organization_id").String(), "%s", res.Raw)
+
+		res = send(t, adminTS, "POST", "/admin/identities", http.StatusBadRequest, json.RawMessage(`{"traits":{"email":"member@members.example"},"organization_id":"`+uuid.Must(uuid.NewV4()).String()+`"}`))
+		assert.Contains(t, res.Get("error.reason").String(), "does not exist", "%s", res.Raw)
+
+		res = send(t, adminTS, "PUT", "/admin/identities/"+identityID, http.StatusBadRequest, json.RawMessage(`{"schema_id":"default","traits":{"email":"member@members.example"},"organization_id":"`+uuid.Must(uuid.NewV4()).String()+`"}`))
+		assert.Contains(t, res.Get("error.reason").String(), "does not exist", "%s", res.Raw)
+
+		_ = send(t, adminTS, "DELETE", "/admin/organizations/"+orgID, http.StatusNoContent, nil)
+		_ = send(t, adminTS, "GET", "/admin/organizations/"+orgID, http.StatusNotFound, nil)
+
+		res = send(t, adminTS, "GET", "/admin/identities/"+identityID, http.StatusOK, nil)
+		assert.False(t, res.Get("organization_id").Exists(), "deleting the organization must remove its members: %s", res.Raw)
+
+		_, err := reg.OrganizationPersister().FindOrganizationByDomain(ctx, "members.example")
+		require.ErrorIs(t, err, sqlcon.ErrNoRows)
+	})
+
+	t.Run("case=should find the organization of a member", func(t *testing.T) {
+		org := create(t, organization.CreateOrganizationBody{Name: "Lookup", Domains: []string{"lookup.example"}})
+		orgID := uuid.FromStringOrNil(org.Get("id").String())
+		other := create(t, organization.CreateOrganizationBody{Name: "Other", Domains: []string{"other.example"}})
+		otherID := uuid.FromStringOrNil(other.Get("id").String())
+
+		p := reg.OrganizationPersister()
+
+		o, err := organization.FindOrganizationForMember(ctx, p, nil, "not-an-email", "user@unknown.example", "User@Lookup.Example")
+		require.NoError(t, err)
+		assert.Equal(t, orgID, o.ID)
+
+		o, err = organization.FindOrganizationForMember(ctx, p, &otherID, "user@lookup.example")
+		require.NoError(t, err)
+		assert.Equal(t, otherID, o.ID, "an explicit organization takes precedence over the domain")
+
+		_, err = organization.FindOrganizationForMember(ctx, p, nil, "user@unknown.example")
+		require.ErrorIs(t, err, sqlcon.ErrNoRows)
+
+		sso := create(t, organization.CreateOrganizationBody{Name: "SSO", Domains: []string{"sso.example"}, OIDCProvider: "sso"})
+		ssoID := uuid.FromStringOrNil(sso.Get("id").String())
+
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.VerifiableAddresses = []identity.VerifiableAddress{{Value: "user@other.example", Verified: true}}
+		o, err = organization.FindOrganizationForIdentity(ctx, p, i.OrganizationID, i.VerifiedAddresses(), i.UnverifiedAddresses())
+		require.NoError(t, err)
+		assert.Equal(t, otherID, o.ID)
+
+		i.VerifiableAddresses = []identity.VerifiableAddress{{Value: "user@other.example"}}
+		i.RecoveryAddresses = []identity.RecoveryAddress{{Value: "user@lookup.example"}}
+		_, err = organization.FindOrganizationForIdentity(ctx, p, i.OrganizationID, i.VerifiedAddresses(), i.UnverifiedAddresses())
+		require.ErrorIs(t, err, sqlcon.ErrNoRows, "unverified addresses must not grant membership")
+
+		i.RecoveryAddresses = []identity.RecoveryAddress{{Value: "user@sso.example"}}
+		o, err = organization.FindOrganizationForIdentity(ctx, p, i.OrganizationID, i.VerifiedAddresses(), i.UnverifiedAddresses())
+		require.NoError(t, err)
+		assert.Equal(t, ssoID, o.ID, "unverified addresses must not evade single sign-on")
+	})
+}
+
+func TestDomainOf(t *testing.T) {
+	for in, expected := range map[string]string{
+		"user@example.org":   "example.org",
+		"User@Example.ORG.":  "example.org",
+		"a@b@sub.example.co": "sub.example.co",
+		"example.org":        "",
+		"@example.org":       "",
+		"user@":              "",
+		"":                   "",
+	} {
+		assert.Equal(t, expected, organization.DomainOf(in), "%s", in)
+	}
+}
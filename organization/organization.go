@@ -0,0 +1,174 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package organization
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/sqlcon"
+)
+
+// Organization groups the identities of a customer tenant
+//
+// Identities whose email address belongs to one of the organization's domains are members of the
+// organization. If the organization has an OpenID Connect provider configured, its members must
+// sign up and sign in using that provider.
+//
+// swagger:model organization
+type Organization struct {
+	// ID is the organization's unique identifier.
+	//
+	// required: true
+	ID uuid.UUID `json:"id" faker:"-" db:"id"`
+
+	// Name is the organization's human-readable name.
+	//
+	// required: true
+	Name string `json:"name" db:"name"`
+
+	// Domains are the email domains of the organization's members, for example `example.org`.
+	//
+	// A domain can only belong to a single organization.
+	//
+	// required: true
+	Domains []string `json:"domains" faker:"-" db:"-"`
+
+	// OIDCProvider is the ID of the OpenID Connect provider the organization's members must use to
+	// sign up and sign in. If empty, members may use any enabled method.
+	OIDCProvider string `json:"oidc_provider,omitempty" db:"oidc_provider"`
+
+	// CreatedAt is a helper struct field for gobuffalo.pop.
+	CreatedAt time.Time `json:"created_at" faker:"-" db:"created_at"`
+
+	// UpdatedAt is a helper struct field for gobuffalo.pop.
+	UpdatedAt time.Time `json:"updated_at" faker:"-" db:"updated_at"`
+	NID       uuid.UUID `json:"-"  faker:"-" db:"nid"`
+}
+
+func (Organization) TableName(context.Context) string {
+	return "organizations"
+}
+
+// RequiresSSO returns true if the organization's members must use its OpenID Connect provider.
+func (o *Organization) RequiresSSO() bool {
+	return o.OIDCProvider != ""
+}
+
+// Domain maps an email domain to the organization it belongs to.
+type Domain struct {
+	ID             uuid.UUID `db:"id"`
+	NID            uuid.UUID `db:"nid"`
+	OrganizationID uuid.UUID `db:"organization_id"`
+	Domain         string    `db:"domain"`
+
+	// CreatedAt is a helper struct field for gobuffalo.pop.
+	CreatedAt time.Time `db:"created_at"`
+
+	// UpdatedAt is a helper struct field for gobuffalo.pop.
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (Domain) TableName(context.Context) string {
+	return "organization_domains"
+}
+
+// NormalizeDomain returns the domain in the form it is stored and looked up in.
+func NormalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+// DomainOf returns the normalized domain of an email address or an empty string if the value is not
+// an email address.
+func DomainOf(address string) string {
+	at := strings.LastIndex(address, "@")
+	if at < 1 || at == len(address)-1 {
+		return ""
+	}
+	return NormalizeDomain(address[at+1:])
+}
+
+type (
+	Persister interface {
+		CreateOrganization(ctx context.Context, o *Organization) error
+		GetOrganization(ctx context.Context, id uuid.UUID) (*Organization, error)
+		ListOrganizations(ctx context.Context, page, itemsPerPage int) ([]Organization, error)
+		CountOrganizations(ctx context.Context) (int64, error)
+		UpdateOrganization(ctx context.Context, o *Organization) error
+
+		// DeleteOrganization deletes the organization and removes its identities and sessions from it.
+		DeleteOrganization(ctx context.Context, id uuid.UUID) error
+
+		// FindOrganizationByDomain returns the organization the email domain belongs to or sqlcon.ErrNoRows.
+		FindOrganizationByDomain(ctx context.Context, domain string) (*Organization, error)
+	}
+
+	PersistenceProvider interface {
+		OrganizationPersister() Persister
+	}
+)
+
+// FindOrganizationForMember returns the organization of a member. If organizationID is set, that
+// organization is returned. Otherwise, the first address belonging to an organization's domain
+// determines the organization. Returns sqlcon.ErrNoRows if the member does not belong to any organization.
+func FindOrganizationForMember(ctx context.Context, p Persister, organizationID *uuid.UUID, addresses ...string) (*Organization, error) {
+	if organizationID != nil {
+		return p.GetOrganization(ctx, *organizationID)
+	}
+
+	seen := map[string]bool{}
+	for _, address := range addresses {
+		domain := DomainOf(address)
+		if domain == "" || seen[domain] {
+			continue
+		}
+		seen[domain] = true
+
+		o, err := p.FindOrganizationByDomain(ctx, domain)
+		if errors.Is(err, sqlcon.ErrNoRows) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		return o, nil
+	}
+
+	return nil, errors.WithStack(sqlcon.ErrNoRows)
+}
+
+// FindOrganizationForIdentity returns the organization an identity belongs to or must sign in through. The
+// identity's organization and its verified addresses determine its membership. Unverified addresses only match
+// organizations which require single sign-on: they do not make the identity a member, but the identity must use the
+// organization's OpenID Connect provider, which then proves the membership. Returns sqlcon.ErrNoRows if no
+// organization applies.
+func FindOrganizationForIdentity(ctx context.Context, p Persister, organizationID *uuid.UUID, verified, unverified []string) (*Organization, error) {
+	o, err := FindOrganizationForMember(ctx, p, organizationID, verified...)
+	if !errors.Is(err, sqlcon.ErrNoRows) {
+		return o, err
+	}
+
+	seen := map[string]bool{}
+	for _, address := range unverified {
+		domain := DomainOf(address)
+		if domain == "" || seen[domain] {
+			continue
+		}
+		seen[domain] = true
+
+		o, err := p.FindOrganizationByDomain(ctx, domain)
+		if errors.Is(err, sqlcon.ErrNoRows) {
+			continue
+		} else if err != nil {
+			return nil, err
+		} else if o.RequiresSSO() {
+			return o, nil
+		}
+	}
+
+	return nil, errors.WithStack(sqlcon.ErrNoRows)
+}
@@ -0,0 +1,179 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/jsonnetsecure"
+	"github.com/ory/x/otelx"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/request"
+	"github.com/ory/kratos/x"
+)
+
+const (
+	// maxRetryDelay caps the exponential delay between two delivery attempts of an event.
+	maxRetryDelay = time.Hour
+
+	// processingLease is the time a dispatcher has to deliver the events it claimed. Afterwards,
+	// the events are claimed again, for example because the dispatcher crashed.
+	processingLease = time.Minute * 5
+)
+
+type (
+	Dependencies interface {
+		PersistenceProvider
+		x.TracingProvider
+		x.LoggingProvider
+		x.HTTPClientProvider
+		jsonnetsecure.VMProvider
+		config.Provider
+	}
+
+	// Dispatcher delivers the events in the outbox to the configured sinks.
+	Dispatcher struct {
+		deps    Dependencies
+		backoff backoff.BackOff
+	}
+
+	DispatcherProvider interface {
+		OutboxDispatcher() *Dispatcher
+	}
+)
+
+func NewDispatcher(deps Dependencies) *Dispatcher {
+	return &Dispatcher{
+		deps:    deps,
+		backoff: backoff.NewExponentialBackOff(),
+	}
+}
+
+func (d *Dispatcher) UseBackoff(b backoff.BackOff) {
+	d.backoff = b
+}
+
+func (d *Dispatcher) Work(ctx context.Context) error {
+	errChan := make(chan error)
+	defer close(errChan)
+
+	go d.watchEvents(ctx, errChan)
+
+	select {
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil
+		}
+		return ctx.Err()
+	case err := <-errChan:
+		return err
+	}
+}
+
+func (d *Dispatcher) watchEvents(ctx context.Context, errChan chan error) {
+	d.backoff.Reset()
+	for {
+		if err := backoff.Retry(func() error {
+			return d.DispatchQueue(ctx)
+		}, d.backoff); err != nil {
+			errChan <- err
+			return
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// DispatchQueue delivers all events which are due. Failed deliveries are retried with an
+// exponential delay until the configured number of retries is exceeded.
+func (d *Dispatcher) DispatchQueue(ctx context.Context) error {
+	maxRetries := d.deps.Config().OutboxEventRetries(ctx)
+
+	for {
+		es, err := d.deps.OutboxPersister().NextEvents(ctx, 10, processingLease)
+		if err != nil {
+			if errors.Is(err, ErrQueueEmpty) {
+				return nil
+			}
+			return err
+		}
+
+		for _, e := range es {
+			logger := d.deps.Logger().
+				WithField("event_id", e.ID).
+				WithField("event_nid", e.NID).
+				WithField("event_type", e.Type).
+				WithField("identity_id", e.IdentityID)
+
+			if err := d.DispatchEvent(ctx, e); err != nil {
+				attempts := e.Attempts + 1
+				abandon := attempts > maxRetries
+				if err := d.deps.OutboxPersister().SetEventFailed(ctx, e.ID, err, time.Now().UTC().Add(retryDelay(attempts)), abandon); err != nil {
+					logger.WithError(err).Error(`Unable to record the failed delivery of the outbox event.`)
+					return err
+				}
+
+				if abandon {
+					logger.WithError(err).Warnf(`Outbox event was abandoned because it could not be delivered after %d attempts.`, attempts)
+				} else {
+					logger.WithError(err).Warn(`Unable to deliver outbox event, it will be retried.`)
+				}
+				continue
+			}
+
+			if err := d.deps.OutboxPersister().SetEventDelivered(ctx, e.ID); err != nil {
+				logger.WithError(err).Error(`Unable to set the outbox event's status to "delivered".`)
+				return err
+			}
+			logger.Debug("Outbox event was delivered.")
+		}
+	}
+}
+
+// DispatchEvent delivers the event to all configured sinks. The event is considered delivered
+// once every sink accepted it. Sinks may receive an event more than once if another sink
+// failed, so they should deduplicate events using the event ID.
+func (d *Dispatcher) DispatchEvent(ctx context.Context, e Event) (err error) {
+	ctx, span := d.deps.Tracer(ctx).Tracer().Start(ctx, "outbox.Dispatcher.DispatchEvent")
+	defer otelx.End(span, &err)
+
+	for _, sink := range d.deps.Config().OutboxSinks(ctx) {
+		builder, err := request.NewBuilder(sink, d.deps)
+		if err != nil {
+			return err
+		}
+
+		req, err := builder.BuildRequest(ctx, e)
+		if err != nil {
+			return err
+		}
+
+		res, err := d.deps.HTTPClient(ctx).Do(req.WithContext(ctx))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		_ = res.Body.Close()
+
+		if res.StatusCode < 200 || res.StatusCode > 299 {
+			return errors.Errorf("sink %s responded with status code %d", builder.Config.URL, res.StatusCode)
+		}
+	}
+
+	return nil
+}
+
+func retryDelay(attempts int) time.Duration {
+	if attempts > 12 {
+		return maxRetryDelay
+	}
+	delay := time.Second * time.Duration(1<<attempts)
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
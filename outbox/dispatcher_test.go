@@ -0,0 +1,202 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/outbox"
+)
+
+type sink struct {
+	sync.Mutex
+	status int
+	bodies []gjson.Result
+}
+
+func newSink(t *testing.T) (*sink, *httptest.Server) {
+	s := &sink{status: http.StatusOK}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		s.Lock()
+		defer s.Unlock()
+		s.bodies = append(s.bodies, gjson.ParseBytes(body))
+		w.WriteHeader(s.status)
+	}))
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func (s *sink) setStatus(status int) {
+	s.Lock()
+	defer s.Unlock()
+	s.status = status
+}
+
+func (s *sink) types() (types []string) {
+	s.Lock()
+	defer s.Unlock()
+	for _, b := range s.bodies {
+		types = append(types, b.Get("type").String())
+	}
+	return types
+}
+
+func newRegistry(t *testing.T, ctx context.Context, sinkURL string) *driver.RegistryDefault {
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	conf.MustSet(ctx, config.ViperKeyOutboxEnabled, true)
+	conf.MustSet(ctx, config.ViperKeyOutboxEventRetries, 1)
+	conf.MustSet(ctx, config.ViperKeyOutboxSinks, []map[string]interface{}{{"url": sinkURL}})
+	return reg
+}
+
+func TestDispatchQueue(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("case=delivers the events of an identity in order", func(t *testing.T) {
+		s, ts := newSink(t)
+		reg := newRegistry(t, ctx, ts.URL)
+
+		id := uuid.Must(uuid.NewV4())
+		require.NoError(t, reg.OutboxPersister().AddEvents(ctx,
+			outbox.NewIdentityCreated(id),
+			outbox.NewIdentityUpdated(id),
+			outbox.NewIdentityDeleted(id),
+		))
+
+		require.NoError(t, reg.OutboxDispatcher().DispatchQueue(ctx))
+		assert.Equal(t, []string{"IdentityCreated", "IdentityUpdated", "IdentityDeleted"}, s.types())
+
+		s.Lock()
+		assert.Equal(t, id.String(), s.bodies[0].Get("identity_id").String())
+		assert.Equal(t, id.String(), s.bodies[0].Get("payload.identity_id").String())
+		s.Unlock()
+
+		es, total, _, err := reg.OutboxPersister().ListEvents(ctx, outbox.ListEventsParameters{IdentityID: &id}, nil)
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, total)
+		for _, e := range es {
+			assert.Equal(t, outbox.StatusDelivered, e.Status)
+			assert.Equal(t, 1, e.Attempts)
+			assert.False(t, time.Time(e.DeliveredAt).IsZero())
+		}
+	})
+
+	t.Run("case=retries and abandons undeliverable events", func(t *testing.T) {
+		s, ts := newSink(t)
+		s.setStatus(http.StatusBadRequest)
+		reg := newRegistry(t, ctx, ts.URL)
+
+		id := uuid.Must(uuid.NewV4())
+		created, updated := outbox.NewIdentityCreated(id), outbox.NewIdentityUpdated(id)
+		require.NoError(t, reg.OutboxPersister().AddEvents(ctx, created, updated))
+
+		require.NoError(t, reg.OutboxDispatcher().DispatchQueue(ctx))
+		assert.Equal(t, []string{"IdentityCreated"}, s.types(), "the second event must wait for the first one")
+
+		e, err := reg.OutboxPersister().GetEvent(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, outbox.StatusPending, e.Status)
+		assert.Equal(t, 1, e.Attempts)
+		assert.Contains(t, e.LastError, "400")
+
+		// The retry is not due yet.
+		_, err = reg.OutboxPersister().NextEvents(ctx, 10, time.Minute)
+		require.ErrorIs(t, err, outbox.ErrQueueEmpty)
+
+		// Make the retry due, the next failure exceeds the configured retries.
+		require.NoError(t, reg.OutboxPersister().SetEventFailed(ctx, created.ID, errors.New("failed"), time.Now().Add(-time.Minute), false))
+
+		require.NoError(t, reg.OutboxDispatcher().DispatchQueue(ctx))
+		e, err = reg.OutboxPersister().GetEvent(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, outbox.StatusAbandoned, e.Status)
+
+		t.Run("case=abandoned events do not block later events", func(t *testing.T) {
+			assert.Equal(t, []string{"IdentityCreated", "IdentityCreated", "IdentityUpdated"}, s.types())
+
+			e, err := reg.OutboxPersister().GetEvent(ctx, updated.ID)
+			require.NoError(t, err)
+			assert.Equal(t, outbox.StatusPending, e.Status)
+			assert.Equal(t, 1, e.Attempts)
+		})
+
+		t.Run("case=replays an abandoned event", func(t *testing.T) {
+			s.setStatus(http.StatusOK)
+			e, err := reg.OutboxPersister().ReplayEvent(ctx, created.ID)
+			require.NoError(t, err)
+			assert.Equal(t, outbox.StatusPending, e.Status)
+			assert.Equal(t, 0, e.Attempts)
+
+			require.NoError(t, reg.OutboxDispatcher().DispatchQueue(ctx))
+			e, err = reg.OutboxPersister().GetEvent(ctx, created.ID)
+			require.NoError(t, err)
+			assert.Equal(t, outbox.StatusDelivered, e.Status)
+		})
+	})
+
+	t.Run("case=assigns the sequence per identity", func(t *testing.T) {
+		_, ts := newSink(t)
+		reg := newRegistry(t, ctx, ts.URL)
+
+		a, b := uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+		a1, b1, a2 := outbox.NewIdentityCreated(a), outbox.NewIdentityCreated(b), outbox.NewIdentityUpdated(a)
+		require.NoError(t, reg.OutboxPersister().AddEvents(ctx, a1, b1, a2))
+		a3 := outbox.NewIdentityDeleted(a)
+		require.NoError(t, reg.OutboxPersister().AddEvents(ctx, a3))
+
+		assert.EqualValues(t, []int64{1, 2, 3}, []int64{a1.Sequence, a2.Sequence, a3.Sequence})
+		assert.EqualValues(t, 1, b1.Sequence)
+	})
+
+	t.Run("case=claims events again once their lease expired", func(t *testing.T) {
+		_, ts := newSink(t)
+		reg := newRegistry(t, ctx, ts.URL)
+
+		e := outbox.NewIdentityCreated(uuid.Must(uuid.NewV4()))
+		require.NoError(t, reg.OutboxPersister().AddEvents(ctx, e))
+
+		es, err := reg.OutboxPersister().NextEvents(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, es, 1)
+		assert.Equal(t, outbox.StatusProcessing, es[0].Status)
+
+		_, err = reg.OutboxPersister().NextEvents(ctx, 10, time.Minute)
+		require.ErrorIs(t, err, outbox.ErrQueueEmpty, "events must not be claimed twice while their lease is valid")
+
+		require.NoError(t, reg.Persister().GetConnection(ctx).RawQuery("UPDATE outbox_events SET next_attempt_at = ? WHERE id = ?", time.Now().UTC().Add(-time.Second), e.ID).Exec())
+		es, err = reg.OutboxPersister().NextEvents(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, es, 1)
+		assert.Equal(t, e.ID, es[0].ID)
+	})
+
+	t.Run("case=does not write events if the outbox is disabled", func(t *testing.T) {
+		_, ts := newSink(t)
+		reg := newRegistry(t, ctx, ts.URL)
+		reg.Config().MustSet(ctx, config.ViperKeyOutboxEnabled, false)
+
+		require.NoError(t, reg.OutboxPersister().AddEvents(ctx, outbox.NewIdentityCreated(uuid.Must(uuid.NewV4()))))
+		_, total, _, err := reg.OutboxPersister().ListEvents(ctx, outbox.ListEventsParameters{}, nil)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, total)
+	})
+}
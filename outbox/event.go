@@ -0,0 +1,245 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/pagination/keysetpagination"
+	"github.com/ory/x/sqlxx"
+
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x/events"
+)
+
+// An Event's Status
+//
+// swagger:model outboxEventStatus
+type Status string
+
+const (
+	// StatusPending events wait for being delivered.
+	StatusPending Status = "pending"
+	// StatusProcessing events are currently being delivered by a dispatcher.
+	StatusProcessing Status = "processing"
+	// StatusDelivered events were accepted by all sinks.
+	StatusDelivered Status = "delivered"
+	// StatusAbandoned events were not delivered after the configured number of retries.
+	StatusAbandoned Status = "abandoned"
+)
+
+func (s Status) IsValid() error {
+	switch s {
+	case StatusPending, StatusProcessing, StatusDelivered, StatusAbandoned:
+		return nil
+	default:
+		return errors.WithStack(herodot.ErrBadRequest.WithReasonf("Event status %q is not valid.", s))
+	}
+}
+
+// Event is an identity or session lifecycle event
+//
+// Events are written to the outbox in the same transaction as the change they describe
+// and are delivered to the configured sinks in order per identity.
+//
+// swagger:model outboxEvent
+type Event struct {
+	// ID is the event's unique identifier.
+	//
+	// required: true
+	ID uuid.UUID `json:"id" faker:"-" db:"id"`
+
+	// Type is the event's type, for example `IdentityCreated` or `SessionIssued`.
+	//
+	// required: true
+	Type string `json:"type" db:"type"`
+
+	// IdentityID is the ID of the identity the event belongs to.
+	//
+	// required: true
+	IdentityID uuid.UUID `json:"identity_id" faker:"-" db:"identity_id"`
+
+	// Payload contains the event's attributes.
+	//
+	// required: true
+	Payload sqlxx.JSONRawMessage `json:"payload" faker:"-" db:"payload"`
+
+	// Status is the event's delivery status.
+	//
+	// required: true
+	Status Status `json:"status" db:"status"`
+
+	// Attempts is the number of times delivering the event was attempted.
+	//
+	// required: true
+	Attempts int `json:"attempts" db:"attempts"`
+
+	// LastError contains the error of the last failed delivery attempt.
+	LastError string `json:"last_error,omitempty" db:"last_error"`
+
+	// NextAttemptAt is the earliest time the next delivery attempt is made. While the event is
+	// processing, it is the time the dispatcher's lease expires.
+	NextAttemptAt time.Time `json:"next_attempt_at" faker:"-" db:"next_attempt_at"`
+
+	// DeliveredAt is the time the event was delivered to all sinks.
+	DeliveredAt sqlxx.NullTime `json:"delivered_at,omitempty" faker:"-" db:"delivered_at"`
+
+	// Sequence orders the events of an identity. It is assigned when the event is written.
+	Sequence int64 `json:"-" faker:"-" db:"sequence"`
+
+	// CreatedAt is a helper struct field for gobuffalo.pop.
+	//
+	// required: true
+	CreatedAt time.Time `json:"created_at" faker:"-" db:"created_at"`
+
+	// UpdatedAt is a helper struct field for gobuffalo.pop.
+	UpdatedAt time.Time `json:"updated_at" faker:"-" db:"updated_at"`
+	NID       uuid.UUID `json:"-"  faker:"-" db:"nid"`
+}
+
+// The format we need to use in the Page tokens, as it's the only format that is understood by all DBs
+const dbFormat = "2006-01-02 15:04:05.99999"
+
+func (Event) TableName(context.Context) string {
+	return "outbox_events"
+}
+
+func (e Event) PageToken() keysetpagination.PageToken {
+	return keysetpagination.MapPageToken{
+		"id":         e.ID.String(),
+		"created_at": e.CreatedAt.Format(dbFormat),
+	}
+}
+
+func (e Event) DefaultPageToken() keysetpagination.PageToken {
+	return keysetpagination.MapPageToken{
+		"id":         uuid.Nil.String(),
+		"created_at": time.Date(2200, 12, 31, 23, 59, 59, 0, time.UTC).Format(dbFormat),
+	}
+}
+
+// NewEvent returns a pending event of the given type. The payload is encoded as JSON.
+func NewEvent(typ string, identityID uuid.UUID, payload interface{}) (*Event, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &Event{
+		ID:            uuid.Must(uuid.NewV4()),
+		Type:          typ,
+		IdentityID:    identityID,
+		Payload:       raw,
+		Status:        StatusPending,
+		NextAttemptAt: time.Now().UTC(),
+	}, nil
+}
+
+type identityPayload struct {
+	IdentityID uuid.UUID `json:"identity_id"`
+}
+
+type sessionPayload struct {
+	IdentityID                  uuid.UUID `json:"identity_id"`
+	SessionID                   uuid.UUID `json:"session_id"`
+	AuthenticatorAssuranceLevel string    `json:"aal,omitempty"`
+}
+
+type loginPayload struct {
+	IdentityID   uuid.UUID `json:"identity_id"`
+	SessionID    uuid.UUID `json:"session_id"`
+	FlowType     string    `json:"flow_type"`
+	RequestedAAL string    `json:"requested_aal"`
+	Method       string    `json:"method"`
+	SSOProvider  string    `json:"sso_provider,omitempty"`
+	IsRefresh    bool      `json:"is_refresh"`
+}
+
+func mustNewEvent(typ string, identityID uuid.UUID, payload interface{}) *Event {
+	e, err := NewEvent(typ, identityID, payload)
+	if err != nil {
+		// The payloads below consist of primitive types only and always encode.
+		panic(err)
+	}
+	return e
+}
+
+func NewIdentityCreated(identityID uuid.UUID) *Event {
+	return mustNewEvent(events.IdentityCreated.String(), identityID, &identityPayload{IdentityID: identityID})
+}
+
+func NewIdentityUpdated(identityID uuid.UUID) *Event {
+	return mustNewEvent(events.IdentityUpdated.String(), identityID, &identityPayload{IdentityID: identityID})
+}
+
+func NewIdentityDeleted(identityID uuid.UUID) *Event {
+	return mustNewEvent(events.IdentityDeleted.String(), identityID, &identityPayload{IdentityID: identityID})
+}
+
+func NewSessionIssued(aal string, sessionID, identityID uuid.UUID) *Event {
+	return mustNewEvent(events.SessionIssued.String(), identityID, &sessionPayload{
+		IdentityID:                  identityID,
+		SessionID:                   sessionID,
+		AuthenticatorAssuranceLevel: aal,
+	})
+}
+
+func NewSessionChanged(aal string, sessionID, identityID uuid.UUID) *Event {
+	return mustNewEvent(events.SessionChanged.String(), identityID, &sessionPayload{
+		IdentityID:                  identityID,
+		SessionID:                   sessionID,
+		AuthenticatorAssuranceLevel: aal,
+	})
+}
+
+func NewSessionRevoked(sessionID, identityID uuid.UUID) *Event {
+	return mustNewEvent(events.SessionRevoked.String(), identityID, &sessionPayload{
+		IdentityID: identityID,
+		SessionID:  sessionID,
+	})
+}
+
+func NewLoginSucceeded(o *events.LoginSucceededOpts) *Event {
+	return mustNewEvent(events.LoginSucceeded.String(), o.IdentityID, &loginPayload{
+		IdentityID:   o.IdentityID,
+		SessionID:    o.SessionID,
+		FlowType:     o.FlowType,
+		RequestedAAL: o.RequestedAAL,
+		Method:       o.Method,
+		SSOProvider:  o.SSOProvider,
+		IsRefresh:    o.IsRefresh,
+	})
+}
+
+type ctxKey int
+
+const sessionEventsKey ctxKey = iota
+
+// SessionEventFunc returns the outbox event for a session once the session was written and its
+// ID is known.
+type SessionEventFunc func(s *session.Session) *Event
+
+// ContextWithSessionEvents attaches event constructors to the context. The events are written to
+// the outbox in the same transaction as the next session write using this context.
+func ContextWithSessionEvents(ctx context.Context, fs ...SessionEventFunc) context.Context {
+	existing, _ := ctx.Value(sessionEventsKey).([]SessionEventFunc)
+	return context.WithValue(ctx, sessionEventsKey, append(existing[:len(existing):len(existing)], fs...))
+}
+
+// SessionEventsFromContext returns the events for the session attached to the context using
+// ContextWithSessionEvents.
+func SessionEventsFromContext(ctx context.Context, s *session.Session) []*Event {
+	fs, _ := ctx.Value(sessionEventsKey).([]SessionEventFunc)
+	es := make([]*Event, len(fs))
+	for k, f := range fs {
+		es[k] = f(s)
+	}
+	return es
+}
@@ -0,0 +1,100 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package outbox_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/outbox"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x/events"
+)
+
+func TestSessionEvents(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	conf.MustSet(ctx, config.ViperKeyOutboxEnabled, true)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/identity.schema.json")
+
+	i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+	require.NoError(t, reg.IdentityManager().Create(ctx, i))
+
+	req, err := http.NewRequest("GET", "/", nil)
+	require.NoError(t, err)
+	s, err := session.NewActiveSession(req, i, conf, time.Now(), identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+	require.NoError(t, err)
+
+	sctx := outbox.ContextWithSessionEvents(ctx, func(s *session.Session) *outbox.Event {
+		return outbox.NewLoginSucceeded(&events.LoginSucceededOpts{SessionID: s.ID, IdentityID: s.IdentityID, Method: "password"})
+	})
+	require.NoError(t, reg.SessionPersister().UpsertSession(sctx, s))
+	require.NoError(t, reg.SessionPersister().RevokeSessionById(ctx, s.ID))
+
+	es, _, _, err := reg.OutboxPersister().ListEvents(ctx, outbox.ListEventsParameters{IdentityID: &i.ID}, nil)
+	require.NoError(t, err)
+
+	byType := map[string]gjson.Result{}
+	for _, e := range es {
+		byType[e.Type] = gjson.ParseBytes(e.Payload)
+	}
+
+	for _, typ := range []string{"SessionIssued", "LoginSucceeded", "SessionRevoked"} {
+		require.Contains(t, byType, typ)
+		assert.Equal(t, s.ID.String(), byType[typ].Get("session_id").String(), typ)
+	}
+	assert.Equal(t, "password", byType["LoginSucceeded"].Get("method").String())
+	assert.Equal(t, "aal1", byType["SessionIssued"].Get("aal").String())
+}
+
+func TestIdentityEvents(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	conf.MustSet(ctx, config.ViperKeyOutboxEnabled, true)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/identity.schema.json")
+
+	i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+	require.NoError(t, reg.IdentityManager().Create(ctx, i))
+
+	req, err := http.NewRequest("GET", "/", nil)
+	require.NoError(t, err)
+	s, err := session.NewActiveSession(req, i, conf, time.Now(), identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+	require.NoError(t, err)
+	require.NoError(t, reg.SessionPersister().UpsertSession(ctx, s))
+
+	require.NoError(t, reg.PrivilegedIdentityPool().UpdateIdentity(ctx, i))
+	require.NoError(t, reg.PrivilegedIdentityPool().DeleteIdentity(ctx, i.ID))
+
+	es, _, _, err := reg.OutboxPersister().ListEvents(ctx, outbox.ListEventsParameters{IdentityID: &i.ID}, nil)
+	require.NoError(t, err)
+	sequences := map[int64]string{}
+	for _, e := range es {
+		sequences[e.Sequence] = e.Type
+	}
+	expected := map[int64]string{1: "IdentityCreated", 2: "SessionIssued", 3: "IdentityUpdated", 4: "IdentityDeleted"}
+	assert.Equal(t, expected, sequences)
+
+	// Only the oldest undelivered event of the identity is due at a time.
+	for seq := int64(1); seq <= int64(len(expected)); seq++ {
+		next, err := reg.OutboxPersister().NextEvents(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, next, 1)
+		assert.Equal(t, seq, next[0].Sequence)
+		assert.Equal(t, expected[seq], next[0].Type)
+		require.NoError(t, reg.OutboxPersister().SetEventDelivered(ctx, next[0].ID))
+	}
+
+	_, err = reg.OutboxPersister().NextEvents(ctx, 10, time.Minute)
+	require.ErrorIs(t, err, outbox.ErrQueueEmpty)
+}
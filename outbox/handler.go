@@ -0,0 +1,270 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package outbox
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/pagination/keysetpagination"
+	"github.com/ory/x/pagination/migrationpagination"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x"
+)
+
+const (
+	AdminRouteOutbox      = "/outbox"
+	AdminRouteListEvents  = AdminRouteOutbox + "/events"
+	AdminRouteGetEvent    = AdminRouteListEvents + "/:id"
+	AdminRouteReplayEvent = AdminRouteGetEvent + "/replay"
+)
+
+type (
+	handlerDependencies interface {
+		x.WriterProvider
+		x.LoggingProvider
+		x.CSRFProvider
+		PersistenceProvider
+		config.Provider
+	}
+	Handler struct {
+		r handlerDependencies
+	}
+	HandlerProvider interface {
+		OutboxHandler() *Handler
+	}
+)
+
+func NewHandler(r handlerDependencies) *Handler {
+	return &Handler{r: r}
+}
+
+func (h *Handler) RegisterPublicRoutes(public *x.RouterPublic) {
+	h.r.CSRFHandler().IgnoreGlobs(
+		x.AdminPrefix+AdminRouteListEvents, x.AdminPrefix+AdminRouteListEvents+"/*", x.AdminPrefix+AdminRouteListEvents+"/*/replay",
+		AdminRouteListEvents, AdminRouteListEvents+"/*", AdminRouteListEvents+"/*/replay",
+	)
+	public.GET(x.AdminPrefix+AdminRouteListEvents, x.RedirectToAdminRoute(h.r))
+	public.GET(x.AdminPrefix+AdminRouteGetEvent, x.RedirectToAdminRoute(h.r))
+	public.POST(x.AdminPrefix+AdminRouteReplayEvent, x.RedirectToAdminRoute(h.r))
+}
+
+func (h *Handler) RegisterAdminRoutes(admin *x.RouterAdmin) {
+	admin.GET(AdminRouteListEvents, h.listOutboxEvents)
+	admin.GET(AdminRouteGetEvent, h.getOutboxEvent)
+	admin.POST(AdminRouteReplayEvent, h.replayOutboxEvent)
+}
+
+// Paginated Outbox Event List Response
+//
+// swagger:response listOutboxEvents
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listOutboxEventsResponse struct {
+	migrationpagination.ResponseHeaderAnnotation
+
+	// List of outbox events
+	//
+	// in:body
+	Body []Event
+}
+
+// Paginated List Outbox Event Parameters
+//
+// swagger:parameters listOutboxEvents
+type ListEventsParameters struct {
+	keysetpagination.RequestParameters
+
+	// Status filters events by their delivery status.
+	// If no value is provided, it doesn't take effect on filter.
+	//
+	// required: false
+	// in: query
+	Status *Status `json:"status"`
+
+	// Type filters events by their type, for example `IdentityCreated`.
+	// If no value is provided, it doesn't take effect on filter.
+	//
+	// required: false
+	// in: query
+	Type string `json:"type"`
+
+	// IdentityID filters events by the identity they belong to.
+	// If no value is provided, it doesn't take effect on filter.
+	//
+	// required: false
+	// in: query
+	IdentityID *uuid.UUID `json:"identity_id"`
+}
+
+// swagger:route GET /admin/outbox/events outbox listOutboxEvents
+//
+// # List Outbox Events
+//
+// Lists the identity and session lifecycle events in the outbox, newest first.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: listOutboxEvents
+//	  400: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) listOutboxEvents(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	filter, paginator, err := parseEventsFilter(r)
+	if err != nil {
+		h.r.Writer().WriteErrorCode(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	l, tc, nextPage, err := h.r.OutboxPersister().ListEvents(r.Context(), filter, paginator)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("X-Total-Count", fmt.Sprint(tc))
+	keysetpagination.Header(w, r.URL, nextPage)
+	h.r.Writer().Write(w, r, l)
+}
+
+func parseEventsFilter(r *http.Request) (ListEventsParameters, []keysetpagination.Option, error) {
+	var filter ListEventsParameters
+
+	if r.URL.Query().Has("status") {
+		status := Status(r.URL.Query().Get("status"))
+		if err := status.IsValid(); err != nil {
+			return ListEventsParameters{}, nil, err
+		}
+		filter.Status = &status
+	}
+
+	if r.URL.Query().Has("identity_id") {
+		id, err := uuid.FromString(r.URL.Query().Get("identity_id"))
+		if err != nil {
+			return ListEventsParameters{}, nil, herodot.ErrBadRequest.WithError(err.Error()).WithReason("The identity_id query parameter must be a UUID.")
+		}
+		filter.IdentityID = &id
+	}
+
+	filter.Type = r.URL.Query().Get("type")
+
+	opts, err := keysetpagination.Parse(r.URL.Query(), keysetpagination.NewMapPageToken)
+	if err != nil {
+		return ListEventsParameters{}, nil, err
+	}
+
+	return filter, opts, nil
+}
+
+// Get Outbox Event Parameters
+//
+// swagger:parameters getOutboxEvent
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type getOutboxEvent struct {
+	// ID is the ID of the event.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route GET /admin/outbox/events/{id} outbox getOutboxEvent
+//
+// # Get an Outbox Event
+//
+// Gets an outbox event by its ID.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: outboxEvent
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) getOutboxEvent(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, err := uuid.FromString(ps.ByName("id"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, herodot.ErrBadRequest.WithError(err.Error()).WithDebugf("could not parse parameter {id} as UUID, got %s", ps.ByName("id")))
+		return
+	}
+
+	e, err := h.r.OutboxPersister().GetEvent(r.Context(), id)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, e)
+}
+
+// Replay Outbox Event Parameters
+//
+// swagger:parameters replayOutboxEvent
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type replayOutboxEvent struct {
+	// ID is the ID of the event.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route POST /admin/outbox/events/{id}/replay outbox replayOutboxEvent
+//
+// # Replay an Outbox Event
+//
+// Resets the event's delivery status so that it is delivered to all sinks again, for example after
+// it was abandoned or when a downstream service lost its data. The event is delivered after all
+// older undelivered events of the same identity.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: outboxEvent
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) replayOutboxEvent(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, err := uuid.FromString(ps.ByName("id"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, herodot.ErrBadRequest.WithError(err.Error()).WithDebugf("could not parse parameter {id} as UUID, got %s", ps.ByName("id")))
+		return
+	}
+
+	e, err := h.r.OutboxPersister().ReplayEvent(r.Context(), id)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, e)
+}
@@ -0,0 +1,142 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package outbox_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/outbox"
+	"github.com/ory/kratos/x"
+)
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	conf.MustSet(ctx, config.ViperKeyOutboxEnabled, true)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/identity.schema.json")
+	publicTS, adminTS := testhelpers.NewKratosServerWithCSRF(t, reg)
+	conf.MustSet(ctx, config.ViperKeyAdminBaseURL, adminTS.URL)
+
+	tss := []struct {
+		name   string
+		s      *httptest.Server
+		prefix string
+	}{
+		{name: "public", s: publicTS, prefix: x.AdminPrefix},
+		{name: "admin", s: adminTS},
+	}
+
+	var do = func(t *testing.T, ts *httptest.Server, method, href string, expectCode int) gjson.Result {
+		t.Helper()
+		req, err := http.NewRequest(method, ts.URL+href, nil)
+		require.NoError(t, err)
+		res, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+
+		assert.EqualValuesf(t, expectCode, res.StatusCode, "%s", body)
+		return gjson.ParseBytes(body)
+	}
+
+	t.Run("case=should return an empty list of events", func(t *testing.T) {
+		for _, tc := range tss {
+			t.Run("endpoint="+tc.name, func(t *testing.T) {
+				parsed := do(t, tc.s, "GET", tc.prefix+outbox.AdminRouteListEvents, http.StatusOK)
+				assert.Len(t, parsed.Array(), 0)
+			})
+		}
+	})
+
+	i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+	i.Traits = identity.Traits(`{"email":"outbox@ory.sh"}`)
+	require.NoError(t, reg.IdentityManager().Create(ctx, i))
+	i.Traits = identity.Traits(`{"email":"outbox-updated@ory.sh"}`)
+	require.NoError(t, reg.IdentityManager().Update(ctx, i, identity.ManagerAllowWriteProtectedTraits))
+
+	t.Run("case=should list the events of the identity", func(t *testing.T) {
+		for _, tc := range tss {
+			t.Run("endpoint="+tc.name, func(t *testing.T) {
+				parsed := do(t, tc.s, "GET", fmt.Sprintf("%s%s?identity_id=%s", tc.prefix, outbox.AdminRouteListEvents, i.ID), http.StatusOK)
+				require.Len(t, parsed.Array(), 2, "%s", parsed.Raw)
+				for _, e := range parsed.Array() {
+					assert.Equal(t, i.ID.String(), e.Get("identity_id").String())
+					assert.Equal(t, "pending", e.Get("status").String())
+				}
+			})
+		}
+	})
+
+	t.Run("case=should filter events", func(t *testing.T) {
+		for _, tc := range tss {
+			t.Run("endpoint="+tc.name, func(t *testing.T) {
+				parsed := do(t, tc.s, "GET", tc.prefix+outbox.AdminRouteListEvents+"?type=IdentityUpdated", http.StatusOK)
+				require.Len(t, parsed.Array(), 1, "%s", parsed.Raw)
+				assert.Equal(t, "IdentityUpdated", parsed.Get("0.type").String())
+
+				parsed = do(t, tc.s, "GET", tc.prefix+outbox.AdminRouteListEvents+"?status=delivered", http.StatusOK)
+				assert.Len(t, parsed.Array(), 0)
+
+				parsed = do(t, tc.s, "GET", fmt.Sprintf("%s%s?identity_id=%s", tc.prefix, outbox.AdminRouteListEvents, uuid.Must(uuid.NewV4())), http.StatusOK)
+				assert.Len(t, parsed.Array(), 0)
+			})
+		}
+	})
+
+	t.Run("case=should reject invalid filters", func(t *testing.T) {
+		for _, tc := range tss {
+			t.Run("endpoint="+tc.name, func(t *testing.T) {
+				do(t, tc.s, "GET", tc.prefix+outbox.AdminRouteListEvents+"?status=invalid", http.StatusBadRequest)
+				do(t, tc.s, "GET", tc.prefix+outbox.AdminRouteListEvents+"?identity_id=invalid", http.StatusBadRequest)
+			})
+		}
+	})
+
+	es, _, _, err := reg.OutboxPersister().ListEvents(ctx, outbox.ListEventsParameters{Type: "IdentityCreated"}, nil)
+	require.NoError(t, err)
+	require.Len(t, es, 1)
+	event := es[0]
+
+	t.Run("case=should get an event", func(t *testing.T) {
+		for _, tc := range tss {
+			t.Run("endpoint="+tc.name, func(t *testing.T) {
+				parsed := do(t, tc.s, "GET", tc.prefix+outbox.AdminRouteListEvents+"/"+event.ID.String(), http.StatusOK)
+				assert.Equal(t, event.ID.String(), parsed.Get("id").String())
+				assert.Equal(t, i.ID.String(), parsed.Get("payload.identity_id").String())
+
+				do(t, tc.s, "GET", tc.prefix+outbox.AdminRouteListEvents+"/"+uuid.Must(uuid.NewV4()).String(), http.StatusNotFound)
+				do(t, tc.s, "GET", tc.prefix+outbox.AdminRouteListEvents+"/not-a-uuid", http.StatusBadRequest)
+			})
+		}
+	})
+
+	t.Run("case=should replay an event", func(t *testing.T) {
+		require.NoError(t, reg.OutboxPersister().SetEventDelivered(ctx, event.ID))
+
+		for _, tc := range tss {
+			t.Run("endpoint="+tc.name, func(t *testing.T) {
+				parsed := do(t, tc.s, "POST", tc.prefix+outbox.AdminRouteListEvents+"/"+event.ID.String()+"/replay", http.StatusOK)
+				assert.Equal(t, "pending", parsed.Get("status").String())
+				assert.EqualValues(t, 0, parsed.Get("attempts").Int())
+
+				do(t, tc.s, "POST", tc.prefix+outbox.AdminRouteListEvents+"/"+uuid.Must(uuid.NewV4()).String()+"/replay", http.StatusNotFound)
+			})
+		}
+	})
+}
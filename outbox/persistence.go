@@ -0,0 +1,52 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/pagination/keysetpagination"
+)
+
+var ErrQueueEmpty = errors.New("queue is empty")
+
+type (
+	Persister interface {
+		// AddEvents writes the events to the outbox and assigns their per-identity sequence in
+		// the order they are given. If called within a transaction, the events are only visible
+		// once the transaction commits.
+		AddEvents(ctx context.Context, es ...*Event) error
+
+		// NextEvents claims up to limit events which are due for delivery and sets their status to
+		// processing for the duration of the lease. Events whose lease expired before they were
+		// delivered are due again. Only the oldest undelivered event of each identity is returned
+		// so that events are delivered in order per identity. Returns ErrQueueEmpty if no event is
+		// due.
+		NextEvents(ctx context.Context, limit uint8, lease time.Duration) ([]Event, error)
+
+		// SetEventDelivered marks the event as delivered.
+		SetEventDelivered(ctx context.Context, id uuid.UUID) error
+
+		// SetEventFailed records a failed delivery attempt. The event is retried at nextAttemptAt
+		// or abandoned if abandon is true.
+		SetEventFailed(ctx context.Context, id uuid.UUID, deliveryErr error, nextAttemptAt time.Time, abandon bool) error
+
+		// ListEvents lists the events in the store given the filter.
+		// Returns list of events, total count of events satisfied by given filter, and error if any
+		ListEvents(ctx context.Context, filter ListEventsParameters, opts []keysetpagination.Option) ([]Event, int64, *keysetpagination.Paginator, error)
+
+		// GetEvent returns the event with the id or an error if not found.
+		GetEvent(ctx context.Context, id uuid.UUID) (*Event, error)
+
+		// ReplayEvent resets the event's status to pending so that it is delivered again.
+		ReplayEvent(ctx context.Context, id uuid.UUID) (*Event, error)
+	}
+	PersistenceProvider interface {
+		OutboxPersister() Persister
+	}
+)
@@ -16,9 +16,14 @@ import (
 
 	"github.com/ory/x/popx"
 
+	"github.com/ory/kratos/audit"
+	"github.com/ory/kratos/bruteforce"
 	"github.com/ory/kratos/continuity"
 	"github.com/ory/kratos/courier"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
+	"github.com/ory/kratos/outbox"
+	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/selfservice/errorx"
 	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/recovery"
@@ -27,6 +32,7 @@ import (
 	"github.com/ory/kratos/selfservice/flow/verification"
 	"github.com/ory/kratos/selfservice/strategy/code"
 	"github.com/ory/kratos/selfservice/strategy/link"
+	"github.com/ory/kratos/selfservice/strategy/saml"
 	"github.com/ory/kratos/session"
 )
 
@@ -51,6 +57,15 @@ type Persister interface {
 	link.VerificationTokenPersister
 	code.RecoveryCodePersister
 	code.VerificationCodePersister
+	code.LoginCodePersister
+	code.RegistrationCodePersister
+	bruteforce.Persister
+	saml.AssertionPersister
+	organization.Persister
+	outbox.Persister
+	audit.Persister
+	schema.Persister
+	identity.SchemaMigrationPersister
 
 	CleanupDatabase(context.Context, time.Duration, time.Duration, int) error
 	Close(context.Context) error
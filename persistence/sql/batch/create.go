@@ -159,6 +159,13 @@ func buildInsertQueryValues[T any](dialect string, mapper *reflectx.Mapper, colu
 					field.Set(reflect.Zero(field.Type()))
 				}
 			}
+
+			// Special-handling for *uuid.UUID: mapper.FieldByName sets this to a zero UUID, but we want
+			// a nil pointer and a NULL value instead.
+			if u, ok := field.Interface().(*uuid.UUID); ok && *u == uuid.Nil {
+				field.Set(reflect.Zero(field.Type()))
+				values[len(values)-1] = nil
+			}
 		}
 	}
 
@@ -143,4 +143,23 @@ func Test_buildInsertQueryValues(t *testing.T) {
 
 		})
 	})
+
+	t.Run("case=nil uuid pointer", func(t *testing.T) {
+		mapper := reflectx.NewMapper("db")
+		nowFunc := func() time.Time {
+			return time.Time{}
+		}
+
+		model := &identity.Identity{}
+		values, err := buildInsertQueryValues("other", mapper, []string{"organization_id"}, []*identity.Identity{model}, nowFunc)
+		require.NoError(t, err)
+		assert.Equal(t, []any{nil}, values)
+		assert.Nil(t, model.OrganizationID)
+
+		orgID := uuid.Must(uuid.NewV4())
+		model = &identity.Identity{OrganizationID: &orgID}
+		values, err = buildInsertQueryValues("other", mapper, []string{"organization_id"}, []*identity.Identity{model}, nowFunc)
+		require.NoError(t, err)
+		assert.Equal(t, []any{&orgID}, values)
+	})
 }
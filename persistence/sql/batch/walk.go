@@ -0,0 +1,61 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package batch
+
+import (
+	"context"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+)
+
+// Walk loads the models matched by the scope in batches of the given size and calls fn with every batch.
+//
+// The models are ordered by their ID and loaded starting after the given ID, or from the beginning if it is
+// uuid.Nil. Because every batch is loaded after the last ID of the previous batch, fn may update the models
+// without affecting the walk. Walk stops when all models were loaded or when fn returns an error.
+func Walk[T any](ctx context.Context, p *TracerConnection, after uuid.UUID, size int, scope pop.ScopeFunc, fn func(ctx context.Context, models []T) error) (err error) {
+	ctx, span := p.Tracer.Tracer().Start(ctx, "persistence.sql.batch.Walk")
+	defer otelx.End(span, &err)
+
+	if size < 1 {
+		return errors.Errorf("batch size must be positive but got %d", size)
+	}
+
+	for {
+		var models []T
+		q := p.Connection.WithContext(ctx).Where("id > ?", after)
+		if scope != nil {
+			q = q.Scope(scope)
+		}
+		if err := q.Order("id ASC").Limit(size).All(&models); err != nil {
+			return sqlcon.HandleError(err)
+		}
+
+		if len(models) == 0 {
+			return nil
+		}
+
+		if err := fn(ctx, models); err != nil {
+			return err
+		}
+
+		if len(models) < size {
+			return nil
+		}
+
+		// pop returns UUID primary keys as strings.
+		id, ok := pop.NewModel(&models[len(models)-1], ctx).ID().(string)
+		if !ok {
+			return errors.Errorf("model %T does not have a UUID primary key", models[0])
+		}
+		if after, err = uuid.FromString(id); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+}
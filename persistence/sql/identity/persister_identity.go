@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/ory/x/contextx"
+	"github.com/ory/x/pagination/keysetpagination"
 	"github.com/ory/x/pointerx"
 	"github.com/ory/x/popx"
 
@@ -27,8 +28,11 @@ import (
 
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/otp"
+	"github.com/ory/kratos/outbox"
 	"github.com/ory/kratos/persistence/sql/batch"
+	outboxpersistence "github.com/ory/kratos/persistence/sql/outbox"
 	"github.com/ory/kratos/persistence/sql/update"
 	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/x"
@@ -54,6 +58,7 @@ type dependencies interface {
 	config.Provider
 	contextx.Provider
 	x.TracingProvider
+	identity.CacheInvalidatorProvider
 }
 
 type IdentityPersister struct {
@@ -127,8 +132,16 @@ func NormalizeIdentifier(ct identity.CredentialsType, match string) string {
 	case identity.CredentialsTypeOIDC:
 		// OIDC credentials are case-sensitive
 		return match
+	case identity.CredentialsTypePasskey:
+		// passkey credentials are identified by their case-sensitive user handle
+		return match
+	case identity.CredentialsTypeSAML:
+		// SAML name identifiers are case-sensitive
+		return match
 	case identity.CredentialsTypePassword:
 		fallthrough
+	case identity.CredentialsTypeCodeAuth:
+		fallthrough
 	case identity.CredentialsTypeWebAuthn:
 		return stringToLowerTrim(match)
 	}
@@ -473,10 +486,28 @@ func (p *IdentityPersister) CreateIdentities(ctx context.Context, identities ...
 		if err = p.createIdentityCredentials(ctx, tx, identities...); err != nil {
 			return sqlcon.HandleError(err)
 		}
-		return nil
+
+		events := make([]*outbox.Event, len(identities))
+		for k, ident := range identities {
+			events[k] = outbox.NewIdentityCreated(ident.ID)
+		}
+		return p.addOutboxEvents(ctx, tx, events...)
 	})
 }
 
+// addOutboxEvents writes the events to the outbox in the given transaction if the outbox is enabled.
+func (p *IdentityPersister) addOutboxEvents(ctx context.Context, tx *pop.Connection, events ...*outbox.Event) error {
+	if !p.r.Config().OutboxEnabled(ctx) {
+		return nil
+	}
+
+	for _, e := range events {
+		e.NID = p.NetworkID(ctx)
+	}
+
+	return outboxpersistence.Create(ctx, &batch.TracerConnection{Tracer: p.r.Tracer(ctx), Connection: tx}, events)
+}
+
 func (p *IdentityPersister) HydrateIdentityAssociations(ctx context.Context, i *identity.Identity, expand identity.Expandables) (err error) {
 	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.HydrateIdentityAssociations")
 	defer otelx.End(span, &err)
@@ -486,14 +517,14 @@ func (p *IdentityPersister) HydrateIdentityAssociations(ctx context.Context, i *
 		nid = p.NetworkID(ctx)
 	)
 
-	eg, ctx := errgroup.WithContext(ctx)
+	eg, egCtx := errgroup.WithContext(ctx)
 	if expand.Has(identity.ExpandFieldRecoveryAddresses) {
 		eg.Go(func() error {
 			// We use WithContext to get a copy of the connection struct, which solves the race detector
 			// from complaining incorrectly.
 			//
 			// https://github.com/gobuffalo/pop/issues/723
-			if err := con.WithContext(ctx).
+			if err := con.WithContext(egCtx).
 				Where("identity_id = ? AND nid = ?", i.ID, nid).
 				Order("id ASC").
 				All(&i.RecoveryAddresses); err != nil {
@@ -509,7 +540,7 @@ func (p *IdentityPersister) HydrateIdentityAssociations(ctx context.Context, i *
 			// from complaining incorrectly.
 			//
 			// https://github.com/gobuffalo/pop/issues/723
-			if err := con.WithContext(ctx).
+			if err := con.WithContext(egCtx).
 				Order("id ASC").
 				Where("identity_id = ? AND nid = ?", i.ID, nid).
 				All(&i.VerifiableAddresses); err != nil {
@@ -525,7 +556,7 @@ func (p *IdentityPersister) HydrateIdentityAssociations(ctx context.Context, i *
 			// from complaining incorrectly.
 			//
 			// https://github.com/gobuffalo/pop/issues/723
-			con := con.WithContext(ctx)
+			con := con.WithContext(egCtx)
 			creds, err := QueryForCredentials(con,
 				Where{"(identity_credentials.identity_id = ? AND identity_credentials.nid = ?)", []interface{}{i.ID, nid}})
 			if err != nil {
@@ -650,7 +681,7 @@ func QueryForCredentials(con *pop.Connection, where ...Where) (map[uuid.UUID](ma
 	return credentialsPerIdentity, nil
 }
 
-func (p *IdentityPersister) ListIdentities(ctx context.Context, params identity.ListIdentityParameters) (res []identity.Identity, err error) {
+func (p *IdentityPersister) ListIdentities(ctx context.Context, params identity.ListIdentityParameters) (res []identity.Identity, nextPage *keysetpagination.Paginator, err error) {
 	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListIdentities")
 	defer otelx.End(span, &err)
 
@@ -659,6 +690,8 @@ func (p *IdentityPersister) ListIdentities(ctx context.Context, params identity.
 		attribute.Int("per_page", params.PerPage),
 		attribute.StringSlice("expand", params.Expand.ToEager()),
 		attribute.Bool("use:credential_identifier_filter", params.CredentialsIdentifier != ""),
+		attribute.Bool("use:keyset_pagination", params.KeySetPagination != nil),
+		attribute.Bool("use:filters", params.HasFilters()),
 		attribute.String("network.id", p.NetworkID(ctx).String()),
 	)
 
@@ -666,7 +699,7 @@ func (p *IdentityPersister) ListIdentities(ctx context.Context, params identity.
 
 	con := p.GetConnection(ctx)
 	nid := p.NetworkID(ctx)
-	query := con.Where("identities.nid = ?", nid).Order("identities.id DESC")
+	query := con.Where("identities.nid = ?", nid)
 
 	// Credentials are not expanded through `EagerPreload` but manually after
 	// fetching the identities, hence we filter out the relevant expand options.
@@ -680,7 +713,27 @@ func (p *IdentityPersister) ListIdentities(ctx context.Context, params identity.
 		query = query.EagerPreload(expandExceptCredentials.ToEager()...)
 	}
 
-	if match := params.CredentialsIdentifier; len(match) > 0 {
+	applyListIdentitiesFilters(con.Dialect.Name(), query, nid, params)
+
+	var paginator *keysetpagination.Paginator
+	if params.KeySetPagination != nil {
+		if match := params.CredentialsIdentifier; len(match) > 0 {
+			// The keyset paginator orders by the unqualified ID column, which is why we can not join the credentials
+			// here but have to use a sub query instead.
+			query = query.Where(`EXISTS (SELECT 1 FROM identity_credentials ic
+	INNER JOIN identity_credential_types ict ON ict.id = ic.identity_credential_type_id
+	INNER JOIN identity_credential_identifiers ici ON ici.identity_credential_id = ic.id
+	WHERE ic.identity_id = identities.id AND ic.nid = ? AND ici.nid = ? AND ici.identifier = ? AND ict.name IN (?, ?))`,
+				nid, nid, NormalizeIdentifier(identity.CredentialsTypePassword, match), identity.CredentialsTypeWebAuthn, identity.CredentialsTypePassword)
+		}
+
+		paginator = keysetpagination.GetPaginator(append(params.KeySetPagination,
+			keysetpagination.WithDefaultSize(250),
+			keysetpagination.WithMaxSize(1000),
+			keysetpagination.WithDefaultToken(new(identity.Identity).DefaultPageToken()),
+		)...)
+		query = query.Scope(keysetpagination.Paginate[identity.Identity](paginator))
+	} else if match := params.CredentialsIdentifier; len(match) > 0 {
 		// When filtering by credentials identifier, we most likely are looking for a username or email. It is therefore
 		// important to normalize the identifier before querying the database.
 		match = NormalizeIdentifier(identity.CredentialsTypePassword, match)
@@ -690,17 +743,22 @@ func (p *IdentityPersister) ListIdentities(ctx context.Context, params identity.
 			InnerJoin("identity_credential_identifiers ici", "ici.identity_credential_id = ic.id").
 			Where("(ic.nid = ? AND ici.nid = ? AND ici.identifier = ?)", nid, nid, match).
 			Where("ict.name IN (?)", identity.CredentialsTypeWebAuthn, identity.CredentialsTypePassword).
+			Order("identities.id DESC").
 			Limit(1)
 	} else {
-		query = query.Paginate(params.Page+1, params.PerPage)
+		query = query.Order("identities.id DESC").Paginate(params.Page+1, params.PerPage)
 	}
 
 	if err := sqlcon.HandleError(query.All(&is)); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if paginator != nil {
+		is, nextPage = keysetpagination.Result(is, paginator)
 	}
 
 	if len(is) == 0 {
-		return is, nil
+		return is, nextPage, nil
 	}
 
 	if params.Expand.Has(identity.ExpandFieldCredentials) {
@@ -712,7 +770,7 @@ func (p *IdentityPersister) ListIdentities(ctx context.Context, params identity.
 			Where{"identity_credentials.nid = ?", []interface{}{nid}},
 			Where{"identity_credentials.identity_id IN (?)", ids})
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		for k := range is {
 			is[k].Credentials = creds[is[k].ID]
@@ -727,23 +785,100 @@ func (p *IdentityPersister) ListIdentities(ctx context.Context, params identity.
 			i.SchemaURL = u
 		} else {
 			if err := p.InjectTraitsSchemaURL(ctx, i); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			schemaCache[i.SchemaID] = i.SchemaURL
 		}
 
 		if err := i.Validate(); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		if err := identity.UpgradeCredentials(i); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		is[k] = *i
 	}
 
-	return is, nil
+	return is, nextPage, nil
+}
+
+func applyListIdentitiesFilters(dialect string, query *pop.Query, nid uuid.UUID, params identity.ListIdentityParameters) {
+	for _, f := range params.Traits {
+		condition, args := traitFilterCondition(dialect, f)
+		query.Where(condition, args...)
+	}
+
+	if params.State != "" {
+		query.Where("identities.state = ?", params.State)
+	}
+
+	if params.SchemaID != "" {
+		query.Where("identities.schema_id = ?", params.SchemaID)
+	}
+
+	if t := params.StateExpiredBefore; t != nil {
+		query.Where("((identities.state = ? AND identities.suspended_until <= ?) OR (identities.state = ? AND identities.delete_after <= ?))",
+			identity.StateSuspended, t.UTC(), identity.StatePendingDeletion, t.UTC())
+	}
+
+	if params.CredentialsType != "" {
+		query.Where(`EXISTS (SELECT 1 FROM identity_credentials fic
+	INNER JOIN identity_credential_types fict ON fict.id = fic.identity_credential_type_id
+	WHERE fic.identity_id = identities.id AND fic.nid = ? AND fict.name = ?)`, nid, params.CredentialsType)
+	}
+
+	if params.Verified != nil {
+		verified := "EXISTS (SELECT 1 FROM identity_verifiable_addresses fiva WHERE fiva.identity_id = identities.id AND fiva.nid = ? AND fiva.verified = ?)"
+		if !*params.Verified {
+			verified = "NOT " + verified
+		}
+		query.Where(verified, nid, true)
+	}
+
+	for condition, t := range map[string]*time.Time{
+		"identities.created_at >= ?": params.CreatedAfter,
+		"identities.created_at <= ?": params.CreatedBefore,
+		"identities.updated_at >= ?": params.UpdatedAfter,
+		"identities.updated_at <= ?": params.UpdatedBefore,
+	} {
+		if t != nil {
+			query.Where(condition, t.UTC())
+		}
+	}
+}
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// traitFilterCondition returns the SQL condition and its arguments for the given trait filter. The path segments
+// are passed as arguments and are restricted to safe characters by identity.ParseTraitFilter.
+func traitFilterCondition(dialect string, f identity.TraitFilter) (string, []interface{}) {
+	var trait string
+	var args []interface{}
+	switch dialect {
+	case "postgres", "cockroach":
+		trait = "identities.traits"
+		for k, segment := range f.Path {
+			operator := "->"
+			if k == len(f.Path)-1 {
+				operator = "->>"
+			}
+			trait += fmt.Sprintf(" %s CAST(? AS TEXT)", operator)
+			args = append(args, segment)
+		}
+	case "mysql":
+		trait = "JSON_UNQUOTE(JSON_EXTRACT(identities.traits, ?))"
+		args = append(args, `$."`+strings.Join(f.Path, `"."`)+`"`)
+	default:
+		trait = "json_extract(identities.traits, ?)"
+		args = append(args, `$."`+strings.Join(f.Path, `"."`)+`"`)
+	}
+
+	if f.Prefix {
+		return fmt.Sprintf("(%s) LIKE ? ESCAPE '!'", trait), append(args, likeEscaper.Replace(f.Value)+"%")
+	}
+	return fmt.Sprintf("(%s) = ?", trait), append(args, f.Value)
 }
 
 func (p *IdentityPersister) UpdateIdentity(ctx context.Context, i *identity.Identity) (err error) {
@@ -755,7 +890,7 @@ func (p *IdentityPersister) UpdateIdentity(ctx context.Context, i *identity.Iden
 	}
 
 	i.NID = p.NetworkID(ctx)
-	return sqlcon.HandleError(p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+	if err := sqlcon.HandleError(p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
 		if count, err := tx.Where("id = ? AND nid = ?", i.ID, p.NetworkID(ctx)).Count(i); err != nil {
 			return err
 		} else if count == 0 {
@@ -783,8 +918,16 @@ func (p *IdentityPersister) UpdateIdentity(ctx context.Context, i *identity.Iden
 			return err
 		}
 
-		return sqlcon.HandleError(p.createIdentityCredentials(ctx, tx, i))
-	}))
+		if err := p.createIdentityCredentials(ctx, tx, i); err != nil {
+			return sqlcon.HandleError(err)
+		}
+
+		return p.addOutboxEvents(ctx, tx, outbox.NewIdentityUpdated(i.ID))
+	})); err != nil {
+		return err
+	}
+
+	return p.r.IdentityCacheInvalidator().InvalidateIdentity(ctx, i.ID)
 }
 
 func (p *IdentityPersister) DeleteIdentity(ctx context.Context, id uuid.UUID) (err error) {
@@ -792,17 +935,24 @@ func (p *IdentityPersister) DeleteIdentity(ctx context.Context, id uuid.UUID) (e
 	defer otelx.End(span, &err)
 
 	nid := p.NetworkID(ctx)
-	count, err := p.GetConnection(ctx).RawQuery(fmt.Sprintf("DELETE FROM %s WHERE id = ? AND nid = ?", new(identity.Identity).TableName(ctx)),
-		id,
-		nid,
-	).ExecWithCount()
-	if err != nil {
-		return sqlcon.HandleError(err)
-	}
-	if count == 0 {
-		return errors.WithStack(sqlcon.ErrNoRows)
+	if err := p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		count, err := tx.RawQuery(fmt.Sprintf("DELETE FROM %s WHERE id = ? AND nid = ?", new(identity.Identity).TableName(ctx)),
+			id,
+			nid,
+		).ExecWithCount()
+		if err != nil {
+			return sqlcon.HandleError(err)
+		}
+		if count == 0 {
+			return errors.WithStack(sqlcon.ErrNoRows)
+		}
+
+		return p.addOutboxEvents(ctx, tx, outbox.NewIdentityDeleted(id))
+	}); err != nil {
+		return err
 	}
-	return nil
+
+	return p.r.IdentityCacheInvalidator().InvalidateIdentity(ctx, id)
 }
 
 func (p *IdentityPersister) GetIdentity(ctx context.Context, id uuid.UUID, expand identity.Expandables) (_ *identity.Identity, err error) {
@@ -897,7 +1047,66 @@ func (p *IdentityPersister) UpdateVerifiableAddress(ctx context.Context, address
 
 	address.NID = p.NetworkID(ctx)
 	address.Value = stringToLowerTrim(address.Value)
-	return update.Generic(ctx, p.GetConnection(ctx), p.r.Tracer(ctx).Tracer(), address)
+	if err := update.Generic(ctx, p.GetConnection(ctx), p.r.Tracer(ctx).Tracer(), address); err != nil {
+		return err
+	}
+
+	return p.r.IdentityCacheInvalidator().InvalidateIdentity(ctx, address.IdentityID)
+}
+
+func (p *IdentityPersister) WalkCredentials(ctx context.Context, ct identity.CredentialsType, after uuid.UUID, size int, fn func(ctx context.Context, credentials []identity.Credentials) ([]identity.Credentials, error)) (skipped int, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.WalkCredentials")
+	defer otelx.End(span, &err)
+
+	t, err := p.findIdentityCredentialsType(ctx, ct)
+	if err != nil {
+		return 0, err
+	}
+
+	// The update only applies if the config was not changed since it was loaded, so that concurrent changes, for
+	// example a password change, are not overwritten.
+	query := "UPDATE identity_credentials SET config = ?, updated_at = ? WHERE id = ? AND nid = ? AND config = ?"
+	if p.GetConnection(ctx).Dialect.Name() == "mysql" {
+		query = "UPDATE identity_credentials SET config = ?, updated_at = ? WHERE id = ? AND nid = ? AND config = CAST(? AS JSON)"
+	}
+
+	nid := p.NetworkID(ctx)
+	conn := &batch.TracerConnection{Tracer: p.r.Tracer(ctx), Connection: p.GetConnection(ctx)}
+	err = batch.Walk(ctx, conn, after, size, func(q *pop.Query) *pop.Query {
+		return q.Where("nid = ? AND identity_credential_type_id = ?", nid, t.ID)
+	}, func(ctx context.Context, credentials []identity.Credentials) error {
+		loaded := make(map[uuid.UUID]sqlxx.JSONRawMessage, len(credentials))
+		for k := range credentials {
+			credentials[k].Type = ct
+			loaded[credentials[k].ID] = append(sqlxx.JSONRawMessage{}, credentials[k].Config...)
+		}
+
+		updated, err := fn(ctx, credentials)
+		if err != nil || len(updated) == 0 {
+			return err
+		}
+
+		var changed int
+		if err := p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+			changed = 0
+			now := time.Now().UTC().Truncate(time.Microsecond)
+			for _, cred := range updated {
+				count, err := tx.RawQuery(query, cred.Config, now, cred.ID, nid, loaded[cred.ID]).ExecWithCount()
+				if err != nil {
+					return sqlcon.HandleError(err)
+				} else if count == 0 {
+					changed++
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		skipped += changed
+		return nil
+	})
+	return skipped, err
 }
 
 func (p *IdentityPersister) validateIdentity(ctx context.Context, i *identity.Identity) (err error) {
@@ -911,6 +1120,14 @@ func (p *IdentityPersister) validateIdentity(ctx context.Context, i *identity.Id
 		return err
 	}
 
+	if i.OrganizationID != nil {
+		if exists, err := p.GetConnection(ctx).Where("id = ? AND nid = ?", *i.OrganizationID, p.NetworkID(ctx)).Exists(new(organization.Organization)); err != nil {
+			return sqlcon.HandleError(err)
+		} else if !exists {
+			return errors.WithStack(herodot.ErrBadRequest.WithReasonf("Organization %s does not exist.", *i.OrganizationID))
+		}
+	}
+
 	return nil
 }
 
@@ -918,11 +1135,7 @@ func (p *IdentityPersister) InjectTraitsSchemaURL(ctx context.Context, i *identi
 	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.InjectTraitsSchemaURL")
 	defer otelx.End(span, &err)
 
-	ss, err := p.r.IdentityTraitsSchemas(ctx)
-	if err != nil {
-		return err
-	}
-	s, err := ss.GetByID(i.SchemaID)
+	s, err := p.r.IdentityTraitsSchema(ctx, i.SchemaID)
 	if err != nil {
 		return errors.WithStack(herodot.ErrInternalServerError.WithReasonf(
 			`The JSON Schema "%s" for this identity's traits could not be found.`, i.SchemaID))
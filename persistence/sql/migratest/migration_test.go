@@ -164,7 +164,7 @@ func testDatabase(t *testing.T, db string, c *pop.Connection) {
 			defer wg.Done()
 			t.Parallel()
 
-			ids, err := d.PrivilegedIdentityPool().ListIdentities(context.Background(), identity.ListIdentityParameters{Expand: identity.ExpandEverything, Page: 0, PerPage: 1000})
+			ids, _, err := d.PrivilegedIdentityPool().ListIdentities(context.Background(), identity.ListIdentityParameters{Expand: identity.ExpandEverything, Page: 0, PerPage: 1000})
 			require.NoError(t, err)
 			require.NotEmpty(t, ids)
 
@@ -192,7 +192,7 @@ func testDatabase(t *testing.T, db string, c *pop.Connection) {
 			defer wg.Done()
 			t.Parallel()
 
-			ids, err := d.PrivilegedIdentityPool().ListIdentities(context.Background(), identity.ListIdentityParameters{Expand: identity.ExpandNothing, Page: 0, PerPage: 1000})
+			ids, _, err := d.PrivilegedIdentityPool().ListIdentities(context.Background(), identity.ListIdentityParameters{Expand: identity.ExpandNothing, Page: 0, PerPage: 1000})
 			require.NoError(t, err)
 			require.NotEmpty(t, ids)
 
@@ -0,0 +1,9 @@
+DROP TABLE identity_login_codes;
+
+DROP TABLE identity_registration_codes;
+
+ALTER TABLE
+  selfservice_login_flows DROP COLUMN submit_count;
+
+ALTER TABLE
+  selfservice_registration_flows DROP COLUMN submit_count;
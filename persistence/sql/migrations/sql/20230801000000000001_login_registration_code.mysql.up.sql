@@ -0,0 +1,53 @@
+CREATE TABLE identity_login_codes (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    code_hmac VARCHAR (64) NOT NULL,
+    -- HMACed value of the actual code
+    address VARCHAR (255) NOT NULL,
+    address_type VARCHAR(36) NOT NULL,
+    used_at timestamp NULL DEFAULT NULL,
+    identity_id CHAR(36) NOT NULL,
+    expires_at timestamp NOT NULL DEFAULT '2000-01-01 00:00:00',
+    issued_at timestamp NOT NULL DEFAULT '2000-01-01 00:00:00',
+    selfservice_login_flow_id CHAR(36) NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    nid CHAR(36) NOT NULL,
+    CONSTRAINT identity_login_codes_identities_id_fk FOREIGN KEY (identity_id) REFERENCES identities (id) ON DELETE cascade,
+    CONSTRAINT identity_login_codes_selfservice_login_flows_id_fk FOREIGN KEY (selfservice_login_flow_id) REFERENCES selfservice_login_flows (id) ON DELETE cascade,
+    CONSTRAINT identity_login_codes_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE TABLE identity_registration_codes (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    code_hmac VARCHAR (64) NOT NULL,
+    -- HMACed value of the actual code
+    address VARCHAR (255) NOT NULL,
+    address_type VARCHAR(36) NOT NULL,
+    used_at timestamp NULL DEFAULT NULL,
+    expires_at timestamp NOT NULL DEFAULT '2000-01-01 00:00:00',
+    issued_at timestamp NOT NULL DEFAULT '2000-01-01 00:00:00',
+    selfservice_registration_flow_id CHAR(36) NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    nid CHAR(36) NOT NULL,
+    CONSTRAINT identity_registration_codes_selfservice_registration_flows_id_fk FOREIGN KEY (selfservice_registration_flow_id) REFERENCES selfservice_registration_flows (id) ON DELETE cascade,
+    CONSTRAINT identity_registration_codes_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+ALTER TABLE
+    selfservice_login_flows
+ADD
+    COLUMN submit_count INT NOT NULL DEFAULT 0;
+
+ALTER TABLE
+    selfservice_registration_flows
+ADD
+    COLUMN submit_count INT NOT NULL DEFAULT 0;
+
+CREATE INDEX identity_login_codes_nid_flow_id_idx ON identity_login_codes (nid, selfservice_login_flow_id);
+
+CREATE INDEX identity_login_codes_id_nid_idx ON identity_login_codes (id, nid);
+
+CREATE INDEX identity_registration_codes_nid_flow_id_idx ON identity_registration_codes (nid, selfservice_registration_flow_id);
+
+CREATE INDEX identity_registration_codes_id_nid_idx ON identity_registration_codes (id, nid);
@@ -0,0 +1,15 @@
+CREATE TABLE selfservice_login_throttles (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    nid CHAR(36) NOT NULL,
+    key_type VARCHAR(16) NOT NULL,
+    -- HMACed value of the identifier or IP address
+    key_hmac VARCHAR(64) NOT NULL,
+    failed_attempts INT NOT NULL DEFAULT 0,
+    last_failed_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until timestamp NULL DEFAULT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT selfservice_login_throttles_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX selfservice_login_throttles_nid_key_type_key_hmac_uq_idx ON selfservice_login_throttles (nid, key_type, key_hmac);
@@ -0,0 +1 @@
+INSERT INTO identity_credential_types (id, name) SELECT 'd106283c-21ee-4845-8e40-6fd03e03e535', 'passkey' WHERE NOT EXISTS ( SELECT * FROM identity_credential_types WHERE name = 'passkey');
@@ -0,0 +1 @@
+INSERT INTO identity_credential_types (id, name) SELECT 'a8f2d3b5-0a63-4c36-9f3e-5d7e1c4b2f91', 'saml' WHERE NOT EXISTS ( SELECT * FROM identity_credential_types WHERE name = 'saml');
@@ -0,0 +1,26 @@
+CREATE TABLE organizations (
+    id UUID NOT NULL PRIMARY KEY,
+    nid UUID NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    -- ID of the OpenID Connect provider members must sign in with
+    oidc_provider VARCHAR(255) NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    CONSTRAINT organizations_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE INDEX organizations_nid_id_idx ON organizations (nid, id);
+
+CREATE TABLE organization_domains (
+    id UUID NOT NULL PRIMARY KEY,
+    nid UUID NOT NULL,
+    organization_id UUID NOT NULL,
+    domain VARCHAR(255) NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    CONSTRAINT organization_domains_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE,
+    CONSTRAINT organization_domains_organizations_id_fk FOREIGN KEY (organization_id) REFERENCES organizations (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX organization_domains_nid_domain_uq_idx ON organization_domains (nid, domain);
+CREATE INDEX organization_domains_organization_id_idx ON organization_domains (organization_id);
@@ -0,0 +1,2 @@
+ALTER TABLE sessions DROP COLUMN organization_id;
+ALTER TABLE identities DROP COLUMN organization_id;
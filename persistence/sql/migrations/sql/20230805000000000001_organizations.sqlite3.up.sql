@@ -0,0 +1,2 @@
+ALTER TABLE identities ADD COLUMN organization_id CHAR(36) NULL;
+ALTER TABLE sessions ADD COLUMN organization_id CHAR(36) NULL;
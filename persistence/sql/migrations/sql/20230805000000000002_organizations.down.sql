@@ -0,0 +1 @@
+DROP INDEX IF EXISTS identities_nid_organization_id_idx;
@@ -0,0 +1 @@
+CREATE INDEX identities_nid_organization_id_idx ON identities (nid, organization_id);
@@ -0,0 +1,21 @@
+CREATE TABLE outbox_events (
+    id UUID NOT NULL PRIMARY KEY,
+    nid UUID NOT NULL,
+    type VARCHAR(64) NOT NULL,
+    -- No foreign key so that events outlive the identity, e.g. IdentityDeleted
+    identity_id UUID NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at timestamp NOT NULL,
+    delivered_at timestamp NULL,
+    sequence BIGINT NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    CONSTRAINT outbox_events_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE INDEX outbox_events_nid_status_next_attempt_at_idx ON outbox_events (nid, status, next_attempt_at);
+CREATE INDEX outbox_events_nid_identity_id_sequence_idx ON outbox_events (nid, identity_id, sequence);
+CREATE INDEX outbox_events_nid_created_at_id_idx ON outbox_events (nid, created_at DESC, id);
@@ -0,0 +1,18 @@
+CREATE TABLE audit_events (
+    id UUID NOT NULL PRIMARY KEY,
+    nid UUID NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    actor_type VARCHAR(16) NOT NULL,
+    actor_id VARCHAR(255) NOT NULL DEFAULT '',
+    target_type VARCHAR(16) NOT NULL,
+    -- No foreign key so that the audit log outlives the target, e.g. after identity.deleted
+    target_id UUID NOT NULL,
+    changed_paths JSON NOT NULL,
+    client_ip VARCHAR(64) NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL,
+    CONSTRAINT audit_events_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE INDEX audit_events_nid_created_at_id_idx ON audit_events (nid, created_at DESC, id);
+CREATE INDEX audit_events_nid_target_id_idx ON audit_events (nid, target_id);
+CREATE INDEX audit_events_nid_actor_id_idx ON audit_events (nid, actor_id);
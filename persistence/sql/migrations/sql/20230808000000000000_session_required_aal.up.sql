@@ -0,0 +1 @@
+ALTER TABLE sessions ADD COLUMN required_aal VARCHAR(4) NOT NULL DEFAULT '';
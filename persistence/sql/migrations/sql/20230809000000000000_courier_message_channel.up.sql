@@ -0,0 +1 @@
+ALTER TABLE courier_messages ADD COLUMN channel VARCHAR(255) NOT NULL DEFAULT '';
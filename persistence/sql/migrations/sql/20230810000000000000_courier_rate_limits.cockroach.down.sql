@@ -0,0 +1,4 @@
+-- Downsizing is not yet supported in CockroachDB. Since the wider dispatch status column has no real-world impact on the
+-- application, we only remove the throttled dispatches.
+DELETE FROM courier_message_dispatches WHERE status = 'throttled';
+ALTER TABLE courier_messages DROP COLUMN throttled_until;
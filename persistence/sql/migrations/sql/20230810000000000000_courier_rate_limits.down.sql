@@ -0,0 +1,3 @@
+DELETE FROM courier_message_dispatches WHERE status = 'throttled';
+ALTER TABLE courier_message_dispatches ALTER COLUMN status TYPE VARCHAR(7);
+ALTER TABLE courier_messages DROP COLUMN throttled_until;
@@ -0,0 +1,2 @@
+DELETE FROM courier_message_dispatches WHERE status = 'throttled';
+ALTER TABLE courier_messages DROP COLUMN throttled_until;
@@ -0,0 +1,2 @@
+-- SQLite does not enforce the length of VARCHAR columns, so the dispatch status column does not need to be widened.
+ALTER TABLE courier_messages ADD COLUMN throttled_until timestamp NULL;
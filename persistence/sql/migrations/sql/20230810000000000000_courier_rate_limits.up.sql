@@ -0,0 +1,2 @@
+ALTER TABLE courier_messages ADD COLUMN throttled_until timestamp NULL;
+ALTER TABLE courier_message_dispatches ALTER COLUMN status TYPE VARCHAR(16);
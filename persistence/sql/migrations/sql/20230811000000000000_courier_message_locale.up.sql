@@ -0,0 +1 @@
+ALTER TABLE courier_messages ADD COLUMN locale VARCHAR(255) NOT NULL DEFAULT '';
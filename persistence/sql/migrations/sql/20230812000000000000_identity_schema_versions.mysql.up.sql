@@ -0,0 +1,16 @@
+CREATE TABLE identity_schema_versions (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    nid CHAR(36) NOT NULL,
+    -- The ID identities reference the version with, for example customer@v2
+    schema_id VARCHAR(128) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    version INTEGER NOT NULL,
+    state VARCHAR(16) NOT NULL,
+    json_schema JSON NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT identity_schema_versions_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX identity_schema_versions_nid_schema_id_uq_idx ON identity_schema_versions (nid, schema_id);
+CREATE UNIQUE INDEX identity_schema_versions_nid_name_version_uq_idx ON identity_schema_versions (nid, name, version);
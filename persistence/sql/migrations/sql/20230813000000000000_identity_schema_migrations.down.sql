@@ -0,0 +1,2 @@
+DROP TABLE identity_schema_migration_failures;
+DROP TABLE identity_schema_migrations;
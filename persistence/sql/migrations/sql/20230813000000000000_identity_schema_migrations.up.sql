@@ -0,0 +1,34 @@
+CREATE TABLE identity_schema_migrations (
+    id UUID NOT NULL PRIMARY KEY,
+    nid UUID NOT NULL,
+    source_schema_id VARCHAR(2048) NOT NULL,
+    target_schema_id VARCHAR(2048) NOT NULL,
+    transform TEXT NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT false,
+    batch_size INTEGER NOT NULL,
+    state VARCHAR(16) NOT NULL,
+    last_identity_id UUID NOT NULL,
+    processed INTEGER NOT NULL DEFAULT 0,
+    migrated INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    CONSTRAINT identity_schema_migrations_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE INDEX identity_schema_migrations_nid_idx ON identity_schema_migrations (nid);
+
+CREATE TABLE identity_schema_migration_failures (
+    id UUID NOT NULL PRIMARY KEY,
+    nid UUID NOT NULL,
+    migration_id UUID NOT NULL,
+    identity_id UUID NOT NULL,
+    reason TEXT NOT NULL,
+    created_at timestamp NOT NULL,
+    CONSTRAINT identity_schema_migration_failures_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE,
+    CONSTRAINT identity_schema_migration_failures_migration_id_fk FOREIGN KEY (migration_id) REFERENCES identity_schema_migrations (id) ON UPDATE RESTRICT ON DELETE CASCADE,
+    CONSTRAINT identity_schema_migration_failures_identity_id_fk FOREIGN KEY (identity_id) REFERENCES identities (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE INDEX identity_schema_migration_failures_nid_migration_id_idx ON identity_schema_migration_failures (nid, migration_id, created_at);
@@ -0,0 +1,4 @@
+ALTER TABLE identities DROP COLUMN delete_after;
+ALTER TABLE identities DROP COLUMN suspended_until;
+ALTER TABLE identities DROP COLUMN state_note;
+ALTER TABLE identities DROP COLUMN state_reason;
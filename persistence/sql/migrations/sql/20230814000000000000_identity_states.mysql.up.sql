@@ -0,0 +1,4 @@
+ALTER TABLE identities ADD COLUMN state_reason VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE identities ADD COLUMN state_note VARCHAR(1024) NOT NULL DEFAULT '';
+ALTER TABLE identities ADD COLUMN suspended_until DATETIME NULL;
+ALTER TABLE identities ADD COLUMN delete_after DATETIME NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS identities_nid_delete_after_idx;
+DROP INDEX IF EXISTS identities_nid_suspended_until_idx;
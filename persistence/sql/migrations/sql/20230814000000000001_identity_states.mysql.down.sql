@@ -0,0 +1,2 @@
+DROP INDEX identities_nid_delete_after_idx ON identities;
+DROP INDEX identities_nid_suspended_until_idx ON identities;
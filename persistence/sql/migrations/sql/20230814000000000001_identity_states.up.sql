@@ -0,0 +1,2 @@
+CREATE INDEX identities_nid_suspended_until_idx ON identities (nid, suspended_until);
+CREATE INDEX identities_nid_delete_after_idx ON identities (nid, delete_after);
@@ -0,0 +1 @@
+DROP TABLE selfservice_saml_used_assertions;
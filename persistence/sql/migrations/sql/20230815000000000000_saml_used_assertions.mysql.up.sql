@@ -0,0 +1,13 @@
+CREATE TABLE selfservice_saml_used_assertions (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    nid CHAR(36) NOT NULL,
+    -- HMACed value of the issuer and the assertion ID
+    assertion_hmac VARCHAR(64) NOT NULL,
+    expires_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT selfservice_saml_used_assertions_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX selfservice_saml_used_assertions_nid_assertion_hmac_uq_idx ON selfservice_saml_used_assertions (nid, assertion_hmac);
+CREATE INDEX selfservice_saml_used_assertions_nid_expires_at_idx ON selfservice_saml_used_assertions (nid, expires_at);
@@ -0,0 +1,14 @@
+-- The last sequence assigned to an event of an identity. Updating the row locks it, which orders
+-- the events of concurrent transactions by the time they commit.
+CREATE TABLE outbox_sequences (
+    nid UUID NOT NULL,
+    identity_id UUID NOT NULL,
+    last_sequence BIGINT NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY (nid, identity_id),
+    CONSTRAINT outbox_sequences_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+INSERT INTO outbox_sequences (nid, identity_id, last_sequence, created_at, updated_at)
+SELECT nid, identity_id, MAX(sequence), CURRENT_TIMESTAMP, CURRENT_TIMESTAMP FROM outbox_events GROUP BY nid, identity_id;
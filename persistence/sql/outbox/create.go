@@ -0,0 +1,90 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/ory/x/sqlcon"
+
+	"github.com/ory/kratos/outbox"
+	"github.com/ory/kratos/persistence/sql/batch"
+)
+
+// Create assigns the next sequences of their identities to the events and writes them to the
+// outbox. The events must have their NID set. Call it within the transaction of the change the
+// events describe.
+func Create(ctx context.Context, c *batch.TracerConnection, es []*outbox.Event) error {
+	if len(es) == 0 {
+		return nil
+	}
+
+	// Identities are sorted so that concurrent transactions lock their sequences in the same
+	// order and do not deadlock.
+	type key struct{ nid, identityID uuid.UUID }
+	counts := map[key]int64{}
+	keys := make([]key, 0, len(es))
+	for _, e := range es {
+		k := key{e.NID, e.IdentityID}
+		if counts[k] == 0 {
+			keys = append(keys, k)
+		}
+		counts[k]++
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if c := bytes.Compare(keys[i].nid.Bytes(), keys[j].nid.Bytes()); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(keys[i].identityID.Bytes(), keys[j].identityID.Bytes()) < 0
+	})
+
+	next := make(map[key]int64, len(keys))
+	for _, k := range keys {
+		last, err := incrementSequence(ctx, c, k.nid, k.identityID, counts[k])
+		if err != nil {
+			return err
+		}
+		next[k] = last - counts[k] + 1
+	}
+
+	for _, e := range es {
+		k := key{e.NID, e.IdentityID}
+		e.Sequence = next[k]
+		next[k]++
+	}
+
+	return batch.Create(ctx, c, es)
+}
+
+// incrementSequence increments the identity's event sequence by n and returns the new last
+// sequence. The row stays locked until the transaction ends, so that events written by concurrent
+// transactions are ordered by the time the transactions commit.
+func incrementSequence(ctx context.Context, c *batch.TracerConnection, nid, identityID uuid.UUID, n int64) (int64, error) {
+	now := time.Now().UTC()
+	tx := c.Connection
+
+	upsert := "ON CONFLICT (nid, identity_id) DO UPDATE SET last_sequence = outbox_sequences.last_sequence + excluded.last_sequence, updated_at = excluded.updated_at"
+	if tx.Dialect.Name() == "mysql" {
+		upsert = "ON DUPLICATE KEY UPDATE last_sequence = last_sequence + VALUES(last_sequence), updated_at = VALUES(updated_at)"
+	}
+
+	//#nosec G201 -- upsert is static
+	if err := tx.RawQuery(
+		"INSERT INTO outbox_sequences (nid, identity_id, last_sequence, created_at, updated_at) VALUES (?, ?, ?, ?, ?) "+upsert,
+		nid, identityID, n, now, now,
+	).Exec(); err != nil {
+		return 0, sqlcon.HandleError(err)
+	}
+
+	var last int64
+	if err := tx.RawQuery("SELECT last_sequence FROM outbox_sequences WHERE nid = ? AND identity_id = ?", nid, identityID).First(&last); err != nil {
+		return 0, sqlcon.HandleError(err)
+	}
+	return last, nil
+}
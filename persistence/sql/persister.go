@@ -39,6 +39,7 @@ type (
 		x.TracingProvider
 		schema.IdentityTraitsProvider
 		identity.ValidationProvider
+		identity.CacheInvalidatorProvider
 	}
 	Persister struct {
 		nid uuid.UUID
@@ -198,6 +199,12 @@ func (p *Persister) CleanupDatabase(ctx context.Context, wait time.Duration, old
 	}
 	time.Sleep(wait)
 
+	p.r.Logger().Println("Cleaning up used SAML assertions")
+	if err := p.DeleteExpiredSAMLAssertions(ctx, currentTime, batchSize); err != nil {
+		return err
+	}
+	time.Sleep(wait)
+
 	p.r.Logger().Println("Successfully cleaned up the latest batch of the SQL database! " +
 		"This should be re-run periodically, to be sure that all expired data is purged.")
 	return nil
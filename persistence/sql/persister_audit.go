@@ -0,0 +1,69 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/pagination/keysetpagination"
+	"github.com/ory/x/sqlcon"
+
+	"github.com/ory/kratos/audit"
+)
+
+var _ audit.Persister = new(Persister)
+
+func (p *Persister) CreateAuditEvent(ctx context.Context, e *audit.Event) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateAuditEvent")
+	defer otelx.End(span, &err)
+
+	e.NID = p.NetworkID(ctx)
+	return sqlcon.HandleError(p.GetConnection(ctx).Create(e))
+}
+
+func (p *Persister) ListAuditEvents(ctx context.Context, filter audit.ListEventsParameters, opts []keysetpagination.Option) (_ []audit.Event, _ int64, _ *keysetpagination.Paginator, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListAuditEvents")
+	defer otelx.End(span, &err)
+
+	q := p.GetConnection(ctx).Where("nid = ?", p.NetworkID(ctx))
+
+	if filter.Action != "" {
+		q = q.Where("action = ?", filter.Action)
+	}
+
+	if filter.ActorID != "" {
+		q = q.Where("actor_id = ?", filter.ActorID)
+	}
+
+	if filter.TargetID != nil {
+		q = q.Where("target_id = ?", *filter.TargetID)
+	}
+
+	if filter.Since != nil {
+		q = q.Where("created_at >= ?", *filter.Since)
+	}
+
+	if filter.Until != nil {
+		q = q.Where("created_at <= ?", *filter.Until)
+	}
+
+	count, err := q.Count(new(audit.Event))
+	if err != nil {
+		return nil, 0, nil, sqlcon.HandleError(err)
+	}
+
+	opts = append(opts, keysetpagination.WithDefaultToken(new(audit.Event).DefaultPageToken()))
+	opts = append(opts, keysetpagination.WithDefaultSize(10))
+	opts = append(opts, keysetpagination.WithColumn("created_at", "DESC"))
+	paginator := keysetpagination.GetPaginator(opts...)
+
+	es := make([]audit.Event, paginator.Size())
+	if err := q.Scope(keysetpagination.Paginate[audit.Event](paginator)).All(&es); err != nil {
+		return nil, 0, nil, sqlcon.HandleError(err)
+	}
+
+	es, nextPage := keysetpagination.Result(es, paginator)
+	return es, int64(count), nextPage, nil
+}
@@ -0,0 +1,111 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
+
+	"github.com/ory/kratos/bruteforce"
+)
+
+var _ bruteforce.Persister = new(Persister)
+
+func (p *Persister) GetLoginThrottle(ctx context.Context, kt bruteforce.KeyType, key string) (*bruteforce.LoginThrottle, error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetLoginThrottle")
+	defer span.End()
+
+	var t bruteforce.LoginThrottle
+	if err := p.GetConnection(ctx).Where("nid = ? AND key_type = ? AND key_hmac = ?", p.NetworkID(ctx), kt, p.hmacValue(ctx, key)).First(&t); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return &t, nil
+}
+
+func (p *Persister) IncrementLoginThrottle(ctx context.Context, kt bruteforce.KeyType, key string, resetBefore time.Time) (*bruteforce.LoginThrottle, error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.IncrementLoginThrottle")
+	defer span.End()
+
+	nid := p.NetworkID(ctx)
+	keyHMAC := p.hmacValue(ctx, key)
+
+	var t bruteforce.LoginThrottle
+	if err := p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		now := time.Now().UTC()
+		if err := sqlcon.HandleError(tx.Where("nid = ? AND key_type = ? AND key_hmac = ?", nid, kt, keyHMAC).First(&t)); errors.Is(err, sqlcon.ErrNoRows) {
+			t = bruteforce.LoginThrottle{
+				NID:            nid,
+				KeyType:        kt,
+				KeyHMAC:        keyHMAC,
+				FailedAttempts: 1,
+				LastFailedAt:   now,
+			}
+			return sqlcon.HandleError(tx.Create(&t))
+		} else if err != nil {
+			return err
+		}
+
+		// Incrementing in the database prevents losing attempts which are made in parallel.
+		increment := "failed_attempts + 1"
+		if t.LastFailedAt.Before(resetBefore) {
+			increment = "1"
+		}
+
+		//#nosec G201 -- TableName and increment are static
+		if err := tx.RawQuery(
+			fmt.Sprintf("UPDATE %s SET failed_attempts = %s, last_failed_at = ?, updated_at = ? WHERE id = ? AND nid = ?", t.TableName(ctx), increment),
+			now, now, t.ID, nid,
+		).Exec(); err != nil {
+			return sqlcon.HandleError(err)
+		}
+
+		return sqlcon.HandleError(tx.Where("id = ? AND nid = ?", t.ID, nid).First(&t))
+	}); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (p *Persister) LockLoginThrottle(ctx context.Context, id uuid.UUID, until time.Time) error {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.LockLoginThrottle")
+	defer span.End()
+
+	//#nosec G201 -- TableName is static
+	count, err := p.GetConnection(ctx).RawQuery(
+		fmt.Sprintf("UPDATE %s SET failed_attempts = 0, locked_until = ?, updated_at = ? WHERE id = ? AND nid = ?", new(bruteforce.LoginThrottle).TableName(ctx)),
+		sqlxx.NullTime(until.UTC()), time.Now().UTC(), id, p.NetworkID(ctx),
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	} else if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows)
+	}
+	return nil
+}
+
+func (p *Persister) DeleteLoginThrottle(ctx context.Context, kt bruteforce.KeyType, key string) error {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteLoginThrottle")
+	defer span.End()
+
+	//#nosec G201 -- TableName is static
+	count, err := p.GetConnection(ctx).RawQuery(
+		fmt.Sprintf("DELETE FROM %s WHERE nid = ? AND key_type = ? AND key_hmac = ?", new(bruteforce.LoginThrottle).TableName(ctx)),
+		p.NetworkID(ctx), kt, p.hmacValue(ctx, key),
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	} else if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/sqlcon"
+
+	"github.com/ory/kratos/selfservice/strategy/code"
+)
+
+type oneTimeCode interface {
+	TableName(context.Context) string
+	GetHMACCode() string
+	GetID() uuid.UUID
+	Validate() error
+}
+
+// useOneTimeCode looks up the code of the given flow matching the user-provided code, marks it as used and returns it.
+//
+// The flow's submit count is increased on every call. If the flow has been submitted more than 5 times,
+// `code.ErrCodeSubmittedTooOften` is returned regardless of whether the code was correct or not.
+// Only codes for which `match` returns true are considered.
+func useOneTimeCode[P any, U interface {
+	*P
+	oneTimeCode
+}](ctx context.Context, p *Persister, flowID uuid.UUID, userProvidedCode, flowTableName, foreignKeyName string, match func(U) bool) (U, error) {
+	var target U
+	nid := p.NetworkID(ctx)
+
+	if err := sqlcon.HandleError(p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		if err := sqlcon.HandleError(
+			tx.RawQuery(
+				//#nosec G201 -- TableName is static
+				fmt.Sprintf("UPDATE %s SET submit_count = submit_count + 1 WHERE id = ? AND nid = ?", flowTableName),
+				flowID,
+				nid,
+			).Exec(),
+		); err != nil {
+			return err
+		}
+
+		var submitCount int
+		// Because MySQL does not support "RETURNING" clauses, but we need the updated `submit_count` later on.
+		if err := sqlcon.HandleError(
+			tx.RawQuery(
+				//#nosec G201 -- TableName is static
+				fmt.Sprintf("SELECT submit_count FROM %s WHERE id = ? AND nid = ?", flowTableName),
+				flowID,
+				nid,
+			).First(&submitCount),
+		); err != nil {
+			if errors.Is(err, sqlcon.ErrNoRows) {
+				// Return no error, as that would roll back the transaction
+				return nil
+			}
+			return err
+		}
+
+		// This check prevents parallel brute force attacks by checking the submit count inside this database
+		// transaction. If the flow has been submitted more than 5 times, the transaction is aborted (regardless of
+		// whether the code was correct or not) and we thus give no indication whether the supplied code was correct.
+		if submitCount > 5 {
+			return errors.WithStack(code.ErrCodeSubmittedTooOften)
+		}
+
+		var codes []P
+		if err := sqlcon.HandleError(
+			tx.Where(fmt.Sprintf("nid = ? AND %s = ?", foreignKeyName), nid, flowID).All(&codes),
+		); err != nil {
+			if errors.Is(err, sqlcon.ErrNoRows) {
+				// Return no error, as that would roll back the transaction
+				return nil
+			}
+			return err
+		}
+
+	secrets:
+		for _, secret := range p.r.Config().SecretsSession(ctx) {
+			suppliedCode := []byte(p.hmacValueWithSecret(ctx, userProvidedCode, secret))
+			for i := range codes {
+				c := U(&codes[i])
+				if subtle.ConstantTimeCompare([]byte(c.GetHMACCode()), suppliedCode) == 0 {
+					// Not the supplied code
+					continue
+				}
+				if !match(c) {
+					continue
+				}
+				target = c
+				break secrets
+			}
+		}
+
+		if target == nil || target.Validate() != nil {
+			// Return no error, as that would roll back the transaction
+			return nil
+		}
+
+		//#nosec G201 -- TableName is static
+		return tx.
+			RawQuery(
+				fmt.Sprintf("UPDATE %s SET used_at = ? WHERE id = ? AND nid = ?", target.TableName(ctx)),
+				time.Now().UTC(),
+				target.GetID(),
+				nid,
+			).Exec()
+	})); err != nil {
+		return nil, err
+	}
+
+	if target == nil {
+		return nil, errors.WithStack(code.ErrCodeNotFound)
+	}
+
+	if err := target.Validate(); err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
@@ -7,6 +7,8 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"strings"
+	"time"
 
 	"github.com/gobuffalo/pop/v6"
 	"github.com/gofrs/uuid"
@@ -73,9 +75,10 @@ func (p *Persister) NextMessages(ctx context.Context, limit uint8) (messages []c
 	if err := p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
 		var m []courier.Message
 		if err := tx.
-			Where("nid = ? AND status = ?",
+			Where("nid = ? AND status = ? AND (throttled_until IS NULL OR throttled_until <= ?)",
 				p.NetworkID(ctx),
 				courier.MessageStatusQueued,
+				time.Now().UTC(),
 			).
 			Order("created_at ASC").
 			Limit(int(limit)).
@@ -216,3 +219,163 @@ func (p *Persister) RecordDispatch(ctx context.Context, msgID uuid.UUID, status
 
 	return nil
 }
+
+func (p *Persister) ListDeadLetters(ctx context.Context, filter courier.ListCourierDeadLettersParameters, opts []keysetpagination.Option) ([]courier.Message, int64, *keysetpagination.Paginator, error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListDeadLetters")
+	defer span.End()
+
+	q := p.GetConnection(ctx).Where("nid=? AND status=?", p.NetworkID(ctx), courier.MessageStatusAbandoned)
+
+	if filter.Recipient != "" {
+		q = q.Where("recipient=?", filter.Recipient)
+	}
+
+	count, err := q.Count(&courier.Message{})
+	if err != nil {
+		return nil, 0, nil, sqlcon.HandleError(err)
+	}
+
+	opts = append(opts, keysetpagination.WithDefaultToken(new(courier.Message).DefaultPageToken()))
+	opts = append(opts, keysetpagination.WithDefaultSize(10))
+	opts = append(opts, keysetpagination.WithColumn("created_at", "DESC"))
+	paginator := keysetpagination.GetPaginator(opts...)
+
+	messages := make([]courier.Message, paginator.Size())
+	if err := q.Scope(keysetpagination.Paginate[courier.Message](paginator)).
+		Eager("Dispatches").
+		All(&messages); err != nil {
+		return nil, 0, nil, sqlcon.HandleError(err)
+	}
+
+	messages, nextPage := keysetpagination.Result(messages, paginator)
+	return messages, int64(count), nextPage, nil
+}
+
+func (p *Persister) RequeueMessages(ctx context.Context, filter courier.DeadLetterFilter) (int, error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.RequeueMessages")
+	defer span.End()
+
+	query := "UPDATE courier_messages SET status = ?, send_count = 0, throttled_until = NULL WHERE nid = ? AND status = ?"
+	args := []interface{}{courier.MessageStatusQueued, p.NetworkID(ctx), courier.MessageStatusAbandoned}
+
+	if len(filter.IDs) > 0 {
+		query += " AND id IN (?" + strings.Repeat(", ?", len(filter.IDs)-1) + ")"
+		for _, id := range filter.IDs {
+			args = append(args, id)
+		}
+	}
+	if filter.Recipient != "" {
+		query += " AND recipient = ?"
+		args = append(args, filter.Recipient)
+	}
+	if filter.TemplateType != "" {
+		query += " AND template_type = ?"
+		args = append(args, filter.TemplateType)
+	}
+
+	count, err := p.GetConnection(ctx).RawQuery(query, args...).ExecWithCount()
+	if err != nil {
+		return 0, sqlcon.HandleError(err)
+	}
+
+	return count, nil
+}
+
+func (p *Persister) CancelMessage(ctx context.Context, id uuid.UUID) error {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CancelMessage")
+	defer span.End()
+
+	count, err := p.GetConnection(ctx).RawQuery(
+		"UPDATE courier_messages SET status = ? WHERE id = ? AND nid = ? AND status = ?",
+		courier.MessageStatusCancelled,
+		id,
+		p.NetworkID(ctx),
+		courier.MessageStatusQueued,
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+
+	if count == 0 {
+		exists, err := p.GetConnection(ctx).Where("id = ? AND nid = ?", id, p.NetworkID(ctx)).Exists(new(courier.Message))
+		if err != nil {
+			return sqlcon.HandleError(err)
+		} else if !exists {
+			return errors.WithStack(sqlcon.ErrNoRows)
+		}
+		return errors.WithStack(courier.ErrMessageNotQueued)
+	}
+
+	return nil
+}
+
+func (p *Persister) PurgeMessages(ctx context.Context, createdBefore time.Time, statuses []courier.MessageStatus) (int, error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.PurgeMessages")
+	defer span.End()
+
+	if len(statuses) == 0 {
+		return 0, nil
+	}
+
+	args := []interface{}{p.NetworkID(ctx), createdBefore.UTC()}
+	for _, status := range statuses {
+		args = append(args, status)
+	}
+
+	// The dispatches of the messages are deleted by the foreign key cascade.
+	count, err := p.GetConnection(ctx).RawQuery(
+		"DELETE FROM courier_messages WHERE nid = ? AND created_at < ? AND status IN (?"+strings.Repeat(", ?", len(statuses)-1)+")",
+		args...,
+	).ExecWithCount()
+	if err != nil {
+		return 0, sqlcon.HandleError(err)
+	}
+
+	return count, nil
+}
+
+func (p *Persister) ThrottleMessage(ctx context.Context, id uuid.UUID, until time.Time) error {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ThrottleMessage")
+	defer span.End()
+
+	count, err := p.GetConnection(ctx).RawQuery(
+		"UPDATE courier_messages SET status = ?, throttled_until = ? WHERE id = ? AND nid = ?",
+		courier.MessageStatusQueued,
+		until.UTC(),
+		id,
+		p.NetworkID(ctx),
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	} else if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (p *Persister) RecentRecipientDispatches(ctx context.Context, recipient string, since time.Time, limit int) ([]time.Time, error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.RecentRecipientDispatches")
+	defer span.End()
+
+	var dispatches []courier.MessageDispatch
+	if err := p.GetConnection(ctx).RawQuery(
+		"SELECT d.* FROM courier_message_dispatches d JOIN courier_messages m ON m.id = d.message_id "+
+			"WHERE d.nid = ? AND m.nid = ? AND m.recipient = ? AND d.status = ? AND d.created_at > ? "+
+			"ORDER BY d.created_at DESC LIMIT ?",
+		p.NetworkID(ctx),
+		p.NetworkID(ctx),
+		recipient,
+		courier.CourierMessageDispatchStatusSuccess,
+		since.UTC(),
+		limit,
+	).All(&dispatches); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	times := make([]time.Time, len(dispatches))
+	for i, d := range dispatches {
+		times[i] = d.CreatedAt
+	}
+	return times, nil
+}
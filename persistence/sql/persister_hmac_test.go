@@ -56,10 +56,18 @@ func (l *logRegistryOnly) IdentityTraitsSchemas(ctx context.Context) (schema.Sch
 	panic("implement me")
 }
 
+func (l *logRegistryOnly) IdentityTraitsSchema(ctx context.Context, id string) (*schema.Schema, error) {
+	panic("implement me")
+}
+
 func (l *logRegistryOnly) IdentityValidator() *identity.Validator {
 	panic("implement me")
 }
 
+func (l *logRegistryOnly) IdentityCacheInvalidator() identity.CacheInvalidator {
+	return identity.NoopCacheInvalidator{}
+}
+
 var _ persisterDependencies = &logRegistryOnly{}
 
 func TestPersisterHMAC(t *testing.T) {
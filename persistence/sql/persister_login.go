@@ -16,6 +16,7 @@ import (
 
 	"github.com/ory/kratos/persistence/sql/update"
 	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/selfservice/strategy/code"
 )
 
 var _ login.FlowPersister = new(Persister)
@@ -84,3 +85,47 @@ func (p *Persister) DeleteExpiredLoginFlows(ctx context.Context, expiresAt time.
 	}
 	return nil
 }
+
+func (p *Persister) CreateLoginCode(ctx context.Context, c *code.CreateLoginCodeParams) (*code.LoginCode, error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateLoginCode")
+	defer span.End()
+
+	now := time.Now().UTC()
+	loginCode := &code.LoginCode{
+		CodeHMAC:    p.hmacValue(ctx, c.RawCode),
+		Address:     c.Address,
+		AddressType: c.AddressType,
+		ExpiresAt:   now.Add(c.ExpiresIn),
+		IssuedAt:    now,
+		FlowID:      c.FlowID,
+		IdentityID:  c.IdentityID,
+		NID:         p.NetworkID(ctx),
+	}
+
+	if err := p.GetConnection(ctx).Create(loginCode); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return loginCode, nil
+}
+
+func (p *Persister) UseLoginCode(ctx context.Context, fID uuid.UUID, identityID uuid.UUID, codeVal string) (*code.LoginCode, error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UseLoginCode")
+	defer span.End()
+
+	return useOneTimeCode[code.LoginCode, *code.LoginCode](ctx, p, fID, codeVal, new(login.Flow).TableName(ctx), "selfservice_login_flow_id", func(c *code.LoginCode) bool {
+		return c.IdentityID == identityID
+	})
+}
+
+func (p *Persister) DeleteLoginCodesOfFlow(ctx context.Context, fID uuid.UUID) error {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteLoginCodesOfFlow")
+	defer span.End()
+
+	//#nosec G201 -- TableName is static
+	return p.GetConnection(ctx).
+		RawQuery(
+			fmt.Sprintf("DELETE FROM %s WHERE selfservice_login_flow_id = ? AND nid = ?", new(code.LoginCode).TableName(ctx)),
+			fID,
+			p.NetworkID(ctx),
+		).Exec()
+}
@@ -0,0 +1,188 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
+	"github.com/ory/kratos/session"
+)
+
+var _ organization.Persister = new(Persister)
+
+func (p *Persister) CreateOrganization(ctx context.Context, o *organization.Organization) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateOrganization")
+	defer otelx.End(span, &err)
+
+	o.NID = p.NetworkID(ctx)
+	return p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		if err := tx.Create(o); err != nil {
+			return sqlcon.HandleError(err)
+		}
+		return p.createOrganizationDomains(ctx, tx, o)
+	})
+}
+
+func (p *Persister) GetOrganization(ctx context.Context, id uuid.UUID) (_ *organization.Organization, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetOrganization")
+	defer otelx.End(span, &err)
+
+	var o organization.Organization
+	if err := p.GetConnection(ctx).Where("id = ? AND nid = ?", id, p.NetworkID(ctx)).First(&o); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	if err := p.hydrateOrganizationDomains(ctx, &o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (p *Persister) ListOrganizations(ctx context.Context, page, itemsPerPage int) (_ []organization.Organization, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListOrganizations")
+	defer otelx.End(span, &err)
+
+	os := make([]organization.Organization, 0)
+	if err := p.GetConnection(ctx).Where("nid = ?", p.NetworkID(ctx)).
+		Order("id ASC").Paginate(page, itemsPerPage).All(&os); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	for k := range os {
+		if err := p.hydrateOrganizationDomains(ctx, &os[k]); err != nil {
+			return nil, err
+		}
+	}
+	return os, nil
+}
+
+func (p *Persister) CountOrganizations(ctx context.Context) (n int64, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CountOrganizations")
+	defer otelx.End(span, &err)
+
+	count, err := p.GetConnection(ctx).Where("nid = ?", p.NetworkID(ctx)).Count(new(organization.Organization))
+	if err != nil {
+		return 0, sqlcon.HandleError(err)
+	}
+	return int64(count), nil
+}
+
+func (p *Persister) UpdateOrganization(ctx context.Context, o *organization.Organization) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UpdateOrganization")
+	defer otelx.End(span, &err)
+
+	o.NID = p.NetworkID(ctx)
+	return p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		if count, err := tx.Where("id = ? AND nid = ?", o.ID, o.NID).Count(o); err != nil {
+			return sqlcon.HandleError(err)
+		} else if count == 0 {
+			return errors.WithStack(sqlcon.ErrNoRows)
+		}
+
+		if err := tx.Update(o, "nid", "created_at"); err != nil {
+			return sqlcon.HandleError(err)
+		}
+
+		//#nosec G201 -- TableName is static
+		if err := tx.RawQuery(
+			fmt.Sprintf("DELETE FROM %s WHERE organization_id = ? AND nid = ?", new(organization.Domain).TableName(ctx)),
+			o.ID, o.NID,
+		).Exec(); err != nil {
+			return sqlcon.HandleError(err)
+		}
+
+		return p.createOrganizationDomains(ctx, tx, o)
+	})
+}
+
+func (p *Persister) DeleteOrganization(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteOrganization")
+	defer otelx.End(span, &err)
+
+	nid := p.NetworkID(ctx)
+	return p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		// Members are kept but no longer belong to the organization.
+		for _, table := range []string{
+			new(identity.Identity).TableName(ctx),
+			new(session.Session).TableName(ctx),
+		} {
+			//#nosec G201 -- TableName is static
+			if err := tx.RawQuery(
+				fmt.Sprintf("UPDATE %s SET organization_id = NULL WHERE organization_id = ? AND nid = ?", table),
+				id, nid,
+			).Exec(); err != nil {
+				return sqlcon.HandleError(err)
+			}
+		}
+
+		//#nosec G201 -- TableName is static
+		if err := tx.RawQuery(
+			fmt.Sprintf("DELETE FROM %s WHERE organization_id = ? AND nid = ?", new(organization.Domain).TableName(ctx)),
+			id, nid,
+		).Exec(); err != nil {
+			return sqlcon.HandleError(err)
+		}
+
+		//#nosec G201 -- TableName is static
+		count, err := tx.RawQuery(
+			fmt.Sprintf("DELETE FROM %s WHERE id = ? AND nid = ?", new(organization.Organization).TableName(ctx)),
+			id, nid,
+		).ExecWithCount()
+		if err != nil {
+			return sqlcon.HandleError(err)
+		} else if count == 0 {
+			return errors.WithStack(sqlcon.ErrNoRows)
+		}
+		return nil
+	})
+}
+
+func (p *Persister) FindOrganizationByDomain(ctx context.Context, domain string) (_ *organization.Organization, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.FindOrganizationByDomain")
+	defer otelx.End(span, &err)
+
+	var d organization.Domain
+	if err := p.GetConnection(ctx).Where("nid = ? AND domain = ?", p.NetworkID(ctx), organization.NormalizeDomain(domain)).First(&d); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	return p.GetOrganization(ctx, d.OrganizationID)
+}
+
+func (p *Persister) createOrganizationDomains(ctx context.Context, tx *pop.Connection, o *organization.Organization) error {
+	for _, domain := range o.Domains {
+		if err := tx.Create(&organization.Domain{
+			NID:            o.NID,
+			OrganizationID: o.ID,
+			Domain:         organization.NormalizeDomain(domain),
+		}); err != nil {
+			return sqlcon.HandleError(err)
+		}
+	}
+	return nil
+}
+
+func (p *Persister) hydrateOrganizationDomains(ctx context.Context, o *organization.Organization) error {
+	var ds []organization.Domain
+	if err := p.GetConnection(ctx).Where("organization_id = ? AND nid = ?", o.ID, p.NetworkID(ctx)).
+		Order("domain ASC").All(&ds); err != nil {
+		return sqlcon.HandleError(err)
+	}
+
+	o.Domains = make([]string, len(ds))
+	for k, d := range ds {
+		o.Domains[k] = d.Domain
+	}
+	return nil
+}
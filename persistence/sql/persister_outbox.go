@@ -0,0 +1,222 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/pagination/keysetpagination"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
+
+	"github.com/ory/kratos/outbox"
+	"github.com/ory/kratos/persistence/sql/batch"
+	outboxpersistence "github.com/ory/kratos/persistence/sql/outbox"
+)
+
+var _ outbox.Persister = new(Persister)
+
+// AddEvents writes the events to the outbox if the outbox is enabled. Call it within the
+// transaction of the change the events describe.
+func (p *Persister) AddEvents(ctx context.Context, es ...*outbox.Event) (err error) {
+	if len(es) == 0 || !p.r.Config().OutboxEnabled(ctx) {
+		return nil
+	}
+
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.AddEvents")
+	defer otelx.End(span, &err)
+
+	nid := p.NetworkID(ctx)
+	for _, e := range es {
+		e.NID = nid
+	}
+
+	return p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		return outboxpersistence.Create(ctx, &batch.TracerConnection{Tracer: p.r.Tracer(ctx), Connection: tx}, es)
+	})
+}
+
+func (p *Persister) NextEvents(ctx context.Context, limit uint8, lease time.Duration) (_ []outbox.Event, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.NextEvents")
+	defer otelx.End(span, &err)
+
+	var es []outbox.Event
+	if err := p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		table := new(outbox.Event).TableName(ctx)
+		nid := p.NetworkID(ctx)
+		now := time.Now().UTC()
+
+		// Events whose lease expired were not delivered by the dispatcher which claimed them and
+		// are due again.
+		//
+		//#nosec G201 -- TableName is static
+		if err := tx.RawQuery(fmt.Sprintf("UPDATE %s SET status = ?, updated_at = ? WHERE nid = ? AND status = ? AND next_attempt_at <= ?", table),
+			outbox.StatusPending, now, nid, outbox.StatusProcessing, now,
+		).Exec(); err != nil {
+			return sqlcon.HandleError(err)
+		}
+
+		// Only the oldest undelivered event of an identity is due, which ensures that the events
+		// of an identity are delivered in order.
+		var candidates []outbox.Event
+		//#nosec G201 -- TableName is static
+		if err := tx.RawQuery(fmt.Sprintf(`SELECT * FROM %[1]s e WHERE e.nid = ? AND e.status = ? AND e.next_attempt_at <= ?
+AND NOT EXISTS (SELECT 1 FROM %[1]s o WHERE o.nid = e.nid AND o.identity_id = e.identity_id AND o.status IN (?, ?) AND o.sequence < e.sequence)
+ORDER BY e.sequence ASC LIMIT ?`, table),
+			nid, outbox.StatusPending, now, outbox.StatusPending, outbox.StatusProcessing, int(limit),
+		).All(&candidates); err != nil {
+			return sqlcon.HandleError(err)
+		}
+
+		// The status guard skips events which another dispatcher claimed in the meantime.
+		leaseUntil := now.Add(lease)
+		for _, e := range candidates {
+			//#nosec G201 -- TableName is static
+			count, err := tx.RawQuery(fmt.Sprintf("UPDATE %s SET status = ?, next_attempt_at = ?, updated_at = ? WHERE id = ? AND nid = ? AND status = ?", table),
+				outbox.StatusProcessing, leaseUntil, now, e.ID, e.NID, outbox.StatusPending,
+			).ExecWithCount()
+			if err != nil {
+				return sqlcon.HandleError(err)
+			} else if count == 0 {
+				continue
+			}
+
+			e.Status = outbox.StatusProcessing
+			e.NextAttemptAt = leaseUntil
+			es = append(es, e)
+		}
+
+		if len(es) == 0 {
+			return errors.WithStack(sql.ErrNoRows)
+		}
+		return nil
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(outbox.ErrQueueEmpty)
+		}
+		return nil, err
+	}
+
+	return es, nil
+}
+
+func (p *Persister) SetEventDelivered(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.SetEventDelivered")
+	defer otelx.End(span, &err)
+
+	now := time.Now().UTC()
+	//#nosec G201 -- TableName is static
+	count, err := p.GetConnection(ctx).RawQuery(
+		fmt.Sprintf("UPDATE %s SET status = ?, attempts = attempts + 1, last_error = '', delivered_at = ?, updated_at = ? WHERE id = ? AND nid = ?", new(outbox.Event).TableName(ctx)),
+		outbox.StatusDelivered, sqlxx.NullTime(now), now, id, p.NetworkID(ctx),
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	} else if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows)
+	}
+	return nil
+}
+
+func (p *Persister) SetEventFailed(ctx context.Context, id uuid.UUID, deliveryErr error, nextAttemptAt time.Time, abandon bool) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.SetEventFailed")
+	defer otelx.End(span, &err)
+
+	status := outbox.StatusPending
+	if abandon {
+		status = outbox.StatusAbandoned
+	}
+
+	var lastError string
+	if deliveryErr != nil {
+		lastError = deliveryErr.Error()
+	}
+
+	//#nosec G201 -- TableName is static
+	count, err := p.GetConnection(ctx).RawQuery(
+		fmt.Sprintf("UPDATE %s SET status = ?, attempts = attempts + 1, last_error = ?, next_attempt_at = ?, updated_at = ? WHERE id = ? AND nid = ?", new(outbox.Event).TableName(ctx)),
+		status, lastError, nextAttemptAt.UTC(), time.Now().UTC(), id, p.NetworkID(ctx),
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	} else if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows)
+	}
+	return nil
+}
+
+func (p *Persister) ListEvents(ctx context.Context, filter outbox.ListEventsParameters, opts []keysetpagination.Option) (_ []outbox.Event, _ int64, _ *keysetpagination.Paginator, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListEvents")
+	defer otelx.End(span, &err)
+
+	q := p.GetConnection(ctx).Where("nid = ?", p.NetworkID(ctx))
+
+	if filter.Status != nil {
+		q = q.Where("status = ?", *filter.Status)
+	}
+
+	if filter.Type != "" {
+		q = q.Where("type = ?", filter.Type)
+	}
+
+	if filter.IdentityID != nil {
+		q = q.Where("identity_id = ?", *filter.IdentityID)
+	}
+
+	count, err := q.Count(new(outbox.Event))
+	if err != nil {
+		return nil, 0, nil, sqlcon.HandleError(err)
+	}
+
+	opts = append(opts, keysetpagination.WithDefaultToken(new(outbox.Event).DefaultPageToken()))
+	opts = append(opts, keysetpagination.WithDefaultSize(10))
+	opts = append(opts, keysetpagination.WithColumn("created_at", "DESC"))
+	paginator := keysetpagination.GetPaginator(opts...)
+
+	es := make([]outbox.Event, paginator.Size())
+	if err := q.Scope(keysetpagination.Paginate[outbox.Event](paginator)).All(&es); err != nil {
+		return nil, 0, nil, sqlcon.HandleError(err)
+	}
+
+	es, nextPage := keysetpagination.Result(es, paginator)
+	return es, int64(count), nextPage, nil
+}
+
+func (p *Persister) GetEvent(ctx context.Context, id uuid.UUID) (_ *outbox.Event, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetEvent")
+	defer otelx.End(span, &err)
+
+	var e outbox.Event
+	if err := p.GetConnection(ctx).Where("id = ? AND nid = ?", id, p.NetworkID(ctx)).First(&e); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return &e, nil
+}
+
+func (p *Persister) ReplayEvent(ctx context.Context, id uuid.UUID) (_ *outbox.Event, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ReplayEvent")
+	defer otelx.End(span, &err)
+
+	now := time.Now().UTC()
+	//#nosec G201 -- TableName is static
+	count, err := p.GetConnection(ctx).RawQuery(
+		fmt.Sprintf("UPDATE %s SET status = ?, attempts = 0, last_error = '', next_attempt_at = ?, delivered_at = NULL, updated_at = ? WHERE id = ? AND nid = ?", new(outbox.Event).TableName(ctx)),
+		outbox.StatusPending, now, now, id, p.NetworkID(ctx),
+	).ExecWithCount()
+	if err != nil {
+		return nil, sqlcon.HandleError(err)
+	} else if count == 0 {
+		return nil, errors.WithStack(sqlcon.ErrNoRows)
+	}
+
+	return p.GetEvent(ctx, id)
+}
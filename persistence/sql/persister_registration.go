@@ -14,6 +14,7 @@ import (
 
 	"github.com/ory/kratos/persistence/sql/update"
 	"github.com/ory/kratos/selfservice/flow/registration"
+	"github.com/ory/kratos/selfservice/strategy/code"
 )
 
 func (p *Persister) CreateRegistrationFlow(ctx context.Context, r *registration.Flow) error {
@@ -64,3 +65,51 @@ func (p *Persister) DeleteExpiredRegistrationFlows(ctx context.Context, expiresA
 	}
 	return nil
 }
+
+func (p *Persister) CreateRegistrationCode(ctx context.Context, c *code.CreateRegistrationCodeParams) (*code.RegistrationCode, error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateRegistrationCode")
+	defer span.End()
+
+	now := time.Now().UTC()
+	registrationCode := &code.RegistrationCode{
+		CodeHMAC:    p.hmacValue(ctx, c.RawCode),
+		Address:     c.Address,
+		AddressType: c.AddressType,
+		ExpiresAt:   now.Add(c.ExpiresIn),
+		IssuedAt:    now,
+		FlowID:      c.FlowID,
+		NID:         p.NetworkID(ctx),
+	}
+
+	if err := p.GetConnection(ctx).Create(registrationCode); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return registrationCode, nil
+}
+
+func (p *Persister) UseRegistrationCode(ctx context.Context, fID uuid.UUID, codeVal string, addresses ...string) (*code.RegistrationCode, error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UseRegistrationCode")
+	defer span.End()
+
+	return useOneTimeCode[code.RegistrationCode, *code.RegistrationCode](ctx, p, fID, codeVal, new(registration.Flow).TableName(ctx), "selfservice_registration_flow_id", func(c *code.RegistrationCode) bool {
+		for _, address := range addresses {
+			if c.Address == address {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (p *Persister) DeleteRegistrationCodesOfFlow(ctx context.Context, fID uuid.UUID) error {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteRegistrationCodesOfFlow")
+	defer span.End()
+
+	//#nosec G201 -- TableName is static
+	return p.GetConnection(ctx).
+		RawQuery(
+			fmt.Sprintf("DELETE FROM %s WHERE selfservice_registration_flow_id = ? AND nid = ?", new(code.RegistrationCode).TableName(ctx)),
+			fID,
+			p.NetworkID(ctx),
+		).Exec()
+}
@@ -0,0 +1,71 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/sqlcon"
+
+	"github.com/ory/kratos/selfservice/strategy/saml"
+)
+
+var _ saml.AssertionPersister = new(Persister)
+
+func (p *Persister) UseSAMLAssertion(ctx context.Context, issuer, assertionID string, expiresAt time.Time) error {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UseSAMLAssertion")
+	defer span.End()
+
+	nid := p.NetworkID(ctx)
+	assertionHMAC := p.hmacValue(ctx, issuer+"|"+assertionID)
+
+	return p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		// An expired record of the same assertion does not prevent its use, because the assertion itself is
+		// rejected once it expired.
+		//#nosec G201 -- TableName is static
+		if err := tx.RawQuery(
+			fmt.Sprintf("DELETE FROM %s WHERE nid = ? AND assertion_hmac = ? AND expires_at < ?", new(saml.UsedAssertion).TableName(ctx)),
+			nid, assertionHMAC, time.Now().UTC(),
+		).Exec(); err != nil {
+			return sqlcon.HandleError(err)
+		}
+
+		if err := sqlcon.HandleError(tx.Create(&saml.UsedAssertion{
+			NID:           nid,
+			AssertionHMAC: assertionHMAC,
+			ExpiresAt:     expiresAt.UTC(),
+		})); errors.Is(err, sqlcon.ErrUniqueViolation) {
+			return errors.WithStack(saml.ErrAssertionReplayed)
+		} else if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (p *Persister) DeleteExpiredSAMLAssertions(ctx context.Context, at time.Time, limit int) error {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteExpiredSAMLAssertions")
+	defer span.End()
+
+	conn := p.GetConnection(ctx)
+
+	//#nosec G201 -- TableName is static
+	err := conn.RawQuery(fmt.Sprintf(
+		"DELETE FROM %s WHERE id in (SELECT id FROM (SELECT id FROM %s c WHERE expires_at <= ? and nid = ? ORDER BY expires_at ASC LIMIT %d ) AS s )",
+		conn.Dialect.Quote(new(saml.UsedAssertion).TableName(ctx)),
+		conn.Dialect.Quote(new(saml.UsedAssertion).TableName(ctx)),
+		limit,
+	),
+		at,
+		p.NetworkID(ctx),
+	).Exec()
+
+	return sqlcon.HandleError(err)
+}
@@ -0,0 +1,127 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/schema"
+)
+
+var _ schema.Persister = new(Persister)
+
+func (p *Persister) CreateIdentitySchemaVersion(ctx context.Context, v *schema.Version) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateIdentitySchemaVersion")
+	defer otelx.End(span, &err)
+
+	v.NID = p.NetworkID(ctx)
+	return p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		var latest struct {
+			Version int `db:"version"`
+		}
+		//#nosec G201 -- TableName is static
+		if err := tx.RawQuery(
+			fmt.Sprintf("SELECT COALESCE(MAX(version), 0) AS version FROM %s WHERE nid = ? AND name = ?", v.TableName(ctx)),
+			v.NID, v.Name,
+		).First(&latest); err != nil {
+			return sqlcon.HandleError(err)
+		}
+
+		// Concurrently created versions violate the unique index and fail with a conflict.
+		v.Version = latest.Version + 1
+		v.SchemaID = schema.VersionSchemaID(v.Name, v.Version)
+		if v.State == "" {
+			v.State = schema.VersionStateActive
+		}
+		return sqlcon.HandleError(tx.Create(v))
+	})
+}
+
+func (p *Persister) GetIdentitySchemaVersion(ctx context.Context, schemaID string) (_ *schema.Version, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetIdentitySchemaVersion")
+	defer otelx.End(span, &err)
+
+	var v schema.Version
+	if err := p.GetConnection(ctx).Where("nid = ? AND schema_id = ?", p.NetworkID(ctx), schemaID).First(&v); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return &v, nil
+}
+
+func (p *Persister) ListIdentitySchemaVersions(ctx context.Context, name string) (_ []schema.Version, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListIdentitySchemaVersions")
+	defer otelx.End(span, &err)
+
+	q := p.GetConnection(ctx).Where("nid = ?", p.NetworkID(ctx))
+	if name != "" {
+		q = q.Where("name = ?", name)
+	}
+
+	vs := make([]schema.Version, 0)
+	if err := q.Order("name ASC, version ASC").All(&vs); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return vs, nil
+}
+
+func (p *Persister) UpdateIdentitySchemaVersionState(ctx context.Context, schemaID string, state schema.VersionState) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UpdateIdentitySchemaVersionState")
+	defer otelx.End(span, &err)
+
+	//#nosec G201 -- TableName is static
+	count, err := p.GetConnection(ctx).RawQuery(
+		fmt.Sprintf("UPDATE %s SET state = ?, updated_at = ? WHERE nid = ? AND schema_id = ?", new(schema.Version).TableName(ctx)),
+		state, time.Now().UTC(), p.NetworkID(ctx), schemaID,
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	} else if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows)
+	}
+	return nil
+}
+
+func (p *Persister) DeleteIdentitySchemaVersion(ctx context.Context, schemaID string) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteIdentitySchemaVersion")
+	defer otelx.End(span, &err)
+
+	// The version is only deleted if no identity uses it. The check only sees identities which were committed before
+	// the statement started, because the database does not enforce the reference: under READ COMMITTED, an identity
+	// which is assigned the version by a concurrent transaction can still end up with a deleted schema, which is then
+	// reported as not found when the identity is validated.
+	nid := p.NetworkID(ctx)
+	conn := p.GetConnection(ctx)
+	//#nosec G201 -- TableName is static
+	count, err := conn.RawQuery(
+		fmt.Sprintf(
+			"DELETE FROM %s WHERE nid = ? AND schema_id = ? AND NOT EXISTS (SELECT 1 FROM %s WHERE nid = ? AND schema_id = ?)",
+			new(schema.Version).TableName(ctx), new(identity.Identity).TableName(ctx),
+		),
+		nid, schemaID, nid, schemaID,
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	} else if count > 0 {
+		return nil
+	}
+
+	// Nothing was deleted, either because the version does not exist or because identities use it.
+	used, err := conn.Where("nid = ? AND schema_id = ?", nid, schemaID).Count(new(identity.Identity))
+	if err != nil {
+		return sqlcon.HandleError(err)
+	} else if used > 0 {
+		return errors.WithStack(herodot.ErrConflict.WithReasonf("The identity schema %s can not be deleted because %d identities still use it.", schemaID, used))
+	}
+	return errors.WithStack(sqlcon.ErrNoRows)
+}
@@ -0,0 +1,102 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+
+	"github.com/ory/kratos/identity"
+)
+
+var _ identity.SchemaMigrationPersister = new(Persister)
+
+func (p *Persister) CreateSchemaMigration(ctx context.Context, m *identity.SchemaMigration) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateSchemaMigration")
+	defer otelx.End(span, &err)
+
+	m.NID = p.NetworkID(ctx)
+	return sqlcon.HandleError(p.GetConnection(ctx).Create(m))
+}
+
+func (p *Persister) GetSchemaMigration(ctx context.Context, id uuid.UUID) (_ *identity.SchemaMigration, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetSchemaMigration")
+	defer otelx.End(span, &err)
+
+	var m identity.SchemaMigration
+	if err := p.GetConnection(ctx).Where("id = ? AND nid = ?", id, p.NetworkID(ctx)).First(&m); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return &m, nil
+}
+
+func (p *Persister) ClaimSchemaMigration(ctx context.Context, id uuid.UUID, staleBefore time.Time) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ClaimSchemaMigration")
+	defer otelx.End(span, &err)
+
+	//#nosec G201 -- TableName is static
+	count, err := p.GetConnection(ctx).RawQuery(
+		fmt.Sprintf("UPDATE %s SET state = ?, updated_at = ? WHERE id = ? AND nid = ? AND (state IN (?, ?) OR (state = ? AND updated_at < ?))", new(identity.SchemaMigration).TableName(ctx)),
+		identity.SchemaMigrationStateRunning, time.Now().UTC(), id, p.NetworkID(ctx),
+		identity.SchemaMigrationStatePending, identity.SchemaMigrationStateFailed,
+		identity.SchemaMigrationStateRunning, staleBefore.UTC(),
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	} else if count == 0 {
+		return errors.WithStack(herodot.ErrConflict.WithReasonf("The identity schema migration %s is already running or completed.", id))
+	}
+	return nil
+}
+
+func (p *Persister) UpdateSchemaMigration(ctx context.Context, m *identity.SchemaMigration, failures []identity.SchemaMigrationFailure) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UpdateSchemaMigration")
+	defer otelx.End(span, &err)
+
+	nid := p.NetworkID(ctx)
+	return p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		//#nosec G201 -- TableName is static
+		count, err := tx.RawQuery(
+			fmt.Sprintf("UPDATE %s SET state = ?, last_identity_id = ?, processed = ?, migrated = ?, failed = ?, last_error = ?, updated_at = ? WHERE id = ? AND nid = ?", m.TableName(ctx)),
+			m.State, m.Cursor, m.Processed, m.Migrated, m.Failed, m.Error, time.Now().UTC(), m.ID, nid,
+		).ExecWithCount()
+		if err != nil {
+			return sqlcon.HandleError(err)
+		} else if count == 0 {
+			return errors.WithStack(sqlcon.ErrNoRows)
+		}
+
+		for k := range failures {
+			failures[k].MigrationID = m.ID
+			failures[k].NID = nid
+		}
+		if len(failures) > 0 {
+			if err := tx.Create(failures); err != nil {
+				return sqlcon.HandleError(err)
+			}
+		}
+		return nil
+	})
+}
+
+func (p *Persister) ListSchemaMigrationFailures(ctx context.Context, id uuid.UUID, page, itemsPerPage int) (_ []identity.SchemaMigrationFailure, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListSchemaMigrationFailures")
+	defer otelx.End(span, &err)
+
+	fs := make([]identity.SchemaMigrationFailure, 0)
+	if err := p.GetConnection(ctx).Where("migration_id = ? AND nid = ?", id, p.NetworkID(ctx)).
+		Order("created_at ASC, id ASC").Paginate(page, itemsPerPage).All(&fs); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return fs, nil
+}
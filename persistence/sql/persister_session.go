@@ -15,6 +15,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/outbox"
 	"github.com/ory/kratos/session"
 	"github.com/ory/kratos/x/events"
 	"github.com/ory/x/otelx"
@@ -199,7 +200,9 @@ func (p *Persister) UpsertSession(ctx context.Context, s *session.Session) (err
 				return sqlcon.HandleError(err)
 			}
 			trace.SpanFromContext(ctx).AddEvent(events.NewSessionChanged(ctx, string(s.AuthenticatorAssuranceLevel), s.ID, s.IdentityID))
-			return nil
+			return p.AddEvents(ctx, append([]*outbox.Event{
+				outbox.NewSessionChanged(string(s.AuthenticatorAssuranceLevel), s.ID, s.IdentityID),
+			}, outbox.SessionEventsFromContext(ctx, s)...)...)
 		}
 
 		// This must not be eager or identities will be created / updated
@@ -225,7 +228,9 @@ func (p *Persister) UpsertSession(ctx context.Context, s *session.Session) (err
 		}
 
 		trace.SpanFromContext(ctx).AddEvent(events.NewSessionIssued(ctx, string(s.AuthenticatorAssuranceLevel), s.ID, s.IdentityID))
-		return nil
+		return p.AddEvents(ctx, append([]*outbox.Event{
+			outbox.NewSessionIssued(string(s.AuthenticatorAssuranceLevel), s.ID, s.IdentityID),
+		}, outbox.SessionEventsFromContext(ctx, s)...)...)
 	}))
 }
 
@@ -339,21 +344,28 @@ func (p *Persister) RevokeSessionByToken(ctx context.Context, token string) (err
 	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.RevokeSessionByToken")
 	defer otelx.End(span, &err)
 
-	//#nosec G201 -- TableName is static
-	count, err := p.GetConnection(ctx).RawQuery(fmt.Sprintf(
-		"UPDATE %s SET active = false WHERE token = ? AND nid = ?",
-		new(session.Session).TableName(ctx),
-	),
-		token,
-		p.NetworkID(ctx),
-	).ExecWithCount()
-	if err != nil {
-		return sqlcon.HandleError(err)
-	}
-	if count == 0 {
-		return errors.WithStack(sqlcon.ErrNoRows)
-	}
-	return nil
+	return p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		revoked, err := p.sessionRevokedEvents(ctx, tx, "token = ?", token)
+		if err != nil {
+			return err
+		}
+
+		//#nosec G201 -- TableName is static
+		count, err := tx.RawQuery(fmt.Sprintf(
+			"UPDATE %s SET active = false WHERE token = ? AND nid = ?",
+			new(session.Session).TableName(ctx),
+		),
+			token,
+			p.NetworkID(ctx),
+		).ExecWithCount()
+		if err != nil {
+			return sqlcon.HandleError(err)
+		}
+		if count == 0 {
+			return errors.WithStack(sqlcon.ErrNoRows)
+		}
+		return p.AddEvents(ctx, revoked...)
+	})
 }
 
 // RevokeSessionById revokes a given session
@@ -361,21 +373,28 @@ func (p *Persister) RevokeSessionById(ctx context.Context, sID uuid.UUID) (err e
 	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.RevokeSessionById")
 	defer otelx.End(span, &err)
 
-	//#nosec G201 -- TableName is static
-	count, err := p.GetConnection(ctx).RawQuery(fmt.Sprintf(
-		"UPDATE %s SET active = false WHERE id = ? AND nid = ?",
-		new(session.Session).TableName(ctx),
-	),
-		sID,
-		p.NetworkID(ctx),
-	).ExecWithCount()
-	if err != nil {
-		return sqlcon.HandleError(err)
-	}
-	if count == 0 {
-		return errors.WithStack(sqlcon.ErrNoRows)
-	}
-	return nil
+	return p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		revoked, err := p.sessionRevokedEvents(ctx, tx, "id = ?", sID)
+		if err != nil {
+			return err
+		}
+
+		//#nosec G201 -- TableName is static
+		count, err := tx.RawQuery(fmt.Sprintf(
+			"UPDATE %s SET active = false WHERE id = ? AND nid = ?",
+			new(session.Session).TableName(ctx),
+		),
+			sID,
+			p.NetworkID(ctx),
+		).ExecWithCount()
+		if err != nil {
+			return sqlcon.HandleError(err)
+		}
+		if count == 0 {
+			return errors.WithStack(sqlcon.ErrNoRows)
+		}
+		return p.AddEvents(ctx, revoked...)
+	})
 }
 
 // RevokeSession revokes a given session. If the session does not exist or was not modified,
@@ -384,19 +403,25 @@ func (p *Persister) RevokeSession(ctx context.Context, iID, sID uuid.UUID) (err
 	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.RevokeSession")
 	defer otelx.End(span, &err)
 
-	//#nosec G201 -- TableName is static
-	err = p.GetConnection(ctx).RawQuery(fmt.Sprintf(
-		"UPDATE %s SET active = false WHERE id = ? AND identity_id = ? AND nid = ?",
-		new(session.Session).TableName(ctx),
-	),
-		sID,
-		iID,
-		p.NetworkID(ctx),
-	).Exec()
-	if err != nil {
-		return sqlcon.HandleError(err)
-	}
-	return nil
+	return p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		revoked, err := p.sessionRevokedEvents(ctx, tx, "id = ? AND identity_id = ?", sID, iID)
+		if err != nil {
+			return err
+		}
+
+		//#nosec G201 -- TableName is static
+		if err := tx.RawQuery(fmt.Sprintf(
+			"UPDATE %s SET active = false WHERE id = ? AND identity_id = ? AND nid = ?",
+			new(session.Session).TableName(ctx),
+		),
+			sID,
+			iID,
+			p.NetworkID(ctx),
+		).Exec(); err != nil {
+			return sqlcon.HandleError(err)
+		}
+		return p.AddEvents(ctx, revoked...)
+	})
 }
 
 // RevokeSessionsIdentityExcept marks all except the given session of an identity inactive.
@@ -404,19 +429,51 @@ func (p *Persister) RevokeSessionsIdentityExcept(ctx context.Context, iID, sID u
 	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.RevokeSessionsIdentityExcept")
 	defer otelx.End(span, &err)
 
-	//#nosec G201 -- TableName is static
-	count, err := p.GetConnection(ctx).RawQuery(fmt.Sprintf(
-		"UPDATE %s SET active = false WHERE identity_id = ? AND id != ? AND nid = ?",
-		new(session.Session).TableName(ctx),
-	),
-		iID,
-		sID,
-		p.NetworkID(ctx),
-	).ExecWithCount()
-	if err != nil {
-		return 0, sqlcon.HandleError(err)
+	if err := p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		revoked, err := p.sessionRevokedEvents(ctx, tx, "identity_id = ? AND id != ?", iID, sID)
+		if err != nil {
+			return err
+		}
+
+		//#nosec G201 -- TableName is static
+		res, err = tx.RawQuery(fmt.Sprintf(
+			"UPDATE %s SET active = false WHERE identity_id = ? AND id != ? AND nid = ?",
+			new(session.Session).TableName(ctx),
+		),
+			iID,
+			sID,
+			p.NetworkID(ctx),
+		).ExecWithCount()
+		if err != nil {
+			return sqlcon.HandleError(err)
+		}
+		return p.AddEvents(ctx, revoked...)
+	}); err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+// sessionRevokedEvents returns a SessionRevoked outbox event for every active session matching the
+// condition. It must be called before the sessions are revoked.
+func (p *Persister) sessionRevokedEvents(ctx context.Context, tx *pop.Connection, where string, args ...interface{}) ([]*outbox.Event, error) {
+	if !p.r.Config().OutboxEnabled(ctx) {
+		return nil, nil
+	}
+
+	var ss []session.Session
+	if err := tx.Select("id", "identity_id").
+		Where("nid = ? AND active = ?", p.NetworkID(ctx), true).
+		Where(where, args...).
+		All(&ss); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	revoked := make([]*outbox.Event, len(ss))
+	for k, s := range ss {
+		revoked[k] = outbox.NewSessionRevoked(s.ID, s.IdentityID)
 	}
-	return count, nil
+	return revoked, nil
 }
 
 func (p *Persister) DeleteExpiredSessions(ctx context.Context, expiresAt time.Time, limit int) (err error) {
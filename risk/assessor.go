@@ -0,0 +1,71 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package risk
+
+import (
+	"net/http"
+
+	"github.com/ory/x/otelx"
+
+	"github.com/ory/kratos/bruteforce"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x"
+)
+
+type (
+	assessorDependencies interface {
+		x.TracingProvider
+		bruteforce.ThrottlerProvider
+		EvaluatorsProvider
+	}
+	AssessorProvider interface {
+		LoginRiskAssessor() *Assessor
+	}
+	// Assessor assesses the risk of logins using all evaluators.
+	Assessor struct {
+		d assessorDependencies
+	}
+
+	// Assessment is the risk of a login.
+	Assessment struct {
+		// Score is the sum of the signals' scores.
+		Score   int      `json:"score"`
+		Signals []Signal `json:"signals"`
+	}
+)
+
+func NewAssessor(d assessorDependencies) *Assessor {
+	return &Assessor{d: d}
+}
+
+// Assess collects the signals of all evaluators for the login of the identity to the session.
+func (a *Assessor) Assess(r *http.Request, i *identity.Identity, s *session.Session) (_ *Assessment, err error) {
+	ctx, span := a.d.Tracer(r.Context()).Tracer().Start(r.Context(), "risk.Assessor.Assess")
+	defer otelx.End(span, &err)
+
+	l := NewLogin(r, a.d.LoginThrottler(), i, s)
+	assessment := &Assessment{Signals: []Signal{}}
+	for _, e := range a.d.LoginRiskEvaluators(ctx) {
+		signals, err := e.EvaluateLoginRisk(ctx, l)
+		if err != nil {
+			return nil, err
+		}
+		for _, signal := range signals {
+			assessment.Score += signal.Score
+			assessment.Signals = append(assessment.Signals, signal)
+		}
+	}
+
+	return assessment, nil
+}
+
+// SignalNames returns the names of the assessment's signals.
+func (a *Assessment) SignalNames() []string {
+	names := make([]string, len(a.Signals))
+	for k, s := range a.Signals {
+		names[k] = s.Name
+	}
+	return names
+}
@@ -0,0 +1,89 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package risk
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ory/kratos/bruteforce"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/session"
+)
+
+const (
+	SignalNewDevice      = "new_device"
+	SignalNewCountry     = "new_country"
+	SignalFailedAttempts = "failed_attempts"
+	SignalWebhook        = "webhook"
+)
+
+type (
+	// Login describes a first factor login whose risk is assessed.
+	Login struct {
+		Identity *identity.Identity
+		Session  *session.Session
+
+		IPAddress string
+		UserAgent string
+		// Country is the ISO 3166-1 alpha-2 code of the country the login came from, if known.
+		Country string
+	}
+
+	// Signal is a reason why a login looks risky.
+	Signal struct {
+		Name  string `json:"name"`
+		Score int    `json:"score"`
+	}
+
+	// Evaluator is a source of risk signals. Custom evaluators can be added to the registry.
+	Evaluator interface {
+		// EvaluateLoginRisk returns the signals which make the login look risky, if any.
+		EvaluateLoginRisk(ctx context.Context, l *Login) ([]Signal, error)
+	}
+
+	EvaluatorsProvider interface {
+		LoginRiskEvaluators(ctx context.Context) []Evaluator
+	}
+)
+
+// NewLogin describes the login of the identity to the session which is performed by the request. The client IP
+// address and the country are taken from the proxy headers only if the throttler trusts the proxy, because clients
+// can forge them.
+func NewLogin(r *http.Request, t *bruteforce.Throttler, i *identity.Identity, s *session.Session) *Login {
+	l := &Login{
+		Identity:  i,
+		Session:   s,
+		IPAddress: t.ClientIP(r),
+		UserAgent: strings.Join(r.Header["User-Agent"], " "),
+	}
+	if t.FromTrustedProxy(r) {
+		l.Country = strings.ToUpper(strings.TrimSpace(r.Header.Get("Cf-Ipcountry")))
+	}
+	return l
+}
+
+// hostOnly strips the port which is included in session devices whose client IP address fell back to the remote
+// address.
+func hostOnly(ip string) string {
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		return host
+	}
+	return ip
+}
+
+// deviceCountry returns the country of a session device. Its location is stored as "<city>, <country>" or
+// "<country>" if the city is unknown.
+func deviceCountry(d session.Device) string {
+	if d.Location == nil {
+		return ""
+	}
+	location := *d.Location
+	if i := strings.LastIndex(location, ", "); i >= 0 {
+		location = location[i+2:]
+	}
+	return strings.ToUpper(strings.TrimSpace(location))
+}
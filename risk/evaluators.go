@@ -0,0 +1,198 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package risk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/pointerx"
+
+	"github.com/ory/kratos/bruteforce"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x"
+)
+
+// previousSessions is the number of the identity's most recent sessions a login is compared with.
+const previousSessions = 100
+
+type (
+	deviceEvaluatorDependencies interface {
+		config.Provider
+		session.PersistenceProvider
+	}
+	// DeviceEvaluator compares the login with the devices of the identity's previous sessions.
+	DeviceEvaluator struct {
+		d deviceEvaluatorDependencies
+	}
+
+	failedAttemptsEvaluatorDependencies interface {
+		config.Provider
+		bruteforce.ThrottlerProvider
+	}
+	// FailedAttemptsEvaluator scores the recent failed login attempts from the client's IP address.
+	FailedAttemptsEvaluator struct {
+		d failedAttemptsEvaluatorDependencies
+	}
+
+	webhookEvaluatorDependencies interface {
+		config.Provider
+		x.LoggingProvider
+		x.HTTPClientProvider
+	}
+	// WebhookEvaluator asks an external service to score the login.
+	WebhookEvaluator struct {
+		d webhookEvaluatorDependencies
+	}
+
+	webhookRequest struct {
+		IdentityID uuid.UUID `json:"identity_id"`
+		IPAddress  string    `json:"ip_address"`
+		UserAgent  string    `json:"user_agent"`
+		Country    string    `json:"country"`
+	}
+	webhookResponse struct {
+		Score int `json:"score"`
+	}
+)
+
+var (
+	_ Evaluator = new(DeviceEvaluator)
+	_ Evaluator = new(FailedAttemptsEvaluator)
+	_ Evaluator = new(WebhookEvaluator)
+)
+
+func NewDeviceEvaluator(d deviceEvaluatorDependencies) *DeviceEvaluator {
+	return &DeviceEvaluator{d: d}
+}
+
+// EvaluateLoginRisk signals a new device if none of the previous sessions were used with the same user agent from the
+// same IP address or country, and a new country if none of the previous sessions came from the login's country.
+func (e *DeviceEvaluator) EvaluateLoginRisk(ctx context.Context, l *Login) ([]Signal, error) {
+	sessions, _, err := e.d.SessionPersister().ListSessionsByIdentity(ctx, l.Identity.ID, nil, 1, previousSessions, l.Session.ID, session.Expandables{session.ExpandSessionDevices})
+	if err != nil {
+		return nil, err
+	}
+
+	var seen, knownDevice bool
+	countries := map[string]bool{}
+	for _, s := range sessions {
+		for _, d := range s.Devices {
+			seen = true
+
+			country := deviceCountry(d)
+			if country != "" {
+				countries[country] = true
+			}
+
+			if l.UserAgent == "" || pointerx.Deref(d.UserAgent) != l.UserAgent {
+				continue
+			}
+			if (l.IPAddress != "" && hostOnly(pointerx.Deref(d.IPAddress)) == l.IPAddress) || (l.Country != "" && country == l.Country) {
+				knownDevice = true
+			}
+		}
+	}
+
+	// The first login of an identity can not be compared with anything.
+	if !seen {
+		return nil, nil
+	}
+
+	conf := e.d.Config().SelfServiceFlowLoginRisk(ctx)
+	var signals []Signal
+	if !knownDevice {
+		signals = append(signals, Signal{Name: SignalNewDevice, Score: conf.NewDeviceScore})
+	}
+	if l.Country != "" && len(countries) > 0 && !countries[l.Country] {
+		signals = append(signals, Signal{Name: SignalNewCountry, Score: conf.NewCountryScore})
+	}
+	return signals, nil
+}
+
+func NewFailedAttemptsEvaluator(d failedAttemptsEvaluatorDependencies) *FailedAttemptsEvaluator {
+	return &FailedAttemptsEvaluator{d: d}
+}
+
+// EvaluateLoginRisk scores every recent failed login attempt from the client's IP address.
+func (e *FailedAttemptsEvaluator) EvaluateLoginRisk(ctx context.Context, l *Login) ([]Signal, error) {
+	failures, err := e.d.LoginThrottler().RecentFailures(ctx, l.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+	if failures == 0 {
+		return nil, nil
+	}
+
+	return []Signal{{Name: SignalFailedAttempts, Score: failures * e.d.Config().SelfServiceFlowLoginRisk(ctx).FailedAttemptScore}}, nil
+}
+
+func NewWebhookEvaluator(d webhookEvaluatorDependencies) *WebhookEvaluator {
+	return &WebhookEvaluator{d: d}
+}
+
+// EvaluateLoginRisk sends the login to the configured webhook and uses the score it responds with. The webhook is
+// ignored if it is not configured or fails, so that an outage of the webhook does not prevent users from logging in.
+func (e *WebhookEvaluator) EvaluateLoginRisk(ctx context.Context, l *Login) ([]Signal, error) {
+	u := e.d.Config().SelfServiceFlowLoginRisk(ctx).WebhookURL
+	if u == nil {
+		return nil, nil
+	}
+
+	score, err := e.score(ctx, u.String(), l)
+	if err != nil {
+		e.d.Logger().
+			WithError(err).
+			WithField("identity_id", l.Identity.ID).
+			WithField("webhook_url", u.Redacted()).
+			Warn("Ignoring the login risk webhook because it failed.")
+		return nil, nil
+	}
+	if score == 0 {
+		return nil, nil
+	}
+
+	return []Signal{{Name: SignalWebhook, Score: score}}, nil
+}
+
+func (e *WebhookEvaluator) score(ctx context.Context, u string, l *Login) (int, error) {
+	body, err := json.Marshal(&webhookRequest{
+		IdentityID: l.Identity.ID,
+		IPAddress:  l.IPAddress,
+		UserAgent:  l.UserAgent,
+		Country:    l.Country,
+	})
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, "POST", u, bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := e.d.HTTPClient(ctx).Do(req)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return 0, errors.Errorf("webhook responded with status code %d", res.StatusCode)
+	}
+
+	var parsed webhookResponse
+	if err := json.NewDecoder(res.Body).Decode(&parsed); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return parsed.Score, nil
+}
@@ -0,0 +1,174 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package risk_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/risk"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x"
+)
+
+type staticEvaluator []risk.Signal
+
+func (e staticEvaluator) EvaluateLoginRisk(context.Context, *risk.Login) ([]risk.Signal, error) {
+	return e, nil
+}
+
+func TestEvaluators(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/identity.schema.json")
+	conf.MustSet(ctx, config.ViperKeySelfServiceLoginRiskEnabled, true)
+	// The test requests come from trusted proxies, so that their client IP address and country are used.
+	conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceTrustedProxies, []string{"192.0.2.0/24"})
+
+	newIdentity := func(t *testing.T) *identity.Identity {
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(ctx, i))
+		return i
+	}
+
+	newRequest := func(t *testing.T, ip, userAgent, country string) *http.Request {
+		r := x.NewTestHTTPRequest(t, "GET", "/", nil)
+		r.RemoteAddr = ip + ":1234"
+		r.Header.Set("True-Client-IP", ip)
+		r.Header.Set("User-Agent", userAgent)
+		if country != "" {
+			r.Header.Set("Cf-Ipcity", "Somewhere")
+			r.Header.Set("Cf-Ipcountry", country)
+		}
+		return r
+	}
+
+	login := func(t *testing.T, i *identity.Identity, r *http.Request) {
+		s, err := session.NewActiveSession(r, i, conf, time.Now().UTC(), identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+		require.NoError(t, err)
+		require.NoError(t, reg.SessionPersister().UpsertSession(ctx, s))
+	}
+
+	evaluate := func(t *testing.T, e risk.Evaluator, i *identity.Identity, r *http.Request) []risk.Signal {
+		signals, err := e.EvaluateLoginRisk(ctx, risk.NewLogin(r, reg.LoginThrottler(), i, session.NewInactiveSession()))
+		require.NoError(t, err)
+		return signals
+	}
+
+	t.Run("evaluator=device", func(t *testing.T) {
+		e := risk.NewDeviceEvaluator(reg)
+		i := newIdentity(t)
+
+		assert.Empty(t, evaluate(t, e, i, newRequest(t, "192.0.2.1", "agent-a", "DE")), "the first login is not risky")
+
+		login(t, i, newRequest(t, "192.0.2.1", "agent-a", "DE"))
+
+		for _, tc := range []struct {
+			d        string
+			r        *http.Request
+			expected []risk.Signal
+		}{
+			{d: "same device", r: newRequest(t, "192.0.2.1", "agent-a", "DE")},
+			{d: "same device from another IP in the same country", r: newRequest(t, "192.0.2.2", "agent-a", "de")},
+			{d: "same device from the same IP without a country", r: newRequest(t, "192.0.2.1", "agent-a", "")},
+			{d: "another user agent", r: newRequest(t, "192.0.2.1", "agent-b", "DE"), expected: []risk.Signal{{Name: risk.SignalNewDevice, Score: 30}}},
+			{d: "another country", r: newRequest(t, "192.0.2.2", "agent-a", "US"), expected: []risk.Signal{{Name: risk.SignalNewDevice, Score: 30}, {Name: risk.SignalNewCountry, Score: 100}}},
+			{d: "same IP from another country", r: newRequest(t, "192.0.2.1", "agent-a", "US"), expected: []risk.Signal{{Name: risk.SignalNewCountry, Score: 100}}},
+		} {
+			t.Run("case="+tc.d, func(t *testing.T) {
+				assert.Equal(t, tc.expected, evaluate(t, e, i, tc.r))
+			})
+		}
+
+		t.Run("case=ignores the forwarded client IP address and country of untrusted clients", func(t *testing.T) {
+			r := newRequest(t, "192.0.2.1", "agent-a", "DE")
+			r.RemoteAddr = "198.51.100.1:1234"
+			l := risk.NewLogin(r, reg.LoginThrottler(), i, session.NewInactiveSession())
+			assert.Equal(t, "198.51.100.1", l.IPAddress)
+			assert.Empty(t, l.Country)
+			assert.Equal(t, []risk.Signal{{Name: risk.SignalNewDevice, Score: 30}}, evaluate(t, e, i, r))
+		})
+	})
+
+	t.Run("evaluator=failed attempts", func(t *testing.T) {
+		e := risk.NewFailedAttemptsEvaluator(reg)
+		i := newIdentity(t)
+		r := newRequest(t, "192.0.2.10", "agent-a", "")
+
+		require.NoError(t, reg.LoginThrottler().RecordFailure(r, "someone"))
+		assert.Empty(t, evaluate(t, e, i, r), "failed attempts are not recorded without brute-force protection")
+
+		conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceProtectionEnabled, true)
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceProtectionEnabled, false)
+		})
+
+		for k := 0; k < 3; k++ {
+			require.NoError(t, reg.LoginThrottler().RecordFailure(r, "someone"))
+		}
+		require.NoError(t, reg.LoginThrottler().RecordSuccess(r, "someone"))
+
+		assert.Equal(t, []risk.Signal{{Name: risk.SignalFailedAttempts, Score: 30}}, evaluate(t, e, i, r))
+		assert.Empty(t, evaluate(t, e, i, newRequest(t, "192.0.2.11", "agent-a", "")))
+	})
+
+	t.Run("evaluator=webhook", func(t *testing.T) {
+		e := risk.NewWebhookEvaluator(reg)
+		i := newIdentity(t)
+		r := newRequest(t, "192.0.2.20", "agent-a", "DE")
+
+		assert.Empty(t, evaluate(t, e, i, r), "the webhook is optional")
+
+		var received map[string]interface{}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			if received["country"] == "US" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"score":42}`))
+		}))
+		t.Cleanup(ts.Close)
+		conf.MustSet(ctx, config.ViperKeySelfServiceLoginRiskWebhookURL, ts.URL)
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeySelfServiceLoginRiskWebhookURL, "")
+		})
+
+		assert.Equal(t, []risk.Signal{{Name: risk.SignalWebhook, Score: 42}}, evaluate(t, e, i, r))
+		assert.Equal(t, map[string]interface{}{
+			"identity_id": i.ID.String(),
+			"ip_address":  "192.0.2.20",
+			"user_agent":  "agent-a",
+			"country":     "DE",
+		}, received)
+
+		assert.Empty(t, evaluate(t, e, i, newRequest(t, "192.0.2.20", "agent-a", "US")), "failing webhooks are ignored")
+	})
+
+	t.Run("case=assessor sums the signals of all evaluators", func(t *testing.T) {
+		reg.WithLoginRiskEvaluators(staticEvaluator{{Name: "custom", Score: 7}})
+		t.Cleanup(func() {
+			reg.WithLoginRiskEvaluators()
+		})
+
+		i := newIdentity(t)
+		login(t, i, newRequest(t, "192.0.2.30", "agent-a", "DE"))
+
+		assessment, err := reg.LoginRiskAssessor().Assess(newRequest(t, "192.0.2.31", "agent-b", "DE"), i, session.NewInactiveSession())
+		require.NoError(t, err)
+		assert.Equal(t, 37, assessment.Score)
+		assert.Equal(t, []string{risk.SignalNewDevice, "custom"}, assessment.SignalNames())
+	})
+}
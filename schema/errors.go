@@ -5,6 +5,7 @@ package schema
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -75,6 +76,39 @@ func NewErrorValidationLookupInvalid() error {
 	})
 }
 
+func NewLoginCodeInvalid() error {
+	t := text.NewErrorValidationLoginCodeInvalidOrAlreadyUsed()
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     t.Text,
+			InstancePtr: "#/code",
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
+
+func NewLoginCodeSubmittedTooOftenError() error {
+	t := text.NewErrorValidationLoginCodeSubmittedTooOften()
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     t.Text,
+			InstancePtr: "#/code",
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
+
+func NewRegistrationCodeInvalid() error {
+	t := text.NewErrorValidationRegistrationCodeInvalidOrAlreadyUsed()
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     t.Text,
+			InstancePtr: "#/code",
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
+
 type ValidationErrorContextPasswordPolicyViolation struct {
 	Reason string
 }
@@ -123,6 +157,116 @@ func NewInvalidCredentialsError() error {
 	})
 }
 
+func NewLoginRetryLaterError(retryAt time.Time) error {
+	t := text.NewErrorValidationLoginRetryLater(retryAt)
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     t.Text,
+			InstancePtr: "#/",
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
+
+func NewLoginLockedOutError(lockedUntil time.Time) error {
+	t := text.NewErrorValidationLoginLockedOut(lockedUntil)
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     t.Text,
+			InstancePtr: "#/",
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
+
+func NewLoginOrganizationSSORequiredError(provider string) error {
+	t := text.NewErrorValidationLoginOrganizationSSORequired(provider)
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     t.Text,
+			InstancePtr: "#/",
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
+
+func NewLoginIdentityInactiveError() error {
+	t := text.NewErrorValidationLoginIdentityInactive()
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     t.Text,
+			InstancePtr: "#/",
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
+
+func NewLoginIdentitySuspendedError() error {
+	t := text.NewErrorValidationLoginIdentitySuspended()
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     t.Text,
+			InstancePtr: "#/",
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
+
+func NewLoginIdentitySuspendedUntilError(suspendedUntil time.Time) error {
+	t := text.NewErrorValidationLoginIdentitySuspendedUntil(suspendedUntil)
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     t.Text,
+			InstancePtr: "#/",
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
+
+func NewLoginIdentityPendingDeletionError(deleteAfter time.Time) error {
+	t := text.NewErrorValidationLoginIdentityPendingDeletion(deleteAfter)
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     t.Text,
+			InstancePtr: "#/",
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
+
+func NewLoginCodeRequiredError() error {
+	t := text.NewErrorValidationLoginCodeRequired()
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     t.Text,
+			InstancePtr: "#/",
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
+
+func NewRegistrationOrganizationSSORequiredError(provider string) error {
+	t := text.NewErrorValidationRegistrationOrganizationSSORequired(provider)
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     t.Text,
+			InstancePtr: "#/",
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
+
+func NewRecoveryOrganizationSSORequiredError(provider string) error {
+	t := text.NewErrorValidationRecoveryOrganizationSSORequired(provider)
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     t.Text,
+			InstancePtr: "#/",
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
+
 type ValidationErrorContextDuplicateCredentialsError struct{}
 
 func (r *ValidationErrorContextDuplicateCredentialsError) AddContext(_, _ string) {}
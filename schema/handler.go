@@ -31,6 +31,7 @@ type (
 		x.WriterProvider
 		x.LoggingProvider
 		IdentityTraitsProvider
+		PersistenceProvider
 		x.CSRFProvider
 		config.Provider
 	}
@@ -57,11 +58,13 @@ func (h *Handler) RegisterPublicRoutes(public *x.RouterPublic) {
 	public.GET(fmt.Sprintf("/%s", SchemasPath), h.getAll)
 	public.GET(fmt.Sprintf("%s/%s/:id", x.AdminPrefix, SchemasPath), h.getIdentitySchema)
 	public.GET(fmt.Sprintf("%s/%s", x.AdminPrefix, SchemasPath), h.getAll)
+	h.registerPublicVersionRoutes(public)
 }
 
 func (h *Handler) RegisterAdminRoutes(admin *x.RouterAdmin) {
 	admin.GET(fmt.Sprintf("/%s/:id", SchemasPath), x.RedirectToPublicRoute(h.r))
 	admin.GET(fmt.Sprintf("/%s", SchemasPath), x.RedirectToPublicRoute(h.r))
+	h.registerAdminVersionRoutes(admin)
 }
 
 // Raw JSON Schema
@@ -102,21 +105,15 @@ type getIdentitySchema struct {
 //	  404: errorGeneric
 //	  default: errorGeneric
 func (h *Handler) getIdentitySchema(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	ss, err := h.r.IdentityTraitsSchemas(r.Context())
-	if err != nil {
-		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err)))
-		return
-	}
-
 	id := ps.ByName("id")
-	s, err := ss.GetByID(id)
+	s, err := h.r.IdentityTraitsSchema(r.Context(), id)
 	if err != nil {
 		// Maybe it is a base64 encoded ID?
 		if dec, err := base64.RawURLEncoding.DecodeString(id); err == nil {
 			id = string(dec)
 		}
 
-		s, err = ss.GetByID(id)
+		s, err = h.r.IdentityTraitsSchema(r.Context(), id)
 		if err != nil {
 			h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrNotFound.WithReasonf("Identity schema `%s` could not be found.", id)))
 			return
@@ -0,0 +1,339 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/jsonx"
+	"github.com/ory/x/pagination"
+	"github.com/ory/x/pagination/migrationpagination"
+	"github.com/ory/x/urlx"
+
+	"github.com/ory/kratos/x"
+)
+
+const (
+	RouteVersionCollection = "/identity-schemas"
+	RouteVersionItem       = RouteVersionCollection + "/:id"
+	RouteVersionDeprecate  = RouteVersionItem + "/deprecate"
+)
+
+func (h *Handler) registerPublicVersionRoutes(public *x.RouterPublic) {
+	h.r.CSRFHandler().IgnoreGlobs(
+		RouteVersionCollection, RouteVersionCollection+"/*", RouteVersionCollection+"/*/*",
+		x.AdminPrefix+RouteVersionCollection, x.AdminPrefix+RouteVersionCollection+"/*", x.AdminPrefix+RouteVersionCollection+"/*/*",
+	)
+
+	for _, prefix := range []string{"", x.AdminPrefix} {
+		public.GET(prefix+RouteVersionCollection, x.RedirectToAdminRoute(h.r))
+		public.GET(prefix+RouteVersionItem, x.RedirectToAdminRoute(h.r))
+		public.POST(prefix+RouteVersionCollection, x.RedirectToAdminRoute(h.r))
+		public.POST(prefix+RouteVersionDeprecate, x.RedirectToAdminRoute(h.r))
+		public.DELETE(prefix+RouteVersionItem, x.RedirectToAdminRoute(h.r))
+	}
+}
+
+func (h *Handler) registerAdminVersionRoutes(admin *x.RouterAdmin) {
+	admin.GET(RouteVersionCollection, h.listVersions)
+	admin.GET(RouteVersionItem, h.getVersion)
+	admin.POST(RouteVersionCollection, h.createVersion)
+	admin.POST(RouteVersionDeprecate, h.deprecateVersion)
+	admin.DELETE(RouteVersionItem, h.deleteVersion)
+}
+
+// Paginated Identity Schema Version List Response
+//
+// swagger:response listIdentitySchemaVersions
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listIdentitySchemaVersionsResponse struct {
+	migrationpagination.ResponseHeaderAnnotation
+
+	// List of identity schema versions
+	//
+	// in:body
+	Body []Version
+}
+
+// Paginated List Identity Schema Version Parameters
+//
+// swagger:parameters listIdentitySchemaVersions
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listIdentitySchemaVersionsParameters struct {
+	migrationpagination.RequestParameters
+
+	// Name lists only the versions of the identity schema with the given name.
+	//
+	// required: false
+	// in: query
+	Name string `json:"name"`
+}
+
+// swagger:route GET /admin/identity-schemas identity listIdentitySchemaVersions
+//
+// # List Identity Schema Versions
+//
+// Lists the identity schema versions which are stored in the database, ordered by name and version.
+// Identity schemas defined in the configuration are not included.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: listIdentitySchemaVersions
+//	  default: errorGeneric
+func (h *Handler) listVersions(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	page, itemsPerPage := x.ParsePagination(r)
+
+	vs, err := h.r.IdentitySchemaPersister().ListIdentitySchemaVersions(r.Context(), r.URL.Query().Get("name"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	start, end := pagination.Index((page+1)*itemsPerPage, page*itemsPerPage, len(vs))
+	x.PaginationHeader(w, urlx.AppendPaths(h.r.Config().SelfAdminURL(r.Context()), RouteVersionCollection), int64(len(vs)), page, itemsPerPage)
+	h.r.Writer().Write(w, r, vs[start:end])
+}
+
+// Get Identity Schema Version Parameters
+//
+// swagger:parameters getIdentitySchemaVersion
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type getIdentitySchemaVersion struct {
+	// ID is the identity schema version's ID, for example `customer@v2`.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route GET /admin/identity-schemas/{id} identity getIdentitySchemaVersion
+//
+// # Get an Identity Schema Version
+//
+// Return an identity schema version which is stored in the database by its ID.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: identitySchemaVersion
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) getVersion(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	v, err := h.r.IdentitySchemaPersister().GetIdentitySchemaVersion(r.Context(), ps.ByName("id"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, v)
+}
+
+// Create Identity Schema Version Parameters
+//
+// swagger:parameters createIdentitySchemaVersion
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type createIdentitySchemaVersion struct {
+	// in: body
+	Body CreateIdentitySchemaVersionBody
+}
+
+// Create Identity Schema Version Body
+//
+// swagger:model createIdentitySchemaVersionBody
+type CreateIdentitySchemaVersionBody struct {
+	// Name is the name of the identity schema, for example `customer`. It must consist of letters,
+	// digits, dashes, or underscores and must not be used by an identity schema defined in the
+	// configuration.
+	//
+	// required: true
+	Name string `json:"name"`
+
+	// Schema is the JSON Schema of the new version. It must define the `traits` property.
+	//
+	// required: true
+	Schema json.RawMessage `json:"schema"`
+}
+
+// swagger:route POST /admin/identity-schemas identity createIdentitySchemaVersion
+//
+// # Create an Identity Schema Version
+//
+// Stores the JSON Schema as the next version of the identity schema with the given name. The first
+// version of `customer` has the ID `customer@v1`, the next one `customer@v2`, and so on. The new
+// version is served by the public identity schema endpoints and can be assigned to identities
+// right away.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  201: identitySchemaVersion
+//	  400: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) createVersion(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var cr CreateIdentitySchemaVersionBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&cr); err != nil {
+		h.r.Writer().WriteErrorCode(w, r, http.StatusBadRequest, errors.WithStack(err))
+		return
+	}
+
+	v := &Version{Name: strings.TrimSpace(cr.Name), Schema: []byte(cr.Schema), State: VersionStateActive}
+	if err := v.Validate(r.Context()); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	configured, err := h.r.Config().IdentityTraitsSchemas(r.Context())
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	for _, s := range configured {
+		if s.ID == v.Name || strings.HasPrefix(s.ID, v.Name+"@") {
+			h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrConflict.WithReasonf("The identity schema name %q is already used by an identity schema defined in the configuration.", v.Name)))
+			return
+		}
+	}
+
+	if err := h.r.IdentitySchemaPersister().CreateIdentitySchemaVersion(r.Context(), v); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().WriteCreated(w, r,
+		urlx.AppendPaths(
+			h.r.Config().SelfAdminURL(r.Context()),
+			RouteVersionCollection,
+			v.SchemaID,
+		).String(),
+		v,
+	)
+}
+
+// Deprecate Identity Schema Version Parameters
+//
+// swagger:parameters deprecateIdentitySchemaVersion
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type deprecateIdentitySchemaVersion struct {
+	// ID is the identity schema version's ID, for example `customer@v2`.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route POST /admin/identity-schemas/{id}/deprecate identity deprecateIdentitySchemaVersion
+//
+// # Deprecate an Identity Schema Version
+//
+// Deprecates an identity schema version. Identities which already use the version are still
+// validated against it, but the version can no longer be assigned to identities.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: identitySchemaVersion
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) deprecateVersion(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	if err := h.r.IdentitySchemaPersister().UpdateIdentitySchemaVersionState(r.Context(), ps.ByName("id"), VersionStateDeprecated); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	v, err := h.r.IdentitySchemaPersister().GetIdentitySchemaVersion(r.Context(), ps.ByName("id"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, v)
+}
+
+// Delete Identity Schema Version Parameters
+//
+// swagger:parameters deleteIdentitySchemaVersion
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type deleteIdentitySchemaVersion struct {
+	// ID is the identity schema version's ID, for example `customer@v2`.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route DELETE /admin/identity-schemas/{id} identity deleteIdentitySchemaVersion
+//
+// # Delete an Identity Schema Version
+//
+// Deletes an identity schema version. Versions which are still used by identities can not be
+// deleted; deprecate them instead. This action can not be undone.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  204: emptyResponse
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) deleteVersion(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	if err := h.r.IdentitySchemaPersister().DeleteIdentitySchemaVersion(r.Context(), ps.ByName("id")); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
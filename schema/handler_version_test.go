@@ -0,0 +1,152 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package schema_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/schema"
+)
+
+func TestVersionHandler(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	publicTS, adminTS := testhelpers.NewKratosServerWithCSRF(t, reg)
+	conf.MustSet(ctx, config.ViperKeyAdminBaseURL, adminTS.URL)
+	testhelpers.SetDefaultIdentitySchema(conf, "base64://"+base64.StdEncoding.EncodeToString([]byte(`{"type":"object","properties":{"traits":{"type":"object"}}}`)))
+
+	send := func(t *testing.T, base *httptest.Server, method, href string, expectCode int, send interface{}) gjson.Result {
+		t.Helper()
+		var b bytes.Buffer
+		if send != nil {
+			require.NoError(t, json.NewEncoder(&b).Encode(send))
+		}
+		req, err := http.NewRequest(method, base.URL+href, &b)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		res, err := base.Client().Do(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+
+		require.EqualValues(t, expectCode, res.StatusCode, "%s", body)
+		return gjson.ParseBytes(body)
+	}
+
+	customer := func(required ...string) json.RawMessage {
+		if required == nil {
+			required = []string{}
+		}
+		raw, err := json.Marshal(map[string]interface{}{
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"type":    "object",
+			"properties": map[string]interface{}{
+				"traits": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"email":   map[string]interface{}{"type": "string", "format": "email"},
+						"company": map[string]interface{}{"type": "string"},
+					},
+					"required": required,
+				},
+			},
+		})
+		require.NoError(t, err)
+		return raw
+	}
+
+	t.Run("case=should reject invalid identity schemas", func(t *testing.T) {
+		for _, tc := range []schema.CreateIdentitySchemaVersionBody{
+			{Name: "", Schema: customer("email")},
+			{Name: "customer@v1", Schema: customer("email")},
+			{Name: "customer", Schema: json.RawMessage(`{"type":"object"}`)},
+			{Name: "customer", Schema: json.RawMessage(`{"properties":{"traits":{"type":"not-a-type"}}}`)},
+		} {
+			res := send(t, adminTS, "POST", "/admin/identity-schemas", http.StatusBadRequest, &tc)
+			assert.NotEmpty(t, res.Get("error.reason").String(), "%s", res.Raw)
+		}
+
+		_ = send(t, adminTS, "POST", "/admin/identity-schemas", http.StatusConflict, &schema.CreateIdentitySchemaVersionBody{Name: config.DefaultIdentityTraitsSchemaID, Schema: customer("email")})
+	})
+
+	t.Run("case=should create versions and serve them publicly", func(t *testing.T) {
+		v1 := send(t, adminTS, "POST", "/admin/identity-schemas", http.StatusCreated, &schema.CreateIdentitySchemaVersionBody{Name: "customer", Schema: customer("email")})
+		assert.Equal(t, "customer@v1", v1.Get("id").String(), "%s", v1.Raw)
+		assert.EqualValues(t, 1, v1.Get("version").Int())
+		assert.Equal(t, string(schema.VersionStateActive), v1.Get("state").String())
+
+		v2 := send(t, adminTS, "POST", "/admin/identity-schemas", http.StatusCreated, &schema.CreateIdentitySchemaVersionBody{Name: "customer", Schema: customer("email", "company")})
+		assert.Equal(t, "customer@v2", v2.Get("id").String(), "%s", v2.Raw)
+
+		for name, ts := range map[string]*httptest.Server{"public": publicTS, "admin": adminTS} {
+			t.Run("endpoint="+name, func(t *testing.T) {
+				res := send(t, ts, "GET", "/identity-schemas/customer@v2", http.StatusOK, nil)
+				assert.JSONEq(t, v2.Raw, res.Raw)
+
+				list := send(t, ts, "GET", "/identity-schemas?name=customer", http.StatusOK, nil)
+				assert.Equal(t, []interface{}{"customer@v1", "customer@v2"}, list.Get("#.id").Value(), "%s", list.Raw)
+			})
+		}
+
+		res := send(t, publicTS, "GET", "/schemas/customer@v1", http.StatusOK, nil)
+		assert.JSONEq(t, string(customer("email")), res.Raw)
+
+		list := send(t, publicTS, "GET", "/schemas?per_page=100", http.StatusOK, nil)
+		assert.Equal(t, []interface{}{config.DefaultIdentityTraitsSchemaID, "customer@v1", "customer@v2"}, list.Get("#.id").Value(), "%s", list.Raw)
+
+		_ = send(t, adminTS, "POST", "/admin/identities", http.StatusBadRequest, json.RawMessage(`{"schema_id":"customer@v2","traits":{"email":"foo@ory.sh"}}`))
+		created := send(t, adminTS, "POST", "/admin/identities", http.StatusCreated, json.RawMessage(`{"schema_id":"customer@v2","traits":{"email":"foo@ory.sh","company":"Ory"}}`))
+		assert.Equal(t, "customer@v2", created.Get("schema_id").String(), "%s", created.Raw)
+	})
+
+	t.Run("case=should not assign deprecated versions", func(t *testing.T) {
+		v := send(t, adminTS, "POST", "/admin/identity-schemas", http.StatusCreated, &schema.CreateIdentitySchemaVersionBody{Name: "legacy", Schema: customer()})
+		id := v.Get("id").String()
+		existing := send(t, adminTS, "POST", "/admin/identities", http.StatusCreated, json.RawMessage(`{"schema_id":"`+id+`","traits":{"email":"legacy@ory.sh"}}`))
+		other := send(t, adminTS, "POST", "/admin/identities", http.StatusCreated, json.RawMessage(`{"traits":{}}`))
+
+		deprecated := send(t, adminTS, "POST", "/admin/identity-schemas/"+id+"/deprecate", http.StatusOK, nil)
+		assert.Equal(t, string(schema.VersionStateDeprecated), deprecated.Get("state").String(), "%s", deprecated.Raw)
+
+		res := send(t, adminTS, "POST", "/admin/identities", http.StatusBadRequest, json.RawMessage(`{"schema_id":"`+id+`","traits":{}}`))
+		assert.Contains(t, res.Get("error.reason").String(), "deprecated", "%s", res.Raw)
+		_ = send(t, adminTS, "PUT", "/admin/identities/"+other.Get("id").String(), http.StatusBadRequest, json.RawMessage(`{"schema_id":"`+id+`","traits":{}}`))
+
+		// Identities which already use the version can still be updated.
+		_ = send(t, adminTS, "PUT", "/admin/identities/"+existing.Get("id").String(), http.StatusOK, json.RawMessage(`{"schema_id":"`+id+`","traits":{"email":"legacy-2@ory.sh"}}`))
+		_ = send(t, publicTS, "GET", "/schemas/"+id, http.StatusOK, nil)
+	})
+
+	t.Run("case=should not delete versions which are in use", func(t *testing.T) {
+		v := send(t, adminTS, "POST", "/admin/identity-schemas", http.StatusCreated, &schema.CreateIdentitySchemaVersionBody{Name: "partner", Schema: customer()})
+		id := v.Get("id").String()
+		i := send(t, adminTS, "POST", "/admin/identities", http.StatusCreated, json.RawMessage(`{"schema_id":"`+id+`","traits":{}}`))
+
+		res := send(t, adminTS, "DELETE", "/admin/identity-schemas/"+id, http.StatusConflict, nil)
+		assert.Contains(t, res.Get("error.reason").String(), "still use it", "%s", res.Raw)
+
+		_ = send(t, adminTS, "DELETE", "/admin/identities/"+i.Get("id").String(), http.StatusNoContent, nil)
+		_ = send(t, adminTS, "DELETE", "/admin/identity-schemas/"+id, http.StatusNoContent, nil)
+		_ = send(t, adminTS, "GET", "/admin/identity-schemas/"+id, http.StatusNotFound, nil)
+		_ = send(t, publicTS, "GET", "/schemas/"+id, http.StatusNotFound, nil)
+
+		_ = send(t, adminTS, "DELETE", "/admin/identity-schemas/"+id, http.StatusNotFound, nil)
+		_ = send(t, adminTS, "POST", "/admin/identity-schemas/"+id+"/deprecate", http.StatusNotFound, nil)
+	})
+}
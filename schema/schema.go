@@ -24,6 +24,8 @@ import (
 type Schemas []Schema
 type IdentityTraitsProvider interface {
 	IdentityTraitsSchemas(ctx context.Context) (Schemas, error)
+	// IdentityTraitsSchema returns the identity schema with the given ID, or the default schema if the ID is empty.
+	IdentityTraitsSchema(ctx context.Context, id string) (*Schema, error)
 }
 
 func (s Schemas) GetByID(id string) (*Schema, error) {
@@ -101,6 +103,9 @@ type Schema struct {
 	ID     string   `json:"id"`
 	URL    *url.URL `json:"-"`
 	RawURL string   `json:"url"`
+
+	// Deprecated schemas can no longer be assigned to identities.
+	Deprecated bool `json:"-"`
 }
 
 func (s *Schema) SchemaURL(host *url.URL) *url.URL {
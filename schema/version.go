@@ -0,0 +1,145 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/herodot"
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/x/sqlxx"
+)
+
+// VersionState is the state of a stored identity schema version.
+type VersionState string
+
+const (
+	// VersionStateActive versions can be assigned to identities.
+	VersionStateActive VersionState = "active"
+	// VersionStateDeprecated versions are still served and validated, but can no longer be assigned to
+	// identities.
+	VersionStateDeprecated VersionState = "deprecated"
+)
+
+var versionNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// Identity Schema Version
+//
+// A version of an identity schema which is managed through the admin API and stored in the
+// database. Identities reference the version using its ID, for example `customer@v2`.
+//
+// swagger:model identitySchemaVersion
+type Version struct {
+	// ID is the identity schema ID identities use to reference this version.
+	//
+	// required: true
+	SchemaID string `json:"id" db:"schema_id"`
+
+	// Name is the name of the identity schema the version belongs to.
+	//
+	// required: true
+	Name string `json:"name" db:"name"`
+
+	// Version is the version number, starting at 1.
+	//
+	// required: true
+	Version int `json:"version" db:"version"`
+
+	// State is either `active` or `deprecated`. Deprecated versions can no longer be assigned to identities.
+	//
+	// required: true
+	State VersionState `json:"state" db:"state"`
+
+	// Schema is the JSON Schema of the version.
+	//
+	// required: true
+	Schema sqlxx.JSONRawMessage `json:"schema" faker:"-" db:"json_schema"`
+
+	// CreatedAt is a helper struct field for gobuffalo.pop.
+	CreatedAt time.Time `json:"created_at" faker:"-" db:"created_at"`
+
+	// UpdatedAt is a helper struct field for gobuffalo.pop.
+	UpdatedAt time.Time `json:"updated_at" faker:"-" db:"updated_at"`
+	ID        uuid.UUID `json:"-" faker:"-" db:"id"`
+	NID       uuid.UUID `json:"-" faker:"-" db:"nid"`
+}
+
+func (Version) TableName(context.Context) string {
+	return "identity_schema_versions"
+}
+
+// VersionSchemaID returns the identity schema ID of the given version of an identity schema.
+func VersionSchemaID(name string, version int) string {
+	return fmt.Sprintf("%s@v%d", name, version)
+}
+
+// ToSchema returns the version as a schema which can be used to validate identities.
+func (v *Version) ToSchema() (*Schema, error) {
+	raw := "base64://" + base64.StdEncoding.EncodeToString(v.Schema)
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &Schema{
+		ID:         v.SchemaID,
+		URL:        u,
+		RawURL:     raw,
+		Deprecated: v.State == VersionStateDeprecated,
+	}, nil
+}
+
+// Validate checks that the version has a valid name and that its schema is a JSON Schema which
+// defines the identity traits.
+func (v *Version) Validate(ctx context.Context) error {
+	if !versionNamePattern.MatchString(v.Name) {
+		return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The identity schema name %q is invalid. It must consist of 1 to 64 letters, digits, dashes, or underscores.", v.Name))
+	}
+
+	if !gjson.ValidBytes(v.Schema) || !gjson.GetBytes(v.Schema, "properties.traits").IsObject() {
+		return errors.WithStack(herodot.ErrBadRequest.WithReason("The identity schema must be a JSON Schema object which defines the `traits` property."))
+	}
+
+	const href = "identity-schema-version.json"
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(href, bytes.NewReader(v.Schema)); err != nil {
+		return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The identity schema is not a valid JSON Schema: %s", err))
+	}
+	if _, err := compiler.Compile(ctx, href); err != nil {
+		return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The identity schema is not a valid JSON Schema: %s", err))
+	}
+	return nil
+}
+
+type (
+	Persister interface {
+		// CreateIdentitySchemaVersion stores the schema as the next version of the identity schema with
+		// the version's name and sets the version's number and ID.
+		CreateIdentitySchemaVersion(ctx context.Context, v *Version) error
+		GetIdentitySchemaVersion(ctx context.Context, schemaID string) (*Version, error)
+
+		// ListIdentitySchemaVersions lists all versions ordered by name and version. If name is not
+		// empty, only the versions of that identity schema are returned.
+		ListIdentitySchemaVersions(ctx context.Context, name string) ([]Version, error)
+		UpdateIdentitySchemaVersionState(ctx context.Context, schemaID string, state VersionState) error
+
+		// DeleteIdentitySchemaVersion deletes the version. Returns herodot.ErrConflict if identities
+		// still use it.
+		DeleteIdentitySchemaVersion(ctx context.Context, schemaID string) error
+	}
+
+	PersistenceProvider interface {
+		IdentitySchemaPersister() Persister
+	}
+)
@@ -13,11 +13,19 @@ import (
 
 	"github.com/ory/kratos/x/events"
 
+	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
 
+	"github.com/ory/herodot"
+
+	"github.com/ory/kratos/continuity"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/hydra"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
+	"github.com/ory/kratos/outbox"
+	"github.com/ory/kratos/risk"
+	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/selfservice/sessiontokenexchange"
 	"github.com/ory/kratos/session"
@@ -25,6 +33,8 @@ import (
 	"github.com/ory/kratos/ui/node"
 	"github.com/ory/kratos/x"
 	"github.com/ory/x/otelx"
+	"github.com/ory/x/pointerx"
+	"github.com/ory/x/sqlcon"
 )
 
 type (
@@ -45,7 +55,11 @@ type (
 type (
 	executorDependencies interface {
 		config.Provider
+		continuity.ManagementProvider
 		hydra.Provider
+		identity.PrivilegedPoolProvider
+		identity.ManagementProvider
+		risk.AssessorProvider
 		session.ManagementProvider
 		session.PersistenceProvider
 		x.CSRFTokenGeneratorProvider
@@ -53,8 +67,10 @@ type (
 		x.LoggingProvider
 		x.TracingProvider
 		sessiontokenexchange.PersistenceProvider
+		organization.PersistenceProvider
 
 		HooksProvider
+		StrategyProvider
 	}
 	HookExecutor struct {
 		d executorDependencies
@@ -114,6 +130,176 @@ func (e *HookExecutor) handleLoginError(_ http.ResponseWriter, r *http.Request,
 	return flowError
 }
 
+// maybeLinkCredentials links credentials which were held back by a registration flow because they collided with
+// the identity which just signed in. The credentials are only linked if the login flow is the one the registration
+// flow was converted into and if the identity is the one the credentials collided with.
+//
+// It must only be called once the session was issued. If the session does not yet satisfy the required AAL, the
+// credentials are held back until the second factor was provided.
+func (e *HookExecutor) maybeLinkCredentials(w http.ResponseWriter, r *http.Request, a *Flow, i *identity.Identity, s *session.Session) error {
+	if a.Type != flow.TypeBrowser {
+		return nil
+	}
+
+	if required, _ := e.requiresAAL2(r, s, a); required {
+		return nil
+	}
+
+	ctx := r.Context()
+	var lc LinkCredentials
+	if _, err := e.d.ContinuityManager().Continue(ctx, w, r, LinkCredentialsContinuityName, continuity.WithIdentity(i), continuity.WithPayload(&lc)); errors.Is(err, &continuity.ErrNotResumable) {
+		return nil
+	} else if err != nil {
+		// The container expired or belongs to another identity. We remove it so that it does not get in the way of
+		// future sign ins.
+		e.d.Logger().
+			WithRequest(r).
+			WithError(err).
+			WithField("identity_id", i.ID).
+			Info("Not linking credentials because the continuity container could not be resumed.")
+		return e.d.ContinuityManager().Abort(ctx, w, r, LinkCredentialsContinuityName)
+	}
+
+	// The second factor is provided in a new login flow, in which case the credentials are linked as well.
+	if lc.FlowID != a.ID && a.RequestedAAL != identity.AuthenticatorAssuranceLevel2 {
+		e.d.Logger().
+			WithRequest(r).
+			WithField("identity_id", i.ID).
+			WithField("link_flow_id", lc.FlowID).
+			Info("Not linking credentials because they were held back for another login flow.")
+		return nil
+	}
+
+	strategy, err := e.d.AllLoginStrategies().Strategy(lc.CredentialsType)
+	if err != nil {
+		return err
+	}
+
+	linkable, ok := strategy.(LinkableStrategy)
+	if !ok {
+		return errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Credentials of type %s can not be linked to an identity.", lc.CredentialsType))
+	}
+
+	ident, err := e.d.PrivilegedIdentityPool().GetIdentityConfidential(ctx, i.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := linkable.Link(ctx, ident, lc.CredentialsConfig); err != nil {
+		return err
+	}
+
+	e.d.Audit().
+		WithRequest(r).
+		WithField("identity_id", i.ID).
+		WithField("credentials_type", lc.CredentialsType).
+		Info("Linked credentials to the identity after it signed in.")
+
+	return nil
+}
+
+// enforceOrganizationSSO assigns the session to the identity's organization and ensures that members of an
+// organization which requires single sign-on completed their first factor using the organization's OpenID
+// Connect provider.
+func (e *HookExecutor) enforceOrganizationSSO(ctx context.Context, i *identity.Identity, s *session.Session) error {
+	o, err := organization.FindOrganizationForIdentity(ctx, e.d.OrganizationPersister(), i.OrganizationID, i.VerifiedAddresses(), i.UnverifiedAddresses())
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	s.OrganizationID = pointerx.Ptr(o.ID)
+	if !o.RequiresSSO() {
+		return nil
+	}
+
+	// The most recent first factor is the one which was just completed, or the one which the session
+	// was issued for if this is a second factor login.
+	for k := len(s.AMR) - 1; k >= 0; k-- {
+		if s.AMR[k].AAL != identity.AuthenticatorAssuranceLevel1 {
+			continue
+		}
+		if s.AMR[k].Method == identity.CredentialsTypeOIDC && s.AMR[k].Provider == o.OIDCProvider {
+			return nil
+		}
+		break
+	}
+
+	return schema.NewLoginOrganizationSSORequiredError(o.OIDCProvider)
+}
+
+// identityStateError explains why an identity which is not active can not sign in. The reason of a suspension is
+// not shown, because it is only meant for administrators.
+func identityStateError(i *identity.Identity) error {
+	if i.IsActive() {
+		return nil
+	}
+
+	switch i.State {
+	case identity.StateSuspended:
+		if i.SuspendedUntil != nil {
+			return schema.NewLoginIdentitySuspendedUntilError(time.Time(*i.SuspendedUntil))
+		}
+		return schema.NewLoginIdentitySuspendedError()
+	case identity.StatePendingDeletion:
+		if i.DeleteAfter != nil {
+			return schema.NewLoginIdentityPendingDeletionError(time.Time(*i.DeleteAfter))
+		}
+	}
+	return schema.NewLoginIdentityInactiveError()
+}
+
+// requireStepUpIfRisky assesses the risk of logins which did not complete a second factor yet. If the risk reaches the
+// configured threshold, the session can not be used until a second factor was completed. Identities without a second
+// factor have to log in using a one-time code instead, which proves that they control one of their addresses.
+func (e *HookExecutor) requireStepUpIfRisky(r *http.Request, i *identity.Identity, s *session.Session) error {
+	ctx := r.Context()
+	conf := e.d.Config().SelfServiceFlowLoginRisk(ctx)
+	if !conf.Enabled || s.AuthenticatorAssuranceLevel >= identity.AuthenticatorAssuranceLevel2 {
+		return nil
+	}
+
+	assessment, err := e.d.LoginRiskAssessor().Assess(r, i, s)
+	if err != nil {
+		return err
+	}
+	if assessment.Score < conf.Threshold {
+		return nil
+	}
+
+	logger := e.d.Audit().
+		WithRequest(r).
+		WithField("identity_id", i.ID).
+		WithField("risk_score", assessment.Score).
+		WithField("risk_signals", assessment.SignalNames())
+
+	ident, err := e.d.PrivilegedIdentityPool().GetIdentityConfidential(ctx, i.ID)
+	if err != nil {
+		return err
+	}
+
+	if count, err := e.d.IdentityManager().CountActiveMultiFactorCredentials(ctx, ident); err != nil {
+		return err
+	} else if count > 0 {
+		s.RequiredAAL = identity.AuthenticatorAssuranceLevel2
+		logger.Info("The login looks risky and requires a second factor.")
+		return nil
+	}
+
+	if len(s.AMR) > 0 && s.AMR[len(s.AMR)-1].Method == identity.CredentialsTypeCodeAuth {
+		return nil
+	}
+
+	if e.d.Config().SelfServiceStrategy(ctx, identity.CredentialsTypeCodeAuth.String()).Enabled && e.d.Config().SelfServiceCodeStrategyPasswordlessEnabled(ctx) {
+		logger.Info("The login looks risky and requires a one-time code.")
+		return schema.NewLoginCodeRequiredError()
+	}
+
+	logger.Warn("The login looks risky but could not be challenged because the identity has no second factor and one-time code login is disabled.")
+	return nil
+}
+
 func (e *HookExecutor) PostLoginHook(
 	w http.ResponseWriter,
 	r *http.Request,
@@ -128,10 +314,22 @@ func (e *HookExecutor) PostLoginHook(
 	r = r.WithContext(ctx)
 	defer otelx.End(span, &err)
 
+	if err := identityStateError(i); err != nil {
+		return e.handleLoginError(w, r, g, a, i, err)
+	}
+
 	if err := s.Activate(r, i, e.d.Config(), time.Now().UTC()); err != nil {
 		return err
 	}
 
+	if err := e.enforceOrganizationSSO(r.Context(), i, s); err != nil {
+		return e.handleLoginError(w, r, g, a, i, err)
+	}
+
+	if err := e.requireStepUpIfRisky(r, i, s); err != nil {
+		return e.handleLoginError(w, r, g, a, i, err)
+	}
+
 	c := e.d.Config()
 	// Verify the redirect URL before we do any other processing.
 	returnTo, err := x.SecureRedirectTo(r,
@@ -181,6 +379,23 @@ func (e *HookExecutor) PostLoginHook(
 			Debug("ExecuteLoginPostHook completed successfully.")
 	}
 
+	succeeded := func(sessionID uuid.UUID) *events.LoginSucceededOpts {
+		return &events.LoginSucceededOpts{
+			SessionID:    sessionID,
+			IdentityID:   i.ID,
+			FlowType:     string(a.Type),
+			RequestedAAL: string(a.RequestedAAL),
+			IsRefresh:    a.Refresh,
+			Method:       a.Active.String(),
+			SSOProvider:  provider,
+		}
+	}
+
+	// The outbox event is written in the same transaction as the session.
+	r = r.WithContext(outbox.ContextWithSessionEvents(r.Context(), func(s *session.Session) *outbox.Event {
+		return outbox.NewLoginSucceeded(succeeded(s.ID))
+	}))
+
 	if a.Type == flow.TypeAPI {
 		if err := e.d.SessionPersister().UpsertSession(r.Context(), s); err != nil {
 			return errors.WithStack(err)
@@ -191,15 +406,7 @@ func (e *HookExecutor) PostLoginHook(
 			WithField("identity_id", i.ID).
 			Info("Identity authenticated successfully and was issued an Ory Kratos Session Token.")
 
-		trace.SpanFromContext(r.Context()).AddEvent(events.NewLoginSucceeded(r.Context(), &events.LoginSucceededOpts{
-			SessionID:    s.ID,
-			IdentityID:   i.ID,
-			FlowType:     string(a.Type),
-			RequestedAAL: string(a.RequestedAAL),
-			IsRefresh:    a.Refresh,
-			Method:       a.Active.String(),
-			SSOProvider:  provider,
-		}))
+		trace.SpanFromContext(r.Context()).AddEvent(events.NewLoginSucceeded(r.Context(), succeeded(s.ID)))
 		if handled, err := e.d.SessionManager().MaybeRedirectAPICodeFlow(w, r, a, s.ID, g); err != nil {
 			return errors.WithStack(err)
 		} else if handled {
@@ -226,11 +433,11 @@ func (e *HookExecutor) PostLoginHook(
 		WithField("session_id", s.ID).
 		Info("Identity authenticated successfully and was issued an Ory Kratos Session Cookie.")
 
-	trace.SpanFromContext(r.Context()).AddEvent(events.NewLoginSucceeded(r.Context(), &events.LoginSucceededOpts{
-		SessionID:  s.ID,
-		IdentityID: i.ID, FlowType: string(a.Type), RequestedAAL: string(a.RequestedAAL), IsRefresh: a.Refresh, Method: a.Active.String(),
-		SSOProvider: provider,
-	}))
+	if err := e.maybeLinkCredentials(w, r, a, i, classified); err != nil {
+		return err
+	}
+
+	trace.SpanFromContext(r.Context()).AddEvent(events.NewLoginSucceeded(r.Context(), succeeded(s.ID)))
 
 	if x.IsJSONRequest(r) {
 		// Browser flows rely on cookies. Adding tokens in the mix will confuse consumers.
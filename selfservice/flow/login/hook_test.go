@@ -5,11 +5,13 @@ package login_test
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/url"
 	"testing"
 	"time"
 
+	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ory/kratos/hydra"
@@ -20,12 +22,17 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/tidwall/gjson"
 
+	"github.com/ory/x/pointerx"
+	"github.com/ory/x/sqlxx"
+
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/internal"
 	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/ui/node"
 	"github.com/ory/kratos/x"
 )
 
@@ -295,3 +302,302 @@ func TestLoginExecutor(t *testing.T) {
 		})
 	}
 }
+
+func TestLoginExecutorOrganizationSSO(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/password.schema.json")
+	conf.MustSet(ctx, config.ViperKeySelfServiceBrowserDefaultReturnTo, "https://www.ory.sh/")
+
+	sso := &organization.Organization{Name: "SSO", Domains: []string{"sso.example"}, OIDCProvider: "sso-provider"}
+	require.NoError(t, reg.OrganizationPersister().CreateOrganization(ctx, sso))
+	open := &organization.Organization{Name: "Open", Domains: []string{"open.example"}}
+	require.NoError(t, reg.OrganizationPersister().CreateOrganization(ctx, open))
+
+	newIdentity := func(t *testing.T, username string) *identity.Identity {
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.Traits = identity.Traits(`{"username":"` + username + `"}`)
+		i.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{
+			Type:   identity.CredentialsTypePassword,
+			Config: []byte(`{"hashed_password":"foo"}`),
+		})
+		require.NoError(t, reg.IdentityManager().Create(ctx, i))
+		return i
+	}
+
+	newServer := func(t *testing.T, i *identity.Identity, amr session.AuthenticationMethods) *httptest.Server {
+		router := httprouter.New()
+		router.GET("/login/post", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+			loginFlow, err := login.NewFlow(conf, time.Minute, "", r, flow.TypeAPI)
+			require.NoError(t, err)
+			loginFlow.Active = amr[len(amr)-1].Method
+			loginFlow.RequestURL = x.RequestURL(r).String()
+
+			sess := session.NewInactiveSession()
+			for _, m := range amr {
+				sess.CompletedLoginForWithProvider(m.Method, m.AAL, m.Provider)
+			}
+
+			testhelpers.SelfServiceHookLoginErrorHandler(t, w, r,
+				reg.LoginHookExecutor().PostLoginHook(w, r, loginFlow.Active.ToUiNodeGroup(), loginFlow, i, sess, amr[len(amr)-1].Provider))
+		})
+
+		ts := httptest.NewServer(router)
+		t.Cleanup(ts.Close)
+		conf.MustSet(ctx, config.ViperKeyPublicBaseURL, ts.URL)
+		return ts
+	}
+
+	var (
+		password = session.AuthenticationMethod{Method: identity.CredentialsTypePassword, AAL: identity.AuthenticatorAssuranceLevel1}
+		otherIdP = session.AuthenticationMethod{Method: identity.CredentialsTypeOIDC, AAL: identity.AuthenticatorAssuranceLevel1, Provider: "other-provider"}
+		orgIdP   = session.AuthenticationMethod{Method: identity.CredentialsTypeOIDC, AAL: identity.AuthenticatorAssuranceLevel1, Provider: "sso-provider"}
+		totp     = session.AuthenticationMethod{Method: identity.CredentialsTypeTOTP, AAL: identity.AuthenticatorAssuranceLevel2}
+	)
+
+	for _, tc := range []struct {
+		d           string
+		username    string
+		amr         session.AuthenticationMethods
+		expectError bool
+		expectOrgID string
+		memberOf    *organization.Organization
+		verified    bool
+	}{
+		{d: "password login of a member of an organization requiring SSO", username: "user@sso.example", amr: session.AuthenticationMethods{password}, expectError: true},
+		{d: "login using another provider", username: "user@SSO.example", amr: session.AuthenticationMethods{otherIdP}, expectError: true},
+		{d: "login using the organization's provider", username: "user@sso.example", amr: session.AuthenticationMethods{orgIdP}, expectOrgID: sso.ID.String()},
+		{d: "second factor after the organization's provider", username: "user@sso.example", amr: session.AuthenticationMethods{orgIdP, totp}, expectOrgID: sso.ID.String()},
+		{d: "refresh using a password", username: "user@sso.example", amr: session.AuthenticationMethods{orgIdP, password}, expectError: true},
+		{d: "explicit membership requires SSO", username: "user@elsewhere.example", amr: session.AuthenticationMethods{password}, expectError: true, memberOf: sso},
+		{d: "organization without SSO", username: "user@open.example", amr: session.AuthenticationMethods{password}, expectOrgID: open.ID.String(), verified: true},
+		{d: "unverified address of an organization without SSO", username: "user@open.example", amr: session.AuthenticationMethods{password}},
+		{d: "no organization", username: "user@elsewhere.example", amr: session.AuthenticationMethods{password}},
+	} {
+		tc := tc
+		t.Run("case="+tc.d, func(t *testing.T) {
+			i := newIdentity(t, x.NewUUID().String()+tc.username)
+			if tc.memberOf != nil {
+				i.OrganizationID = &tc.memberOf.ID
+			}
+			if tc.verified {
+				i.VerifiableAddresses = []identity.VerifiableAddress{{Value: gjson.GetBytes(i.Traits, "username").String(), Verified: true}}
+			}
+
+			res, body := testhelpers.SelfServiceMakeLoginPostHookRequest(t, newServer(t, i, tc.amr), true, url.Values{})
+			if tc.expectError {
+				assert.EqualValues(t, http.StatusInternalServerError, res.StatusCode, "%s", body)
+				assert.Contains(t, body, "Your organization requires you to sign in with sso-provider.")
+				return
+			}
+
+			require.EqualValues(t, http.StatusOK, res.StatusCode, "%s", body)
+			assert.Equal(t, tc.expectOrgID, gjson.Get(body, "session.organization_id").String(), "%s", body)
+		})
+	}
+}
+
+func TestLoginExecutorIdentityState(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/password.schema.json")
+	conf.MustSet(ctx, config.ViperKeySelfServiceBrowserDefaultReturnTo, "https://www.ory.sh/")
+
+	newServer := func(t *testing.T, i *identity.Identity) *httptest.Server {
+		router := httprouter.New()
+		router.GET("/login/post", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+			loginFlow, err := login.NewFlow(conf, time.Minute, "", r, flow.TypeAPI)
+			require.NoError(t, err)
+			loginFlow.Active = identity.CredentialsTypePassword
+			loginFlow.RequestURL = x.RequestURL(r).String()
+
+			sess := session.NewInactiveSession()
+			sess.CompletedLoginFor(identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+
+			testhelpers.SelfServiceHookLoginErrorHandler(t, w, r,
+				reg.LoginHookExecutor().PostLoginHook(w, r, node.PasswordGroup, loginFlow, i, sess, ""))
+		})
+
+		ts := httptest.NewServer(router)
+		t.Cleanup(ts.Close)
+		conf.MustSet(ctx, config.ViperKeyPublicBaseURL, ts.URL)
+		return ts
+	}
+
+	until := time.Date(2100, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, tc := range []struct {
+		d         string
+		state     identity.State
+		until     *sqlxx.NullTime
+		deleteAt  *sqlxx.NullTime
+		expectMsg string
+	}{
+		{d: "inactive", state: identity.StateInactive, expectMsg: "This account was disabled."},
+		{d: "suspended", state: identity.StateSuspended, expectMsg: "This account was suspended."},
+		{d: "suspended until", state: identity.StateSuspended, until: pointerx.Ptr(sqlxx.NullTime(until)), expectMsg: "This account was suspended until Sat, 02 Jan 2100 03:04:05 UTC."},
+		{d: "pending deletion", state: identity.StatePendingDeletion, deleteAt: pointerx.Ptr(sqlxx.NullTime(until)), expectMsg: "This account will be deleted on Sat, 02 Jan 2100 03:04:05 UTC."},
+		{d: "suspension ended", state: identity.StateSuspended, until: pointerx.Ptr(sqlxx.NullTime(time.Now().Add(-time.Minute)))},
+	} {
+		tc := tc
+		t.Run("case="+tc.d, func(t *testing.T) {
+			i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+			i.Traits = identity.Traits(`{"username":"` + x.NewUUID().String() + `"}`)
+			require.NoError(t, reg.IdentityManager().Create(ctx, i))
+			i.State = tc.state
+			i.StateReason = "fraud"
+			i.SuspendedUntil = tc.until
+			i.DeleteAfter = tc.deleteAt
+
+			res, body := testhelpers.SelfServiceMakeLoginPostHookRequest(t, newServer(t, i), true, url.Values{})
+			if tc.expectMsg != "" {
+				assert.EqualValues(t, http.StatusInternalServerError, res.StatusCode, "%s", body)
+				assert.Contains(t, body, tc.expectMsg)
+				assert.NotContains(t, body, "fraud", "the reason must not be shown to the user")
+				return
+			}
+
+			require.EqualValues(t, http.StatusOK, res.StatusCode, "%s", body)
+			assert.NotEmpty(t, gjson.Get(body, "session.id").String(), "%s", body)
+		})
+	}
+}
+
+func TestLoginExecutorRisk(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/password.schema.json")
+	conf.MustSet(ctx, config.ViperKeySelfServiceBrowserDefaultReturnTo, "https://www.ory.sh/")
+	conf.MustSet(ctx, config.ViperKeySelfServiceStrategyConfig+".totp.enabled", true)
+	conf.MustSet(ctx, config.ViperKeySelfServiceLoginRiskEnabled, true)
+	conf.MustSet(ctx, config.ViperKeySessionWhoAmIAAL, string(identity.AuthenticatorAssuranceLevel1))
+	// The country is only read from requests of trusted proxies.
+	conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceTrustedProxies, []string{"127.0.0.1"})
+
+	var webhookScore int
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"score":%d}`, webhookScore)
+	}))
+	t.Cleanup(webhook.Close)
+
+	newIdentity := func(t *testing.T, withTOTP bool) *identity.Identity {
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.Traits = identity.Traits(`{"username":"` + x.NewUUID().String() + `"}`)
+		i.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{
+			Type:   identity.CredentialsTypePassword,
+			Config: []byte(`{"hashed_password":"foo"}`),
+		})
+		if withTOTP {
+			i.SetCredentials(identity.CredentialsTypeTOTP, identity.Credentials{
+				Type:        identity.CredentialsTypeTOTP,
+				Identifiers: []string{x.NewUUID().String()},
+				Config:      []byte(`{"totp_url":"otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP&issuer=Example"}`),
+			})
+		}
+		require.NoError(t, reg.IdentityManager().Create(ctx, i))
+
+		// A previous session from a known device in Germany.
+		req := x.NewTestHTTPRequest(t, "GET", "/", nil)
+		req.Header.Set("User-Agent", "known-agent")
+		req.Header.Set("Cf-Ipcountry", "DE")
+		sess, err := session.NewActiveSession(req, i, conf, time.Now().UTC(), identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+		require.NoError(t, err)
+		require.NoError(t, reg.SessionPersister().UpsertSession(ctx, sess))
+		return i
+	}
+
+	newServer := func(t *testing.T, i *identity.Identity, method identity.CredentialsType, header http.Header) *httptest.Server {
+		router := httprouter.New()
+		router.GET("/login/post", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+			for k, v := range header {
+				r.Header[k] = v
+			}
+
+			loginFlow, err := login.NewFlow(conf, time.Minute, "", r, flow.TypeAPI)
+			require.NoError(t, err)
+			loginFlow.Active = method
+			loginFlow.RequestURL = x.RequestURL(r).String()
+
+			sess := session.NewInactiveSession()
+			sess.CompletedLoginFor(method, identity.AuthenticatorAssuranceLevel1)
+
+			testhelpers.SelfServiceHookLoginErrorHandler(t, w, r,
+				reg.LoginHookExecutor().PostLoginHook(w, r, method.ToUiNodeGroup(), loginFlow, i, sess, ""))
+		})
+
+		ts := httptest.NewServer(router)
+		t.Cleanup(ts.Close)
+		conf.MustSet(ctx, config.ViperKeyPublicBaseURL, ts.URL)
+		return ts
+	}
+
+	knownDevice := http.Header{"User-Agent": {"known-agent"}, "Cf-Ipcountry": {"DE"}}
+	newDevice := http.Header{"User-Agent": {"new-agent"}, "Cf-Ipcountry": {"DE"}}
+	newCountry := http.Header{"User-Agent": {"known-agent"}, "Cf-Ipcountry": {"US"}}
+
+	for _, tc := range []struct {
+		d             string
+		withTOTP      bool
+		method        identity.CredentialsType
+		header        http.Header
+		webhookScore  int
+		codeEnabled   bool
+		expectStepUp  bool
+		expectCodeErr bool
+	}{
+		{d: "known device", withTOTP: true, header: knownDevice},
+		{d: "new device below the threshold", withTOTP: true, header: newDevice},
+		{d: "new country", withTOTP: true, header: newCountry, expectStepUp: true},
+		{d: "new device with a risky webhook score", withTOTP: true, header: newDevice, webhookScore: 20, expectStepUp: true},
+		{d: "known device with a risky webhook score", withTOTP: true, header: knownDevice, webhookScore: 60, expectStepUp: true},
+		{d: "new country without a second factor", header: newCountry, codeEnabled: true, expectCodeErr: true},
+		{d: "new country without a second factor using a one-time code", method: identity.CredentialsTypeCodeAuth, header: newCountry, codeEnabled: true},
+		{d: "new country without any way to challenge", header: newCountry},
+	} {
+		tc := tc
+		t.Run("case="+tc.d, func(t *testing.T) {
+			conf.MustSet(ctx, config.ViperKeySelfServiceLoginRiskWebhookURL, "")
+			if tc.webhookScore > 0 {
+				webhookScore = tc.webhookScore
+				conf.MustSet(ctx, config.ViperKeySelfServiceLoginRiskWebhookURL, webhook.URL)
+			}
+			conf.MustSet(ctx, config.ViperKeySelfServiceStrategyConfig+".code.enabled", tc.codeEnabled)
+			conf.MustSet(ctx, config.ViperKeyCodePasswordlessEnabled, tc.codeEnabled)
+
+			method := tc.method
+			if method == "" {
+				method = identity.CredentialsTypePassword
+			}
+
+			i := newIdentity(t, tc.withTOTP)
+			res, body := testhelpers.SelfServiceMakeLoginPostHookRequest(t, newServer(t, i, method, tc.header), true, url.Values{})
+			if tc.expectCodeErr {
+				assert.EqualValues(t, http.StatusInternalServerError, res.StatusCode, "%s", body)
+				assert.Contains(t, body, "Please sign in with a one-time code instead.")
+				return
+			}
+			require.EqualValues(t, http.StatusOK, res.StatusCode, "%s", body)
+
+			sess, err := reg.SessionPersister().GetSession(ctx, uuid.FromStringOrNil(gjson.Get(body, "session.id").String()), session.ExpandNothing)
+			require.NoError(t, err)
+
+			if tc.expectStepUp {
+				assert.Equal(t, identity.AuthenticatorAssuranceLevel2, sess.RequiredAAL)
+				assert.Empty(t, gjson.Get(body, "session.identity.id").String(), "the identity is omitted until the second factor was completed: %s", body)
+
+				req := x.NewTestHTTPRequest(t, "GET", "/sessions/whoami", nil)
+				err := reg.SessionManager().DoesSessionSatisfy(req, sess, string(identity.AuthenticatorAssuranceLevel1))
+				assert.ErrorAs(t, err, new(*session.ErrAALNotSatisfied))
+				return
+			}
+
+			assert.Empty(t, sess.RequiredAAL)
+			assert.Equal(t, i.ID.String(), gjson.Get(body, "session.identity.id").String(), "%s", body)
+		})
+	}
+}
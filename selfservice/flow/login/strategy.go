@@ -9,6 +9,8 @@ import (
 
 	"github.com/gofrs/uuid"
 
+	"github.com/ory/x/sqlxx"
+
 	"github.com/ory/kratos/session"
 
 	"github.com/pkg/errors"
@@ -27,6 +29,23 @@ type Strategy interface {
 	CompletedAuthenticationMethod(ctx context.Context) session.AuthenticationMethod
 }
 
+// LinkableStrategy is implemented by strategies whose credentials can be linked to an existing identity after the
+// user has proven ownership of that identity by signing in.
+type LinkableStrategy interface {
+	Link(ctx context.Context, i *identity.Identity, credentialsConfig sqlxx.JSONRawMessage) error
+}
+
+// LinkCredentialsContinuityName is the name of the continuity container holding the LinkCredentials payload.
+const LinkCredentialsContinuityName = "ory_kratos_login_link_credentials"
+
+// LinkCredentials are credentials which could not be used to register a new identity because they collided with an
+// existing identity. They are linked to that identity once the user signs in to it using the login flow with FlowID.
+type LinkCredentials struct {
+	FlowID            uuid.UUID                `json:"flow_id"`
+	CredentialsType   identity.CredentialsType `json:"credentials_type"`
+	CredentialsConfig sqlxx.JSONRawMessage     `json:"credentials_config"`
+}
+
 type Strategies []Strategy
 
 func (s Strategies) Strategy(id identity.CredentialsType) (Strategy, error) {
@@ -8,12 +8,17 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/ory/x/sqlcon"
+
 	"github.com/ory/kratos/x/events"
 
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
+	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/session"
 	"github.com/ory/kratos/ui/node"
@@ -58,6 +63,7 @@ type (
 		config.Provider
 		identity.ManagementProvider
 		identity.ValidationProvider
+		organization.PersistenceProvider
 		session.PersistenceProvider
 		HooksProvider
 		x.CSRFTokenGeneratorProvider
@@ -80,6 +86,23 @@ func NewHookExecutor(d executorDependencies) *HookExecutor {
 	}
 }
 
+// CheckOrganizationSSO returns an error if the recovered identity belongs to, or must sign in through, an organization
+// which requires single sign-on. A session issued by recovery would bypass the organization's provider, which also
+// manages the identity's credentials.
+func (e *HookExecutor) CheckOrganizationSSO(ctx context.Context, i *identity.Identity) error {
+	o, err := organization.FindOrganizationForIdentity(ctx, e.d.OrganizationPersister(), i.OrganizationID, i.VerifiedAddresses(), i.UnverifiedAddresses())
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if o.RequiresSSO() {
+		return schema.NewRecoveryOrganizationSSORequiredError(o.OIDCProvider)
+	}
+	return nil
+}
+
 func (e *HookExecutor) PostRecoveryHook(w http.ResponseWriter, r *http.Request, a *Flow, s *session.Session) error {
 	e.d.Logger().
 		WithRequest(r).
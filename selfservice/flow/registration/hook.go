@@ -16,11 +16,14 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/ory/x/pointerx"
 	"github.com/ory/x/sqlcon"
 
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/hydra"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
+	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/session"
 	"github.com/ory/kratos/x"
@@ -83,6 +86,7 @@ type (
 		x.LoggingProvider
 		x.WriterProvider
 		sessiontokenexchange.PersistenceProvider
+		organization.PersistenceProvider
 	}
 	HookExecutor struct {
 		d executorDependencies
@@ -96,6 +100,24 @@ func NewHookExecutor(d executorDependencies) *HookExecutor {
 	return &HookExecutor{d: d}
 }
 
+// assignOrganization adds the identity to the organization of its email domain. If the organization requires single
+// sign-on, the identity must sign up using the organization's OpenID Connect provider.
+func (e *HookExecutor) assignOrganization(ctx context.Context, ct identity.CredentialsType, provider string, i *identity.Identity) error {
+	o, err := organization.FindOrganizationForIdentity(ctx, e.d.OrganizationPersister(), i.OrganizationID, i.VerifiedAddresses(), i.UnverifiedAddresses())
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if o.RequiresSSO() && (ct != identity.CredentialsTypeOIDC || provider != o.OIDCProvider) {
+		return schema.NewRegistrationOrganizationSSORequiredError(o.OIDCProvider)
+	}
+
+	i.OrganizationID = pointerx.Ptr(o.ID)
+	return nil
+}
+
 func (e *HookExecutor) PostRegistrationHook(w http.ResponseWriter, r *http.Request, ct identity.CredentialsType, provider string, a *Flow, i *identity.Identity) error {
 	e.d.Logger().
 		WithRequest(r).
@@ -142,9 +164,16 @@ func (e *HookExecutor) PostRegistrationHook(w http.ResponseWriter, r *http.Reque
 	// We need to make sure that the identity has a valid schema before passing it down to the identity pool.
 	if err := e.d.IdentityValidator().Validate(r.Context(), i); err != nil {
 		return err
-		// We're now creating the identity because any of the hooks could trigger a "redirect" or a "session" which
-		// would imply that the identity has to exist already.
-	} else if err := e.d.IdentityManager().Create(r.Context(), i); err != nil {
+	}
+
+	// The addresses are known only after validation, which is why organization membership is resolved here.
+	if err := e.assignOrganization(r.Context(), ct, provider, i); err != nil {
+		return err
+	}
+
+	// We're now creating the identity because any of the hooks could trigger a "redirect" or a "session" which
+	// would imply that the identity has to exist already.
+	if err := e.d.IdentityManager().Create(r.Context(), i); err != nil {
 		if errors.Is(err, sqlcon.ErrUniqueViolation) {
 			// In this case the user is already registered through another method.
 			// We handle this case by returning a spcial error that is handled by
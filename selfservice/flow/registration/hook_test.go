@@ -7,6 +7,7 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/internal"
 	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/selfservice/flow/registration"
 	"github.com/ory/kratos/selfservice/hook"
@@ -243,3 +245,80 @@ func TestRegistrationExecutor(t *testing.T) {
 		})
 	}
 }
+
+func TestRegistrationExecutorOrganization(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/registration.schema.json")
+	_ = testhelpers.NewRedirTS(t, "ok", conf)
+
+	sso := &organization.Organization{Name: "SSO", Domains: []string{"sso.example"}, OIDCProvider: "sso-provider"}
+	require.NoError(t, reg.OrganizationPersister().CreateOrganization(ctx, sso))
+	open := &organization.Organization{Name: "Open", Domains: []string{"open.example"}}
+	require.NoError(t, reg.OrganizationPersister().CreateOrganization(ctx, open))
+
+	newServer := func(t *testing.T, i *identity.Identity, ct identity.CredentialsType, provider string) *httptest.Server {
+		router := httprouter.New()
+		router.GET("/registration/post", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+			a, err := registration.NewFlow(conf, time.Minute, x.FakeCSRFToken, r, flow.TypeBrowser)
+			require.NoError(t, err)
+			a.RequestURL = x.RequestURL(r).String()
+			_ = testhelpers.SelfServiceHookRegistrationErrorHandler(t, w, r, reg.RegistrationHookExecutor().PostRegistrationHook(w, r, ct, provider, a, i))
+		})
+
+		ts := httptest.NewServer(router)
+		t.Cleanup(ts.Close)
+		conf.MustSet(ctx, config.ViperKeyPublicBaseURL, ts.URL)
+		return ts
+	}
+
+	for _, tc := range []struct {
+		d           string
+		email       string
+		ct          identity.CredentialsType
+		provider    string
+		verified    bool
+		expectError bool
+		expectOrg   *organization.Organization
+	}{
+		{d: "password sign up of a member of an organization requiring SSO", email: "user@sso.example", ct: identity.CredentialsTypePassword, expectError: true},
+		{d: "sign up using another provider", email: "user@sso.example", ct: identity.CredentialsTypeOIDC, provider: "other-provider", expectError: true},
+		{d: "sign up using the organization's provider", email: "user@sso.example", ct: identity.CredentialsTypeOIDC, provider: "sso-provider", expectOrg: sso},
+		{d: "organization without SSO", email: "user@Open.example", ct: identity.CredentialsTypePassword, verified: true, expectOrg: open},
+		{d: "unverified address of an organization without SSO", email: "user@open.example", ct: identity.CredentialsTypePassword},
+		{d: "no organization", email: "user@elsewhere.example", ct: identity.CredentialsTypePassword},
+	} {
+		t.Run("case="+tc.d, func(t *testing.T) {
+			t.Cleanup(testhelpers.SelfServiceHookConfigReset(t, conf))
+			i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+			email := x.NewUUID().String() + tc.email
+			i.Traits = identity.Traits(`{"email":"` + email + `"}`)
+			if tc.verified {
+				address := identity.NewVerifiableEmailAddress(strings.ToLower(email), i.ID)
+				address.Verified = true
+				i.VerifiableAddresses = []identity.VerifiableAddress{*address}
+			}
+
+			res, body := testhelpers.SelfServiceMakeRegistrationPostHookRequest(t, newServer(t, i, tc.ct, tc.provider), false, url.Values{})
+			if tc.expectError {
+				assert.EqualValues(t, http.StatusInternalServerError, res.StatusCode, "%s", body)
+				assert.Contains(t, body, "Your organization requires you to sign up with sso-provider.")
+
+				_, err := reg.IdentityPool().GetIdentity(ctx, i.ID, identity.ExpandNothing)
+				require.Error(t, err)
+				return
+			}
+
+			assert.EqualValues(t, http.StatusOK, res.StatusCode, "%s", body)
+			assert.Equal(t, "ok", body)
+			actual, err := reg.IdentityPool().GetIdentity(ctx, i.ID, identity.ExpandNothing)
+			require.NoError(t, err)
+			if tc.expectOrg == nil {
+				assert.Nil(t, actual.OrganizationID)
+			} else {
+				require.NotNil(t, actual.OrganizationID)
+				assert.Equal(t, tc.expectOrg.ID, *actual.OrganizationID)
+			}
+		})
+	}
+}
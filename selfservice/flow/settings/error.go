@@ -43,7 +43,7 @@ type (
 
 		HandlerProvider
 		FlowPersistenceProvider
-		IdentityTraitsSchemas(ctx context.Context) (schema.Schemas, error)
+		IdentityTraitsSchema(ctx context.Context, id string) (*schema.Schema, error)
 	}
 
 	ErrorHandlerProvider interface{ SettingsFlowErrorHandler() *ErrorHandler }
@@ -215,13 +215,7 @@ func (s *ErrorHandler) WriteFlowError(
 
 	// Lookup the schema from the loaded configuration. This local schema
 	// URL is needed for sorting the UI nodes, instead of the public URL.
-	schemas, err := s.d.IdentityTraitsSchemas(r.Context())
-	if err != nil {
-		s.forward(w, r, f, err)
-		return
-	}
-
-	schema, err := schemas.GetByID(id.SchemaID)
+	schema, err := s.d.IdentityTraitsSchema(r.Context(), id.SchemaID)
 	if err != nil {
 		s.forward(w, r, f, err)
 		return
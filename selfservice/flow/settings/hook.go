@@ -20,6 +20,7 @@ import (
 	"github.com/ory/kratos/ui/node"
 
 	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
 
 	"github.com/ory/kratos/schema"
 
@@ -177,6 +178,8 @@ func (e *HookExecutor) PostSettingsHook(w http.ResponseWriter, r *http.Request,
 		f(hookOptions)
 	}
 
+	ctxUpdate.Flow.Active = sqlxx.NullString(settingsType)
+
 	for k, executor := range e.d.PostSettingsPrePersistHooks(r.Context(), settingsType) {
 		logFields := logrus.Fields{
 			"executor":          fmt.Sprintf("%T", executor),
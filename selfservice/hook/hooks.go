@@ -9,4 +9,5 @@ const (
 	KeyWebHook          = "web_hook"
 	KeyAddressVerifier  = "require_verified_address"
 	KeyVerificationUI   = "show_verification_ui"
+	KeySecurityNotifier = "notify_security_events"
 )
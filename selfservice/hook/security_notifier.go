@@ -0,0 +1,196 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/pointerx"
+
+	"github.com/ory/kratos/bruteforce"
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/courier/template"
+	"github.com/ory/kratos/courier/template/email"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/risk"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/selfservice/flow/recovery"
+	"github.com/ory/kratos/selfservice/flow/settings"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+)
+
+var (
+	_ login.PostHookExecutor               = new(SecurityNotifier)
+	_ settings.PostHookPostPersistExecutor = new(SecurityNotifier)
+	_ recovery.PostHookExecutor            = new(SecurityNotifier)
+)
+
+type (
+	securityNotifierDependencies interface {
+		config.Provider
+		bruteforce.ThrottlerProvider
+		courier.Provider
+		template.Dependencies
+		identity.PrivilegedPoolProvider
+		session.PersistenceProvider
+		x.LoggingProvider
+	}
+	// SecurityNotifier emails the owner of an account when something security-relevant happens to it: a login from a
+	// new device, a changed password, an added or removed second factor, or a completed recovery.
+	SecurityNotifier struct {
+		r securityNotifierDependencies
+	}
+)
+
+func NewSecurityNotifier(r securityNotifierDependencies) *SecurityNotifier {
+	return &SecurityNotifier{r: r}
+}
+
+func (e *SecurityNotifier) ExecuteLoginPostHook(_ http.ResponseWriter, r *http.Request, _ node.UiNodeGroup, a *login.Flow, s *session.Session) error {
+	return otelx.WithSpan(r.Context(), "selfservice.hook.SecurityNotifier.ExecuteLoginPostHook", func(ctx context.Context) error {
+		// Second factor and refresh logins complete a session which was already issued to this device.
+		if a.Refresh || a.RequestedAAL != identity.AuthenticatorAssuranceLevel1 {
+			return nil
+		}
+
+		signals, err := risk.NewDeviceEvaluator(e.r).EvaluateLoginRisk(ctx, risk.NewLogin(r.WithContext(ctx), e.r.LoginThrottler(), s.Identity, s))
+		if err != nil {
+			return err
+		}
+
+		for _, signal := range signals {
+			if signal.Name != risk.SignalNewDevice {
+				continue
+			}
+
+			device := e.device(r, s)
+			return e.notify(ctx, s.Identity.ID, func(to string, model map[string]interface{}) courier.EmailTemplate {
+				return email.NewSecurityNewDeviceLogin(e.r, &email.SecurityNewDeviceLoginModel{To: to, Identity: model, Device: device})
+			})
+		}
+		return nil
+	})
+}
+
+func (e *SecurityNotifier) ExecuteSettingsPostPersistHook(_ http.ResponseWriter, r *http.Request, a *settings.Flow, i *identity.Identity) error {
+	return otelx.WithSpan(r.Context(), "selfservice.hook.SecurityNotifier.ExecuteSettingsPostPersistHook", func(ctx context.Context) error {
+		device := e.device(r, nil)
+		method := a.Active.String()
+
+		switch identity.CredentialsType(method) {
+		case identity.CredentialsTypePassword:
+			return e.notify(ctx, i.ID, func(to string, model map[string]interface{}) courier.EmailTemplate {
+				return email.NewSecurityPasswordChanged(e.r, &email.SecurityPasswordChangedModel{To: to, Identity: model, Device: device})
+			})
+		case identity.CredentialsTypeTOTP, identity.CredentialsTypeLookup, identity.CredentialsTypeWebAuthn, identity.CredentialsTypePasskey:
+			added, err := secondFactorAdded(a, i, identity.CredentialsType(method))
+			if err != nil {
+				return err
+			}
+
+			if added {
+				return e.notify(ctx, i.ID, func(to string, model map[string]interface{}) courier.EmailTemplate {
+					return email.NewSecurityMFAAdded(e.r, &email.SecurityMFAAddedModel{To: to, Identity: model, Device: device, Method: method})
+				})
+			}
+			return e.notify(ctx, i.ID, func(to string, model map[string]interface{}) courier.EmailTemplate {
+				return email.NewSecurityMFARemoved(e.r, &email.SecurityMFARemovedModel{To: to, Identity: model, Device: device, Method: method})
+			})
+		}
+		return nil
+	})
+}
+
+func (e *SecurityNotifier) ExecutePostRecoveryHook(_ http.ResponseWriter, r *http.Request, _ *recovery.Flow, s *session.Session) error {
+	return otelx.WithSpan(r.Context(), "selfservice.hook.SecurityNotifier.ExecutePostRecoveryHook", func(ctx context.Context) error {
+		device := e.device(r, s)
+		return e.notify(ctx, s.Identity.ID, func(to string, model map[string]interface{}) courier.EmailTemplate {
+			return email.NewSecurityRecoveryUsed(e.r, &email.SecurityRecoveryUsedModel{To: to, Identity: model, Device: device})
+		})
+	})
+}
+
+// secondFactorAdded tells if the settings flow added the second factor or removed it. TOTP and lookup secrets are
+// removed by deleting their credentials, while WebAuthn and passkey credentials hold several keys, which is why
+// a key has to have been added during the flow.
+func secondFactorAdded(a *settings.Flow, i *identity.Identity, ct identity.CredentialsType) (bool, error) {
+	c, ok := i.GetCredentials(ct)
+	if !ok {
+		return false, nil
+	}
+
+	switch ct {
+	case identity.CredentialsTypeWebAuthn, identity.CredentialsTypePasskey:
+		var conf identity.CredentialsWebAuthnConfig
+		if err := json.Unmarshal(c.Config, &conf); err != nil {
+			return false, errors.WithStack(err)
+		}
+		for _, key := range conf.Credentials {
+			if !key.AddedAt.Before(a.IssuedAt) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case identity.CredentialsTypeLookup:
+		var conf identity.CredentialsLookupConfig
+		if err := json.Unmarshal(c.Config, &conf); err != nil {
+			return false, errors.WithStack(err)
+		}
+		return len(conf.RecoveryCodes) > 0, nil
+	}
+	return true, nil
+}
+
+// device describes the device of the session, or the device which sent the request if there is no session.
+func (e *SecurityNotifier) device(r *http.Request, s *session.Session) email.DeviceModel {
+	d := session.NewDevice(r)
+	if s != nil && len(s.Devices) > 0 {
+		d = s.Devices[len(s.Devices)-1]
+	}
+
+	return email.DeviceModel{
+		IPAddress: pointerx.Deref(d.IPAddress),
+		UserAgent: pointerx.Deref(d.UserAgent),
+		Location:  pointerx.Deref(d.Location),
+	}
+}
+
+// notify sends the template to every verified email address of the identity. Unverified addresses
+// are skipped, because anyone could have added them.
+func (e *SecurityNotifier) notify(ctx context.Context, id uuid.UUID, newTemplate func(to string, model map[string]interface{}) courier.EmailTemplate) error {
+	i, err := e.r.PrivilegedIdentityPool().GetIdentity(ctx, id, identity.ExpandDefault)
+	if err != nil {
+		return err
+	}
+
+	model, err := x.StructToMap(i)
+	if err != nil {
+		return err
+	}
+
+	c, err := e.r.Courier(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, address := range i.VerifiableAddresses {
+		if address.Via != identity.AddressTypeEmail || !address.Verified {
+			continue
+		}
+
+		if _, err := c.QueueEmail(ctx, newTemplate(address.Value, model)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package hook_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/x/sqlxx"
+
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/selfservice/flow/settings"
+	"github.com/ory/kratos/selfservice/hook"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+)
+
+func TestSecurityNotifier(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/verify.schema.json")
+	conf.MustSet(ctx, config.ViperKeyPublicBaseURL, "https://www.ory.sh/")
+	conf.MustSet(ctx, config.ViperKeyCourierSMTPURL, "smtp://foo@bar@dev.null/")
+
+	h := hook.NewSecurityNotifier(reg)
+
+	// newIdentity returns an identity with a verified address and an unverified address, which is
+	// the verified address prefixed with "unverified-".
+	newIdentity := func(t *testing.T) (*identity.Identity, string) {
+		address := x.NewUUID().String() + "@ory.sh"
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.Traits = identity.Traits(`{"emails":["` + address + `","unverified-` + address + `"]}`)
+		verified := identity.NewVerifiableEmailAddress(address, i.ID)
+		verified.Verified = true
+		verified.Status = identity.VerifiableAddressStatusCompleted
+		i.VerifiableAddresses = []identity.VerifiableAddress{*verified}
+		require.NoError(t, reg.IdentityManager().Create(ctx, i))
+		return i, address
+	}
+
+	newSession := func(t *testing.T, i *identity.Identity, userAgent string) *session.Session {
+		r := x.NewTestHTTPRequest(t, "POST", "/", nil)
+		r.RemoteAddr = "192.0.2.1:1234"
+		r.Header.Set("User-Agent", userAgent)
+		r.Header.Set("Cf-Ipcity", "Munich")
+		r.Header.Set("Cf-Ipcountry", "DE")
+		s, err := session.NewActiveSession(r, i, conf, time.Now().UTC(), identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+		require.NoError(t, err)
+		return s
+	}
+
+	expectMessages := func(t *testing.T, address string, expected ...courier.TemplateType) []courier.Message {
+		messages, _, _, err := reg.CourierPersister().ListMessages(ctx, courier.ListCourierMessagesParameters{Recipient: address}, nil)
+		require.NoError(t, err)
+
+		actual := make([]courier.TemplateType, len(messages))
+		for k, m := range messages {
+			actual[k] = m.TemplateType
+		}
+		assert.ElementsMatch(t, expected, actual)
+		return messages
+	}
+
+	t.Run("method=ExecuteLoginPostHook", func(t *testing.T) {
+		logIn := func(t *testing.T, i *identity.Identity, userAgent string, aal identity.AuthenticatorAssuranceLevel) {
+			s := newSession(t, i, userAgent)
+			r := x.NewTestHTTPRequest(t, "POST", "/", nil)
+			r.RemoteAddr = "192.0.2.1:1234"
+			r.Header.Set("User-Agent", userAgent)
+			r.Header.Set("Cf-Ipcountry", "DE")
+			require.NoError(t, h.ExecuteLoginPostHook(httptest.NewRecorder(), r, node.PasswordGroup, &login.Flow{RequestedAAL: aal}, s))
+			require.NoError(t, reg.SessionPersister().UpsertSession(ctx, s))
+		}
+
+		t.Run("case=first login is not notified", func(t *testing.T) {
+			i, address := newIdentity(t)
+			logIn(t, i, "agent-a", identity.AuthenticatorAssuranceLevel1)
+			expectMessages(t, address)
+		})
+
+		t.Run("case=login from a known device is not notified", func(t *testing.T) {
+			i, address := newIdentity(t)
+			logIn(t, i, "agent-a", identity.AuthenticatorAssuranceLevel1)
+			logIn(t, i, "agent-a", identity.AuthenticatorAssuranceLevel1)
+			expectMessages(t, address)
+		})
+
+		t.Run("case=second factor login is not notified", func(t *testing.T) {
+			i, address := newIdentity(t)
+			logIn(t, i, "agent-a", identity.AuthenticatorAssuranceLevel1)
+			logIn(t, i, "agent-b", identity.AuthenticatorAssuranceLevel2)
+			expectMessages(t, address)
+		})
+
+		t.Run("case=login from a new device is notified", func(t *testing.T) {
+			i, address := newIdentity(t)
+			logIn(t, i, "agent-a", identity.AuthenticatorAssuranceLevel1)
+			logIn(t, i, "agent-b", identity.AuthenticatorAssuranceLevel1)
+
+			messages := expectMessages(t, address, courier.TypeSecurityNewDeviceLogin)
+			assert.Contains(t, messages[0].Body, "Device: agent-b")
+			assert.Contains(t, messages[0].Body, "Location: Munich, DE")
+		})
+	})
+
+	t.Run("method=ExecuteSettingsPostPersistHook", func(t *testing.T) {
+		issuedAt := time.Now().UTC().Add(-time.Minute)
+		webAuthnKey := func(addedAt time.Time) identity.Credentials {
+			c, err := json.Marshal(identity.CredentialsWebAuthnConfig{Credentials: identity.CredentialsWebAuthn{{ID: []byte("key"), AddedAt: addedAt}}})
+			require.NoError(t, err)
+			return identity.Credentials{Type: identity.CredentialsTypeWebAuthn, Identifiers: []string{x.NewUUID().String()}, Config: c}
+		}
+
+		for _, tc := range []struct {
+			d           string
+			method      string
+			credentials []identity.Credentials
+			expected    []courier.TemplateType
+		}{
+			{d: "profile", method: "profile"},
+			{d: "password", method: "password", expected: []courier.TemplateType{courier.TypeSecurityPasswordChanged}},
+			{
+				d:           "totp added",
+				method:      "totp",
+				credentials: []identity.Credentials{{Type: identity.CredentialsTypeTOTP, Identifiers: []string{x.NewUUID().String()}, Config: []byte(`{"totp_url":"otpauth://totp/foo"}`)}},
+				expected:    []courier.TemplateType{courier.TypeSecurityMFAAdded},
+			},
+			{d: "totp removed", method: "totp", expected: []courier.TemplateType{courier.TypeSecurityMFARemoved}},
+			{
+				d:           "lookup secrets removed",
+				method:      "lookup_secret",
+				credentials: []identity.Credentials{{Type: identity.CredentialsTypeLookup, Identifiers: []string{x.NewUUID().String()}, Config: []byte(`{"recovery_codes":[]}`)}},
+				expected:    []courier.TemplateType{courier.TypeSecurityMFARemoved},
+			},
+			{
+				d:           "webauthn key added",
+				method:      "webauthn",
+				credentials: []identity.Credentials{webAuthnKey(time.Now().UTC())},
+				expected:    []courier.TemplateType{courier.TypeSecurityMFAAdded},
+			},
+			{
+				d:           "webauthn key removed",
+				method:      "webauthn",
+				credentials: []identity.Credentials{webAuthnKey(issuedAt.Add(-time.Hour))},
+				expected:    []courier.TemplateType{courier.TypeSecurityMFARemoved},
+			},
+		} {
+			t.Run("case="+tc.d, func(t *testing.T) {
+				i, address := newIdentity(t)
+				for _, c := range tc.credentials {
+					i.SetCredentials(c.Type, c)
+				}
+
+				r := x.NewTestHTTPRequest(t, "POST", "/", nil)
+				r.Header.Set("User-Agent", "agent-a")
+				f := &settings.Flow{Active: sqlxx.NullString(tc.method), IssuedAt: issuedAt}
+				require.NoError(t, h.ExecuteSettingsPostPersistHook(httptest.NewRecorder(), r, f, i))
+
+				messages := expectMessages(t, address, tc.expected...)
+				for _, m := range messages {
+					assert.Contains(t, m.Body, "Device: agent-a")
+					if m.TemplateType != courier.TypeSecurityPasswordChanged {
+						assert.Contains(t, m.Body, "("+tc.method+")")
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("method=ExecutePostRecoveryHook", func(t *testing.T) {
+		i, address := newIdentity(t)
+		s := newSession(t, i, "agent-a")
+
+		require.NoError(t, h.ExecutePostRecoveryHook(httptest.NewRecorder(), x.NewTestHTTPRequest(t, "POST", "/", nil), nil, s))
+		expectMessages(t, address, courier.TypeSecurityRecoveryUsed)
+		expectMessages(t, "unverified-"+address)
+	})
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/ory/herodot"
 
+	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
 	"go.opentelemetry.io/otel/attribute"
@@ -65,6 +66,7 @@ type (
 		RequestURL     string             `json:"request_url"`
 		RequestCookies map[string]string  `json:"request_cookies"`
 		Identity       *identity.Identity `json:"identity,omitempty"`
+		OrganizationID *uuid.UUID         `json:"organization_id,omitempty"`
 	}
 
 	WebHook struct {
@@ -124,6 +126,7 @@ func (e *WebHook) ExecuteLoginPostHook(_ http.ResponseWriter, req *http.Request,
 			RequestURL:     x.RequestURL(req).String(),
 			RequestCookies: cookies(req),
 			Identity:       session.Identity,
+			OrganizationID: session.OrganizationID,
 		})
 	})
 }
@@ -174,6 +177,7 @@ func (e *WebHook) ExecutePostRecoveryHook(_ http.ResponseWriter, req *http.Reque
 			RequestURL:     x.RequestURL(req).String(),
 			RequestCookies: cookies(req),
 			Identity:       session.Identity,
+			OrganizationID: session.OrganizationID,
 		})
 	})
 }
@@ -227,6 +231,7 @@ func (e *WebHook) ExecutePostRegistrationPostPersistHook(_ http.ResponseWriter,
 			RequestURL:     x.RequestURL(req).String(),
 			RequestCookies: cookies(req),
 			Identity:       session.Identity,
+			OrganizationID: session.OrganizationID,
 		})
 	})
 }
@@ -286,6 +291,9 @@ func (e *WebHook) execute(ctx context.Context, data *templateContext) error {
 	if ignoreResponse && (parseResponse || canInterrupt) {
 		return errors.WithStack(herodot.ErrInternalServerError.WithReasonf("A webhook is configured to ignore the response but also to parse the response. This is not possible."))
 	}
+	if data.OrganizationID == nil && data.Identity != nil {
+		data.OrganizationID = data.Identity.OrganizationID
+	}
 
 	makeRequest := func() (finalErr error) {
 		if ignoreResponse {
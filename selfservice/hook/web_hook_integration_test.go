@@ -42,7 +42,9 @@ import (
 
 	"github.com/ory/kratos/selfservice/flow"
 
+	"github.com/gofrs/uuid"
 	"github.com/julienschmidt/httprouter"
+	"github.com/tidwall/gjson"
 
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/x"
@@ -388,6 +390,51 @@ func TestWebHooks(t *testing.T) {
 		})
 	}
 
+	t.Run("case=includes the organization ID", func(t *testing.T) {
+		req := &http.Request{
+			Host:       "www.ory.sh",
+			Header:     map[string][]string{},
+			RequestURI: "/some_end_point",
+			Method:     http.MethodPost,
+			URL:        &url.URL{Path: "/some_end_point"},
+		}
+
+		for _, tc := range []struct {
+			uc          string
+			callWebHook func(wh *hook.WebHook, orgID uuid.UUID) error
+		}{
+			{
+				uc: "from the session",
+				callWebHook: func(wh *hook.WebHook, orgID uuid.UUID) error {
+					s := &session.Session{ID: x.NewUUID(), Identity: &identity.Identity{ID: x.NewUUID()}, OrganizationID: &orgID}
+					return wh.ExecuteLoginPostHook(nil, req, node.PasswordGroup, &login.Flow{ID: x.NewUUID()}, s)
+				},
+			},
+			{
+				uc: "from the identity",
+				callWebHook: func(wh *hook.WebHook, orgID uuid.UUID) error {
+					i := &identity.Identity{ID: x.NewUUID(), OrganizationID: &orgID}
+					return wh.ExecuteSettingsPrePersistHook(nil, req, &settings.Flow{ID: x.NewUUID()}, i)
+				},
+			},
+		} {
+			t.Run("uc="+tc.uc, func(t *testing.T) {
+				whr := &WebHookRequest{}
+				ts := newServer(webHookEndPoint(whr))
+				wh := hook.NewWebHook(&whDeps, json.RawMessage(fmt.Sprintf(`{
+					"url": "%s",
+					"method": "POST",
+					"body": "file://./stub/test_body.jsonnet",
+					"can_interrupt": true
+				}`, ts.URL+path)))
+
+				orgID := x.NewUUID()
+				require.NoError(t, tc.callWebHook(wh, orgID))
+				assert.Equal(t, orgID.String(), gjson.Get(whr.Body, "organization_id").String(), whr.Body)
+			})
+		}
+	})
+
 	webHookResponse := []byte(
 		`{
 			"messages": [{
@@ -13,6 +13,7 @@ import (
 	"github.com/ory/herodot"
 
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/text"
 )
 
 type RecoveryCodeType int
@@ -25,7 +26,7 @@ const (
 var (
 	ErrCodeNotFound          = herodot.ErrNotFound.WithReasonf("unknown code")
 	ErrCodeAlreadyUsed       = herodot.ErrBadRequest.WithReasonf("The code was already used. Please request another code.")
-	ErrCodeSubmittedTooOften = herodot.ErrBadRequest.WithReasonf("The request was submitted too often. Please request another code.")
+	ErrCodeSubmittedTooOften = herodot.ErrBadRequest.WithID(text.ErrIDSelfServiceCodeSubmittedTooOften).WithReasonf("The request was submitted too often. Please request another code.")
 )
 
 type RecoveryCode struct {
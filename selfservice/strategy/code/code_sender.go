@@ -13,6 +13,7 @@ import (
 
 	"github.com/ory/herodot"
 	"github.com/ory/kratos/courier/template/email"
+	"github.com/ory/kratos/courier/template/sms"
 
 	"github.com/ory/x/httpx"
 	"github.com/ory/x/sqlcon"
@@ -22,7 +23,9 @@ import (
 	"github.com/ory/kratos/courier"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/recovery"
+	"github.com/ory/kratos/selfservice/flow/registration"
 	"github.com/ory/kratos/selfservice/flow/verification"
 	"github.com/ory/kratos/x"
 )
@@ -40,6 +43,8 @@ type (
 
 		RecoveryCodePersistenceProvider
 		VerificationCodePersistenceProvider
+		LoginCodePersistenceProvider
+		RegistrationCodePersistenceProvider
 
 		HTTPClient(ctx context.Context, opts ...httpx.ResilientOptions) *retryablehttp.Client
 	}
@@ -227,6 +232,111 @@ func (s *Sender) SendVerificationCodeTo(ctx context.Context, f *verification.Flo
 	return s.deps.PrivilegedIdentityPool().UpdateVerifiableAddress(ctx, code.VerifiableAddress)
 }
 
+// SendLoginCode sends a one-time login code to the given address of the identity.
+func (s *Sender) SendLoginCode(ctx context.Context, f *login.Flow, i *identity.Identity, address *identity.CredentialsCodeAddress) error {
+	s.deps.Logger().
+		WithField("via", address.Channel).
+		WithSensitiveField("address", address.Address).
+		Debug("Preparing login code.")
+
+	rawCode := GenerateCode()
+	code, err := s.deps.LoginCodePersister().CreateLoginCode(ctx, &CreateLoginCodeParams{
+		RawCode:     rawCode,
+		ExpiresIn:   s.deps.Config().SelfServiceCodeMethodLifespan(ctx),
+		Address:     address.Address,
+		AddressType: address.Channel,
+		FlowID:      f.ID,
+		IdentityID:  i.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.deps.Audit().
+		WithField("via", code.AddressType).
+		WithField("identity_id", i.ID).
+		WithField("login_code_id", code.ID).
+		WithSensitiveField("address", code.Address).
+		WithSensitiveField("login_code", rawCode).
+		Info("Sending out login code.")
+
+	model, err := x.StructToMap(i)
+	if err != nil {
+		return err
+	}
+
+	if code.AddressType == identity.CodeAddressTypeSMS {
+		return s.sendSMS(ctx, sms.NewOTPMessage(s.deps, &sms.OTPMessageModel{
+			To:       code.Address,
+			Code:     rawCode,
+			Identity: model,
+		}))
+	}
+
+	return s.send(ctx, string(code.AddressType), email.NewLoginCodeValid(s.deps, &email.LoginCodeValidModel{
+		To:        code.Address,
+		LoginCode: rawCode,
+		Identity:  model,
+	}))
+}
+
+// SendRegistrationCode sends a one-time registration code to the given address. The identity is not yet
+// persisted at this point, so only its traits are passed to the template.
+func (s *Sender) SendRegistrationCode(ctx context.Context, f *registration.Flow, i *identity.Identity, address *identity.CredentialsCodeAddress) error {
+	s.deps.Logger().
+		WithField("via", address.Channel).
+		WithSensitiveField("address", address.Address).
+		Debug("Preparing registration code.")
+
+	rawCode := GenerateCode()
+	code, err := s.deps.RegistrationCodePersister().CreateRegistrationCode(ctx, &CreateRegistrationCodeParams{
+		RawCode:     rawCode,
+		ExpiresIn:   s.deps.Config().SelfServiceCodeMethodLifespan(ctx),
+		Address:     address.Address,
+		AddressType: address.Channel,
+		FlowID:      f.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.deps.Audit().
+		WithField("via", code.AddressType).
+		WithField("registration_code_id", code.ID).
+		WithSensitiveField("address", code.Address).
+		WithSensitiveField("registration_code", rawCode).
+		Info("Sending out registration code.")
+
+	model, err := x.StructToMap(i)
+	if err != nil {
+		return err
+	}
+
+	if code.AddressType == identity.CodeAddressTypeSMS {
+		return s.sendSMS(ctx, sms.NewOTPMessage(s.deps, &sms.OTPMessageModel{
+			To:       code.Address,
+			Code:     rawCode,
+			Identity: model,
+		}))
+	}
+
+	return s.send(ctx, string(code.AddressType), email.NewRegistrationCodeValid(s.deps, &email.RegistrationCodeValidModel{
+		To:               code.Address,
+		RegistrationCode: rawCode,
+		Identity:         model,
+	}))
+}
+
+func (s *Sender) sendSMS(ctx context.Context, t courier.SMSTemplate) error {
+	c, err := s.deps.Courier(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.QueueSMS(ctx, t)
+	return err
+}
+
 func (s *Sender) send(ctx context.Context, via string, t courier.EmailTemplate) error {
 	switch f := stringsx.SwitchExact(via); {
 	case f.AddCase(identity.AddressTypeEmail):
@@ -29,4 +29,24 @@ type (
 	VerificationCodePersistenceProvider interface {
 		VerificationCodePersister() VerificationCodePersister
 	}
+
+	LoginCodePersister interface {
+		CreateLoginCode(context.Context, *CreateLoginCodeParams) (*LoginCode, error)
+		UseLoginCode(ctx context.Context, fID uuid.UUID, identityID uuid.UUID, code string) (*LoginCode, error)
+		DeleteLoginCodesOfFlow(ctx context.Context, fID uuid.UUID) error
+	}
+
+	LoginCodePersistenceProvider interface {
+		LoginCodePersister() LoginCodePersister
+	}
+
+	RegistrationCodePersister interface {
+		CreateRegistrationCode(context.Context, *CreateRegistrationCodeParams) (*RegistrationCode, error)
+		UseRegistrationCode(ctx context.Context, fID uuid.UUID, code string, addresses ...string) (*RegistrationCode, error)
+		DeleteRegistrationCodesOfFlow(ctx context.Context, fID uuid.UUID) error
+	}
+
+	RegistrationCodePersistenceProvider interface {
+		RegistrationCodePersister() RegistrationCodePersister
+	}
 )
@@ -0,0 +1,99 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package code
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/flow"
+)
+
+type RegistrationCode struct {
+	// ID represents the code's unique ID.
+	//
+	// required: true
+	// type: string
+	// format: uuid
+	ID uuid.UUID `json:"id" db:"id" faker:"-"`
+
+	// CodeHMAC represents the HMACed value of the registration code
+	CodeHMAC string `json:"-" db:"code_hmac"`
+
+	// Address is the address the code was sent to.
+	// required: true
+	Address string `json:"address" db:"address"`
+
+	// AddressType is the channel the code was sent through (e.g. "email" or "sms").
+	// required: true
+	AddressType identity.CodeAddressType `json:"address_type" db:"address_type"`
+
+	// UsedAt is the timestamp of when the code was used or null if it wasn't yet
+	UsedAt sql.NullTime `json:"-" db:"used_at"`
+
+	// ExpiresAt is the time (UTC) when the code expires.
+	// required: true
+	ExpiresAt time.Time `json:"expires_at" faker:"time_type" db:"expires_at"`
+
+	// IssuedAt is the time (UTC) when the code was issued.
+	// required: true
+	IssuedAt time.Time `json:"issued_at" faker:"time_type" db:"issued_at"`
+
+	// CreatedAt is a helper struct field for gobuffalo.pop.
+	CreatedAt time.Time `json:"-" faker:"-" db:"created_at"`
+	// UpdatedAt is a helper struct field for gobuffalo.pop.
+	UpdatedAt time.Time `json:"-" faker:"-" db:"updated_at"`
+	// FlowID is a helper struct field for gobuffalo.pop.
+	FlowID uuid.UUID `json:"-" faker:"-" db:"selfservice_registration_flow_id"`
+	NID    uuid.UUID `json:"-" faker:"-" db:"nid"`
+}
+
+func (RegistrationCode) TableName(context.Context) string {
+	return "identity_registration_codes"
+}
+
+// Validate validates the state of the registration code
+//
+// - If the code is expired, `flow.ExpiredError` is returned
+// - If the code was already used `ErrCodeAlreadyUsed` is returned
+// - Otherwise, `nil` is returned
+func (f *RegistrationCode) Validate() error {
+	if f.ExpiresAt.Before(time.Now().UTC()) {
+		return errors.WithStack(flow.NewFlowExpiredError(f.ExpiresAt))
+	}
+	if f.UsedAt.Valid {
+		return errors.WithStack(ErrCodeAlreadyUsed)
+	}
+	return nil
+}
+
+func (f *RegistrationCode) GetHMACCode() string {
+	return f.CodeHMAC
+}
+
+func (f *RegistrationCode) GetID() uuid.UUID {
+	return f.ID
+}
+
+type CreateRegistrationCodeParams struct {
+	// Code represents the registration code
+	RawCode string
+
+	// ExpiresIn is the lifetime of the code
+	ExpiresIn time.Duration
+
+	// Address is the address the code is sent to
+	Address string
+
+	// AddressType is the channel the code is sent through
+	AddressType identity.CodeAddressType
+
+	// FlowID is the id of the current registration flow
+	FlowID uuid.UUID
+}
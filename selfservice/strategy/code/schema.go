@@ -12,3 +12,9 @@ var recoveryMethodSchema []byte
 
 //go:embed .schema/verification.schema.json
 var verificationMethodSchema []byte
+
+//go:embed .schema/login.schema.json
+var loginMethodSchema []byte
+
+//go:embed .schema/registration.schema.json
+var registrationMethodSchema []byte
@@ -4,12 +4,19 @@
 package code
 
 import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
 	"github.com/ory/kratos/courier"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/selfservice/errorx"
+	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/recovery"
+	"github.com/ory/kratos/selfservice/flow/registration"
 	"github.com/ory/kratos/selfservice/flow/settings"
 	"github.com/ory/kratos/selfservice/flow/verification"
 	"github.com/ory/kratos/session"
@@ -28,6 +35,10 @@ var _ verification.Strategy = new(Strategy)
 var _ verification.AdminHandler = new(Strategy)
 var _ verification.PublicHandler = new(Strategy)
 
+var _ login.Strategy = new(Strategy)
+var _ registration.Strategy = new(Strategy)
+var _ identity.ActiveCredentialsCounter = new(Strategy)
+
 type (
 	// FlowMethod contains the configuration for this selfservice strategy.
 	FlowMethod struct {
@@ -65,8 +76,13 @@ type (
 		verification.StrategyProvider
 		verification.HookExecutorProvider
 
+		login.FlowPersistenceProvider
+		registration.FlowPersistenceProvider
+
 		RecoveryCodePersistenceProvider
 		VerificationCodePersistenceProvider
+		LoginCodePersistenceProvider
+		RegistrationCodePersistenceProvider
 		SenderProvider
 
 		schema.IdentityTraitsProvider
@@ -82,6 +98,41 @@ func NewStrategy(deps strategyDependencies) *Strategy {
 	return &Strategy{deps: deps, dx: decoderx.NewHTTP()}
 }
 
+func (s *Strategy) ID() identity.CredentialsType {
+	return identity.CredentialsTypeCodeAuth
+}
+
+func (s *Strategy) NodeGroup() node.UiNodeGroup {
+	return node.CodeGroup
+}
+
+func (s *Strategy) CompletedAuthenticationMethod(ctx context.Context) session.AuthenticationMethod {
+	return session.AuthenticationMethod{
+		Method: s.ID(),
+		AAL:    identity.AuthenticatorAssuranceLevel1,
+	}
+}
+
+func (s *Strategy) CountActiveFirstFactorCredentials(cc map[identity.CredentialsType]identity.Credentials) (count int, err error) {
+	for _, c := range cc {
+		if c.Type == s.ID() && len(c.Config) > 0 {
+			var conf identity.CredentialsCode
+			if err = json.Unmarshal(c.Config, &conf); err != nil {
+				return 0, errors.WithStack(err)
+			}
+
+			if len(c.Identifiers) > 0 && len(c.Identifiers[0]) > 0 && len(conf.Addresses) > 0 {
+				count++
+			}
+		}
+	}
+	return
+}
+
+func (s *Strategy) CountActiveMultiFactorCredentials(cc map[identity.CredentialsType]identity.Credentials) (count int, err error) {
+	return 0, nil
+}
+
 func (s *Strategy) RecoveryNodeGroup() node.UiNodeGroup {
 	return node.CodeGroup
 }
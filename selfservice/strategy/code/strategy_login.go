@@ -0,0 +1,247 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package code
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/decoderx"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/selfservice/flowhelpers"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+)
+
+// Update Login flow using the code method
+//
+// swagger:model updateLoginFlowWithCodeMethod
+type updateLoginFlowWithCodeMethod struct {
+	// Method should be set to "code" when logging in using the code strategy.
+	//
+	// required: true
+	Method string `json:"method" form:"method"`
+
+	// CSRFToken is the anti-CSRF token
+	CSRFToken string `json:"csrf_token" form:"csrf_token"`
+
+	// Identifier is the email address or phone number of the identity which wants to sign in.
+	//
+	// required: true
+	Identifier string `json:"identifier" form:"identifier"`
+
+	// Code is the one-time code which was sent to the identity's address.
+	//
+	// required: false
+	Code string `json:"code" form:"code"`
+
+	// Resend is set when the user wants to receive a new code.
+	//
+	// required: false
+	Resend string `json:"resend" form:"resend"`
+}
+
+func (s *Strategy) RegisterLoginRoutes(*x.RouterPublic) {}
+
+func (s *Strategy) PopulateLoginMethod(r *http.Request, requestedAAL identity.AuthenticatorAssuranceLevel, f *login.Flow) error {
+	// This strategy can only solve AAL1
+	if requestedAAL > identity.AuthenticatorAssuranceLevel1 {
+		return nil
+	}
+
+	if !s.deps.Config().SelfServiceCodeStrategyPasswordlessEnabled(r.Context()) {
+		return nil
+	}
+
+	if f.IsForced() {
+		// We only show this method on a refresh request if the identity can sign in using a code.
+		identifier, id, _ := flowhelpers.GuessForcedLoginIdentifier(r, s.deps, f, s.ID())
+		if identifier == "" {
+			return nil
+		}
+
+		count, err := s.CountActiveFirstFactorCredentials(id.Credentials)
+		if err != nil {
+			return err
+		} else if count == 0 {
+			return nil
+		}
+
+		f.UI.SetNode(node.NewInputField("identifier", identifier, node.DefaultGroup, node.InputAttributeTypeHidden))
+	} else {
+		f.UI.SetNode(node.NewInputField("identifier", "", node.DefaultGroup, node.InputAttributeTypeText, node.WithRequiredInputAttribute).WithMetaLabel(text.NewInfoNodeLabelID()))
+	}
+
+	f.UI.SetCSRF(s.deps.GenerateCSRFToken(r))
+	f.UI.GetNodes().Append(node.NewInputField("method", s.ID(), node.CodeGroup, node.InputAttributeTypeSubmit).WithMetaLabel(text.NewInfoSelfServiceLoginCode()))
+
+	return nil
+}
+
+func (s *Strategy) handleLoginError(r *http.Request, f *login.Flow, p *updateLoginFlowWithCodeMethod, err error) error {
+	if f != nil {
+		if p != nil {
+			f.UI.Nodes.SetValueAttribute("identifier", p.Identifier)
+		}
+		if f.Type == flow.TypeBrowser {
+			f.UI.SetCSRF(s.deps.GenerateCSRFToken(r))
+		}
+	}
+
+	return err
+}
+
+func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow, _ uuid.UUID) (*identity.Identity, error) {
+	if err := login.CheckAAL(f, identity.AuthenticatorAssuranceLevel1); err != nil {
+		return nil, err
+	}
+
+	if err := flow.MethodEnabledAndAllowedFromRequest(r, s.ID().String(), s.deps); err != nil {
+		return nil, err
+	}
+
+	if !s.deps.Config().SelfServiceCodeStrategyPasswordlessEnabled(r.Context()) {
+		return nil, errors.WithStack(flow.ErrStrategyNotResponsible)
+	}
+
+	var p updateLoginFlowWithCodeMethod
+	if err := s.dx.Decode(r, &p,
+		decoderx.HTTPDecoderSetValidatePayloads(true),
+		decoderx.MustHTTPRawJSONSchemaCompiler(loginMethodSchema),
+		decoderx.HTTPDecoderJSONFollowsFormFormat()); err != nil {
+		return nil, s.handleLoginError(r, f, &p, err)
+	}
+
+	if err := flow.EnsureCSRF(s.deps, r, f.Type, s.deps.Config().DisableAPIFlowEnforcement(r.Context()), s.deps.GenerateCSRFToken, p.CSRFToken); err != nil {
+		return nil, s.handleLoginError(r, f, &p, err)
+	}
+
+	if len(p.Code) == 0 || len(p.Resend) > 0 {
+		return nil, s.loginSendCode(w, r, f, &p)
+	}
+
+	return s.loginUseCode(r, f, &p)
+}
+
+// loginSendCode sends a login code to the address matching the identifier and renders the form asking for the code.
+//
+// If no identity matches the identifier, no code is sent but the response looks the same to prevent account
+// enumeration attacks.
+func (s *Strategy) loginSendCode(w http.ResponseWriter, r *http.Request, f *login.Flow, p *updateLoginFlowWithCodeMethod) error {
+	ctx := r.Context()
+	identifier := strings.ToLower(strings.TrimSpace(p.Identifier))
+
+	if err := s.deps.LoginCodePersister().DeleteLoginCodesOfFlow(ctx, f.ID); err != nil {
+		return s.handleLoginError(r, f, p, err)
+	}
+
+	i, c, err := s.deps.PrivilegedIdentityPool().FindByCredentialsIdentifier(ctx, s.ID(), identifier)
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		s.deps.Audit().
+			WithField("strategy", "code").
+			WithSensitiveField("identifier", identifier).
+			Info("A login code was requested for an unknown identifier.")
+	} else if err != nil {
+		return s.handleLoginError(r, f, p, err)
+	} else {
+		var conf identity.CredentialsCode
+		if err := json.Unmarshal(c.Config, &conf); err != nil {
+			return s.handleLoginError(r, f, p, errors.WithStack(herodot.ErrInternalServerError.WithReason("The code credentials could not be decoded properly").WithDebug(err.Error()).WithWrap(err)))
+		}
+
+		address, ok := conf.FindAddress(identifier)
+		if !ok {
+			// Credentials created before addresses were tracked only know the identifier.
+			address = &identity.CredentialsCodeAddress{Channel: identity.CodeAddressTypeEmail, Address: identifier}
+		}
+
+		if err := s.deps.CodeSender().SendLoginCode(ctx, f, i, address); err != nil {
+			return s.handleLoginError(r, f, p, err)
+		}
+	}
+
+	f.Active = s.ID()
+	f.UI.Messages.Clear()
+	f.UI.Messages.Set(text.NewLoginCodeSent())
+
+	// Reset all nodes to not confuse users.
+	f.UI.Nodes = node.Nodes{}
+	f.UI.SetNode(node.NewInputField("identifier", p.Identifier, node.DefaultGroup, node.InputAttributeTypeHidden, node.WithRequiredInputAttribute))
+	f.UI.SetNode(node.NewInputField("code", nil, node.CodeGroup, node.InputAttributeTypeText).WithMetaLabel(text.NewInfoNodeLabelVerifyOTP()))
+	// Required for the re-send code button
+	f.UI.Nodes.Append(node.NewInputField("method", s.ID(), node.CodeGroup, node.InputAttributeTypeHidden))
+	f.UI.Nodes.Append(node.NewInputField("method", s.ID(), node.CodeGroup, node.InputAttributeTypeSubmit).WithMetaLabel(text.NewInfoNodeLabelSubmit()))
+	f.UI.Nodes.Append(node.NewInputField("resend", s.ID(), node.CodeGroup, node.InputAttributeTypeSubmit).WithMetaLabel(text.NewInfoNodeResendOTP()))
+	f.UI.SetCSRF(s.deps.GenerateCSRFToken(r))
+
+	if err := s.deps.LoginFlowPersister().UpdateLoginFlow(ctx, f); err != nil {
+		return s.handleLoginError(r, f, p, err)
+	}
+
+	if f.Type == flow.TypeBrowser && !x.IsJSONRequest(r) {
+		http.Redirect(w, r, f.AppendTo(s.deps.Config().SelfServiceFlowLoginUI(ctx)).String(), http.StatusSeeOther)
+	} else {
+		s.deps.Writer().WriteCode(w, r, http.StatusBadRequest, f)
+	}
+
+	return errors.WithStack(flow.ErrCompletedByStrategy)
+}
+
+func (s *Strategy) loginUseCode(r *http.Request, f *login.Flow, p *updateLoginFlowWithCodeMethod) (*identity.Identity, error) {
+	ctx := r.Context()
+
+	identifier := strings.ToLower(strings.TrimSpace(p.Identifier))
+	i, _, err := s.deps.PrivilegedIdentityPool().FindByCredentialsIdentifier(ctx, s.ID(), identifier)
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		time.Sleep(x.RandomDelay(s.deps.Config().HasherArgon2(ctx).ExpectedDuration, s.deps.Config().HasherArgon2(ctx).ExpectedDeviation))
+		return nil, s.handleLoginError(r, f, p, schema.NewLoginCodeInvalid())
+	} else if err != nil {
+		return nil, s.handleLoginError(r, f, p, err)
+	}
+
+	loginCode, err := s.deps.LoginCodePersister().UseLoginCode(ctx, f.ID, i.ID, p.Code)
+	if errors.Is(err, ErrCodeSubmittedTooOften) {
+		return nil, s.handleLoginError(r, f, p, schema.NewLoginCodeSubmittedTooOftenError())
+	} else if errors.Is(err, ErrCodeNotFound) || errors.Is(err, ErrCodeAlreadyUsed) {
+		return nil, s.handleLoginError(r, f, p, schema.NewLoginCodeInvalid())
+	} else if err != nil {
+		return nil, s.handleLoginError(r, f, p, err)
+	}
+
+	// Receiving the code proves ownership of the address, so we can mark it as verified.
+	for k := range i.VerifiableAddresses {
+		va := &i.VerifiableAddresses[k]
+		if va.Verified || va.Value != loginCode.Address {
+			continue
+		}
+
+		va.Verified = true
+		verifiedAt := sqlxx.NullTime(time.Now().UTC())
+		va.VerifiedAt = &verifiedAt
+		va.Status = identity.VerifiableAddressStatusCompleted
+		if err := s.deps.PrivilegedIdentityPool().UpdateVerifiableAddress(ctx, va); err != nil {
+			return nil, s.handleLoginError(r, f, p, err)
+		}
+	}
+
+	f.Active = s.ID()
+	if err := s.deps.LoginFlowPersister().UpdateLoginFlow(ctx, f); err != nil {
+		return nil, s.handleLoginError(r, f, p, errors.WithStack(herodot.ErrInternalServerError.WithReason("Could not update flow").WithDebug(err.Error())))
+	}
+
+	return i, nil
+}
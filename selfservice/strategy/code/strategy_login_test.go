@@ -0,0 +1,195 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package code_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	kratos "github.com/ory/kratos/internal/httpclient"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/x"
+)
+
+func initPasswordlessViper(t *testing.T, ctx context.Context, c *config.Config) {
+	initViper(t, ctx, c)
+	testhelpers.SetDefaultIdentitySchema(c, "file://./stub/code.identity.schema.json")
+	c.MustSet(ctx, config.ViperKeySelfServiceStrategyConfig+"."+identity.CredentialsTypeCodeAuth.String()+".enabled", true)
+	c.MustSet(ctx, config.ViperKeyCodePasswordlessEnabled, true)
+}
+
+func TestLoginCodeStrategy(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	initPasswordlessViper(t, ctx, conf)
+
+	_ = testhelpers.NewLoginUIFlowEchoServer(t, reg)
+	_ = testhelpers.NewErrorTestServer(t, reg)
+	public, _ := testhelpers.NewKratosServerWithCSRF(t, reg)
+
+	createIdentity := func(t *testing.T) string {
+		email := testhelpers.RandomEmail()
+		i := &identity.Identity{
+			ID:       x.NewUUID(),
+			Traits:   identity.Traits(fmt.Sprintf(`{"email":%q}`, email)),
+			SchemaID: config.DefaultIdentityTraitsSchemaID,
+		}
+		require.NoError(t, reg.IdentityManager().Create(ctx, i))
+		return email
+	}
+
+	for _, tc := range []struct {
+		d     string
+		isAPI bool
+		isSPA bool
+	}{
+		{d: "api", isAPI: true},
+		{d: "spa", isSPA: true},
+	} {
+		t.Run("type="+tc.d, func(t *testing.T) {
+			initFlow := func(t *testing.T) (*http.Client, *kratos.LoginFlow) {
+				if tc.isAPI {
+					hc := testhelpers.NewDebugClient(t)
+					return hc, testhelpers.InitializeLoginFlowViaAPI(t, hc, public, false)
+				}
+				hc := testhelpers.NewClientWithCookies(t)
+				return hc, testhelpers.InitializeLoginFlowViaBrowser(t, hc, public, false, true, false, false)
+			}
+
+			submit := func(t *testing.T, hc *http.Client, f *kratos.LoginFlow, values func(v url.Values)) (string, *http.Response) {
+				payload := testhelpers.SDKFormFieldsToURLValues(f.Ui.Nodes)
+				payload.Set("method", "code")
+				values(payload)
+				return testhelpers.LoginMakeRequest(t, tc.isAPI, tc.isSPA, f, hc, testhelpers.EncodeFormAsJSON(t, tc.isAPI, payload))
+			}
+
+			t.Run("case=should render the code method", func(t *testing.T) {
+				_, f := initFlow(t)
+				body, err := f.MarshalJSON()
+				require.NoError(t, err)
+				assert.Equal(t, "code", gjson.GetBytes(body, "ui.nodes.#(attributes.value==code).group").String(), "%s", body)
+			})
+
+			t.Run("case=should sign in with a code", func(t *testing.T) {
+				email := createIdentity(t)
+				hc, f := initFlow(t)
+
+				body, res := submit(t, hc, f, func(v url.Values) {
+					v.Set("identifier", email)
+				})
+				require.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+				assert.EqualValues(t, text.InfoSelfServiceLoginCodeSent, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+				assert.True(t, gjson.Get(body, "ui.nodes.#(attributes.name==code)").Exists(), "%s", body)
+
+				message := testhelpers.CourierExpectMessage(t, reg, email, "Your login code")
+				loginCode := testhelpers.CourierExpectCodeInMessage(t, message, 1)
+
+				body, res = submit(t, hc, f, func(v url.Values) {
+					v.Set("identifier", email)
+					v.Set("code", loginCode)
+				})
+				require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+				assert.Equal(t, email, gjson.Get(body, "session.identity.traits.email").String(), "%s", body)
+				assert.Equal(t, "code", gjson.Get(body, "session.authentication_methods.0.method").String(), "%s", body)
+				assert.True(t, gjson.Get(body, "session.identity.verifiable_addresses.0.verified").Bool(), "%s", body)
+			})
+
+			t.Run("case=should not accept an invalid code", func(t *testing.T) {
+				email := createIdentity(t)
+				hc, f := initFlow(t)
+
+				body, res := submit(t, hc, f, func(v url.Values) {
+					v.Set("identifier", email)
+				})
+				require.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+
+				body, res = submit(t, hc, f, func(v url.Values) {
+					v.Set("identifier", email)
+					v.Set("code", "000000")
+				})
+				require.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+				assert.EqualValues(t, text.ErrorValidationLoginCodeInvalidOrAlreadyUsed, gjson.Get(body, "ui.nodes.#(attributes.name==code).messages.0.id").Int(), "%s", body)
+			})
+
+			t.Run("case=should normalize the identifier when using the code", func(t *testing.T) {
+				email := createIdentity(t)
+				hc, f := initFlow(t)
+
+				body, res := submit(t, hc, f, func(v url.Values) {
+					v.Set("identifier", email)
+				})
+				require.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+
+				message := testhelpers.CourierExpectMessage(t, reg, email, "Your login code")
+				loginCode := testhelpers.CourierExpectCodeInMessage(t, message, 1)
+
+				body, res = submit(t, hc, f, func(v url.Values) {
+					v.Set("identifier", " "+strings.ToUpper(email)+" ")
+					v.Set("code", loginCode)
+				})
+				require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+				assert.Equal(t, email, gjson.Get(body, "session.identity.traits.email").String(), "%s", body)
+			})
+
+			t.Run("case=should show an error if the code was submitted too often", func(t *testing.T) {
+				email := createIdentity(t)
+				hc, f := initFlow(t)
+
+				body, res := submit(t, hc, f, func(v url.Values) {
+					v.Set("identifier", email)
+				})
+				require.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+
+				for i := 0; i < 6; i++ {
+					body, res = submit(t, hc, f, func(v url.Values) {
+						v.Set("identifier", email)
+						v.Set("code", "000000")
+					})
+					require.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+				}
+
+				body, res = submit(t, hc, f, func(v url.Values) {
+					v.Set("identifier", email)
+					v.Set("code", "000000")
+				})
+				require.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+				assert.EqualValues(t, text.ErrorValidationLoginCodeSubmittedTooOften, gjson.Get(body, "ui.nodes.#(attributes.name==code).messages.0.id").Int(), "%s", body)
+			})
+
+			t.Run("case=should not leak whether an identifier is unknown", func(t *testing.T) {
+				hc, f := initFlow(t)
+
+				body, res := submit(t, hc, f, func(v url.Values) {
+					v.Set("identifier", testhelpers.RandomEmail())
+				})
+				require.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+				assert.EqualValues(t, text.InfoSelfServiceLoginCodeSent, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+			})
+		})
+	}
+
+	t.Run("case=should not render the code method if passwordless login is disabled", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeyCodePasswordlessEnabled, false)
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeyCodePasswordlessEnabled, true)
+		})
+
+		f := testhelpers.InitializeLoginFlowViaAPI(t, testhelpers.NewDebugClient(t), public, false)
+		for _, n := range f.Ui.Nodes {
+			assert.NotEqual(t, "code", n.Group)
+		}
+	})
+}
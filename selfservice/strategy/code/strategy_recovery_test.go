@@ -45,6 +45,7 @@ import (
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/internal"
 	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/selfservice/flow/recovery"
 	"github.com/ory/kratos/selfservice/strategy/code"
@@ -751,6 +752,25 @@ func TestRecovery(t *testing.T) {
 		}
 	})
 
+	t.Run("description=should not be able to recover an account of an organization requiring SSO", func(t *testing.T) {
+		require.NoError(t, reg.OrganizationPersister().CreateOrganization(ctx, &organization.Organization{Name: "SSO", Domains: []string{"recovery-sso.example"}, OIDCProvider: "sso-provider"}))
+		email := "recoverme@recovery-sso.example"
+		createIdentityToRecover(t, reg, email)
+
+		client := testhelpers.NewClientWithCookies(t)
+		body := submitRecovery(t, client, RecoveryFlowTypeBrowser, func(v url.Values) {
+			v.Set("email", email)
+		}, http.StatusOK)
+		message := testhelpers.CourierExpectMessage(t, reg, email, "Recover access to your account")
+		body = submitRecoveryCode(t, client, body, RecoveryFlowTypeBrowser, testhelpers.CourierExpectCodeInMessage(t, message, 1), http.StatusOK)
+		assert.EqualValues(t, text.ErrorValidationRecoveryOrganizationSSORequired, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+
+		res, err := client.Get(public.URL + session.RouteWhoami)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode, "recovery must not issue a session")
+	})
+
 	t.Run("description=should recover and invalidate all other sessions if hook is set", func(t *testing.T) {
 		conf.MustSet(ctx, config.HookStrategyKey(config.ViperKeySelfServiceRecoveryAfter, config.HookGlobal), []config.SelfServiceHook{{Name: "revoke_active_sessions"}})
 		t.Cleanup(func() {
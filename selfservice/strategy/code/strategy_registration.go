@@ -0,0 +1,222 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package code
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/sqlxx"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/registration"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/container"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+)
+
+// Update Registration Flow with Code Method
+//
+// swagger:model updateRegistrationFlowWithCodeMethod
+type updateRegistrationFlowWithCodeMethod struct {
+	// The identity's traits
+	//
+	// required: true
+	Traits json.RawMessage `json:"traits" form:"traits"`
+
+	// The one-time code which was sent to the identity's address.
+	//
+	// required: false
+	Code string `json:"code" form:"code"`
+
+	// Resend is set when the user wants to receive a new code.
+	//
+	// required: false
+	Resend string `json:"resend" form:"resend"`
+
+	// The CSRF Token
+	CSRFToken string `json:"csrf_token" form:"csrf_token"`
+
+	// Method to use
+	//
+	// This field must be set to `code` when using the code method.
+	//
+	// required: true
+	Method string `json:"method" form:"method"`
+
+	// Transient data to pass along to any webhooks
+	//
+	// required: false
+	TransientPayload json.RawMessage `json:"transient_payload,omitempty" form:"transient_payload"`
+}
+
+func (s *Strategy) RegisterRegistrationRoutes(*x.RouterPublic) {}
+
+func (s *Strategy) PopulateRegistrationMethod(r *http.Request, f *registration.Flow) error {
+	if !s.deps.Config().SelfServiceCodeStrategyPasswordlessEnabled(r.Context()) {
+		return nil
+	}
+
+	ds, err := s.deps.Config().DefaultIdentityTraitsSchemaURL(r.Context())
+	if err != nil {
+		return err
+	}
+
+	nodes, err := container.NodesFromJSONSchema(r.Context(), node.DefaultGroup, ds.String(), "", nil)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range nodes {
+		f.UI.SetNode(n)
+	}
+
+	f.UI.SetCSRF(s.deps.GenerateCSRFToken(r))
+	f.UI.Nodes.Append(node.NewInputField("method", s.ID(), node.CodeGroup, node.InputAttributeTypeSubmit).WithMetaLabel(text.NewInfoSelfServiceRegistrationRegisterCode()))
+
+	return nil
+}
+
+func (s *Strategy) handleRegistrationError(r *http.Request, f *registration.Flow, p *updateRegistrationFlowWithCodeMethod, err error) error {
+	if f != nil {
+		if p != nil {
+			for _, n := range container.NewFromJSON("", node.DefaultGroup, p.Traits, "traits").Nodes {
+				// we only set the value and not the whole field because we want to keep types from the initial form generation
+				f.UI.Nodes.SetValueAttribute(n.ID(), n.Attributes.GetValue())
+			}
+		}
+
+		if f.Type == flow.TypeBrowser {
+			f.UI.SetCSRF(s.deps.GenerateCSRFToken(r))
+		}
+	}
+
+	return err
+}
+
+func (s *Strategy) Register(w http.ResponseWriter, r *http.Request, f *registration.Flow, i *identity.Identity) error {
+	if err := flow.MethodEnabledAndAllowedFromRequest(r, s.ID().String(), s.deps); err != nil {
+		return err
+	}
+
+	if !s.deps.Config().SelfServiceCodeStrategyPasswordlessEnabled(r.Context()) {
+		return errors.WithStack(flow.ErrStrategyNotResponsible)
+	}
+
+	var p updateRegistrationFlowWithCodeMethod
+	if err := registration.DecodeBody(&p, r, s.dx, s.deps.Config(), registrationMethodSchema); err != nil {
+		return s.handleRegistrationError(r, f, &p, err)
+	}
+
+	f.TransientPayload = p.TransientPayload
+
+	if err := flow.EnsureCSRF(s.deps, r, f.Type, s.deps.Config().DisableAPIFlowEnforcement(r.Context()), s.deps.GenerateCSRFToken, p.CSRFToken); err != nil {
+		return s.handleRegistrationError(r, f, &p, err)
+	}
+
+	if len(p.Traits) == 0 {
+		p.Traits = json.RawMessage("{}")
+	}
+
+	i.Traits = identity.Traits(p.Traits)
+	if err := s.deps.IdentityValidator().Validate(r.Context(), i); err != nil {
+		return s.handleRegistrationError(r, f, &p, err)
+	}
+
+	c, ok := i.GetCredentials(s.ID())
+	if !ok || len(c.Identifiers) == 0 {
+		return s.handleRegistrationError(r, f, &p, schema.NewMissingIdentifierError())
+	}
+
+	var conf identity.CredentialsCode
+	if err := json.Unmarshal(c.Config, &conf); err != nil {
+		return s.handleRegistrationError(r, f, &p, errors.WithStack(herodot.ErrInternalServerError.WithReason("The code credentials could not be decoded properly").WithDebug(err.Error()).WithWrap(err)))
+	} else if len(conf.Addresses) == 0 {
+		return s.handleRegistrationError(r, f, &p, schema.NewMissingIdentifierError())
+	}
+
+	if len(p.Code) == 0 || len(p.Resend) > 0 {
+		return s.registrationSendCode(w, r, f, &p, i, &conf)
+	}
+
+	return s.registrationUseCode(r, f, &p, i, &conf)
+}
+
+// registrationSendCode sends a registration code to the identity's first code address and renders the form asking
+// for the code. The traits are kept as hidden fields so that they are submitted together with the code.
+func (s *Strategy) registrationSendCode(w http.ResponseWriter, r *http.Request, f *registration.Flow, p *updateRegistrationFlowWithCodeMethod, i *identity.Identity, conf *identity.CredentialsCode) error {
+	ctx := r.Context()
+
+	if err := s.deps.RegistrationCodePersister().DeleteRegistrationCodesOfFlow(ctx, f.ID); err != nil {
+		return s.handleRegistrationError(r, f, p, err)
+	}
+
+	if err := s.deps.CodeSender().SendRegistrationCode(ctx, f, i, &conf.Addresses[0]); err != nil {
+		return s.handleRegistrationError(r, f, p, err)
+	}
+
+	f.Active = s.ID()
+	f.UI.Messages.Clear()
+	f.UI.Messages.Set(text.NewRegistrationCodeSent())
+
+	// Reset all nodes to not confuse users.
+	f.UI.Nodes = node.Nodes{}
+	for _, n := range container.NewFromJSON("", node.DefaultGroup, p.Traits, "traits").Nodes {
+		f.UI.SetNode(node.NewInputField(n.ID(), n.Attributes.GetValue(), node.DefaultGroup, node.InputAttributeTypeHidden))
+	}
+	f.UI.SetNode(node.NewInputField("code", nil, node.CodeGroup, node.InputAttributeTypeText).WithMetaLabel(text.NewInfoNodeLabelVerifyOTP()))
+	// Required for the re-send code button
+	f.UI.Nodes.Append(node.NewInputField("method", s.ID(), node.CodeGroup, node.InputAttributeTypeHidden))
+	f.UI.Nodes.Append(node.NewInputField("method", s.ID(), node.CodeGroup, node.InputAttributeTypeSubmit).WithMetaLabel(text.NewInfoNodeLabelSubmit()))
+	f.UI.Nodes.Append(node.NewInputField("resend", s.ID(), node.CodeGroup, node.InputAttributeTypeSubmit).WithMetaLabel(text.NewInfoNodeResendOTP()))
+	f.UI.SetCSRF(s.deps.GenerateCSRFToken(r))
+
+	if err := s.deps.RegistrationFlowPersister().UpdateRegistrationFlow(ctx, f); err != nil {
+		return s.handleRegistrationError(r, f, p, err)
+	}
+
+	if f.Type == flow.TypeBrowser && !x.IsJSONRequest(r) {
+		http.Redirect(w, r, f.AppendTo(s.deps.Config().SelfServiceFlowRegistrationUI(ctx)).String(), http.StatusSeeOther)
+	} else {
+		s.deps.Writer().WriteCode(w, r, http.StatusBadRequest, f)
+	}
+
+	return errors.WithStack(flow.ErrCompletedByStrategy)
+}
+
+func (s *Strategy) registrationUseCode(r *http.Request, f *registration.Flow, p *updateRegistrationFlowWithCodeMethod, i *identity.Identity, conf *identity.CredentialsCode) error {
+	addresses := make([]string, len(conf.Addresses))
+	for k := range conf.Addresses {
+		addresses[k] = conf.Addresses[k].Address
+	}
+
+	registrationCode, err := s.deps.RegistrationCodePersister().UseRegistrationCode(r.Context(), f.ID, p.Code, addresses...)
+	if errors.Is(err, ErrCodeNotFound) || errors.Is(err, ErrCodeAlreadyUsed) {
+		return s.handleRegistrationError(r, f, p, schema.NewRegistrationCodeInvalid())
+	} else if err != nil {
+		return s.handleRegistrationError(r, f, p, err)
+	}
+
+	// Receiving the code proves ownership of the address, so we can mark it as verified.
+	for k := range i.VerifiableAddresses {
+		va := &i.VerifiableAddresses[k]
+		if va.Value != registrationCode.Address {
+			continue
+		}
+
+		va.Verified = true
+		verifiedAt := sqlxx.NullTime(time.Now().UTC())
+		va.VerifiedAt = &verifiedAt
+		va.Status = identity.VerifiableAddressStatusCompleted
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package code_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/internal"
+	kratos "github.com/ory/kratos/internal/httpclient"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/text"
+)
+
+func TestRegistrationCodeStrategy(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	initPasswordlessViper(t, ctx, conf)
+
+	_ = testhelpers.NewRegistrationUIFlowEchoServer(t, reg)
+	_ = testhelpers.NewErrorTestServer(t, reg)
+	public, _ := testhelpers.NewKratosServerWithCSRF(t, reg)
+
+	for _, tc := range []struct {
+		d     string
+		isAPI bool
+		isSPA bool
+	}{
+		{d: "api", isAPI: true},
+		{d: "spa", isSPA: true},
+	} {
+		t.Run("type="+tc.d, func(t *testing.T) {
+			initFlow := func(t *testing.T) (*http.Client, *kratos.RegistrationFlow) {
+				if tc.isAPI {
+					hc := testhelpers.NewDebugClient(t)
+					return hc, testhelpers.InitializeRegistrationFlowViaAPI(t, hc, public)
+				}
+				hc := testhelpers.NewClientWithCookies(t)
+				return hc, testhelpers.InitializeRegistrationFlowViaBrowser(t, hc, public, true, false, false)
+			}
+
+			submit := func(t *testing.T, hc *http.Client, f *kratos.RegistrationFlow, values func(v url.Values)) (string, *http.Response) {
+				payload := testhelpers.SDKFormFieldsToURLValues(f.Ui.Nodes)
+				payload.Set("method", "code")
+				payload.Del("password")
+				values(payload)
+				return testhelpers.RegistrationMakeRequest(t, tc.isAPI, tc.isSPA, f, hc, testhelpers.EncodeFormAsJSON(t, tc.isAPI, payload))
+			}
+
+			t.Run("case=should sign up with a code", func(t *testing.T) {
+				email := testhelpers.RandomEmail()
+				hc, f := initFlow(t)
+
+				body, res := submit(t, hc, f, func(v url.Values) {
+					v.Set("traits.email", email)
+				})
+				require.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+				assert.EqualValues(t, text.InfoSelfServiceRegistrationCodeSent, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+				assert.Equal(t, "hidden", gjson.Get(body, "ui.nodes.#(attributes.name==traits.email).attributes.type").String(), "%s", body)
+
+				message := testhelpers.CourierExpectMessage(t, reg, email, "Complete your account registration")
+				registrationCode := testhelpers.CourierExpectCodeInMessage(t, message, 1)
+
+				body, res = submit(t, hc, f, func(v url.Values) {
+					v.Set("traits.email", email)
+					v.Set("code", registrationCode)
+				})
+				require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+				assert.Equal(t, email, gjson.Get(body, "identity.traits.email").String(), "%s", body)
+				assert.True(t, gjson.Get(body, "identity.verifiable_addresses.0.verified").Bool(), "%s", body)
+			})
+
+			t.Run("case=should not accept a code sent to another address", func(t *testing.T) {
+				email := testhelpers.RandomEmail()
+				hc, f := initFlow(t)
+
+				body, res := submit(t, hc, f, func(v url.Values) {
+					v.Set("traits.email", email)
+				})
+				require.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+
+				message := testhelpers.CourierExpectMessage(t, reg, email, "Complete your account registration")
+				registrationCode := testhelpers.CourierExpectCodeInMessage(t, message, 1)
+
+				body, res = submit(t, hc, f, func(v url.Values) {
+					v.Set("traits.email", testhelpers.RandomEmail())
+					v.Set("code", registrationCode)
+				})
+				require.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+				assert.EqualValues(t, text.ErrorValidationRegistrationCodeInvalidOrAlreadyUsed, gjson.Get(body, "ui.nodes.#(attributes.name==code).messages.0.id").Int(), "%s", body)
+			})
+		})
+	}
+}
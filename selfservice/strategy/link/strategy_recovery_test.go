@@ -51,6 +51,7 @@ import (
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/internal"
 	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/selfservice/flow/recovery"
 	"github.com/ory/kratos/text"
 	"github.com/ory/kratos/x"
@@ -502,6 +503,28 @@ func TestRecovery(t *testing.T) {
 		})
 	})
 
+	t.Run("description=should not be able to recover an account of an organization requiring SSO", func(t *testing.T) {
+		require.NoError(t, reg.OrganizationPersister().CreateOrganization(ctx, &organization.Organization{Name: "SSO", Domains: []string{"recovery-sso.example"}, OIDCProvider: "sso-provider"}))
+		email := "recoverme@recovery-sso.example"
+		createIdentityToRecover(t, reg, email)
+		expectSuccess(t, nil, false, false, func(v url.Values) {
+			v.Set("email", email)
+		})
+
+		cl := testhelpers.NewClientWithCookies(t)
+		res, err := cl.Get(testhelpers.CourierExpectLinkInMessage(t, testhelpers.CourierExpectMessage(t, reg, email, "Recover access to your account"), 1))
+		require.NoError(t, err)
+		body := ioutilx.MustReadAll(res.Body)
+		require.NoError(t, res.Body.Close())
+		assert.Contains(t, res.Request.URL.String(), conf.SelfServiceFlowRecoveryUI(ctx).String())
+		assert.EqualValues(t, text.ErrorValidationRecoveryOrganizationSSORequired, gjson.GetBytes(body, "ui.messages.0.id").Int(), "%s", body)
+
+		res, err = cl.Get(public.URL + session.RouteWhoami)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode, "recovery must not issue a session")
+	})
+
 	t.Run("description=should recover an account", func(t *testing.T) {
 		var check = func(t *testing.T, recoverySubmissionResponse, recoveryEmail, returnTo string) {
 			addr, err := reg.IdentityPool().FindVerifiableAddressByValue(context.Background(), identity.VerifiableAddressTypeEmail, recoveryEmail)
@@ -110,6 +110,10 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 		return nil, s.handleLoginError(r, f, err)
 	}
 
+	if err := s.d.LoginThrottler().Check(r, identityID.String()); err != nil {
+		return nil, s.handleLoginError(r, f, err)
+	}
+
 	i, c, err := s.d.PrivilegedIdentityPool().FindByCredentialsIdentifier(r.Context(), s.ID(), identityID.String())
 	if errors.Is(err, sqlcon.ErrNoRows) {
 		return nil, s.handleLoginError(r, f, errors.WithStack(schema.NewNoLookupDefined()))
@@ -135,9 +139,16 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 	}
 
 	if !found {
+		if err := s.d.LoginThrottler().RecordFailure(r, identityID.String()); err != nil {
+			return nil, s.handleLoginError(r, f, err)
+		}
 		return nil, s.handleLoginError(r, f, errors.WithStack(schema.NewErrorValidationLookupInvalid()))
 	}
 
+	if err := s.d.LoginThrottler().RecordSuccess(r, identityID.String()); err != nil {
+		return nil, s.handleLoginError(r, f, err)
+	}
+
 	toUpdate, err := s.d.PrivilegedIdentityPool().GetIdentityConfidential(r.Context(), identityID)
 	if err != nil {
 		return nil, err
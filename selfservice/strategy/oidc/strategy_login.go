@@ -5,6 +5,7 @@ package oidc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"time"
@@ -17,6 +18,8 @@ import (
 
 	"github.com/ory/kratos/ui/node"
 	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
+	"github.com/ory/x/stringslice"
 
 	"github.com/ory/kratos/selfservice/flow/registration"
 
@@ -35,6 +38,7 @@ import (
 )
 
 var _ login.Strategy = new(Strategy)
+var _ login.LinkableStrategy = new(Strategy)
 
 func (s *Strategy) RegisterLoginRoutes(r *x.RouterPublic) {
 	s.setRoutes(r)
@@ -221,3 +225,38 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 
 	return nil, errors.WithStack(flow.ErrCompletedByStrategy)
 }
+
+// Link adds the OpenID Connect providers of the given credentials configuration to the identity's OpenID Connect
+// credentials and persists the identity.
+func (s *Strategy) Link(ctx context.Context, i *identity.Identity, credentialsConfig sqlxx.JSONRawMessage) error {
+	var toLink identity.CredentialsOIDC
+	if err := json.Unmarshal(credentialsConfig, &toLink); err != nil {
+		return errors.WithStack(herodot.ErrInternalServerError.WithReason("The OpenID Connect credentials could not be decoded properly").WithDebug(err.Error()).WithWrap(err))
+	}
+
+	var conf identity.CredentialsOIDC
+	creds, err := i.ParseCredentials(s.ID(), &conf)
+	if errors.Is(err, herodot.ErrNotFound) {
+		creds = &identity.Credentials{Type: s.ID()}
+	} else if err != nil {
+		return err
+	}
+
+	for _, p := range toLink.Providers {
+		id := identity.OIDCUniqueID(p.Provider, p.Subject)
+		if stringslice.Has(creds.Identifiers, id) {
+			continue
+		}
+
+		creds.Identifiers = append(creds.Identifiers, id)
+		conf.Providers = append(conf.Providers, p)
+	}
+
+	creds.Config, err = json.Marshal(conf)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	i.SetCredentials(s.ID(), *creds)
+	return s.d.IdentityManager().Update(ctx, i, identity.ManagerAllowWriteProtectedTraits)
+}
@@ -5,6 +5,7 @@ package oidc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/ory/herodot"
 
 	"github.com/ory/x/fetcher"
+	"github.com/ory/x/sqlcon"
 
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
@@ -282,13 +284,84 @@ func (s *Strategy) processRegistration(w http.ResponseWriter, r *http.Request, r
 	}
 
 	i.SetCredentials(s.ID(), *creds)
-	if err := s.d.RegistrationExecutor().PostRegistrationHook(w, r, identity.CredentialsTypeOIDC, provider.Config().ID, rf, i); err != nil {
+	if err := s.d.RegistrationExecutor().PostRegistrationHook(w, r, identity.CredentialsTypeOIDC, provider.Config().ID, rf, i); errors.Is(err, registration.ErrDuplicateCredentials) {
+		return nil, s.registrationToLoginAndLink(w, r, rf, provider.Config().ID, i, creds)
+	} else if err != nil {
 		return nil, s.handleError(w, r, rf, provider.Config().ID, i.Traits, err)
 	}
 
 	return nil, nil
 }
 
+// registrationToLoginAndLink is called when the identity created from the OpenID Connect claims collides with an
+// existing identity. The registration flow is converted into a login flow and the OpenID Connect credentials are kept
+// in a continuity container. Once the user has proven ownership of the existing identity by signing in with any of
+// its first-factor methods, the credentials are linked to it.
+func (s *Strategy) registrationToLoginAndLink(w http.ResponseWriter, r *http.Request, rf *registration.Flow, providerID string, i *identity.Identity, creds *identity.Credentials) error {
+	ctx := r.Context()
+
+	// The continuity container is bound to the browser, so linking is not available for API flows.
+	if rf.Type != flow.TypeBrowser {
+		return s.handleError(w, r, rf, providerID, i.Traits, errors.WithStack(registration.ErrDuplicateCredentials))
+	}
+
+	existing, err := s.findDuplicateIdentity(ctx, i)
+	if err != nil {
+		return s.handleError(w, r, rf, providerID, i.Traits, err)
+	} else if existing == nil {
+		return s.handleError(w, r, rf, providerID, i.Traits, errors.WithStack(registration.ErrDuplicateCredentials))
+	}
+
+	rf.UI.Messages.Add(text.NewInfoSelfServiceLoginLinkCredentials(providerID))
+	lf, err := s.registrationToLogin(w, r, rf, providerID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.d.ContinuityManager().Pause(ctx, w, r, login.LinkCredentialsContinuityName,
+		continuity.WithIdentity(existing),
+		continuity.WithPayload(&login.LinkCredentials{
+			FlowID:            lf.ID,
+			CredentialsType:   s.ID(),
+			CredentialsConfig: creds.Config,
+		}),
+		continuity.WithLifespan(time.Until(lf.ExpiresAt))); err != nil {
+		return err
+	}
+
+	s.d.Logger().
+		WithRequest(r).
+		WithField("provider", providerID).
+		WithField("identity_id", existing.ID).
+		Debug("OpenID Connect credentials collided with an existing identity. Asking the user to sign in to link them.")
+
+	x.AcceptToRedirectOrJSON(w, r, s.d.Writer(), lf, lf.AppendTo(s.d.Config().SelfServiceFlowLoginUI(ctx)).String())
+	return registration.ErrHookAbortFlow
+}
+
+// findDuplicateIdentity returns the identity which owns one of the identifiers of the given identity's credentials,
+// or nil if there is none.
+func (s *Strategy) findDuplicateIdentity(ctx context.Context, i *identity.Identity) (*identity.Identity, error) {
+	for ct, c := range i.Credentials {
+		if ct == s.ID() {
+			continue
+		}
+
+		for _, identifier := range c.Identifiers {
+			existing, _, err := s.d.PrivilegedIdentityPool().FindByCredentialsIdentifier(ctx, ct, identifier)
+			if errors.Is(err, sqlcon.ErrNoRows) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			return existing, nil
+		}
+	}
+
+	return nil, nil
+}
+
 func (s *Strategy) createIdentity(w http.ResponseWriter, r *http.Request, a *registration.Flow, claims *Claims, provider Provider, container *authCodeContainer, jn *bytes.Buffer) (*identity.Identity, error) {
 	var jsonClaims bytes.Buffer
 	if err := json.NewEncoder(&jsonClaims).Encode(claims); err != nil {
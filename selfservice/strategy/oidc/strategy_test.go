@@ -643,7 +643,7 @@ func TestStrategy(t *testing.T) {
 			r := newBrowserRegistrationFlow(t, returnTS.URL, time.Minute)
 			action := assertFormValues(t, r.ID, "valid")
 			res, body := makeRequest(t, "valid", action, url.Values{})
-			assertUIError(t, res, body, "An account with the same identifier (email, phone, username, ...) exists already. Please sign in to your existing account and link your social profile in the settings page.")
+			assertUIError(t, res, body, "An account with the same identifier (email, phone, username, ...) exists already. Please sign in to your existing account to link your valid profile to it.")
 			require.Contains(t, gjson.GetBytes(body, "ui.action").String(), "/self-service/login")
 		})
 
@@ -655,6 +655,80 @@ func TestStrategy(t *testing.T) {
 		})
 	})
 
+	t.Run("case=should link the social profile after signing in to the existing account", func(t *testing.T) {
+		subject = "link-after-login@ory.sh"
+		scope = []string{"openid"}
+		password := x.NewUUID().String()
+
+		hashed, err := reg.Hasher(ctx).Generate(ctx, []byte(password))
+		require.NoError(t, err)
+
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{
+			Identifiers: []string{subject},
+			Config:      []byte(`{"hashed_password":"` + string(hashed) + `"}`),
+		})
+		i.Traits = identity.Traits(`{"subject":"` + subject + `"}`)
+		require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(ctx, i))
+
+		jar, _ := cookiejar.New(nil)
+		r := newBrowserRegistrationFlow(t, returnTS.URL, time.Minute)
+		action := assertFormValues(t, r.ID, "valid")
+		res, body := makeRequestWithCookieJar(t, "valid", action, url.Values{}, jar)
+		assertUIError(t, res, body, "Please sign in to your existing account to link your valid profile to it.")
+		assert.EqualValues(t, text.InfoSelfServiceLoginLinkCredentials, gjson.GetBytes(body, "ui.messages.0.id").Int(), "%s", body)
+
+		t.Run("case=should not link if the sign in is rejected", func(t *testing.T) {
+			i.State = identity.StateInactive
+			require.NoError(t, reg.PrivilegedIdentityPool().UpdateIdentity(ctx, i))
+			t.Cleanup(func() {
+				i.State = identity.StateActive
+				require.NoError(t, reg.PrivilegedIdentityPool().UpdateIdentity(ctx, i))
+			})
+
+			res, err := testhelpers.NewClientWithCookieJar(t, jar, false).PostForm(gjson.GetBytes(body, "ui.action").String(), url.Values{
+				"method":     {"password"},
+				"identifier": {subject},
+				"password":   {password},
+				"csrf_token": {gjson.GetBytes(body, "ui.nodes.#(attributes.name==csrf_token).attributes.value").String()},
+			})
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+
+			actual, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, i.ID)
+			require.NoError(t, err)
+			_, ok := actual.GetCredentials(identity.CredentialsTypeOIDC)
+			assert.False(t, ok)
+		})
+
+		t.Run("case=should link after signing in", func(t *testing.T) {
+			res, err := testhelpers.NewClientWithCookieJar(t, jar, false).PostForm(gjson.GetBytes(body, "ui.action").String(), url.Values{
+				"method":     {"password"},
+				"identifier": {subject},
+				"password":   {password},
+				"csrf_token": {gjson.GetBytes(body, "ui.nodes.#(attributes.name==csrf_token).attributes.value").String()},
+			})
+			require.NoError(t, err)
+			loginBody := ioutilx.MustReadAll(res.Body)
+			require.NoError(t, res.Body.Close())
+			assert.Contains(t, res.Request.URL.String(), returnTS.URL, "%s", loginBody)
+
+			actual, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, i.ID)
+			require.NoError(t, err)
+			creds, ok := actual.GetCredentials(identity.CredentialsTypeOIDC)
+			require.True(t, ok)
+			assert.Equal(t, []string{identity.OIDCUniqueID("valid", subject)}, creds.Identifiers)
+		})
+
+		t.Run("case=should sign in with the linked social profile", func(t *testing.T) {
+			lf := newBrowserLoginFlow(t, returnTS.URL, time.Minute)
+			action := assertFormValues(t, lf.ID, "valid")
+			res, body := makeRequest(t, "valid", action, url.Values{})
+			assert.Contains(t, res.Request.URL.String(), returnTS.URL, "%s", body)
+			assert.Equal(t, i.ID.String(), gjson.GetBytes(body, "identity.id").String(), "%s", body)
+		})
+	})
+
 	t.Run("case=should redirect to default return ts when sending authenticated login flow without forced flag", func(t *testing.T) {
 		subject = "no-reauth-login@ory.sh"
 		scope = []string{"openid"}
@@ -904,6 +978,62 @@ func TestCountActiveFirstFactorCredentials(t *testing.T) {
 	}
 }
 
+func TestLink(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/registration.schema.json")
+	strategy := oidc.NewStrategy(reg)
+
+	toConfig := func(providers ...identity.CredentialsOIDCProvider) []byte {
+		out, err := json.Marshal(&identity.CredentialsOIDC{Providers: providers})
+		require.NoError(t, err)
+		return out
+	}
+
+	newIdentity := func(t *testing.T) *identity.Identity {
+		subject := x.NewUUID().String() + "@ory.sh"
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.Traits = identity.Traits(`{"subject":"` + subject + `"}`)
+		i.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{Identifiers: []string{subject}, Config: []byte(`{}`)})
+		require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(ctx, i))
+		return i
+	}
+
+	assertProviders := func(t *testing.T, i *identity.Identity, expected ...string) {
+		actual, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, i.ID)
+		require.NoError(t, err)
+
+		var conf identity.CredentialsOIDC
+		creds, err := actual.ParseCredentials(identity.CredentialsTypeOIDC, &conf)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, expected, creds.Identifiers)
+		require.Len(t, conf.Providers, len(expected))
+	}
+
+	t.Run("case=adds credentials to an identity without oidc credentials", func(t *testing.T) {
+		i := newIdentity(t)
+		require.NoError(t, strategy.Link(ctx, i, toConfig(identity.CredentialsOIDCProvider{Provider: "google", Subject: "a"})))
+		assertProviders(t, i, "google:a")
+	})
+
+	t.Run("case=merges credentials with existing oidc credentials", func(t *testing.T) {
+		i := newIdentity(t)
+		require.NoError(t, strategy.Link(ctx, i, toConfig(identity.CredentialsOIDCProvider{Provider: "google", Subject: "b"})))
+
+		i, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, i.ID)
+		require.NoError(t, err)
+		require.NoError(t, strategy.Link(ctx, i, toConfig(
+			identity.CredentialsOIDCProvider{Provider: "google", Subject: "b"},
+			identity.CredentialsOIDCProvider{Provider: "github", Subject: "b"},
+		)))
+		assertProviders(t, i, "google:b", "github:b")
+	})
+
+	t.Run("case=fails on invalid config", func(t *testing.T) {
+		require.Error(t, strategy.Link(ctx, newIdentity(t), []byte(`not json`)))
+	})
+}
+
 func TestDisabledEndpoint(t *testing.T) {
 	conf, reg := internal.NewFastRegistryWithMocks(t)
 	testhelpers.StrategyEnable(t, conf, identity.CredentialsTypeOIDC.String(), false)
@@ -0,0 +1,11 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package passkey
+
+import (
+	"github.com/ory/jsonschema/v3"
+)
+
+var ErrNotEnoughCredentials = &jsonschema.ValidationError{
+	Message: "unable to remove this passkey because it would lock you out of your account", InstancePtr: "#/passkey_remove"}
@@ -0,0 +1,201 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package passkey
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/duo-labs/webauthn/protocol"
+	"github.com/duo-labs/webauthn/webauthn"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/decoderx"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
+	kratoswebauthn "github.com/ory/kratos/selfservice/strategy/webauthn"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+)
+
+func (s *Strategy) RegisterLoginRoutes(r *x.RouterPublic) {
+	kratoswebauthn.RegisterScriptRoute(r)
+}
+
+func (s *Strategy) PopulateLoginMethod(r *http.Request, requestedAAL identity.AuthenticatorAssuranceLevel, sr *login.Flow) error {
+	if sr.Type != flow.TypeBrowser || requestedAAL != identity.AuthenticatorAssuranceLevel1 {
+		return nil
+	}
+
+	web, err := webauthn.New(s.d.Config().PasskeyConfig(r.Context()))
+	if err != nil {
+		return errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to initiate passkey login.").WithDebug(err.Error()))
+	}
+
+	// Passkeys are discoverable credentials, which is why we do not know the user (and
+	// therefore can not use web.BeginLogin) until the authenticator returns the user handle.
+	challenge, err := protocol.CreateChallenge()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	options := protocol.CredentialAssertion{Response: protocol.PublicKeyCredentialRequestOptions{
+		Challenge:        challenge,
+		Timeout:          web.Config.Timeout,
+		RelyingPartyID:   web.Config.RPID,
+		UserVerification: web.Config.AuthenticatorSelection.UserVerification,
+	}}
+
+	sr.InternalContext, err = sjson.SetBytes(sr.InternalContext, flow.PrefixInternalContextKey(s.ID(), InternalContextKeySessionData), &webauthn.SessionData{
+		Challenge:        base64.RawURLEncoding.EncodeToString(challenge),
+		UserVerification: options.Response.UserVerification,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	injectWebAuthnOptions, err := json.Marshal(options)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	sr.UI.SetCSRF(s.d.GenerateCSRFToken(r))
+
+	// Enables conditional UI, where the browser offers the passkeys as autofill suggestions of
+	// the identifier field rendered by the other login methods.
+	if n := sr.UI.Nodes.Find("identifier"); n != nil {
+		if attr, ok := n.Attributes.(*node.InputAttributes); ok {
+			attr.Autocomplete = node.InputAttributeAutocompleteUsernameWebAuthn
+		}
+	}
+
+	sr.UI.Nodes.Upsert(NewPasskeyScript(s.d.Config().SelfPublicURL(r.Context())))
+	sr.UI.Nodes.Upsert(NewPasskeyChallenge(string(injectWebAuthnOptions)))
+	sr.UI.Nodes.Upsert(NewPasskeyLoginInput())
+	sr.UI.Nodes.Upsert(NewPasskeyLoginTrigger())
+
+	return nil
+}
+
+func (s *Strategy) handleLoginError(r *http.Request, f *login.Flow, err error) error {
+	if f != nil {
+		f.UI.Nodes.SetValueAttribute(node.PasskeyLogin, "")
+		if f.Type == flow.TypeBrowser {
+			f.UI.SetCSRF(s.d.GenerateCSRFToken(r))
+		}
+	}
+
+	return err
+}
+
+// Update Login Flow with Passkey Method
+//
+// swagger:model updateLoginFlowWithPasskeyMethod
+type updateLoginFlowWithPasskeyMethod struct {
+	// Method should be set to "passkey" when logging in using the Passkey strategy.
+	//
+	// required: true
+	Method string `json:"method"`
+
+	// Sending the anti-csrf token is only required for browser login flows.
+	CSRFToken string `json:"csrf_token"`
+
+	// Login a Passkey
+	//
+	// This must contain the JSON returned by the WebAuthn assertion (login) process.
+	Login string `json:"passkey_login"`
+}
+
+func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow, _ uuid.UUID) (*identity.Identity, error) {
+	if f.Type != flow.TypeBrowser {
+		return nil, flow.ErrStrategyNotResponsible
+	}
+
+	var p updateLoginFlowWithPasskeyMethod
+	if err := s.hd.Decode(r, &p,
+		decoderx.HTTPDecoderSetValidatePayloads(true),
+		decoderx.MustHTTPRawJSONSchemaCompiler(loginSchema),
+		decoderx.HTTPDecoderJSONFollowsFormFormat()); err != nil {
+		return nil, s.handleLoginError(r, f, err)
+	}
+
+	if len(p.Login) > 0 || p.Method == s.SettingsStrategyID() {
+		// This method has only one submit button
+		p.Method = s.SettingsStrategyID()
+	} else {
+		return nil, flow.ErrStrategyNotResponsible
+	}
+
+	if err := flow.MethodEnabledAndAllowed(r.Context(), s.SettingsStrategyID(), p.Method, s.d); err != nil {
+		return nil, s.handleLoginError(r, f, err)
+	}
+
+	if err := flow.EnsureCSRF(s.d, r, f.Type, s.d.Config().DisableAPIFlowEnforcement(r.Context()), s.d.GenerateCSRFToken, p.CSRFToken); err != nil {
+		return nil, s.handleLoginError(r, f, err)
+	}
+
+	if err := login.CheckAAL(f, identity.AuthenticatorAssuranceLevel1); err != nil {
+		return nil, s.handleLoginError(r, f, err)
+	}
+
+	webAuthnResponse, err := protocol.ParseCredentialRequestResponseBody(strings.NewReader(p.Login))
+	if err != nil {
+		return nil, s.handleLoginError(r, f, errors.WithStack(herodot.ErrBadRequest.WithReasonf("Unable to parse WebAuthn response.").WithDebug(err.Error())))
+	}
+
+	userHandle := webAuthnResponse.Response.UserHandle
+	if len(userHandle) == 0 {
+		return nil, s.handleLoginError(r, f, errors.WithStack(schema.NewNoWebAuthnCredentials()))
+	}
+
+	i, c, err := s.d.PrivilegedIdentityPool().FindByCredentialsIdentifier(r.Context(), s.ID(), base64.RawURLEncoding.EncodeToString(userHandle))
+	if err != nil {
+		time.Sleep(x.RandomDelay(s.d.Config().HasherArgon2(r.Context()).ExpectedDuration, s.d.Config().HasherArgon2(r.Context()).ExpectedDeviation))
+		return nil, s.handleLoginError(r, f, errors.WithStack(schema.NewNoWebAuthnCredentials()))
+	}
+
+	var o identity.CredentialsWebAuthnConfig
+	if err := json.Unmarshal(c.Config, &o); err != nil {
+		return nil, s.handleLoginError(r, f, errors.WithStack(herodot.ErrInternalServerError.WithReason("The passkey credentials could not be decoded properly").WithDebug(err.Error()).WithWrap(err)))
+	}
+
+	web, err := webauthn.New(s.d.Config().PasskeyConfig(r.Context()))
+	if err != nil {
+		return nil, s.handleLoginError(r, f, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to get passkey config.").WithDebug(err.Error())))
+	}
+
+	var webAuthnSess webauthn.SessionData
+	if err := json.Unmarshal([]byte(gjson.GetBytes(f.InternalContext, flow.PrefixInternalContextKey(s.ID(), InternalContextKeySessionData)).Raw), &webAuthnSess); err != nil {
+		return nil, s.handleLoginError(r, f, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Expected WebAuthN in internal context to be an object but got: %s", err)))
+	}
+
+	// The challenge was issued without knowing the user, so we bind it to the user now.
+	webAuthnSess.UserID = userHandle
+	if _, err := web.ValidateLogin(NewUser(o.UserHandle, "", o.Credentials.ToWebAuthn(), web.Config), webAuthnSess, webAuthnResponse); err != nil {
+		return nil, s.handleLoginError(r, f, errors.WithStack(schema.NewWebAuthnVerifierWrongError("#/")))
+	}
+
+	// Remove the WebAuthn URL from the internal context now that it is set!
+	f.InternalContext, err = sjson.DeleteBytes(f.InternalContext, flow.PrefixInternalContextKey(s.ID(), InternalContextKeySessionData))
+	if err != nil {
+		return nil, s.handleLoginError(r, f, errors.WithStack(err))
+	}
+
+	f.Active = s.ID()
+	if err = s.d.LoginFlowPersister().UpdateLoginFlow(r.Context(), f); err != nil {
+		return nil, s.handleLoginError(r, f, errors.WithStack(herodot.ErrInternalServerError.WithReason("Could not update flow").WithDebug(err.Error())))
+	}
+
+	return i, nil
+}
@@ -0,0 +1,146 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package passkey_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+)
+
+func TestCompleteLogin(t *testing.T) {
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	conf.MustSet(ctx, config.ViperKeySelfServiceStrategyConfig+"."+string(identity.CredentialsTypePassword)+".enabled", true)
+	enablePasskey(conf)
+
+	router := x.NewRouterPublic()
+	publicTS, _ := testhelpers.NewKratosServerWithRouters(t, reg, router, x.NewRouterAdmin())
+
+	errTS := testhelpers.NewErrorTestServer(t, reg)
+	uiTS := testhelpers.NewLoginUIFlowEchoServer(t, reg)
+
+	conf.MustSet(ctx, config.ViperKeySelfServiceErrorUI, errTS.URL+"/error-ts")
+	conf.MustSet(ctx, config.ViperKeySelfServiceLoginUI, uiTS.URL+"/login-ts")
+
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/registration.schema.json")
+	conf.MustSet(ctx, config.ViperKeySecretsDefault, []string{"not-a-secure-session-key"})
+
+	t.Run("case=passkey nodes are rendered for browser flows", func(t *testing.T) {
+		for _, spa := range []bool{false, true} {
+			client := testhelpers.NewClientWithCookies(t)
+			f := testhelpers.InitializeLoginFlowViaBrowser(t, client, publicTS, false, spa, false, false)
+
+			nodes := f.Ui.Nodes
+			var found []string
+			for _, n := range nodes {
+				if n.Group != string(node.PasskeyGroup) {
+					continue
+				}
+				if n.Attributes.UiNodeInputAttributes != nil {
+					found = append(found, n.Attributes.UiNodeInputAttributes.Name)
+				} else if n.Attributes.UiNodeScriptAttributes != nil {
+					found = append(found, n.Attributes.UiNodeScriptAttributes.Id)
+				}
+			}
+			assert.ElementsMatch(t, []string{node.PasskeyScript, node.PasskeyChallenge, node.PasskeyLogin, node.PasskeyLoginTrigger}, found)
+
+			for _, n := range nodes {
+				if a := n.Attributes.UiNodeInputAttributes; a != nil {
+					switch a.Name {
+					case "identifier":
+						assert.EqualValues(t, node.InputAttributeAutocompleteUsernameWebAuthn, *a.Autocomplete)
+					case node.PasskeyChallenge:
+						options := a.Value.(string)
+						assert.NotEmpty(t, gjson.Get(options, "publicKey.challenge").String(), options)
+						assert.Equal(t, "localhost", gjson.Get(options, "publicKey.rpId").String(), options)
+						assert.False(t, gjson.Get(options, "publicKey.allowCredentials").Exists(), options)
+					case node.PasskeyLoginTrigger:
+						assert.EqualValues(t, text.InfoSelfServiceLoginPasskey, n.Meta.Label.Id)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("case=passkey nodes are not rendered for api flows", func(t *testing.T) {
+		f := testhelpers.InitializeLoginFlowViaAPI(t, testhelpers.NewDebugClient(t), publicTS, false)
+		for _, n := range f.Ui.Nodes {
+			assert.NotEqual(t, string(node.PasskeyGroup), n.Group)
+		}
+	})
+
+	t.Run("case=passkey nodes are not rendered when disabled", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySelfServiceStrategyConfig+"."+string(identity.CredentialsTypePasskey)+".enabled", false)
+		t.Cleanup(func() { enablePasskey(conf) })
+
+		f := testhelpers.InitializeLoginFlowViaBrowser(t, testhelpers.NewClientWithCookies(t), publicTS, false, true, false, false)
+		for _, n := range f.Ui.Nodes {
+			assert.NotEqual(t, string(node.PasskeyGroup), n.Group)
+		}
+	})
+
+	t.Run("case=fails with invalid passkey response", func(t *testing.T) {
+		client := testhelpers.NewClientWithCookies(t)
+		f := testhelpers.InitializeLoginFlowViaBrowser(t, client, publicTS, false, true, false, false)
+		values := testhelpers.SDKFormFieldsToURLValues(f.Ui.Nodes)
+		values.Set("method", "passkey")
+		values.Set(node.PasskeyLogin, "{}")
+
+		body, res := testhelpers.LoginMakeRequest(t, false, true, f, client, values.Encode())
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+		assert.Equal(t, "Unable to parse WebAuthn response.", gjson.Get(body, "ui.messages.0.text").String(), body)
+	})
+
+	t.Run("case=fails with unknown user handle", func(t *testing.T) {
+		client := testhelpers.NewClientWithCookies(t)
+		f := testhelpers.InitializeLoginFlowViaBrowser(t, client, publicTS, false, true, false, false)
+		values := testhelpers.SDKFormFieldsToURLValues(f.Ui.Nodes)
+		values.Set("method", "passkey")
+		values.Set(node.PasskeyLogin, loginResponseWithUserHandle(t, []byte("does-not-exist")))
+
+		body, res := testhelpers.LoginMakeRequest(t, false, true, f, client, values.Encode())
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+		assert.EqualValues(t, text.ErrorValidationSuchNoWebAuthnUser, gjson.Get(body, "ui.messages.0.id").Int(), body)
+	})
+}
+
+// loginResponseWithUserHandle returns a well-formed but unsigned WebAuthn assertion for the given user handle.
+func loginResponseWithUserHandle(t *testing.T, userHandle []byte) string {
+	enc := base64.RawURLEncoding.EncodeToString
+	authenticatorData := append(bytes.Repeat([]byte{0}, 32), 0x01, 0, 0, 0, 0)
+	clientData, err := json.Marshal(map[string]string{
+		"type":      "webauthn.get",
+		"challenge": enc([]byte("challenge")),
+		"origin":    "http://localhost:4455",
+	})
+	require.NoError(t, err)
+
+	res, err := json.Marshal(map[string]interface{}{
+		"id":    enc([]byte("credential")),
+		"rawId": enc([]byte("credential")),
+		"type":  "public-key",
+		"response": map[string]string{
+			"authenticatorData": enc(authenticatorData),
+			"clientDataJSON":    enc(clientData),
+			"signature":         enc([]byte("signature")),
+			"userHandle":        enc(userHandle),
+		},
+	})
+	require.NoError(t, err)
+	return string(res)
+}
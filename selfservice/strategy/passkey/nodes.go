@@ -0,0 +1,60 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package passkey
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ory/x/stringsx"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/strategy/webauthn"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/node"
+)
+
+func NewPasskeyScript(base *url.URL) *node.Node {
+	return webauthn.NewScript(base, node.PasskeyScript, node.PasskeyGroup)
+}
+
+func NewPasskeyChallenge(options string) *node.Node {
+	return node.NewInputField(node.PasskeyChallenge, options, node.PasskeyGroup,
+		node.InputAttributeTypeHidden)
+}
+
+func NewPasskeyLoginTrigger() *node.Node {
+	return node.NewInputField(node.PasskeyLoginTrigger, "", node.PasskeyGroup,
+		node.InputAttributeTypeButton, node.WithInputAttributes(func(a *node.InputAttributes) {
+			a.OnClick = "window.__oryPasskeyLogin()"
+		})).WithMetaLabel(text.NewInfoSelfServiceLoginPasskey())
+}
+
+func NewPasskeyLoginInput() *node.Node {
+	return node.NewInputField(node.PasskeyLogin, "", node.PasskeyGroup,
+		node.InputAttributeTypeHidden)
+}
+
+func NewPasskeyConnectionTrigger(options string) *node.Node {
+	return node.NewInputField(node.PasskeyRegisterTrigger, "", node.PasskeyGroup,
+		node.InputAttributeTypeButton, node.WithInputAttributes(func(a *node.InputAttributes) {
+			a.OnClick = "window.__oryPasskeyRegistration(" + options + ")"
+		}))
+}
+
+func NewPasskeyConnectionInput() *node.Node {
+	return node.NewInputField(node.PasskeyRegister, "", node.PasskeyGroup,
+		node.InputAttributeTypeHidden)
+}
+
+func NewPasskeyConnectionName() *node.Node {
+	return node.NewInputField(node.PasskeyRegisterDisplayName, "", node.PasskeyGroup, node.InputAttributeTypeText).
+		WithMetaLabel(text.NewInfoSelfServiceSettingsRegisterPasskeyDisplayName())
+}
+
+func NewPasskeyUnlink(c *identity.CredentialWebAuthn) *node.Node {
+	return node.NewInputField(node.PasskeyRemove, fmt.Sprintf("%x", c.ID), node.PasskeyGroup,
+		node.InputAttributeTypeSubmit).
+		WithMetaLabel(text.NewInfoSelfServiceSettingsRemovePasskey(stringsx.Coalesce(c.DisplayName, "unnamed"), c.AddedAt))
+}
@@ -0,0 +1,262 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package passkey
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/duo-labs/webauthn/protocol"
+	"github.com/duo-labs/webauthn/webauthn"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/registration"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/container"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+)
+
+// Update Registration Flow with Passkey Method
+//
+// swagger:model updateRegistrationFlowWithPasskeyMethod
+type updateRegistrationFlowWithPasskeyMethod struct {
+	// Register a Passkey
+	//
+	// It is expected that the JSON returned by the WebAuthn registration process
+	// is included here.
+	Register string `json:"passkey_register"`
+
+	// Name of the Passkey to be Added
+	//
+	// A human-readable name for the passkey which will be added.
+	RegisterDisplayName string `json:"passkey_register_displayname"`
+
+	// CSRFToken is the anti-CSRF token
+	CSRFToken string `json:"csrf_token"`
+
+	// The identity's traits
+	//
+	// required: true
+	Traits json.RawMessage `json:"traits"`
+
+	// Method
+	//
+	// Should be set to "passkey" when trying to sign up with a passkey.
+	//
+	// required: true
+	Method string `json:"method"`
+
+	// Flow is flow ID.
+	//
+	// swagger:ignore
+	Flow string `json:"flow"`
+
+	// Transient data to pass along to any webhooks
+	//
+	// required: false
+	TransientPayload json.RawMessage `json:"transient_payload,omitempty"`
+}
+
+func (s *Strategy) RegisterRegistrationRoutes(_ *x.RouterPublic) {
+}
+
+func (s *Strategy) handleRegistrationError(_ http.ResponseWriter, r *http.Request, f *registration.Flow, p *updateRegistrationFlowWithPasskeyMethod, err error) error {
+	if f != nil {
+		if p != nil {
+			for _, n := range container.NewFromJSON("", node.DefaultGroup, p.Traits, "traits").Nodes {
+				// we only set the value and not the whole field because we want to keep types from the initial form generation
+				f.UI.Nodes.SetValueAttribute(n.ID(), n.Attributes.GetValue())
+			}
+			f.UI.Nodes.SetValueAttribute(node.PasskeyRegisterDisplayName, p.RegisterDisplayName)
+		}
+
+		if f.Type == flow.TypeBrowser {
+			f.UI.SetCSRF(s.d.GenerateCSRFToken(r))
+		}
+	}
+
+	return err
+}
+
+func (s *Strategy) decode(p *updateRegistrationFlowWithPasskeyMethod, r *http.Request) error {
+	return registration.DecodeBody(p, r, s.hd, s.d.Config(), registrationSchema)
+}
+
+// Register signs up an identity with a passkey in two steps. The first submission
+// validates the traits and returns the WebAuthn credential creation options, because
+// the passkey stores a name which identifies the account. The second submission
+// contains the newly created passkey.
+func (s *Strategy) Register(w http.ResponseWriter, r *http.Request, f *registration.Flow, i *identity.Identity) (err error) {
+	if f.Type != flow.TypeBrowser {
+		return flow.ErrStrategyNotResponsible
+	}
+
+	var p updateRegistrationFlowWithPasskeyMethod
+	if err := s.decode(&p, r); err != nil {
+		return s.handleRegistrationError(w, r, f, &p, err)
+	}
+
+	if len(p.Register) == 0 && p.Method != s.SettingsStrategyID() {
+		return flow.ErrStrategyNotResponsible
+	}
+
+	p.Method = s.SettingsStrategyID()
+	if err := flow.MethodEnabledAndAllowed(r.Context(), s.SettingsStrategyID(), p.Method, s.d); err != nil {
+		return s.handleRegistrationError(w, r, f, &p, err)
+	}
+
+	f.TransientPayload = p.TransientPayload
+
+	if err := flow.EnsureCSRF(s.d, r, f.Type, s.d.Config().DisableAPIFlowEnforcement(r.Context()), s.d.GenerateCSRFToken, p.CSRFToken); err != nil {
+		return s.handleRegistrationError(w, r, f, &p, err)
+	}
+
+	if len(p.Traits) == 0 {
+		p.Traits = json.RawMessage("{}")
+	}
+	i.Traits = identity.Traits(p.Traits)
+
+	if len(p.Register) == 0 {
+		return s.registrationCreateOptions(w, r, f, i, &p)
+	}
+
+	var webAuthnSess webauthn.SessionData
+	if webAuthnSession := gjson.GetBytes(f.InternalContext, flow.PrefixInternalContextKey(s.ID(), InternalContextKeySessionData)); !webAuthnSession.IsObject() {
+		return s.handleRegistrationError(w, r, f, &p, errors.WithStack(herodot.ErrBadRequest.WithReasonf("Expected WebAuthN in internal context to be an object.")))
+	} else if err := json.Unmarshal([]byte(webAuthnSession.Raw), &webAuthnSess); err != nil {
+		return s.handleRegistrationError(w, r, f, &p, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Expected WebAuthN in internal context to be an object but got: %s", err)))
+	}
+
+	webAuthnResponse, err := protocol.ParseCredentialCreationResponseBody(strings.NewReader(p.Register))
+	if err != nil {
+		return s.handleRegistrationError(w, r, f, &p, errors.WithStack(herodot.ErrBadRequest.WithReasonf("Unable to parse WebAuthn response: %s", err)))
+	}
+
+	web, err := webauthn.New(s.d.Config().PasskeyConfig(r.Context()))
+	if err != nil {
+		return s.handleRegistrationError(w, r, f, &p, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to get passkey config.").WithDebug(err.Error())))
+	}
+
+	credential, err := web.CreateCredential(NewUser(webAuthnSess.UserID, "", nil, web.Config), webAuthnSess, webAuthnResponse)
+	if err != nil {
+		if devErr := new(protocol.Error); errors.As(err, &devErr) {
+			s.d.Logger().WithError(err).WithField("error_devinfo", devErr.DevInfo).Error("Failed to create passkey credential")
+		}
+		return s.handleRegistrationError(w, r, f, &p, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to create passkey credential: %s", err)))
+	}
+
+	var cc identity.CredentialsWebAuthnConfig
+	wc := identity.CredentialFromWebAuthn(credential, true)
+	wc.AddedAt = time.Now().UTC().Round(time.Second)
+	wc.DisplayName = p.RegisterDisplayName
+	cc.UserHandle = webAuthnSess.UserID
+
+	cc.Credentials = append(cc.Credentials, *wc)
+	co, err := json.Marshal(cc)
+	if err != nil {
+		return s.handleRegistrationError(w, r, f, &p, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to encode identity credentials.").WithDebug(err.Error())))
+	}
+
+	i.UpsertCredentialsConfig(s.ID(), co, 1)
+	setUserHandleIdentifier(i, cc.UserHandle)
+	if err := s.d.IdentityValidator().Validate(r.Context(), i); err != nil {
+		return s.handleRegistrationError(w, r, f, &p, err)
+	}
+
+	// Remove the WebAuthn URL from the internal context now that it is set!
+	f.InternalContext, err = sjson.DeleteBytes(f.InternalContext, flow.PrefixInternalContextKey(s.ID(), InternalContextKeySessionData))
+	if err != nil {
+		return s.handleRegistrationError(w, r, f, &p, err)
+	}
+
+	if err := s.d.RegistrationFlowPersister().UpdateRegistrationFlow(r.Context(), f); err != nil {
+		return s.handleRegistrationError(w, r, f, &p, err)
+	}
+
+	return nil
+}
+
+func (s *Strategy) registrationCreateOptions(w http.ResponseWriter, r *http.Request, f *registration.Flow, i *identity.Identity, p *updateRegistrationFlowWithPasskeyMethod) error {
+	// Runs the identity schema extensions, which also collect the identifiers we use as the passkey's name.
+	if err := s.d.IdentityValidator().Validate(r.Context(), i); err != nil {
+		return s.handleRegistrationError(w, r, f, p, err)
+	}
+
+	web, err := webauthn.New(s.d.Config().PasskeyConfig(r.Context()))
+	if err != nil {
+		return s.handleRegistrationError(w, r, f, p, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to get passkey config.").WithDebug(err.Error())))
+	}
+
+	userHandle := newUserHandle()
+	option, sessionData, err := web.BeginRegistration(NewUser(userHandle, userName(i), nil, web.Config))
+	if err != nil {
+		return s.handleRegistrationError(w, r, f, p, errors.WithStack(err))
+	}
+
+	f.InternalContext, err = sjson.SetBytes(f.InternalContext, flow.PrefixInternalContextKey(s.ID(), InternalContextKeySessionData), sessionData)
+	if err != nil {
+		return s.handleRegistrationError(w, r, f, p, errors.WithStack(err))
+	}
+
+	injectWebAuthnOptions, err := json.Marshal(option)
+	if err != nil {
+		return s.handleRegistrationError(w, r, f, p, errors.WithStack(err))
+	}
+
+	f.UI.Nodes.Upsert(NewPasskeyScript(s.d.Config().SelfPublicURL(r.Context())))
+	f.UI.Nodes.Upsert(NewPasskeyConnectionName())
+	f.UI.Nodes.Upsert(NewPasskeyConnectionInput())
+	f.UI.Nodes.Upsert(NewPasskeyConnectionTrigger(string(injectWebAuthnOptions)).
+		WithMetaLabel(text.NewInfoSelfServiceRegistrationRegisterPasskey()))
+	for _, n := range container.NewFromJSON("", node.DefaultGroup, p.Traits, "traits").Nodes {
+		f.UI.Nodes.SetValueAttribute(n.ID(), n.Attributes.GetValue())
+	}
+	f.UI.SetCSRF(s.d.GenerateCSRFToken(r))
+
+	if err := s.d.RegistrationFlowPersister().UpdateRegistrationFlow(r.Context(), f); err != nil {
+		return s.handleRegistrationError(w, r, f, p, err)
+	}
+
+	redirectTo := f.AppendTo(s.d.Config().SelfServiceFlowRegistrationUI(r.Context())).String()
+	if x.IsJSONRequest(r) {
+		s.d.Writer().WriteError(w, r, flow.NewBrowserLocationChangeRequiredError(redirectTo))
+	} else {
+		http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+	}
+
+	return errors.WithStack(flow.ErrCompletedByStrategy)
+}
+
+func (s *Strategy) PopulateRegistrationMethod(r *http.Request, f *registration.Flow) error {
+	if f.Type != flow.TypeBrowser {
+		return nil
+	}
+
+	ds, err := s.d.Config().DefaultIdentityTraitsSchemaURL(r.Context())
+	if err != nil {
+		return err
+	}
+
+	nodes, err := container.NodesFromJSONSchema(r.Context(), node.DefaultGroup, ds.String(), "", nil)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range nodes {
+		f.UI.SetNode(n)
+	}
+
+	f.UI.SetCSRF(s.d.GenerateCSRFToken(r))
+	f.UI.GetNodes().Append(node.NewInputField("method", s.SettingsStrategyID(), node.PasskeyGroup, node.InputAttributeTypeSubmit).
+		WithMetaLabel(text.NewInfoSelfServiceRegistrationRegisterPasskey()))
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package passkey_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+)
+
+func TestRegistration(t *testing.T) {
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	conf.MustSet(ctx, config.ViperKeySelfServiceStrategyConfig+"."+string(identity.CredentialsTypePassword)+".enabled", false)
+	enablePasskey(conf)
+
+	router := x.NewRouterPublic()
+	publicTS, _ := testhelpers.NewKratosServerWithRouters(t, reg, router, x.NewRouterAdmin())
+
+	_ = testhelpers.NewErrorTestServer(t, reg)
+	_ = testhelpers.NewRegistrationUIFlowEchoServer(t, reg)
+	_ = testhelpers.NewRedirSessionEchoTS(t, reg)
+
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/registration.schema.json")
+	conf.MustSet(ctx, config.ViperKeySecretsDefault, []string{"not-a-secure-session-key"})
+
+	t.Run("case=passkey button exists", func(t *testing.T) {
+		client := testhelpers.NewClientWithCookies(t)
+		f := testhelpers.InitializeRegistrationFlowViaBrowser(t, client, publicTS, false, false, false)
+
+		var found bool
+		for _, n := range f.Ui.Nodes {
+			if a := n.Attributes.UiNodeInputAttributes; a != nil && a.Name == "method" && a.Value == "passkey" {
+				found = true
+				assert.EqualValues(t, text.InfoSelfServiceRegistrationRegisterPasskey, n.Meta.Label.Id)
+			}
+		}
+		assert.True(t, found, "%+v", f.Ui.Nodes)
+	})
+
+	t.Run("case=first submission validates the traits", func(t *testing.T) {
+		client := testhelpers.NewClientWithCookies(t)
+		f := testhelpers.InitializeRegistrationFlowViaBrowser(t, client, publicTS, true, false, false)
+		values := testhelpers.SDKFormFieldsToURLValues(f.Ui.Nodes)
+		values.Set("method", "passkey")
+		values.Set("traits.username", "registration-passkey-invalid")
+		values.Del("traits.foobar")
+
+		body, res := testhelpers.RegistrationMakeRequest(t, false, true, f, client, values.Encode())
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+		assert.Contains(t, gjson.Get(body, "ui.nodes.#(attributes.name==traits.foobar).messages.0.text").String(), "Property foobar is missing", body)
+		assert.False(t, gjson.Get(body, "ui.nodes.#(attributes.name=="+node.PasskeyRegisterTrigger+")").Exists(), body)
+	})
+
+	for _, spa := range []bool{false, true} {
+		t.Run("case=first submission returns the credential creation options", func(t *testing.T) {
+			client := testhelpers.NewClientWithCookies(t)
+			f := testhelpers.InitializeRegistrationFlowViaBrowser(t, client, publicTS, spa, false, false)
+			values := testhelpers.SDKFormFieldsToURLValues(f.Ui.Nodes)
+			values.Set("method", "passkey")
+			values.Set("traits.username", "registration-passkey@ory.sh")
+			values.Set("traits.foobar", "bar")
+
+			body, res := testhelpers.RegistrationMakeRequest(t, false, spa, f, client, values.Encode())
+			if spa {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode, body)
+				redirectTo := gjson.Get(body, "redirect_browser_to").String()
+				require.NotEmpty(t, redirectTo, body)
+
+				var err error
+				res, err = client.Get(redirectTo)
+				require.NoError(t, err)
+				defer res.Body.Close()
+				body = string(x.MustReadAll(res.Body))
+			}
+			assert.Equal(t, http.StatusOK, res.StatusCode, body)
+
+			assert.Equal(t, "registration-passkey@ory.sh", gjson.Get(body, "ui.nodes.#(attributes.name==traits.username).attributes.value").String(), body)
+			assert.Equal(t, "bar", gjson.Get(body, "ui.nodes.#(attributes.name==traits.foobar).attributes.value").String(), body)
+			assert.True(t, gjson.Get(body, "ui.nodes.#(attributes.name=="+node.PasskeyRegister+")").Exists(), body)
+			assert.True(t, gjson.Get(body, "ui.nodes.#(attributes.name=="+node.PasskeyRegisterDisplayName+")").Exists(), body)
+
+			onclick := gjson.Get(body, "ui.nodes.#(attributes.name=="+node.PasskeyRegisterTrigger+").attributes.onclick").String()
+			assert.Contains(t, onclick, "window.__oryPasskeyRegistration(", body)
+			assert.Contains(t, onclick, `"name":"registration-passkey@ory.sh"`, body)
+			assert.Contains(t, onclick, `"residentKey":"required"`, body)
+		})
+	}
+}
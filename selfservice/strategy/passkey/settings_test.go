@@ -0,0 +1,120 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package passkey_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+)
+
+func createIdentityWithPasskeys(t *testing.T, reg driver.Registry, withPassword bool, passkeys ...string) *identity.Identity {
+	identifier := x.NewUUID().String() + "@ory.sh"
+	i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+	i.Traits = identity.Traits(fmt.Sprintf(`{"username":%q,"foobar":"bar"}`, identifier))
+
+	if withPassword {
+		i.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{
+			Type:        identity.CredentialsTypePassword,
+			Identifiers: []string{identifier},
+			Config:      []byte(`{"hashed_password":"$2a$08$.cOYmAd.vCpDOoiVJrO5B.hjTLKQQ6cAK40u8uB.FnZDyPvVvQ9Q."}`),
+		})
+	}
+
+	if len(passkeys) > 0 {
+		var cc identity.CredentialsWebAuthnConfig
+		cc.UserHandle = []byte(x.NewUUID().String())
+		for _, name := range passkeys {
+			cc.Credentials = append(cc.Credentials, identity.CredentialWebAuthn{
+				ID:             []byte(name),
+				DisplayName:    name,
+				IsPasswordless: true,
+			})
+		}
+		conf, err := json.Marshal(cc)
+		require.NoError(t, err)
+		i.SetCredentials(identity.CredentialsTypePasskey, identity.Credentials{
+			Type:        identity.CredentialsTypePasskey,
+			Identifiers: []string{x.NewUUID().String()},
+			Config:      conf,
+		})
+	}
+
+	require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(context.Background(), i))
+	return i
+}
+
+func TestCompleteSettings(t *testing.T) {
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	conf.MustSet(ctx, config.ViperKeySelfServiceStrategyConfig+"."+string(identity.CredentialsTypePassword)+".enabled", false)
+	enablePasskey(conf)
+	conf.MustSet(ctx, config.ViperKeySelfServiceStrategyConfig+".profile.enabled", false)
+	conf.MustSet(ctx, config.ViperKeySelfServiceSettingsRequiredAAL, "aal1")
+
+	router := x.NewRouterPublic()
+	publicTS, _ := testhelpers.NewKratosServerWithRouters(t, reg, router, x.NewRouterAdmin())
+
+	_ = testhelpers.NewErrorTestServer(t, reg)
+	_ = testhelpers.NewSettingsUIFlowEchoServer(t, reg)
+	_ = testhelpers.NewRedirSessionEchoTS(t, reg)
+	_ = testhelpers.NewLoginUIFlowEchoServer(t, reg)
+
+	conf.MustSet(ctx, config.ViperKeySelfServiceSettingsPrivilegedAuthenticationAfter, "1m")
+
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/registration.schema.json")
+	conf.MustSet(ctx, config.ViperKeySecretsDefault, []string{"not-a-secure-session-key"})
+
+	settingsNodes := func(t *testing.T, id *identity.Identity) string {
+		client := testhelpers.NewHTTPClientWithIdentitySessionCookie(t, reg, id)
+		f := testhelpers.InitializeSettingsFlowViaBrowser(t, client, true, publicTS)
+		nodes, err := json.Marshal(f.Ui.Nodes)
+		require.NoError(t, err)
+		return string(nodes)
+	}
+
+	t.Run("case=passkey can be added", func(t *testing.T) {
+		nodes := settingsNodes(t, createIdentityWithPasskeys(t, reg, true))
+
+		onclick := gjson.Get(nodes, "#(attributes.name=="+node.PasskeyRegisterTrigger+").attributes.onclick").String()
+		assert.Contains(t, onclick, "window.__oryPasskeyRegistration(", nodes)
+		assert.Contains(t, onclick, `"residentKey":"required"`, nodes)
+		assert.True(t, gjson.Get(nodes, "#(attributes.name=="+node.PasskeyRegisterDisplayName+")").Exists(), nodes)
+		assert.False(t, gjson.Get(nodes, "#(attributes.name=="+node.PasskeyRemove+")").Exists(), nodes)
+	})
+
+	t.Run("case=the only passkey can not be removed", func(t *testing.T) {
+		nodes := settingsNodes(t, createIdentityWithPasskeys(t, reg, false, "my-passkey"))
+		assert.False(t, gjson.Get(nodes, "#(attributes.name=="+node.PasskeyRemove+")").Exists(), nodes)
+	})
+
+	t.Run("case=passkeys can be removed if there is another credential", func(t *testing.T) {
+		id := createIdentityWithPasskeys(t, reg, false, "first", "second")
+		nodes := settingsNodes(t, id)
+
+		removable := gjson.Get(nodes, "#(attributes.name=="+node.PasskeyRemove+")#.attributes.value").Array()
+		require.Len(t, removable, 2, nodes)
+		assert.Equal(t, fmt.Sprintf("%x", "first"), removable[0].String())
+		assert.Equal(t, fmt.Sprintf("%x", "second"), removable[1].String())
+
+		// The user handle is reused so that all passkeys of an identity are found on login.
+		var cc identity.CredentialsWebAuthnConfig
+		require.NoError(t, json.Unmarshal(id.Credentials[identity.CredentialsTypePasskey].Config, &cc))
+		options := gjson.Get(nodes, "#(attributes.name=="+node.PasskeyRegisterTrigger+").attributes.onclick").String()
+		assert.Contains(t, options, fmt.Sprintf("%q", base64.StdEncoding.EncodeToString(cc.UserHandle)), nodes)
+	})
+}
@@ -0,0 +1,143 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package passkey
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/continuity"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/errorx"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/selfservice/flow/registration"
+	"github.com/ory/kratos/selfservice/flow/settings"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+	"github.com/ory/x/decoderx"
+	"github.com/ory/x/randx"
+)
+
+var _ login.Strategy = new(Strategy)
+var _ registration.Strategy = new(Strategy)
+var _ settings.Strategy = new(Strategy)
+var _ identity.ActiveCredentialsCounter = new(Strategy)
+
+type strategyDependencies interface {
+	x.LoggingProvider
+	x.WriterProvider
+	x.CSRFTokenGeneratorProvider
+	x.CSRFProvider
+
+	config.Provider
+
+	continuity.ManagementProvider
+
+	errorx.ManagementProvider
+
+	registration.HandlerProvider
+	registration.HooksProvider
+	registration.ErrorHandlerProvider
+	registration.HookExecutorProvider
+	registration.FlowPersistenceProvider
+
+	login.HooksProvider
+	login.ErrorHandlerProvider
+	login.HookExecutorProvider
+	login.FlowPersistenceProvider
+	login.HandlerProvider
+
+	settings.FlowPersistenceProvider
+	settings.HookExecutorProvider
+	settings.HooksProvider
+	settings.ErrorHandlerProvider
+
+	identity.PrivilegedPoolProvider
+	identity.ValidationProvider
+	identity.ActiveCredentialsCounterStrategyProvider
+	identity.ManagementProvider
+
+	session.HandlerProvider
+	session.ManagementProvider
+}
+
+type Strategy struct {
+	d  strategyDependencies
+	hd *decoderx.HTTP
+}
+
+func NewStrategy(d strategyDependencies) *Strategy {
+	return &Strategy{
+		d:  d,
+		hd: decoderx.NewHTTP(),
+	}
+}
+
+func (s *Strategy) CountActiveMultiFactorCredentials(_ map[identity.CredentialsType]identity.Credentials) (count int, err error) {
+	return 0, nil
+}
+
+func (s *Strategy) CountActiveFirstFactorCredentials(cc map[identity.CredentialsType]identity.Credentials) (count int, err error) {
+	for _, c := range cc {
+		if c.Type == s.ID() && len(c.Config) > 0 && len(c.Identifiers) > 0 {
+			var conf identity.CredentialsWebAuthnConfig
+			if err = json.Unmarshal(c.Config, &conf); err != nil {
+				return 0, errors.WithStack(err)
+			}
+			count += len(conf.Credentials)
+		}
+	}
+	return
+}
+
+func (s *Strategy) ID() identity.CredentialsType {
+	return identity.CredentialsTypePasskey
+}
+
+func (s *Strategy) NodeGroup() node.UiNodeGroup {
+	return node.PasskeyGroup
+}
+
+func (s *Strategy) CompletedAuthenticationMethod(_ context.Context) session.AuthenticationMethod {
+	return session.AuthenticationMethod{
+		Method: s.ID(),
+		AAL:    identity.AuthenticatorAssuranceLevel1,
+	}
+}
+
+// newUserHandle returns a random user handle. The user handle is stored on the
+// authenticator and must not contain personal information such as the identity ID.
+func newUserHandle() []byte {
+	return []byte(randx.MustString(64, randx.AlphaNum))
+}
+
+// userName returns the identifier which is shown to the user when picking a passkey.
+func userName(i *identity.Identity) string {
+	for _, ct := range []identity.CredentialsType{
+		identity.CredentialsTypePassword,
+		identity.CredentialsTypeCodeAuth,
+		identity.CredentialsTypeWebAuthn,
+	} {
+		if c, ok := i.GetCredentials(ct); ok && len(c.Identifiers) > 0 {
+			return c.Identifiers[0]
+		}
+	}
+	return ""
+}
+
+// setUserHandleIdentifier sets the encoded user handle as the only identifier of the passkey credentials,
+// which is how the identity is found once the authenticator returns the user handle on login.
+func setUserHandleIdentifier(i *identity.Identity, userHandle []byte) {
+	c, ok := i.GetCredentials(identity.CredentialsTypePasskey)
+	if !ok {
+		return
+	}
+	c.Identifiers = []string{base64.RawURLEncoding.EncodeToString(userHandle)}
+	i.SetCredentials(identity.CredentialsTypePasskey, *c)
+}
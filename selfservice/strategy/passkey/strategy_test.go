@@ -0,0 +1,101 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package passkey_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/selfservice/strategy/passkey"
+	"github.com/ory/kratos/session"
+)
+
+var ctx = context.Background()
+
+func enablePasskey(conf *config.Config) {
+	conf.MustSet(ctx, config.ViperKeySelfServiceStrategyConfig+"."+string(identity.CredentialsTypePasskey)+".enabled", true)
+	conf.MustSet(ctx, config.ViperKeyPasskeyRPDisplayName, "Ory Corp")
+	conf.MustSet(ctx, config.ViperKeyPasskeyRPID, "localhost")
+	conf.MustSet(ctx, config.ViperKeyPasskeyRPOrigin, "http://localhost:4455")
+}
+
+func TestCompletedAuthenticationMethod(t *testing.T) {
+	_, reg := internal.NewFastRegistryWithMocks(t)
+	strategy := passkey.NewStrategy(reg)
+
+	assert.Equal(t, session.AuthenticationMethod{
+		Method: identity.CredentialsTypePasskey,
+		AAL:    identity.AuthenticatorAssuranceLevel1,
+	}, strategy.CompletedAuthenticationMethod(ctx))
+}
+
+func TestCountActiveCredentials(t *testing.T) {
+	_, reg := internal.NewFastRegistryWithMocks(t)
+	strategy := passkey.NewStrategy(reg)
+
+	for k, tc := range []struct {
+		in            map[identity.CredentialsType]identity.Credentials
+		expectedFirst int
+	}{
+		{
+			in: map[identity.CredentialsType]identity.Credentials{strategy.ID(): {
+				Type:   strategy.ID(),
+				Config: []byte{},
+			}},
+		},
+		{
+			in: map[identity.CredentialsType]identity.Credentials{strategy.ID(): {
+				Type:        strategy.ID(),
+				Identifiers: []string{"foo"},
+				Config:      []byte(`{"credentials": []}`),
+			}},
+		},
+		{
+			in: map[identity.CredentialsType]identity.Credentials{strategy.ID(): {
+				Type:   strategy.ID(),
+				Config: []byte(`{"credentials": [{}]}`),
+			}},
+		},
+		{
+			in: map[identity.CredentialsType]identity.Credentials{strategy.ID(): {
+				Type:        strategy.ID(),
+				Identifiers: []string{"foo"},
+				Config:      []byte(`{"credentials": [{}]}`),
+			}},
+			expectedFirst: 1,
+		},
+		{
+			in: map[identity.CredentialsType]identity.Credentials{strategy.ID(): {
+				Type:        strategy.ID(),
+				Identifiers: []string{"foo"},
+				Config:      []byte(`{"credentials": [{}, {"is_passwordless": false}]}`),
+			}},
+			expectedFirst: 2,
+		},
+		{
+			in: map[identity.CredentialsType]identity.Credentials{identity.CredentialsTypeWebAuthn: {
+				Type:        identity.CredentialsTypeWebAuthn,
+				Identifiers: []string{"foo"},
+				Config:      []byte(`{"credentials": [{"is_passwordless": true}]}`),
+			}},
+		},
+	} {
+		t.Run(fmt.Sprintf("case=%d", k), func(t *testing.T) {
+			actual, err := strategy.CountActiveFirstFactorCredentials(tc.in)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedFirst, actual)
+
+			actual, err = strategy.CountActiveMultiFactorCredentials(tc.in)
+			require.NoError(t, err)
+			assert.Equal(t, 0, actual)
+		})
+	}
+}
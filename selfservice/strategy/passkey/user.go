@@ -0,0 +1,50 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package passkey
+
+import "github.com/duo-labs/webauthn/webauthn"
+
+var _ webauthn.User = (*User)(nil)
+
+// User is a discoverable WebAuthn user. Unlike the WebAuthn second factor, the
+// name is stored on the authenticator and shown to the user when picking a
+// passkey, so it must identify the account and not the relying party.
+type User struct {
+	id   []byte
+	name string
+	c    []webauthn.Credential
+	cfg  *webauthn.Config
+}
+
+func NewUser(id []byte, name string, c []webauthn.Credential, cfg *webauthn.Config) *User {
+	return &User{
+		id:   id,
+		name: name,
+		c:    c,
+		cfg:  cfg,
+	}
+}
+
+func (u *User) WebAuthnID() []byte {
+	return u.id
+}
+
+func (u *User) WebAuthnName() string {
+	if u.name == "" {
+		return u.cfg.RPDisplayName
+	}
+	return u.name
+}
+
+func (u *User) WebAuthnDisplayName() string {
+	return u.WebAuthnName()
+}
+
+func (u *User) WebAuthnIcon() string {
+	return u.cfg.RPIcon
+}
+
+func (u *User) WebAuthnCredentials() []webauthn.Credential {
+	return u.c
+}
@@ -67,10 +67,15 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 		return nil, s.handleLoginError(w, r, f, &p, err)
 	}
 
-	i, c, err := s.d.PrivilegedIdentityPool().FindByCredentialsIdentifier(r.Context(), s.ID(), stringsx.Coalesce(p.Identifier, p.LegacyIdentifier))
+	identifier := stringsx.Coalesce(p.Identifier, p.LegacyIdentifier)
+	if err := s.d.LoginThrottler().Check(r, identifier); err != nil {
+		return nil, s.handleLoginError(w, r, f, &p, err)
+	}
+
+	i, c, err := s.d.PrivilegedIdentityPool().FindByCredentialsIdentifier(r.Context(), s.ID(), identifier)
 	if err != nil {
 		time.Sleep(x.RandomDelay(s.d.Config().HasherArgon2(r.Context()).ExpectedDuration, s.d.Config().HasherArgon2(r.Context()).ExpectedDeviation))
-		return nil, s.handleLoginError(w, r, f, &p, errors.WithStack(schema.NewInvalidCredentialsError()))
+		return nil, s.handleLoginError(w, r, f, &p, s.recordLoginFailure(r, identifier))
 	}
 
 	var o identity.CredentialsPassword
@@ -80,10 +85,14 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 	}
 
 	if err := hash.Compare(r.Context(), []byte(p.Password), []byte(o.HashedPassword)); err != nil {
-		return nil, s.handleLoginError(w, r, f, &p, errors.WithStack(schema.NewInvalidCredentialsError()))
+		return nil, s.handleLoginError(w, r, f, &p, s.recordLoginFailure(r, identifier))
+	}
+
+	if err := s.d.LoginThrottler().RecordSuccess(r, identifier); err != nil {
+		return nil, s.handleLoginError(w, r, f, &p, err)
 	}
 
-	if !s.d.Hasher(r.Context()).Understands([]byte(o.HashedPassword)) {
+	if hasher := s.d.Hasher(r.Context()); !hasher.Understands([]byte(o.HashedPassword)) || hasher.NeedsRehash(r.Context(), []byte(o.HashedPassword)) {
 		if err := s.migratePasswordHash(r.Context(), i.ID, []byte(p.Password)); err != nil {
 			return nil, s.handleLoginError(w, r, f, &p, err)
 		}
@@ -98,6 +107,14 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 	return i, nil
 }
 
+// recordLoginFailure counts the failed attempt and returns the error to show to the user.
+func (s *Strategy) recordLoginFailure(r *http.Request, identifier string) error {
+	if err := s.d.LoginThrottler().RecordFailure(r, identifier); err != nil {
+		return err
+	}
+	return errors.WithStack(schema.NewInvalidCredentialsError())
+}
+
 func (s *Strategy) migratePasswordHash(ctx context.Context, identifier uuid.UUID, password []byte) error {
 	hpw, err := s.d.Hasher(ctx).Generate(ctx, password)
 	if err != nil {
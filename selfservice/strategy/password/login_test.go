@@ -35,6 +35,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tidwall/gjson"
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
@@ -466,6 +467,40 @@ func TestCompleteLogin(t *testing.T) {
 		})
 	})
 
+	t.Run("should lock out the identifier after too many failed attempts", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceProtectionEnabled, true)
+		conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceMaxAttemptsIdentifier, 2)
+		conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceBackoffAfter, 10)
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeySelfServiceLoginBruteForceProtectionEnabled, false)
+		})
+
+		identifier, pwd := x.NewUUID().String(), "password"
+		createIdentity(ctx, reg, t, identifier, pwd)
+
+		for k := 0; k < 2; k++ {
+			body := expectValidationError(t, true, false, false, func(v url.Values) {
+				v.Set("identifier", identifier)
+				v.Set("password", "not-password")
+			})
+			assert.EqualValues(t, text.ErrorValidationInvalidCredentials, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+		}
+
+		body := expectValidationError(t, true, false, false, func(v url.Values) {
+			v.Set("identifier", identifier)
+			v.Set("password", pwd)
+		})
+		assert.EqualValues(t, text.ErrorValidationLoginLockedOut, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+		assert.NotEmpty(t, gjson.Get(body, "ui.messages.0.context.locked_until").String(), "%s", body)
+
+		require.NoError(t, reg.LoginThrottler().Clear(ctx, identifier))
+		body = testhelpers.SubmitLoginForm(t, true, nil, publicTS, func(v url.Values) {
+			v.Set("identifier", identifier)
+			v.Set("password", pwd)
+		}, false, false, http.StatusOK, publicTS.URL+login.RouteSubmitFlow)
+		assert.Equal(t, identifier, gjson.Get(body, "session.identity.traits.subject").String(), "%s", body)
+	})
+
 	t.Run("should pass with real request", func(t *testing.T) {
 		identifier, pwd := x.NewUUID().String(), "password"
 		createIdentity(ctx, reg, t, identifier, pwd)
@@ -862,4 +897,59 @@ func TestCompleteLogin(t *testing.T) {
 			false, true, http.StatusOK, redirTS.URL)
 		assert.Equal(t, identifier, gjson.Get(body, "identity.traits.subject").String(), "%s", body)
 	})
+
+	t.Run("should upgrade password hash if the bcrypt cost was increased", func(t *testing.T) {
+		identifier, pwd := x.NewUUID().String(), "password"
+		p, err := hash.NewHasherBcrypt(reg).Generate(ctx, []byte(pwd))
+		require.NoError(t, err)
+		cost, err := bcrypt.Cost(p)
+		require.NoError(t, err)
+
+		iId := x.NewUUID()
+		require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(ctx, &identity.Identity{
+			ID:     iId,
+			Traits: identity.Traits(fmt.Sprintf(`{"subject":"%s"}`, identifier)),
+			Credentials: map[identity.CredentialsType]identity.Credentials{
+				identity.CredentialsTypePassword: {
+					Type:        identity.CredentialsTypePassword,
+					Identifiers: []string{identifier},
+					Config:      sqlxx.JSONRawMessage(`{"hashed_password":"` + string(p) + `"}`),
+				},
+			},
+			VerifiableAddresses: []identity.VerifiableAddress{
+				{
+					ID:         x.NewUUID(),
+					Value:      identifier,
+					Verified:   true,
+					CreatedAt:  time.Now(),
+					IdentityID: iId,
+				},
+			},
+		}))
+
+		conf.MustSet(ctx, config.ViperKeyHasherBcryptCost, cost+1)
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeyHasherBcryptCost, cost)
+		})
+
+		var values = func(v url.Values) {
+			v.Set("identifier", identifier)
+			v.Set("method", identity.CredentialsTypePassword.String())
+			v.Set("password", pwd)
+		}
+
+		body := testhelpers.SubmitLoginForm(t, false, testhelpers.NewClientWithCookies(t), publicTS, values,
+			false, false, http.StatusOK, redirTS.URL)
+		assert.Equal(t, identifier, gjson.Get(body, "identity.traits.subject").String(), "%s", body)
+
+		_, c, err := reg.PrivilegedIdentityPool().FindByCredentialsIdentifier(ctx, identity.CredentialsTypePassword, identifier)
+		require.NoError(t, err)
+		var o identity.CredentialsPassword
+		require.NoError(t, json.NewDecoder(bytes.NewBuffer(c.Config)).Decode(&o))
+		assert.False(t, reg.Hasher(ctx).NeedsRehash(ctx, []byte(o.HashedPassword)), "%s", o.HashedPassword)
+
+		upgraded, err := bcrypt.Cost([]byte(o.HashedPassword))
+		require.NoError(t, err)
+		assert.Equal(t, cost+1, upgraded)
+	})
 }
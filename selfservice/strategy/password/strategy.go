@@ -14,6 +14,7 @@ import (
 
 	"github.com/ory/x/decoderx"
 
+	"github.com/ory/kratos/bruteforce"
 	"github.com/ory/kratos/continuity"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/hash"
@@ -66,6 +67,8 @@ type registrationStrategyDependencies interface {
 
 	session.HandlerProvider
 	session.ManagementProvider
+
+	bruteforce.ThrottlerProvider
 }
 
 type Strategy struct {
@@ -79,19 +79,14 @@ func (s *Strategy) SettingsStrategyID() string {
 func (s *Strategy) RegisterSettingsRoutes(public *x.RouterPublic) {}
 
 func (s *Strategy) PopulateSettingsMethod(r *http.Request, id *identity.Identity, f *settings.Flow) error {
-	schemas, err := s.d.Config().IdentityTraitsSchemas(r.Context())
-	if err != nil {
-		return err
-	}
-
-	traitsSchema, err := schemas.FindSchemaByID(id.SchemaID)
+	traitsSchema, err := s.d.IdentityTraitsSchema(r.Context(), id.SchemaID)
 	if err != nil {
 		return err
 	}
 
 	// use a schema compiler that disables identifiers
 	schemaCompiler := jsonschema.NewCompiler()
-	nodes, err := container.NodesFromJSONSchema(r.Context(), node.ProfileGroup, traitsSchema.URL, "", schemaCompiler)
+	nodes, err := container.NodesFromJSONSchema(r.Context(), node.ProfileGroup, traitsSchema.URL.String(), "", schemaCompiler)
 	if err != nil {
 		return err
 	}
@@ -259,11 +254,7 @@ func (s *Strategy) handleSettingsError(w http.ResponseWriter, r *http.Request, p
 // newSettingsProfileDecoder returns a decoderx.HTTPDecoderOption with a JSON Schema for type assertion and
 // validation.
 func (s *Strategy) newSettingsProfileDecoder(ctx context.Context, i *identity.Identity) (decoderx.HTTPDecoderOption, error) {
-	schemas, err := s.d.IdentityTraitsSchemas(ctx)
-	if err != nil {
-		return nil, err
-	}
-	ss, err := schemas.GetByID(i.SchemaID)
+	ss, err := s.d.IdentityTraitsSchema(ctx, i.SchemaID)
 	if err != nil {
 		return nil, err
 	}
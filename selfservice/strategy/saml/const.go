@@ -0,0 +1,11 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+const (
+	sessionName = "ory_kratos_saml_auth_session"
+
+	// internalContextKeyResponse is the key of the validated SAML response in the flow's internal context.
+	internalContextKeyResponse = "response"
+)
@@ -0,0 +1,269 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/x"
+)
+
+// NewTestKeyPair returns a self-signed PEM encoded certificate and its PEM encoded RSA private key.
+func NewTestKeyPair(t *testing.T) (certificate string, privateKey string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "kratos-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour * 24),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+// TestIdentityProvider is a minimal SAML identity provider which issues signed responses.
+type TestIdentityProvider struct {
+	EntityID    string
+	SSOURL      string
+	Certificate string
+
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func NewTestIdentityProvider(t *testing.T, entityID, ssoURL string) *TestIdentityProvider {
+	cert, key := NewTestKeyPair(t)
+
+	certs, err := parseCertificates(cert)
+	require.NoError(t, err)
+	privateKey, err := parsePrivateKey(key)
+	require.NoError(t, err)
+
+	return &TestIdentityProvider{EntityID: entityID, SSOURL: ssoURL, Certificate: cert, key: privateKey, cert: certs[0]}
+}
+
+// Metadata returns the identity provider's SAML metadata.
+func (idp *TestIdentityProvider) Metadata() []byte {
+	return []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<md:EntityDescriptor xmlns:md="%s" xmlns:ds="%s" entityID="%s">
+  <md:IDPSSODescriptor protocolSupportEnumeration="%s">
+    <md:KeyDescriptor use="signing">
+      <ds:KeyInfo>
+        <ds:X509Data>
+          <ds:X509Certificate>%s</ds:X509Certificate>
+        </ds:X509Data>
+      </ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:SingleSignOnService Binding="%s" Location="%s/post"/>
+    <md:SingleSignOnService Binding="%s" Location="%s"/>
+  </md:IDPSSODescriptor>
+</md:EntityDescriptor>`, nsMetadata, nsDSig, idp.EntityID, nsProtocol, base64.StdEncoding.EncodeToString(idp.cert.Raw),
+		bindingHTTPPost, idp.SSOURL, bindingHTTPRedirect, idp.SSOURL))
+}
+
+// TestAuthnRequest is an authentication request received by the TestIdentityProvider.
+type TestAuthnRequest struct {
+	ID         string
+	Issuer     string
+	ACS        string
+	RelayState string
+}
+
+// ParseAuthnRequest verifies the signature of the HTTP-Redirect binding request and decodes the authentication
+// request.
+func (idp *TestIdentityProvider) ParseAuthnRequest(t *testing.T, r *http.Request, spCertificate string) *TestAuthnRequest {
+	certs, err := parseCertificates(spCertificate)
+	require.NoError(t, err)
+
+	// The signature covers the raw query parameters in the order mandated by the specification.
+	raw := map[string]string{}
+	for _, pair := range strings.Split(r.URL.RawQuery, "&") {
+		k, v, _ := strings.Cut(pair, "=")
+		raw[k] = v
+	}
+	signed := "SAMLRequest=" + raw["SAMLRequest"]
+	if _, ok := raw["RelayState"]; ok {
+		signed += "&RelayState=" + raw["RelayState"]
+	}
+	signed += "&SigAlg=" + raw["SigAlg"]
+
+	query := r.URL.Query()
+	require.Equal(t, dsig.RSASHA256SignatureMethod, query.Get("SigAlg"))
+	signature, err := base64.StdEncoding.DecodeString(query.Get("Signature"))
+	require.NoError(t, err)
+	hashed := sha256.Sum256([]byte(signed))
+	require.NoError(t, rsa.VerifyPKCS1v15(certs[0].PublicKey.(*rsa.PublicKey), crypto.SHA256, hashed[:], signature))
+
+	deflated, err := base64.StdEncoding.DecodeString(query.Get("SAMLRequest"))
+	require.NoError(t, err)
+	inflated, err := io.ReadAll(flate.NewReader(bytes.NewReader(deflated)))
+	require.NoError(t, err)
+
+	request, err := parseXML(inflated)
+	require.NoError(t, err)
+	require.True(t, isElement(request, nsProtocol, "AuthnRequest"))
+	require.Equal(t, idp.SSOURL, attr(request, "Destination"))
+
+	issuer := childElement(request, nsAssertion, "Issuer")
+	require.NotNil(t, issuer)
+
+	return &TestAuthnRequest{
+		ID:         attr(request, "ID"),
+		Issuer:     elementText(issuer),
+		ACS:        attr(request, "AssertionConsumerServiceURL"),
+		RelayState: query.Get("RelayState"),
+	}
+}
+
+// TestResponse describes the response issued by the TestIdentityProvider. Empty fields are set to valid defaults by
+// TestIdentityProvider.Response.
+type TestResponse struct {
+	// AssertionID defaults to a random ID, so that assertions are not rejected as replays.
+	AssertionID  string
+	InResponseTo string
+	// ConfirmationInResponseTo overrides the InResponseTo of the assertion's subject confirmation.
+	ConfirmationInResponseTo *string
+	// OmitAudienceRestriction removes the audience restriction from the assertion's conditions.
+	OmitAudienceRestriction bool
+	Destination             string
+	Audience                string
+	Issuer                  string
+	Subject                 string
+	Status                  string
+	NotBefore               time.Time
+	NotOnOrAfter            time.Time
+	Attributes              map[string][]string
+	SignResponse            bool
+	SignAssertion           bool
+	// SignatureMethod overrides the default RSA-SHA256 signature method.
+	SignatureMethod string
+}
+
+// Response returns the XML encoded SAML response.
+func (idp *TestIdentityProvider) Response(t *testing.T, r TestResponse) []byte {
+	now := time.Now().UTC()
+	if r.AssertionID == "" {
+		r.AssertionID = "id-" + x.NewUUID().String()
+	}
+	if r.Issuer == "" {
+		r.Issuer = idp.EntityID
+	}
+	confirmationInResponseTo := r.InResponseTo
+	if r.ConfirmationInResponseTo != nil {
+		confirmationInResponseTo = *r.ConfirmationInResponseTo
+	}
+	audienceRestriction := `<saml:AudienceRestriction><saml:Audience>` + xmlEscape(r.Audience) + `</saml:Audience></saml:AudienceRestriction>`
+	if r.OmitAudienceRestriction {
+		audienceRestriction = ""
+	}
+	if r.Status == "" {
+		r.Status = statusSuccess
+	}
+	if r.NotBefore.IsZero() {
+		r.NotBefore = now.Add(-time.Minute)
+	}
+	if r.NotOnOrAfter.IsZero() {
+		r.NotOnOrAfter = now.Add(time.Minute * 5)
+	}
+
+	var attributes strings.Builder
+	names := make([]string, 0, len(r.Attributes))
+	for name := range r.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&attributes, `<saml:Attribute Name="%s">`, xmlEscape(name))
+		for _, value := range r.Attributes[name] {
+			fmt.Fprintf(&attributes, `<saml:AttributeValue>%s</saml:AttributeValue>`, xmlEscape(value))
+		}
+		attributes.WriteString(`</saml:Attribute>`)
+	}
+
+	assertion := fmt.Sprintf(`<saml:Assertion xmlns:saml="%[1]s" ID="%[13]s" Version="2.0" IssueInstant="%[2]s">`+
+		`<saml:Issuer>%[3]s</saml:Issuer>`+
+		`<saml:Subject><saml:NameID Format="%[4]s">%[5]s</saml:NameID>`+
+		`<saml:SubjectConfirmation Method="%[6]s"><saml:SubjectConfirmationData InResponseTo="%[7]s" NotOnOrAfter="%[8]s" Recipient="%[9]s"/></saml:SubjectConfirmation>`+
+		`</saml:Subject>`+
+		`<saml:Conditions NotBefore="%[10]s" NotOnOrAfter="%[8]s">%[11]s</saml:Conditions>`+
+		`<saml:AuthnStatement AuthnInstant="%[2]s" SessionIndex="session-index"/>`+
+		`<saml:AttributeStatement>%[12]s</saml:AttributeStatement>`+
+		`</saml:Assertion>`,
+		nsAssertion, now.Format(time.RFC3339), xmlEscape(r.Issuer), nameIDFormatPersistent, xmlEscape(r.Subject), confirmationBearer,
+		xmlEscape(confirmationInResponseTo), r.NotOnOrAfter.Format(time.RFC3339), xmlEscape(r.Destination), r.NotBefore.Format(time.RFC3339),
+		audienceRestriction, attributes.String(), xmlEscape(r.AssertionID))
+	if r.SignAssertion {
+		assertion = idp.sign(t, assertion, r.SignatureMethod)
+	}
+
+	response := fmt.Sprintf(`<samlp:Response xmlns:samlp="%[1]s" xmlns:saml="%[2]s" ID="response-id" Version="2.0" IssueInstant="%[3]s" Destination="%[4]s" InResponseTo="%[5]s">`+
+		`<saml:Issuer>%[6]s</saml:Issuer>`+
+		`<samlp:Status><samlp:StatusCode Value="%[7]s"/></samlp:Status>`+
+		`%[8]s</samlp:Response>`,
+		nsProtocol, nsAssertion, now.Format(time.RFC3339), xmlEscape(r.Destination), xmlEscape(r.InResponseTo),
+		xmlEscape(r.Issuer), xmlEscape(r.Status), assertion)
+	if r.SignResponse {
+		response = idp.sign(t, response, r.SignatureMethod)
+	}
+
+	return []byte(response)
+}
+
+// EncodedResponse returns the response as it is posted to the assertion consumer service.
+func (idp *TestIdentityProvider) EncodedResponse(t *testing.T, r TestResponse) string {
+	return base64.StdEncoding.EncodeToString(idp.Response(t, r))
+}
+
+// sign adds an enveloped signature of the document's root element after its issuer.
+func (idp *TestIdentityProvider) sign(t *testing.T, doc, signatureMethod string) string {
+	root, err := parseXML([]byte(doc))
+	require.NoError(t, err)
+
+	ctx, err := dsig.NewSigningContext(idp.key, [][]byte{idp.cert.Raw})
+	require.NoError(t, err)
+	ctx.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+	if signatureMethod != "" {
+		require.NoError(t, ctx.SetSignatureMethod(signatureMethod))
+	}
+
+	signature, err := ctx.ConstructSignature(root, true)
+	require.NoError(t, err)
+
+	issuer := childElement(root, nsAssertion, "Issuer")
+	require.NotNil(t, issuer)
+	root.InsertChildAt(issuer.Index()+1, signature)
+
+	signed := etree.NewDocument()
+	signed.SetRoot(root)
+	out, err := signed.WriteToString()
+	require.NoError(t, err)
+	return out
+}
@@ -0,0 +1,17 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/node"
+)
+
+func NewLinkNode(provider string) *node.Node {
+	return node.NewInputField("saml_link", provider, node.SAMLGroup, node.InputAttributeTypeSubmit).WithMetaLabel(text.NewInfoSelfServiceSettingsUpdateLinkSAML(provider))
+}
+
+func NewUnlinkNode(provider string) *node.Node {
+	return node.NewInputField("saml_unlink", provider, node.SAMLGroup, node.InputAttributeTypeSubmit).WithMetaLabel(text.NewInfoSelfServiceSettingsUpdateUnlinkSAML(provider))
+}
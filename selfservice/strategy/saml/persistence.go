@@ -0,0 +1,53 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/ory/herodot"
+
+	"github.com/ory/kratos/text"
+)
+
+// ErrAssertionReplayed is returned if an assertion is used again before it expired.
+var ErrAssertionReplayed = herodot.ErrBadRequest.WithID(text.ErrIDSelfServiceSAMLAssertionReplayed).WithReason("The SAML assertion was already used.")
+
+// UsedAssertion records an assertion which was accepted, so that it can not be replayed until it expires.
+type UsedAssertion struct {
+	ID  uuid.UUID `db:"id"`
+	NID uuid.UUID `db:"nid"`
+
+	// AssertionHMAC is the HMAC of the issuer and the ID of the assertion.
+	AssertionHMAC string `db:"assertion_hmac"`
+
+	ExpiresAt time.Time `db:"expires_at"`
+
+	// CreatedAt is a helper struct field for gobuffalo.pop.
+	CreatedAt time.Time `db:"created_at"`
+
+	// UpdatedAt is a helper struct field for gobuffalo.pop.
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (UsedAssertion) TableName(context.Context) string {
+	return "selfservice_saml_used_assertions"
+}
+
+type (
+	AssertionPersister interface {
+		// UseSAMLAssertion records the assertion of the issuer as used until it expires. It returns
+		// ErrAssertionReplayed if the assertion was already used and has not expired yet.
+		UseSAMLAssertion(ctx context.Context, issuer, assertionID string, expiresAt time.Time) error
+
+		// DeleteExpiredSAMLAssertions removes used assertions which expired before the given time.
+		DeleteExpiredSAMLAssertions(ctx context.Context, at time.Time, limit int) error
+	}
+
+	AssertionPersistenceProvider interface {
+		SAMLAssertionPersister() AssertionPersister
+	}
+)
@@ -0,0 +1,74 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/urlx"
+)
+
+type Configuration struct {
+	// ID is the provider's ID
+	ID string `json:"id"`
+
+	// Label represents an optional label which can be used in the UI generation.
+	Label string `json:"label"`
+
+	// IDPMetadataURL is the URL of the identity provider's SAML metadata. It can be a http(s)://, file:// or base64://
+	// URL. If set, neither `idp_entity_id`, `idp_sso_url` nor `idp_certificate` are required.
+	IDPMetadataURL string `json:"idp_metadata_url"`
+
+	// IDPEntityID is the entity ID (issuer) of the identity provider.
+	IDPEntityID string `json:"idp_entity_id"`
+
+	// IDPSSOURL is the identity provider's single sign-on URL supporting the HTTP-Redirect binding.
+	IDPSSOURL string `json:"idp_sso_url"`
+
+	// IDPCertificate is the PEM encoded certificate the identity provider signs responses and assertions with.
+	IDPCertificate string `json:"idp_certificate"`
+
+	// SPCertificate is the PEM encoded certificate which is published in the service provider's metadata.
+	SPCertificate string `json:"sp_certificate"`
+
+	// SPPrivateKey is the PEM encoded RSA private key of `sp_certificate`. It is used to sign authentication requests.
+	SPPrivateKey string `json:"sp_private_key"`
+
+	// NameIDFormat is the requested format of the subject's name identifier. Defaults to
+	// `urn:oasis:names:tc:SAML:2.0:nameid-format:persistent`.
+	NameIDFormat string `json:"name_id_format"`
+
+	// Mapper specifies the JSONNet code snippet which uses the identity provider's assertion (the subject and the
+	// attributes) to hydrate the identity's data.
+	//
+	// It can be either a URL (file://, http(s)://, base64://) or an inline JSONNet code snippet.
+	Mapper string `json:"mapper_url"`
+}
+
+// EntityID returns the service provider's entity ID, which is also the URL of its metadata.
+func (p Configuration) EntityID(public *url.URL) string {
+	return urlx.AppendPaths(public, strings.Replace(RouteMetadata, ":provider", p.ID, 1)).String()
+}
+
+// ACS returns the service provider's assertion consumer service URL.
+func (p Configuration) ACS(public *url.URL) string {
+	return urlx.AppendPaths(public, strings.Replace(RouteACS, ":provider", p.ID, 1)).String()
+}
+
+type ConfigurationCollection struct {
+	Providers []Configuration `json:"providers"`
+}
+
+func (c ConfigurationCollection) Provider(id string) (*Configuration, error) {
+	for k := range c.Providers {
+		if c.Providers[k].ID == id {
+			return &c.Providers[k], nil
+		}
+	}
+	return nil, errors.WithStack(herodot.ErrNotFound.WithReasonf(`SAML Provider "%s" is unknown or has not been configured`, id))
+}
@@ -0,0 +1,14 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	_ "embed"
+)
+
+//go:embed .schema/link.schema.json
+var linkSchema []byte
+
+//go:embed .schema/settings.schema.json
+var settingsSchema []byte
@@ -0,0 +1,504 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/pkg/errors"
+	dsig "github.com/russellhaering/goxmldsig"
+
+	"github.com/ory/herodot"
+)
+
+const (
+	bindingHTTPPost     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	bindingHTTPRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+
+	nameIDFormatPersistent = "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent"
+	statusSuccess          = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	confirmationBearer     = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+
+	// maxClockSkew is the tolerated difference between the clocks of the identity and the service provider.
+	maxClockSkew = 3 * time.Minute
+)
+
+// Assertion contains the data of a validated SAML assertion. It is passed to the Jsonnet mapper as `claims`.
+type Assertion struct {
+	Issuer       string              `json:"issuer"`
+	Subject      string              `json:"subject"`
+	NameIDFormat string              `json:"name_id_format,omitempty"`
+	SessionIndex string              `json:"session_index,omitempty"`
+	Attributes   map[string][]string `json:"attributes"`
+}
+
+// validatedResponse is a SAML response whose signature and conditions were validated.
+type validatedResponse struct {
+	Assertion *Assertion
+
+	// AssertionID is the ID of the assertion. It can not be used again until the assertion expired.
+	AssertionID string
+
+	// InResponseTo is the ID of the authentication request which the signed assertion answers.
+	InResponseTo string
+
+	// ExpiresAt is the time until which the assertion is accepted, including the tolerated clock skew.
+	ExpiresAt time.Time
+}
+
+type identityProvider struct {
+	EntityID     string
+	SSOURL       string
+	Certificates []*x509.Certificate
+}
+
+type serviceProvider struct {
+	config       *Configuration
+	entityID     string
+	acs          string
+	nameIDFormat string
+	idp          *identityProvider
+	key          *rsa.PrivateKey
+	cert         *x509.Certificate
+}
+
+func newServiceProvider(c *Configuration, public *url.URL, idpMetadata []byte) (*serviceProvider, error) {
+	sp := &serviceProvider{
+		config:       c,
+		entityID:     c.EntityID(public),
+		acs:          c.ACS(public),
+		nameIDFormat: c.NameIDFormat,
+	}
+	if sp.nameIDFormat == "" {
+		sp.nameIDFormat = nameIDFormatPersistent
+	}
+
+	if len(idpMetadata) > 0 {
+		idp, err := parseIdentityProviderMetadata(idpMetadata)
+		if err != nil {
+			return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to parse the metadata of SAML Provider %s: %s", c.ID, err))
+		}
+		sp.idp = idp
+	} else {
+		certs, err := parseCertificates(c.IDPCertificate)
+		if err != nil {
+			return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to parse the certificate of SAML Provider %s: %s", c.ID, err))
+		}
+		sp.idp = &identityProvider{EntityID: c.IDPEntityID, SSOURL: c.IDPSSOURL, Certificates: certs}
+	}
+
+	if sp.idp.EntityID == "" || sp.idp.SSOURL == "" || len(sp.idp.Certificates) == 0 {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("SAML Provider %s must be configured with an entity ID, a single sign-on URL and a signing certificate.", c.ID))
+	}
+
+	certs, err := parseCertificates(c.SPCertificate)
+	if err != nil || len(certs) != 1 {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to parse the service provider certificate of SAML Provider %s.", c.ID))
+	}
+	sp.cert = certs[0]
+
+	sp.key, err = parsePrivateKey(c.SPPrivateKey)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to parse the service provider private key of SAML Provider %s: %s", c.ID, err))
+	}
+
+	return sp, nil
+}
+
+func parseCertificates(in string) ([]*x509.Certificate, error) {
+	rest := []byte(strings.TrimSpace(in))
+	if len(rest) > 0 && !bytes.HasPrefix(rest, []byte("-----")) {
+		// Certificates copied from SAML metadata are base64 encoded DER without PEM armor.
+		der, err := decodeBase64(string(rest))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return []*x509.Certificate{cert}, nil
+	}
+
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+	return certs, nil
+}
+
+func parsePrivateKey(in string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(in)))
+	if block == nil {
+		return nil, errors.New("no PEM encoded private key found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("only RSA private keys are supported")
+	}
+	return rsaKey, nil
+}
+
+func parseIdentityProviderMetadata(raw []byte) (*identityProvider, error) {
+	root, err := parseXML(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptor := root
+	if isElement(root, nsMetadata, "EntitiesDescriptor") {
+		descriptor = nil
+		for _, ed := range childElements(root, nsMetadata, "EntityDescriptor") {
+			if childElement(ed, nsMetadata, "IDPSSODescriptor") != nil {
+				descriptor = ed
+				break
+			}
+		}
+	}
+	if descriptor == nil || !isElement(descriptor, nsMetadata, "EntityDescriptor") {
+		return nil, errors.New("the metadata does not contain an entity descriptor")
+	}
+
+	idpDescriptor := childElement(descriptor, nsMetadata, "IDPSSODescriptor")
+	if idpDescriptor == nil {
+		return nil, errors.New("the metadata does not describe an identity provider")
+	}
+
+	idp := &identityProvider{EntityID: attr(descriptor, "entityID")}
+	for _, sso := range childElements(idpDescriptor, nsMetadata, "SingleSignOnService") {
+		if attr(sso, "Binding") == bindingHTTPRedirect {
+			idp.SSOURL = attr(sso, "Location")
+			break
+		}
+	}
+
+	for _, kd := range childElements(idpDescriptor, nsMetadata, "KeyDescriptor") {
+		if use := attr(kd, "use"); use != "" && use != "signing" {
+			continue
+		}
+
+		keyInfo := childElement(kd, nsDSig, "KeyInfo")
+		if keyInfo == nil {
+			continue
+		}
+
+		for _, data := range childElements(keyInfo, nsDSig, "X509Data") {
+			for _, c := range childElements(data, nsDSig, "X509Certificate") {
+				certs, err := parseCertificates(elementText(c))
+				if err != nil {
+					return nil, err
+				}
+				idp.Certificates = append(idp.Certificates, certs...)
+			}
+		}
+	}
+
+	return idp, nil
+}
+
+// Metadata returns the service provider's SAML metadata.
+func (sp *serviceProvider) Metadata() []byte {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&b, `<md:EntityDescriptor xmlns:md="%s" xmlns:ds="%s" entityID="%s">`, nsMetadata, nsDSig, xmlEscape(sp.entityID))
+	fmt.Fprintf(&b, `<md:SPSSODescriptor AuthnRequestsSigned="true" WantAssertionsSigned="true" protocolSupportEnumeration="%s">`, nsProtocol)
+	fmt.Fprintf(&b, `<md:KeyDescriptor use="signing"><ds:KeyInfo><ds:X509Data><ds:X509Certificate>%s</ds:X509Certificate></ds:X509Data></ds:KeyInfo></md:KeyDescriptor>`, base64.StdEncoding.EncodeToString(sp.cert.Raw))
+	fmt.Fprintf(&b, `<md:NameIDFormat>%s</md:NameIDFormat>`, xmlEscape(sp.nameIDFormat))
+	fmt.Fprintf(&b, `<md:AssertionConsumerService Binding="%s" Location="%s" index="1"/>`, bindingHTTPPost, xmlEscape(sp.acs))
+	b.WriteString(`</md:SPSSODescriptor></md:EntityDescriptor>`)
+	return b.Bytes()
+}
+
+// AuthnRequestURL returns the identity provider URL which starts the authentication using the HTTP-Redirect binding
+// and the ID of the signed authentication request.
+func (sp *serviceProvider) AuthnRequestURL(relayState string, now time.Time) (string, string, error) {
+	id := make([]byte, 20)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	requestID := "id-" + hex.EncodeToString(id)
+
+	var request bytes.Buffer
+	fmt.Fprintf(&request, `<samlp:AuthnRequest xmlns:samlp="%s" xmlns:saml="%s" ID="%s" Version="2.0" IssueInstant="%s" Destination="%s" ProtocolBinding="%s" AssertionConsumerServiceURL="%s">`,
+		nsProtocol, nsAssertion, requestID, now.UTC().Format(time.RFC3339), xmlEscape(sp.idp.SSOURL), bindingHTTPPost, xmlEscape(sp.acs))
+	fmt.Fprintf(&request, `<saml:Issuer>%s</saml:Issuer>`, xmlEscape(sp.entityID))
+	fmt.Fprintf(&request, `<samlp:NameIDPolicy Format="%s" AllowCreate="true"/>`, xmlEscape(sp.nameIDFormat))
+	request.WriteString(`</samlp:AuthnRequest>`)
+
+	var deflated bytes.Buffer
+	w, err := flate.NewWriter(&deflated, flate.BestCompression)
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	if _, err := w.Write(request.Bytes()); err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	if err := w.Close(); err != nil {
+		return "", "", errors.WithStack(err)
+	}
+
+	// The signature covers the URL encoded query parameters in this exact order, see section 3.4.4.1 of the SAML
+	// 2.0 bindings specification.
+	query := "SAMLRequest=" + url.QueryEscape(base64.StdEncoding.EncodeToString(deflated.Bytes()))
+	if relayState != "" {
+		query += "&RelayState=" + url.QueryEscape(relayState)
+	}
+	query += "&SigAlg=" + url.QueryEscape(dsig.RSASHA256SignatureMethod)
+
+	hashed := sha256.Sum256([]byte(query))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, sp.key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	query += "&Signature=" + url.QueryEscape(base64.StdEncoding.EncodeToString(signature))
+
+	separator := "?"
+	if strings.Contains(sp.idp.SSOURL, "?") {
+		separator = "&"
+	}
+
+	return sp.idp.SSOURL + separator + query, requestID, nil
+}
+
+// ParseResponse validates the base64 encoded SAML response received by the assertion consumer service. The caller
+// must ensure that the response answers the request sent by this browser and that the assertion is not replayed.
+func (sp *serviceProvider) ParseResponse(encoded string, now time.Time) (*validatedResponse, error) {
+	raw, err := decodeBase64(encoded)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReason("The SAML response is not base64 encoded.").WithDebug(err.Error()))
+	}
+
+	response, err := sp.parseResponse(raw, now)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf("Unable to validate the SAML response: %s", err))
+	}
+
+	return response, nil
+}
+
+func (sp *serviceProvider) parseResponse(raw []byte, now time.Time) (*validatedResponse, error) {
+	response, err := parseXML(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isElement(response, nsProtocol, "Response") {
+		return nil, errors.New("the document is not a SAML response")
+	}
+
+	// If the response is signed, all data is read from the verified copy of the response.
+	responseSigned := true
+	if verified, err := verifySignature(response, sp.idp.Certificates, now); errors.Is(err, errNotSigned) {
+		responseSigned = false
+	} else if err != nil {
+		return nil, err
+	} else {
+		response = verified
+	}
+
+	if destination := attr(response, "Destination"); destination != "" && destination != sp.acs {
+		return nil, errors.Errorf("the response is destined for %s", destination)
+	}
+
+	if issuer := childElement(response, nsAssertion, "Issuer"); issuer != nil && elementText(issuer) != sp.idp.EntityID {
+		return nil, errors.Errorf("the response was issued by %s", elementText(issuer))
+	}
+
+	status := childElement(response, nsProtocol, "Status")
+	if status == nil {
+		return nil, errors.New("the response is missing the status")
+	} else if code := childElement(status, nsProtocol, "StatusCode"); code == nil || attr(code, "Value") != statusSuccess {
+		var value string
+		if code != nil {
+			value = attr(code, "Value")
+		}
+		return nil, errors.Errorf("the identity provider returned status %s", value)
+	}
+
+	if len(childElements(response, nsAssertion, "EncryptedAssertion")) > 0 {
+		return nil, errors.New("encrypted assertions are not supported")
+	}
+
+	assertions := childElements(response, nsAssertion, "Assertion")
+	if len(assertions) != 1 {
+		return nil, errors.New("the response must contain exactly one assertion")
+	}
+	assertion := assertions[0]
+
+	if verified, err := verifySignature(assertion, sp.idp.Certificates, now); errors.Is(err, errNotSigned) {
+		if !responseSigned {
+			return nil, errors.New("neither the response nor the assertion is signed")
+		}
+	} else if err != nil {
+		return nil, err
+	} else {
+		assertion = verified
+	}
+
+	result, err := sp.validateAssertion(assertion, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// The response's InResponseTo is not covered by the signature if only the assertion is signed, which is why the
+	// signed subject confirmation is authoritative.
+	if inResponseTo := attr(response, "InResponseTo"); inResponseTo != "" && inResponseTo != result.InResponseTo {
+		return nil, errors.New("the response and the assertion answer different requests")
+	}
+
+	return result, nil
+}
+
+func (sp *serviceProvider) validateAssertion(assertion *etree.Element, now time.Time) (*validatedResponse, error) {
+	result := &validatedResponse{AssertionID: attr(assertion, "ID")}
+	if result.AssertionID == "" {
+		return nil, errors.New("the assertion is missing its ID")
+	}
+
+	if issuer := childElement(assertion, nsAssertion, "Issuer"); issuer == nil || elementText(issuer) != sp.idp.EntityID {
+		return nil, errors.New("the assertion was not issued by the identity provider")
+	}
+
+	subject := childElement(assertion, nsAssertion, "Subject")
+	if subject == nil {
+		return nil, errors.New("the assertion is missing the subject")
+	}
+
+	nameID := childElement(subject, nsAssertion, "NameID")
+	if nameID == nil || elementText(nameID) == "" {
+		return nil, errors.New("the assertion is missing the subject's name identifier")
+	}
+
+	for _, confirmation := range childElements(subject, nsAssertion, "SubjectConfirmation") {
+		if attr(confirmation, "Method") != confirmationBearer {
+			continue
+		}
+
+		data := childElement(confirmation, nsAssertion, "SubjectConfirmationData")
+		if data == nil {
+			continue
+		}
+
+		if recipient := attr(data, "Recipient"); recipient != "" && recipient != sp.acs {
+			continue
+		}
+		// Unsolicited responses are not supported, so the confirmation must name the request it answers.
+		inResponseTo := attr(data, "InResponseTo")
+		if inResponseTo == "" {
+			continue
+		}
+		notOnOrAfter, err := parseTime(attr(data, "NotOnOrAfter"))
+		if err != nil || !notOnOrAfter.Add(maxClockSkew).After(now) {
+			continue
+		}
+
+		result.InResponseTo = inResponseTo
+		result.ExpiresAt = notOnOrAfter.Add(maxClockSkew)
+		break
+	}
+	if result.InResponseTo == "" {
+		return nil, errors.New("the assertion's subject could not be confirmed")
+	}
+
+	conditions := childElement(assertion, nsAssertion, "Conditions")
+	if conditions == nil {
+		return nil, errors.New("the assertion is missing the conditions")
+	}
+	if v := attr(conditions, "NotBefore"); v != "" {
+		if notBefore, err := parseTime(v); err != nil || notBefore.Add(-maxClockSkew).After(now) {
+			return nil, errors.New("the assertion is not yet valid")
+		}
+	}
+	if v := attr(conditions, "NotOnOrAfter"); v != "" {
+		notOnOrAfter, err := parseTime(v)
+		if err != nil || !notOnOrAfter.Add(maxClockSkew).After(now) {
+			return nil, errors.New("the assertion has expired")
+		}
+		if expiresAt := notOnOrAfter.Add(maxClockSkew); expiresAt.Before(result.ExpiresAt) {
+			result.ExpiresAt = expiresAt
+		}
+	}
+
+	// Every audience restriction must name this service provider, and there must be at least one. Otherwise, an
+	// assertion issued for another service provider of the identity provider would be accepted.
+	restrictions := childElements(conditions, nsAssertion, "AudienceRestriction")
+	if len(restrictions) == 0 {
+		return nil, errors.New("the assertion is not restricted to an audience")
+	}
+	for _, restriction := range restrictions {
+		var found bool
+		for _, audience := range childElements(restriction, nsAssertion, "Audience") {
+			if elementText(audience) == sp.entityID {
+				found = true
+			}
+		}
+		if !found {
+			return nil, errors.New("the assertion is not intended for this service provider")
+		}
+	}
+
+	result.Assertion = &Assertion{
+		Issuer:       sp.idp.EntityID,
+		Subject:      elementText(nameID),
+		NameIDFormat: attr(nameID, "Format"),
+		Attributes:   map[string][]string{},
+	}
+
+	if statement := childElement(assertion, nsAssertion, "AuthnStatement"); statement != nil {
+		result.Assertion.SessionIndex = attr(statement, "SessionIndex")
+	}
+
+	for _, statement := range childElements(assertion, nsAssertion, "AttributeStatement") {
+		for _, attribute := range childElements(statement, nsAssertion, "Attribute") {
+			name := attr(attribute, "Name")
+			for _, value := range childElements(attribute, nsAssertion, "AttributeValue") {
+				result.Assertion.Attributes[name] = append(result.Assertion.Attributes[name], elementText(value))
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func parseTime(in string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, in)
+	return t, errors.WithStack(err)
+}
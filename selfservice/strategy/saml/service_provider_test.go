@@ -0,0 +1,311 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/x/pointerx"
+	"github.com/ory/x/urlx"
+)
+
+func TestParseXML(t *testing.T) {
+	t.Run("case=rejects document type definitions", func(t *testing.T) {
+		_, err := parseXML([]byte(`<!DOCTYPE x [<!ENTITY e "entity">]><x>&e;</x>`))
+		require.Error(t, err)
+	})
+}
+
+func TestServiceProvider(t *testing.T) {
+	public := urlx.ParseOrPanic("https://kratos.example.com/")
+	idp := NewTestIdentityProvider(t, "https://idp.example.com/metadata", "https://idp.example.com/sso")
+	spCert, spKey := NewTestKeyPair(t)
+
+	conf := &Configuration{ID: "idp", SPCertificate: spCert, SPPrivateKey: spKey}
+	sp, err := newServiceProvider(conf, public, idp.Metadata())
+	require.NoError(t, err)
+
+	acs := "https://kratos.example.com/self-service/methods/saml/acs/idp"
+	entityID := "https://kratos.example.com/self-service/methods/saml/metadata/idp"
+
+	valid := func() TestResponse {
+		return TestResponse{
+			InResponseTo:  "id-request",
+			Destination:   acs,
+			Audience:      entityID,
+			Subject:       "subject",
+			Attributes:    map[string][]string{"email": {"foo@ory.sh"}, "groups": {"a", "b"}},
+			SignAssertion: true,
+		}
+	}
+
+	t.Run("case=parses the metadata", func(t *testing.T) {
+		assert.Equal(t, idp.EntityID, sp.idp.EntityID)
+		assert.Equal(t, idp.SSOURL, sp.idp.SSOURL)
+		require.Len(t, sp.idp.Certificates, 1)
+		assert.Equal(t, entityID, sp.entityID)
+		assert.Equal(t, acs, sp.acs)
+	})
+
+	t.Run("case=supports a configuration without metadata", func(t *testing.T) {
+		sp, err := newServiceProvider(&Configuration{
+			ID:             "idp",
+			IDPEntityID:    idp.EntityID,
+			IDPSSOURL:      idp.SSOURL,
+			IDPCertificate: idp.Certificate,
+			SPCertificate:  spCert,
+			SPPrivateKey:   spKey,
+		}, public, nil)
+		require.NoError(t, err)
+		assert.Equal(t, idp.EntityID, sp.idp.EntityID)
+	})
+
+	t.Run("case=fails on an incomplete configuration", func(t *testing.T) {
+		_, err := newServiceProvider(&Configuration{ID: "idp", IDPSSOURL: idp.SSOURL, IDPCertificate: idp.Certificate, SPCertificate: spCert, SPPrivateKey: spKey}, public, nil)
+		require.Error(t, err)
+
+		_, err = newServiceProvider(&Configuration{ID: "idp", SPCertificate: spCert, SPPrivateKey: "not a key"}, public, idp.Metadata())
+		require.Error(t, err)
+	})
+
+	t.Run("case=renders the metadata", func(t *testing.T) {
+		metadata, err := parseXML(sp.Metadata())
+		require.NoError(t, err)
+		assert.Equal(t, entityID, attr(metadata, "entityID"))
+		descriptor := childElement(metadata, nsMetadata, "SPSSODescriptor")
+		require.NotNil(t, descriptor)
+		assert.Equal(t, acs, attr(childElement(descriptor, nsMetadata, "AssertionConsumerService"), "Location"))
+	})
+
+	t.Run("case=creates a signed authentication request", func(t *testing.T) {
+		authURL, requestID, err := sp.AuthnRequestURL("relay-state", time.Now())
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(authURL, idp.SSOURL+"?"), authURL)
+
+		u, err := url.Parse(authURL)
+		require.NoError(t, err)
+		req := idp.ParseAuthnRequest(t, &http.Request{URL: u}, spCert)
+		assert.Equal(t, requestID, req.ID)
+		assert.Equal(t, entityID, req.Issuer)
+		assert.Equal(t, acs, req.ACS)
+		assert.Equal(t, "relay-state", req.RelayState)
+	})
+
+	t.Run("case=accepts valid responses", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			response func(r *TestResponse)
+		}{
+			{name: "signed assertion", response: func(r *TestResponse) {}},
+			{name: "signed response", response: func(r *TestResponse) { r.SignAssertion, r.SignResponse = false, true }},
+			{name: "signed response and assertion", response: func(r *TestResponse) { r.SignResponse = true }},
+		} {
+			t.Run("response="+tc.name, func(t *testing.T) {
+				r := valid()
+				tc.response(&r)
+
+				r.AssertionID = "id-assertion"
+
+				now := time.Now()
+				response, err := sp.ParseResponse(idp.EncodedResponse(t, r), now)
+				require.NoError(t, err)
+				assert.Equal(t, "id-request", response.InResponseTo)
+				assert.Equal(t, "id-assertion", response.AssertionID)
+				assert.WithinDuration(t, now.Add(time.Minute*5+maxClockSkew), response.ExpiresAt, time.Second)
+				assert.Equal(t, &Assertion{
+					Issuer:       idp.EntityID,
+					Subject:      "subject",
+					NameIDFormat: nameIDFormatPersistent,
+					SessionIndex: "session-index",
+					Attributes:   map[string][]string{"email": {"foo@ory.sh"}, "groups": {"a", "b"}},
+				}, response.Assertion)
+			})
+		}
+	})
+
+	t.Run("case=rejects invalid responses", func(t *testing.T) {
+		other := NewTestIdentityProvider(t, idp.EntityID, idp.SSOURL)
+
+		for _, tc := range []struct {
+			name     string
+			response func(t *testing.T) []byte
+			reason   string
+		}{
+			{
+				name: "unsigned",
+				response: func(t *testing.T) []byte {
+					r := valid()
+					r.SignAssertion = false
+					return idp.Response(t, r)
+				},
+				reason: "neither the response nor the assertion is signed",
+			},
+			{
+				name: "signed by another key",
+				response: func(t *testing.T) []byte {
+					return other.Response(t, valid())
+				},
+				reason: "could not be verified",
+			},
+			{
+				name: "tampered subject",
+				response: func(t *testing.T) []byte {
+					return bytes.Replace(idp.Response(t, valid()), []byte(">subject<"), []byte(">admin<"), 1)
+				},
+				reason: "signature could not be verified",
+			},
+			{
+				name: "tampered response with signed response",
+				response: func(t *testing.T) []byte {
+					r := valid()
+					r.SignAssertion, r.SignResponse = false, true
+					return bytes.Replace(idp.Response(t, r), []byte(">subject<"), []byte(">admin<"), 1)
+				},
+				reason: "signature could not be verified",
+			},
+			{
+				name: "signed with SHA-1",
+				response: func(t *testing.T) []byte {
+					r := valid()
+					r.SignatureMethod = dsig.RSASHA1SignatureMethod
+					return idp.Response(t, r)
+				},
+				reason: "signature method is not supported",
+			},
+			{
+				name: "wrapped assertion",
+				response: func(t *testing.T) []byte {
+					r := valid()
+					r.AssertionID = "assertion-id"
+					signed := idp.Response(t, r)
+					start := bytes.Index(signed, []byte("<saml:Assertion "))
+					end := bytes.Index(signed, []byte("</saml:Assertion>")) + len("</saml:Assertion>")
+					evil := bytes.Replace(bytes.Replace(signed[start:end], []byte(">subject<"), []byte(">admin<"), 1), []byte(`ID="assertion-id"`), []byte(`ID="evil"`), 1)
+					return append(append(append([]byte{}, signed[:end]...), evil...), signed[end:]...)
+				},
+				reason: "exactly one assertion",
+			},
+			{
+				name: "wrong audience",
+				response: func(t *testing.T) []byte {
+					r := valid()
+					r.Audience = "https://other.example.com"
+					return idp.Response(t, r)
+				},
+				reason: "not intended for this service provider",
+			},
+			{
+				name: "wrong destination",
+				response: func(t *testing.T) []byte {
+					r := valid()
+					r.Destination = "https://other.example.com/acs"
+					return idp.Response(t, r)
+				},
+				reason: "destined for",
+			},
+			{
+				name: "wrong issuer",
+				response: func(t *testing.T) []byte {
+					r := valid()
+					r.Issuer = "https://other.example.com"
+					return idp.Response(t, r)
+				},
+				reason: "issued by",
+			},
+			{
+				name: "expired",
+				response: func(t *testing.T) []byte {
+					r := valid()
+					r.NotBefore = time.Now().Add(-time.Hour)
+					r.NotOnOrAfter = time.Now().Add(-time.Minute * 30)
+					return idp.Response(t, r)
+				},
+				reason: "could not be confirmed",
+			},
+			{
+				name: "not yet valid",
+				response: func(t *testing.T) []byte {
+					r := valid()
+					r.NotBefore = time.Now().Add(time.Hour)
+					return idp.Response(t, r)
+				},
+				reason: "not yet valid",
+			},
+			{
+				name: "unsolicited",
+				response: func(t *testing.T) []byte {
+					r := valid()
+					r.InResponseTo = ""
+					return idp.Response(t, r)
+				},
+				reason: "could not be confirmed",
+			},
+			{
+				name: "subject confirmation without request",
+				response: func(t *testing.T) []byte {
+					r := valid()
+					r.ConfirmationInResponseTo = pointerx.Ptr("")
+					return idp.Response(t, r)
+				},
+				reason: "could not be confirmed",
+			},
+			{
+				name: "subject confirmation for another request",
+				response: func(t *testing.T) []byte {
+					r := valid()
+					r.ConfirmationInResponseTo = pointerx.Ptr("id-other-request")
+					return idp.Response(t, r)
+				},
+				reason: "answer different requests",
+			},
+			{
+				name: "no audience restriction",
+				response: func(t *testing.T) []byte {
+					r := valid()
+					r.OmitAudienceRestriction = true
+					return idp.Response(t, r)
+				},
+				reason: "not restricted to an audience",
+			},
+			{
+				name: "failed status",
+				response: func(t *testing.T) []byte {
+					r := valid()
+					r.Status = "urn:oasis:names:tc:SAML:2.0:status:Requester"
+					return idp.Response(t, r)
+				},
+				reason: "returned status",
+			},
+			{
+				name: "encrypted assertion",
+				response: func(t *testing.T) []byte {
+					return bytes.Replace(idp.Response(t, valid()), []byte("</samlp:Status>"), []byte("</samlp:Status><saml:EncryptedAssertion/>"), 1)
+				},
+				reason: "encrypted assertions are not supported",
+			},
+			{
+				name: "document type definition",
+				response: func(t *testing.T) []byte {
+					return append([]byte(`<!DOCTYPE x [<!ENTITY e "entity">]>`), idp.Response(t, valid())...)
+				},
+				reason: "document type definition",
+			},
+		} {
+			t.Run("response="+tc.name, func(t *testing.T) {
+				_, err := sp.parseResponse(tc.response(t), time.Now())
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.reason)
+			})
+		}
+	})
+}
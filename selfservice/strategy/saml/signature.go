@@ -0,0 +1,86 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/pkg/errors"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+// errNotSigned is returned if the element does not carry an enveloped signature.
+var errNotSigned = errors.New("the element is not signed")
+
+// digestAlgorithms and signatureAlgorithms are the algorithms which are accepted in XML signatures. SHA-1 is not
+// accepted because it is not collision resistant.
+var (
+	digestAlgorithms = map[string]bool{
+		"http://www.w3.org/2001/04/xmlenc#sha256":       true,
+		"http://www.w3.org/2001/04/xmldsig-more#sha384": true,
+		"http://www.w3.org/2001/04/xmlenc#sha512":       true,
+	}
+	signatureAlgorithms = map[string]bool{
+		dsig.RSASHA256SignatureMethod:   true,
+		dsig.RSASHA384SignatureMethod:   true,
+		dsig.RSASHA512SignatureMethod:   true,
+		dsig.ECDSASHA256SignatureMethod: true,
+		dsig.ECDSASHA384SignatureMethod: true,
+		dsig.ECDSASHA512SignatureMethod: true,
+	}
+)
+
+// verifySignature verifies the enveloped XML signature of the element against the trusted certificates and returns
+// the verified element. The signature must reference the element itself and callers must only read data from the
+// returned element, which prevents signature wrapping attacks.
+func verifySignature(e *etree.Element, trusted []*x509.Certificate, now time.Time) (*etree.Element, error) {
+	signatures := childElements(e, nsDSig, "Signature")
+	if len(signatures) == 0 {
+		return nil, errNotSigned
+	} else if len(signatures) > 1 {
+		return nil, errors.New("the element has more than one signature")
+	}
+	signature := signatures[0]
+
+	signedInfo := childElement(signature, nsDSig, "SignedInfo")
+	if signedInfo == nil {
+		return nil, errors.New("the signature is missing the SignedInfo element")
+	}
+
+	if method := childElement(signedInfo, nsDSig, "SignatureMethod"); method == nil || !signatureAlgorithms[attr(method, "Algorithm")] {
+		return nil, errors.New("the signature method is not supported")
+	}
+
+	references := childElements(signedInfo, nsDSig, "Reference")
+	if len(references) != 1 {
+		return nil, errors.New("the signature must contain exactly one reference")
+	}
+
+	if id := attr(e, "ID"); id == "" || attr(references[0], "URI") != "#"+id {
+		return nil, errors.New("the signature does not reference the signed element")
+	}
+
+	if method := childElement(references[0], nsDSig, "DigestMethod"); method == nil || !digestAlgorithms[attr(method, "Algorithm")] {
+		return nil, errors.New("the digest method is not supported")
+	}
+
+	ctx := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: trusted})
+	ctx.Clock = dsig.NewFakeClockAt(now)
+
+	verified, err := ctx.Validate(e)
+	if err != nil {
+		return nil, errors.Wrap(err, "the signature could not be verified")
+	}
+
+	return verified, nil
+}
+
+// decodeBase64 decodes base64 data which may be wrapped across several lines.
+func decodeBase64(in string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(in), ""))
+}
@@ -0,0 +1,697 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/decoderx"
+	"github.com/ory/x/fetcher"
+	"github.com/ory/x/jsonnetsecure"
+	"github.com/ory/x/jsonx"
+	"github.com/ory/x/urlx"
+
+	"github.com/ory/kratos/continuity"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/selfservice/errorx"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/selfservice/flow/registration"
+	"github.com/ory/kratos/selfservice/flow/settings"
+	"github.com/ory/kratos/selfservice/strategy"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/container"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+)
+
+const (
+	RouteBase = "/self-service/methods/saml"
+
+	RouteMetadata = RouteBase + "/metadata/:provider"
+	RouteACS      = RouteBase + "/acs/:provider"
+)
+
+var _ identity.ActiveCredentialsCounter = new(Strategy)
+
+type dependencies interface {
+	errorx.ManagementProvider
+
+	config.Provider
+
+	x.LoggingProvider
+	x.CookieProvider
+	x.CSRFProvider
+	x.CSRFTokenGeneratorProvider
+	x.WriterProvider
+	x.HTTPClientProvider
+	x.TracingProvider
+
+	identity.ValidationProvider
+	identity.PrivilegedPoolProvider
+	identity.ActiveCredentialsCounterStrategyProvider
+	identity.ManagementProvider
+
+	session.ManagementProvider
+	session.HandlerProvider
+
+	AssertionPersistenceProvider
+
+	login.HookExecutorProvider
+	login.FlowPersistenceProvider
+	login.HooksProvider
+	login.StrategyProvider
+	login.HandlerProvider
+	login.ErrorHandlerProvider
+
+	registration.HookExecutorProvider
+	registration.FlowPersistenceProvider
+	registration.HooksProvider
+	registration.StrategyProvider
+	registration.HandlerProvider
+	registration.ErrorHandlerProvider
+
+	settings.ErrorHandlerProvider
+	settings.FlowPersistenceProvider
+	settings.HookExecutorProvider
+
+	continuity.ManagementProvider
+
+	jsonnetsecure.VMProvider
+}
+
+func isForced(req interface{}) bool {
+	f, ok := req.(interface {
+		IsForced() bool
+	})
+	return ok && f.IsForced()
+}
+
+// Strategy implements selfservice.LoginStrategy, selfservice.RegistrationStrategy and selfservice.SettingsStrategy.
+// It supports login, registration and settings via SAML 2.0 identity providers.
+type Strategy struct {
+	d         dependencies
+	validator *schema.Validator
+	dec       *decoderx.HTTP
+}
+
+type authnRequestContainer struct {
+	FlowID           string          `json:"flow_id"`
+	State            string          `json:"state"`
+	RequestID        string          `json:"request_id"`
+	Traits           json.RawMessage `json:"traits"`
+	TransientPayload json.RawMessage `json:"transient_payload"`
+}
+
+// storedResponse is the validated SAML response which the assertion consumer service keeps in the flow's internal
+// context until the browser returns with its cookies.
+type storedResponse struct {
+	State        string     `json:"state"`
+	Provider     string     `json:"provider"`
+	InResponseTo string     `json:"in_response_to"`
+	Assertion    *Assertion `json:"assertion"`
+}
+
+// State is sent to the identity provider as the RelayState. It must not exceed 80 bytes.
+type State struct {
+	FlowID string
+	Data   []byte
+}
+
+func (s *State) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", s.FlowID, s.Data)))
+}
+
+func generateState(flowID string) *State {
+	return &State{
+		FlowID: flowID,
+		Data:   x.NewUUID().Bytes(),
+	}
+}
+
+func parseState(s string) (*State, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if id, data, ok := bytes.Cut(raw, []byte(":")); !ok {
+		return nil, errors.New("state has invalid format")
+	} else {
+		return &State{FlowID: string(id), Data: data}, nil
+	}
+}
+
+func (s *Strategy) CountActiveFirstFactorCredentials(cc map[identity.CredentialsType]identity.Credentials) (count int, err error) {
+	for _, c := range cc {
+		if c.Type == s.ID() && gjson.ValidBytes(c.Config) {
+			var conf identity.CredentialsSAML
+			if err = json.Unmarshal(c.Config, &conf); err != nil {
+				return 0, errors.WithStack(err)
+			}
+
+			for _, ider := range c.Identifiers {
+				for _, prov := range conf.Providers {
+					if ider == identity.SAMLUniqueID(prov.Provider, prov.Subject) && len(prov.Subject) > 0 && len(prov.Provider) > 0 {
+						count++
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+func (s *Strategy) CountActiveMultiFactorCredentials(cc map[identity.CredentialsType]identity.Credentials) (count int, err error) {
+	return 0, nil
+}
+
+func (s *Strategy) setRoutes(r *x.RouterPublic) {
+	if handle, _, _ := r.Lookup("GET", RouteMetadata); handle == nil {
+		r.GET(RouteMetadata, strategy.IsDisabled(s.d, s.ID().String(), s.handleMetadata))
+	}
+
+	if handle, _, _ := r.Lookup("GET", RouteACS); handle == nil {
+		r.GET(RouteACS, strategy.IsDisabled(s.d, s.ID().String(), s.handleCallback))
+	}
+
+	if handle, _, _ := r.Lookup("POST", RouteACS); handle == nil {
+		// The identity provider posts the SAML response from its own origin. The response is bound to the browser
+		// by the ID of the authentication request which is kept in the continuity container.
+		s.d.CSRFHandler().IgnoreGlob(RouteBase + "/acs/*")
+		r.POST(RouteACS, strategy.IsDisabled(s.d, s.ID().String(), s.handleResponse))
+	}
+}
+
+func NewStrategy(d dependencies) *Strategy {
+	return &Strategy{
+		d:         d,
+		validator: schema.NewValidator(),
+		dec:       decoderx.NewHTTP(),
+	}
+}
+
+func (s *Strategy) ID() identity.CredentialsType {
+	return identity.CredentialsTypeSAML
+}
+
+// getFlow loads the login, registration or settings flow without validating it.
+func (s *Strategy) getFlow(ctx context.Context, rid uuid.UUID) (flow.Flow, error) {
+	if rid.IsNil() {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReason("The SAML relay state contains invalid values and the flow could not be executed. Please try again."))
+	}
+
+	if f, err := s.d.RegistrationFlowPersister().GetRegistrationFlow(ctx, rid); err == nil {
+		return f, nil
+	}
+
+	if f, err := s.d.LoginFlowPersister().GetLoginFlow(ctx, rid); err == nil {
+		return f, nil
+	}
+
+	f, err := s.d.SettingsFlowPersister().GetSettingsFlow(ctx, rid)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *Strategy) validateFlow(ctx context.Context, r *http.Request, rid uuid.UUID) (flow.Flow, error) {
+	f, err := s.getFlow(ctx, rid)
+	if err != nil {
+		return nil, err
+	}
+
+	switch ff := f.(type) {
+	case *registration.Flow:
+		return ff, ff.Valid()
+	case *login.Flow:
+		return ff, ff.Valid()
+	case *settings.Flow:
+		sess, err := s.d.SessionManager().FetchFromRequest(ctx, r)
+		if err != nil {
+			return ff, err
+		}
+		return ff, ff.Valid(sess)
+	}
+
+	return f, nil
+}
+
+func (s *Strategy) alreadyAuthenticated(w http.ResponseWriter, r *http.Request, f interface{}) (bool, error) {
+	ctx := r.Context()
+
+	if sess, _ := s.d.SessionManager().FetchFromRequest(ctx, r); sess != nil {
+		if _, ok := f.(*settings.Flow); ok {
+			// ignore this if it's a settings flow
+		} else if !isForced(f) {
+			returnTo := s.d.Config().SelfServiceBrowserDefaultReturnTo(ctx)
+			if redirecter, ok := f.(flow.FlowWithRedirect); ok {
+				if u, err := x.SecureRedirectTo(r, returnTo, redirecter.SecureRedirectToOpts(ctx, s.d)...); err == nil {
+					returnTo = u
+				}
+			}
+			http.Redirect(w, r, returnTo.String(), http.StatusSeeOther)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// startAuthentication pauses the flow and redirects the browser to the identity provider.
+func (s *Strategy) startAuthentication(w http.ResponseWriter, r *http.Request, f flow.Flow, pid string, traits, transientPayload json.RawMessage) error {
+	sp, err := s.serviceProvider(r.Context(), pid)
+	if err != nil {
+		return err
+	}
+
+	state := generateState(f.GetID().String())
+	authURL, requestID, err := sp.AuthnRequestURL(state.String(), time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := s.d.ContinuityManager().Pause(r.Context(), w, r, sessionName,
+		continuity.WithPayload(&authnRequestContainer{
+			State:            state.String(),
+			FlowID:           f.GetID().String(),
+			RequestID:        requestID,
+			Traits:           traits,
+			TransientPayload: transientPayload,
+		}),
+		continuity.WithLifespan(time.Minute*30)); err != nil {
+		return err
+	}
+
+	if x.IsJSONRequest(r) {
+		s.d.Writer().WriteError(w, r, flow.NewBrowserLocationChangeRequiredError(authURL))
+	} else {
+		http.Redirect(w, r, authURL, http.StatusSeeOther)
+	}
+
+	return nil
+}
+
+func (s *Strategy) handleMetadata(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	sp, err := s.serviceProvider(r.Context(), ps.ByName("provider"))
+	if err != nil {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/samlmetadata+xml")
+	_, _ = w.Write(sp.Metadata())
+}
+
+// handleResponse is the assertion consumer service using the HTTP-POST binding. Because the identity provider posts
+// the response cross-site, the browser does not send the cookies required to continue the flow. The validated response
+// is therefore stored in the flow and the browser is redirected to handleCallback.
+func (s *Strategy) handleResponse(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var (
+		ctx = r.Context()
+		pid = ps.ByName("provider")
+	)
+
+	if err := r.ParseForm(); err != nil {
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, errors.WithStack(herodot.ErrBadRequest.WithReason("Unable to parse the SAML response.").WithDebug(err.Error())))
+		return
+	}
+
+	relayState := r.PostForm.Get("RelayState")
+	state, err := parseState(relayState)
+	if err != nil {
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, errors.WithStack(herodot.ErrBadRequest.WithReason("Unable to complete SAML flow because the relay state was invalid.")))
+		return
+	}
+
+	f, err := s.getFlow(ctx, x.ParseUUID(state.FlowID))
+	if err != nil {
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, err)
+		return
+	}
+
+	sp, err := s.serviceProvider(ctx, pid)
+	if err != nil {
+		s.forwardError(w, r, f, s.handleError(w, r, f, pid, nil, err))
+		return
+	}
+
+	response, err := sp.ParseResponse(r.PostForm.Get("SAMLResponse"), time.Now())
+	if err != nil {
+		s.forwardError(w, r, f, s.handleError(w, r, f, pid, nil, err))
+		return
+	}
+
+	if err := s.d.SAMLAssertionPersister().UseSAMLAssertion(ctx, response.Assertion.Issuer, response.AssertionID, response.ExpiresAt); err != nil {
+		s.forwardError(w, r, f, s.handleError(w, r, f, pid, nil, err))
+		return
+	}
+
+	if err := s.storeResponse(ctx, f, &storedResponse{
+		State:        relayState,
+		Provider:     pid,
+		InResponseTo: response.InResponseTo,
+		Assertion:    response.Assertion,
+	}); err != nil {
+		s.forwardError(w, r, f, s.handleError(w, r, f, pid, nil, err))
+		return
+	}
+
+	dest := urlx.CopyWithQuery(
+		urlx.AppendPaths(s.d.Config().SelfPublicURL(ctx), strings.Replace(RouteACS, ":provider", pid, 1)),
+		url.Values{"state": {relayState}},
+	)
+	http.Redirect(w, r, dest.String(), http.StatusSeeOther)
+}
+
+func (s *Strategy) storeResponse(ctx context.Context, f flow.Flow, response *storedResponse) error {
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	key := flow.PrefixInternalContextKey(s.ID(), internalContextKeyResponse)
+	switch ff := f.(type) {
+	case *login.Flow:
+		ff.EnsureInternalContext()
+		if ff.InternalContext, err = sjson.SetRawBytes(ff.InternalContext, key, raw); err != nil {
+			return errors.WithStack(err)
+		}
+		return s.d.LoginFlowPersister().UpdateLoginFlow(ctx, ff)
+	case *registration.Flow:
+		ff.EnsureInternalContext()
+		if ff.InternalContext, err = sjson.SetRawBytes(ff.InternalContext, key, raw); err != nil {
+			return errors.WithStack(err)
+		}
+		return s.d.RegistrationFlowPersister().UpdateRegistrationFlow(ctx, ff)
+	case *settings.Flow:
+		ff.EnsureInternalContext()
+		if ff.InternalContext, err = sjson.SetRawBytes(ff.InternalContext, key, raw); err != nil {
+			return errors.WithStack(err)
+		}
+		return s.d.SettingsFlowPersister().UpdateSettingsFlow(ctx, ff)
+	}
+
+	return errors.WithStack(x.PseudoPanic.WithDebugf("Unexpected type in SAML flow: %T", f))
+}
+
+// popResponse returns the stored SAML response and removes it from the flow, so that it can only be used once.
+func (s *Strategy) popResponse(ctx context.Context, f flow.Flow) (*storedResponse, error) {
+	key := flow.PrefixInternalContextKey(s.ID(), internalContextKeyResponse)
+
+	var internalContext []byte
+	switch ff := f.(type) {
+	case *login.Flow:
+		internalContext = ff.InternalContext
+	case *registration.Flow:
+		internalContext = ff.InternalContext
+	case *settings.Flow:
+		internalContext = ff.InternalContext
+	}
+
+	raw := gjson.GetBytes(internalContext, key)
+	if !raw.IsObject() {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReason("Unable to complete SAML flow because no SAML response was received for this flow. Please try again."))
+	}
+
+	var response storedResponse
+	if err := json.Unmarshal([]byte(raw.Raw), &response); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	internalContext, err := sjson.DeleteBytes(internalContext, key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	switch ff := f.(type) {
+	case *login.Flow:
+		ff.InternalContext = internalContext
+		err = s.d.LoginFlowPersister().UpdateLoginFlow(ctx, ff)
+	case *registration.Flow:
+		ff.InternalContext = internalContext
+		err = s.d.RegistrationFlowPersister().UpdateRegistrationFlow(ctx, ff)
+	case *settings.Flow:
+		ff.InternalContext = internalContext
+		err = s.d.SettingsFlowPersister().UpdateSettingsFlow(ctx, ff)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (s *Strategy) validateCallback(w http.ResponseWriter, r *http.Request, pid string) (flow.Flow, *authnRequestContainer, *Assertion, error) {
+	stateParam := r.URL.Query().Get("state")
+	if stateParam == "" {
+		return nil, nil, nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete SAML flow because the state query parameter is missing.`))
+	}
+
+	state, err := parseState(stateParam)
+	if err != nil {
+		return nil, nil, nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete SAML flow because the state parameter was invalid.`))
+	}
+
+	f, err := s.validateFlow(r.Context(), r, x.ParseUUID(state.FlowID))
+	if err != nil {
+		return f, nil, nil, err
+	}
+
+	var cntnr authnRequestContainer
+	if _, err := s.d.ContinuityManager().Continue(r.Context(), w, r, sessionName, continuity.WithPayload(&cntnr)); err != nil {
+		return f, nil, nil, err
+	}
+
+	if stateParam != cntnr.State || cntnr.FlowID != f.GetID().String() {
+		return f, &cntnr, nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete SAML flow because the query state parameter does not match the state parameter from the session cookie.`))
+	}
+
+	response, err := s.popResponse(r.Context(), f)
+	if err != nil {
+		return f, &cntnr, nil, err
+	}
+
+	if response.State != stateParam || response.Provider != pid {
+		return f, &cntnr, nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete SAML flow because the SAML response does not belong to this flow.`))
+	}
+
+	if response.InResponseTo != cntnr.RequestID {
+		return f, &cntnr, nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete SAML flow because the SAML response does not answer the authentication request sent by this browser.`))
+	}
+
+	return f, &cntnr, response.Assertion, nil
+}
+
+// handleCallback finishes the login, registration or settings flow once the assertion consumer service has validated
+// the SAML response.
+func (s *Strategy) handleCallback(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	pid := ps.ByName("provider")
+
+	req, cntnr, assertion, err := s.validateCallback(w, r, pid)
+	if err != nil {
+		if req != nil {
+			s.forwardError(w, r, req, s.handleError(w, r, req, pid, nil, err))
+		} else {
+			s.d.SelfServiceErrorManager().Forward(r.Context(), w, r, s.handleError(w, r, nil, pid, nil, err))
+		}
+		return
+	}
+
+	if authenticated, err := s.alreadyAuthenticated(w, r, req); err != nil {
+		s.forwardError(w, r, req, s.handleError(w, r, req, pid, nil, err))
+		return
+	} else if authenticated {
+		return
+	}
+
+	provider, err := s.provider(r.Context(), pid)
+	if err != nil {
+		s.forwardError(w, r, req, s.handleError(w, r, req, pid, nil, err))
+		return
+	}
+
+	switch a := req.(type) {
+	case *login.Flow:
+		if ff, err := s.processLogin(w, r, a, assertion, provider, cntnr); err != nil {
+			if ff != nil {
+				s.forwardError(w, r, ff, err)
+				return
+			}
+			s.forwardError(w, r, a, err)
+		}
+		return
+	case *registration.Flow:
+		a.TransientPayload = cntnr.TransientPayload
+		if ff, err := s.processRegistration(w, r, a, assertion, provider, cntnr); err != nil {
+			if ff != nil {
+				s.forwardError(w, r, ff, err)
+				return
+			}
+			s.forwardError(w, r, a, err)
+		}
+		return
+	case *settings.Flow:
+		sess, err := s.d.SessionManager().FetchFromRequest(r.Context(), r)
+		if err != nil {
+			s.forwardError(w, r, a, s.handleError(w, r, a, pid, nil, err))
+			return
+		}
+		if err := s.linkProvider(w, r, &settings.UpdateContext{Session: sess, Flow: a}, assertion, provider); err != nil {
+			s.forwardError(w, r, a, s.handleError(w, r, a, pid, nil, err))
+			return
+		}
+		return
+	default:
+		s.forwardError(w, r, req, s.handleError(w, r, req, pid, nil, errors.WithStack(x.PseudoPanic.
+			WithDetailf("cause", "Unexpected type in SAML flow: %T", a))))
+		return
+	}
+}
+
+func (s *Strategy) populateMethod(r *http.Request, c *container.Container, message func(provider string) *text.Message) error {
+	conf, err := s.Config(r.Context())
+	if err != nil {
+		return err
+	}
+
+	// does not need sorting because there is only one field
+	c.SetCSRF(s.d.GenerateCSRFToken(r))
+	AddProviders(c, conf.Providers, message)
+
+	return nil
+}
+
+func (s *Strategy) Config(ctx context.Context) (*ConfigurationCollection, error) {
+	var c ConfigurationCollection
+
+	conf := s.d.Config().SelfServiceStrategy(ctx, string(s.ID())).Config
+	if err := jsonx.
+		NewStrictDecoder(bytes.NewBuffer(conf)).
+		Decode(&c); err != nil {
+		s.d.Logger().WithError(err).WithField("config", conf)
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to decode SAML Provider configuration: %s", err))
+	}
+
+	return &c, nil
+}
+
+func (s *Strategy) provider(ctx context.Context, id string) (*Configuration, error) {
+	c, err := s.Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.Provider(id)
+}
+
+func (s *Strategy) serviceProvider(ctx context.Context, id string) (*serviceProvider, error) {
+	c, err := s.provider(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata []byte
+	if c.IDPMetadataURL != "" {
+		buf, err := fetcher.NewFetcher(fetcher.WithClient(s.d.HTTPClient(ctx))).FetchContext(ctx, c.IDPMetadataURL)
+		if err != nil {
+			return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to fetch the metadata of SAML Provider %s.", c.ID).WithDebug(err.Error()))
+		}
+		metadata = buf.Bytes()
+	}
+
+	return newServiceProvider(c, s.d.Config().SelfPublicURL(ctx), metadata)
+}
+
+func (s *Strategy) forwardError(w http.ResponseWriter, r *http.Request, f flow.Flow, err error) {
+	switch ff := f.(type) {
+	case *login.Flow:
+		s.d.LoginFlowErrorHandler().WriteFlowError(w, r, ff, s.NodeGroup(), err)
+	case *registration.Flow:
+		s.d.RegistrationFlowErrorHandler().WriteFlowError(w, r, ff, s.NodeGroup(), err)
+	case *settings.Flow:
+		var i *identity.Identity
+		if sess, err := s.d.SessionManager().FetchFromRequest(r.Context(), r); err == nil {
+			i = sess.Identity
+		}
+		s.d.SettingsFlowErrorHandler().WriteFlowError(w, r, s.NodeGroup(), ff, i, err)
+	default:
+		panic(errors.Errorf("unexpected type: %T", ff))
+	}
+}
+
+func (s *Strategy) handleError(w http.ResponseWriter, r *http.Request, f flow.Flow, provider string, traits []byte, err error) error {
+	switch rf := f.(type) {
+	case *login.Flow:
+		return err
+	case *registration.Flow:
+		if errors.Is(err, registration.ErrDuplicateCredentials) {
+			rf.UI.Messages.Add(text.NewErrorValidationDuplicateCredentialsOnOIDCLink())
+			lf, err := s.registrationToLogin(w, r, rf)
+			if err != nil {
+				return err
+			}
+			// return a new login flow with the error message embedded in the login flow.
+			x.AcceptToRedirectOrJSON(w, r, s.d.Writer(), lf, lf.AppendTo(s.d.Config().SelfServiceFlowLoginUI(r.Context())).String())
+			// ensure the function does not continue to execute
+			return registration.ErrHookAbortFlow
+		}
+
+		// Reset all nodes to not confuse users.
+		rf.UI.Nodes = node.Nodes{}
+
+		// Adds the "Continue" button
+		rf.UI.SetCSRF(s.d.GenerateCSRFToken(r))
+		AddProvider(rf.UI, provider, text.NewInfoRegistrationContinue())
+
+		if traits != nil {
+			ds, err := s.d.Config().DefaultIdentityTraitsSchemaURL(r.Context())
+			if err != nil {
+				return err
+			}
+
+			traitNodes, err := container.NodesFromJSONSchema(r.Context(), node.SAMLGroup, ds.String(), "", nil)
+			if err != nil {
+				return err
+			}
+
+			rf.UI.Nodes = append(rf.UI.Nodes, traitNodes...)
+			rf.UI.UpdateNodeValuesFromJSON(traits, "traits", node.SAMLGroup)
+		}
+
+		return err
+	case *settings.Flow:
+		return err
+	}
+
+	return err
+}
+
+func (s *Strategy) NodeGroup() node.UiNodeGroup {
+	return node.SAMLGroup
+}
+
+func (s *Strategy) CompletedAuthenticationMethod(ctx context.Context) session.AuthenticationMethod {
+	return session.AuthenticationMethod{
+		Method: s.ID(),
+		AAL:    identity.AuthenticatorAssuranceLevel1,
+	}
+}
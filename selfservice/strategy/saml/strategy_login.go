@@ -0,0 +1,206 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
+	"github.com/ory/x/stringslice"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/selfservice/flow/registration"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+)
+
+var _ login.Strategy = new(Strategy)
+var _ login.LinkableStrategy = new(Strategy)
+
+func (s *Strategy) RegisterLoginRoutes(r *x.RouterPublic) {
+	s.setRoutes(r)
+}
+
+func (s *Strategy) PopulateLoginMethod(r *http.Request, requestedAAL identity.AuthenticatorAssuranceLevel, l *login.Flow) error {
+	// This strategy can only solve AAL1
+	if requestedAAL > identity.AuthenticatorAssuranceLevel1 {
+		return nil
+	}
+
+	// SAML relies on cookies which are not available for API flows.
+	if l.Type != flow.TypeBrowser {
+		return nil
+	}
+
+	return s.populateMethod(r, l.UI, text.NewInfoLoginWith)
+}
+
+// Update Login Flow with SAML Method
+//
+// swagger:model updateLoginFlowWithSamlMethod
+type UpdateLoginFlowWithSamlMethod struct {
+	// The SAML identity provider to sign in with
+	//
+	// required: true
+	Provider string `json:"saml_provider"`
+
+	// The CSRF Token
+	CSRFToken string `json:"csrf_token"`
+
+	// Method to use
+	//
+	// This field must be set to `saml` when using the saml method.
+	//
+	// required: true
+	Method string `json:"method"`
+
+	// The identity traits. This is a placeholder for the registration flow.
+	Traits json.RawMessage `json:"traits"`
+}
+
+func (s *Strategy) processLogin(w http.ResponseWriter, r *http.Request, a *login.Flow, assertion *Assertion, provider *Configuration, container *authnRequestContainer) (*registration.Flow, error) {
+	i, c, err := s.d.PrivilegedIdentityPool().FindByCredentialsIdentifier(r.Context(), identity.CredentialsTypeSAML, identity.SAMLUniqueID(provider.ID, assertion.Subject))
+	if err != nil {
+		if errors.Is(err, sqlcon.ErrNoRows) {
+			// If no account was found we're "manually" creating a new registration flow and use the assertion to
+			// register the identity, which is the same behavior as the OpenID Connect strategy.
+			s.d.Logger().WithField("provider", provider.ID).WithField("subject", assertion.Subject).Debug("Received a valid SAML assertion but user is not registered. Re-initializing registration flow now.")
+
+			// If return_to was set before, we need to preserve it.
+			var opts []registration.FlowOption
+			if len(a.ReturnTo) > 0 {
+				opts = append(opts, registration.WithFlowReturnTo(a.ReturnTo))
+			}
+
+			aa, err := s.d.RegistrationHandler().NewRegistrationFlow(w, r, a.Type, opts...)
+			if err != nil {
+				return nil, s.handleError(w, r, a, provider.ID, nil, err)
+			}
+
+			aa.RequestURL, err = x.TakeOverReturnToParameter(a.RequestURL, aa.RequestURL)
+			if err != nil {
+				return nil, s.handleError(w, r, a, provider.ID, nil, err)
+			}
+
+			if _, err := s.processRegistration(w, r, aa, assertion, provider, container); err != nil {
+				return aa, err
+			}
+
+			return nil, nil
+		}
+
+		return nil, s.handleError(w, r, a, provider.ID, nil, err)
+	}
+
+	var o identity.CredentialsSAML
+	if err := json.NewDecoder(bytes.NewBuffer(c.Config)).Decode(&o); err != nil {
+		return nil, s.handleError(w, r, a, provider.ID, nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("The SAML credentials could not be decoded properly").WithDebug(err.Error())))
+	}
+
+	sess := session.NewInactiveSession()
+	sess.CompletedLoginForWithProvider(s.ID(), identity.AuthenticatorAssuranceLevel1, provider.ID)
+	for _, c := range o.Providers {
+		if c.Subject == assertion.Subject && c.Provider == provider.ID {
+			if err = s.d.LoginHookExecutor().PostLoginHook(w, r, node.SAMLGroup, a, i, sess, provider.ID); err != nil {
+				return nil, s.handleError(w, r, a, provider.ID, nil, err)
+			}
+			return nil, nil
+		}
+	}
+
+	return nil, s.handleError(w, r, a, provider.ID, nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("Unable to find matching SAML Credentials.").WithDebugf(`Unable to find credentials that match the given provider "%s" and subject "%s".`, provider.ID, assertion.Subject)))
+}
+
+func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow, _ uuid.UUID) (i *identity.Identity, err error) {
+	if err := login.CheckAAL(f, identity.AuthenticatorAssuranceLevel1); err != nil {
+		return nil, err
+	}
+
+	var p UpdateLoginFlowWithSamlMethod
+	if err := s.newLinkDecoder(&p, r); err != nil {
+		return nil, s.handleError(w, r, f, "", nil, errors.WithStack(herodot.ErrBadRequest.WithDebug(err.Error()).WithReasonf("Unable to parse HTTP form request: %s", err.Error())))
+	}
+
+	var pid = p.Provider // this can come from both url query and post body
+	if pid == "" {
+		return nil, errors.WithStack(flow.ErrStrategyNotResponsible)
+	}
+
+	if err := flow.MethodEnabledAndAllowed(r.Context(), s.SettingsStrategyID(), s.SettingsStrategyID(), s.d); err != nil {
+		return nil, s.handleError(w, r, f, pid, nil, err)
+	}
+
+	if f.Type != flow.TypeBrowser {
+		return nil, s.handleError(w, r, f, pid, nil, errors.WithStack(herodot.ErrBadRequest.WithReason("SAML is only supported for browser flows.")))
+	}
+
+	req, err := s.validateFlow(r.Context(), r, f.ID)
+	if err != nil {
+		return nil, s.handleError(w, r, f, pid, nil, err)
+	}
+
+	if authenticated, err := s.alreadyAuthenticated(w, r, req); err != nil {
+		return nil, s.handleError(w, r, f, pid, nil, err)
+	} else if authenticated {
+		return i, nil
+	}
+
+	f.Active = s.ID()
+	if err = s.d.LoginFlowPersister().UpdateLoginFlow(r.Context(), f); err != nil {
+		return nil, s.handleError(w, r, f, pid, nil, errors.WithStack(herodot.ErrInternalServerError.WithReason("Could not update flow").WithDebug(err.Error())))
+	}
+
+	if err := s.startAuthentication(w, r, f, pid, p.Traits, nil); err != nil {
+		return nil, s.handleError(w, r, f, pid, nil, err)
+	}
+
+	return nil, errors.WithStack(flow.ErrCompletedByStrategy)
+}
+
+// Link adds the SAML providers of the given credentials configuration to the identity's SAML credentials and persists
+// the identity.
+func (s *Strategy) Link(ctx context.Context, i *identity.Identity, credentialsConfig sqlxx.JSONRawMessage) error {
+	var toLink identity.CredentialsSAML
+	if err := json.Unmarshal(credentialsConfig, &toLink); err != nil {
+		return errors.WithStack(herodot.ErrInternalServerError.WithReason("The SAML credentials could not be decoded properly").WithDebug(err.Error()).WithWrap(err))
+	}
+
+	var conf identity.CredentialsSAML
+	creds, err := i.ParseCredentials(s.ID(), &conf)
+	if errors.Is(err, herodot.ErrNotFound) {
+		creds = &identity.Credentials{Type: s.ID()}
+	} else if err != nil {
+		return err
+	}
+
+	for _, p := range toLink.Providers {
+		id := identity.SAMLUniqueID(p.Provider, p.Subject)
+		if stringslice.Has(creds.Identifiers, id) {
+			continue
+		}
+
+		creds.Identifiers = append(creds.Identifiers, id)
+		conf.Providers = append(conf.Providers, p)
+	}
+
+	creds.Config, err = json.Marshal(conf)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	i.SetCredentials(s.ID(), *creds)
+	return s.d.IdentityManager().Update(ctx, i, identity.ManagerAllowWriteProtectedTraits)
+}
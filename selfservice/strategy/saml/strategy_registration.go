@@ -0,0 +1,396 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/imdario/mergo"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/decoderx"
+	"github.com/ory/x/fetcher"
+	"github.com/ory/x/sqlcon"
+
+	"github.com/ory/kratos/continuity"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/selfservice/flow/registration"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/x"
+)
+
+var _ registration.Strategy = new(Strategy)
+
+type MetadataType string
+
+const (
+	PublicMetadata MetadataType = "identity.metadata_public"
+	AdminMetadata  MetadataType = "identity.metadata_admin"
+)
+
+func (s *Strategy) RegisterRegistrationRoutes(r *x.RouterPublic) {
+	s.setRoutes(r)
+}
+
+func (s *Strategy) PopulateRegistrationMethod(r *http.Request, f *registration.Flow) error {
+	// SAML relies on cookies which are not available for API flows.
+	if f.Type != flow.TypeBrowser {
+		return nil
+	}
+
+	return s.populateMethod(r, f.UI, text.NewInfoRegistrationWith)
+}
+
+// Update Registration Flow with SAML Method
+//
+// swagger:model updateRegistrationFlowWithSamlMethod
+type UpdateRegistrationFlowWithSamlMethod struct {
+	// The SAML identity provider to register with
+	//
+	// required: true
+	Provider string `json:"saml_provider"`
+
+	// The CSRF Token
+	CSRFToken string `json:"csrf_token"`
+
+	// The identity traits
+	Traits json.RawMessage `json:"traits"`
+
+	// Method to use
+	//
+	// This field must be set to `saml` when using the saml method.
+	//
+	// required: true
+	Method string `json:"method"`
+
+	// Transient data to pass along to any webhooks
+	//
+	// required: false
+	TransientPayload json.RawMessage `json:"transient_payload,omitempty"`
+}
+
+func (s *Strategy) newLinkDecoder(p interface{}, r *http.Request) error {
+	ds, err := s.d.Config().DefaultIdentityTraitsSchemaURL(r.Context())
+	if err != nil {
+		return err
+	}
+
+	raw, err := sjson.SetBytes(linkSchema, "properties.traits.$ref", ds.String()+"#/properties/traits")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	compiler, err := decoderx.HTTPRawJSONSchemaCompiler(raw)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := s.dec.Decode(r, &p, compiler,
+		decoderx.HTTPKeepRequestBody(true),
+		decoderx.HTTPDecoderSetValidatePayloads(false),
+		decoderx.HTTPDecoderUseQueryAndBody(),
+		decoderx.HTTPDecoderAllowedMethods("POST", "GET"),
+		decoderx.HTTPDecoderJSONFollowsFormFormat(),
+	); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+func (s *Strategy) Register(w http.ResponseWriter, r *http.Request, f *registration.Flow, i *identity.Identity) (err error) {
+	var p UpdateRegistrationFlowWithSamlMethod
+	if err := s.newLinkDecoder(&p, r); err != nil {
+		return s.handleError(w, r, f, "", nil, err)
+	}
+
+	var pid = p.Provider // this can come from both url query and post body
+	if pid == "" {
+		return errors.WithStack(flow.ErrStrategyNotResponsible)
+	}
+
+	f.TransientPayload = p.TransientPayload
+
+	if err := flow.MethodEnabledAndAllowed(r.Context(), s.SettingsStrategyID(), s.SettingsStrategyID(), s.d); err != nil {
+		return s.handleError(w, r, f, pid, nil, err)
+	}
+
+	if f.Type != flow.TypeBrowser {
+		return s.handleError(w, r, f, pid, nil, errors.WithStack(herodot.ErrBadRequest.WithReason("SAML is only supported for browser flows.")))
+	}
+
+	req, err := s.validateFlow(r.Context(), r, f.ID)
+	if err != nil {
+		return s.handleError(w, r, f, pid, nil, err)
+	}
+
+	if authenticated, err := s.alreadyAuthenticated(w, r, req); err != nil {
+		return s.handleError(w, r, f, pid, nil, err)
+	} else if authenticated {
+		return errors.WithStack(registration.ErrAlreadyLoggedIn)
+	}
+
+	if err := s.startAuthentication(w, r, f, pid, p.Traits, f.TransientPayload); err != nil {
+		return s.handleError(w, r, f, pid, nil, err)
+	}
+
+	return errors.WithStack(flow.ErrCompletedByStrategy)
+}
+
+func (s *Strategy) registrationToLogin(w http.ResponseWriter, r *http.Request, rf *registration.Flow) (*login.Flow, error) {
+	// If return_to was set before, we need to preserve it.
+	var opts []login.FlowOption
+	if len(rf.ReturnTo) > 0 {
+		opts = append(opts, login.WithFlowReturnTo(rf.ReturnTo))
+	}
+
+	if len(rf.UI.Messages) > 0 {
+		opts = append(opts, login.WithFormErrorMessage(rf.UI.Messages))
+	}
+
+	lf, _, err := s.d.LoginHandler().NewLoginFlow(w, r, rf.Type, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	lf.RequestURL, err = x.TakeOverReturnToParameter(rf.RequestURL, lf.RequestURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return lf, nil
+}
+
+func (s *Strategy) processRegistration(w http.ResponseWriter, r *http.Request, rf *registration.Flow, assertion *Assertion, provider *Configuration, container *authnRequestContainer) (*login.Flow, error) {
+	if _, _, err := s.d.PrivilegedIdentityPool().FindByCredentialsIdentifier(r.Context(), identity.CredentialsTypeSAML, identity.SAMLUniqueID(provider.ID, assertion.Subject)); err == nil {
+		// If the identity already exists, we perform the login flow instead.
+		s.d.Logger().WithRequest(r).WithField("provider", provider.ID).
+			WithField("subject", assertion.Subject).
+			Debug("Received a valid SAML assertion but user is already registered. Re-initializing login flow now.")
+
+		lf, err := s.registrationToLogin(w, r, rf)
+		if err != nil {
+			return nil, s.handleError(w, r, rf, provider.ID, nil, err)
+		}
+
+		if _, err := s.processLogin(w, r, lf, assertion, provider, container); err != nil {
+			return lf, s.handleError(w, r, rf, provider.ID, nil, err)
+		}
+
+		return nil, nil
+	}
+
+	fetch := fetcher.NewFetcher(fetcher.WithClient(s.d.HTTPClient(r.Context())))
+	jn, err := fetch.FetchContext(r.Context(), provider.Mapper)
+	if err != nil {
+		return nil, s.handleError(w, r, rf, provider.ID, nil, err)
+	}
+
+	i, err := s.createIdentity(w, r, rf, assertion, provider, container, jn)
+	if err != nil {
+		return nil, s.handleError(w, r, rf, provider.ID, nil, err)
+	}
+
+	// Validate the identity itself
+	if err := s.d.IdentityValidator().Validate(r.Context(), i); err != nil {
+		return nil, s.handleError(w, r, rf, provider.ID, i.Traits, err)
+	}
+
+	creds, err := identity.NewCredentialsSAML(provider.ID, assertion.Subject)
+	if err != nil {
+		return nil, s.handleError(w, r, rf, provider.ID, i.Traits, err)
+	}
+
+	i.SetCredentials(s.ID(), *creds)
+	if err := s.d.RegistrationExecutor().PostRegistrationHook(w, r, identity.CredentialsTypeSAML, provider.ID, rf, i); errors.Is(err, registration.ErrDuplicateCredentials) {
+		return nil, s.registrationToLoginAndLink(w, r, rf, provider.ID, i, creds)
+	} else if err != nil {
+		return nil, s.handleError(w, r, rf, provider.ID, i.Traits, err)
+	}
+
+	return nil, nil
+}
+
+// registrationToLoginAndLink is called when the identity created from the SAML assertion collides with an existing
+// identity. The registration flow is converted into a login flow and the SAML credentials are linked to the existing
+// identity once the user has signed in with any of its first-factor methods.
+func (s *Strategy) registrationToLoginAndLink(w http.ResponseWriter, r *http.Request, rf *registration.Flow, providerID string, i *identity.Identity, creds *identity.Credentials) error {
+	ctx := r.Context()
+
+	existing, err := s.findDuplicateIdentity(ctx, i)
+	if err != nil {
+		return s.handleError(w, r, rf, providerID, i.Traits, err)
+	} else if existing == nil {
+		return s.handleError(w, r, rf, providerID, i.Traits, errors.WithStack(registration.ErrDuplicateCredentials))
+	}
+
+	rf.UI.Messages.Add(text.NewInfoSelfServiceLoginLinkCredentials(providerID))
+	lf, err := s.registrationToLogin(w, r, rf)
+	if err != nil {
+		return err
+	}
+
+	if err := s.d.ContinuityManager().Pause(ctx, w, r, login.LinkCredentialsContinuityName,
+		continuity.WithIdentity(existing),
+		continuity.WithPayload(&login.LinkCredentials{
+			FlowID:            lf.ID,
+			CredentialsType:   s.ID(),
+			CredentialsConfig: creds.Config,
+		}),
+		continuity.WithLifespan(time.Until(lf.ExpiresAt))); err != nil {
+		return err
+	}
+
+	s.d.Logger().
+		WithRequest(r).
+		WithField("provider", providerID).
+		WithField("identity_id", existing.ID).
+		Debug("SAML credentials collided with an existing identity. Asking the user to sign in to link them.")
+
+	x.AcceptToRedirectOrJSON(w, r, s.d.Writer(), lf, lf.AppendTo(s.d.Config().SelfServiceFlowLoginUI(ctx)).String())
+	return registration.ErrHookAbortFlow
+}
+
+// findDuplicateIdentity returns the identity which owns one of the identifiers of the given identity's credentials,
+// or nil if there is none.
+func (s *Strategy) findDuplicateIdentity(ctx context.Context, i *identity.Identity) (*identity.Identity, error) {
+	for ct, c := range i.Credentials {
+		if ct == s.ID() {
+			continue
+		}
+
+		for _, identifier := range c.Identifiers {
+			existing, _, err := s.d.PrivilegedIdentityPool().FindByCredentialsIdentifier(ctx, ct, identifier)
+			if errors.Is(err, sqlcon.ErrNoRows) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			return existing, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *Strategy) createIdentity(w http.ResponseWriter, r *http.Request, a *registration.Flow, assertion *Assertion, provider *Configuration, container *authnRequestContainer, jn *bytes.Buffer) (*identity.Identity, error) {
+	var jsonClaims bytes.Buffer
+	if err := json.NewEncoder(&jsonClaims).Encode(assertion); err != nil {
+		return nil, s.handleError(w, r, a, provider.ID, nil, err)
+	}
+
+	vm, err := s.d.JsonnetVM(r.Context())
+	if err != nil {
+		return nil, s.handleError(w, r, a, provider.ID, nil, err)
+	}
+
+	vm.ExtCode("claims", jsonClaims.String())
+	evaluated, err := vm.EvaluateAnonymousSnippet(provider.Mapper, jn.String())
+	if err != nil {
+		return nil, s.handleError(w, r, a, provider.ID, nil, err)
+	}
+
+	i := identity.NewIdentity(s.d.Config().DefaultIdentityTraitsSchemaID(r.Context()))
+	if err := s.setTraits(r, container, provider, evaluated, i); err != nil {
+		return nil, s.handleError(w, r, a, provider.ID, i.Traits, err)
+	}
+
+	if err := s.setMetadata(evaluated, i, PublicMetadata); err != nil {
+		return nil, s.handleError(w, r, a, provider.ID, i.Traits, err)
+	}
+
+	if err := s.setMetadata(evaluated, i, AdminMetadata); err != nil {
+		return nil, s.handleError(w, r, a, provider.ID, i.Traits, err)
+	}
+
+	s.d.Logger().
+		WithRequest(r).
+		WithField("saml_provider", provider.ID).
+		WithSensitiveField("saml_claims", assertion).
+		WithSensitiveField("mapper_jsonnet_output", evaluated).
+		WithField("mapper_jsonnet_url", provider.Mapper).
+		Debug("SAML Jsonnet mapper completed.")
+	return i, nil
+}
+
+func (s *Strategy) setTraits(r *http.Request, container *authnRequestContainer, provider *Configuration, evaluated string, i *identity.Identity) error {
+	jsonTraits := gjson.Get(evaluated, "identity.traits")
+	if !jsonTraits.IsObject() {
+		return errors.WithStack(herodot.ErrInternalServerError.WithReasonf("SAML Jsonnet mapper did not return an object for key identity.traits. Please check your Jsonnet code!"))
+	}
+
+	traits, err := merge(container.Traits, json.RawMessage(jsonTraits.Raw))
+	if err != nil {
+		return err
+	}
+
+	i.Traits = traits
+	s.d.Logger().
+		WithRequest(r).
+		WithField("saml_provider", provider.ID).
+		WithSensitiveField("identity_traits", i.Traits).
+		WithSensitiveField("mapper_jsonnet_output", evaluated).
+		WithField("mapper_jsonnet_url", provider.Mapper).
+		Debug("Merged form values and SAML Jsonnet output.")
+	return nil
+}
+
+func (s *Strategy) setMetadata(evaluated string, i *identity.Identity, m MetadataType) error {
+	if m != PublicMetadata && m != AdminMetadata {
+		return errors.Errorf("undefined metadata type: %s", m)
+	}
+
+	metadata := gjson.Get(evaluated, string(m))
+	if metadata.Exists() && !metadata.IsObject() {
+		return errors.WithStack(herodot.ErrInternalServerError.WithReasonf("SAML Jsonnet mapper did not return an object for key %s. Please check your Jsonnet code!", m))
+	}
+
+	switch m {
+	case PublicMetadata:
+		i.MetadataPublic = []byte(metadata.Raw)
+	case AdminMetadata:
+		i.MetadataAdmin = []byte(metadata.Raw)
+	}
+
+	return nil
+}
+
+// merge merges the traits submitted with the initial form (encoded in the continuity container) with the traits
+// returned by the Jsonnet mapper. Submitted traits take precedence.
+func merge(containerTraits json.RawMessage, mapperTraits json.RawMessage) (identity.Traits, error) {
+	if len(containerTraits) == 0 || string(containerTraits) == "{}" || string(containerTraits) == "null" {
+		return identity.Traits(mapperTraits), nil
+	}
+
+	var pt map[string]interface{}
+	if err := json.NewDecoder(bytes.NewBuffer(mapperTraits)).Decode(&pt); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var ct map[string]interface{}
+	if err := json.NewDecoder(bytes.NewBuffer(containerTraits)).Decode(&ct); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if err := mergo.Merge(&pt, &ct, mergo.WithOverride); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var result bytes.Buffer
+	if err := json.NewEncoder(&result).Encode(pt); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return result.Bytes(), nil
+}
@@ -0,0 +1,435 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/tidwall/sjson"
+
+	"github.com/ory/herodot"
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/x/decoderx"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/settings"
+	"github.com/ory/kratos/selfservice/strategy"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x"
+)
+
+var _ settings.Strategy = new(Strategy)
+var UnknownConnectionValidationError = &jsonschema.ValidationError{
+	Message: "can not unlink non-existing SAML connection", InstancePtr: "#/"}
+var ConnectionExistValidationError = &jsonschema.ValidationError{
+	Message: "can not link unknown or already existing SAML connection", InstancePtr: "#/"}
+var UnlinkAllFirstFactorConnectionsError = &jsonschema.ValidationError{
+	Message: "can not unlink SAML connection because it is the last remaining first factor credential", InstancePtr: "#/"}
+
+func (s *Strategy) RegisterSettingsRoutes(router *x.RouterPublic) {}
+
+func (s *Strategy) SettingsStrategyID() string {
+	return s.ID().String()
+}
+
+func (s *Strategy) decoderSettings(p *updateSettingsFlowWithSamlMethod, r *http.Request) error {
+	ds, err := s.d.Config().DefaultIdentityTraitsSchemaURL(r.Context())
+	if err != nil {
+		return err
+	}
+	raw, err := sjson.SetBytes(settingsSchema,
+		"properties.traits.$ref", ds.String()+"#/properties/traits")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	compiler, err := decoderx.HTTPRawJSONSchemaCompiler(raw)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := s.dec.Decode(r, &p, compiler,
+		decoderx.HTTPKeepRequestBody(true),
+		decoderx.HTTPDecoderUseQueryAndBody(),
+		decoderx.HTTPDecoderSetValidatePayloads(false),
+		decoderx.HTTPDecoderAllowedMethods("POST", "GET"),
+		decoderx.HTTPDecoderJSONFollowsFormFormat()); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// linkedProviders returns the configured providers the identity has SAML credentials for.
+func (s *Strategy) linkedProviders(conf *ConfigurationCollection, confidential *identity.Identity) ([]Configuration, error) {
+	creds, ok := confidential.GetCredentials(s.ID())
+	if !ok {
+		return nil, nil
+	}
+
+	var available identity.CredentialsSAML
+	if err := json.Unmarshal(creds.Config, &available); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var result []Configuration
+	for _, p := range available.Providers {
+		prov, err := conf.Provider(p.Provider)
+		if errors.Is(err, herodot.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		result = append(result, *prov)
+	}
+
+	return result, nil
+}
+
+// linkableProviders returns the configured providers the identity has no SAML credentials for.
+func (s *Strategy) linkableProviders(conf *ConfigurationCollection, confidential *identity.Identity) ([]Configuration, error) {
+	var available identity.CredentialsSAML
+	creds, ok := confidential.GetCredentials(s.ID())
+	if ok {
+		if err := json.Unmarshal(creds.Config, &available); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	var result []Configuration
+	for _, p := range conf.Providers {
+		var found bool
+		for _, pp := range available.Providers {
+			if pp.Provider == p.ID {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			result = append(result, p)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Strategy) PopulateSettingsMethod(r *http.Request, id *identity.Identity, sr *settings.Flow) error {
+	if sr.Type != flow.TypeBrowser {
+		return nil
+	}
+
+	conf, err := s.Config(r.Context())
+	if err != nil {
+		return err
+	}
+
+	confidential, err := s.d.PrivilegedIdentityPool().GetIdentityConfidential(r.Context(), id.ID)
+	if err != nil {
+		return err
+	}
+
+	linkable, err := s.linkableProviders(conf, confidential)
+	if err != nil {
+		return err
+	}
+
+	linked, err := s.linkedProviders(conf, confidential)
+	if err != nil {
+		return err
+	}
+
+	sr.UI.GetNodes().Remove("saml_unlink", "saml_link")
+	sr.UI.SetCSRF(s.d.GenerateCSRFToken(r))
+	for _, l := range linkable {
+		sr.UI.GetNodes().Append(NewLinkNode(l.ID))
+	}
+
+	count, err := s.d.IdentityManager().CountActiveFirstFactorCredentials(r.Context(), confidential)
+	if err != nil {
+		return err
+	}
+
+	if count > 1 {
+		// This means that we're able to remove a connection because it is not the last configured credential. If
+		// the last one was removed, the identity would no longer be able to sign in.
+		for _, l := range linked {
+			sr.UI.GetNodes().Append(NewUnlinkNode(l.ID))
+		}
+	}
+
+	return nil
+}
+
+// Update Settings Flow with SAML Method
+//
+// swagger:model updateSettingsFlowWithSamlMethod
+type updateSettingsFlowWithSamlMethod struct {
+	// Method
+	//
+	// Should be set to saml when trying to link or unlink a SAML identity provider.
+	//
+	// required: true
+	Method string `json:"method"`
+
+	// Link this SAML identity provider
+	//
+	// Either this or `saml_unlink` must be set.
+	//
+	// type: string
+	// in: body
+	Link string `json:"saml_link"`
+
+	// Unlink this SAML identity provider
+	//
+	// Either this or `saml_link` must be set.
+	//
+	// type: string
+	// in: body
+	Unlink string `json:"saml_unlink"`
+
+	// Flow ID is the flow's ID.
+	//
+	// in: query
+	FlowID string `json:"flow"`
+
+	// The identity's traits
+	//
+	// in: body
+	Traits json.RawMessage `json:"traits"`
+}
+
+func (p *updateSettingsFlowWithSamlMethod) GetFlowID() uuid.UUID {
+	return x.ParseUUID(p.FlowID)
+}
+
+func (p *updateSettingsFlowWithSamlMethod) SetFlowID(rid uuid.UUID) {
+	p.FlowID = rid.String()
+}
+
+func (s *Strategy) Settings(w http.ResponseWriter, r *http.Request, f *settings.Flow, ss *session.Session) (*settings.UpdateContext, error) {
+	var p updateSettingsFlowWithSamlMethod
+	if err := s.decoderSettings(&p, r); err != nil {
+		return nil, err
+	}
+
+	ctxUpdate, err := settings.PrepareUpdate(s.d, w, r, f, ss, settings.ContinuityKey(s.SettingsStrategyID()), &p)
+	if errors.Is(err, settings.ErrContinuePreviousAction) {
+		if !s.d.Config().SelfServiceStrategy(r.Context(), s.SettingsStrategyID()).Enabled {
+			return nil, errors.WithStack(herodot.ErrNotFound.WithReason(strategy.EndpointDisabledMessage))
+		}
+
+		if l := len(p.Link); l > 0 {
+			if err := s.initLinkProvider(w, r, ctxUpdate, &p); err != nil {
+				return nil, err
+			}
+
+			return ctxUpdate, nil
+		} else if u := len(p.Unlink); u > 0 {
+			if err := s.unlinkProvider(w, r, ctxUpdate, &p); err != nil {
+				return nil, err
+			}
+
+			return ctxUpdate, nil
+		}
+
+		return nil, s.handleSettingsError(w, r, ctxUpdate, &p, errors.WithStack(herodot.ErrInternalServerError.WithReason("Expected either saml_link or saml_unlink to be set when continuing flow but both are unset.")))
+	} else if err != nil {
+		return nil, s.handleSettingsError(w, r, ctxUpdate, &p, err)
+	}
+
+	if len(p.Link+p.Unlink) == 0 {
+		return nil, errors.WithStack(flow.ErrStrategyNotResponsible)
+	}
+
+	if !s.d.Config().SelfServiceStrategy(r.Context(), s.SettingsStrategyID()).Enabled {
+		return nil, errors.WithStack(herodot.ErrNotFound.WithReason(strategy.EndpointDisabledMessage))
+	}
+
+	if l, u := len(p.Link), len(p.Unlink); l > 0 && u > 0 {
+		return nil, s.handleSettingsError(w, r, ctxUpdate, &p, errors.WithStack(&jsonschema.ValidationError{
+			Message:     "it is not possible to link and unlink providers in the same request",
+			InstancePtr: "#/",
+		}))
+	} else if l > 0 {
+		if err := s.initLinkProvider(w, r, ctxUpdate, &p); err != nil {
+			return nil, err
+		}
+		return ctxUpdate, nil
+	}
+
+	if err := s.unlinkProvider(w, r, ctxUpdate, &p); err != nil {
+		return nil, err
+	}
+
+	return ctxUpdate, nil
+}
+
+func (s *Strategy) isLinkable(r *http.Request, ctxUpdate *settings.UpdateContext, toLink string) (*identity.Identity, error) {
+	providers, err := s.Config(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := s.d.PrivilegedIdentityPool().GetIdentityConfidential(r.Context(), ctxUpdate.Session.Identity.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	linkable, err := s.linkableProviders(providers, i)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, available := range linkable {
+		if toLink == available.ID {
+			return i, nil
+		}
+	}
+
+	return nil, errors.WithStack(ConnectionExistValidationError)
+}
+
+func (s *Strategy) initLinkProvider(w http.ResponseWriter, r *http.Request, ctxUpdate *settings.UpdateContext, p *updateSettingsFlowWithSamlMethod) error {
+	if _, err := s.isLinkable(r, ctxUpdate, p.Link); err != nil {
+		return s.handleSettingsError(w, r, ctxUpdate, p, err)
+	}
+
+	if ctxUpdate.Session.AuthenticatedAt.Add(s.d.Config().SelfServiceFlowSettingsPrivilegedSessionMaxAge(r.Context())).Before(time.Now()) {
+		return s.handleSettingsError(w, r, ctxUpdate, p, errors.WithStack(settings.NewFlowNeedsReAuth()))
+	}
+
+	if _, err := s.validateFlow(r.Context(), r, ctxUpdate.Flow.ID); err != nil {
+		return s.handleSettingsError(w, r, ctxUpdate, p, err)
+	}
+
+	if err := s.startAuthentication(w, r, ctxUpdate.Flow, p.Link, p.Traits, nil); err != nil {
+		return s.handleSettingsError(w, r, ctxUpdate, p, err)
+	}
+
+	return errors.WithStack(flow.ErrCompletedByStrategy)
+}
+
+func (s *Strategy) linkProvider(w http.ResponseWriter, r *http.Request, ctxUpdate *settings.UpdateContext, assertion *Assertion, provider *Configuration) error {
+	p := &updateSettingsFlowWithSamlMethod{
+		Link: provider.ID, FlowID: ctxUpdate.Flow.ID.String()}
+	if ctxUpdate.Session.AuthenticatedAt.Add(s.d.Config().SelfServiceFlowSettingsPrivilegedSessionMaxAge(r.Context())).Before(time.Now()) {
+		return s.handleSettingsError(w, r, ctxUpdate, p, errors.WithStack(settings.NewFlowNeedsReAuth()))
+	}
+
+	i, err := s.isLinkable(r, ctxUpdate, p.Link)
+	if err != nil {
+		return s.handleSettingsError(w, r, ctxUpdate, p, err)
+	}
+
+	var conf identity.CredentialsSAML
+	creds, err := i.ParseCredentials(s.ID(), &conf)
+	if errors.Is(err, herodot.ErrNotFound) {
+		var err error
+		if creds, err = identity.NewCredentialsSAML(provider.ID, assertion.Subject); err != nil {
+			return s.handleSettingsError(w, r, ctxUpdate, p, err)
+		}
+	} else if err != nil {
+		return s.handleSettingsError(w, r, ctxUpdate, p, err)
+	} else {
+		creds.Identifiers = append(creds.Identifiers, identity.SAMLUniqueID(provider.ID, assertion.Subject))
+		conf.Providers = append(conf.Providers, identity.CredentialsSAMLProvider{
+			Subject: assertion.Subject, Provider: provider.ID,
+		})
+
+		creds.Config, err = json.Marshal(conf)
+		if err != nil {
+			return s.handleSettingsError(w, r, ctxUpdate, p, err)
+		}
+	}
+
+	i.Credentials[s.ID()] = *creds
+	if err := s.d.SettingsHookExecutor().PostSettingsHook(w, r, s.SettingsStrategyID(), ctxUpdate, i, settings.WithCallback(func(ctxUpdate *settings.UpdateContext) error {
+		return s.PopulateSettingsMethod(r, ctxUpdate.Session.Identity, ctxUpdate.Flow)
+	})); err != nil {
+		return s.handleSettingsError(w, r, ctxUpdate, p, err)
+	}
+
+	return nil
+}
+
+func (s *Strategy) unlinkProvider(w http.ResponseWriter, r *http.Request, ctxUpdate *settings.UpdateContext, p *updateSettingsFlowWithSamlMethod) error {
+	if ctxUpdate.Session.AuthenticatedAt.Add(s.d.Config().SelfServiceFlowSettingsPrivilegedSessionMaxAge(r.Context())).Before(time.Now()) {
+		return s.handleSettingsError(w, r, ctxUpdate, p, errors.WithStack(settings.NewFlowNeedsReAuth()))
+	}
+
+	i, err := s.d.PrivilegedIdentityPool().GetIdentityConfidential(r.Context(), ctxUpdate.Session.Identity.ID)
+	if err != nil {
+		return s.handleSettingsError(w, r, ctxUpdate, p, err)
+	}
+
+	var cc identity.CredentialsSAML
+	creds, err := i.ParseCredentials(s.ID(), &cc)
+	if errors.Is(err, herodot.ErrNotFound) {
+		return s.handleSettingsError(w, r, ctxUpdate, p, errors.WithStack(UnknownConnectionValidationError))
+	} else if err != nil {
+		return s.handleSettingsError(w, r, ctxUpdate, p, err)
+	}
+
+	count, err := s.d.IdentityManager().CountActiveFirstFactorCredentials(r.Context(), i)
+	if err != nil {
+		return s.handleSettingsError(w, r, ctxUpdate, p, err)
+	}
+
+	if count < 2 {
+		return s.handleSettingsError(w, r, ctxUpdate, p, errors.WithStack(UnlinkAllFirstFactorConnectionsError))
+	}
+
+	var found bool
+	var updatedProviders []identity.CredentialsSAMLProvider
+	var updatedIdentifiers []string
+	for _, link := range cc.Providers {
+		if link.Provider != p.Unlink {
+			updatedIdentifiers = append(updatedIdentifiers, identity.SAMLUniqueID(link.Provider, link.Subject))
+			updatedProviders = append(updatedProviders, link)
+		} else {
+			found = true
+		}
+	}
+
+	if !found {
+		return s.handleSettingsError(w, r, ctxUpdate, p, errors.WithStack(UnknownConnectionValidationError))
+	}
+
+	creds.Identifiers = updatedIdentifiers
+	creds.Config, err = json.Marshal(&identity.CredentialsSAML{Providers: updatedProviders})
+	if err != nil {
+		return s.handleSettingsError(w, r, ctxUpdate, p, errors.WithStack(err))
+	}
+
+	i.Credentials[s.ID()] = *creds
+	if err := s.d.SettingsHookExecutor().PostSettingsHook(w, r, s.SettingsStrategyID(), ctxUpdate, i, settings.WithCallback(func(ctxUpdate *settings.UpdateContext) error {
+		return s.PopulateSettingsMethod(r, ctxUpdate.Session.Identity, ctxUpdate.Flow)
+	})); err != nil {
+		return s.handleSettingsError(w, r, ctxUpdate, p, err)
+	}
+
+	return errors.WithStack(flow.ErrCompletedByStrategy)
+}
+
+func (s *Strategy) handleSettingsError(w http.ResponseWriter, r *http.Request, ctxUpdate *settings.UpdateContext, p *updateSettingsFlowWithSamlMethod, err error) error {
+	if e := new(settings.FlowNeedsReAuth); errors.As(err, &e) {
+		if err := s.d.ContinuityManager().Pause(r.Context(), w, r,
+			settings.ContinuityKey(s.SettingsStrategyID()), settings.ContinuityOptions(p, ctxUpdate.Session.Identity)...); err != nil {
+			return err
+		}
+	}
+
+	if ctxUpdate.Flow != nil {
+		ctxUpdate.Flow.UI.ResetMessages()
+		ctxUpdate.Flow.UI.SetCSRF(s.d.GenerateCSRFToken(r))
+	}
+
+	return err
+}
@@ -0,0 +1,403 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
+	"github.com/ory/x/urlx"
+
+	"github.com/ory/kratos/corpx"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/selfservice/strategy/saml"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x"
+)
+
+func init() {
+	corpx.RegisterFakes()
+}
+
+func TestStrategy(t *testing.T) {
+	ctx := context.Background()
+	if testing.Short() {
+		t.Skip()
+	}
+
+	var (
+		conf, reg = internal.NewFastRegistryWithMocks(t)
+		idp       *saml.TestIdentityProvider
+		subject   string
+		email     string
+		modify    func(r *saml.TestResponse)
+	)
+
+	spCert, spKey := saml.NewTestKeyPair(t)
+	idpTS := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := idp.ParseAuthnRequest(t, r, spCert)
+		response := saml.TestResponse{
+			InResponseTo:  req.ID,
+			Destination:   req.ACS,
+			Audience:      req.Issuer,
+			Subject:       subject,
+			Attributes:    map[string][]string{"email": {email}, "name": {"Jane Doe"}},
+			SignAssertion: true,
+		}
+		if modify != nil {
+			modify(&response)
+		}
+
+		// Instead of rendering an auto-submitting form we return the form values to the test.
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]string{
+			"acs":          req.ACS,
+			"SAMLResponse": idp.EncodedResponse(t, response),
+			"RelayState":   req.RelayState,
+		}))
+	}))
+	t.Cleanup(idpTS.Close)
+	idp = saml.NewTestIdentityProvider(t, idpTS.URL+"/metadata", idpTS.URL+"/sso")
+
+	returnTS := testhelpers.NewRedirSessionEchoTS(t, reg)
+	loginTS := testhelpers.NewLoginUIFlowEchoServer(t, reg)
+	testhelpers.NewRegistrationUIFlowEchoServer(t, reg)
+	settingsTS := testhelpers.NewSettingsUIFlowEchoServer(t, reg)
+	errTS := testhelpers.NewErrorTestServer(t, reg)
+	publicTS, _ := testhelpers.NewKratosServerWithRouters(t, reg, x.NewRouterPublic(), x.NewRouterAdmin())
+
+	conf.MustSet(ctx, config.ViperKeySelfServiceStrategyConfig+"."+string(identity.CredentialsTypeSAML)+".enabled", true)
+	conf.MustSet(ctx, config.ViperKeySelfServiceStrategyConfig+"."+string(identity.CredentialsTypeSAML)+".config", &saml.ConfigurationCollection{
+		Providers: []saml.Configuration{{
+			ID:             "idp",
+			IDPMetadataURL: "base64://" + base64.StdEncoding.EncodeToString(idp.Metadata()),
+			SPCertificate:  spCert,
+			SPPrivateKey:   spKey,
+			Mapper:         "file://./stub/saml.jsonnet",
+		}},
+	})
+	conf.MustSet(ctx, config.ViperKeySelfServiceRegistrationEnabled, true)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/registration.schema.json")
+	conf.MustSet(ctx, config.HookStrategyKey(config.ViperKeySelfServiceRegistrationAfter,
+		identity.CredentialsTypeSAML.String()), []config.SelfServiceHook{{Name: "session"}})
+
+	// authenticate submits the flow form and returns the response once the browser returned from the identity
+	// provider.
+	var authenticate = func(t *testing.T, client *http.Client, action string, values url.Values) (*http.Response, []byte) {
+		values.Set("csrf_token", x.FakeCSRFToken)
+		res, err := client.PostForm(action, values)
+		require.NoError(t, err)
+		body := x.MustReadAll(res.Body)
+		require.NoError(t, res.Body.Close())
+		require.True(t, strings.HasPrefix(res.Request.URL.String(), idpTS.URL+"/sso"), "%s: %s", res.Request.URL, body)
+
+		res, err = client.PostForm(gjson.GetBytes(body, "acs").String(), url.Values{
+			"SAMLResponse": {gjson.GetBytes(body, "SAMLResponse").String()},
+			"RelayState":   {gjson.GetBytes(body, "RelayState").String()},
+		})
+		require.NoError(t, err)
+		body, err = io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		return res, body
+	}
+
+	var register = func(t *testing.T, client *http.Client) (*http.Response, []byte) {
+		f := testhelpers.InitializeRegistrationFlowViaBrowser(t, client, publicTS, false, false, false)
+		return authenticate(t, client, f.Ui.Action, url.Values{"method": {"saml"}, "saml_provider": {"idp"}})
+	}
+
+	var login = func(t *testing.T, client *http.Client) (*http.Response, []byte) {
+		f := testhelpers.InitializeLoginFlowViaBrowser(t, client, publicTS, false, false, false, false)
+		return authenticate(t, client, f.Ui.Action, url.Values{"method": {"saml"}, "saml_provider": {"idp"}})
+	}
+
+	var reset = func(t *testing.T) {
+		subject = x.NewUUID().String()
+		email = subject + "@ory.sh"
+		modify = nil
+	}
+
+	var assertIdentity = func(t *testing.T, res *http.Response, body []byte) {
+		require.Contains(t, res.Request.URL.String(), returnTS.URL, "%s", body)
+		assert.Equal(t, email, gjson.GetBytes(body, "identity.traits.email").String(), "%s", body)
+		assert.Equal(t, "Jane Doe", gjson.GetBytes(body, "identity.traits.name").String(), "%s", body)
+		assert.Equal(t, "saml", gjson.GetBytes(body, "authentication_methods.0.method").String(), "%s", body)
+		assert.Equal(t, "idp", gjson.GetBytes(body, "authentication_methods.0.provider").String(), "%s", body)
+	}
+
+	t.Run("case=serves the service provider metadata", func(t *testing.T) {
+		res, err := http.Get(publicTS.URL + "/self-service/methods/saml/metadata/idp")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body := x.MustReadAll(res.Body)
+
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, "application/samlmetadata+xml", res.Header.Get("Content-Type"))
+		assert.Contains(t, string(body), `entityID="`+publicTS.URL+`/self-service/methods/saml/metadata/idp"`)
+		assert.Contains(t, string(body), `Location="`+publicTS.URL+`/self-service/methods/saml/acs/idp"`)
+	})
+
+	t.Run("case=shows the provider in the login and registration flows", func(t *testing.T) {
+		client := testhelpers.NewClientWithCookieJar(t, nil, false)
+		for _, nodes := range []interface{}{
+			testhelpers.InitializeLoginFlowViaBrowser(t, client, publicTS, false, false, false, false).Ui.Nodes,
+			testhelpers.InitializeRegistrationFlowViaBrowser(t, client, publicTS, false, false, false).Ui.Nodes,
+		} {
+			raw, err := json.Marshal(nodes)
+			require.NoError(t, err)
+			assert.Equal(t, "idp", gjson.GetBytes(raw, `#(attributes.name=="saml_provider").attributes.value`).String(), "%s", raw)
+			assert.Equal(t, "saml", gjson.GetBytes(raw, `#(attributes.name=="saml_provider").group`).String(), "%s", raw)
+		}
+	})
+
+	t.Run("case=fails because the provider does not exist", func(t *testing.T) {
+		client := testhelpers.NewClientWithCookieJar(t, nil, false)
+		f := testhelpers.InitializeLoginFlowViaBrowser(t, client, publicTS, false, false, false, false)
+		res, err := client.PostForm(f.Ui.Action, url.Values{"csrf_token": {x.FakeCSRFToken}, "method": {"saml"}, "saml_provider": {"unknown"}})
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body := x.MustReadAll(res.Body)
+
+		require.Contains(t, res.Request.URL.String(), errTS.URL, "%s", body)
+		assert.Contains(t, gjson.GetBytes(body, "reason").String(), "is unknown or has not been configured", "%s", body)
+	})
+
+	t.Run("case=registers and signs in an identity", func(t *testing.T) {
+		reset(t)
+
+		res, body := register(t, testhelpers.NewClientWithCookieJar(t, nil, false))
+		assertIdentity(t, res, body)
+
+		i, c, err := reg.PrivilegedIdentityPool().FindByCredentialsIdentifier(ctx, identity.CredentialsTypeSAML, identity.SAMLUniqueID("idp", subject))
+		require.NoError(t, err)
+		assert.Equal(t, email, gjson.GetBytes(i.Traits, "email").String())
+		assert.JSONEq(t, `{"providers":[{"provider":"idp","subject":"`+subject+`"}]}`, string(c.Config))
+
+		t.Run("case=signs in with the registered identity", func(t *testing.T) {
+			res, body := login(t, testhelpers.NewClientWithCookieJar(t, nil, false))
+			assertIdentity(t, res, body)
+			assert.Equal(t, i.ID.String(), gjson.GetBytes(body, "identity.id").String())
+		})
+	})
+
+	t.Run("case=registers the identity when signing in for the first time", func(t *testing.T) {
+		reset(t)
+
+		res, body := login(t, testhelpers.NewClientWithCookieJar(t, nil, false))
+		assertIdentity(t, res, body)
+	})
+
+	t.Run("case=fails if the response does not answer the browser's request", func(t *testing.T) {
+		reset(t)
+		modify = func(r *saml.TestResponse) {
+			r.InResponseTo = "id-of-another-request"
+		}
+
+		res, body := login(t, testhelpers.NewClientWithCookieJar(t, nil, false))
+		require.Contains(t, res.Request.URL.String(), loginTS.URL, "%s", body)
+		assert.Contains(t, gjson.GetBytes(body, "ui.messages.0.text").String(), "does not answer the authentication request sent by this browser", "%s", body)
+	})
+
+	t.Run("case=fails if the response is not signed", func(t *testing.T) {
+		reset(t)
+		modify = func(r *saml.TestResponse) {
+			r.SignAssertion = false
+		}
+
+		res, body := login(t, testhelpers.NewClientWithCookieJar(t, nil, false))
+		require.Contains(t, res.Request.URL.String(), loginTS.URL, "%s", body)
+		assert.Contains(t, gjson.GetBytes(body, "ui.messages.0.text").String(), "neither the response nor the assertion is signed", "%s", body)
+	})
+
+	t.Run("case=fails if the assertion is replayed", func(t *testing.T) {
+		reset(t)
+
+		client := testhelpers.NewClientWithCookieJar(t, nil, false)
+		f := testhelpers.InitializeLoginFlowViaBrowser(t, client, publicTS, false, false, false, false)
+		res, err := client.PostForm(f.Ui.Action, url.Values{"csrf_token": {x.FakeCSRFToken}, "method": {"saml"}, "saml_provider": {"idp"}})
+		require.NoError(t, err)
+		body := x.MustReadAll(res.Body)
+		require.NoError(t, res.Body.Close())
+
+		values := url.Values{
+			"SAMLResponse": {gjson.GetBytes(body, "SAMLResponse").String()},
+			"RelayState":   {gjson.GetBytes(body, "RelayState").String()},
+		}
+		res, err = client.PostForm(gjson.GetBytes(body, "acs").String(), values)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		require.Contains(t, res.Request.URL.String(), returnTS.URL)
+
+		res, err = testhelpers.NewClientWithCookieJar(t, nil, false).PostForm(gjson.GetBytes(body, "acs").String(), values)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body = x.MustReadAll(res.Body)
+		assert.NotContains(t, res.Request.URL.String(), returnTS.URL, "%s", body)
+		assert.Contains(t, string(body), "The SAML assertion was already used", "%s", body)
+	})
+
+	t.Run("case=fails if the callback is opened by another browser", func(t *testing.T) {
+		reset(t)
+
+		client := testhelpers.NewClientWithCookieJar(t, nil, false)
+		f := testhelpers.InitializeLoginFlowViaBrowser(t, client, publicTS, false, false, false, false)
+		res, err := client.PostForm(f.Ui.Action, url.Values{"csrf_token": {x.FakeCSRFToken}, "method": {"saml"}, "saml_provider": {"idp"}})
+		require.NoError(t, err)
+		body := x.MustReadAll(res.Body)
+		require.NoError(t, res.Body.Close())
+
+		// The attacker's browser does not have the continuity cookie of the victim's browser.
+		res, err = testhelpers.NewClientWithCookieJar(t, nil, false).PostForm(gjson.GetBytes(body, "acs").String(), url.Values{
+			"SAMLResponse": {gjson.GetBytes(body, "SAMLResponse").String()},
+			"RelayState":   {gjson.GetBytes(body, "RelayState").String()},
+		})
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body = x.MustReadAll(res.Body)
+		assert.NotContains(t, res.Request.URL.String(), returnTS.URL, "%s", body)
+		assert.False(t, gjson.GetBytes(body, "identity").Exists(), "%s", body)
+	})
+
+	t.Run("case=redirects to the flow's return_to URL if signed in before returning from the provider", func(t *testing.T) {
+		reset(t)
+		conf.MustSet(ctx, config.ViperKeyURLsAllowedReturnToDomains, []string{returnTS.URL})
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeyURLsAllowedReturnToDomains, []string{})
+		})
+
+		client := testhelpers.NewClientWithCookieJar(t, nil, false)
+		f := testhelpers.InitializeLoginFlowViaBrowser(t, client, publicTS, false, false, false, false,
+			testhelpers.InitFlowWithReturnTo(returnTS.URL+"/return-to"))
+		res, err := client.PostForm(f.Ui.Action, url.Values{"csrf_token": {x.FakeCSRFToken}, "method": {"saml"}, "saml_provider": {"idp"}})
+		require.NoError(t, err)
+		body := x.MustReadAll(res.Body)
+		require.NoError(t, res.Body.Close())
+
+		// The browser signs in with another tab while it is at the identity provider.
+		signedIn := testhelpers.NewClientWithCookieJar(t, nil, false)
+		reset(t)
+		_, _ = register(t, signedIn)
+		publicURL := urlx.ParseOrPanic(publicTS.URL)
+		for _, c := range signedIn.Jar.Cookies(publicURL) {
+			if c.Name == config.DefaultSessionCookieName {
+				client.Jar.SetCookies(publicURL, []*http.Cookie{c})
+			}
+		}
+
+		res, err = client.PostForm(gjson.GetBytes(body, "acs").String(), url.Values{
+			"SAMLResponse": {gjson.GetBytes(body, "SAMLResponse").String()},
+			"RelayState":   {gjson.GetBytes(body, "RelayState").String()},
+		})
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body = x.MustReadAll(res.Body)
+
+		assert.Equal(t, returnTS.URL+"/return-to", res.Request.URL.String(), "%s", body)
+	})
+
+	t.Run("case=rejects API flows", func(t *testing.T) {
+		f := testhelpers.InitializeLoginFlowViaAPI(t, http.DefaultClient, publicTS, false)
+		raw, err := json.Marshal(f.Ui.Nodes)
+		require.NoError(t, err)
+		assert.False(t, gjson.GetBytes(raw, `#(attributes.name=="saml_provider")`).Exists(), "%s", raw)
+	})
+
+	t.Run("suite=settings", func(t *testing.T) {
+		reset(t)
+		id := &identity.Identity{
+			ID:       x.NewUUID(),
+			Traits:   identity.Traits(`{"email":"` + email + `"}`),
+			SchemaID: config.DefaultIdentityTraitsSchemaID,
+			Credentials: map[identity.CredentialsType]identity.Credentials{
+				identity.CredentialsTypePassword: {
+					Type:        identity.CredentialsTypePassword,
+					Identifiers: []string{email},
+					Config:      sqlxx.JSONRawMessage(`{"hashed_password":"$argon2id$iammocked...."}`),
+				},
+			},
+		}
+		client := testhelpers.AddAndLoginIdentities(t, reg, publicTS, map[string]*identity.Identity{"user": id})["user"]
+
+		var submit = func(t *testing.T, values url.Values) (*http.Response, []byte) {
+			f := testhelpers.InitializeSettingsFlowViaBrowser(t, client, false, publicTS)
+			if values.Has("saml_link") {
+				return authenticate(t, client, f.Ui.Action, values)
+			}
+
+			values.Set("csrf_token", x.FakeCSRFToken)
+			res, err := client.PostForm(f.Ui.Action, values)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			return res, x.MustReadAll(res.Body)
+		}
+
+		t.Run("case=links the provider", func(t *testing.T) {
+			res, body := submit(t, url.Values{"method": {"saml"}, "saml_link": {"idp"}})
+			require.Contains(t, res.Request.URL.String(), settingsTS.URL, "%s", body)
+			assert.Equal(t, "success", gjson.GetBytes(body, "state").String(), "%s", body)
+
+			i, _, err := reg.PrivilegedIdentityPool().FindByCredentialsIdentifier(ctx, identity.CredentialsTypeSAML, identity.SAMLUniqueID("idp", subject))
+			require.NoError(t, err)
+			assert.Equal(t, id.ID, i.ID)
+		})
+
+		t.Run("case=unlinks the provider", func(t *testing.T) {
+			res, body := submit(t, url.Values{"method": {"saml"}, "saml_unlink": {"idp"}})
+			require.Contains(t, res.Request.URL.String(), settingsTS.URL, "%s", body)
+			assert.Equal(t, "success", gjson.GetBytes(body, "state").String(), "%s", body)
+
+			_, _, err := reg.PrivilegedIdentityPool().FindByCredentialsIdentifier(ctx, identity.CredentialsTypeSAML, identity.SAMLUniqueID("idp", subject))
+			require.ErrorIs(t, err, sqlcon.ErrNoRows)
+		})
+	})
+}
+
+func TestCompletedAuthenticationMethod(t *testing.T) {
+	_, reg := internal.NewFastRegistryWithMocks(t)
+	strategy := saml.NewStrategy(reg)
+
+	assert.Equal(t, session.AuthenticationMethod{
+		Method: identity.CredentialsTypeSAML,
+		AAL:    identity.AuthenticatorAssuranceLevel1,
+	}, strategy.CompletedAuthenticationMethod(context.Background()))
+}
+
+func TestCountActiveFirstFactorCredentials(t *testing.T) {
+	_, reg := internal.NewFastRegistryWithMocks(t)
+	strategy := saml.NewStrategy(reg)
+
+	for k, tc := range []struct {
+		in       identity.Credentials
+		expected int
+	}{
+		{in: identity.Credentials{Type: strategy.ID(), Config: []byte(`{}`)}},
+		{in: identity.Credentials{Type: strategy.ID(), Identifiers: []string{"idp:foo"}, Config: []byte(`{"providers":[]}`)}},
+		{in: identity.Credentials{Type: strategy.ID(), Identifiers: []string{"other:foo"}, Config: []byte(`{"providers":[{"provider":"idp","subject":"foo"}]}`)}},
+		{in: identity.Credentials{Type: strategy.ID(), Identifiers: []string{"idp:foo"}, Config: []byte(`{"providers":[{"provider":"idp","subject":"foo"}]}`)}, expected: 1},
+	} {
+		t.Run(fmt.Sprintf("case=%d", k), func(t *testing.T) {
+			actual, err := strategy.CountActiveFirstFactorCredentials(map[identity.CredentialsType]identity.Credentials{strategy.ID(): tc.in})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	"github.com/ory/x/stringsx"
+
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/container"
+	"github.com/ory/kratos/ui/node"
+)
+
+func AddProviders(c *container.Container, providers []Configuration, message func(provider string) *text.Message) {
+	for _, p := range providers {
+		AddProvider(c, p.ID, message(
+			stringsx.Coalesce(p.Label, p.ID)))
+	}
+}
+
+func AddProvider(c *container.Container, providerID string, message *text.Message) {
+	c.GetNodes().Append(
+		node.NewInputField("saml_provider", providerID, node.SAMLGroup, node.InputAttributeTypeSubmit).WithMetaLabel(message),
+	)
+}
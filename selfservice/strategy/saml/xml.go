@@ -0,0 +1,84 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/pkg/errors"
+)
+
+const (
+	nsDSig      = "http://www.w3.org/2000/09/xmldsig#"
+	nsMetadata  = "urn:oasis:names:tc:SAML:2.0:metadata"
+	nsAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+	nsProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+)
+
+// parseXML parses the document and returns its root element. Documents containing a DTD are rejected.
+func parseXML(raw []byte) (*etree.Element, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(raw); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for _, t := range doc.Child {
+		if _, ok := t.(*etree.Directive); ok {
+			return nil, errors.New("XML documents with a document type definition are not supported")
+		}
+	}
+
+	root := doc.Root()
+	if root == nil {
+		return nil, errors.New("the XML document is incomplete")
+	}
+
+	return root, nil
+}
+
+// isElement returns true if the element has the given namespace and local name.
+func isElement(e *etree.Element, ns, local string) bool {
+	return e.Tag == local && e.NamespaceURI() == ns
+}
+
+func childElements(e *etree.Element, ns, local string) (result []*etree.Element) {
+	for _, c := range e.ChildElements() {
+		if isElement(c, ns, local) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func childElement(e *etree.Element, ns, local string) *etree.Element {
+	if children := childElements(e, ns, local); len(children) > 0 {
+		return children[0]
+	}
+	return nil
+}
+
+// attr returns the value of the attribute without namespace.
+func attr(e *etree.Element, key string) string {
+	for _, a := range e.Attr {
+		if a.Space == "" && a.Key == key {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// elementText returns the trimmed character data of the element.
+func elementText(e *etree.Element) string {
+	return strings.TrimSpace(e.Text())
+}
+
+// xmlEscape escapes text for use in both attribute values and character data.
+func xmlEscape(in string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(in))
+	return b.String()
+}
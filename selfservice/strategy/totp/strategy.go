@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/pquerna/otp"
 
+	"github.com/ory/kratos/bruteforce"
 	"github.com/ory/kratos/continuity"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/hash"
@@ -64,6 +65,8 @@ type registrationStrategyDependencies interface {
 	session.HandlerProvider
 	session.ManagementProvider
 	session.PersistenceProvider
+
+	bruteforce.ThrottlerProvider
 }
 
 type Strategy struct {
@@ -15,7 +15,8 @@ import (
 //go:embed js/webauthn.js
 var jsOnLoad []byte
 
-const webAuthnRoute = "/.well-known/ory/webauthn.js"
+// ScriptURL is the path of the JavaScript which is shared by the WebAuthn and passkey strategies.
+const ScriptURL = "/.well-known/ory/webauthn.js"
 
 // swagger:model webAuthnJavaScript
 type webAuthnJavaScript string
@@ -42,8 +43,13 @@ type webAuthnJavaScript string
 //	Responses:
 //	  200: webAuthnJavaScript
 func (s *Strategy) RegisterLoginRoutes(r *x.RouterPublic) {
-	if handle, _, _ := r.Lookup("GET", webAuthnRoute); handle == nil {
-		r.GET(webAuthnRoute, func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	RegisterScriptRoute(r)
+}
+
+// RegisterScriptRoute serves the WebAuthn JavaScript at ScriptURL unless it is already registered.
+func RegisterScriptRoute(r *x.RouterPublic) {
+	if handle, _, _ := r.Lookup("GET", ScriptURL); handle == nil {
+		r.GET(ScriptURL, func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 			w.Header().Set("Content-Type", "text/javascript; charset=UTF-8")
 			_, _ = w.Write([]byte(webAuthnJavaScript(jsOnLoad)))
 		})
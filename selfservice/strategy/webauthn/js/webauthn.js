@@ -93,7 +93,121 @@
     })
   }
 
+  function __oryPasskeyBufferDecode(value) {
+    return __oryWebAuthnBufferDecode(value.replace(/-/g, '+').replace(/_/g, '/'));
+  }
+
+  function __oryPasskeyLoginOptions() {
+    const challenge = document.querySelector('*[name="passkey_challenge"]');
+    if (!challenge || !challenge.value) {
+      return null
+    }
+
+    const opt = JSON.parse(challenge.value);
+    opt.publicKey.challenge = __oryPasskeyBufferDecode(opt.publicKey.challenge);
+    // Passkeys are discoverable, which is why we never restrict the allowed credentials.
+    opt.publicKey.allowCredentials = [];
+    return opt
+  }
+
+  function __oryPasskeyLoginSubmit(credential, resultQuerySelector = '*[name="passkey_login"]') {
+    const result = document.querySelector(resultQuerySelector);
+    result.value = JSON.stringify({
+      id: credential.id,
+      rawId: __oryWebAuthnBufferEncode(credential.rawId),
+      type: credential.type,
+      response: {
+        authenticatorData: __oryWebAuthnBufferEncode(credential.response.authenticatorData),
+        clientDataJSON: __oryWebAuthnBufferEncode(credential.response.clientDataJSON),
+        signature: __oryWebAuthnBufferEncode(credential.response.signature),
+        userHandle: __oryWebAuthnBufferEncode(credential.response.userHandle),
+      },
+    })
+
+    result.closest('form').submit()
+  }
+
+  function __oryPasskeyLogin() {
+    if (!window.PublicKeyCredential) {
+      alert('This browser does not support WebAuthn!');
+    }
+
+    const opt = __oryPasskeyLoginOptions();
+    if (!opt) {
+      return
+    }
+
+    navigator.credentials.get(opt).then(function (credential) {
+      __oryPasskeyLoginSubmit(credential)
+    }).catch((err) => {
+      alert(err)
+    })
+  }
+
+  // Offers the passkeys as autofill suggestions for inputs with autocomplete="username webauthn".
+  function __oryPasskeyLoginAutocompleteInit() {
+    if (!window.PublicKeyCredential || !PublicKeyCredential.isConditionalMediationAvailable) {
+      return
+    }
+
+    PublicKeyCredential.isConditionalMediationAvailable().then(function (available) {
+      const opt = __oryPasskeyLoginOptions();
+      if (!available || !opt) {
+        return
+      }
+
+      navigator.credentials.get({...opt, mediation: 'conditional'}).then(function (credential) {
+        __oryPasskeyLoginSubmit(credential)
+      }).catch((err) => {
+        console.error(err)
+      })
+    })
+  }
+
+  function __oryPasskeyRegistration(opt, resultQuerySelector = '*[name="passkey_register"]', triggerQuerySelector = '*[name="passkey_register_trigger"]') {
+    if (!window.PublicKeyCredential) {
+      alert('This browser does not support WebAuthn!');
+    }
+
+    opt.publicKey.user.id = __oryPasskeyBufferDecode(opt.publicKey.user.id);
+    opt.publicKey.challenge = __oryPasskeyBufferDecode(opt.publicKey.challenge);
+
+    if (opt.publicKey.excludeCredentials) {
+      opt.publicKey.excludeCredentials = opt.publicKey.excludeCredentials.map(function (value) {
+        return {
+          ...value,
+          id: __oryPasskeyBufferDecode(value.id)
+        }
+      })
+    }
+
+    navigator.credentials.create(opt).then(function (credential) {
+      document.querySelector(resultQuerySelector).value = JSON.stringify({
+        id: credential.id,
+        rawId: __oryWebAuthnBufferEncode(credential.rawId),
+        type: credential.type,
+        response: {
+          attestationObject: __oryWebAuthnBufferEncode(credential.response.attestationObject),
+          clientDataJSON: __oryWebAuthnBufferEncode(credential.response.clientDataJSON),
+        },
+      })
+
+      document.querySelector(triggerQuerySelector).closest('form').submit()
+    }).catch((err) => {
+      alert(err)
+    })
+  }
+
   window['__oryWebAuthnLogin'] = __oryWebAuthnLogin
   window['__oryWebAuthnRegistration'] = __oryWebAuthnRegistration
+  window['__oryPasskeyLogin'] = __oryPasskeyLogin
+  window['__oryPasskeyLoginAutocompleteInit'] = __oryPasskeyLoginAutocompleteInit
+  window['__oryPasskeyRegistration'] = __oryPasskeyRegistration
   window['__oryWebAuthnInitialized'] = true
+
+  if (document.readyState === 'loading') {
+    document.addEventListener('DOMContentLoaded', __oryPasskeyLoginAutocompleteInit)
+  } else {
+    __oryPasskeyLoginAutocompleteInit()
+  }
 })()
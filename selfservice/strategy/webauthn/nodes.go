@@ -8,8 +8,10 @@ import (
 	_ "embed"
 	"encoding/base64"
 	"fmt"
+	"net/url"
 
 	"github.com/ory/x/stringsx"
+	"github.com/ory/x/urlx"
 
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/text"
@@ -28,6 +30,12 @@ func NewWebAuthnScript(src string, contents []byte) *node.Node {
 	return node.NewScriptField(node.WebAuthnScript, src, node.WebAuthnGroup, fmt.Sprintf("sha512-%s", base64.StdEncoding.EncodeToString(integrity[:])))
 }
 
+// NewScript returns a script node of the given group which loads the JavaScript served at ScriptURL.
+func NewScript(base *url.URL, name string, group node.UiNodeGroup) *node.Node {
+	integrity := sha512.Sum512(jsOnLoad)
+	return node.NewScriptField(name, urlx.AppendPaths(base, ScriptURL).String(), group, fmt.Sprintf("sha512-%s", base64.StdEncoding.EncodeToString(integrity[:])))
+}
+
 func NewWebAuthnConnectionInput() *node.Node {
 	return node.NewInputField(node.WebAuthnRegister, "", node.WebAuthnGroup,
 		node.InputAttributeTypeHidden)
@@ -0,0 +1,258 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/otelx"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/x"
+)
+
+var (
+	_ Persister                 = new(CachingPersister)
+	_ identity.CacheInvalidator = new(CachingPersister)
+)
+
+type (
+	// Cache stores encoded sessions. Every entry carries tags, which allows dropping all entries of a session
+	// or of an identity at once.
+	Cache interface {
+		// Get returns the value stored at key, and false if there is none.
+		Get(ctx context.Context, key string) ([]byte, bool, error)
+
+		// Set stores the value at key for the given duration and tags it.
+		Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error
+
+		// Invalidate removes all entries carrying any of the tags.
+		Invalidate(ctx context.Context, tags ...string) error
+	}
+	CacheProvider interface {
+		SessionCache() Cache
+	}
+
+	cachingPersisterDependencies interface {
+		config.Provider
+		x.TracingProvider
+	}
+	networkPersister interface {
+		Persister
+		NetworkID(ctx context.Context) uuid.UUID
+	}
+	// CachingPersister caches sessions looked up by their token in front of a Persister. All methods which
+	// change or remove sessions invalidate the affected cache entries once the change was persisted.
+	CachingPersister struct {
+		networkPersister
+		r cachingPersisterDependencies
+		c Cache
+	}
+)
+
+func NewCachingPersister(r cachingPersisterDependencies, p networkPersister, c Cache) *CachingPersister {
+	return &CachingPersister{networkPersister: p, r: r, c: c}
+}
+
+func hashSessionToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+func (p *CachingPersister) tag(ctx context.Context, kind, value string) string {
+	return "session:" + p.NetworkID(ctx).String() + ":" + kind + ":" + value
+}
+
+func (p *CachingPersister) sessionTag(ctx context.Context, sid uuid.UUID) string {
+	return p.tag(ctx, "id", sid.String())
+}
+
+func (p *CachingPersister) identityTag(ctx context.Context, iid uuid.UUID) string {
+	return p.tag(ctx, "identity", iid.String())
+}
+
+func (p *CachingPersister) tokenTag(ctx context.Context, token string) string {
+	return p.tag(ctx, "token", hashSessionToken(token))
+}
+
+func (p *CachingPersister) GetSessionByToken(ctx context.Context, token string, expandables Expandables, identityExpandables identity.Expandables) (_ *Session, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "sessions.CachingPersister.GetSessionByToken")
+	defer otelx.End(span, &err)
+
+	// The expandables are part of the key because they change what the session contains.
+	key := p.tokenTag(ctx, token) + ":" + strings.Join(expandables.ToEager(), ",") + ":" + strings.Join(identityExpandables.ToEager(), ",")
+	if value, ok, err := p.c.Get(ctx, key); err != nil {
+		return nil, err
+	} else if ok {
+		return decodeCachedSession(value)
+	}
+
+	s, err := p.networkPersister.GetSessionByToken(ctx, token, expandables, identityExpandables)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := p.r.Config().SessionCache(ctx).TTL
+	if untilExpiry := time.Until(s.ExpiresAt); untilExpiry < ttl {
+		ttl = untilExpiry
+	}
+	if ttl <= 0 {
+		return s, nil
+	}
+
+	value, err := encodeCachedSession(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.c.Set(ctx, key, value, ttl, p.tokenTag(ctx, token), p.sessionTag(ctx, s.ID), p.identityTag(ctx, s.IdentityID)); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// cachedSession is how sessions are stored in the cache. The JSON representation of a session omits fields such as
+// its token or the credentials of its identity, which is why they are stored alongside.
+type cachedSession struct {
+	Session     *Session                                        `json:"session"`
+	Identity    *identity.WithCredentialsAndAdminMetadataInJSON `json:"identity"`
+	IdentityID  uuid.UUID                                       `json:"identity_id"`
+	Token       string                                          `json:"token"`
+	LogoutToken string                                          `json:"logout_token"`
+	RequiredAAL identity.AuthenticatorAssuranceLevel            `json:"required_aal"`
+	NID         uuid.UUID                                       `json:"nid"`
+	CreatedAt   time.Time                                       `json:"created_at"`
+	UpdatedAt   time.Time                                       `json:"updated_at"`
+}
+
+func encodeCachedSession(s *Session) ([]byte, error) {
+	withoutIdentity := *s
+	withoutIdentity.Identity = nil
+
+	value, err := json.Marshal(&cachedSession{
+		Session:     &withoutIdentity,
+		Identity:    (*identity.WithCredentialsAndAdminMetadataInJSON)(s.Identity),
+		IdentityID:  s.IdentityID,
+		Token:       s.Token,
+		LogoutToken: s.LogoutToken,
+		RequiredAAL: s.RequiredAAL,
+		NID:         s.NID,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+	})
+	return value, errors.WithStack(err)
+}
+
+func decodeCachedSession(value []byte) (*Session, error) {
+	var c cachedSession
+	if err := json.Unmarshal(value, &c); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	s := c.Session
+	s.IdentityID = c.IdentityID
+	s.Token = c.Token
+	s.LogoutToken = c.LogoutToken
+	s.RequiredAAL = c.RequiredAAL
+	s.NID = c.NID
+	s.CreatedAt = c.CreatedAt
+	s.UpdatedAt = c.UpdatedAt
+	for k := range s.Devices {
+		s.Devices[k].SessionID = s.ID
+		s.Devices[k].NID = s.NID
+	}
+
+	if c.Identity != nil {
+		i := (*identity.Identity)(c.Identity)
+		i.NID = s.NID
+		for k := range i.VerifiableAddresses {
+			i.VerifiableAddresses[k].IdentityID = i.ID
+			i.VerifiableAddresses[k].NID = s.NID
+		}
+		for k := range i.RecoveryAddresses {
+			i.RecoveryAddresses[k].IdentityID = i.ID
+			i.RecoveryAddresses[k].NID = s.NID
+		}
+		for t, cred := range i.Credentials {
+			cred.IdentityID = i.ID
+			cred.NID = s.NID
+			i.Credentials[t] = cred
+		}
+		s.Identity = i
+	}
+	return s, nil
+}
+
+func (p *CachingPersister) UpsertSession(ctx context.Context, s *Session) error {
+	if err := p.networkPersister.UpsertSession(ctx, s); err != nil {
+		return err
+	}
+	return p.c.Invalidate(ctx, p.sessionTag(ctx, s.ID))
+}
+
+func (p *CachingPersister) DeleteSession(ctx context.Context, id uuid.UUID) error {
+	if err := p.networkPersister.DeleteSession(ctx, id); err != nil {
+		return err
+	}
+	return p.c.Invalidate(ctx, p.sessionTag(ctx, id))
+}
+
+func (p *CachingPersister) DeleteSessionsByIdentity(ctx context.Context, iID uuid.UUID) error {
+	if err := p.networkPersister.DeleteSessionsByIdentity(ctx, iID); err != nil {
+		return err
+	}
+	return p.c.Invalidate(ctx, p.identityTag(ctx, iID))
+}
+
+func (p *CachingPersister) DeleteSessionByToken(ctx context.Context, token string) error {
+	if err := p.networkPersister.DeleteSessionByToken(ctx, token); err != nil {
+		return err
+	}
+	return p.c.Invalidate(ctx, p.tokenTag(ctx, token))
+}
+
+func (p *CachingPersister) RevokeSessionByToken(ctx context.Context, token string) error {
+	if err := p.networkPersister.RevokeSessionByToken(ctx, token); err != nil {
+		return err
+	}
+	return p.c.Invalidate(ctx, p.tokenTag(ctx, token))
+}
+
+func (p *CachingPersister) RevokeSessionById(ctx context.Context, sID uuid.UUID) error {
+	if err := p.networkPersister.RevokeSessionById(ctx, sID); err != nil {
+		return err
+	}
+	return p.c.Invalidate(ctx, p.sessionTag(ctx, sID))
+}
+
+func (p *CachingPersister) RevokeSession(ctx context.Context, iID, sID uuid.UUID) error {
+	if err := p.networkPersister.RevokeSession(ctx, iID, sID); err != nil {
+		return err
+	}
+	return p.c.Invalidate(ctx, p.sessionTag(ctx, sID))
+}
+
+func (p *CachingPersister) RevokeSessionsIdentityExcept(ctx context.Context, iID, sID uuid.UUID) (int, error) {
+	count, err := p.networkPersister.RevokeSessionsIdentityExcept(ctx, iID, sID)
+	if err != nil {
+		return 0, err
+	}
+
+	// The session which was not revoked is dropped as well, it will be cached again on its next use.
+	return count, p.c.Invalidate(ctx, p.identityTag(ctx, iID))
+}
+
+// InvalidateIdentity drops all cached sessions of the identity, for example because the identity was updated.
+func (p *CachingPersister) InvalidateIdentity(ctx context.Context, id uuid.UUID) error {
+	return p.c.Invalidate(ctx, p.identityTag(ctx, id))
+}
@@ -0,0 +1,94 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+)
+
+var _ Cache = new(MemoryCache)
+
+type (
+	memoryCacheEntry struct {
+		value     []byte
+		expiresAt time.Time
+		tags      []string
+	}
+	// MemoryCache is a Cache which holds the least recently used entries in process memory.
+	MemoryCache struct {
+		sync.Mutex
+		entries *lru.Cache
+		tags    map[string]map[string]struct{}
+	}
+)
+
+func NewMemoryCache(maxEntries int) (*MemoryCache, error) {
+	c := &MemoryCache{tags: make(map[string]map[string]struct{})}
+	entries, err := lru.NewWithEvict(maxEntries, c.onEvict)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	c.entries = entries
+	return c, nil
+}
+
+// onEvict is called by the LRU cache, which happens while the mutex is held.
+func (c *MemoryCache) onEvict(key, value interface{}) {
+	for _, tag := range value.(*memoryCacheEntry).tags {
+		delete(c.tags[tag], key.(string))
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
+
+func (c *MemoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	value, ok := c.entries.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := value.(*memoryCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.entries.Remove(key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (c *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	// Removing the previous entry first keeps the tag index free of stale tags.
+	c.entries.Remove(key)
+	c.entries.Add(key, &memoryCacheEntry{value: value, expiresAt: time.Now().Add(ttl), tags: tags})
+	for _, tag := range tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = make(map[string]struct{})
+		}
+		c.tags[tag][key] = struct{}{}
+	}
+	return nil
+}
+
+func (c *MemoryCache) Invalidate(_ context.Context, tags ...string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			c.entries.Remove(key)
+		}
+	}
+	return nil
+}
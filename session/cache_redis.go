@@ -0,0 +1,79 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+var _ Cache = new(RedisCache)
+
+// RedisCache is a Cache backed by Redis, which allows sharing the cache and its invalidations between instances.
+//
+// Every tag is stored as a set holding the keys of the entries carrying it. The sets expire together with the
+// longest-living entry they reference.
+type RedisCache struct {
+	c redis.UniversalClient
+}
+
+func NewRedisCache(c redis.UniversalClient) *RedisCache {
+	return &RedisCache{c: c}
+}
+
+// NewRedisCacheFromURL connects to Redis using an URL such as `redis://localhost:6379/0`.
+func NewRedisCacheFromURL(u string) (*RedisCache, error) {
+	opts, err := redis.ParseURL(u)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return NewRedisCache(redis.NewClient(opts)), nil
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.c.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+	return value, true, nil
+}
+
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	_, err := c.c.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.Set(ctx, key, value, ttl)
+		for _, tag := range tags {
+			p.SAdd(ctx, tag, key)
+			p.ExpireGT(ctx, tag, ttl)
+			// EXPIRE ... GT does not set an expiry on sets which have none, which is the case for new sets.
+			p.ExpireNX(ctx, tag, ttl)
+		}
+		return nil
+	})
+	return errors.WithStack(err)
+}
+
+func (c *RedisCache) Invalidate(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		keys, err := c.c.SMembers(ctx, tag).Result()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if _, err := c.c.TxPipelined(ctx, func(p redis.Pipeliner) error {
+			if len(keys) > 0 {
+				p.Del(ctx, keys...)
+			}
+			p.Del(ctx, tag)
+			return nil
+		}); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package session_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x"
+)
+
+func newMiniredisCache(t *testing.T) (*session.RedisCache, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	c, err := session.NewRedisCacheFromURL("redis://" + mr.Addr())
+	require.NoError(t, err)
+	return c, mr
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+
+	memory, err := session.NewMemoryCache(2)
+	require.NoError(t, err)
+	redis, mr := newMiniredisCache(t)
+
+	for name, tc := range map[string]struct {
+		c       session.Cache
+		advance func(time.Duration)
+	}{
+		"memory": {c: memory, advance: func(d time.Duration) { time.Sleep(d) }},
+		"redis":  {c: redis, advance: mr.FastForward},
+	} {
+		t.Run("backend="+name, func(t *testing.T) {
+			c := tc.c
+			key := func(k string) string { return name + ":" + k }
+
+			expectValue := func(t *testing.T, k string, expected string) {
+				value, ok, err := c.Get(ctx, key(k))
+				require.NoError(t, err)
+				if expected == "" {
+					assert.False(t, ok)
+					return
+				}
+				require.True(t, ok)
+				assert.Equal(t, expected, string(value))
+			}
+
+			t.Run("case=get and set", func(t *testing.T) {
+				expectValue(t, "a", "")
+				require.NoError(t, c.Set(ctx, key("a"), []byte("value-a"), time.Minute, "tag-1"))
+				expectValue(t, "a", "value-a")
+			})
+
+			t.Run("case=invalidate removes tagged entries", func(t *testing.T) {
+				require.NoError(t, c.Set(ctx, key("b"), []byte("value-b"), time.Minute, "tag-2", "tag-3"))
+				require.NoError(t, c.Set(ctx, key("c"), []byte("value-c"), time.Minute, "tag-3"))
+
+				require.NoError(t, c.Invalidate(ctx, "tag-2"))
+				expectValue(t, "b", "")
+				expectValue(t, "c", "value-c")
+
+				require.NoError(t, c.Invalidate(ctx, "tag-3", "unknown-tag"))
+				expectValue(t, "c", "")
+			})
+
+			t.Run("case=entries expire", func(t *testing.T) {
+				require.NoError(t, c.Set(ctx, key("d"), []byte("value-d"), 50*time.Millisecond, "tag-4"))
+				expectValue(t, "d", "value-d")
+				tc.advance(100 * time.Millisecond)
+				expectValue(t, "d", "")
+			})
+		})
+	}
+
+	t.Run("case=memory cache evicts least recently used entries", func(t *testing.T) {
+		c, err := session.NewMemoryCache(2)
+		require.NoError(t, err)
+
+		for k := 0; k < 3; k++ {
+			require.NoError(t, c.Set(ctx, fmt.Sprintf("key-%d", k), []byte("value"), time.Minute, "tag"))
+		}
+
+		_, ok, err := c.Get(ctx, "key-0")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		// Invalidating a tag whose entries were partially evicted must still work.
+		require.NoError(t, c.Invalidate(ctx, "tag"))
+		_, ok, err = c.Get(ctx, "key-2")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestCachingPersister(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/identity.schema.json")
+
+	cache, mr := newMiniredisCache(t)
+	reg.WithSessionCache(cache)
+	p := reg.SessionPersister()
+	require.IsType(t, new(session.CachingPersister), p)
+
+	newSession := func(t *testing.T) *session.Session {
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.Traits = identity.Traits(`{"email":"` + x.NewUUID().String() + `@ory.sh"}`)
+		require.NoError(t, reg.IdentityManager().Create(ctx, i))
+
+		s, err := session.NewActiveSession(x.NewTestHTTPRequest(t, "GET", "/", nil), i, conf, time.Now().UTC(), identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+		require.NoError(t, err)
+		require.NoError(t, p.UpsertSession(ctx, s))
+		return s
+	}
+
+	fetch := func(t *testing.T, s *session.Session) *session.Session {
+		actual, err := p.GetSessionByToken(ctx, s.Token, session.ExpandEverything, identity.ExpandDefault)
+		require.NoError(t, err)
+		return actual
+	}
+
+	// expectCached checks whether the session is served from the cache by changing the database behind its back.
+	expectCached := func(t *testing.T, s *session.Session, cached bool) {
+		require.NoError(t, reg.Persister().RevokeSessionByToken(ctx, s.Token))
+		assert.Equal(t, cached, fetch(t, s).IsActive())
+	}
+
+	t.Run("case=serves sessions from the cache", func(t *testing.T) {
+		s := newSession(t)
+		actual := fetch(t, s)
+		assert.Equal(t, s.ID, actual.ID)
+		assert.Equal(t, s.Token, actual.Token)
+		assert.Equal(t, s.IdentityID, actual.Identity.ID)
+		assert.NotEmpty(t, actual.Identity.VerifiableAddresses)
+		assert.NotEmpty(t, mr.Keys())
+
+		// Fields which are not part of the session's JSON representation survive the cache.
+		cached := fetch(t, s)
+		assert.Equal(t, actual.Token, cached.Token)
+		assert.Equal(t, actual.LogoutToken, cached.LogoutToken)
+		assert.Equal(t, actual.NID, cached.NID)
+		assert.Equal(t, actual.IdentityID, cached.IdentityID)
+		assert.Equal(t, actual.Identity.NID, cached.Identity.NID)
+		assert.Equal(t, actual.Identity.VerifiableAddresses[0].IdentityID, cached.Identity.VerifiableAddresses[0].IdentityID)
+		assert.Len(t, cached.Devices, len(actual.Devices))
+
+		expectCached(t, s, true)
+	})
+
+	t.Run("case=cache entries do not outlive the session", func(t *testing.T) {
+		s := newSession(t)
+		s.ExpiresAt = time.Now().UTC().Add(time.Second)
+		require.NoError(t, reg.Persister().UpsertSession(ctx, s))
+
+		fetch(t, s)
+		mr.FastForward(2 * time.Second)
+		expectCached(t, s, false)
+	})
+
+	for name, invalidate := range map[string]func(t *testing.T, s *session.Session){
+		"RevokeSessionByToken": func(t *testing.T, s *session.Session) {
+			require.NoError(t, p.RevokeSessionByToken(ctx, s.Token))
+		},
+		"RevokeSessionById": func(t *testing.T, s *session.Session) {
+			require.NoError(t, p.RevokeSessionById(ctx, s.ID))
+		},
+		"RevokeSession": func(t *testing.T, s *session.Session) {
+			require.NoError(t, p.RevokeSession(ctx, s.IdentityID, s.ID))
+		},
+		"RevokeSessionsIdentityExcept": func(t *testing.T, s *session.Session) {
+			_, err := p.RevokeSessionsIdentityExcept(ctx, s.IdentityID, x.NewUUID())
+			require.NoError(t, err)
+		},
+		"UpsertSession": func(t *testing.T, s *session.Session) {
+			require.NoError(t, p.UpsertSession(ctx, s))
+		},
+		"identity update": func(t *testing.T, s *session.Session) {
+			i, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, s.IdentityID)
+			require.NoError(t, err)
+			i.MetadataPublic = []byte(`{"updated":true}`)
+			require.NoError(t, reg.IdentityManager().Update(ctx, i, identity.ManagerAllowWriteProtectedTraits))
+		},
+		"UpdateIdentity": func(t *testing.T, s *session.Session) {
+			i, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, s.IdentityID)
+			require.NoError(t, err)
+			i.MetadataPublic = []byte(`{"updated":true}`)
+			require.NoError(t, reg.PrivilegedIdentityPool().UpdateIdentity(ctx, i))
+		},
+		"UpdateVerifiableAddress": func(t *testing.T, s *session.Session) {
+			i, err := reg.PrivilegedIdentityPool().GetIdentity(ctx, s.IdentityID, identity.ExpandDefault)
+			require.NoError(t, err)
+			require.NotEmpty(t, i.VerifiableAddresses)
+			i.VerifiableAddresses[0].Verified = true
+			require.NoError(t, reg.PrivilegedIdentityPool().UpdateVerifiableAddress(ctx, &i.VerifiableAddresses[0]))
+		},
+	} {
+		t.Run("case=invalidated by "+name, func(t *testing.T) {
+			s := newSession(t)
+			fetch(t, s)
+			invalidate(t, s)
+			expectCached(t, s, false)
+		})
+	}
+
+	for name, remove := range map[string]func(t *testing.T, s *session.Session){
+		"DeleteSession": func(t *testing.T, s *session.Session) {
+			require.NoError(t, p.DeleteSession(ctx, s.ID))
+		},
+		"DeleteSessionByToken": func(t *testing.T, s *session.Session) {
+			require.NoError(t, p.DeleteSessionByToken(ctx, s.Token))
+		},
+		"DeleteSessionsByIdentity": func(t *testing.T, s *session.Session) {
+			require.NoError(t, p.DeleteSessionsByIdentity(ctx, s.IdentityID))
+		},
+		"DeleteIdentity": func(t *testing.T, s *session.Session) {
+			require.NoError(t, reg.PrivilegedIdentityPool().DeleteIdentity(ctx, s.IdentityID))
+		},
+	} {
+		t.Run("case=invalidated by "+name, func(t *testing.T) {
+			s := newSession(t)
+			fetch(t, s)
+			remove(t, s)
+
+			_, err := p.GetSessionByToken(ctx, s.Token, session.ExpandEverything, identity.ExpandDefault)
+			require.Error(t, err)
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/ory/herodot"
 
+	"github.com/ory/kratos/audit"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/x"
 )
@@ -38,6 +39,8 @@ type (
 		x.CSRFProvider
 		config.Provider
 		sessiontokenexchange.PersistenceProvider
+		audit.RecorderProvider
+		TokenizerProvider
 	}
 	HandlerProvider interface {
 		SessionHandler() *Handler
@@ -124,6 +127,13 @@ type toSession struct {
 	//
 	// in: header
 	Cookie string `json:"Cookie"`
+
+	// Returns the session additionally as a token (such as a JWT)
+	//
+	// The value of this parameter has to be the name of a tokenizer template configured at `session.whoami.tokenizer.templates`.
+	//
+	// in: query
+	TokenizeAs string `json:"tokenize_as"`
 }
 
 // swagger:route GET /sessions/whoami frontend toSession
@@ -180,6 +190,9 @@ type toSession struct {
 // - `session_inactive`: No active session was found in the request (e.g. no Ory Session Cookie / Ory Session Token).
 // - `session_aal2_required`: An active session was found but it does not fulfil the Authenticator Assurance Level, implying that the session must (e.g.) authenticate the second factor.
 //
+// If the `tokenize_as` query parameter is set, the session is additionally returned as a short-lived JSON Web Token
+// in the `tokenized` field. The parameter must name a template configured at `session.whoami.tokenizer.templates`.
+//
 //	Produces:
 //	- application/json
 //
@@ -232,6 +245,14 @@ func (h *Handler) whoami(w http.ResponseWriter, r *http.Request, ps httprouter.P
 		return
 	}
 
+	tokenizeTemplate := r.URL.Query().Get("tokenize_as")
+	if tokenizeTemplate != "" {
+		if err := h.r.SessionTokenizer().TokenizeSession(r.Context(), tokenizeTemplate, s); err != nil {
+			h.r.Writer().WriteError(w, r, err)
+			return
+		}
+	}
+
 	h.r.Writer().Write(w, r, s)
 }
 
@@ -276,6 +297,7 @@ func (h *Handler) deleteIdentitySessions(w http.ResponseWriter, r *http.Request,
 		h.r.Writer().WriteError(w, r, err)
 		return
 	}
+	h.r.AuditRecorder().Record(r, audit.ActionIdentitySessionsDeleted, audit.TargetTypeIdentity, iID, nil)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -502,6 +524,7 @@ func (h *Handler) disableSession(w http.ResponseWriter, r *http.Request, ps http
 		h.r.Writer().WriteError(w, r, err)
 		return
 	}
+	h.r.AuditRecorder().Record(r, audit.ActionSessionDisabled, audit.TargetTypeSession, sID, []string{"active"})
 
 	h.r.Writer().WriteCode(w, r, http.StatusNoContent, nil)
 }
@@ -859,6 +882,7 @@ func (h *Handler) adminSessionExtend(w http.ResponseWriter, r *http.Request, ps
 			h.r.Writer().WriteError(w, r, err)
 			return
 		}
+		h.r.AuditRecorder().Record(r, audit.ActionSessionExtended, audit.TargetTypeSession, s.ID, []string{"expires_at"})
 	}
 
 	h.r.Writer().Write(w, r, s)
@@ -211,6 +211,43 @@ func TestSessionWhoAmI(t *testing.T) {
 		})
 	})
 
+	t.Run("case=tokenize", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySessionTokenizerTemplates+".es256", map[string]interface{}{
+			"ttl":               "1m",
+			"jwks_url":          "file://stub/jwk.es256.json",
+			"claims_mapper_url": "file://stub/jwt.claims.jsonnet",
+		})
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeySessionTokenizerTemplates, nil)
+		})
+
+		client := testhelpers.NewClientWithCookies(t)
+		testhelpers.MockHydrateCookieClient(t, client, ts.URL+"/set")
+
+		t.Run("case=returns the tokenized session", func(t *testing.T) {
+			res, err := client.Get(ts.URL + RouteWhoami + "?tokenize_as=es256")
+			require.NoError(t, err)
+			body := x.MustReadAll(res.Body)
+			require.EqualValues(t, http.StatusOK, res.StatusCode, "%s", body)
+			assert.NotEmpty(t, gjson.GetBytes(body, "tokenized").String(), "%s", body)
+		})
+
+		t.Run("case=does not tokenize by default", func(t *testing.T) {
+			res, err := client.Get(ts.URL + RouteWhoami)
+			require.NoError(t, err)
+			body := x.MustReadAll(res.Body)
+			require.EqualValues(t, http.StatusOK, res.StatusCode, "%s", body)
+			assert.False(t, gjson.GetBytes(body, "tokenized").Exists(), "%s", body)
+		})
+
+		t.Run("case=rejects unknown templates", func(t *testing.T) {
+			res, err := client.Get(ts.URL + RouteWhoami + "?tokenize_as=unknown")
+			require.NoError(t, err)
+			body := x.MustReadAll(res.Body)
+			assert.EqualValues(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+		})
+	})
+
 	/*
 		t.Run("case=respects AAL config", func(t *testing.T) {
 			conf.MustSet(ctx, config.ViperKeySessionLifespan, "1m")
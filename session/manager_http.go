@@ -289,6 +289,20 @@ func (s *ManagerHTTP) DoesSessionSatisfy(r *http.Request, sess *Session, request
 	}
 
 	sess.SetAuthenticatorAssuranceLevel()
+
+	loginURL := urlx.CopyWithQuery(urlx.AppendPaths(s.r.Config().SelfPublicURL(ctx), "/self-service/login/browser"), url.Values{"aal": {"aal2"}})
+
+	// return to the requestURL if it was set
+	if managerOpts.requestURL != "" {
+		loginURL = urlx.CopyWithQuery(loginURL, url.Values{"return_to": {managerOpts.requestURL}})
+	}
+
+	// The login which issued the session looked risky, which is why the session can not be used until the
+	// required AAL was reached, regardless of the requested AAL.
+	if sess.RequiredAAL > sess.AuthenticatorAssuranceLevel {
+		return NewErrAALNotSatisfied(loginURL.String())
+	}
+
 	switch requestedAAL {
 	case string(identity.AuthenticatorAssuranceLevel1):
 		if sess.AuthenticatorAssuranceLevel >= identity.AuthenticatorAssuranceLevel1 {
@@ -326,13 +340,6 @@ func (s *ManagerHTTP) DoesSessionSatisfy(r *http.Request, sess *Session, request
 			return nil
 		}
 
-		loginURL := urlx.CopyWithQuery(urlx.AppendPaths(s.r.Config().SelfPublicURL(ctx), "/self-service/login/browser"), url.Values{"aal": {"aal2"}})
-
-		// return to the requestURL if it was set
-		if managerOpts.requestURL != "" {
-			loginURL = urlx.CopyWithQuery(loginURL, url.Values{"return_to": {managerOpts.requestURL}})
-		}
-
 		return NewErrAALNotSatisfied(loginURL.String())
 	}
 
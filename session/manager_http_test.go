@@ -442,6 +442,7 @@ func TestDoesSessionSatisfy(t *testing.T) {
 		d                     string
 		err                   error
 		requested             identity.AuthenticatorAssuranceLevel
+		required              identity.AuthenticatorAssuranceLevel
 		creds                 []identity.Credentials
 		amr                   session.AuthenticationMethods
 		sessionManagerOptions []session.ManagerOptions
@@ -550,6 +551,24 @@ func TestDoesSessionSatisfy(t *testing.T) {
 				require.Equal(t, tcError.(*session.ErrAALNotSatisfied).RedirectTo, err.(*session.ErrAALNotSatisfied).RedirectTo)
 			},
 		},
+		{
+			d:         "has=aal1, requested=aal1, required=aal2, credentials=password+webauthn_mfa",
+			requested: identity.AuthenticatorAssuranceLevel1,
+			required:  identity.AuthenticatorAssuranceLevel2,
+			creds:     []identity.Credentials{password, mfaWebAuth},
+			amr:       session.AuthenticationMethods{amrPassword},
+			err:       session.NewErrAALNotSatisfied(urlx.CopyWithQuery(urlx.AppendPaths(conf.SelfPublicURL(context.Background()), "/self-service/login/browser"), url.Values{"aal": {"aal2"}}).String()),
+			expectedFunc: func(t *testing.T, err error, tcError error) {
+				require.Equal(t, tcError.(*session.ErrAALNotSatisfied).RedirectTo, err.(*session.ErrAALNotSatisfied).RedirectTo)
+			},
+		},
+		{
+			d:         "has=aal2, requested=aal1, required=aal2, credentials=password+webauthn_mfa",
+			requested: identity.AuthenticatorAssuranceLevel1,
+			required:  identity.AuthenticatorAssuranceLevel2,
+			creds:     []identity.Credentials{password, mfaWebAuth},
+			amr:       session.AuthenticationMethods{amrPassword, {Method: identity.CredentialsTypeWebAuthn, AAL: identity.AuthenticatorAssuranceLevel2}},
+		},
 	} {
 		t.Run(fmt.Sprintf("run=%d/desc=%s", k, tc.d), func(t *testing.T) {
 			id := identity.NewIdentity("")
@@ -567,6 +586,7 @@ func TestDoesSessionSatisfy(t *testing.T) {
 				s.CompletedLoginFor(m.Method, m.AAL)
 			}
 			require.NoError(t, s.Activate(req, id, conf, time.Now().UTC()))
+			s.RequiredAAL = tc.required
 
 			err := reg.SessionManager().DoesSessionSatisfy((&http.Request{}).WithContext(context.Background()), s, string(tc.requested), tc.sessionManagerOptions...)
 			if tc.err != nil {
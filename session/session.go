@@ -105,6 +105,10 @@ type Session struct {
 	// To learn more about these levels please head over to: https://www.ory.sh/kratos/docs/concepts/credentials
 	AuthenticatorAssuranceLevel identity.AuthenticatorAssuranceLevel `faker:"len=4" db:"aal" json:"authenticator_assurance_level"`
 
+	// RequiredAAL is the authenticator assurance level the session has to reach before it can be used. It is set if
+	// the login which issued the session looked risky.
+	RequiredAAL identity.AuthenticatorAssuranceLevel `faker:"-" db:"required_aal" json:"-"`
+
 	// Authentication Method References (AMR)
 	//
 	// A list of authentication methods (e.g. password, oidc, ...) used to issue this session.
@@ -129,6 +133,9 @@ type Session struct {
 	// IdentityID is a helper struct field for gobuffalo.pop.
 	IdentityID uuid.UUID `json:"-" faker:"-" db:"identity_id"`
 
+	// OrganizationID is the ID of the organization the identity belonged to when the session was issued.
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty" faker:"-" db:"organization_id"`
+
 	// CreatedAt is a helper struct field for gobuffalo.pop.
 	CreatedAt time.Time `json:"-" faker:"-" db:"created_at"`
 
@@ -140,6 +147,11 @@ type Session struct {
 	// The token of this session.
 	Token string    `json:"-" db:"token"`
 	NID   uuid.UUID `json:"-"  faker:"-" db:"nid"`
+
+	// Tokenized is the tokenized (e.g. JWT) version of the session.
+	//
+	// It is only set when the `tokenize_as` query parameter was set to a valid tokenize template during calls to `/session/whoami`.
+	Tokenized string `json:"tokenized,omitempty" faker:"-" db:"-"`
 }
 
 func (s Session) PageToken() keysetpagination.PageToken {
@@ -197,6 +209,12 @@ func (s *Session) SetAuthenticatorAssuranceLevel() {
 				isAAL1 = true
 			case identity.CredentialsTypePassword:
 				isAAL1 = true
+			case identity.CredentialsTypeCodeAuth:
+				isAAL1 = true
+			case identity.CredentialsTypePasskey:
+				isAAL1 = true
+			case identity.CredentialsTypeSAML:
+				isAAL1 = true
 			case identity.CredentialsTypeWebAuthn:
 				isAAL2 = true
 			case identity.CredentialsTypeTOTP:
@@ -247,6 +265,7 @@ func (s *Session) Activate(r *http.Request, i *identity.Identity, c lifespanProv
 	s.IssuedAt = authenticatedAt
 	s.Identity = i
 	s.IdentityID = i.ID
+	s.OrganizationID = i.OrganizationID
 
 	s.SetSessionDeviceInformation(r)
 	s.SetAuthenticatorAssuranceLevel()
@@ -254,8 +273,14 @@ func (s *Session) Activate(r *http.Request, i *identity.Identity, c lifespanProv
 }
 
 func (s *Session) SetSessionDeviceInformation(r *http.Request) {
+	device := NewDevice(r)
+	device.SessionID = s.ID
+	s.Devices = append(s.Devices, device)
+}
+
+// NewDevice describes the device which sent the request.
+func NewDevice(r *http.Request) Device {
 	device := Device{
-		SessionID: s.ID,
 		IPAddress: stringsx.GetPointer(httpx.ClientIP(r)),
 	}
 
@@ -273,7 +298,7 @@ func (s *Session) SetSessionDeviceInformation(r *http.Request) {
 	}
 	device.Location = stringsx.GetPointer(strings.Join(clientGeoLocation, ", "))
 
-	s.Devices = append(s.Devices, device)
+	return device
 }
 
 func (s Session) Declassified() *Session {
@@ -0,0 +1,234 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/fetcher"
+	"github.com/ory/x/jsonnetsecure"
+	"github.com/ory/x/otelx"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x"
+)
+
+type (
+	tokenizerDependencies interface {
+		jsonnetsecure.VMProvider
+		x.TracingProvider
+		x.HTTPClientProvider
+		config.Provider
+	}
+	TokenizerProvider interface {
+		SessionTokenizer() *Tokenizer
+	}
+	// Tokenizer converts sessions to short-lived JSON Web Tokens using the templates configured at
+	// `session.whoami.tokenizer.templates`.
+	Tokenizer struct {
+		r       tokenizerDependencies
+		nowFunc func() time.Time
+		cache   *lru.Cache
+	}
+	tokenizerCacheEntry struct {
+		value     interface{}
+		expiresAt time.Time
+	}
+)
+
+// tokenizerCacheTTL is how long JSON Web Key Sets and claims mappers are cached after they were fetched. Rotated keys
+// and changed mappers are used once the cached entry expired.
+const tokenizerCacheTTL = time.Minute * 5
+
+// tokenizerSigningMethods are the algorithms which tokenized sessions can be signed with.
+var tokenizerSigningMethods = map[string]jwt.SigningMethod{
+	jwt.SigningMethodES256.Alg(): jwt.SigningMethodES256,
+	jwt.SigningMethodRS256.Alg(): jwt.SigningMethodRS256,
+	jwt.SigningMethodEdDSA.Alg(): jwt.SigningMethodEdDSA,
+}
+
+func NewTokenizer(r tokenizerDependencies) *Tokenizer {
+	cache, _ := lru.New(64)
+	return &Tokenizer{r: r, nowFunc: time.Now, cache: cache}
+}
+
+// SetNowFunc overrides the clock of the tokenizer and is only used in tests.
+func (s *Tokenizer) SetNowFunc(t func() time.Time) {
+	s.nowFunc = t
+}
+
+// TokenizeSession signs the session using the given template and sets the result as the session's tokenized
+// representation.
+func (s *Tokenizer) TokenizeSession(ctx context.Context, template string, session *Session) (err error) {
+	ctx, span := s.r.Tracer(ctx).Tracer().Start(ctx, "sessions.Tokenizer.TokenizeSession")
+	defer otelx.End(span, &err)
+
+	tpl, err := s.r.Config().TokenizeTemplate(ctx, template)
+	if err != nil {
+		return err
+	}
+
+	key, err := s.signingKey(ctx, tpl.JWKSURL)
+	if err != nil {
+		return err
+	}
+
+	if key.Algorithm == "" {
+		return errors.WithStack(herodot.ErrInternalServerError.WithReasonf("The JSON Web Key of tokenizer template \"%s\" does not specify an algorithm.", template))
+	}
+	method, ok := tokenizerSigningMethods[key.Algorithm]
+	if !ok {
+		return errors.WithStack(herodot.ErrInternalServerError.WithReasonf("The JSON Web Key of tokenizer template \"%s\" uses the unsupported algorithm \"%s\".", template, key.Algorithm))
+	}
+
+	now := s.nowFunc().UTC()
+	exp := now.Add(tpl.TTL)
+	if session.ExpiresAt.Before(exp) {
+		exp = session.ExpiresAt
+	}
+
+	// Registered claims are set after the claims mapper ran so that the mapper can not overwrite them.
+	registered := jwt.MapClaims{
+		"jti": x.NewUUID().String(),
+		"iss": s.r.Config().SelfPublicURL(ctx).String(),
+		"exp": exp.Unix(),
+		"sub": session.IdentityID.String(),
+		"sid": session.ID.String(),
+		"nbf": now.Unix(),
+		"iat": now.Unix(),
+	}
+
+	claims := jwt.MapClaims{}
+	if mapper := tpl.ClaimsMapperURL; len(mapper) > 0 {
+		jn, err := s.claimsMapper(ctx, mapper)
+		if err != nil {
+			return err
+		}
+
+		vm, err := s.r.JsonnetVM(ctx)
+		if err != nil {
+			return err
+		}
+
+		sessionJSON, err := json.Marshal(session)
+		if err != nil {
+			return errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReasonf("Unable to encode session to JSON."))
+		}
+
+		claimsJSON, err := json.Marshal(registered)
+		if err != nil {
+			return errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReasonf("Unable to encode default claims to JSON."))
+		}
+
+		vm.ExtCode("session", string(sessionJSON))
+		vm.ExtCode("claims", string(claimsJSON))
+
+		evaluated, err := vm.EvaluateAnonymousSnippet(mapper, jn)
+		if err != nil {
+			return errors.WithStack(herodot.ErrBadRequest.WithWrap(err).WithDebug(err.Error()).WithReasonf("Unable to execute tokenizer JsonNet."))
+		}
+
+		evaluatedClaims := gjson.Get(evaluated, "claims")
+		if !evaluatedClaims.IsObject() {
+			return errors.WithStack(herodot.ErrBadRequest.WithReasonf("Expected tokenizer JsonNet to return a claims object but it did not."))
+		}
+
+		if err := json.Unmarshal([]byte(evaluatedClaims.Raw), &claims); err != nil {
+			return errors.WithStack(herodot.ErrBadRequest.WithWrap(err).WithReasonf("Unable to decode claims returned by the tokenizer JsonNet."))
+		}
+	}
+
+	for k, v := range registered {
+		claims[k] = v
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = key.KeyID
+	signed, err := token.SignedString(key.Key)
+	if err != nil {
+		return errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReasonf("Unable to sign JSON Web Token."))
+	}
+
+	session.Tokenized = signed
+	return nil
+}
+
+// cached returns the value cached for the key, or loads and caches it.
+func (s *Tokenizer) cached(key string, load func() (interface{}, error)) (interface{}, error) {
+	now := s.nowFunc()
+	if value, ok := s.cache.Get(key); ok {
+		if entry := value.(*tokenizerCacheEntry); now.Before(entry.expiresAt) {
+			return entry.value, nil
+		}
+	}
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Add(key, &tokenizerCacheEntry{value: value, expiresAt: now.Add(tokenizerCacheTTL)})
+	return value, nil
+}
+
+// claimsMapper loads the JsonNet claims mapper.
+func (s *Tokenizer) claimsMapper(ctx context.Context, mapperURL string) (string, error) {
+	jn, err := s.cached("jsonnet:"+mapperURL, func() (interface{}, error) {
+		buf, err := fetcher.NewFetcher(fetcher.WithClient(s.r.HTTPClient(ctx))).FetchContext(ctx, mapperURL)
+		if err != nil {
+			return nil, err
+		}
+		return buf.String(), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return jn.(string), nil
+}
+
+// signingKey loads the first private key of the JSON Web Key Set.
+func (s *Tokenizer) signingKey(ctx context.Context, jwksURL string) (*jose.JSONWebKey, error) {
+	key, err := s.cached("jwks:"+jwksURL, func() (interface{}, error) {
+		return s.fetchSigningKey(ctx, jwksURL)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return key.(*jose.JSONWebKey), nil
+}
+
+func (s *Tokenizer) fetchSigningKey(ctx context.Context, jwksURL string) (*jose.JSONWebKey, error) {
+	buf, err := fetcher.NewFetcher(fetcher.WithClient(s.r.HTTPClient(ctx))).FetchContext(ctx, jwksURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.NewDecoder(bytes.NewReader(buf.Bytes())).Decode(&set); err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithWrap(err).WithReasonf("Unable to decode the tokenizer JSON Web Key Set."))
+	}
+
+	for k := range set.Keys {
+		// Symmetric keys are not public either, but tokens signed with them could only be verified by parties who
+		// can forge them, too.
+		if _, ok := set.Keys[k].Key.([]byte); ok {
+			return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("The tokenizer JSON Web Key Set contains a symmetric key, which is not supported."))
+		}
+		if !set.Keys[k].IsPublic() {
+			return &set.Keys[k], nil
+		}
+	}
+
+	return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("The tokenizer JSON Web Key Set does not contain a private key."))
+}
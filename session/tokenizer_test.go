@@ -0,0 +1,179 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package session_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/sjson"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/ory/herodot"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x"
+)
+
+func validateTokenized(t *testing.T, raw string, jwksPath string) *jwt.Token {
+	buf, err := os.ReadFile(jwksPath)
+	require.NoError(t, err)
+
+	var set jose.JSONWebKeySet
+	require.NoError(t, json.Unmarshal(buf, &set))
+
+	token, err := jwt.Parse(raw, func(token *jwt.Token) (interface{}, error) {
+		keys := set.Key(token.Header["kid"].(string))
+		require.Len(t, keys, 1)
+		return keys[0].Public().Key, nil
+	})
+	require.NoError(t, err)
+	return token
+}
+
+func TestTokenizer(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/identity.schema.json")
+	conf.MustSet(ctx, config.ViperKeyPublicBaseURL, "http://localhost/")
+
+	tkn := session.NewTokenizer(reg)
+	now := time.Now()
+	tkn.SetNowFunc(func() time.Time {
+		return now
+	})
+
+	r := x.NewTestHTTPRequest(t, "GET", "/sessions/whoami", nil)
+	i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+	i.Traits = identity.Traits(`{"email":"tokenizer@ory.sh"}`)
+	s, err := session.NewActiveSession(r, i, conf, now, identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+	require.NoError(t, err)
+
+	setTemplate := func(t *testing.T, key, ttl, jwksURL, mapperURL string) {
+		tpl := map[string]interface{}{"ttl": ttl, "jwks_url": jwksURL}
+		if mapperURL != "" {
+			tpl["claims_mapper_url"] = mapperURL
+		}
+		conf.MustSet(ctx, config.ViperKeySessionTokenizerTemplates+"."+key, tpl)
+	}
+
+	for _, alg := range []string{"es256", "rs256", "eddsa"} {
+		t.Run("alg="+alg, func(t *testing.T) {
+			setTemplate(t, alg, "1m", "file://stub/jwk."+alg+".json", "")
+
+			require.NoError(t, tkn.TokenizeSession(ctx, alg, s))
+			token := validateTokenized(t, s.Tokenized, "stub/jwk."+alg+".json")
+
+			claims := token.Claims.(jwt.MapClaims)
+			assert.Equal(t, "http://localhost/", claims["iss"])
+			assert.Equal(t, i.ID.String(), claims["sub"])
+			assert.Equal(t, s.ID.String(), claims["sid"])
+			assert.NotEmpty(t, claims["jti"])
+			assert.EqualValues(t, now.Add(time.Minute).Unix(), claims["exp"])
+			assert.EqualValues(t, now.Unix(), claims["iat"])
+		})
+	}
+
+	t.Run("case=claims mapper adds claims", func(t *testing.T) {
+		setTemplate(t, "mapped", "1m", "file://stub/jwk.es256.json", "file://stub/jwt.claims.jsonnet")
+
+		require.NoError(t, tkn.TokenizeSession(ctx, "mapped", s))
+		claims := validateTokenized(t, s.Tokenized, "stub/jwk.es256.json").Claims.(jwt.MapClaims)
+		assert.Equal(t, "tokenizer@ory.sh", claims["email"])
+		assert.Equal(t, i.ID.String(), claims["session_sub"])
+		assert.Equal(t, i.ID.String(), claims["sub"], "registered claims must not be overwritten")
+	})
+
+	t.Run("case=ttl is capped at session expiry", func(t *testing.T) {
+		setTemplate(t, "long", "8760h", "file://stub/jwk.es256.json", "")
+
+		require.NoError(t, tkn.TokenizeSession(ctx, "long", s))
+		claims := validateTokenized(t, s.Tokenized, "stub/jwk.es256.json").Claims.(jwt.MapClaims)
+		assert.EqualValues(t, s.ExpiresAt.Unix(), claims["exp"])
+	})
+
+	t.Run("case=rejects mapper without claims", func(t *testing.T) {
+		setTemplate(t, "invalid", "1m", "file://stub/jwk.es256.json", "file://stub/jwt.invalid.jsonnet")
+		require.Error(t, tkn.TokenizeSession(ctx, "invalid", s))
+	})
+
+	t.Run("case=rejects unknown template", func(t *testing.T) {
+		require.Error(t, tkn.TokenizeSession(ctx, "does-not-exist", s))
+	})
+
+	t.Run("case=rejects key without algorithm", func(t *testing.T) {
+		buf, err := os.ReadFile("stub/jwk.es256.json")
+		require.NoError(t, err)
+		buf, err = sjson.DeleteBytes(buf, "keys.0.alg")
+		require.NoError(t, err)
+		jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+		require.NoError(t, os.WriteFile(jwksPath, buf, 0600))
+
+		setTemplate(t, "no-alg", "1m", "file://"+jwksPath, "")
+		err = tkn.TokenizeSession(ctx, "no-alg", s)
+		require.ErrorIs(t, err, herodot.ErrInternalServerError)
+	})
+
+	t.Run("case=rejects symmetric key", func(t *testing.T) {
+		jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+		require.NoError(t, os.WriteFile(jwksPath, []byte(`{"keys":[{"kty":"oct","alg":"HS256","k":"c2VjcmV0LXNlY3JldC1zZWNyZXQtc2VjcmV0LXNlY3JldA"}]}`), 0600))
+
+		setTemplate(t, "symmetric", "1m", "file://"+jwksPath, "")
+		err := tkn.TokenizeSession(ctx, "symmetric", s)
+		require.ErrorIs(t, err, herodot.ErrInternalServerError)
+		assert.Contains(t, herodot.ToDefaultError(err, "").Reason(), "symmetric key")
+	})
+
+	t.Run("case=rejects unsupported algorithm", func(t *testing.T) {
+		buf, err := os.ReadFile("stub/jwk.es256.json")
+		require.NoError(t, err)
+		buf, err = sjson.SetBytes(buf, "keys.0.alg", "ES384")
+		require.NoError(t, err)
+		jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+		require.NoError(t, os.WriteFile(jwksPath, buf, 0600))
+
+		setTemplate(t, "es384", "1m", "file://"+jwksPath, "")
+		err = tkn.TokenizeSession(ctx, "es384", s)
+		require.ErrorIs(t, err, herodot.ErrInternalServerError)
+	})
+
+	t.Run("case=caches the key set until it expires", func(t *testing.T) {
+		tkn := session.NewTokenizer(reg)
+		clock := now.Add(-time.Hour)
+		tkn.SetNowFunc(func() time.Time {
+			return clock
+		})
+
+		jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+		write := func(t *testing.T, source string) {
+			buf, err := os.ReadFile(source)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(jwksPath, buf, 0600))
+		}
+		setTemplate(t, "cached", "2h", "file://"+jwksPath, "")
+
+		write(t, "stub/jwk.es256.json")
+		require.NoError(t, tkn.TokenizeSession(ctx, "cached", s))
+		validateTokenized(t, s.Tokenized, "stub/jwk.es256.json")
+
+		write(t, "stub/jwk.rs256.json")
+		require.NoError(t, tkn.TokenizeSession(ctx, "cached", s))
+		validateTokenized(t, s.Tokenized, "stub/jwk.es256.json")
+
+		clock = now
+		require.NoError(t, tkn.TokenizeSession(ctx, "cached", s))
+		validateTokenized(t, s.Tokenized, "stub/jwk.rs256.json")
+	})
+}
@@ -23,6 +23,10 @@ const (
 	InfoSelfServiceLoginContinueWebAuthn                         // 1010011
 	InfoSelfServiceLoginWebAuthnPasswordless                     // 1010012
 	InfoSelfServiceLoginContinue                                 // 1010013
+	InfoSelfServiceLoginCode                                     // 1010014
+	InfoSelfServiceLoginCodeSent                                 // 1010015
+	InfoSelfServiceLoginLinkCredentials                          // 1010016
+	InfoSelfServiceLoginPasskey                                  // 1010017
 )
 
 const (
@@ -39,6 +43,9 @@ const (
 	InfoSelfServiceRegistrationWith                                 // 1040002
 	InfoSelfServiceRegistrationContinue                             // 1040003
 	InfoSelfServiceRegistrationRegisterWebAuthn                     // 1040004
+	InfoSelfServiceRegistrationCode                                 // 1040005
+	InfoSelfServiceRegistrationCodeSent                             // 1040006
+	InfoSelfServiceRegistrationRegisterPasskey                      // 1040007
 )
 
 const (
@@ -61,6 +68,11 @@ const (
 	InfoSelfServiceSettingsDisableLookup
 	InfoSelfServiceSettingsTOTPSecretLabel
 	InfoSelfServiceSettingsRemoveWebAuthn
+	InfoSelfServiceSettingsRegisterPasskey
+	InfoSelfServiceSettingsRemovePasskey
+	InfoSelfServiceSettingsRegisterPasskeyDisplayName
+	InfoSelfServiceSettingsUpdateLinkSAML
+	InfoSelfServiceSettingsUpdateUnlinkSAML
 )
 
 const (
@@ -122,18 +134,30 @@ const (
 )
 
 const (
-	ErrorValidationLogin                       ID = 4010000 + iota // 4010000
-	ErrorValidationLoginFlowExpired                                // 4010001
-	ErrorValidationLoginNoStrategyFound                            // 4010002
-	ErrorValidationRegistrationNoStrategyFound                     // 4010003
-	ErrorValidationSettingsNoStrategyFound                         // 4010004
-	ErrorValidationRecoveryNoStrategyFound                         // 4010005
-	ErrorValidationVerificationNoStrategyFound                     // 4010006
+	ErrorValidationLogin                         ID = 4010000 + iota // 4010000
+	ErrorValidationLoginFlowExpired                                  // 4010001
+	ErrorValidationLoginNoStrategyFound                              // 4010002
+	ErrorValidationRegistrationNoStrategyFound                       // 4010003
+	ErrorValidationSettingsNoStrategyFound                           // 4010004
+	ErrorValidationRecoveryNoStrategyFound                           // 4010005
+	ErrorValidationVerificationNoStrategyFound                       // 4010006
+	ErrorValidationLoginCodeInvalidOrAlreadyUsed                     // 4010007
+	ErrorValidationLoginRetryLater                                   // 4010008
+	ErrorValidationLoginLockedOut                                    // 4010009
+	ErrorValidationLoginOrganizationSSORequired                      // 4010010
+	ErrorValidationLoginCodeRequired                                 // 4010011
+	ErrorValidationLoginIdentityInactive                             // 4010012
+	ErrorValidationLoginIdentitySuspended                            // 4010013
+	ErrorValidationLoginIdentitySuspendedUntil                       // 4010014
+	ErrorValidationLoginIdentityPendingDeletion                      // 4010015
+	ErrorValidationLoginCodeSubmittedTooOften                        // 4010016
 )
 
 const (
 	ErrorValidationRegistration ID = 4040000 + iota
 	ErrorValidationRegistrationFlowExpired
+	ErrorValidationRegistrationCodeInvalidOrAlreadyUsed
+	ErrorValidationRegistrationOrganizationSSORequired
 )
 
 const (
@@ -149,6 +173,7 @@ const (
 	ErrorValidationRecoveryTokenInvalidOrAlreadyUsed                     // 4060004
 	ErrorValidationRecoveryFlowExpired                                   // 4060005
 	ErrorValidationRecoveryCodeInvalidOrAlreadyUsed                      // 4060006
+	ErrorValidationRecoveryOrganizationSSORequired                       // 4060007
 )
 
 const (
@@ -11,6 +11,10 @@ import (
 
 func TestIDs(t *testing.T) {
 	assert.Equal(t, 1010000, int(InfoSelfServiceLoginRoot))
+	assert.Equal(t, 1010014, int(InfoSelfServiceLoginCode))
+	assert.Equal(t, 1010015, int(InfoSelfServiceLoginCodeSent))
+	assert.Equal(t, 1010016, int(InfoSelfServiceLoginLinkCredentials))
+	assert.Equal(t, 1010017, int(InfoSelfServiceLoginPasskey))
 
 	assert.Equal(t, 1020000, int(InfoSelfServiceLogout))
 
@@ -18,9 +22,18 @@ func TestIDs(t *testing.T) {
 
 	assert.Equal(t, 1040000, int(InfoSelfServiceRegistrationRoot))
 	assert.Equal(t, 1040001, int(InfoSelfServiceRegistration))
+	assert.Equal(t, 1040005, int(InfoSelfServiceRegistrationCode))
+	assert.Equal(t, 1040006, int(InfoSelfServiceRegistrationCodeSent))
+	assert.Equal(t, 1040007, int(InfoSelfServiceRegistrationRegisterPasskey))
 
 	assert.Equal(t, 1050000, int(InfoSelfServiceSettings))
 	assert.Equal(t, 1050001, int(InfoSelfServiceSettingsUpdateSuccess))
+	assert.Equal(t, 1050018, int(InfoSelfServiceSettingsRemoveWebAuthn))
+	assert.Equal(t, 1050019, int(InfoSelfServiceSettingsRegisterPasskey))
+	assert.Equal(t, 1050020, int(InfoSelfServiceSettingsRemovePasskey))
+	assert.Equal(t, 1050021, int(InfoSelfServiceSettingsRegisterPasskeyDisplayName))
+	assert.Equal(t, 1050022, int(InfoSelfServiceSettingsUpdateLinkSAML))
+	assert.Equal(t, 1050023, int(InfoSelfServiceSettingsUpdateUnlinkSAML))
 
 	assert.Equal(t, 1060000, int(InfoSelfServiceRecovery))
 	assert.Equal(t, 1060001, int(InfoSelfServiceRecoverySuccessful))
@@ -46,9 +59,21 @@ func TestIDs(t *testing.T) {
 
 	assert.Equal(t, 4010000, int(ErrorValidationLogin))
 	assert.Equal(t, 4010001, int(ErrorValidationLoginFlowExpired))
+	assert.Equal(t, 4010007, int(ErrorValidationLoginCodeInvalidOrAlreadyUsed))
+	assert.Equal(t, 4010008, int(ErrorValidationLoginRetryLater))
+	assert.Equal(t, 4010009, int(ErrorValidationLoginLockedOut))
+	assert.Equal(t, 4010010, int(ErrorValidationLoginOrganizationSSORequired))
+	assert.Equal(t, 4010011, int(ErrorValidationLoginCodeRequired))
+	assert.Equal(t, 4010012, int(ErrorValidationLoginIdentityInactive))
+	assert.Equal(t, 4010013, int(ErrorValidationLoginIdentitySuspended))
+	assert.Equal(t, 4010014, int(ErrorValidationLoginIdentitySuspendedUntil))
+	assert.Equal(t, 4010015, int(ErrorValidationLoginIdentityPendingDeletion))
+	assert.Equal(t, 4010016, int(ErrorValidationLoginCodeSubmittedTooOften))
 
 	assert.Equal(t, 4040000, int(ErrorValidationRegistration))
 	assert.Equal(t, 4040001, int(ErrorValidationRegistrationFlowExpired))
+	assert.Equal(t, 4040002, int(ErrorValidationRegistrationCodeInvalidOrAlreadyUsed))
+	assert.Equal(t, 4040003, int(ErrorValidationRegistrationOrganizationSSORequired))
 
 	assert.Equal(t, 4050000, int(ErrorValidationSettings))
 	assert.Equal(t, 4050001, int(ErrorValidationSettingsFlowExpired))
@@ -63,6 +88,7 @@ func TestIDs(t *testing.T) {
 	assert.Equal(t, 5000000, int(ErrorSystem))
 
 	assert.Equal(t, 4060006, int(ErrorValidationRecoveryCodeInvalidOrAlreadyUsed))
+	assert.Equal(t, 4060007, int(ErrorValidationRecoveryOrganizationSSORequired))
 	assert.Equal(t, 4070006, int(ErrorValidationVerificationCodeInvalidOrAlreadyUsed))
 
 	assert.Equal(t, 1080000, int(InfoSelfServiceVerification))
@@ -11,6 +11,8 @@ const (
 	ErrIDSelfServiceFlowDisabled                       = "self_service_flow_disabled"
 	ErrIDSelfServiceBrowserLocationChangeRequiredError = "browser_location_change_required"
 	ErrIDSelfServiceFlowReplaced                       = "self_service_flow_replaced"
+	ErrIDSelfServiceCodeSubmittedTooOften              = "self_service_code_submitted_too_often"
+	ErrIDSelfServiceSAMLAssertionReplayed              = "self_service_saml_assertion_replayed"
 
 	ErrIDAlreadyLoggedIn             = "session_already_available"
 	ErrIDAddressNotVerified          = "session_verified_address_required"
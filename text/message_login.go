@@ -5,6 +5,7 @@ package text
 
 import (
 	"fmt"
+	"math"
 	"time"
 )
 
@@ -71,6 +72,53 @@ func NewInfoLoginPasswordlessWebAuthn() *Message {
 	}
 }
 
+func NewInfoSelfServiceLoginCode() *Message {
+	return &Message{
+		ID:      InfoSelfServiceLoginCode,
+		Text:    "Sign in with code",
+		Type:    Info,
+		Context: context(map[string]interface{}{}),
+	}
+}
+
+func NewLoginCodeSent() *Message {
+	return &Message{
+		ID:      InfoSelfServiceLoginCodeSent,
+		Text:    "A code has been sent to the address you provided. If you have not received a message, check the spelling of the address and retry the login.",
+		Type:    Info,
+		Context: context(nil),
+	}
+}
+
+func NewInfoSelfServiceLoginLinkCredentials(provider string) *Message {
+	return &Message{
+		ID:   InfoSelfServiceLoginLinkCredentials,
+		Text: fmt.Sprintf("An account with the same identifier (email, phone, username, ...) exists already. Please sign in to your existing account to link your %s profile to it.", provider),
+		Type: Info,
+		Context: context(map[string]interface{}{
+			"provider": provider,
+		}),
+	}
+}
+
+func NewErrorValidationLoginCodeInvalidOrAlreadyUsed() *Message {
+	return &Message{
+		ID:      ErrorValidationLoginCodeInvalidOrAlreadyUsed,
+		Text:    "The login code is invalid or has already been used. Please try again.",
+		Type:    Error,
+		Context: context(nil),
+	}
+}
+
+func NewErrorValidationLoginCodeSubmittedTooOften() *Message {
+	return &Message{
+		ID:      ErrorValidationLoginCodeSubmittedTooOften,
+		Text:    "The login code was submitted too often. Please request another code.",
+		Type:    Error,
+		Context: context(nil),
+	}
+}
+
 func NewInfoLoginTOTP() *Message {
 	return &Message{
 		ID:      InfoLoginTOTP,
@@ -120,6 +168,85 @@ func NewErrorValidationLoginFlowExpired(expiredAt time.Time) *Message {
 	}
 }
 
+func NewErrorValidationLoginRetryLater(retryAt time.Time) *Message {
+	return &Message{
+		ID:   ErrorValidationLoginRetryLater,
+		Text: fmt.Sprintf("Too many failed login attempts, please try again in %.0f seconds.", math.Ceil(Until(retryAt).Seconds())),
+		Type: Error,
+		Context: context(map[string]interface{}{
+			"retry_at": retryAt,
+		}),
+	}
+}
+
+func NewErrorValidationLoginLockedOut(lockedUntil time.Time) *Message {
+	return &Message{
+		ID:   ErrorValidationLoginLockedOut,
+		Text: fmt.Sprintf("Too many failed login attempts, logging in has been locked for %.0f minutes.", math.Ceil(Until(lockedUntil).Minutes())),
+		Type: Error,
+		Context: context(map[string]interface{}{
+			"locked_until": lockedUntil,
+		}),
+	}
+}
+
+func NewErrorValidationLoginOrganizationSSORequired(provider string) *Message {
+	return &Message{
+		ID:   ErrorValidationLoginOrganizationSSORequired,
+		Text: fmt.Sprintf("Your organization requires you to sign in with %s.", provider),
+		Type: Error,
+		Context: context(map[string]interface{}{
+			"provider": provider,
+		}),
+	}
+}
+
+func NewErrorValidationLoginCodeRequired() *Message {
+	return &Message{
+		ID:   ErrorValidationLoginCodeRequired,
+		Text: "This sign in looks unusual. Please sign in with a one-time code instead.",
+		Type: Error,
+	}
+}
+
+func NewErrorValidationLoginIdentityInactive() *Message {
+	return &Message{
+		ID:   ErrorValidationLoginIdentityInactive,
+		Text: "This account was disabled.",
+		Type: Error,
+	}
+}
+
+func NewErrorValidationLoginIdentitySuspended() *Message {
+	return &Message{
+		ID:   ErrorValidationLoginIdentitySuspended,
+		Text: "This account was suspended.",
+		Type: Error,
+	}
+}
+
+func NewErrorValidationLoginIdentitySuspendedUntil(suspendedUntil time.Time) *Message {
+	return &Message{
+		ID:   ErrorValidationLoginIdentitySuspendedUntil,
+		Text: fmt.Sprintf("This account was suspended until %s.", suspendedUntil.UTC().Format(time.RFC1123)),
+		Type: Error,
+		Context: context(map[string]interface{}{
+			"suspended_until": suspendedUntil,
+		}),
+	}
+}
+
+func NewErrorValidationLoginIdentityPendingDeletion(deleteAfter time.Time) *Message {
+	return &Message{
+		ID:   ErrorValidationLoginIdentityPendingDeletion,
+		Text: fmt.Sprintf("This account will be deleted on %s.", deleteAfter.UTC().Format(time.RFC1123)),
+		Type: Error,
+		Context: context(map[string]interface{}{
+			"delete_after": deleteAfter,
+		}),
+	}
+}
+
 func NewErrorValidationLoginNoStrategyFound() *Message {
 	return &Message{
 		ID:   ErrorValidationLoginNoStrategyFound,
@@ -168,6 +295,14 @@ func NewInfoSelfServiceLoginWebAuthn() *Message {
 	}
 }
 
+func NewInfoSelfServiceLoginPasskey() *Message {
+	return &Message{
+		ID:   InfoSelfServiceLoginPasskey,
+		Text: "Sign in with passkey",
+		Type: Info,
+	}
+}
+
 func NewInfoSelfServiceContinueLoginWebAuthn() *Message {
 	return &Message{
 		ID:   InfoSelfServiceLoginContinueWebAuthn,
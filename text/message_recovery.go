@@ -67,6 +67,17 @@ func NewErrorValidationRecoveryCodeInvalidOrAlreadyUsed() *Message {
 	}
 }
 
+func NewErrorValidationRecoveryOrganizationSSORequired(provider string) *Message {
+	return &Message{
+		ID:   ErrorValidationRecoveryOrganizationSSORequired,
+		Text: fmt.Sprintf("Your organization requires you to sign in with %s. Please recover your account there.", provider),
+		Type: Error,
+		Context: context(map[string]interface{}{
+			"provider": provider,
+		}),
+	}
+}
+
 func NewErrorValidationRecoveryRetrySuccess() *Message {
 	return &Message{
 		ID:      ErrorValidationRecoveryRetrySuccess,
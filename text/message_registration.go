@@ -54,3 +54,48 @@ func NewInfoSelfServiceRegistrationRegisterWebAuthn() *Message {
 		Type: Info,
 	}
 }
+
+func NewInfoSelfServiceRegistrationRegisterPasskey() *Message {
+	return &Message{
+		ID:   InfoSelfServiceRegistrationRegisterPasskey,
+		Text: "Sign up with passkey",
+		Type: Info,
+	}
+}
+
+func NewInfoSelfServiceRegistrationRegisterCode() *Message {
+	return &Message{
+		ID:   InfoSelfServiceRegistrationCode,
+		Text: "Sign up with code",
+		Type: Info,
+	}
+}
+
+func NewRegistrationCodeSent() *Message {
+	return &Message{
+		ID:      InfoSelfServiceRegistrationCodeSent,
+		Text:    "A code has been sent to the address you provided. If you have not received a message, check the spelling of the address and retry the registration.",
+		Type:    Info,
+		Context: context(nil),
+	}
+}
+
+func NewErrorValidationRegistrationCodeInvalidOrAlreadyUsed() *Message {
+	return &Message{
+		ID:      ErrorValidationRegistrationCodeInvalidOrAlreadyUsed,
+		Text:    "The registration code is invalid or has already been used. Please try again.",
+		Type:    Error,
+		Context: context(nil),
+	}
+}
+
+func NewErrorValidationRegistrationOrganizationSSORequired(provider string) *Message {
+	return &Message{
+		ID:   ErrorValidationRegistrationOrganizationSSORequired,
+		Text: fmt.Sprintf("Your organization requires you to sign up with %s.", provider),
+		Type: Error,
+		Context: context(map[string]interface{}{
+			"provider": provider,
+		}),
+	}
+}
@@ -156,6 +156,28 @@ func NewInfoSelfServiceSettingsUpdateUnlinkOIDC(provider string) *Message {
 	}
 }
 
+func NewInfoSelfServiceSettingsUpdateLinkSAML(provider string) *Message {
+	return &Message{
+		ID:   InfoSelfServiceSettingsUpdateLinkSAML,
+		Text: fmt.Sprintf("Link %s", provider),
+		Type: Info,
+		Context: context(map[string]interface{}{
+			"provider": provider,
+		}),
+	}
+}
+
+func NewInfoSelfServiceSettingsUpdateUnlinkSAML(provider string) *Message {
+	return &Message{
+		ID:   InfoSelfServiceSettingsUpdateUnlinkSAML,
+		Text: fmt.Sprintf("Unlink %s", provider),
+		Type: Info,
+		Context: context(map[string]interface{}{
+			"provider": provider,
+		}),
+	}
+}
+
 func NewInfoSelfServiceSettingsRegisterWebAuthn() *Message {
 	return &Message{
 		ID:   InfoSelfServiceSettingsRegisterWebAuthn,
@@ -183,3 +205,31 @@ func NewInfoSelfServiceRemoveWebAuthn(name string, createdAt time.Time) *Message
 		}),
 	}
 }
+
+func NewInfoSelfServiceSettingsRegisterPasskey() *Message {
+	return &Message{
+		ID:   InfoSelfServiceSettingsRegisterPasskey,
+		Text: "Add passkey",
+		Type: Info,
+	}
+}
+
+func NewInfoSelfServiceSettingsRegisterPasskeyDisplayName() *Message {
+	return &Message{
+		ID:   InfoSelfServiceSettingsRegisterPasskeyDisplayName,
+		Text: "Name of the passkey",
+		Type: Info,
+	}
+}
+
+func NewInfoSelfServiceSettingsRemovePasskey(name string, createdAt time.Time) *Message {
+	return &Message{
+		ID:   InfoSelfServiceSettingsRemovePasskey,
+		Text: fmt.Sprintf("Remove passkey \"%s\"", name),
+		Type: Info,
+		Context: context(map[string]interface{}{
+			"display_name": name,
+			"added_at":     createdAt,
+		}),
+	}
+}
@@ -21,12 +21,13 @@ const (
 )
 
 const (
-	InputAttributeAutocompleteEmail           UiNodeInputAttributeAutocomplete = "email"
-	InputAttributeAutocompleteTel             UiNodeInputAttributeAutocomplete = "tel"
-	InputAttributeAutocompleteUrl             UiNodeInputAttributeAutocomplete = "url"
-	InputAttributeAutocompleteCurrentPassword UiNodeInputAttributeAutocomplete = "current-password"
-	InputAttributeAutocompleteNewPassword     UiNodeInputAttributeAutocomplete = "new-password"
-	InputAttributeAutocompleteOneTimeCode     UiNodeInputAttributeAutocomplete = "one-time-code"
+	InputAttributeAutocompleteEmail            UiNodeInputAttributeAutocomplete = "email"
+	InputAttributeAutocompleteTel              UiNodeInputAttributeAutocomplete = "tel"
+	InputAttributeAutocompleteUrl              UiNodeInputAttributeAutocomplete = "url"
+	InputAttributeAutocompleteCurrentPassword  UiNodeInputAttributeAutocomplete = "current-password"
+	InputAttributeAutocompleteNewPassword      UiNodeInputAttributeAutocomplete = "new-password"
+	InputAttributeAutocompleteOneTimeCode      UiNodeInputAttributeAutocomplete = "one-time-code"
+	InputAttributeAutocompleteUsernameWebAuthn UiNodeInputAttributeAutocomplete = "username webauthn"
 )
 
 // swagger:enum UiNodeInputAttributeType
@@ -28,3 +28,14 @@ const (
 	WebAuthnRemove              = "webauthn_remove"
 	WebAuthnScript              = "webauthn_script"
 )
+
+const (
+	PasskeyRegisterTrigger     = "passkey_register_trigger"
+	PasskeyRegister            = "passkey_register"
+	PasskeyLogin               = "passkey_login"
+	PasskeyLoginTrigger        = "passkey_login_trigger"
+	PasskeyChallenge           = "passkey_challenge"
+	PasskeyRegisterDisplayName = "passkey_register_displayname"
+	PasskeyRemove              = "passkey_remove"
+	PasskeyScript              = "passkey_script"
+)
@@ -48,6 +48,8 @@ const (
 	TOTPGroup          UiNodeGroup = "totp"
 	LookupGroup        UiNodeGroup = "lookup_secret"
 	WebAuthnGroup      UiNodeGroup = "webauthn"
+	PasskeyGroup       UiNodeGroup = "passkey"
+	SAMLGroup          UiNodeGroup = "saml"
 )
 
 func (g UiNodeGroup) String() string {
@@ -0,0 +1,33 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package x
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/urfave/negroni"
+)
+
+type acceptLanguageContextKey struct{}
+
+// AcceptLanguageContextMiddleware stores the request's Accept-Language header in the request context, so that messages
+// sent while handling the request can be rendered in the user's language.
+var AcceptLanguageContextMiddleware negroni.HandlerFunc = func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if al := r.Header.Get("Accept-Language"); al != "" {
+		r = r.WithContext(ContextWithAcceptLanguage(r.Context(), al))
+	}
+	next(rw, r)
+}
+
+// ContextWithAcceptLanguage returns a copy of the context carrying the value of an Accept-Language header.
+func ContextWithAcceptLanguage(ctx context.Context, acceptLanguage string) context.Context {
+	return context.WithValue(ctx, acceptLanguageContextKey{}, acceptLanguage)
+}
+
+// AcceptLanguageFromContext returns the Accept-Language header stored in the context, or an empty string.
+func AcceptLanguageFromContext(ctx context.Context) string {
+	al, _ := ctx.Value(acceptLanguageContextKey{}).(string)
+	return al
+}
@@ -0,0 +1,30 @@
+// Copyright © 2023 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package x
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/negroni"
+)
+
+func TestAcceptLanguageContextMiddleware(t *testing.T) {
+	var actual string
+	n := negroni.New()
+	n.UseFunc(AcceptLanguageContextMiddleware)
+	n.UseHandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actual = AcceptLanguageFromContext(r.Context())
+	})
+
+	r := httptest.NewRequest("GET", "/", nil)
+	n.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Empty(t, actual)
+
+	r.Header.Set("Accept-Language", "de-CH, de;q=0.9")
+	n.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Equal(t, "de-CH, de;q=0.9", actual)
+}
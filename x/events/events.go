@@ -29,6 +29,7 @@ const (
 	VerificationSucceeded semconv.Event = "VerificationSucceeded"
 	IdentityCreated       semconv.Event = "IdentityCreated"
 	IdentityUpdated       semconv.Event = "IdentityUpdated"
+	IdentityDeleted       semconv.Event = "IdentityDeleted"
 )
 
 const (
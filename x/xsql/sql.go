@@ -12,9 +12,14 @@ import (
 	"github.com/ory/kratos/selfservice/errorx"
 	"github.com/ory/kratos/selfservice/sessiontokenexchange"
 
+	"github.com/ory/kratos/audit"
+	"github.com/ory/kratos/bruteforce"
 	"github.com/ory/kratos/continuity"
 	"github.com/ory/kratos/courier"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
+	"github.com/ory/kratos/outbox"
+	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/recovery"
 	"github.com/ory/kratos/selfservice/flow/registration"
@@ -22,6 +27,7 @@ import (
 	"github.com/ory/kratos/selfservice/flow/verification"
 	"github.com/ory/kratos/selfservice/strategy/code"
 	"github.com/ory/kratos/selfservice/strategy/link"
+	"github.com/ory/kratos/selfservice/strategy/saml"
 	"github.com/ory/kratos/session"
 )
 
@@ -31,10 +37,17 @@ func CleanSQL(t testing.TB, c *pop.Connection) {
 		new(continuity.Container).TableName(ctx),
 		new(courier.MessageDispatch).TableName(),
 		new(courier.Message).TableName(ctx),
+		new(outbox.Event).TableName(ctx),
+		"outbox_sequences",
+		new(audit.Event).TableName(ctx),
 
 		new(session.Device).TableName(ctx),
 		new(session.Session).TableName(ctx),
+		new(code.LoginCode).TableName(ctx),
+		new(code.RegistrationCode).TableName(ctx),
 		new(login.Flow).TableName(ctx),
+		new(bruteforce.LoginThrottle).TableName(ctx),
+		new(saml.UsedAssertion).TableName(ctx),
 		new(registration.Flow).TableName(ctx),
 		new(settings.Flow).TableName(ctx),
 
@@ -49,11 +62,16 @@ func CleanSQL(t testing.TB, c *pop.Connection) {
 
 		new(errorx.ErrorContainer).TableName(ctx),
 
+		new(identity.SchemaMigrationFailure).TableName(ctx),
+		new(identity.SchemaMigration).TableName(ctx),
 		new(identity.CredentialIdentifier).TableName(ctx),
 		new(identity.Credentials).TableName(ctx),
 		new(identity.VerifiableAddress).TableName(ctx),
 		new(identity.RecoveryAddress).TableName(ctx),
 		new(identity.Identity).TableName(ctx),
+		new(schema.Version).TableName(ctx),
+		new(organization.Domain).TableName(ctx),
+		new(organization.Organization).TableName(ctx),
 		new(identity.CredentialsTypeTable).TableName(ctx),
 		new(sessiontokenexchange.Exchanger).TableName(),
 		"networks",